@@ -0,0 +1,60 @@
+package chanacceptor
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// counterOfferMarker precedes the feature bits of the channel type that an
+// acceptor counter-offers in the error sent to the initiator of a channel.
+const counterOfferMarker = "counter-offer channel_type="
+
+// counterOfferRegex matches a counter-offer within an error message, capturing
+// the comma separated feature bits of the channel type.
+var counterOfferRegex = regexp.MustCompile(counterOfferMarker + `([0-9,]*)`)
+
+// NewCounterOfferError returns an error for acceptors to reject a channel with
+// while proposing another channel type instead. As the recipient of an
+// open_channel message can't respond with a different channel type, the
+// counter-offer is included in the error sent to the initiator, which allows
+// it to propose the channel again with the counter-offered type.
+func NewCounterOfferError(reason error, chanType lnwire.ChannelType) error {
+	featureBits := chanType.FeatureBits()
+	bits := make([]string, 0, len(featureBits))
+	for _, bit := range featureBits {
+		bits = append(bits, strconv.Itoa(int(bit)))
+	}
+
+	return fmt.Errorf("%v, %s%s", reason, counterOfferMarker,
+		strings.Join(bits, ","))
+}
+
+// ParseCounterOffer extracts the channel type counter-offered in an error
+// received from the recipient of a channel, returning false if the error
+// doesn't include a valid counter-offer.
+func ParseCounterOffer(errMsg string) (lnwire.ChannelType, bool) {
+	match := counterOfferRegex.FindStringSubmatch(errMsg)
+	if match == nil {
+		return lnwire.ChannelType{}, false
+	}
+
+	fv := lnwire.NewRawFeatureVector()
+	if match[1] == "" {
+		return lnwire.ChannelType(*fv), true
+	}
+
+	for _, bitStr := range strings.Split(match[1], ",") {
+		bit, err := strconv.ParseUint(bitStr, 10, 16)
+		if err != nil {
+			return lnwire.ChannelType{}, false
+		}
+
+		fv.Set(lnwire.FeatureBit(bit))
+	}
+
+	return lnwire.ChannelType(*fv), true
+}
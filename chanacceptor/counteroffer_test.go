@@ -0,0 +1,71 @@
+package chanacceptor
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCounterOffer tests that counter-offered channel types can be parsed
+// from the error sent to the initiator of a channel.
+func TestCounterOffer(t *testing.T) {
+	t.Parallel()
+
+	anchors := lnwire.ChannelType(*lnwire.NewRawFeatureVector(
+		lnwire.StaticRemoteKeyRequired,
+		lnwire.AnchorsZeroFeeHtlcTxRequired,
+	))
+	legacy := lnwire.ChannelType(*lnwire.NewRawFeatureVector())
+
+	tests := []struct {
+		name     string
+		errMsg   string
+		chanType lnwire.ChannelType
+		ok       bool
+	}{
+		{
+			name: "anchors",
+			errMsg: NewCounterOfferError(
+				errChannelRejected, anchors,
+			).Error(),
+			chanType: anchors,
+			ok:       true,
+		},
+		{
+			name: "legacy",
+			errMsg: NewCounterOfferError(
+				errors.New("no anchors"), legacy,
+			).Error(),
+			chanType: legacy,
+			ok:       true,
+		},
+		{
+			name:   "no counter-offer",
+			errMsg: errChannelRejected.Error(),
+		},
+		{
+			name:   "invalid feature bit",
+			errMsg: counterOfferMarker + "12,99999",
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			chanType, ok := ParseCounterOffer(test.errMsg)
+			require.Equal(t, test.ok, ok)
+			if !ok {
+				return
+			}
+
+			expected := lnwire.RawFeatureVector(test.chanType)
+			parsed := lnwire.RawFeatureVector(chanType)
+			require.True(t, expected.Equals(&parsed))
+		})
+	}
+}
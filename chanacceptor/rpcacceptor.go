@@ -38,6 +38,17 @@ var (
 	errAcceptWithError = errors.New("channel acceptor response accepts " +
 		"channel, but also includes custom error")
 
+	// errAcceptWithCounterOffer is returned when we get a response which
+	// accepts a channel but ambiguously also counter-offers another
+	// channel type.
+	errAcceptWithCounterOffer = errors.New("channel acceptor response " +
+		"accepts channel, but also counter-offers a channel type")
+
+	// errInvalidCounterOffer is returned when the channel type an acceptor
+	// counter-offers isn't a valid channel type.
+	errInvalidCounterOffer = errors.New("invalid counter-offered " +
+		"channel type")
+
 	// errMaxHtlcTooHigh is returned if our htlc count exceeds the number
 	// hard-set by BOLT 2.
 	errMaxHtlcTooHigh = fmt.Errorf("htlc limit exceeds spec limit of: %v",
@@ -217,6 +228,8 @@ func (r *RPCAcceptor) receiveResponses(errChan chan error,
 			MinHtlcIn:       resp.MinHtlcIn,
 			MinAcceptDepth:  resp.MinAcceptDepth,
 			ZeroConf:        resp.ZeroConf,
+
+			CounterChannelType: resp.CounterChannelType,
 		}
 
 		// We have received a decision for one of our channel
@@ -381,6 +394,11 @@ func (r *RPCAcceptor) sendAcceptRequests(errChan chan error,
 				WantsZeroConf:    wantsZeroConf,
 				WantsScidAlias:   wantsScidAlias,
 			}
+			chanType := req.OpenChanMsg.ChannelType
+			if chanType != nil {
+				chanAcceptReq.ChannelType =
+					lnrpc.MarshallChannelType(*chanType)
+			}
 
 			if err := r.send(chanAcceptReq); err != nil {
 				return err
@@ -486,26 +504,52 @@ func (r *RPCAcceptor) validateAcceptorResponse(dustLimit btcutil.Amount,
 
 	var haveCustomError = len(req.Error) != 0
 
+	// Parse the channel type the acceptor counter-offers, if any.
+	var counterOffer *lnwire.ChannelType
+	if req.CounterChannelType != nil {
+		chanType, err := lnrpc.UnmarshallChannelType(
+			req.CounterChannelType,
+		)
+		if err != nil {
+			log.Errorf("Invalid counter-offer for %v: %v",
+				channelStr, err)
+
+			return false, errChannelRejected, nil,
+				errInvalidCounterOffer
+		}
+		counterOffer = &chanType
+	}
+
 	switch {
 	// If accept is true, but we also have an error specified, we fail
 	// because this result is ambiguous.
 	case req.Accept && haveCustomError:
 		return false, errChannelRejected, nil, errAcceptWithError
 
+	// The same goes for accepting the channel while counter-offering
+	// another channel type.
+	case req.Accept && counterOffer != nil:
+		return false, errChannelRejected, nil, errAcceptWithCounterOffer
+
 	// If we accept without an error message, we can just return a nil
 	// error.
 	case req.Accept:
 		return true, nil, upfront, nil
+	}
 
-	// If we reject the channel, and have a custom error, then we use it.
-	case haveCustomError:
-		return false, fmt.Errorf(req.Error), nil, nil
+	// We reject the channel, using the custom error if we have one, or a
+	// generic error otherwise.
+	rejectErr := errChannelRejected
+	if haveCustomError {
+		rejectErr = fmt.Errorf(req.Error)
+	}
 
-	// Otherwise, we have rejected the channel with no custom error, so we
-	// just use a generic error to fail the channel.
-	default:
-		return false, errChannelRejected, nil, nil
+	// Let the initiator know which channel type we'd accept instead.
+	if counterOffer != nil {
+		rejectErr = NewCounterOfferError(rejectErr, *counterOffer)
 	}
+
+	return false, rejectErr, nil, nil
 }
 
 // A compile-time constraint to ensure RPCAcceptor implements the ChannelAcceptor
@@ -22,6 +22,11 @@ func TestValidateAcceptorResponse(t *testing.T) {
 		addr, _     = chancloser.ParseUpfrontShutdownAddress(
 			validAddr, &chaincfg.RegressionNetParams,
 		)
+		anchors = lnwire.ChannelType(*lnwire.NewRawFeatureVector(
+			lnwire.StaticRemoteKeyRequired,
+			lnwire.AnchorsZeroFeeHtlcTxRequired,
+		))
+		rpcAnchors = lnrpc.MarshallChannelType(anchors)
 	)
 
 	tests := []struct {
@@ -104,6 +109,39 @@ func TestValidateAcceptorResponse(t *testing.T) {
 			acceptorErr: errChannelRejected,
 			error:       errInsufficientReserve,
 		},
+		{
+			name: "accepted with counter-offer",
+			response: &lnrpc.ChannelAcceptResponse{
+				Accept:             true,
+				CounterChannelType: rpcAnchors,
+			},
+			accept:      false,
+			acceptorErr: errChannelRejected,
+			error:       errAcceptWithCounterOffer,
+		},
+		{
+			name: "rejected with counter-offer",
+			response: &lnrpc.ChannelAcceptResponse{
+				Accept:             false,
+				Error:              customError.Error(),
+				CounterChannelType: rpcAnchors,
+			},
+			accept:      false,
+			acceptorErr: NewCounterOfferError(customError, anchors),
+			error:       nil,
+		},
+		{
+			name: "invalid counter-offer",
+			response: &lnrpc.ChannelAcceptResponse{
+				Accept: false,
+				CounterChannelType: &lnrpc.ChannelType{
+					FeatureBits: []uint32{13},
+				},
+			},
+			accept:      false,
+			acceptorErr: errChannelRejected,
+			error:       errInvalidCounterOffer,
+		},
 		{
 			name:      "max htlcs too high",
 			dustLimit: 100,
@@ -1,6 +1,7 @@
 package chanbackup
 
 import (
+	"errors"
 	"fmt"
 	"net"
 
@@ -50,6 +51,21 @@ func assembleChanBackup(addrSource AddressSource,
 
 	single := NewSingle(openChan, nodeAddrs)
 
+	// If we already broadcast a cooperative close transaction for this
+	// channel, we'll include it in the backup, so it can be re-broadcast
+	// when restoring the channel.
+	if openChan.HasChanStatus(channeldb.ChanStatusCoopBroadcasted) {
+		closeTx, err := openChan.BroadcastedCooperative()
+		switch {
+		case err == nil:
+			single.CoopCloseTx = closeTx
+			single.Version = TLVBackupVersion
+
+		case !errors.Is(err, channeldb.ErrNoCloseTx):
+			return nil, err
+		}
+	}
+
 	return &single, nil
 }
 
@@ -0,0 +1,50 @@
+package chanbackup
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+)
+
+// ExecSink is a Sink that hands the backup to an external command on stdin.
+// This allows users to hook up any kind of storage lnd doesn't support
+// natively.
+type ExecSink struct {
+	command string
+	args    []string
+}
+
+// NewExecSink creates a new ExecSink that runs the given command with the
+// given arguments.
+func NewExecSink(command string, args ...string) *ExecSink {
+	return &ExecSink{
+		command: command,
+		args:    args,
+	}
+}
+
+// Name returns a human readable name of the sink.
+//
+// NOTE: This is part of the Sink interface.
+func (e *ExecSink) Name() string {
+	return fmt.Sprintf("exec(%s)", filepath.Base(e.command))
+}
+
+// Upload runs the command, passing the backup on stdin. The upload is
+// considered failed if the command exits with a non-zero exit code.
+//
+// NOTE: This is part of the Sink interface.
+func (e *ExecSink) Upload(ctx context.Context, backup PackedMulti) error {
+	cmd := exec.CommandContext(ctx, e.command, e.args...)
+	cmd.Stdin = bytes.NewReader(backup)
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("command failed: %w: %s", err,
+			bytes.TrimSpace(output))
+	}
+
+	return nil
+}
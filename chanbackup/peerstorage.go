@@ -0,0 +1,98 @@
+package chanbackup
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// ErrPeerBackupTooLarge is returned when the packed backup of all channels
+// with a peer doesn't fit within a single peer storage message.
+var ErrPeerBackupTooLarge = errors.New("packed peer backup exceeds maximum " +
+	"peer storage size")
+
+// FetchStaticChanBackupsForPeer will return a plaintext static channel back
+// up for all known active/open channels that we have with the target peer.
+func FetchStaticChanBackupsForPeer(nodePub *btcec.PublicKey,
+	chanSource LiveChannelSource, addrSource AddressSource) ([]Single,
+	error) {
+
+	openChans, err := chanSource.FetchAllChannels()
+	if err != nil {
+		return nil, err
+	}
+
+	var staticChanBackups []Single
+	for _, openChan := range openChans {
+		if !openChan.IdentityPub.IsEqual(nodePub) {
+			continue
+		}
+
+		chanBackup, err := assembleChanBackup(addrSource, openChan)
+		if err != nil {
+			return nil, err
+		}
+
+		staticChanBackups = append(staticChanBackups, *chanBackup)
+	}
+
+	return staticChanBackups, nil
+}
+
+// PackPeerBackup packs (encrypts+serializes) the set of static channel
+// backups into a multi-channel backup that can be handed to a channel peer
+// for storage. An error is returned if the packed backup doesn't fit into a
+// single peer storage message.
+func PackPeerBackup(backups []Single,
+	keyRing keychain.KeyRing) (PackedMulti, error) {
+
+	multi := Multi{
+		Version:       DefaultMultiVersion,
+		StaticBackups: backups,
+	}
+
+	var b bytes.Buffer
+	if err := multi.PackToWriter(&b, keyRing); err != nil {
+		return nil, err
+	}
+
+	if b.Len() > lnwire.MaxPeerStorageBytes {
+		return nil, fmt.Errorf("%w: %v channels packed into %v bytes",
+			ErrPeerBackupTooLarge, len(backups), b.Len())
+	}
+
+	return b.Bytes(), nil
+}
+
+// UnpackPeerBackup attempts to unpack (decrypt+deserialize) a backup that was
+// handed back to us by the peer identified by nodePub. Only the channel
+// backups that were made with this very peer are returned, as a peer should
+// never hold backups of channels we have with other nodes.
+func UnpackPeerBackup(packedBackup PackedMulti, nodePub *btcec.PublicKey,
+	keyRing keychain.KeyRing) ([]Single, error) {
+
+	multi, err := packedBackup.Unpack(keyRing)
+	if err != nil {
+		return nil, err
+	}
+
+	var backups []Single
+	for _, backup := range multi.StaticBackups {
+		if !backup.RemoteNodePub.IsEqual(nodePub) {
+			log.Warnf("Ignoring backup for ChannelPoint(%v) handed "+
+				"to us by unrelated peer %x",
+				backup.FundingOutpoint,
+				nodePub.SerializeCompressed())
+
+			continue
+		}
+
+		backups = append(backups, backup)
+	}
+
+	return backups, nil
+}
@@ -0,0 +1,92 @@
+package chanbackup
+
+import (
+	"net"
+	"testing"
+
+	"github.com/lightningnetwork/lnd/lnencrypt"
+	"github.com/stretchr/testify/require"
+)
+
+// TestFetchStaticChanBackupsForPeer tests that only the channels we have with
+// the target peer are included in the set of backups for that peer.
+func TestFetchStaticChanBackupsForPeer(t *testing.T) {
+	t.Parallel()
+
+	randomChan1, err := genRandomOpenChannelShell()
+	require.NoError(t, err, "unable to generate chan")
+	randomChan2, err := genRandomOpenChannelShell()
+	require.NoError(t, err, "unable to generate chan")
+
+	// We'll make a second channel with the same peer as the first one.
+	randomChan3, err := genRandomOpenChannelShell()
+	require.NoError(t, err, "unable to generate chan")
+	randomChan3.IdentityPub = randomChan1.IdentityPub
+
+	chanSource := newMockChannelSource()
+	chanSource.chans[randomChan1.FundingOutpoint] = randomChan1
+	chanSource.chans[randomChan2.FundingOutpoint] = randomChan2
+	chanSource.chans[randomChan3.FundingOutpoint] = randomChan3
+	chanSource.addAddrsForNode(randomChan1.IdentityPub, []net.Addr{addr1})
+	chanSource.addAddrsForNode(randomChan2.IdentityPub, []net.Addr{addr2})
+
+	backups, err := FetchStaticChanBackupsForPeer(
+		randomChan1.IdentityPub, chanSource, chanSource,
+	)
+	require.NoError(t, err)
+	require.Len(t, backups, 2)
+	for _, backup := range backups {
+		require.True(
+			t, backup.RemoteNodePub.IsEqual(randomChan1.IdentityPub),
+		)
+	}
+
+	// If we're unable to query the channel source, we should fail.
+	chanSource.failQuery = true
+	_, err = FetchStaticChanBackupsForPeer(
+		randomChan1.IdentityPub, chanSource, chanSource,
+	)
+	require.Error(t, err)
+}
+
+// TestPeerBackupPackUnpack tests that a packed peer backup can be unpacked
+// again, and that backups of channels with other peers are filtered out.
+func TestPeerBackupPackUnpack(t *testing.T) {
+	t.Parallel()
+
+	keyRing := &lnencrypt.MockKeyRing{}
+
+	randomChan1, err := genRandomOpenChannelShell()
+	require.NoError(t, err, "unable to generate chan")
+	randomChan2, err := genRandomOpenChannelShell()
+	require.NoError(t, err, "unable to generate chan")
+
+	single1 := NewSingle(randomChan1, []net.Addr{addr1})
+	single2 := NewSingle(randomChan2, []net.Addr{addr2})
+
+	packed, err := PackPeerBackup([]Single{single1, single2}, keyRing)
+	require.NoError(t, err)
+
+	// Only the backup of the channel with the first peer should be
+	// returned when the blob is handed back by that peer.
+	backups, err := UnpackPeerBackup(
+		packed, randomChan1.IdentityPub, keyRing,
+	)
+	require.NoError(t, err)
+	require.Len(t, backups, 1)
+	assertSingleEqual(t, single1, backups[0])
+
+	// A blob that was tampered with should fail to decrypt.
+	packed[len(packed)-1] ^= 1
+	_, err = UnpackPeerBackup(packed, randomChan1.IdentityPub, keyRing)
+	require.Error(t, err)
+
+	// Finally, a set of backups that doesn't fit into a single peer
+	// storage message should be rejected.
+	tooManyBackups := make([]Single, 0, 1000)
+	for i := 0; i < 1000; i++ {
+		tooManyBackups = append(tooManyBackups, single1)
+	}
+	_, err = PackPeerBackup(tooManyBackups, keyRing)
+	require.ErrorIs(t, err, ErrPeerBackupTooLarge)
+}
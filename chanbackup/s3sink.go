@@ -0,0 +1,278 @@
+package chanbackup
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// s3SigningAlgorithm is the identifier of the AWS signature version 4
+	// signing algorithm.
+	s3SigningAlgorithm = "AWS4-HMAC-SHA256"
+
+	// s3Service is the service name that is part of the credential scope
+	// of signed S3 requests.
+	s3Service = "s3"
+
+	// amzDateFormat is the timestamp format of the X-Amz-Date header.
+	amzDateFormat = "20060102T150405Z"
+
+	// amzShortDateFormat is the date format used in the credential scope.
+	amzShortDateFormat = "20060102"
+)
+
+// S3SinkConfig houses the configuration of an S3Sink.
+type S3SinkConfig struct {
+	// Endpoint is the base URL of the S3-compatible service, for example
+	// https://s3.us-east-1.amazonaws.com.
+	Endpoint string
+
+	// Region is the region used to sign requests.
+	Region string
+
+	// Bucket is the bucket the backup is stored in.
+	Bucket string
+
+	// Key is the object key the backup is stored under.
+	Key string
+
+	// AccessKeyID is the access key ID used to sign requests.
+	AccessKeyID string
+
+	// SecretAccessKey is the secret key used to sign requests.
+	SecretAccessKey string
+
+	// Client is the HTTP client used to perform requests. If nil,
+	// http.DefaultClient is used.
+	Client *http.Client
+
+	// Now returns the current time used to sign requests. If nil,
+	// time.Now is used.
+	Now func() time.Time
+}
+
+// S3Sink is a Sink that stores the backup as an object in an S3-compatible
+// object store. Objects are addressed path-style and requests are signed
+// using AWS signature version 4, which is understood by most S3-compatible
+// services.
+type S3Sink struct {
+	cfg S3SinkConfig
+}
+
+// NewS3Sink creates a new S3Sink from the given config.
+func NewS3Sink(cfg S3SinkConfig) (*S3Sink, error) {
+	if _, err := url.Parse(cfg.Endpoint); err != nil {
+		return nil, fmt.Errorf("invalid S3 endpoint: %w", err)
+	}
+
+	if cfg.Client == nil {
+		cfg.Client = http.DefaultClient
+	}
+	if cfg.Now == nil {
+		cfg.Now = time.Now
+	}
+
+	return &S3Sink{
+		cfg: cfg,
+	}, nil
+}
+
+// Name returns a human readable name of the sink.
+//
+// NOTE: This is part of the Sink interface.
+func (s *S3Sink) Name() string {
+	return fmt.Sprintf("s3(%s/%s)", s.cfg.Bucket, s.cfg.Key)
+}
+
+// Upload stores the backup in the configured bucket with a single PUT
+// request.
+//
+// NOTE: This is part of the Sink interface.
+func (s *S3Sink) Upload(ctx context.Context, backup PackedMulti) error {
+	objectURL := strings.TrimSuffix(s.cfg.Endpoint, "/") + "/" +
+		s.cfg.Bucket + "/" + strings.TrimPrefix(s.cfg.Key, "/")
+
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodPut, objectURL, bytes.NewReader(backup),
+	)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+
+	payloadHash := sha256.Sum256(backup)
+	signS3Request(
+		req, hex.EncodeToString(payloadHash[:]), s.cfg.AccessKeyID,
+		s.cfg.SecretAccessKey, s.cfg.Region, s.cfg.Now(),
+	)
+
+	resp, err := s.cfg.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return checkHTTPResponse(resp)
+}
+
+// signS3Request adds the X-Amz-Date, X-Amz-Content-Sha256 and Authorization
+// headers to the request, signing the request and all of its headers using
+// AWS signature version 4.
+func signS3Request(req *http.Request, payloadHash, accessKeyID,
+	secretAccessKey, region string, now time.Time) {
+
+	now = now.UTC()
+	amzDate := now.Format(amzDateFormat)
+	shortDate := now.Format(amzShortDateFormat)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	// The host header isn't part of req.Header, so we'll add it to the
+	// set of headers to sign explicitly.
+	host := req.Host
+	if host == "" {
+		host = req.URL.Host
+	}
+	headers := map[string]string{
+		"host": host,
+	}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(
+			strings.Join(values, ","),
+		)
+	}
+
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		s3EscapePath(req.URL.Path),
+		s3CanonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := strings.Join(
+		[]string{shortDate, region, s3Service, "aws4_request"}, "/",
+	)
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{
+		s3SigningAlgorithm,
+		amzDate,
+		scope,
+		hex.EncodeToString(requestHash[:]),
+	}, "\n")
+
+	signingKey := hmacSHA256([]byte("AWS4"+secretAccessKey), shortDate)
+	signingKey = hmacSHA256(signingKey, region)
+	signingKey = hmacSHA256(signingKey, s3Service)
+	signingKey = hmacSHA256(signingKey, "aws4_request")
+	signature := hmacSHA256(signingKey, stringToSign)
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"%s Credential=%s/%s, SignedHeaders=%s, Signature=%x",
+		s3SigningAlgorithm, accessKeyID, scope, signedHeaders,
+		signature,
+	))
+}
+
+// hmacSHA256 returns the HMAC-SHA256 of data under the given key.
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	_, _ = mac.Write([]byte(data))
+
+	return mac.Sum(nil)
+}
+
+// s3Escape URI-encodes the string as required by AWS signature version 4:
+// every byte except the unreserved characters is percent-encoded. If
+// encodeSlash is false, slashes are left as is.
+func s3Escape(s string, encodeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z',
+			'0' <= c && c <= '9', c == '-', c == '_', c == '.',
+			c == '~':
+
+			b.WriteByte(c)
+
+		case c == '/' && !encodeSlash:
+			b.WriteByte(c)
+
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+
+	return b.String()
+}
+
+// s3EscapePath returns the canonical URI of the given path.
+func s3EscapePath(path string) string {
+	if path == "" {
+		return "/"
+	}
+
+	return s3Escape(path, false)
+}
+
+// s3CanonicalQuery returns the canonical query string of the given query
+// values, sorted by key.
+func s3CanonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var params []string
+	for _, key := range keys {
+		values := query[key]
+		sort.Strings(values)
+		for _, value := range values {
+			params = append(
+				params, s3Escape(key, true)+"="+
+					s3Escape(value, true),
+			)
+		}
+	}
+
+	return strings.Join(params, "&")
+}
+
+// checkHTTPResponse returns an error including (a prefix of) the response
+// body if the response doesn't carry a 2xx status code.
+func checkHTTPResponse(resp *http.Response) error {
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+
+	return fmt.Errorf("unexpected response status %v: %s", resp.Status,
+		bytes.TrimSpace(body))
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/lightningnetwork/lnd/keychain"
 	"github.com/lightningnetwork/lnd/lnencrypt"
 	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/tlv"
 )
 
 // SingleBackupVersion denotes the version of the single static channel backup.
@@ -48,8 +49,56 @@ const (
 	// commitment and HTLC outputs that pay directly to the channel
 	// initiator.
 	ScriptEnforcedLeaseVersion = 4
+
+	// TLVBackupVersion is a version that no longer implies the commitment
+	// format of the channel through the version itself. Instead, the
+	// static channel information is followed by a TLV stream that carries
+	// the channel type explicitly, along with a set of optional hints
+	// about the state of the channel at the time the backup was created.
+	// These hints allow us to speed up the recovery of the channel.
+	TLVBackupVersion = 5
+)
+
+const (
+	// chanTypeType is the TLV type of the required channel type record.
+	chanTypeType tlv.Type = 0
+
+	// leaseExpiryType is the TLV type of the lease expiry record, which
+	// is only present for channels with a script enforced lease.
+	leaseExpiryType tlv.Type = 1
+
+	// localCommitHeightType is the TLV type of the local commitment height
+	// hint.
+	localCommitHeightType tlv.Type = 3
+
+	// remoteCommitHeightType is the TLV type of the remote commitment
+	// height hint.
+	remoteCommitHeightType tlv.Type = 5
+
+	// numPendingHtlcsType is the TLV type of the hint carrying the number
+	// of HTLCs pending on our commitment.
+	numPendingHtlcsType tlv.Type = 7
+
+	// pendingHtlcAmtType is the TLV type of the hint carrying the total
+	// amount of the HTLCs pending on our commitment.
+	pendingHtlcAmtType tlv.Type = 9
+
+	// localShutdownScriptType is the TLV type of the hint carrying our
+	// upfront shutdown script.
+	localShutdownScriptType tlv.Type = 11
+
+	// coopCloseTxType is the TLV type of the hint carrying a cooperative
+	// close transaction we broadcast for the channel.
+	coopCloseTxType tlv.Type = 13
 )
 
+// backupChanTypeMask is the set of channel type bits that determine the
+// commitment format of a channel. Only these bits are relevant when restoring
+// a channel from a backup, so all others are omitted.
+const backupChanTypeMask = channeldb.SingleFunderTweaklessBit |
+	channeldb.AnchorOutputsBit | channeldb.ZeroHtlcTxFeeBit |
+	channeldb.LeaseExpirationBit
+
 // Single is a static description of an existing channel that can be used for
 // the purposes of backing up. The fields in this struct allow a node to
 // recover the settled funds within a channel in the case of partial or
@@ -133,7 +182,50 @@ type Single struct {
 	// NOTE: This field will only be present for the following versions:
 	//
 	// - ScriptEnforcedLeaseVersion
+	// - TLVBackupVersion
 	LeaseExpiry uint32
+
+	// ChanType is the channel type of the channel, reduced to the bits
+	// that determine its commitment format.
+	//
+	// NOTE: This field will only be present for the following versions:
+	//
+	// - TLVBackupVersion
+	ChanType channeldb.ChannelType
+
+	// The remaining fields are optional hints about the state of the
+	// channel at the time the backup was created. They are never needed
+	// to recover the funds of a channel, but allow us to speed up the
+	// recovery and to inform the user about what to expect.
+	//
+	// NOTE: These fields will only be present for the following versions:
+	//
+	// - TLVBackupVersion
+
+	// LocalCommitHeight is the height of our latest commitment.
+	LocalCommitHeight uint64
+
+	// RemoteCommitHeight is the height of the remote party's latest
+	// commitment.
+	RemoteCommitHeight uint64
+
+	// NumPendingHTLCs is the number of HTLCs that were pending on our
+	// commitment. Funds in pending HTLCs can't be recovered from a static
+	// channel backup.
+	NumPendingHTLCs uint32
+
+	// PendingHTLCAmt is the total amount of all HTLCs that were pending
+	// on our commitment.
+	PendingHTLCAmt lnwire.MilliSatoshi
+
+	// LocalShutdownScript is the script our funds are paid out to on a
+	// cooperative close, if we committed to one when opening the channel.
+	LocalShutdownScript lnwire.DeliveryAddress
+
+	// CoopCloseTx is the cooperative close transaction we broadcast for
+	// the channel, if a cooperative close was in progress. It spends the
+	// funding output directly, so re-broadcasting it is always safe.
+	CoopCloseTx *wire.MsgTx
 }
 
 // NewSingle creates a new static channel backup based on an existing open
@@ -210,27 +302,63 @@ func NewSingle(channel *channeldb.OpenChannel,
 		LocalChanCfg:     channel.LocalChanCfg,
 		RemoteChanCfg:    channel.RemoteChanCfg,
 		ShaChainRootDesc: shaChainRootDesc,
+		ChanType:         channel.ChanType & backupChanTypeMask,
 	}
 
-	switch {
-	case channel.ChanType.HasLeaseExpiration():
-		single.Version = ScriptEnforcedLeaseVersion
+	if channel.ChanType.HasLeaseExpiration() {
 		single.LeaseExpiry = channel.ThawHeight
+	}
+
+	// Next, we'll add the hints about the current state of the channel.
+	// We use our own commitment to determine the pending HTLCs, as that's
+	// the one we'd broadcast to go on-chain.
+	single.LocalCommitHeight = channel.LocalCommitment.CommitHeight
+	single.RemoteCommitHeight = channel.RemoteCommitment.CommitHeight
+	for _, htlc := range channel.LocalCommitment.Htlcs {
+		single.NumPendingHTLCs++
+		single.PendingHTLCAmt += htlc.Amt
+	}
+	single.LocalShutdownScript = channel.LocalShutdownScript
 
-	case channel.ChanType.ZeroHtlcTxFee():
-		single.Version = AnchorsZeroFeeHtlcTxCommitVersion
+	// Finally, we'll pick the version. Older nodes can't restore backups
+	// of the TLV version, so we'll only use it if there are hints to
+	// carry and stick to the version implied by the channel type
+	// otherwise.
+	single.Version = single.legacyVersion()
+	if single.hasStateHints() {
+		single.Version = TLVBackupVersion
+	}
+
+	return single
+}
+
+// legacyVersion returns the version prior to the TLV version that implies the
+// commitment format of the channel type of the backup.
+func (s *Single) legacyVersion() SingleBackupVersion {
+	switch {
+	case s.ChanType.HasLeaseExpiration():
+		return ScriptEnforcedLeaseVersion
 
-	case channel.ChanType.HasAnchors():
-		single.Version = AnchorsCommitVersion
+	case s.ChanType.ZeroHtlcTxFee():
+		return AnchorsZeroFeeHtlcTxCommitVersion
 
-	case channel.ChanType.IsTweakless():
-		single.Version = TweaklessCommitVersion
+	case s.ChanType.HasAnchors():
+		return AnchorsCommitVersion
+
+	case s.ChanType.IsTweakless():
+		return TweaklessCommitVersion
 
 	default:
-		single.Version = DefaultSingleVersion
+		return DefaultSingleVersion
 	}
+}
 
-	return single
+// hasStateHints returns true if any of the optional hints about the state of
+// the channel are set, which can only be carried by the TLV version.
+func (s *Single) hasStateHints() bool {
+	return s.LocalCommitHeight != 0 || s.RemoteCommitHeight != 0 ||
+		s.NumPendingHTLCs != 0 || s.PendingHTLCAmt != 0 ||
+		len(s.LocalShutdownScript) != 0 || s.CoopCloseTx != nil
 }
 
 // Serialize attempts to write out the serialized version of the target
@@ -244,6 +372,7 @@ func (s *Single) Serialize(w io.Writer) error {
 	case AnchorsCommitVersion:
 	case AnchorsZeroFeeHtlcTxCommitVersion:
 	case ScriptEnforcedLeaseVersion:
+	case TLVBackupVersion:
 	default:
 		return fmt.Errorf("unable to serialize w/ unknown "+
 			"version: %v", s.Version)
@@ -305,11 +434,17 @@ func (s *Single) Serialize(w io.Writer) error {
 	); err != nil {
 		return err
 	}
-	if s.Version == ScriptEnforcedLeaseVersion {
+	switch s.Version {
+	case ScriptEnforcedLeaseVersion:
 		err := lnwire.WriteElements(&singleBytes, s.LeaseExpiry)
 		if err != nil {
 			return err
 		}
+
+	case TLVBackupVersion:
+		if err := s.encodeTLVRecords(&singleBytes); err != nil {
+			return err
+		}
 	}
 
 	// TODO(yy): remove the type assertion when we finished refactoring db
@@ -420,6 +555,7 @@ func (s *Single) Deserialize(r io.Reader) error {
 	case AnchorsCommitVersion:
 	case AnchorsZeroFeeHtlcTxCommitVersion:
 	case ScriptEnforcedLeaseVersion:
+	case TLVBackupVersion:
 	default:
 		return fmt.Errorf("unable to de-serialize w/ unknown "+
 			"version: %v", s.Version)
@@ -430,6 +566,18 @@ func (s *Single) Deserialize(r io.Reader) error {
 		return err
 	}
 
+	// The TLV stream at the end of the TLV version extends until the end
+	// of the serialized single. As the single may be followed by others
+	// within a multi, we'll restrict reading to the length of this one.
+	if s.Version == TLVBackupVersion {
+		singleBytes := make([]byte, length)
+		if _, err := io.ReadFull(r, singleBytes); err != nil {
+			return err
+		}
+
+		r = bytes.NewReader(singleBytes)
+	}
+
 	err = lnwire.ReadElements(
 		r, &s.IsInitiator, s.ChainHash[:], &s.FundingOutpoint,
 		&s.ShortChannelID, &s.RemoteNodePub, &s.Addresses, &s.Capacity,
@@ -518,10 +666,144 @@ func (s *Single) Deserialize(r io.Reader) error {
 		return err
 	}
 
-	if s.Version == ScriptEnforcedLeaseVersion {
+	switch s.Version {
+	case ScriptEnforcedLeaseVersion:
 		if err := lnwire.ReadElement(r, &s.LeaseExpiry); err != nil {
 			return err
 		}
+
+	case TLVBackupVersion:
+		if err := s.decodeTLVRecords(r); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// encodeTLVRecords writes the TLV stream of the TLVBackupVersion, which
+// carries the channel type and all hints that are set, to the passed writer.
+func (s *Single) encodeTLVRecords(w io.Writer) error {
+	chanType := uint64(s.ChanType)
+	records := []tlv.Record{
+		tlv.MakePrimitiveRecord(chanTypeType, &chanType),
+	}
+
+	// All other records are optional, so we'll only add them if they're
+	// set.
+	if s.LeaseExpiry != 0 {
+		records = append(records, tlv.MakePrimitiveRecord(
+			leaseExpiryType, &s.LeaseExpiry,
+		))
+	}
+	if s.LocalCommitHeight != 0 {
+		records = append(records, tlv.MakePrimitiveRecord(
+			localCommitHeightType, &s.LocalCommitHeight,
+		))
+	}
+	if s.RemoteCommitHeight != 0 {
+		records = append(records, tlv.MakePrimitiveRecord(
+			remoteCommitHeightType, &s.RemoteCommitHeight,
+		))
+	}
+
+	pendingHtlcAmt := uint64(s.PendingHTLCAmt)
+	if s.NumPendingHTLCs != 0 {
+		records = append(
+			records,
+			tlv.MakePrimitiveRecord(
+				numPendingHtlcsType, &s.NumPendingHTLCs,
+			),
+			tlv.MakePrimitiveRecord(
+				pendingHtlcAmtType, &pendingHtlcAmt,
+			),
+		)
+	}
+
+	shutdownScript := []byte(s.LocalShutdownScript)
+	if len(shutdownScript) != 0 {
+		records = append(records, tlv.MakePrimitiveRecord(
+			localShutdownScriptType, &shutdownScript,
+		))
+	}
+
+	var closeTx []byte
+	if s.CoopCloseTx != nil {
+		var b bytes.Buffer
+		if err := s.CoopCloseTx.Serialize(&b); err != nil {
+			return err
+		}
+		closeTx = b.Bytes()
+
+		records = append(records, tlv.MakePrimitiveRecord(
+			coopCloseTxType, &closeTx,
+		))
+	}
+
+	tlvStream, err := tlv.NewStream(records...)
+	if err != nil {
+		return err
+	}
+
+	return tlvStream.Encode(w)
+}
+
+// decodeTLVRecords reads the TLV stream of the TLVBackupVersion from the
+// passed reader. Unknown odd records are ignored, allowing us to add new
+// hints in the future without bumping the version.
+func (s *Single) decodeTLVRecords(r io.Reader) error {
+	var (
+		chanType       uint64
+		pendingHtlcAmt uint64
+		shutdownScript []byte
+		closeTx        []byte
+	)
+	tlvStream, err := tlv.NewStream(
+		tlv.MakePrimitiveRecord(chanTypeType, &chanType),
+		tlv.MakePrimitiveRecord(leaseExpiryType, &s.LeaseExpiry),
+		tlv.MakePrimitiveRecord(
+			localCommitHeightType, &s.LocalCommitHeight,
+		),
+		tlv.MakePrimitiveRecord(
+			remoteCommitHeightType, &s.RemoteCommitHeight,
+		),
+		tlv.MakePrimitiveRecord(
+			numPendingHtlcsType, &s.NumPendingHTLCs,
+		),
+		tlv.MakePrimitiveRecord(pendingHtlcAmtType, &pendingHtlcAmt),
+		tlv.MakePrimitiveRecord(
+			localShutdownScriptType, &shutdownScript,
+		),
+		tlv.MakePrimitiveRecord(coopCloseTxType, &closeTx),
+	)
+	if err != nil {
+		return err
+	}
+
+	parsedTypes, err := tlvStream.DecodeWithParsedTypes(r)
+	if err != nil {
+		return err
+	}
+
+	// The channel type is required, as we wouldn't know how to restore
+	// the channel without it.
+	if _, ok := parsedTypes[chanTypeType]; !ok {
+		return fmt.Errorf("channel type missing from single backup")
+	}
+	s.ChanType = channeldb.ChannelType(chanType)
+
+	s.PendingHTLCAmt = lnwire.MilliSatoshi(pendingHtlcAmt)
+	if len(shutdownScript) != 0 {
+		s.LocalShutdownScript = shutdownScript
+	}
+
+	if _, ok := parsedTypes[coopCloseTxType]; ok {
+		s.CoopCloseTx = &wire.MsgTx{}
+		err := s.CoopCloseTx.Deserialize(bytes.NewReader(closeTx))
+		if err != nil {
+			return fmt.Errorf("unable to decode coop close tx: %w",
+				err)
+		}
 	}
 
 	return nil
@@ -85,6 +85,19 @@ func assertSingleEqual(t *testing.T, a, b Single) {
 			spew.Sdump(b.ShaChainRootDesc))
 	}
 
+	// The channel type and the state hints are only serialized by the
+	// TLV version.
+	if a.Version == TLVBackupVersion {
+		require.Equal(t, a.ChanType, b.ChanType)
+		require.Equal(t, a.LeaseExpiry, b.LeaseExpiry)
+		require.Equal(t, a.LocalCommitHeight, b.LocalCommitHeight)
+		require.Equal(t, a.RemoteCommitHeight, b.RemoteCommitHeight)
+		require.Equal(t, a.NumPendingHTLCs, b.NumPendingHTLCs)
+		require.Equal(t, a.PendingHTLCAmt, b.PendingHTLCAmt)
+		require.Equal(t, a.LocalShutdownScript, b.LocalShutdownScript)
+		require.Equal(t, a.CoopCloseTx, b.CoopCloseTx)
+	}
+
 	if len(a.Addresses) != len(b.Addresses) {
 		t.Fatalf("expected %v addrs got %v", len(a.Addresses),
 			len(b.Addresses))
@@ -245,6 +258,12 @@ func TestSinglePackUnpack(t *testing.T) {
 			valid:   true,
 		},
 
+		// The TLV version should pack/unpack with no problem.
+		{
+			version: TLVBackupVersion,
+			valid:   true,
+		},
+
 		// A non-default version, atm this should result in a failure.
 		{
 			version: 99,
@@ -453,4 +472,102 @@ func TestSingleUnconfirmedChannel(t *testing.T) {
 	}
 }
 
+// TestSingleTLVHints tests that the channel type and the state hints carried
+// by the TLV version survive a round trip, also when multiple singles are
+// packed into a multi.
+func TestSingleTLVHints(t *testing.T) {
+	t.Parallel()
+
+	keyRing := &lnencrypt.MockKeyRing{}
+
+	channel, err := genRandomOpenChannelShell()
+	require.NoError(t, err, "unable to gen open channel")
+
+	channel.ChanType = channeldb.LeaseExpirationBit |
+		channeldb.ZeroHtlcTxFeeBit | channeldb.AnchorOutputsBit |
+		channeldb.SingleFunderTweaklessBit | channeldb.ScidAliasChanBit
+	channel.LocalCommitment.CommitHeight = 42
+	channel.RemoteCommitment.CommitHeight = 41
+	channel.LocalCommitment.Htlcs = []channeldb.HTLC{
+		{Amt: 1000}, {Amt: 2000},
+	}
+	channel.LocalShutdownScript = []byte{0x00, 0x14, 0x01, 0x02}
+
+	single := NewSingle(channel, []net.Addr{addr1})
+	require.Equal(t, SingleBackupVersion(TLVBackupVersion), single.Version)
+
+	// Bits that don't determine the commitment format should have been
+	// omitted.
+	require.Equal(
+		t, channel.ChanType&^channeldb.ScidAliasChanBit,
+		single.ChanType,
+	)
+	require.Equal(t, channel.ThawHeight, single.LeaseExpiry)
+	require.EqualValues(t, 2, single.NumPendingHTLCs)
+	require.EqualValues(t, 3000, single.PendingHTLCAmt)
+
+	closeTx := wire.NewMsgTx(2)
+	closeTx.AddTxIn(&wire.TxIn{
+		PreviousOutPoint: channel.FundingOutpoint,
+		SignatureScript:  []byte{},
+	})
+	closeTx.AddTxOut(&wire.TxOut{
+		Value:    100_000,
+		PkScript: channel.LocalShutdownScript,
+	})
+	single.CoopCloseTx = closeTx
+
+	// A second single without any of the optional hints.
+	channel2, err := genRandomOpenChannelShell()
+	require.NoError(t, err, "unable to gen open channel")
+	single2 := NewSingle(channel2, []net.Addr{addr2})
+
+	var b bytes.Buffer
+	require.NoError(t, single.PackToWriter(&b, keyRing))
+
+	var unpackedSingle Single
+	require.NoError(t, unpackedSingle.UnpackFromReader(&b, keyRing))
+	assertSingleEqual(t, single, unpackedSingle)
+
+	multi := Multi{
+		StaticBackups: []Single{single, single2},
+	}
+	b.Reset()
+	require.NoError(t, multi.PackToWriter(&b, keyRing))
+
+	var unpackedMulti Multi
+	require.NoError(t, unpackedMulti.UnpackFromReader(&b, keyRing))
+	require.Len(t, unpackedMulti.StaticBackups, 2)
+	assertSingleEqual(t, single, unpackedMulti.StaticBackups[0])
+	assertSingleEqual(t, single2, unpackedMulti.StaticBackups[1])
+}
+
+// TestSingleLegacyVersion tests that a backup without any state hints uses the
+// version implied by its channel type, so older nodes can still restore it.
+func TestSingleLegacyVersion(t *testing.T) {
+	t.Parallel()
+
+	channel, err := genRandomOpenChannelShell()
+	require.NoError(t, err, "unable to gen open channel")
+
+	channel.ChanType = channeldb.ZeroHtlcTxFeeBit |
+		channeldb.AnchorOutputsBit | channeldb.SingleFunderTweaklessBit
+	channel.LocalCommitment.CommitHeight = 0
+	channel.RemoteCommitment.CommitHeight = 0
+	channel.LocalCommitment.Htlcs = nil
+	channel.LocalShutdownScript = nil
+
+	single := NewSingle(channel, []net.Addr{addr1})
+	require.Equal(
+		t, SingleBackupVersion(AnchorsZeroFeeHtlcTxCommitVersion),
+		single.Version,
+	)
+
+	// Once the channel has been used, the hints are carried by the TLV
+	// version.
+	channel.LocalCommitment.CommitHeight = 1
+	single = NewSingle(channel, []net.Addr{addr1})
+	require.Equal(t, SingleBackupVersion(TLVBackupVersion), single.Version)
+}
+
 // TODO(roasbsef): fuzz parsing
@@ -0,0 +1,351 @@
+package chanbackup
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/lightningnetwork/lnd/clock"
+)
+
+// Sink is a remote destination that the packed multi-channel backup is
+// uploaded to each time the backup is updated.
+type Sink interface {
+	// Name returns a human readable name of the sink that identifies it in
+	// logs and status reports.
+	Name() string
+
+	// Upload attempts to store the given packed multi backup at the remote
+	// destination, replacing any prior version. The upload must be aborted
+	// once the passed context is canceled.
+	Upload(ctx context.Context, backup PackedMulti) error
+}
+
+// SinkStatus describes the outcome of the most recent upload attempts to a
+// remote backup sink.
+type SinkStatus struct {
+	// Name is the name of the sink this status belongs to.
+	Name string
+
+	// LastSuccess is the time of the last successful upload. It's the
+	// zero time if no upload has succeeded yet.
+	LastSuccess time.Time
+
+	// LastAttempt is the time of the last upload attempt, successful or
+	// not. It's the zero time if no upload was attempted yet.
+	LastAttempt time.Time
+
+	// LastErr is the error returned by the last upload attempt, or nil if
+	// that attempt succeeded.
+	LastErr error
+
+	// NumFailures is the number of consecutive failed upload attempts
+	// since the last successful upload.
+	NumFailures uint32
+}
+
+// SinkDispatcherConfig houses the configuration of a SinkDispatcher.
+type SinkDispatcherConfig struct {
+	// Sinks is the set of remote destinations each new backup is
+	// uploaded to.
+	Sinks []Sink
+
+	// MinBackoff is the time we wait before retrying a failed upload for
+	// the first time. The back off is doubled after each consecutive
+	// failure.
+	MinBackoff time.Duration
+
+	// MaxBackoff is the upper bound of the back off between two upload
+	// attempts.
+	MaxBackoff time.Duration
+
+	// MaxAttempts is the number of times we'll try to upload a single
+	// backup to a sink before giving up. A value of zero means we'll keep
+	// trying until the upload succeeds or a newer backup is available.
+	MaxAttempts uint32
+
+	// Timeout is the maximum time a single upload attempt may take.
+	Timeout time.Duration
+
+	// Clock is the time source used for back offs and status reporting.
+	Clock clock.Clock
+}
+
+// sinkWorker uploads backups to a single sink. Only the most recent backup is
+// kept around, a newer backup always supersedes one that wasn't uploaded yet.
+type sinkWorker struct {
+	sink Sink
+
+	// newBackup is signaled each time pendingBackup is replaced.
+	newBackup chan struct{}
+
+	mu            sync.Mutex
+	pendingBackup PackedMulti
+	status        SinkStatus
+}
+
+// SinkDispatcher uploads each new multi-channel backup to a set of remote
+// sinks. Every sink is served by its own goroutine, so a slow or unreachable
+// sink doesn't delay uploads to the others. Failed uploads are retried with
+// an exponential back off.
+type SinkDispatcher struct {
+	started sync.Once
+	stopped sync.Once
+
+	cfg SinkDispatcherConfig
+
+	workers []*sinkWorker
+
+	// ctx is canceled once the dispatcher is stopped, aborting any
+	// in-flight uploads.
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewSinkDispatcher creates a new SinkDispatcher from the given config.
+func NewSinkDispatcher(cfg SinkDispatcherConfig) (*SinkDispatcher, error) {
+	switch {
+	case cfg.MinBackoff <= 0:
+		return nil, errors.New("min backoff must be positive")
+
+	case cfg.MaxBackoff < cfg.MinBackoff:
+		return nil, errors.New("max backoff must not be smaller than " +
+			"min backoff")
+
+	case cfg.Timeout <= 0:
+		return nil, errors.New("timeout must be positive")
+	}
+
+	if cfg.Clock == nil {
+		cfg.Clock = clock.NewDefaultClock()
+	}
+
+	workers := make([]*sinkWorker, 0, len(cfg.Sinks))
+	for _, sink := range cfg.Sinks {
+		workers = append(workers, &sinkWorker{
+			sink:      sink,
+			newBackup: make(chan struct{}, 1),
+			status: SinkStatus{
+				Name: sink.Name(),
+			},
+		})
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &SinkDispatcher{
+		cfg:     cfg,
+		workers: workers,
+		ctx:     ctx,
+		cancel:  cancel,
+		quit:    make(chan struct{}),
+	}, nil
+}
+
+// Start launches the upload goroutine of each sink.
+func (d *SinkDispatcher) Start() error {
+	d.started.Do(func() {
+		log.Infof("chanbackup.SinkDispatcher starting with %v sinks",
+			len(d.workers))
+
+		for _, w := range d.workers {
+			d.wg.Add(1)
+			go d.uploader(w)
+		}
+	})
+
+	return nil
+}
+
+// Stop aborts any in-flight uploads and waits for all goroutines to exit.
+func (d *SinkDispatcher) Stop() error {
+	d.stopped.Do(func() {
+		log.Infof("Stopping chanbackup.SinkDispatcher")
+
+		close(d.quit)
+		d.cancel()
+		d.wg.Wait()
+	})
+
+	return nil
+}
+
+// Dispatch queues the given packed multi backup for upload to all sinks,
+// replacing any backup that wasn't uploaded yet. This method never blocks.
+func (d *SinkDispatcher) Dispatch(backup PackedMulti) {
+	for _, w := range d.workers {
+		w.mu.Lock()
+		w.pendingBackup = backup
+		w.mu.Unlock()
+
+		select {
+		case w.newBackup <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// SinkStatuses returns a snapshot of the upload status of every sink, in the
+// order the sinks were configured.
+func (d *SinkDispatcher) SinkStatuses() []SinkStatus {
+	statuses := make([]SinkStatus, 0, len(d.workers))
+	for _, w := range d.workers {
+		w.mu.Lock()
+		statuses = append(statuses, w.status)
+		w.mu.Unlock()
+	}
+
+	return statuses
+}
+
+// uploader is the goroutine serving a single sink. It waits for new backups
+// to be dispatched and uploads them.
+//
+// NOTE: This MUST be run as a goroutine.
+func (d *SinkDispatcher) uploader(w *sinkWorker) {
+	defer d.wg.Done()
+
+	for {
+		select {
+		case <-w.newBackup:
+			w.mu.Lock()
+			backup := w.pendingBackup
+			w.pendingBackup = nil
+			w.mu.Unlock()
+
+			if backup == nil {
+				continue
+			}
+
+			d.uploadWithRetry(w, backup)
+
+		case <-d.quit:
+			return
+		}
+	}
+}
+
+// uploadWithRetry attempts to upload the backup to the sink of the worker,
+// backing off between failed attempts. It returns early if a newer backup is
+// dispatched while backing off, or if the dispatcher is shutting down.
+func (d *SinkDispatcher) uploadWithRetry(w *sinkWorker, backup PackedMulti) {
+	name := w.sink.Name()
+	backoff := d.cfg.MinBackoff
+
+	for attempt := uint32(1); ; attempt++ {
+		err := d.upload(w, backup)
+		if err == nil {
+			log.Infof("Uploaded multi SCB backup to sink %v", name)
+			return
+		}
+
+		// If we're shutting down, the upload failed because we
+		// canceled it, so there's no need to log an error.
+		select {
+		case <-d.quit:
+			return
+		default:
+		}
+
+		if d.cfg.MaxAttempts != 0 && attempt >= d.cfg.MaxAttempts {
+			log.Errorf("Giving up uploading multi SCB backup to "+
+				"sink %v after %v attempts: %v", name, attempt,
+				err)
+
+			return
+		}
+
+		log.Warnf("Unable to upload multi SCB backup to sink %v "+
+			"(attempt %v), retrying in %v: %v", name, attempt,
+			backoff, err)
+
+		select {
+		case <-d.cfg.Clock.TickAfter(backoff):
+
+		// A newer backup is available, there's no point in retrying
+		// the outdated one. We'll re-signal the worker so the newer
+		// backup is picked up by the main loop right away.
+		case <-w.newBackup:
+			select {
+			case w.newBackup <- struct{}{}:
+			default:
+			}
+
+			return
+
+		case <-d.quit:
+			return
+		}
+
+		backoff *= 2
+		if backoff > d.cfg.MaxBackoff {
+			backoff = d.cfg.MaxBackoff
+		}
+	}
+}
+
+// upload performs a single upload attempt and records its outcome in the
+// status of the worker.
+func (d *SinkDispatcher) upload(w *sinkWorker, backup PackedMulti) error {
+	ctx, cancel := context.WithTimeout(d.ctx, d.cfg.Timeout)
+	defer cancel()
+
+	err := w.sink.Upload(ctx, backup)
+
+	now := d.cfg.Clock.Now()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.status.LastAttempt = now
+	w.status.LastErr = err
+	if err != nil {
+		w.status.NumFailures++
+		return err
+	}
+
+	w.status.LastSuccess = now
+	w.status.NumFailures = 0
+
+	return nil
+}
+
+// SinkSwapper is a Swapper that hands every backup that was successfully
+// swapped in by the wrapped Swapper to a SinkDispatcher for upload to the
+// configured remote sinks.
+type SinkSwapper struct {
+	Swapper
+
+	dispatcher *SinkDispatcher
+}
+
+// NewSinkSwapper wraps the given Swapper so each new backup is also
+// dispatched to the remote sinks of the dispatcher.
+func NewSinkSwapper(swapper Swapper, dispatcher *SinkDispatcher) *SinkSwapper {
+	return &SinkSwapper{
+		Swapper:    swapper,
+		dispatcher: dispatcher,
+	}
+}
+
+// UpdateAndSwap updates the wrapped Swapper and, if that succeeded, queues the
+// new backup for upload to the remote sinks.
+//
+// NOTE: This is part of the Swapper interface.
+func (s *SinkSwapper) UpdateAndSwap(newBackup PackedMulti) error {
+	if err := s.Swapper.UpdateAndSwap(newBackup); err != nil {
+		return err
+	}
+
+	s.dispatcher.Dispatch(newBackup)
+
+	return nil
+}
+
+// A compile-time constraint to ensure SinkSwapper implements the Swapper
+// interface.
+var _ Swapper = (*SinkSwapper)(nil)
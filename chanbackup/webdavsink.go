@@ -0,0 +1,113 @@
+package chanbackup
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// WebDAVSinkConfig houses the configuration of a WebDAVSink.
+type WebDAVSinkConfig struct {
+	// URL is the full URL of the file the backup is stored in.
+	URL string
+
+	// Username is the username used for HTTP basic authentication. If
+	// empty, no authentication is used.
+	Username string
+
+	// Password is the password used for HTTP basic authentication.
+	Password string
+
+	// Client is the HTTP client used to perform requests. If nil,
+	// http.DefaultClient is used.
+	Client *http.Client
+}
+
+// WebDAVSink is a Sink that stores the backup as a file on a WebDAV server.
+// Similar to the MultiFile, the backup is first written to a temporary file
+// which is then moved into place, so the target file always contains a
+// complete backup.
+type WebDAVSink struct {
+	cfg WebDAVSinkConfig
+
+	tempURL string
+}
+
+// NewWebDAVSink creates a new WebDAVSink from the given config.
+func NewWebDAVSink(cfg WebDAVSinkConfig) (*WebDAVSink, error) {
+	u, err := url.Parse(cfg.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid WebDAV URL: %w", err)
+	}
+	u.Path += ".tmp"
+
+	if cfg.Client == nil {
+		cfg.Client = http.DefaultClient
+	}
+
+	return &WebDAVSink{
+		cfg:     cfg,
+		tempURL: u.String(),
+	}, nil
+}
+
+// Name returns a human readable name of the sink.
+//
+// NOTE: This is part of the Sink interface.
+func (w *WebDAVSink) Name() string {
+	return fmt.Sprintf("webdav(%s)", w.cfg.URL)
+}
+
+// Upload stores the backup on the WebDAV server by uploading it to a
+// temporary file and then moving that file over the target file.
+//
+// NOTE: This is part of the Sink interface.
+func (w *WebDAVSink) Upload(ctx context.Context, backup PackedMulti) error {
+	err := w.do(ctx, http.MethodPut, w.tempURL, backup, nil)
+	if err != nil {
+		return fmt.Errorf("unable to upload temp file: %w", err)
+	}
+
+	err = w.do(ctx, "MOVE", w.tempURL, nil, map[string]string{
+		"Destination": w.cfg.URL,
+		"Overwrite":   "T",
+	})
+	if err != nil {
+		return fmt.Errorf("unable to move temp file into place: %w",
+			err)
+	}
+
+	return nil
+}
+
+// do performs a single request against the WebDAV server.
+func (w *WebDAVSink) do(ctx context.Context, method, target string,
+	body []byte, headers map[string]string) error {
+
+	req, err := http.NewRequestWithContext(
+		ctx, method, target, bytes.NewReader(body),
+	)
+	if err != nil {
+		return err
+	}
+
+	if body != nil {
+		req.Header.Set("Content-Type", "application/octet-stream")
+	}
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	if w.cfg.Username != "" {
+		req.SetBasicAuth(w.cfg.Username, w.cfg.Password)
+	}
+
+	resp, err := w.cfg.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return checkHTTPResponse(resp)
+}
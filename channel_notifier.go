@@ -117,6 +117,12 @@ func (c *channelNotifier) SubscribeChans(startingChans map[wire.OutPoint]struct{
 				case channelnotifier.OpenChannelEvent:
 					sendChanOpenUpdate(event.Channel)
 
+				// The commitment of a channel was upgraded,
+				// we'll replace its backup as the channel type
+				// may have changed.
+				case channelnotifier.UpgradedChannelEvent:
+					sendChanOpenUpdate(event.Channel)
+
 				// An existing channel has been closed, we'll
 				// send only the chanPoint of the closed
 				// channel to the sub-swapper.
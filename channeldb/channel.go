@@ -119,6 +119,11 @@ var (
 	// broadcasted when moving the channel to state CoopBroadcasted.
 	coopCloseTxKey = []byte("coop-closing-tx-key")
 
+	// coopCloseTxidsKey points to the txids of all cooperative closing
+	// txns that we broadcasted or signed. With the RBF cooperative close
+	// protocol, any of these may end up confirming.
+	coopCloseTxidsKey = []byte("coop-closing-txids-key")
+
 	// commitDiffKey stores the current pending commitment state we've
 	// extended to the remote party (if any). Each time we propose a new
 	// state, we store the information necessary to reconstruct this state
@@ -368,9 +373,8 @@ func (c ChannelType) HasScidAliasFeature() bool {
 // limit their exposure, enact flow control and ensure that all HTLCs are
 // economically relevant. This struct will be mirrored for both sides of the
 // channel, as each side will enforce various constraints that MUST be adhered
-// to for the life time of the channel. The parameters for each of these
-// constraints are static for the duration of the channel, meaning the channel
-// must be torn down for them to change.
+// to for the life time of the channel. Some of these constraints can only be
+// changed by upgrading the commitment of the channel, see UpgradeCommitment.
 type ChannelConstraints struct {
 	// DustLimit is the threshold (in satoshis) below which any outputs
 	// should be trimmed. When an output is trimmed, it isn't materialized
@@ -817,6 +821,11 @@ type OpenChannel struct {
 	// have private key isolation from lnd.
 	RevocationKeyLocator keychain.KeyLocator
 
+	// CommitUpgrades is the history of the commitment upgrades of the
+	// channel, ordered from oldest to newest. It's empty for channels that
+	// still use the parameters negotiated during funding.
+	CommitUpgrades []CommitUpgrade
+
 	// confirmedScid is the confirmed ShortChannelID for a zero-conf
 	// channel. If the channel is unconfirmed, then this will be the
 	// default ShortChannelID. This is only set for zero-conf channels.
@@ -1488,21 +1497,73 @@ func (c *OpenChannel) MarkCommitmentBroadcasted(closeTx *wire.MsgTx,
 // should be rebroadcast upon startup. This is only used to republish and
 // ensure propagation, and we should still handle the case where a different tx
 // actually hits the chain.
+//
+// The txid of every close tx passed in is recorded as well, so all versions
+// of a replaceable closing transaction can be recognized once one of them
+// confirms.
 func (c *OpenChannel) MarkCoopBroadcasted(closeTx *wire.MsgTx,
 	locallyInitiated bool) error {
 
+	var fs []func(kvdb.RwBucket) error
+	if closeTx != nil {
+		txid := closeTx.TxHash()
+		fs = append(fs, func(chanBucket kvdb.RwBucket) error {
+			return addCoopCloseTxid(chanBucket, txid)
+		})
+	}
+
 	return c.markBroadcasted(
 		ChanStatusCoopBroadcasted, coopCloseTxKey, closeTx,
-		locallyInitiated,
+		locallyInitiated, fs...,
 	)
 }
 
+// AddCoopCloseTxid records the txid of a cooperative closing transaction that
+// may appear on chain without being broadcast by us, such as a closing
+// transaction we proposed that the remote party can complete with its own
+// signature. Unlike MarkCoopBroadcasted, the status of the channel isn't
+// changed.
+func (c *OpenChannel) AddCoopCloseTxid(txid chainhash.Hash) error {
+	c.Lock()
+	defer c.Unlock()
+
+	return kvdb.Update(c.Db.backend, func(tx kvdb.RwTx) error {
+		chanBucket, err := fetchChanBucketRw(
+			tx, c.IdentityPub, &c.FundingOutpoint, c.ChainHash,
+		)
+		if err != nil {
+			return err
+		}
+
+		return addCoopCloseTxid(chanBucket, txid)
+	}, func() {})
+}
+
+// addCoopCloseTxid adds the given txid to the set of cooperative closing
+// txids stored in the channel bucket, if it isn't already part of it.
+func addCoopCloseTxid(chanBucket kvdb.RwBucket, txid chainhash.Hash) error {
+	txids := chanBucket.Get(coopCloseTxidsKey)
+	for i := 0; i+chainhash.HashSize <= len(txids); i += chainhash.HashSize {
+		if bytes.Equal(txids[i:i+chainhash.HashSize], txid[:]) {
+			return nil
+		}
+	}
+
+	newTxids := make([]byte, 0, len(txids)+chainhash.HashSize)
+	newTxids = append(newTxids, txids...)
+	newTxids = append(newTxids, txid[:]...)
+
+	return chanBucket.Put(coopCloseTxidsKey, newTxids)
+}
+
 // markBroadcasted is a helper function which modifies the channel status of the
 // receiving channel and inserts a close transaction under the requested key,
 // which should specify either a coop or force close. It adds a status which
-// indicates the party that initiated the channel close.
+// indicates the party that initiated the channel close. Any additional
+// closures are executed within the same transaction.
 func (c *OpenChannel) markBroadcasted(status ChannelStatus, key []byte,
-	closeTx *wire.MsgTx, locallyInitiated bool) error {
+	closeTx *wire.MsgTx, locallyInitiated bool,
+	fs ...func(kvdb.RwBucket) error) error {
 
 	c.Lock()
 	defer c.Unlock()
@@ -1519,6 +1580,7 @@ func (c *OpenChannel) markBroadcasted(status ChannelStatus, key []byte,
 		putClosingTx = func(chanBucket kvdb.RwBucket) error {
 			return chanBucket.Put(key, b.Bytes())
 		}
+		fs = append(fs, putClosingTx)
 	}
 
 	// Add the initiator status to the status provided. These statuses are
@@ -1530,7 +1592,7 @@ func (c *OpenChannel) markBroadcasted(status ChannelStatus, key []byte,
 		status |= ChanStatusRemoteCloseInitiator
 	}
 
-	return c.putChanStatus(status, putClosingTx)
+	return c.putChanStatus(status, fs...)
 }
 
 // BroadcastedCommitment retrieves the stored unilateral closing tx set during
@@ -1545,6 +1607,44 @@ func (c *OpenChannel) BroadcastedCooperative() (*wire.MsgTx, error) {
 	return c.getClosingTx(coopCloseTxKey)
 }
 
+// CoopCloseTxids returns the txids of all cooperative closing transactions
+// recorded through MarkCoopBroadcasted and AddCoopCloseTxid. If none were
+// recorded, an empty slice is returned.
+func (c *OpenChannel) CoopCloseTxids() ([]chainhash.Hash, error) {
+	var txids []chainhash.Hash
+
+	err := kvdb.View(c.Db.backend, func(tx kvdb.RTx) error {
+		chanBucket, err := fetchChanBucket(
+			tx, c.IdentityPub, &c.FundingOutpoint, c.ChainHash,
+		)
+		switch err {
+		case nil:
+		case ErrNoChanDBExists, ErrNoActiveChannels, ErrChannelNotFound:
+			return nil
+		default:
+			return err
+		}
+
+		bs := chanBucket.Get(coopCloseTxidsKey)
+		for len(bs) >= chainhash.HashSize {
+			var txid chainhash.Hash
+			copy(txid[:], bs[:chainhash.HashSize])
+			txids = append(txids, txid)
+
+			bs = bs[chainhash.HashSize:]
+		}
+
+		return nil
+	}, func() {
+		txids = nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return txids, nil
+}
+
 // getClosingTx is a helper method which returns the stored closing transaction
 // for key. The caller should use either the force or coop closing keys.
 func (c *OpenChannel) getClosingTx(key []byte) (*wire.MsgTx, error) {
@@ -1682,6 +1782,12 @@ func putOpenChannel(chanBucket kvdb.RwBucket, channel *OpenChannel) error {
 		}
 	}
 
+	// If the commitment of the channel has been upgraded, we'll write out
+	// the parameters of the commitments that predate the upgrades.
+	if err := putCommitUpgrades(chanBucket, channel); err != nil {
+		return fmt.Errorf("unable to store commit upgrades: %v", err)
+	}
+
 	// Finally, we'll write out the revocation state for both parties
 	// within a distinct key space.
 	if err := putChanRevocationState(chanBucket, channel); err != nil {
@@ -1724,6 +1830,13 @@ func fetchOpenChannel(chanBucket kvdb.RBucket,
 		channel.ThawHeight = thawHeight
 	}
 
+	// Read the parameters of the commitments that predate any upgrades of
+	// the channel.
+	if err := fetchCommitUpgrades(chanBucket, channel); err != nil {
+		return nil, fmt.Errorf("unable to fetch commit upgrades: %v",
+			err)
+	}
+
 	// Finally, we'll retrieve the current revocation state so we can
 	// properly
 	if err := fetchChanRevocationState(chanBucket, channel); err != nil {
@@ -2058,6 +2171,11 @@ type HTLC struct {
 	// routing.
 	OnionBlob []byte
 
+	// ExtraData contains the tlv stream that was appended to the
+	// update_add_htlc message which added this HTLC. It is used to carry
+	// custom wire records alongside the HTLC.
+	ExtraData lnwire.ExtraOpaqueData
+
 	// HtlcIndex is the HTLC counter index of this active, outstanding
 	// HTLC. This differs from the LogIndex, as the HtlcIndex is only
 	// incremented for each offered HTLC, while they LogIndex is
@@ -2082,9 +2200,33 @@ func SerializeHtlcs(b io.Writer, htlcs ...HTLC) error {
 	}
 
 	for _, htlc := range htlcs {
+		// The extra data isn't part of the original serialization
+		// format. To remain compatible, it is appended to the onion
+		// blob, which always has a fixed size if extra data is
+		// present.
+		onionAndExtraData := htlc.OnionBlob
+		if len(htlc.ExtraData) > 0 {
+			if len(htlc.OnionBlob) != lnwire.OnionPacketSize {
+				return fmt.Errorf("htlc with extra data has "+
+					"invalid onion blob size %v",
+					len(htlc.OnionBlob))
+			}
+
+			onionAndExtraData = make(
+				[]byte, 0,
+				lnwire.OnionPacketSize+len(htlc.ExtraData),
+			)
+			onionAndExtraData = append(
+				onionAndExtraData, htlc.OnionBlob...,
+			)
+			onionAndExtraData = append(
+				onionAndExtraData, htlc.ExtraData...,
+			)
+		}
+
 		if err := WriteElements(b,
 			htlc.Signature, htlc.RHash, htlc.Amt, htlc.RefundTimeout,
-			htlc.OutputIndex, htlc.Incoming, htlc.OnionBlob[:],
+			htlc.OutputIndex, htlc.Incoming, onionAndExtraData,
 			htlc.HtlcIndex, htlc.LogIndex,
 		); err != nil {
 			return err
@@ -2121,6 +2263,15 @@ func DeserializeHtlcs(r io.Reader) ([]HTLC, error) {
 		); err != nil {
 			return htlcs, err
 		}
+
+		// Any bytes beyond the fixed onion blob size are the extra
+		// data of the htlc.
+		const onionSize = lnwire.OnionPacketSize
+		onionAndExtraData := htlcs[i].OnionBlob
+		if len(onionAndExtraData) > onionSize {
+			htlcs[i].OnionBlob = onionAndExtraData[:onionSize:onionSize]
+			htlcs[i].ExtraData = onionAndExtraData[onionSize:]
+		}
 	}
 
 	return htlcs, nil
@@ -4004,6 +4155,10 @@ func deleteOpenChannel(chanBucket kvdb.RwBucket) error {
 		return err
 	}
 
+	if err := chanBucket.Delete(commitUpgradesKey); err != nil {
+		return err
+	}
+
 	if diff := chanBucket.Get(commitDiffKey); diff != nil {
 		return chanBucket.Delete(commitDiffKey)
 	}
@@ -1154,6 +1154,58 @@ func TestFetchWaitingCloseChannels(t *testing.T) {
 	}
 }
 
+// TestCoopCloseTxids asserts that the txids of all cooperative closing
+// transactions are recorded, while the latest one is returned as the
+// broadcasted cooperative close.
+func TestCoopCloseTxids(t *testing.T) {
+	t.Parallel()
+
+	fullDB, err := MakeTestDB(t)
+	require.NoError(t, err, "unable to make test database")
+
+	cdb := fullDB.ChannelStateDB()
+	channel := createTestChannel(t, cdb, openChannelOption())
+
+	// Without any closing transaction, no txids are known.
+	txids, err := channel.CoopCloseTxids()
+	require.NoError(t, err)
+	require.Empty(t, txids)
+
+	// Marking a coop close without a transaction shouldn't record a txid.
+	require.NoError(t, channel.MarkCoopBroadcasted(nil, true))
+	txids, err = channel.CoopCloseTxids()
+	require.NoError(t, err)
+	require.Empty(t, txids)
+
+	// Record two versions of the closing transaction, the second one
+	// twice, which should only be stored once.
+	closeTx1 := wire.NewMsgTx(2)
+	closeTx1.AddTxIn(&wire.TxIn{
+		PreviousOutPoint: channel.FundingOutpoint,
+		Sequence:         wire.MaxTxInSequenceNum - 2,
+	})
+	closeTx1.AddTxOut(&wire.TxOut{Value: 1000})
+
+	closeTx2 := closeTx1.Copy()
+	closeTx2.TxOut[0].Value = 900
+
+	require.NoError(t, channel.MarkCoopBroadcasted(closeTx1, true))
+	require.NoError(t, channel.MarkCoopBroadcasted(closeTx2, true))
+	require.NoError(t, channel.MarkCoopBroadcasted(closeTx2, true))
+
+	txids, err = channel.CoopCloseTxids()
+	require.NoError(t, err)
+	require.Equal(
+		t, []chainhash.Hash{closeTx1.TxHash(), closeTx2.TxHash()},
+		txids,
+	)
+
+	// The latest version is returned as the broadcasted coop close.
+	coopCloseTx, err := channel.BroadcastedCooperative()
+	require.NoError(t, err)
+	require.Equal(t, closeTx2.TxHash(), coopCloseTx.TxHash())
+}
+
 // TestRefresh asserts that Refresh updates the in-memory state of another
 // OpenChannel to reflect a preceding call to MarkOpen on a different
 // OpenChannel.
@@ -1470,6 +1522,44 @@ func TestKeyLocatorEncoding(t *testing.T) {
 	require.Equal(t, keyLoc, decodedKeyLoc)
 }
 
+// TestHtlcExtraDataEncoding tests that the extra data of an htlc survives a
+// serialization round trip and that htlcs without extra data are encoded as
+// before.
+func TestHtlcExtraDataEncoding(t *testing.T) {
+	t.Parallel()
+
+	onionBlob := bytes.Repeat([]byte{1}, lnwire.OnionPacketSize)
+	htlcs := []HTLC{
+		{
+			Signature: testSig.Serialize(),
+			Amt:       10,
+			OnionBlob: []byte("onionblob"),
+			HtlcIndex: 1,
+			LogIndex:  2,
+		},
+		{
+			Signature: testSig.Serialize(),
+			Amt:       20,
+			Incoming:  true,
+			OnionBlob: onionBlob,
+			ExtraData: []byte{0xfe, 0x00, 0x01, 0x00, 0x01, 0x01, 0x02},
+			HtlcIndex: 3,
+			LogIndex:  4,
+		},
+	}
+
+	var b bytes.Buffer
+	require.NoError(t, SerializeHtlcs(&b, htlcs...))
+
+	decoded, err := DeserializeHtlcs(&b)
+	require.NoError(t, err)
+	require.Equal(t, htlcs, decoded)
+
+	// Extra data can't be stored for htlcs with a non-standard onion.
+	htlcs[0].ExtraData = []byte{1}
+	require.Error(t, SerializeHtlcs(&b, htlcs...))
+}
+
 // TestFinalHtlcs tests final htlc storage and retrieval.
 func TestFinalHtlcs(t *testing.T) {
 	t.Parallel()
@@ -0,0 +1,283 @@
+package channeldb
+
+import (
+	"bytes"
+	"errors"
+	"io"
+
+	"github.com/lightningnetwork/lnd/kvdb"
+)
+
+var (
+	// commitUpgradesKey stores the history of the commitment upgrades of a
+	// channel, which allows the parameters of commitments that predate an
+	// upgrade to be recovered. The key is only present within the bucket
+	// of channels that have been upgraded at least once.
+	commitUpgradesKey = []byte("commit-upgrades-key")
+)
+
+// CommitParams are the parameters of a channel that determine the shape of
+// its commitment transactions.
+type CommitParams struct {
+	// ChanType is the commitment format of the channel.
+	ChanType ChannelType
+
+	// LocalChanCfg is the channel configuration of the local node.
+	LocalChanCfg ChannelConfig
+
+	// RemoteChanCfg is the channel configuration of the remote node.
+	RemoteChanCfg ChannelConfig
+}
+
+// CommitUpgrade records a change of the commitment parameters of a channel
+// that happened while the channel was open.
+type CommitUpgrade struct {
+	// LocalHeight is the height of the first local commitment that was
+	// constructed using the new parameters.
+	LocalHeight uint64
+
+	// RemoteHeight is the height of the first remote commitment that was
+	// constructed using the new parameters.
+	RemoteHeight uint64
+
+	// Prev holds the parameters that were used for all commitments below
+	// the upgrade heights.
+	Prev CommitParams
+}
+
+// CommitParams returns the parameters currently used to construct new
+// commitments.
+func (c *OpenChannel) CommitParams() CommitParams {
+	c.RLock()
+	defer c.RUnlock()
+
+	return c.commitParams()
+}
+
+// commitParams returns the parameters currently used to construct new
+// commitments.
+//
+// NOTE: The channel's mutex must be held when calling this method.
+func (c *OpenChannel) commitParams() CommitParams {
+	return CommitParams{
+		ChanType:      c.ChanType,
+		LocalChanCfg:  c.LocalChanCfg,
+		RemoteChanCfg: c.RemoteChanCfg,
+	}
+}
+
+// UpgradeCommitment switches the channel over to new commitment parameters,
+// which are used for all local commitments starting at localHeight and all
+// remote commitments starting at remoteHeight. The replaced parameters are
+// added to the upgrade history of the channel, so that commitments created
+// before the upgrade can still be resolved on chain.
+func (c *OpenChannel) UpgradeCommitment(params *CommitParams, localHeight,
+	remoteHeight uint64) error {
+
+	c.Lock()
+	defer c.Unlock()
+
+	upgrade := CommitUpgrade{
+		LocalHeight:  localHeight,
+		RemoteHeight: remoteHeight,
+		Prev:         c.commitParams(),
+	}
+
+	if err := kvdb.Update(c.Db.backend, func(tx kvdb.RwTx) error {
+		chanBucket, err := fetchChanBucketRw(
+			tx, c.IdentityPub, &c.FundingOutpoint, c.ChainHash,
+		)
+		if err != nil {
+			return err
+		}
+
+		channel, err := fetchOpenChannel(
+			chanBucket, &c.FundingOutpoint,
+		)
+		if err != nil {
+			return err
+		}
+
+		channel.ChanType = params.ChanType
+		channel.LocalChanCfg = params.LocalChanCfg
+		channel.RemoteChanCfg = params.RemoteChanCfg
+		channel.CommitUpgrades = append(
+			channel.CommitUpgrades, upgrade,
+		)
+
+		return putOpenChannel(chanBucket, channel)
+	}, func() {}); err != nil {
+		return err
+	}
+
+	c.ChanType = params.ChanType
+	c.LocalChanCfg = params.LocalChanCfg
+	c.RemoteChanCfg = params.RemoteChanCfg
+	c.CommitUpgrades = append(c.CommitUpgrades, upgrade)
+
+	return nil
+}
+
+// CommitParamsAt returns the parameters that were used to construct the local
+// or remote commitment at the given height. The parameters are read from disk
+// if the channel is still open, such that upgrades performed through another
+// instance of the channel state are taken into account as well.
+func (c *OpenChannel) CommitParamsAt(local bool,
+	height uint64) (*CommitParams, error) {
+
+	c.RLock()
+	defer c.RUnlock()
+
+	current := c.commitParams()
+	upgrades := c.CommitUpgrades
+
+	if c.Db != nil {
+		err := kvdb.View(c.Db.backend, func(tx kvdb.RTx) error {
+			chanBucket, err := fetchChanBucket(
+				tx, c.IdentityPub, &c.FundingOutpoint,
+				c.ChainHash,
+			)
+			if err != nil {
+				return err
+			}
+
+			channel := &OpenChannel{}
+			err = fetchChanInfo(chanBucket, channel)
+			if err != nil {
+				return err
+			}
+			err = fetchCommitUpgrades(chanBucket, channel)
+			if err != nil {
+				return err
+			}
+
+			current = channel.commitParams()
+			upgrades = channel.CommitUpgrades
+
+			return nil
+		}, func() {})
+
+		// A channel that is no longer open was loaded from the
+		// historical channel bucket, so its in-memory state is final.
+		switch {
+		case errors.Is(err, ErrNoChanDBExists),
+			errors.Is(err, ErrNoActiveChannels),
+			errors.Is(err, ErrChannelNotFound):
+
+		case err != nil:
+			return nil, err
+		}
+	}
+
+	// The upgrades are stored in the order they were performed, so the
+	// first upgrade at a height greater than the requested one holds the
+	// parameters that were used back then.
+	for _, upgrade := range upgrades {
+		upgradeHeight := upgrade.RemoteHeight
+		if local {
+			upgradeHeight = upgrade.LocalHeight
+		}
+
+		if height < upgradeHeight {
+			prev := upgrade.Prev
+			return &prev, nil
+		}
+	}
+
+	return &current, nil
+}
+
+// putCommitUpgrades stores the upgrade history of the channel, if it has been
+// upgraded before.
+func putCommitUpgrades(chanBucket kvdb.RwBucket, channel *OpenChannel) error {
+	if len(channel.CommitUpgrades) == 0 {
+		return nil
+	}
+
+	var b bytes.Buffer
+	err := serializeCommitUpgrades(&b, channel.CommitUpgrades)
+	if err != nil {
+		return err
+	}
+
+	return chanBucket.Put(commitUpgradesKey, b.Bytes())
+}
+
+// fetchCommitUpgrades reads the upgrade history of the channel. Channels that
+// have never been upgraded don't have a history stored.
+func fetchCommitUpgrades(chanBucket kvdb.RBucket, channel *OpenChannel) error {
+	upgradeBytes := chanBucket.Get(commitUpgradesKey)
+	if upgradeBytes == nil {
+		return nil
+	}
+
+	upgrades, err := deserializeCommitUpgrades(
+		bytes.NewReader(upgradeBytes),
+	)
+	if err != nil {
+		return err
+	}
+	channel.CommitUpgrades = upgrades
+
+	return nil
+}
+
+// serializeCommitUpgrades writes the given upgrade history to w.
+func serializeCommitUpgrades(w io.Writer, upgrades []CommitUpgrade) error {
+	if err := WriteElement(w, uint32(len(upgrades))); err != nil {
+		return err
+	}
+
+	for _, upgrade := range upgrades {
+		err := WriteElements(
+			w, upgrade.LocalHeight, upgrade.RemoteHeight,
+			upgrade.Prev.ChanType,
+		)
+		if err != nil {
+			return err
+		}
+
+		err = writeChanConfig(w, &upgrade.Prev.LocalChanCfg)
+		if err != nil {
+			return err
+		}
+		err = writeChanConfig(w, &upgrade.Prev.RemoteChanCfg)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// deserializeCommitUpgrades reads an upgrade history from r.
+func deserializeCommitUpgrades(r io.Reader) ([]CommitUpgrade, error) {
+	var numUpgrades uint32
+	if err := ReadElement(r, &numUpgrades); err != nil {
+		return nil, err
+	}
+
+	upgrades := make([]CommitUpgrade, numUpgrades)
+	for i := range upgrades {
+		upgrade := &upgrades[i]
+
+		err := ReadElements(
+			r, &upgrade.LocalHeight, &upgrade.RemoteHeight,
+			&upgrade.Prev.ChanType,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		err = readChanConfig(r, &upgrade.Prev.LocalChanCfg)
+		if err != nil {
+			return nil, err
+		}
+		err = readChanConfig(r, &upgrade.Prev.RemoteChanCfg)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return upgrades, nil
+}
@@ -0,0 +1,108 @@
+package channeldb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestUpgradeCommitment tests that upgrading the commitment of a channel
+// persists the new parameters, and that the parameters of commitments that
+// predate the upgrade can still be looked up, including through stale copies
+// of the channel state and after the channel has been closed.
+func TestUpgradeCommitment(t *testing.T) {
+	t.Parallel()
+
+	fullDB, err := MakeTestDB(t)
+	require.NoError(t, err, "unable to make test database")
+
+	cdb := fullDB.ChannelStateDB()
+
+	state := createTestChannel(t, cdb, openChannelOption())
+	oldParams := state.CommitParams()
+
+	// Load a second copy of the channel state, which won't observe the
+	// upgrade in memory.
+	channels, err := cdb.FetchOpenChannels(state.IdentityPub)
+	require.NoError(t, err)
+	require.Len(t, channels, 1)
+	staleState := channels[0]
+
+	newParams := oldParams
+	newParams.ChanType |= AnchorOutputsBit | ZeroHtlcTxFeeBit
+	newParams.LocalChanCfg.DustLimit = 354
+	newParams.RemoteChanCfg.CsvDelay = 144
+	newParams.RemoteChanCfg.MaxAcceptedHtlcs = 30
+
+	localHeight := state.LocalCommitment.CommitHeight + 1
+	remoteHeight := state.RemoteCommitment.CommitHeight + 1
+	err = state.UpgradeCommitment(&newParams, localHeight, remoteHeight)
+	require.NoError(t, err)
+
+	// The in-memory state reflects the upgrade.
+	require.Equal(t, newParams, state.CommitParams())
+	require.Len(t, state.CommitUpgrades, 1)
+
+	// So does the channel state on disk.
+	channels, err = cdb.FetchOpenChannels(state.IdentityPub)
+	require.NoError(t, err)
+	require.Len(t, channels, 1)
+	require.Equal(t, newParams.ChanType, channels[0].ChanType)
+	require.Equal(
+		t, newParams.LocalChanCfg.ChannelConstraints,
+		channels[0].LocalChanCfg.ChannelConstraints,
+	)
+	require.Equal(
+		t, newParams.RemoteChanCfg.ChannelConstraints,
+		channels[0].RemoteChanCfg.ChannelConstraints,
+	)
+	require.Len(t, channels[0].CommitUpgrades, 1)
+	require.Equal(t, localHeight, channels[0].CommitUpgrades[0].LocalHeight)
+	require.Equal(
+		t, remoteHeight, channels[0].CommitUpgrades[0].RemoteHeight,
+	)
+
+	assertParams := func(c *OpenChannel, local bool, height uint64,
+		expected CommitParams) {
+
+		t.Helper()
+
+		params, err := c.CommitParamsAt(local, height)
+		require.NoError(t, err)
+		require.Equal(t, expected.ChanType, params.ChanType)
+		require.Equal(
+			t, expected.LocalChanCfg.ChannelConstraints,
+			params.LocalChanCfg.ChannelConstraints,
+		)
+		require.Equal(
+			t, expected.RemoteChanCfg.ChannelConstraints,
+			params.RemoteChanCfg.ChannelConstraints,
+		)
+	}
+
+	// Commitments below the upgrade heights use the old parameters, all
+	// others the new ones. This also holds for the stale copy, as the
+	// parameters are read from disk.
+	for _, c := range []*OpenChannel{state, staleState} {
+		assertParams(c, true, localHeight-1, oldParams)
+		assertParams(c, true, localHeight, newParams)
+		assertParams(c, false, remoteHeight-1, oldParams)
+		assertParams(c, false, remoteHeight, newParams)
+	}
+
+	// Once the channel is closed, the history is retained within the
+	// historical channel bucket.
+	err = state.CloseChannel(&ChannelCloseSummary{
+		ChanPoint: state.FundingOutpoint,
+		RemotePub: state.IdentityPub,
+		CloseType: CooperativeClose,
+	})
+	require.NoError(t, err)
+
+	histChannel, err := cdb.FetchHistoricalChannel(&state.FundingOutpoint)
+	require.NoError(t, err)
+	require.Len(t, histChannel.CommitUpgrades, 1)
+
+	assertParams(histChannel, false, remoteHeight-1, oldParams)
+	assertParams(histChannel, false, remoteHeight, newParams)
+}
@@ -442,6 +442,9 @@ var dbTopLevelBuckets = [][]byte{
 	outpointBucket,
 	chanIDBucket,
 	historicalChannelBucket,
+	peerPolicyBucket,
+	soldLeaseBucket,
+	boughtLeaseBucket,
 }
 
 // Wipe completely deletes all saved state within all used buckets within the
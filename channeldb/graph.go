@@ -26,6 +26,7 @@ import (
 	"github.com/lightningnetwork/lnd/kvdb"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/routing/route"
+	"github.com/lightningnetwork/lnd/tlv"
 )
 
 var (
@@ -3026,6 +3027,36 @@ func (l *LightningNode) ForEachChannel(tx kvdb.RTx,
 	return nodeTraversal(tx, nodePub, db, cb)
 }
 
+// ChanAnnVersion denotes the version of the channel announcement that was used
+// to announce a channel.
+type ChanAnnVersion uint8
+
+const (
+	// ChanAnnVersion1 is the version of channels announced with a
+	// ChannelAnnouncement message, proving ownership of a P2WSH multisig
+	// funding output with four ECDSA signatures.
+	ChanAnnVersion1 ChanAnnVersion = 0
+
+	// ChanAnnVersion2 is the version of channels announced with a
+	// ChannelAnnouncement2 message, proving ownership of a P2TR funding
+	// output with a single MuSig2 Schnorr signature.
+	ChanAnnVersion2 ChanAnnVersion = 1
+)
+
+// String returns a human readable version of the announcement version.
+func (v ChanAnnVersion) String() string {
+	switch v {
+	case ChanAnnVersion1:
+		return "v1"
+
+	case ChanAnnVersion2:
+		return "v2"
+
+	default:
+		return fmt.Sprintf("unknown(%d)", uint8(v))
+	}
+}
+
 // ChannelEdgeInfo represents a fully authenticated channel along with all its
 // unique attributes. Once an authenticated channel announcement has been
 // processed on the network, then an instance of ChannelEdgeInfo encapsulating
@@ -3065,6 +3096,15 @@ type ChannelEdgeInfo struct {
 	// specific features that this channel edge supports.
 	Features []byte
 
+	// Version is the version of the announcement the channel was announced
+	// with. For ChanAnnVersion2 channels, the bitcoin keys are optional
+	// and left zero if they weren't included in the announcement.
+	Version ChanAnnVersion
+
+	// MerkleRootHash is the optional tapscript root committed to by the
+	// taproot funding output of a ChanAnnVersion2 channel.
+	MerkleRootHash *[32]byte
+
 	// AuthProof is the authentication proof for this channel. This proof
 	// contains a set of signatures binding four identities, which attests
 	// to the legitimacy of the advertised channel.
@@ -3190,6 +3230,40 @@ func (c *ChannelEdgeInfo) BitcoinKey2() (*btcec.PublicKey, error) {
 	return key, nil
 }
 
+// FundingPkScript returns the script of the funding output of the channel,
+// which is a P2WSH multisig script for ChanAnnVersion1 channels and a P2TR
+// script for ChanAnnVersion2 channels.
+func (c *ChannelEdgeInfo) FundingPkScript() ([]byte, error) {
+	if c.Version != ChanAnnVersion2 {
+		return genMultiSigP2WSH(
+			c.BitcoinKey1Bytes[:], c.BitcoinKey2Bytes[:],
+		)
+	}
+
+	// If the announcement didn't include any bitcoin keys, the node keys
+	// are aggregated into the internal key of the funding output instead.
+	key1Bytes, key2Bytes := c.NodeKey1Bytes, c.NodeKey2Bytes
+	if c.BitcoinKey1Bytes != [33]byte{} {
+		key1Bytes, key2Bytes = c.BitcoinKey1Bytes, c.BitcoinKey2Bytes
+	}
+
+	key1, err := btcec.ParsePubKey(key1Bytes[:])
+	if err != nil {
+		return nil, err
+	}
+	key2, err := btcec.ParsePubKey(key2Bytes[:])
+	if err != nil {
+		return nil, err
+	}
+
+	var merkleRoot []byte
+	if c.MerkleRootHash != nil {
+		merkleRoot = c.MerkleRootHash[:]
+	}
+
+	return input.GenTaprootFundingScript(key1, key2, merkleRoot)
+}
+
 // OtherNodeKeyBytes returns the node key bytes of the other end of
 // the channel.
 func (c *ChannelEdgeInfo) OtherNodeKeyBytes(thisNodeKey []byte) (
@@ -3290,6 +3364,11 @@ type ChannelAuthProof struct {
 	// BitcoinSig2Bytes are the raw bytes of the second bitcoin signature
 	// encoded in DER format.
 	BitcoinSig2Bytes []byte
+
+	// SchnorrSigBytes is the raw 64-byte Schnorr signature that replaces
+	// the four ECDSA signatures for channels announced with a
+	// ChannelAnnouncement2 message.
+	SchnorrSigBytes []byte
 }
 
 // Node1Sig is the signature using the identity key of the node that is first
@@ -3375,8 +3454,13 @@ func (c *ChannelAuthProof) BitcoinSig2() (*ecdsa.Signature, error) {
 }
 
 // IsEmpty check is the authentication proof is empty Proof is empty if at
-// least one of the signatures are equal to nil.
+// least one of the signatures are equal to nil, unless it carries a Schnorr
+// signature instead.
 func (c *ChannelAuthProof) IsEmpty() bool {
+	if len(c.SchnorrSigBytes) != 0 {
+		return false
+	}
+
 	return len(c.NodeSig1Bytes) == 0 ||
 		len(c.NodeSig2Bytes) == 0 ||
 		len(c.BitcoinSig1Bytes) == 0 ||
@@ -3810,10 +3894,7 @@ func (c *ChannelGraph) ChannelView() ([]EdgePoint, error) {
 				return err
 			}
 
-			pkScript, err := genMultiSigP2WSH(
-				edgeInfo.BitcoinKey1Bytes[:],
-				edgeInfo.BitcoinKey2Bytes[:],
-			)
+			pkScript, err := edgeInfo.FundingPkScript()
 			if err != nil {
 				return err
 			}
@@ -4372,9 +4453,101 @@ func putChanEdgeInfo(edgeIndex kvdb.RwBucket, edgeInfo *ChannelEdgeInfo, chanID
 		return err
 	}
 
+	// The fields that are specific to taproot announcements are appended
+	// as a TLV stream, which is omitted for regular channels to keep their
+	// encoding unchanged.
+	if edgeInfo.Version == ChanAnnVersion2 {
+		if err := serializeChanEdgeInfoV2(&b, edgeInfo); err != nil {
+			return err
+		}
+	}
+
 	return edgeIndex.Put(chanID[:], b.Bytes())
 }
 
+const (
+	// edgeInfoVersionType is the TLV type of the announcement version of
+	// a channel edge.
+	edgeInfoVersionType tlv.Type = 0
+
+	// edgeInfoSchnorrSigType is the TLV type of the Schnorr signature of
+	// the authentication proof of a channel edge.
+	edgeInfoSchnorrSigType tlv.Type = 1
+
+	// edgeInfoMerkleRootType is the TLV type of the tapscript root of the
+	// funding output of a channel edge.
+	edgeInfoMerkleRootType tlv.Type = 3
+)
+
+// serializeChanEdgeInfoV2 writes the fields of a ChanAnnVersion2 channel edge
+// as a TLV stream.
+func serializeChanEdgeInfoV2(w io.Writer, edgeInfo *ChannelEdgeInfo) error {
+	version := uint8(edgeInfo.Version)
+	records := []tlv.Record{
+		tlv.MakePrimitiveRecord(edgeInfoVersionType, &version),
+	}
+
+	if edgeInfo.AuthProof != nil &&
+		len(edgeInfo.AuthProof.SchnorrSigBytes) != 0 {
+
+		records = append(records, tlv.MakePrimitiveRecord(
+			edgeInfoSchnorrSigType,
+			&edgeInfo.AuthProof.SchnorrSigBytes,
+		))
+	}
+
+	if edgeInfo.MerkleRootHash != nil {
+		records = append(records, tlv.MakePrimitiveRecord(
+			edgeInfoMerkleRootType, edgeInfo.MerkleRootHash,
+		))
+	}
+
+	tlvStream, err := tlv.NewStream(records...)
+	if err != nil {
+		return err
+	}
+
+	return tlvStream.Encode(w)
+}
+
+// deserializeChanEdgeInfoV2 reads the TLV stream that follows the fields of a
+// ChanAnnVersion2 channel edge.
+func deserializeChanEdgeInfoV2(r io.Reader, edgeInfo *ChannelEdgeInfo) error {
+	var (
+		version    uint8
+		schnorrSig []byte
+		merkleRoot [32]byte
+	)
+	tlvStream, err := tlv.NewStream(
+		tlv.MakePrimitiveRecord(edgeInfoVersionType, &version),
+		tlv.MakePrimitiveRecord(edgeInfoSchnorrSigType, &schnorrSig),
+		tlv.MakePrimitiveRecord(edgeInfoMerkleRootType, &merkleRoot),
+	)
+	if err != nil {
+		return err
+	}
+
+	parsedTypes, err := tlvStream.DecodeWithParsedTypes(r)
+	if err != nil {
+		return err
+	}
+
+	edgeInfo.Version = ChanAnnVersion(version)
+
+	if _, ok := parsedTypes[edgeInfoSchnorrSigType]; ok {
+		if edgeInfo.AuthProof == nil {
+			edgeInfo.AuthProof = &ChannelAuthProof{}
+		}
+		edgeInfo.AuthProof.SchnorrSigBytes = schnorrSig
+	}
+
+	if _, ok := parsedTypes[edgeInfoMerkleRootType]; ok {
+		edgeInfo.MerkleRootHash = &merkleRoot
+	}
+
+	return nil
+}
+
 func fetchChanEdgeInfo(edgeIndex kvdb.RBucket,
 	chanID []byte) (ChannelEdgeInfo, error) {
 
@@ -4456,11 +4629,18 @@ func deserializeChanEdgeInfo(r io.Reader) (ChannelEdgeInfo, error) {
 	)
 	switch {
 	case err == io.ErrUnexpectedEOF:
+		return edgeInfo, nil
 	case err == io.EOF:
+		return edgeInfo, nil
 	case err != nil:
 		return ChannelEdgeInfo{}, err
 	}
 
+	// Any remaining bytes hold the fields of a taproot announcement.
+	if err := deserializeChanEdgeInfoV2(r, &edgeInfo); err != nil {
+		return ChannelEdgeInfo{}, err
+	}
+
 	return edgeInfo, nil
 }
 
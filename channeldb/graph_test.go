@@ -22,6 +22,7 @@ import (
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/wire"
 	"github.com/davecgh/go-spew/spew"
+	"github.com/lightningnetwork/lnd/input"
 	"github.com/lightningnetwork/lnd/kvdb"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/routing/route"
@@ -386,6 +387,71 @@ func TestEdgeInsertionDeletion(t *testing.T) {
 	}
 }
 
+// TestTaprootEdgeInfo asserts that the fields specific to channels announced
+// with a taproot announcement are persisted, and that the channel view watches
+// their taproot funding output.
+func TestTaprootEdgeInfo(t *testing.T) {
+	t.Parallel()
+
+	graph, err := MakeTestGraph(t)
+	require.NoError(t, err, "unable to make test database")
+
+	node1, err := createTestVertex(graph.db)
+	require.NoError(t, err, "unable to create test node")
+	node2, err := createTestVertex(graph.db)
+	require.NoError(t, err, "unable to create test node")
+	node1Pub, err := node1.PubKey()
+	require.NoError(t, err, "unable to generate node key")
+	node2Pub, err := node2.PubKey()
+	require.NoError(t, err, "unable to generate node key")
+
+	// The announcement didn't include any bitcoin keys, so the funding
+	// output commits to the node keys and the merkle root.
+	merkleRoot := [32]byte{1, 2, 3}
+	chanID := uint64(prand.Int63())
+	edgeInfo := ChannelEdgeInfo{
+		ChannelID: chanID,
+		ChainHash: key,
+		Version:   ChanAnnVersion2,
+		AuthProof: &ChannelAuthProof{
+			SchnorrSigBytes: bytes.Repeat([]byte{1}, 64),
+		},
+		MerkleRootHash: &merkleRoot,
+		ChannelPoint: wire.OutPoint{
+			Hash:  rev,
+			Index: 1,
+		},
+		Capacity:        9000,
+		ExtraOpaqueData: []byte{},
+	}
+	copy(edgeInfo.NodeKey1Bytes[:], node1Pub.SerializeCompressed())
+	copy(edgeInfo.NodeKey2Bytes[:], node2Pub.SerializeCompressed())
+
+	require.NoError(t, graph.AddChannelEdge(&edgeInfo))
+
+	dbEdgeInfo, _, _, err := graph.FetchChannelEdgesByID(chanID)
+	require.NoError(t, err)
+	require.Equal(t, ChanAnnVersion2, dbEdgeInfo.Version)
+	require.Equal(t, &merkleRoot, dbEdgeInfo.MerkleRootHash)
+	require.Equal(t, [33]byte{}, dbEdgeInfo.BitcoinKey1Bytes)
+	require.NotNil(t, dbEdgeInfo.AuthProof)
+	require.Equal(
+		t, edgeInfo.AuthProof.SchnorrSigBytes,
+		dbEdgeInfo.AuthProof.SchnorrSigBytes,
+	)
+	require.Empty(t, dbEdgeInfo.AuthProof.NodeSig1Bytes)
+
+	expectedScript, err := input.GenTaprootFundingScript(
+		node1Pub, node2Pub, merkleRoot[:],
+	)
+	require.NoError(t, err)
+
+	edgePoints, err := graph.ChannelView()
+	require.NoError(t, err)
+	require.Len(t, edgePoints, 1)
+	require.Equal(t, expectedScript, edgePoints[0].FundingPkScript)
+}
+
 func createEdge(height, txIndex uint32, txPosition uint16, outPointIndex uint32,
 	node1, node2 *LightningNode) (ChannelEdgeInfo, lnwire.ShortChannelID) {
 
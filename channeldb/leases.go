@@ -0,0 +1,344 @@
+package channeldb
+
+import (
+	"bytes"
+	"errors"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/lightningnetwork/lnd/kvdb"
+)
+
+var (
+	// soldLeaseBucket is the name of a top level bucket in which we store
+	// the channel leases we sold through our liquidity ad whose channel
+	// isn't pending yet. The leases are keyed by the buyer and the lease
+	// ID the buyer chose.
+	//
+	// sold-lease-bucket
+	//      |
+	//      |--<buyer pubkey><lease id>: <lease>
+	soldLeaseBucket = []byte("sold-lease-bucket")
+
+	// boughtLeaseBucket is the name of a top level bucket in which we
+	// store the channel leases we bought from liquidity ads whose channel
+	// isn't pending yet. The leases are keyed by the seller and the lease
+	// ID we chose.
+	//
+	// bought-lease-bucket
+	//      |
+	//      |--<seller pubkey><lease id>: <lease>
+	boughtLeaseBucket = []byte("bought-lease-bucket")
+
+	// ErrSoldLeaseNotFound is returned when we try to delete a sold lease
+	// that isn't stored.
+	ErrSoldLeaseNotFound = errors.New("sold lease not found")
+
+	// ErrBoughtLeaseNotFound is returned when we try to delete a bought
+	// lease that isn't stored.
+	ErrBoughtLeaseNotFound = errors.New("bought lease not found")
+)
+
+// SoldLease is a channel lease we sold through our liquidity ad. It's stored
+// from the moment we offered it until its channel is pending, so that we can
+// still open the channel of a paid lease after a restart.
+type SoldLease struct {
+	// LeaseID identifies the lease throughout the request flow.
+	LeaseID [32]byte
+
+	// Buyer is the node the lease was sold to.
+	Buyer *btcec.PublicKey
+
+	// LeaseAmt is the capacity of the lease channel.
+	LeaseAmt btcutil.Amount
+
+	// LeaseDuration is the number of blocks our funds are locked for once
+	// the lease channel is opened.
+	LeaseDuration uint32
+
+	// Fee is the fee paid for the lease.
+	Fee btcutil.Amount
+
+	// LeaseExpiry is the earliest absolute height the lease can expire
+	// at.
+	LeaseExpiry uint32
+
+	// PaymentRequest is the invoice for the lease fee.
+	PaymentRequest string
+
+	// PaymentHash is the payment hash of the invoice for the lease fee.
+	PaymentHash [32]byte
+
+	// CreatedAt is the time the lease was offered.
+	CreatedAt time.Time
+}
+
+// key returns the key the lease is stored under.
+func (l *SoldLease) key() []byte {
+	return leaseKey(l.Buyer, l.LeaseID)
+}
+
+// PutSoldLease stores a lease we sold, replacing any lease with the same
+// buyer and lease ID.
+func (c *ChannelStateDB) PutSoldLease(lease *SoldLease) error {
+	var b bytes.Buffer
+	err := WriteElements(
+		&b, lease.LeaseAmt, lease.LeaseDuration, lease.Fee,
+		lease.LeaseExpiry, []byte(lease.PaymentRequest),
+		lease.PaymentHash,
+	)
+	if err != nil {
+		return err
+	}
+	if err := serializeTime(&b, lease.CreatedAt); err != nil {
+		return err
+	}
+
+	return c.putLease(soldLeaseBucket, lease.key(), b.Bytes())
+}
+
+// DeleteSoldLease removes the lease we sold with the same buyer and lease ID
+// as the given one. If there is no such lease, ErrSoldLeaseNotFound is
+// returned.
+func (c *ChannelStateDB) DeleteSoldLease(lease *SoldLease) error {
+	return c.deleteLease(
+		soldLeaseBucket, lease.key(), ErrSoldLeaseNotFound,
+	)
+}
+
+// FetchSoldLeases returns all stored leases we sold.
+func (c *ChannelStateDB) FetchSoldLeases() ([]*SoldLease, error) {
+	var leases []*SoldLease
+	err := c.fetchLeases(soldLeaseBucket, func(k, v []byte) error {
+		lease, err := decodeSoldLease(k, v)
+		if err != nil {
+			return err
+		}
+
+		leases = append(leases, lease)
+		return nil
+	}, func() {
+		leases = nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return leases, nil
+}
+
+// decodeSoldLease decodes a sold lease from the key and value it is stored
+// under.
+func decodeSoldLease(k, v []byte) (*SoldLease, error) {
+	if len(k) != 33+32 {
+		return nil, errors.New("invalid sold lease key")
+	}
+
+	buyer, err := btcec.ParsePubKey(k[:33])
+	if err != nil {
+		return nil, err
+	}
+
+	lease := &SoldLease{
+		Buyer: buyer,
+	}
+	copy(lease.LeaseID[:], k[33:])
+
+	var (
+		r      = bytes.NewReader(v)
+		payReq []byte
+	)
+	err = ReadElements(
+		r, &lease.LeaseAmt, &lease.LeaseDuration, &lease.Fee,
+		&lease.LeaseExpiry, &payReq, &lease.PaymentHash,
+	)
+	if err != nil {
+		return nil, err
+	}
+	lease.PaymentRequest = string(payReq)
+
+	lease.CreatedAt, err = deserializeTime(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return lease, nil
+}
+
+// BoughtLease is a channel lease we bought from a liquidity ad. It's stored
+// from the moment we received the offer for it until its channel is pending,
+// so that we still accept the lease channel after a restart.
+type BoughtLease struct {
+	// LeaseID identifies the lease throughout the request flow.
+	LeaseID [32]byte
+
+	// Seller is the node the lease was bought from.
+	Seller *btcec.PublicKey
+
+	// LeaseAmt is the capacity of the lease channel.
+	LeaseAmt btcutil.Amount
+
+	// LeaseDuration is the number of blocks the funds of the seller are
+	// locked for once the lease channel is opened.
+	LeaseDuration uint32
+
+	// Fee is the fee paid for the lease.
+	Fee btcutil.Amount
+
+	// LeaseExpiry is the earliest absolute height the lease can expire
+	// at.
+	LeaseExpiry uint32
+
+	// PaymentRequest is the invoice for the lease fee.
+	PaymentRequest string
+
+	// PaymentHash is the payment hash of the invoice for the lease fee.
+	PaymentHash [32]byte
+
+	// CreatedAt is the time the offer for the lease was received.
+	CreatedAt time.Time
+}
+
+// key returns the key the lease is stored under.
+func (l *BoughtLease) key() []byte {
+	return leaseKey(l.Seller, l.LeaseID)
+}
+
+// PutBoughtLease stores a lease we bought, replacing any lease with the same
+// seller and lease ID.
+func (c *ChannelStateDB) PutBoughtLease(lease *BoughtLease) error {
+	var b bytes.Buffer
+	err := WriteElements(
+		&b, lease.LeaseAmt, lease.LeaseDuration, lease.Fee,
+		lease.LeaseExpiry, []byte(lease.PaymentRequest),
+		lease.PaymentHash,
+	)
+	if err != nil {
+		return err
+	}
+	if err := serializeTime(&b, lease.CreatedAt); err != nil {
+		return err
+	}
+
+	return c.putLease(boughtLeaseBucket, lease.key(), b.Bytes())
+}
+
+// DeleteBoughtLease removes the lease we bought with the same seller and
+// lease ID as the given one. If there is no such lease,
+// ErrBoughtLeaseNotFound is returned.
+func (c *ChannelStateDB) DeleteBoughtLease(lease *BoughtLease) error {
+	return c.deleteLease(
+		boughtLeaseBucket, lease.key(), ErrBoughtLeaseNotFound,
+	)
+}
+
+// FetchBoughtLeases returns all stored leases we bought.
+func (c *ChannelStateDB) FetchBoughtLeases() ([]*BoughtLease, error) {
+	var leases []*BoughtLease
+	err := c.fetchLeases(boughtLeaseBucket, func(k, v []byte) error {
+		lease, err := decodeBoughtLease(k, v)
+		if err != nil {
+			return err
+		}
+
+		leases = append(leases, lease)
+		return nil
+	}, func() {
+		leases = nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return leases, nil
+}
+
+// decodeBoughtLease decodes a bought lease from the key and value it is
+// stored under.
+func decodeBoughtLease(k, v []byte) (*BoughtLease, error) {
+	if len(k) != 33+32 {
+		return nil, errors.New("invalid bought lease key")
+	}
+
+	seller, err := btcec.ParsePubKey(k[:33])
+	if err != nil {
+		return nil, err
+	}
+
+	lease := &BoughtLease{
+		Seller: seller,
+	}
+	copy(lease.LeaseID[:], k[33:])
+
+	var (
+		r      = bytes.NewReader(v)
+		payReq []byte
+	)
+	err = ReadElements(
+		r, &lease.LeaseAmt, &lease.LeaseDuration, &lease.Fee,
+		&lease.LeaseExpiry, &payReq, &lease.PaymentHash,
+	)
+	if err != nil {
+		return nil, err
+	}
+	lease.PaymentRequest = string(payReq)
+
+	lease.CreatedAt, err = deserializeTime(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return lease, nil
+}
+
+// leaseKey returns the key a lease negotiated with the given peer is stored
+// under.
+func leaseKey(peer *btcec.PublicKey, leaseID [32]byte) []byte {
+	key := make([]byte, 0, 33+32)
+	key = append(key, peer.SerializeCompressed()...)
+
+	return append(key, leaseID[:]...)
+}
+
+// putLease stores a serialized lease in the given top level bucket.
+func (c *ChannelStateDB) putLease(bucketName, key, value []byte) error {
+	return kvdb.Update(c.backend, func(tx kvdb.RwTx) error {
+		bucket, err := tx.CreateTopLevelBucket(bucketName)
+		if err != nil {
+			return err
+		}
+
+		return bucket.Put(key, value)
+	}, func() {})
+}
+
+// deleteLease removes a lease from the given top level bucket, returning
+// errNotFound if it isn't stored.
+func (c *ChannelStateDB) deleteLease(bucketName, key []byte,
+	errNotFound error) error {
+
+	return kvdb.Update(c.backend, func(tx kvdb.RwTx) error {
+		bucket := tx.ReadWriteBucket(bucketName)
+		if bucket == nil || bucket.Get(key) == nil {
+			return errNotFound
+		}
+
+		return bucket.Delete(key)
+	}, func() {})
+}
+
+// fetchLeases calls cb for every lease stored in the given top level bucket.
+// The reset closure is called before the database transaction is retried.
+func (c *ChannelStateDB) fetchLeases(bucketName []byte,
+	cb func(k, v []byte) error, reset func()) error {
+
+	return kvdb.View(c.backend, func(tx kvdb.RTx) error {
+		bucket := tx.ReadBucket(bucketName)
+		if bucket == nil {
+			return nil
+		}
+
+		return bucket.ForEach(cb)
+	}, reset)
+}
@@ -0,0 +1,103 @@
+package channeldb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestSoldLeases tests storing, fetching and deleting the leases we sold.
+func TestSoldLeases(t *testing.T) {
+	fullDB, err := MakeTestDB(t)
+	require.NoError(t, err)
+	db := fullDB.ChannelStateDB()
+
+	leases, err := db.FetchSoldLeases()
+	require.NoError(t, err)
+	require.Empty(t, leases)
+
+	lease1 := &SoldLease{
+		LeaseID:        [32]byte{1},
+		Buyer:          pubKey,
+		LeaseAmt:       500_000,
+		LeaseDuration:  4032,
+		Fee:            6000,
+		LeaseExpiry:    800_000,
+		PaymentRequest: "lnbc1",
+		PaymentHash:    [32]byte{2},
+		CreatedAt:      time.Unix(100, 0),
+	}
+	lease2 := &SoldLease{
+		LeaseID:        [32]byte{3},
+		Buyer:          pubKey,
+		LeaseAmt:       100_000,
+		LeaseDuration:  144,
+		PaymentRequest: "lnbc2",
+		CreatedAt:      time.Unix(200, 0),
+	}
+	require.NoError(t, db.PutSoldLease(lease1))
+	require.NoError(t, db.PutSoldLease(lease2))
+
+	leases, err = db.FetchSoldLeases()
+	require.NoError(t, err)
+	require.ElementsMatch(t, []*SoldLease{lease1, lease2}, leases)
+
+	require.NoError(t, db.DeleteSoldLease(lease1))
+	require.ErrorIs(t, db.DeleteSoldLease(lease1), ErrSoldLeaseNotFound)
+
+	leases, err = db.FetchSoldLeases()
+	require.NoError(t, err)
+	require.Equal(t, []*SoldLease{lease2}, leases)
+}
+
+// TestBoughtLeases tests storing, fetching and deleting the leases we bought.
+func TestBoughtLeases(t *testing.T) {
+	fullDB, err := MakeTestDB(t)
+	require.NoError(t, err)
+	db := fullDB.ChannelStateDB()
+
+	leases, err := db.FetchBoughtLeases()
+	require.NoError(t, err)
+	require.Empty(t, leases)
+
+	lease1 := &BoughtLease{
+		LeaseID:        [32]byte{1},
+		Seller:         pubKey,
+		LeaseAmt:       500_000,
+		LeaseDuration:  4032,
+		Fee:            6000,
+		LeaseExpiry:    800_000,
+		PaymentRequest: "lnbc1",
+		PaymentHash:    [32]byte{2},
+		CreatedAt:      time.Unix(100, 0),
+	}
+	lease2 := &BoughtLease{
+		LeaseID:        [32]byte{3},
+		Seller:         pubKey,
+		LeaseAmt:       100_000,
+		LeaseDuration:  144,
+		PaymentRequest: "lnbc2",
+		CreatedAt:      time.Unix(200, 0),
+	}
+	require.NoError(t, db.PutBoughtLease(lease1))
+	require.NoError(t, db.PutBoughtLease(lease2))
+
+	// Sold leases are kept apart from the ones we bought.
+	soldLeases, err := db.FetchSoldLeases()
+	require.NoError(t, err)
+	require.Empty(t, soldLeases)
+
+	leases, err = db.FetchBoughtLeases()
+	require.NoError(t, err)
+	require.ElementsMatch(t, []*BoughtLease{lease1, lease2}, leases)
+
+	require.NoError(t, db.DeleteBoughtLease(lease1))
+	require.ErrorIs(
+		t, db.DeleteBoughtLease(lease1), ErrBoughtLeaseNotFound,
+	)
+
+	leases, err = db.FetchBoughtLeases()
+	require.NoError(t, err)
+	require.Equal(t, []*BoughtLease{lease2}, leases)
+}
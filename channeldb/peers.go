@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"net"
 	"time"
 
 	"github.com/lightningnetwork/lnd/kvdb"
@@ -20,6 +21,7 @@ var (
 	//      |
 	//      |-- <peer-pubkey>
 	//      |        |--flap-count-key: <ts><flap count>
+	//      |        |--peer-storage-key: <blob>
 	//      |
 	//      |-- <peer-pubkey>
 	//      |        |--flap-count-key: <ts><flap count>
@@ -29,12 +31,56 @@ var (
 	// the timestamp of a peer's last flap count and its all time flap
 	// count.
 	flapCountKey = []byte("flap-count")
+
+	// peerStorageKey is a key used in the peer pubkey sub-bucket that
+	// stores the latest opaque blob the peer asked us to store on its
+	// behalf.
+	peerStorageKey = []byte("peer-storage")
+
+	// peerPolicyBucket is the name of a top level bucket in which we store
+	// the ban and allow lists of our peer connection policy. Each list is
+	// stored in a sub-bucket, keyed by the target of its entries.
+	//
+	// peer-policy-bucket
+	//      |
+	//      |-- ban-list
+	//      |        |--<target>: <created at><expiry><reason>
+	//      |
+	//      |-- allow-list
+	//      |        |--<target>: <created at><expiry><reason>
+	peerPolicyBucket = []byte("peer-policy-bucket")
+
+	// banListBucket is the sub-bucket of the peer policy bucket that holds
+	// the entries of the ban list.
+	banListBucket = []byte("ban-list")
+
+	// allowListBucket is the sub-bucket of the peer policy bucket that
+	// holds the entries of the allow list.
+	allowListBucket = []byte("allow-list")
+)
+
+const (
+	// policyTargetPubkey is the type byte of the key of a peer policy
+	// entry that matches a single node by its public key.
+	policyTargetPubkey byte = 0
+
+	// policyTargetIPNet is the type byte of the key of a peer policy entry
+	// that matches a range of IP addresses.
+	policyTargetIPNet byte = 1
 )
 
 var (
 	// ErrNoPeerBucket is returned when we try to read entries for a peer
 	// that is not tracked.
 	ErrNoPeerBucket = errors.New("peer bucket not found")
+
+	// ErrNoPeerStorage is returned when we try to read the peer storage
+	// blob of a peer that hasn't asked us to store anything.
+	ErrNoPeerStorage = errors.New("no peer storage found")
+
+	// ErrPeerPolicyEntryNotFound is returned when we try to delete an
+	// entry that isn't part of a peer policy list.
+	ErrPeerPolicyEntryNotFound = errors.New("peer policy entry not found")
 )
 
 // FlapCount contains information about a peer's flap count.
@@ -121,3 +167,337 @@ func (d *DB) ReadFlapCount(pubkey route.Vertex) (*FlapCount, error) {
 
 	return &flapCount, nil
 }
+
+// PutPeerStorage stores the opaque blob that a peer asked us to keep on its
+// behalf, replacing any blob previously stored for the same peer.
+func (d *DB) PutPeerStorage(pubkey route.Vertex, blob []byte) error {
+	return kvdb.Update(d, func(tx kvdb.RwTx) error {
+		peers := tx.ReadWriteBucket(peersBucket)
+
+		peerBucket, err := peers.CreateBucketIfNotExists(pubkey[:])
+		if err != nil {
+			return err
+		}
+
+		return peerBucket.Put(peerStorageKey, blob)
+	}, func() {})
+}
+
+// FetchPeerStorage returns the latest blob that a peer asked us to store on
+// its behalf. If the peer never sent us a blob, ErrNoPeerStorage is returned.
+func (d *DB) FetchPeerStorage(pubkey route.Vertex) ([]byte, error) {
+	var blob []byte
+
+	if err := kvdb.View(d, func(tx kvdb.RTx) error {
+		peers := tx.ReadBucket(peersBucket)
+
+		peerBucket := peers.NestedReadBucket(pubkey[:])
+		if peerBucket == nil {
+			return ErrNoPeerStorage
+		}
+
+		storedBlob := peerBucket.Get(peerStorageKey)
+		if storedBlob == nil {
+			return ErrNoPeerStorage
+		}
+
+		blob = make([]byte, len(storedBlob))
+		copy(blob, storedBlob)
+
+		return nil
+	}, func() {
+		blob = nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return blob, nil
+}
+
+// PeerPolicyList identifies one of the lists of our peer connection policy.
+type PeerPolicyList uint8
+
+const (
+	// PeerBanList is the list of nodes and IP ranges that we refuse any
+	// connection with.
+	PeerBanList PeerPolicyList = 0
+
+	// PeerAllowList is the list of nodes and IP ranges that are allowed
+	// to connect to us when only allow-listed peers are accepted.
+	PeerAllowList PeerPolicyList = 1
+)
+
+// String returns a human readable name of the list.
+func (l PeerPolicyList) String() string {
+	switch l {
+	case PeerBanList:
+		return "ban list"
+
+	case PeerAllowList:
+		return "allow list"
+
+	default:
+		return fmt.Sprintf("unknown list %d", uint8(l))
+	}
+}
+
+// bucket returns the name of the sub-bucket the entries of the list are
+// stored in.
+func (l PeerPolicyList) bucket() ([]byte, error) {
+	switch l {
+	case PeerBanList:
+		return banListBucket, nil
+
+	case PeerAllowList:
+		return allowListBucket, nil
+
+	default:
+		return nil, fmt.Errorf("unknown peer policy list: %v", l)
+	}
+}
+
+// PeerPolicyEntry is an entry of one of the lists of our peer connection
+// policy. An entry matches either a single node by its public key or a range
+// of IP addresses.
+type PeerPolicyEntry struct {
+	// Pubkey is the public key of the node matched by the entry. It's nil
+	// if the entry matches a range of IP addresses instead.
+	Pubkey *route.Vertex
+
+	// IPNet is the range of IP addresses matched by the entry. It's nil if
+	// the entry matches a node by its public key instead.
+	IPNet *net.IPNet
+
+	// Reason is a free form description of why the entry was added.
+	Reason string
+
+	// CreatedAt is the time the entry was added.
+	CreatedAt time.Time
+
+	// Expiry is the time after which the entry no longer applies. A zero
+	// value means that the entry never expires.
+	Expiry time.Time
+}
+
+// Expired returns true if the entry no longer applies at the given time.
+func (e *PeerPolicyEntry) Expired(now time.Time) bool {
+	return !e.Expiry.IsZero() && !now.Before(e.Expiry)
+}
+
+// Target returns a human readable description of what the entry matches.
+func (e *PeerPolicyEntry) Target() string {
+	if e.Pubkey != nil {
+		return e.Pubkey.String()
+	}
+
+	if e.IPNet != nil {
+		return e.IPNet.String()
+	}
+
+	return "<none>"
+}
+
+// key returns the key the entry is stored under, which is unique for the
+// target of the entry.
+func (e *PeerPolicyEntry) key() ([]byte, error) {
+	switch {
+	case e.Pubkey != nil && e.IPNet != nil:
+		return nil, errors.New("peer policy entry can't match both a " +
+			"pubkey and an IP range")
+
+	case e.Pubkey != nil:
+		return append([]byte{policyTargetPubkey}, e.Pubkey[:]...), nil
+
+	case e.IPNet != nil:
+		ip := e.IPNet.IP.To4()
+		if ip == nil {
+			ip = e.IPNet.IP.To16()
+		}
+		ones, bits := e.IPNet.Mask.Size()
+		if ip == nil || bits != len(ip)*8 {
+			return nil, fmt.Errorf("invalid IP range: %v", e.IPNet)
+		}
+
+		key := []byte{policyTargetIPNet, byte(len(ip))}
+		key = append(key, ip.Mask(e.IPNet.Mask)...)
+
+		return append(key, byte(ones)), nil
+
+	default:
+		return nil, errors.New("peer policy entry must match either " +
+			"a pubkey or an IP range")
+	}
+}
+
+// decodePeerPolicyEntry decodes an entry from the key and value it is stored
+// under.
+func decodePeerPolicyEntry(k, v []byte) (*PeerPolicyEntry, error) {
+	if len(k) == 0 {
+		return nil, errors.New("empty peer policy key")
+	}
+
+	var entry PeerPolicyEntry
+	switch k[0] {
+	case policyTargetPubkey:
+		pubkey, err := route.NewVertexFromBytes(k[1:])
+		if err != nil {
+			return nil, err
+		}
+		entry.Pubkey = &pubkey
+
+	case policyTargetIPNet:
+		if len(k) < 2 || len(k) != int(k[1])+3 {
+			return nil, fmt.Errorf("invalid IP range key: %x", k)
+		}
+
+		ip := net.IP(append([]byte(nil), k[2:len(k)-1]...))
+		ones := int(k[len(k)-1])
+		entry.IPNet = &net.IPNet{
+			IP:   ip,
+			Mask: net.CIDRMask(ones, len(ip)*8),
+		}
+		if entry.IPNet.Mask == nil {
+			return nil, fmt.Errorf("invalid IP range key: %x", k)
+		}
+
+	default:
+		return nil, fmt.Errorf("unknown peer policy target type: %v",
+			k[0])
+	}
+
+	var (
+		err    error
+		r      = bytes.NewReader(v)
+		reason []byte
+	)
+	entry.CreatedAt, err = deserializeTime(r)
+	if err != nil {
+		return nil, err
+	}
+
+	entry.Expiry, err = deserializeTime(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ReadElements(r, &reason); err != nil {
+		return nil, err
+	}
+	entry.Reason = string(reason)
+
+	return &entry, nil
+}
+
+// PutPeerPolicyEntry adds an entry to the given peer policy list, replacing
+// any entry of the list with the same target.
+func (d *DB) PutPeerPolicyEntry(list PeerPolicyList,
+	entry *PeerPolicyEntry) error {
+
+	listBucketName, err := list.bucket()
+	if err != nil {
+		return err
+	}
+
+	key, err := entry.key()
+	if err != nil {
+		return err
+	}
+
+	var b bytes.Buffer
+	if err := serializeTime(&b, entry.CreatedAt); err != nil {
+		return err
+	}
+	if err := serializeTime(&b, entry.Expiry); err != nil {
+		return err
+	}
+	if err := WriteElements(&b, []byte(entry.Reason)); err != nil {
+		return err
+	}
+
+	return kvdb.Update(d, func(tx kvdb.RwTx) error {
+		policy, err := tx.CreateTopLevelBucket(peerPolicyBucket)
+		if err != nil {
+			return err
+		}
+
+		listBucket, err := policy.CreateBucketIfNotExists(
+			listBucketName,
+		)
+		if err != nil {
+			return err
+		}
+
+		return listBucket.Put(key, b.Bytes())
+	}, func() {})
+}
+
+// DeletePeerPolicyEntry removes the entry with the same target as the given
+// one from the peer policy list. If there is no such entry,
+// ErrPeerPolicyEntryNotFound is returned.
+func (d *DB) DeletePeerPolicyEntry(list PeerPolicyList,
+	entry *PeerPolicyEntry) error {
+
+	listBucketName, err := list.bucket()
+	if err != nil {
+		return err
+	}
+
+	key, err := entry.key()
+	if err != nil {
+		return err
+	}
+
+	return kvdb.Update(d, func(tx kvdb.RwTx) error {
+		policy := tx.ReadWriteBucket(peerPolicyBucket)
+		if policy == nil {
+			return ErrPeerPolicyEntryNotFound
+		}
+
+		listBucket := policy.NestedReadWriteBucket(listBucketName)
+		if listBucket == nil || listBucket.Get(key) == nil {
+			return ErrPeerPolicyEntryNotFound
+		}
+
+		return listBucket.Delete(key)
+	}, func() {})
+}
+
+// FetchPeerPolicyEntries returns all entries of the given peer policy list,
+// including the ones that have expired.
+func (d *DB) FetchPeerPolicyEntries(list PeerPolicyList) ([]*PeerPolicyEntry,
+	error) {
+
+	listBucketName, err := list.bucket()
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []*PeerPolicyEntry
+	if err := kvdb.View(d, func(tx kvdb.RTx) error {
+		policy := tx.ReadBucket(peerPolicyBucket)
+		if policy == nil {
+			return nil
+		}
+
+		listBucket := policy.NestedReadBucket(listBucketName)
+		if listBucket == nil {
+			return nil
+		}
+
+		return listBucket.ForEach(func(k, v []byte) error {
+			entry, err := decodePeerPolicyEntry(k, v)
+			if err != nil {
+				return err
+			}
+
+			entries = append(entries, entry)
+			return nil
+		})
+	}, func() {
+		entries = nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
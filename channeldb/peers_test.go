@@ -1,6 +1,7 @@
 package channeldb
 
 import (
+	"net"
 	"testing"
 	"time"
 
@@ -47,3 +48,115 @@ func TestFlapCount(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, peer2FlapCount, count)
 }
+
+// TestPeerStorage tests storing and fetching the blobs that our peers ask us
+// to keep on their behalf.
+func TestPeerStorage(t *testing.T) {
+	db, err := MakeTestDB(t)
+	require.NoError(t, err)
+
+	// Try to read the blob for a peer that we have no records for.
+	_, err = db.FetchPeerStorage(testPub)
+	require.ErrorIs(t, err, ErrNoPeerStorage)
+
+	// A peer that we only track flap counts for should also not have a
+	// blob stored.
+	err = db.WriteFlapCounts(map[route.Vertex]*FlapCount{
+		testPub: {Count: 1, LastFlap: time.Unix(100, 0)},
+	})
+	require.NoError(t, err)
+
+	_, err = db.FetchPeerStorage(testPub)
+	require.ErrorIs(t, err, ErrNoPeerStorage)
+
+	// Store a blob and make sure we get the same one back.
+	blob := []byte{1, 2, 3, 4}
+	require.NoError(t, db.PutPeerStorage(testPub, blob))
+
+	stored, err := db.FetchPeerStorage(testPub)
+	require.NoError(t, err)
+	require.Equal(t, blob, stored)
+
+	// A new blob should replace the prior one.
+	newBlob := []byte{5, 6, 7}
+	require.NoError(t, db.PutPeerStorage(testPub, newBlob))
+
+	stored, err = db.FetchPeerStorage(testPub)
+	require.NoError(t, err)
+	require.Equal(t, newBlob, stored)
+
+	// The flap count of the peer should be unaffected.
+	count, err := db.ReadFlapCount(testPub)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, count.Count)
+}
+
+// TestPeerPolicyEntries tests adding, replacing, fetching and deleting the
+// entries of the peer policy lists.
+func TestPeerPolicyEntries(t *testing.T) {
+	db, err := MakeTestDB(t)
+	require.NoError(t, err)
+
+	// Both lists are empty before anything is added.
+	entries, err := db.FetchPeerPolicyEntries(PeerBanList)
+	require.NoError(t, err)
+	require.Empty(t, entries)
+
+	_, ipNet, err := net.ParseCIDR("10.1.0.0/16")
+	require.NoError(t, err)
+
+	pubkey := testPub
+	pubkeyBan := &PeerPolicyEntry{
+		Pubkey:    &pubkey,
+		Reason:    "spam",
+		CreatedAt: time.Unix(100, 0),
+		Expiry:    time.Unix(200, 0),
+	}
+	ipBan := &PeerPolicyEntry{
+		IPNet:     ipNet,
+		CreatedAt: time.Unix(100, 0),
+	}
+	require.NoError(t, db.PutPeerPolicyEntry(PeerBanList, pubkeyBan))
+	require.NoError(t, db.PutPeerPolicyEntry(PeerBanList, ipBan))
+
+	entries, err = db.FetchPeerPolicyEntries(PeerBanList)
+	require.NoError(t, err)
+	require.ElementsMatch(
+		t, []*PeerPolicyEntry{pubkeyBan, ipBan}, entries,
+	)
+
+	// The lists are independent of each other.
+	entries, err = db.FetchPeerPolicyEntries(PeerAllowList)
+	require.NoError(t, err)
+	require.Empty(t, entries)
+
+	// An entry with the same target replaces the existing one.
+	pubkeyBan.Expiry = time.Time{}
+	require.NoError(t, db.PutPeerPolicyEntry(PeerBanList, pubkeyBan))
+
+	entries, err = db.FetchPeerPolicyEntries(PeerBanList)
+	require.NoError(t, err)
+	require.ElementsMatch(
+		t, []*PeerPolicyEntry{pubkeyBan, ipBan}, entries,
+	)
+
+	// Entries are deleted by their target only.
+	require.NoError(t, db.DeletePeerPolicyEntry(
+		PeerBanList, &PeerPolicyEntry{IPNet: ipNet},
+	))
+	err = db.DeletePeerPolicyEntry(PeerBanList, ipBan)
+	require.ErrorIs(t, err, ErrPeerPolicyEntryNotFound)
+
+	entries, err = db.FetchPeerPolicyEntries(PeerBanList)
+	require.NoError(t, err)
+	require.Equal(t, []*PeerPolicyEntry{pubkeyBan}, entries)
+
+	// Entries must match exactly one target.
+	err = db.PutPeerPolicyEntry(PeerAllowList, &PeerPolicyEntry{})
+	require.Error(t, err)
+	err = db.PutPeerPolicyEntry(PeerAllowList, &PeerPolicyEntry{
+		Pubkey: &pubkey,
+		IPNet:  ipNet,
+	})
+	require.Error(t, err)
+}
@@ -72,6 +72,13 @@ type ClosedChannelEvent struct {
 	CloseSummary *channeldb.ChannelCloseSummary
 }
 
+// UpgradedChannelEvent represents a new event where the commitment of an open
+// channel was upgraded.
+type UpgradedChannelEvent struct {
+	// Channel is the channel after the upgrade.
+	Channel *channeldb.OpenChannel
+}
+
 // FullyResolvedChannelEvent represents a new event where a channel becomes
 // fully resolved.
 type FullyResolvedChannelEvent struct {
@@ -154,6 +161,22 @@ func (c *ChannelNotifier) NotifyOpenChannelEvent(chanPoint wire.OutPoint) {
 	}
 }
 
+// NotifyUpgradedChannelEvent notifies the channelEventNotifier goroutine that
+// the commitment of a channel was upgraded.
+func (c *ChannelNotifier) NotifyUpgradedChannelEvent(chanPoint wire.OutPoint) {
+	// Fetch the upgraded channel from the database.
+	channel, err := c.chanDB.FetchChannel(nil, chanPoint)
+	if err != nil {
+		log.Warnf("Unable to fetch open channel from the db: %v", err)
+		return
+	}
+
+	event := UpgradedChannelEvent{Channel: channel}
+	if err := c.ntfnServer.SendUpdate(event); err != nil {
+		log.Warnf("Unable to send upgraded channel update: %v", err)
+	}
+}
+
 // NotifyClosedChannelEvent notifies the channelEventNotifier goroutine that a
 // channel has closed.
 func (c *ChannelNotifier) NotifyClosedChannelEvent(chanPoint wire.OutPoint) {
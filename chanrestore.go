@@ -1,6 +1,7 @@
 package lnd
 
 import (
+	"errors"
 	"fmt"
 	"math"
 	"net"
@@ -8,10 +9,12 @@ import (
 	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/btcsuite/btcd/chaincfg"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
 	"github.com/lightningnetwork/lnd/chanbackup"
 	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/contractcourt"
 	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/lightningnetwork/lnd/labels"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/shachain"
 )
@@ -39,6 +42,10 @@ type chanDBRestorer struct {
 	secretKeys keychain.SecretKeyRing
 
 	chainArb *contractcourt.ChainArbitrator
+
+	// publishTx is used to re-broadcast a cooperative close transaction
+	// that is carried by a backup.
+	publishTx func(*wire.MsgTx, string) error
 }
 
 // openChannelShell maps the static channel back up into an open channel
@@ -154,6 +161,11 @@ func (c *chanDBRestorer) openChannelShell(backup chanbackup.Single) (
 		chanType |= channeldb.AnchorOutputsBit
 		chanType |= channeldb.SingleFunderTweaklessBit
 
+	// Starting with the TLV version, the channel type is no longer implied
+	// by the version, but carried explicitly.
+	case chanbackup.TLVBackupVersion:
+		chanType = backup.ChanType
+
 	default:
 		return nil, fmt.Errorf("unknown Single version: %v", err)
 	}
@@ -161,6 +173,24 @@ func (c *chanDBRestorer) openChannelShell(backup chanbackup.Single) (
 	ltndLog.Infof("SCB Recovery: created channel shell for ChannelPoint"+
 		"(%v), chan_type=%v", backup.FundingOutpoint, chanType)
 
+	// The TLV version carries hints about the state of the channel at the
+	// time of the backup. Let the user know about the funds that can't be
+	// recovered using the backup.
+	if backup.Version == chanbackup.TLVBackupVersion {
+		ltndLog.Infof("SCB Recovery: ChannelPoint(%v) was backed up "+
+			"at local_commit_height=%v, remote_commit_height=%v",
+			backup.FundingOutpoint, backup.LocalCommitHeight,
+			backup.RemoteCommitHeight)
+
+		if backup.NumPendingHTLCs > 0 {
+			ltndLog.Warnf("SCB Recovery: ChannelPoint(%v) had %v "+
+				"pending HTLCs worth %v at the time of the "+
+				"backup, funds in pending HTLCs can't be "+
+				"recovered", backup.FundingOutpoint,
+				backup.NumPendingHTLCs, backup.PendingHTLCAmt)
+		}
+	}
+
 	chanShell := channeldb.ChannelShell{
 		NodeAddrs: backup.Addresses,
 		Chan: &channeldb.OpenChannel{
@@ -178,6 +208,7 @@ func (c *chanDBRestorer) openChannelShell(backup chanbackup.Single) (
 			RevocationStore:         shachain.NewRevocationStore(),
 			RevocationProducer:      shaChainProducer,
 			ThawHeight:              backup.LeaseExpiry,
+			LocalShutdownScript:     backup.LocalShutdownScript,
 		},
 	}
 
@@ -281,6 +312,34 @@ func (c *chanDBRestorer) RestoreChansFromSingles(backups ...chanbackup.Single) e
 		}
 	}
 
+	// If a cooperative close was in progress for any of the channels, we
+	// can re-broadcast the close transaction. Once it confirms, the funds
+	// are recovered without waiting for the remote party to force close
+	// the channel.
+	for _, backup := range backups {
+		if backup.CoopCloseTx == nil || c.publishTx == nil {
+			continue
+		}
+
+		ltndLog.Infof("SCB Recovery: re-broadcasting coop close tx %v "+
+			"for ChannelPoint(%v)", backup.CoopCloseTx.TxHash(),
+			backup.FundingOutpoint)
+
+		shortChanID := backup.ShortChannelID
+		label := labels.MakeLabel(
+			labels.LabelTypeChannelClose, &shortChanID,
+		)
+
+		// The close transaction may already be confirmed or conflict
+		// with a commitment the remote party broadcast in the
+		// meantime, so this isn't a critical error.
+		if err := c.publishTx(backup.CoopCloseTx, label); err != nil {
+			ltndLog.Warnf("SCB Recovery: unable to re-broadcast "+
+				"coop close tx for ChannelPoint(%v): %v",
+				backup.FundingOutpoint, err)
+		}
+	}
+
 	return nil
 }
 
@@ -288,6 +347,117 @@ func (c *chanDBRestorer) RestoreChansFromSingles(backups ...chanbackup.Single) e
 // chanbackup.ChannelRestorer.
 var _ chanbackup.ChannelRestorer = (*chanDBRestorer)(nil)
 
+// isKnownChannel returns true if the channel with the given funding outpoint
+// is known to our database, either as an open or as a closed channel.
+func (c *chanDBRestorer) isKnownChannel(chanPoint wire.OutPoint) (bool,
+	error) {
+
+	_, err := c.db.FetchChannel(nil, chanPoint)
+	switch {
+	case err == nil:
+		return true, nil
+
+	case !errors.Is(err, channeldb.ErrChannelNotFound):
+		return false, err
+	}
+
+	_, err = c.db.FetchClosedChannel(&chanPoint)
+	switch {
+	case err == nil:
+		return true, nil
+
+	case errors.Is(err, channeldb.ErrClosedChannelNotFound):
+		return false, nil
+
+	default:
+		return false, err
+	}
+}
+
+// genPeerBackup assembles an encrypted static channel backup of all channels
+// we have with the target peer, that the peer can store on our behalf. If we
+// don't have any channels with the peer, nil is returned.
+func (s *server) genPeerBackup(nodePub *btcec.PublicKey) ([]byte, error) {
+	backups, err := chanbackup.FetchStaticChanBackupsForPeer(
+		nodePub, s.chanStateDB, s.addrSource,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(backups) == 0 {
+		return nil, nil
+	}
+
+	return chanbackup.PackPeerBackup(backups, s.cc.KeyRing)
+}
+
+// handlePeerBackup is called whenever a peer hands back the backup we asked
+// it to store on our behalf. Any channels in the backup that we don't know of
+// were lost, for example because we restored our node from seed alone. For
+// these channels, we'll insert channel shells and reconnect to the peer to
+// trigger the data loss recovery protocol.
+func (s *server) handlePeerBackup(nodePub *btcec.PublicKey,
+	blob []byte) error {
+
+	backups, err := chanbackup.UnpackPeerBackup(
+		blob, nodePub, s.cc.KeyRing,
+	)
+	if err != nil {
+		return fmt.Errorf("unable to unpack peer backup: %w", err)
+	}
+
+	chanRestorer := &chanDBRestorer{
+		db:         s.chanStateDB,
+		secretKeys: s.cc.KeyRing,
+		chainArb:   s.chainArb,
+		publishTx:  s.cc.Wallet.PublishTransaction,
+	}
+
+	// We only want to restore channels we have no record of at all. Any
+	// channel that we still know of, or that we know was closed, is
+	// either up to date or was dropped from a stale backup.
+	var lostChans []chanbackup.Single
+	for _, backup := range backups {
+		known, err := chanRestorer.isKnownChannel(
+			backup.FundingOutpoint,
+		)
+		if err != nil {
+			return err
+		}
+
+		if known {
+			continue
+		}
+
+		lostChans = append(lostChans, backup)
+	}
+
+	if len(lostChans) == 0 {
+		return nil
+	}
+
+	ltndLog.Infof("Peer %x handed back backup of %v unknown channels, "+
+		"attempting recovery", nodePub.SerializeCompressed(),
+		len(lostChans))
+
+	// Restoring the channels will disconnect and reconnect the peer, so
+	// we can't do this from within the peer's read handler.
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		err := chanbackup.Recover(lostChans, chanRestorer, s)
+		if err != nil {
+			ltndLog.Errorf("Unable to recover channels from peer "+
+				"%x backup: %v", nodePub.SerializeCompressed(),
+				err)
+		}
+	}()
+
+	return nil
+}
+
 // ConnectPeer attempts to connect to the target node at the set of available
 // addresses. Once this method returns with a non-nil error, the connector
 // should attempt to persistently connect to the target peer in the background
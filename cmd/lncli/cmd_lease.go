@@ -0,0 +1,200 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/urfave/cli"
+)
+
+var updateLiquidityAdCommand = cli.Command{
+	Name:     "updateliquidityad",
+	Category: "Channels",
+	Usage:    "Update the liquidity ad used to sell channel leases.",
+	Description: `
+	Replaces the liquidity ad advertised in our node announcement. Other
+	nodes can lease inbound liquidity from the on-chain funds of the wallet
+	by paying the advertised fee, after which a lease channel is opened to
+	them. The funds of the channel stay locked on our side for the lease
+	duration.
+
+	The fee of a lease is fee_base plus fee_rate parts per million of the
+	leased amount.
+
+	Sell leases of up to 0.1 BTC for four weeks:
+	    lncli updateliquidityad --lease_duration=4032 --fee_base=1000 \
+	        --fee_rate=2500 --min_lease_amt=100000 \
+	        --max_lease_amt=10000000
+
+	Stop selling leases:
+	    lncli updateliquidityad --disable
+	`,
+	Flags: []cli.Flag{
+		cli.Uint64Flag{
+			Name: "lease_duration",
+			Usage: "the number of blocks the funds of a sold " +
+				"lease are locked for",
+		},
+		cli.Int64Flag{
+			Name:  "fee_base",
+			Usage: "the fixed fee in satoshis charged for a lease",
+		},
+		cli.Uint64Flag{
+			Name: "fee_rate",
+			Usage: "the fee charged proportionally to the leased " +
+				"amount, in parts per million",
+		},
+		cli.Int64Flag{
+			Name: "min_lease_amt",
+			Usage: "the minimum amount in satoshis that can be " +
+				"leased",
+		},
+		cli.Int64Flag{
+			Name: "max_lease_amt",
+			Usage: "the maximum amount in satoshis that can be " +
+				"leased",
+		},
+		cli.BoolFlag{
+			Name:  "disable",
+			Usage: "if set, the liquidity ad is removed",
+		},
+	},
+	Action: actionDecorator(updateLiquidityAd),
+}
+
+func updateLiquidityAd(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	req := &lnrpc.UpdateLiquidityAdRequest{
+		Disable: ctx.Bool("disable"),
+	}
+	if !req.Disable {
+		req.Ad = &lnrpc.LiquidityAd{
+			LeaseDuration: uint32(ctx.Uint64("lease_duration")),
+			FeeBaseSat:    ctx.Int64("fee_base"),
+			FeeRatePpm:    uint32(ctx.Uint64("fee_rate")),
+			MinLeaseSat:   ctx.Int64("min_lease_amt"),
+			MaxLeaseSat:   ctx.Int64("max_lease_amt"),
+		}
+	}
+
+	resp, err := client.UpdateLiquidityAd(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var requestLeaseCommand = cli.Command{
+	Name:      "requestlease",
+	Category:  "Channels",
+	Usage:     "Request a channel lease from a connected peer.",
+	ArgsUsage: "node_key amt",
+	Description: `
+	Requests a channel lease of the given amount in satoshis from a
+	connected peer advertising a liquidity ad. The peer replies with an
+	offer including an invoice for the lease fee. Once the invoice is paid,
+	the peer opens a lease channel to us, giving us inbound liquidity for
+	the duration of the lease.
+
+	The lease duration and the maximum fee default to those of the
+	liquidity ad of the peer.
+	`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name: "node_key",
+			Usage: "the identity public key of the peer to " +
+				"lease from",
+		},
+		cli.Int64Flag{
+			Name:  "amt",
+			Usage: "the amount in satoshis to lease",
+		},
+		cli.Uint64Flag{
+			Name: "lease_duration",
+			Usage: "the number of blocks the leased funds should " +
+				"be locked for",
+		},
+		cli.Int64Flag{
+			Name: "max_fee",
+			Usage: "the maximum lease fee in satoshis we're " +
+				"willing to pay",
+		},
+	},
+	Action: actionDecorator(requestLease),
+}
+
+func requestLease(ctx *cli.Context) error {
+	ctxc := getContext()
+	args := ctx.Args()
+
+	var nodeKey string
+	switch {
+	case ctx.IsSet("node_key"):
+		nodeKey = ctx.String("node_key")
+	case args.Present():
+		nodeKey = args.First()
+		args = args.Tail()
+	default:
+		return fmt.Errorf("node_key argument missing")
+	}
+
+	var (
+		amt int64
+		err error
+	)
+	switch {
+	case ctx.IsSet("amt"):
+		amt = ctx.Int64("amt")
+	case args.Present():
+		amt, err = strconv.ParseInt(args.First(), 10, 64)
+		if err != nil {
+			return fmt.Errorf("unable to decode lease amount: %w",
+				err)
+		}
+	default:
+		return fmt.Errorf("amt argument missing")
+	}
+
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.RequestLease(ctxc, &lnrpc.RequestLeaseRequest{
+		NodePubkey:    nodeKey,
+		LeaseSat:      amt,
+		LeaseDuration: uint32(ctx.Uint64("lease_duration")),
+		MaxFeeSat:     ctx.Int64("max_fee"),
+	})
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var listChannelLeasesCommand = cli.Command{
+	Name:     "listleases",
+	Category: "Channels",
+	Usage:    "List our liquidity ad and the leases we bought or sold.",
+	Action:   actionDecorator(listChannelLeases),
+}
+
+func listChannelLeases(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.ListLeases(ctxc, &lnrpc.ListLeasesRequest{})
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
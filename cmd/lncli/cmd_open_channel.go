@@ -245,6 +245,16 @@ var openChannelCommand = cli.Command{
 				"propose to the remote peer (%q, %q)",
 				channelTypeTweakless, channelTypeAnchors),
 		},
+		cli.StringSliceFlag{
+			Name: "channel_types",
+			Usage: "(optional) an acceptable channel type " +
+				"given as the comma separated feature bits " +
+				"it's comprised of, e.g. \"12,22\" for " +
+				"anchor channels. Can be set multiple times " +
+				"in order of preference, in which case the " +
+				"next type is proposed if the remote peer " +
+				"rejects one",
+		},
 		cli.BoolFlag{
 			Name: "zero_conf",
 			Usage: "(optional) whether a zero-conf channel open " +
@@ -425,6 +435,15 @@ func openChannel(ctx *cli.Context) error {
 		return fmt.Errorf("unsupported channel type %v", channelType)
 	}
 
+	// Parse the ordered list of acceptable channel types.
+	for _, chanType := range ctx.StringSlice("channel_types") {
+		rpcChanType, err := parseChannelType(chanType)
+		if err != nil {
+			return err
+		}
+		req.ChannelTypes = append(req.ChannelTypes, rpcChanType)
+	}
+
 	// PSBT funding is a more involved, interactive process that is too
 	// large to also fit into this already long function.
 	if ctx.Bool("psbt") {
@@ -465,6 +484,29 @@ func openChannel(ctx *cli.Context) error {
 	}
 }
 
+// parseChannelType parses a channel type given as a comma separated list of
+// the feature bits it's comprised of. An empty string denotes the legacy
+// channel type without any feature bits.
+func parseChannelType(chanType string) (*lnrpc.ChannelType, error) {
+	rpcChanType := &lnrpc.ChannelType{}
+	if chanType == "" {
+		return rpcChanType, nil
+	}
+
+	for _, bitStr := range strings.Split(chanType, ",") {
+		bit, err := strconv.ParseUint(strings.TrimSpace(bitStr), 10, 16)
+		if err != nil {
+			return nil, fmt.Errorf("invalid feature bit %q in "+
+				"channel type %q", bitStr, chanType)
+		}
+		rpcChanType.FeatureBits = append(
+			rpcChanType.FeatureBits, uint32(bit),
+		)
+	}
+
+	return rpcChanType, nil
+}
+
 // openChannelPsbt starts an interactive channel open protocol that uses a
 // partially signed bitcoin transaction (PSBT) to fund the channel output. The
 // protocol involves several steps between the RPC server and the CLI client:
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/urfave/cli"
+)
+
+var peerPolicyCommand = cli.Command{
+	Name:     "peerpolicy",
+	Category: "Peers",
+	Usage:    "Manage the ban list and allow list of the peer policy.",
+	Description: `
+	The peer connection policy decides which peers the node accepts
+	connections from. Nodes and ranges of IP addresses on the ban list are
+	refused any connection, and peers that repeatedly send invalid messages
+	are banned temporarily. If the node is configured with
+	peerpolicy.allowlistonly, only inbound connections from nodes and IP
+	ranges on the allow list are accepted.
+	`,
+	Subcommands: []cli.Command{
+		addPeerPolicyEntryCommand,
+		removePeerPolicyEntryCommand,
+		listPeerPolicyCommand,
+	},
+}
+
+// peerPolicyTargetFlags are the flags identifying the target of a peer policy
+// entry.
+var peerPolicyTargetFlags = []cli.Flag{
+	cli.BoolFlag{
+		Name: "allow",
+		Usage: "if set, the allow list is targeted instead of the " +
+			"ban list",
+	},
+	cli.StringFlag{
+		Name:  "pub_key",
+		Usage: "the hex-encoded pubkey of the node",
+	},
+	cli.StringFlag{
+		Name: "ip_range",
+		Usage: "the range of IP addresses in CIDR notation, or a " +
+			"single IP address",
+	},
+}
+
+// peerPolicyList returns the list targeted by the command.
+func peerPolicyList(ctx *cli.Context) lnrpc.PeerPolicyList {
+	if ctx.Bool("allow") {
+		return lnrpc.PeerPolicyList_ALLOW_LIST
+	}
+
+	return lnrpc.PeerPolicyList_BAN_LIST
+}
+
+var addPeerPolicyEntryCommand = cli.Command{
+	Name:  "add",
+	Usage: "Ban a node or IP range, or add it to the allow list.",
+	Description: `
+	Adds a node or a range of IP addresses to the ban list, or to the allow
+	list if --allow is set. Exactly one of --pub_key and --ip_range must be
+	set. Connected peers that are banned as a result are disconnected.
+
+	Ban a node for one day:
+	    lncli peerpolicy add --pub_key=<pubkey> --duration=24h
+
+	Allow a range of IP addresses to connect:
+	    lncli peerpolicy add --allow --ip_range=10.0.0.0/8
+	`,
+	Flags: append(peerPolicyTargetFlags,
+		cli.StringFlag{
+			Name: "reason",
+			Usage: "an optional description of why the entry " +
+				"is added",
+		},
+		cli.DurationFlag{
+			Name: "duration",
+			Usage: "the duration after which the entry expires, " +
+				"if not set the entry never expires",
+		},
+	),
+	Action: actionDecorator(addPeerPolicyEntry),
+}
+
+func addPeerPolicyEntry(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.AddPeerPolicyEntry(
+		ctxc, &lnrpc.AddPeerPolicyEntryRequest{
+			List:    peerPolicyList(ctx),
+			PubKey:  ctx.String("pub_key"),
+			IpRange: ctx.String("ip_range"),
+			Reason:  ctx.String("reason"),
+			DurationSeconds: uint64(
+				ctx.Duration("duration").Seconds(),
+			),
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var removePeerPolicyEntryCommand = cli.Command{
+	Name:  "remove",
+	Usage: "Unban a node or IP range, or remove it from the allow list.",
+	Description: `
+	Removes a node or a range of IP addresses from the ban list, or from
+	the allow list if --allow is set. Exactly one of --pub_key and
+	--ip_range must be set.
+	`,
+	Flags:  peerPolicyTargetFlags,
+	Action: actionDecorator(removePeerPolicyEntry),
+}
+
+func removePeerPolicyEntry(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.RemovePeerPolicyEntry(
+		ctxc, &lnrpc.RemovePeerPolicyEntryRequest{
+			List:    peerPolicyList(ctx),
+			PubKey:  ctx.String("pub_key"),
+			IpRange: ctx.String("ip_range"),
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var listPeerPolicyCommand = cli.Command{
+	Name:   "list",
+	Usage:  "List the active bans and allow list entries.",
+	Action: actionDecorator(listPeerPolicy),
+}
+
+func listPeerPolicy(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.ListPeerPolicy(ctxc, &lnrpc.ListPeerPolicyRequest{})
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lncfg"
+	"github.com/lightningnetwork/lnd/lntest/wirereplay"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/wirecapture"
+	"github.com/urfave/cli"
+)
+
+// defaultPeerPort is the port assumed for the node if the address doesn't
+// specify one.
+const defaultPeerPort = "9735"
+
+var replayCaptureCommand = cli.Command{
+	Name:      "replaycapture",
+	Category:  "Peers",
+	Usage:     "Replay a wire capture against a node.",
+	ArgsUsage: "capture_file pubkey@host[:port]",
+	Description: `
+	Connects to the given node with an ephemeral identity and sends it the
+	messages recorded in a capture file, which lnd writes for the peers
+	configured with wirecapture.peer. The messages are sent exactly as
+	captured, including the init message and messages that couldn't be
+	decoded, while pings of the node are answered live. Messages received
+	from the node are printed.
+
+	By default, the messages the captured peer sent are replayed, so the
+	node takes the role of the node that made the capture. With --outbound,
+	the messages sent by the capturing node are replayed instead.
+
+	Messages carrying signatures only verify against a node with the same
+	keys and channel state as the one the capture was made with.
+
+	This command doesn't use the RPC connection of lncli.
+	`,
+	Flags: []cli.Flag{
+		cli.BoolFlag{
+			Name: "outbound",
+			Usage: "replay the messages sent by the capturing " +
+				"node instead of the ones of the captured peer",
+		},
+		cli.Float64Flag{
+			Name: "speed",
+			Usage: "the speed factor of the replay, e.g. 2 to " +
+				"replay twice as fast as captured, or 0 to " +
+				"send all messages without delay",
+		},
+		cli.DurationFlag{
+			Name: "linger",
+			Usage: "how long to keep the connection open after " +
+				"the last message was sent",
+			Value: 5 * time.Second,
+		},
+	},
+	Action: actionDecorator(replayCapture),
+}
+
+func replayCapture(ctx *cli.Context) error {
+	if ctx.NArg() != 2 {
+		return cli.ShowCommandHelp(ctx, "replaycapture")
+	}
+
+	peer, records, err := wirecapture.ReadFile(ctx.Args().Get(0))
+	if err != nil {
+		return fmt.Errorf("unable to read capture: %w", err)
+	}
+
+	addr, err := lncfg.ParseLNAddressString(
+		ctx.Args().Get(1), defaultPeerPort, net.ResolveTCPAddr,
+	)
+	if err != nil {
+		return fmt.Errorf("invalid node address: %w", err)
+	}
+
+	direction := wirecapture.Inbound
+	if ctx.Bool("outbound") {
+		direction = wirecapture.Outbound
+	}
+
+	fmt.Printf("Replaying %d captured messages with peer %v\n",
+		len(records), peer)
+
+	result, err := wirereplay.Replay(getContext(), &wirereplay.Config{
+		Addr:      addr,
+		Direction: direction,
+		Speed:     ctx.Float64("speed"),
+		Linger:    ctx.Duration("linger"),
+		OnMessage: func(msg lnwire.Message, err error) {
+			if err != nil {
+				fmt.Printf("Received invalid message: %v\n",
+					err)
+				return
+			}
+
+			fmt.Printf("Received %v\n", msg.MsgType())
+		},
+	}, records)
+	if result != nil {
+		fmt.Printf("Sent %d messages, skipped %d, received %d\n",
+			result.Sent, result.Skipped, result.Received)
+	}
+
+	return err
+}
@@ -15,9 +15,11 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/lncfg"
 	"github.com/lightningnetwork/lnd/lnrpc"
 	"github.com/lightningnetwork/lnd/routing"
 	"github.com/lightningnetwork/lnd/routing/route"
@@ -708,6 +710,11 @@ var closeChannelCommand = cli.Command{
 	if an upfront shutdown address has not already been set. If neither are
 	set the funds will be delivered to a new wallet address.
 
+	A cooperative closure fails if the channel has HTLCs in flight, unless
+	--drain is set. In that case, the channel stops accepting new HTLCs and
+	the closure starts once all in-flight HTLCs are resolved, or fails after
+	--drain_timeout.
+
 	To view which funding_txids/output_indexes can be used for a channel close,
 	see the channel_point values within the listchannels command output.
 	The format for a channel_point is 'funding_txid:output_index'.`,
@@ -762,6 +769,18 @@ var closeChannelCommand = cli.Command{
 				"be used if an upfront shutdown address is not " +
 				"already set",
 		},
+		cli.BoolFlag{
+			Name: "drain",
+			Usage: "(optional) wait for in-flight htlcs to " +
+				"resolve before starting a cooperative " +
+				"closure, while not accepting new htlcs",
+		},
+		cli.DurationFlag{
+			Name: "drain_timeout",
+			Usage: "(optional) the maximum time to wait for " +
+				"in-flight htlcs to resolve if --drain is " +
+				"set, defaults to 10m if not set",
+		},
 	},
 	Action: actionDecorator(closeChannel),
 }
@@ -792,12 +811,15 @@ func closeChannel(ctx *cli.Context) error {
 	}
 
 	// TODO(roasbeef): implement time deadline within server
+	drainTimeout := ctx.Duration("drain_timeout")
 	req := &lnrpc.CloseChannelRequest{
-		ChannelPoint:    channelPoint,
-		Force:           ctx.Bool("force"),
-		TargetConf:      int32(ctx.Int64("conf_target")),
-		SatPerVbyte:     ctx.Uint64(feeRateFlag),
-		DeliveryAddress: ctx.String("delivery_addr"),
+		ChannelPoint:        channelPoint,
+		Force:               ctx.Bool("force"),
+		TargetConf:          int32(ctx.Int64("conf_target")),
+		SatPerVbyte:         ctx.Uint64(feeRateFlag),
+		DeliveryAddress:     ctx.String("delivery_addr"),
+		DrainHtlcs:          ctx.Bool("drain"),
+		DrainTimeoutSeconds: uint32(drainTimeout.Seconds()),
 	}
 
 	// After parsing the request, we'll spin up a goroutine that will
@@ -867,6 +889,10 @@ func executeChannelClose(ctxc context.Context, client lnrpc.LightningClient,
 			}
 		case *lnrpc.CloseStatusUpdate_ChanClose:
 			return nil
+
+		case *lnrpc.CloseStatusUpdate_ChanDrain:
+			fmt.Fprintf(os.Stderr, "Waiting for %d in-flight htlcs "+
+				"to resolve\n", update.ChanDrain.NumPendingHtlcs)
 		}
 	}
 }
@@ -1194,6 +1220,351 @@ func abandonChannel(ctx *cli.Context) error {
 	return nil
 }
 
+var bumpCoopCloseFeeCommand = cli.Command{
+	Name:     "bumpcoopclosefee",
+	Category: "Channels",
+	Usage:    "Bump the fee of a cooperative closing transaction.",
+	Description: `
+	Replaces the closing transaction of a channel that is being
+	cooperatively closed with the simple close protocol with one paying a
+	higher fee rate. The fee is paid from our own channel balance. The new
+	closing transaction is broadcast once the remote peer signed it.
+
+	The fee rate is estimated for the given confirmation target, or set
+	manually with --sat_per_vbyte. It must exceed the fee rate of the
+	prior closing transaction we proposed.
+
+	The format for a channel_point is 'funding_txid:output_index'.`,
+	ArgsUsage: "funding_txid [output_index]",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "funding_txid",
+			Usage: "the txid of the channel's funding transaction",
+		},
+		cli.IntFlag{
+			Name: "output_index",
+			Usage: "the output index for the funding output of " +
+				"the funding transaction",
+		},
+		cli.StringFlag{
+			Name: "chan_point",
+			Usage: "(optional) the channel point. If set, " +
+				"funding_txid and output_index flags and " +
+				"positional arguments will be ignored",
+		},
+		cli.Int64Flag{
+			Name: "conf_target",
+			Usage: "(optional) the number of blocks the new " +
+				"closing transaction should confirm within",
+		},
+		cli.Uint64Flag{
+			Name: "sat_per_vbyte",
+			Usage: "(optional) a manual fee rate in sat/vbyte the " +
+				"new closing transaction pays",
+		},
+	},
+	Action: actionDecorator(bumpCoopCloseFee),
+}
+
+func bumpCoopCloseFee(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	// Show command help if no arguments and flags were provided.
+	if ctx.NArg() == 0 && ctx.NumFlags() == 0 {
+		cli.ShowCommandHelp(ctx, "bumpcoopclosefee")
+		return nil
+	}
+
+	if ctx.IsSet("conf_target") && ctx.IsSet("sat_per_vbyte") {
+		return fmt.Errorf("either conf_target or sat_per_vbyte should " +
+			"be set, but not both")
+	}
+
+	channelPoint, err := parseChannelPoint(ctx)
+	if err != nil {
+		return err
+	}
+
+	req := &lnrpc.BumpCloseFeeRequest{
+		ChannelPoint: channelPoint,
+		TargetConf:   int32(ctx.Int64("conf_target")),
+		SatPerVbyte:  ctx.Uint64("sat_per_vbyte"),
+	}
+
+	resp, err := client.BumpCloseFee(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var upgradeChannelCommand = cli.Command{
+	Name:     "upgradechannel",
+	Category: "Channels",
+	Usage:    "Upgrade the commitment of an open channel.",
+	Description: `
+	Upgrades the commitment format and parameters of an open channel
+	without closing it, using the dynamic commitments protocol. The
+	command blocks until the upgrade is locked in by both parties or
+	rejected by the remote peer. Parameters that aren't set keep their
+	current value.
+
+	The format for a channel_point is 'funding_txid:output_index'.`,
+	ArgsUsage: "funding_txid [output_index]",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "funding_txid",
+			Usage: "the txid of the channel's funding transaction",
+		},
+		cli.IntFlag{
+			Name: "output_index",
+			Usage: "the output index for the funding output of " +
+				"the funding transaction",
+		},
+		cli.StringFlag{
+			Name: "chan_point",
+			Usage: "(optional) the channel point. If set, " +
+				"funding_txid and output_index flags and " +
+				"positional arguments will be ignored",
+		},
+		cli.StringFlag{
+			Name: "channel_type",
+			Usage: fmt.Sprintf("(optional) the commitment format "+
+				"to upgrade the channel to (%q, %q)",
+				channelTypeTweakless, channelTypeAnchors),
+		},
+		cli.Uint64Flag{
+			Name: "local_dust_limit",
+			Usage: "(optional) our new dust limit in satoshis, " +
+				"below which outputs aren't materialized on " +
+				"our commitment",
+		},
+		cli.Uint64Flag{
+			Name: "remote_csv_delay",
+			Usage: "(optional) the new CSV delay in blocks the " +
+				"remote peer must use for its own outputs",
+		},
+		cli.Uint64Flag{
+			Name: "remote_max_accepted_htlcs",
+			Usage: "(optional) the new maximum number of HTLCs " +
+				"the remote peer may offer to us",
+		},
+	},
+	Action: actionDecorator(upgradeChannel),
+}
+
+func upgradeChannel(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	// Show command help if no arguments and flags were provided.
+	if ctx.NArg() == 0 && ctx.NumFlags() == 0 {
+		cli.ShowCommandHelp(ctx, "upgradechannel")
+		return nil
+	}
+
+	channelPoint, err := parseChannelPoint(ctx)
+	if err != nil {
+		return err
+	}
+
+	maxHtlcs := ctx.Uint64("remote_max_accepted_htlcs")
+	req := &lnrpc.UpgradeChannelCommitmentRequest{
+		ChannelPoint:           channelPoint,
+		LocalDustLimitSat:      ctx.Uint64("local_dust_limit"),
+		RemoteCsvDelay:         uint32(ctx.Uint64("remote_csv_delay")),
+		RemoteMaxAcceptedHtlcs: uint32(maxHtlcs),
+	}
+
+	channelType := ctx.String("channel_type")
+	switch channelType {
+	case "":
+		break
+	case channelTypeTweakless:
+		req.CommitmentType = lnrpc.CommitmentType_STATIC_REMOTE_KEY
+	case channelTypeAnchors:
+		req.CommitmentType = lnrpc.CommitmentType_ANCHORS
+	default:
+		return fmt.Errorf("unsupported channel type %v", channelType)
+	}
+
+	resp, err := client.UpgradeChannelCommitment(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var simulateForceCloseCommand = cli.Command{
+	Name:     "simulateforceclose",
+	Category: "Channels",
+	Usage:    "Simulate a force close of an existing channel.",
+	Description: `
+	Performs a dry run of a force close of an active channel. Shows the
+	commitment transaction that would be broadcast, how each of our
+	outputs on it would be resolved, when the outputs mature and the fees
+	needed to sweep them. Nothing is signed or broadcast.
+
+	The sweep fees are estimated at the fee rate for the given confirmation
+	target, or at a manual fee rate if --sat_per_vbyte is set.
+
+	The format for a channel_point is 'funding_txid:output_index'.`,
+	ArgsUsage: "funding_txid [output_index]",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "funding_txid",
+			Usage: "the txid of the channel's funding transaction",
+		},
+		cli.IntFlag{
+			Name: "output_index",
+			Usage: "the output index for the funding output of " +
+				"the funding transaction",
+		},
+		cli.StringFlag{
+			Name: "chan_point",
+			Usage: "(optional) the channel point. If set, " +
+				"funding_txid and output_index flags and " +
+				"positional arguments will be ignored",
+		},
+		cli.Int64Flag{
+			Name: "conf_target",
+			Usage: "(optional) the number of blocks the sweep " +
+				"fees are estimated for",
+		},
+		cli.Uint64Flag{
+			Name: "sat_per_vbyte",
+			Usage: "(optional) a manual fee rate in sat/vbyte the " +
+				"sweep fees are estimated at",
+		},
+	},
+	Action: actionDecorator(simulateForceClose),
+}
+
+func simulateForceClose(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	// Show command help if no arguments and flags were provided.
+	if ctx.NArg() == 0 && ctx.NumFlags() == 0 {
+		cli.ShowCommandHelp(ctx, "simulateforceclose")
+		return nil
+	}
+
+	if ctx.IsSet("conf_target") && ctx.IsSet("sat_per_vbyte") {
+		return fmt.Errorf("either conf_target or sat_per_vbyte should " +
+			"be set, but not both")
+	}
+
+	channelPoint, err := parseChannelPoint(ctx)
+	if err != nil {
+		return err
+	}
+
+	req := &lnrpc.SimulateForceCloseRequest{
+		ChannelPoint: channelPoint,
+		TargetConf:   int32(ctx.Int64("conf_target")),
+		SatPerVbyte:  ctx.Uint64("sat_per_vbyte"),
+	}
+
+	resp, err := client.SimulateForceClose(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var listHtlcDeadlinesCommand = cli.Command{
+	Name:     "listhtlcdeadlines",
+	Category: "Channels",
+	Usage: "List HTLCs that are approaching a force close of their " +
+		"channel.",
+	Description: `
+	Lists the HTLCs of open channels along with the height at which they
+	would trigger a force close of their channel, as determined by the
+	force close policy. HTLCs the policy exempts from triggering a force
+	close, for example because they're worth less than claiming them
+	on-chain would cost, are marked as such.
+
+	Incoming HTLCs only trigger a force close if their preimage is known.`,
+	Flags: []cli.Flag{
+		cli.Uint64Flag{
+			Name: "within_blocks",
+			Usage: "(optional) only list HTLCs that trigger a " +
+				"force close within this many blocks",
+		},
+	},
+	Action: actionDecorator(listHtlcDeadlines),
+}
+
+func listHtlcDeadlines(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	req := &lnrpc.ListHtlcDeadlinesRequest{
+		WithinBlocks: uint32(ctx.Uint64("within_blocks")),
+	}
+
+	resp, err := client.ListHtlcDeadlines(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var subscribeResolverProgressCommand = cli.Command{
+	Name:     "subscriberesolverprogress",
+	Category: "Channels",
+	Usage: "Stream the progress of the on-chain resolution of force " +
+		"closed channels.",
+	Description: `
+	Streams the progress of the resolvers of each output of force closed
+	channels, including what each resolver is waiting for and the txid,
+	fee and number of attempts of its most recent sweep transaction.
+
+	The current progress of all channels being resolved is printed first,
+	followed by an update each time the progress of a channel changes. An
+	update without resolvers means the channel is no longer being
+	resolved.`,
+	Action: actionDecorator(subscribeResolverProgress),
+}
+
+func subscribeResolverProgress(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	stream, err := client.SubscribeResolverProgress(
+		ctxc, &lnrpc.ResolverProgressSubscription{},
+	)
+	if err != nil {
+		return err
+	}
+
+	for {
+		update, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		printRespJSON(update)
+	}
+}
+
 // parseChannelPoint parses a funding txid and output index from the command
 // line. Both named options as well as unnamed parameters are supported.
 func parseChannelPoint(ctx *cli.Context) (*lnrpc.ChannelPoint, error) {
@@ -1249,6 +1620,11 @@ var listPeersCommand = cli.Command{
 			Name:  "list_errors",
 			Usage: "list a full set of most recent errors for the peer",
 		},
+		cli.BoolFlag{
+			Name: "stats",
+			Usage: "include the per message type traffic " +
+				"statistics and queue depths of each peer",
+		},
 	},
 	Action: actionDecorator(listPeers),
 }
@@ -1261,7 +1637,8 @@ func listPeers(ctx *cli.Context) error {
 	// By default, we display a single error on the cli. If the user
 	// specifically requests a full error set, then we will provide it.
 	req := &lnrpc.ListPeersRequest{
-		LatestError: !ctx.IsSet("list_errors"),
+		LatestError:  !ctx.IsSet("list_errors"),
+		IncludeStats: ctx.Bool("stats"),
 	}
 	resp, err := client.ListPeers(ctxc, req)
 	if err != nil {
@@ -1272,6 +1649,59 @@ func listPeers(ctx *cli.Context) error {
 	return nil
 }
 
+var peerStatsCommand = cli.Command{
+	Name:     "peerstats",
+	Category: "Peers",
+	Usage:    "Stream the traffic statistics of connected peers.",
+	Description: `
+	Periodically prints the traffic statistics of the connected peers,
+	including the number of messages and bytes exchanged by message type,
+	the depths of the outgoing message queues and any errors stored for the
+	peer since the previous update. The command runs until interrupted.
+	`,
+	Flags: []cli.Flag{
+		cli.StringSliceFlag{
+			Name: "pub_key",
+			Usage: "only show the statistics of the peer with " +
+				"this pubkey, can be specified multiple times",
+		},
+		cli.DurationFlag{
+			Name:  "interval",
+			Usage: "the interval in between updates",
+			Value: 5 * time.Second,
+		},
+	},
+	Action: actionDecorator(peerStats),
+}
+
+func peerStats(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	interval := ctx.Duration("interval")
+	stream, err := client.SubscribePeerStats(
+		ctxc, &lnrpc.PeerStatsSubscription{
+			PubKeys:    ctx.StringSlice("pub_key"),
+			IntervalMs: uint32(interval.Milliseconds()),
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	for {
+		update, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		printRespJSON(update)
+	}
+}
+
 var walletBalanceCommand = cli.Command{
 	Name:     "walletbalance",
 	Category: "Wallet",
@@ -1608,6 +2038,126 @@ func getNodeMetrics(ctx *cli.Context) error {
 	return nil
 }
 
+var exportGraphSnapshotCommand = cli.Command{
+	Name:     "exportgraphsnapshot",
+	Category: "Graph",
+	Usage:    "Export a snapshot of the channel graph to a file.",
+	Description: `
+	Exports a compact binary snapshot of the public channel graph, made up
+	of the signed announcements of all public channels and nodes, to the
+	given file. The snapshot can be imported by other nodes, such as fresh
+	neutrino nodes, with the importgraphsnapshot command.
+	`,
+	ArgsUsage: "output_file",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "output_file",
+			Usage: "the file to write the snapshot to",
+		},
+	},
+	Action: actionDecorator(exportGraphSnapshot),
+}
+
+func exportGraphSnapshot(ctx *cli.Context) error {
+	ctxc := getContext()
+
+	var outputFile string
+	switch {
+	case ctx.IsSet("output_file"):
+		outputFile = ctx.String("output_file")
+	case ctx.Args().Present():
+		outputFile = ctx.Args().First()
+	default:
+		return fmt.Errorf("output_file argument missing")
+	}
+
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.ExportGraphSnapshot(
+		ctxc, &lnrpc.ExportGraphSnapshotRequest{},
+	)
+	if err != nil {
+		return err
+	}
+
+	outputFile = lncfg.CleanAndExpandPath(outputFile)
+	err = ioutil.WriteFile(outputFile, resp.Snapshot, 0644)
+	if err != nil {
+		return fmt.Errorf("unable to write snapshot to %v: %v",
+			outputFile, err)
+	}
+
+	fmt.Printf("Exported %v channels and %v nodes to %v\n",
+		resp.NumChannels, resp.NumNodes, outputFile)
+
+	return nil
+}
+
+var importGraphSnapshotCommand = cli.Command{
+	Name:     "importgraphsnapshot",
+	Category: "Graph",
+	Usage:    "Import a snapshot of the channel graph from a file.",
+	Description: `
+	Imports a graph snapshot created by the exportgraphsnapshot command into
+	the channel graph of the node. Unless --skip_signature_verification is
+	set, the signatures of all announcements of the snapshot are verified
+	before anything is imported. Channels, updates and nodes the graph
+	already knows of, or knows a newer version of, are skipped.
+	`,
+	ArgsUsage: "input_file",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "input_file",
+			Usage: "the file to read the snapshot from",
+		},
+		cli.BoolFlag{
+			Name: "skip_signature_verification",
+			Usage: "if set, the signatures of the announcements " +
+				"of the snapshot aren't verified, only use " +
+				"this for snapshots of a trusted node",
+		},
+	},
+	Action: actionDecorator(importGraphSnapshot),
+}
+
+func importGraphSnapshot(ctx *cli.Context) error {
+	ctxc := getContext()
+
+	var inputFile string
+	switch {
+	case ctx.IsSet("input_file"):
+		inputFile = ctx.String("input_file")
+	case ctx.Args().Present():
+		inputFile = ctx.Args().First()
+	default:
+		return fmt.Errorf("input_file argument missing")
+	}
+
+	snapshot, err := ioutil.ReadFile(lncfg.CleanAndExpandPath(inputFile))
+	if err != nil {
+		return fmt.Errorf("unable to read snapshot: %v", err)
+	}
+
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.ImportGraphSnapshot(
+		ctxc, &lnrpc.ImportGraphSnapshotRequest{
+			Snapshot: snapshot,
+			SkipSignatureVerification: ctx.Bool(
+				"skip_signature_verification",
+			),
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
 var getChanInfoCommand = cli.Command{
 	Name:     "getchaninfo",
 	Category: "Graph",
@@ -1737,6 +2287,34 @@ func getNetworkInfo(ctx *cli.Context) error {
 	return nil
 }
 
+var gossipBudgetCommand = cli.Command{
+	Name:     "gossipbudget",
+	Category: "Peers",
+	Usage: "Get the gossip exchanged with peers and the usage of the " +
+		"gossip bandwidth budgets.",
+	Description: "Returns the gossip exchanged with all peers since " +
+		"startup, along with the gossip exchanged with each " +
+		"connected peer, including the messages that were delayed " +
+		"or dropped to stay within the configured budgets.",
+	Action: actionDecorator(gossipBudget),
+}
+
+func gossipBudget(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	req := &lnrpc.GossipBudgetUsageRequest{}
+
+	resp, err := client.GossipBudgetUsage(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
 var debugLevelCommand = cli.Command{
 	Name:  "debuglevel",
 	Usage: "Set the debug level.",
@@ -2412,6 +2990,35 @@ func exportChanBackup(ctx *cli.Context) error {
 	return nil
 }
 
+var backupSinkStatusCommand = cli.Command{
+	Name:     "backupsinkstatus",
+	Category: "Channels",
+	Usage:    "Show the upload status of the remote backup sinks.",
+	Description: `
+    Returns the upload status of every remote sink the multi-channel backup
+    is uploaded to, including the time of the last successful upload and the
+    error of the last failed attempt, if any.
+    `,
+	Action: actionDecorator(backupSinkStatus),
+}
+
+func backupSinkStatus(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.BackupSinkStatus(
+		ctxc, &lnrpc.BackupSinkStatusRequest{},
+	)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
 var verifyChanBackupCommand = cli.Command{
 	Name:      "verifychanbackup",
 	Category:  "Channels",
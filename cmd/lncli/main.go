@@ -459,12 +459,23 @@ func main() {
 		listUnspentCommand,
 		connectCommand,
 		disconnectCommand,
+		peerPolicyCommand,
 		openChannelCommand,
 		batchOpenChannelCommand,
 		closeChannelCommand,
 		closeAllChannelsCommand,
 		abandonChannelCommand,
+		simulateForceCloseCommand,
+		bumpCoopCloseFeeCommand,
+		upgradeChannelCommand,
+		updateLiquidityAdCommand,
+		requestLeaseCommand,
+		listChannelLeasesCommand,
+		listHtlcDeadlinesCommand,
+		subscribeResolverProgressCommand,
 		listPeersCommand,
+		peerStatsCommand,
+		replayCaptureCommand,
 		walletBalanceCommand,
 		channelBalanceCommand,
 		getInfoCommand,
@@ -480,11 +491,14 @@ func main() {
 		closedChannelsCommand,
 		listPaymentsCommand,
 		describeGraphCommand,
+		exportGraphSnapshotCommand,
+		importGraphSnapshotCommand,
 		getNodeMetricsCommand,
 		getChanInfoCommand,
 		getNodeInfoCommand,
 		queryRoutesCommand,
 		getNetworkInfoCommand,
+		gossipBudgetCommand,
 		debugLevelCommand,
 		decodePayReqCommand,
 		listChainTxnsCommand,
@@ -496,6 +510,7 @@ func main() {
 		forwardingHistoryCommand,
 		exportChanBackupCommand,
 		verifyChanBackupCommand,
+		backupSinkStatusCommand,
 		restoreChanBackupCommand,
 		bakeMacaroonCommand,
 		listMacaroonIDsCommand,
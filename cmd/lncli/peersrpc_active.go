@@ -5,6 +5,7 @@ package main
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/lightningnetwork/lnd/lnrpc"
 	"github.com/lightningnetwork/lnd/lnrpc/peersrpc"
@@ -22,6 +23,8 @@ func peersCommands() []cli.Command {
 				"network",
 			Subcommands: []cli.Command{
 				updateNodeAnnouncementCommand,
+				listFeaturesCommand,
+				updateFeaturesCommand,
 			},
 		},
 	}
@@ -160,3 +163,113 @@ func updateNodeAnnouncement(ctx *cli.Context) error {
 
 	return nil
 }
+
+var listFeaturesCommand = cli.Command{
+	Name:     "listfeatures",
+	Category: "Peers",
+	Usage:    "List the feature bits advertised by this node.",
+	Description: `
+	List the features of every feature set, i.e. the features advertised in
+	init messages, node announcements and invoices, along with their
+	dependencies and whether they can be toggled at runtime.`,
+	Action: actionDecorator(listFeatures),
+}
+
+func listFeatures(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getPeersClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.ListFeatures(
+		ctxc, &peersrpc.ListFeaturesRequest{},
+	)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var updateFeaturesCommand = cli.Command{
+	Name:     "updatefeatures",
+	Category: "Peers",
+	Usage:    "Enable or disable optional feature bits.",
+	Description: `
+	Enable or disable optional feature bits of a feature set without
+	restarting the node. Features are only enabled if all the features they
+	depend on are enabled as well, and features other enabled features
+	depend on can't be disabled. If the features of the node announcement
+	change, a new node announcement is broadcast to the network.
+
+	The feature set is one of init, legacy_global, node_ann, invoice or
+	invoice_amp.`,
+	ArgsUsage: "--set=node_ann [--feature_bit_add=] " +
+		"[--feature_bit_remove=]",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "set",
+			Usage: "the feature set to update",
+		},
+		cli.Int64SliceFlag{
+			Name: "feature_bit_add",
+			Usage: "an optional feature bit that needs to be " +
+				"enabled. Can be set multiple times in the " +
+				"same command",
+		},
+		cli.Int64SliceFlag{
+			Name: "feature_bit_remove",
+			Usage: "an optional feature bit that needs to be " +
+				"disabled. Can be set multiple times in the " +
+				"same command",
+		},
+	},
+	Action: actionDecorator(updateFeatures),
+}
+
+func updateFeatures(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getPeersClient(ctx)
+	defer cleanUp()
+
+	setName := "SET_" + strings.ToUpper(ctx.String("set"))
+	set, ok := peersrpc.FeatureSet_value[setName]
+	if !ctx.IsSet("set") || !ok {
+		return fmt.Errorf("invalid feature set %q, must be one of "+
+			"init, legacy_global, node_ann, invoice or "+
+			"invoice_amp", ctx.String("set"))
+	}
+
+	req := &peersrpc.UpdateFeaturesRequest{}
+	addToggles := func(bits []int64, action peersrpc.UpdateAction) {
+		for _, bit := range bits {
+			toggle := &peersrpc.FeatureToggle{
+				Set:        peersrpc.FeatureSet(set),
+				FeatureBit: uint32(bit),
+				Action:     action,
+			}
+			req.Updates = append(req.Updates, toggle)
+		}
+	}
+	addToggles(
+		ctx.Int64Slice("feature_bit_add"), peersrpc.UpdateAction_ADD,
+	)
+	addToggles(
+		ctx.Int64Slice("feature_bit_remove"),
+		peersrpc.UpdateAction_REMOVE,
+	)
+
+	if len(req.Updates) == 0 {
+		return fmt.Errorf("no feature bits to add or remove given")
+	}
+
+	resp, err := client.UpdateFeatures(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
@@ -80,6 +80,7 @@ func walletCommands() []cli.Command {
 				psbtCommand,
 				accountsCommand,
 				requiredReserveCommand,
+				anchorReserveCommand,
 				addressesCommand,
 			},
 		},
@@ -1190,6 +1191,70 @@ func requiredReserve(ctx *cli.Context) error {
 	return nil
 }
 
+var anchorReserveCommand = cli.Command{
+	Name:  "anchorreserve",
+	Usage: "Plans the wallet reserve needed to fee bump anchor channels.",
+	Description: `
+	Returns the wallet reserve needed to fee bump all anchor channels if
+	they were force closed at once at the current fee estimate, along
+	with the wallet outputs assigned to each of the channels.
+
+	Use the flag --follow to keep receiving updated plans each time the
+	periodic planning changes the outcome, e.g. when the wallet becomes
+	under-reserved.
+	`,
+	Flags: []cli.Flag{
+		cli.BoolFlag{
+			Name: "follow",
+			Usage: "(optional) keep streaming updated plans until " +
+				"interrupted",
+		},
+	},
+	Action: actionDecorator(anchorReserve),
+}
+
+func anchorReserve(ctx *cli.Context) error {
+	ctxc := getContext()
+
+	// Display the command's help message if we do not have the expected
+	// number of arguments/flags.
+	if ctx.NArg() > 0 || ctx.NumFlags() > 1 {
+		return cli.ShowCommandHelp(ctx, "anchorreserve")
+	}
+
+	walletClient, cleanUp := getWalletClient(ctx)
+	defer cleanUp()
+
+	if !ctx.Bool("follow") {
+		resp, err := walletClient.AnchorReserve(
+			ctxc, &walletrpc.AnchorReserveRequest{},
+		)
+		if err != nil {
+			return err
+		}
+
+		printRespJSON(resp)
+
+		return nil
+	}
+
+	stream, err := walletClient.SubscribeAnchorReserve(
+		ctxc, &walletrpc.SubscribeAnchorReserveRequest{},
+	)
+	if err != nil {
+		return err
+	}
+
+	for {
+		plan, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+
+		printRespJSON(plan)
+	}
+}
+
 var listAddressesCommand = cli.Command{
 	Name:  "list",
 	Usage: "Retrieve information of existing on-chain wallet addresses.",
@@ -51,6 +51,7 @@ const (
 	defaultChainSubDirname    = "chain"
 	defaultGraphSubDirname    = "graph"
 	defaultTowerSubDirname    = "watchtower"
+	defaultCaptureSubDirname  = "wirecapture"
 	defaultTLSCertFilename    = "tls.cert"
 	defaultTLSKeyFilename     = "tls.key"
 	defaultAdminMacFilename   = "admin.macaroon"
@@ -454,6 +455,18 @@ type Config struct {
 
 	Htlcswitch *lncfg.Htlcswitch `group:"htlcswitch" namespace:"htlcswitch"`
 
+	RemoteBackup *lncfg.RemoteBackup `group:"remotebackup" namespace:"remotebackup"`
+
+	AnchorReserve *lncfg.AnchorReserve `group:"anchorreserve" namespace:"anchorreserve"`
+
+	ForceClosePolicy *lncfg.ForceClosePolicy `group:"forceclosepolicy" namespace:"forceclosepolicy"`
+
+	PeerPolicy *lncfg.PeerPolicy `group:"peerpolicy" namespace:"peerpolicy"`
+
+	WireCapture *lncfg.WireCapture `group:"wirecapture" namespace:"wirecapture"`
+
+	LiquidityAd *lncfg.LiquidityAd `group:"liquidityad" namespace:"liquidityad"`
+
 	// LogWriter is the root logger that all of the daemon's subloggers are
 	// hooked up to.
 	LogWriter *build.RotatingLogWriter
@@ -662,6 +675,12 @@ func DefaultConfig() Config {
 		Htlcswitch: &lncfg.Htlcswitch{
 			MailboxDeliveryTimeout: htlcswitch.DefaultMailboxDeliveryTimeout,
 		},
+		RemoteBackup:     lncfg.DefaultRemoteBackup(),
+		AnchorReserve:    lncfg.DefaultAnchorReserve(),
+		ForceClosePolicy: lncfg.DefaultForceClosePolicy(),
+		PeerPolicy:       lncfg.DefaultPeerPolicy(),
+		WireCapture:      lncfg.DefaultWireCapture(),
+		LiquidityAd:      lncfg.DefaultLiquidityAd(),
 	}
 }
 
@@ -907,6 +926,12 @@ func ValidateConfig(cfg Config, interceptor signal.Interceptor, fileParser,
 	cfg.Tor.PrivateKeyPath = CleanAndExpandPath(cfg.Tor.PrivateKeyPath)
 	cfg.Tor.WatchtowerKeyPath = CleanAndExpandPath(cfg.Tor.WatchtowerKeyPath)
 	cfg.Watchtower.TowerDir = CleanAndExpandPath(cfg.Watchtower.TowerDir)
+	if cfg.WireCapture.Dir == "" {
+		cfg.WireCapture.Dir = filepath.Join(
+			cfg.DataDir, defaultCaptureSubDirname,
+		)
+	}
+	cfg.WireCapture.Dir = CleanAndExpandPath(cfg.WireCapture.Dir)
 	cfg.BackupFilePath = CleanAndExpandPath(cfg.BackupFilePath)
 	cfg.WalletUnlockPasswordFile = CleanAndExpandPath(
 		cfg.WalletUnlockPasswordFile,
@@ -1754,6 +1779,12 @@ func ValidateConfig(cfg Config, interceptor signal.Interceptor, fileParser,
 		cfg.RemoteSigner,
 		cfg.Sweeper,
 		cfg.Htlcswitch,
+		cfg.RemoteBackup,
+		cfg.AnchorReserve,
+		cfg.ForceClosePolicy,
+		cfg.PeerPolicy,
+		cfg.WireCapture,
+		cfg.LiquidityAd,
 	)
 	if err != nil {
 		return nil, err
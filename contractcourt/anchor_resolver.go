@@ -105,6 +105,11 @@ func (c *anchorResolver) Resolve() (ContractResolver, error) {
 		nil,
 	)
 
+	c.reportLock.Lock()
+	c.currentReport.ResolverStage = ResolverStageSweeping
+	c.currentReport.sweepOutpoint = &c.anchor
+	c.reportLock.Unlock()
+
 	resultChan, err := c.Sweeper.SweepInput(
 		&anchorInput,
 		sweep.Params{
@@ -166,6 +171,8 @@ func (c *anchorResolver) Resolve() (ContractResolver, error) {
 		c.currentReport.RecoveredBalance = c.currentReport.LimboBalance
 	}
 	c.currentReport.LimboBalance = 0
+	c.currentReport.ResolverStage = ResolverStageResolved
+	c.currentReport.sweepOutpoint = nil
 	report := c.currentReport.resolverReport(
 		spendTx, channeldb.ResolverTypeAnchor, outcome,
 	)
@@ -20,6 +20,9 @@ import (
 	"github.com/lightningnetwork/lnd/lnwallet"
 	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
 	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing/route"
+	"github.com/lightningnetwork/lnd/subscribe"
+	"github.com/lightningnetwork/lnd/ticker"
 )
 
 // ErrChainArbExiting signals that the chain arbitrator is shutting down.
@@ -72,6 +75,11 @@ type ChainArbitratorConfig struct {
 	// htlcs. This value can be lower than the incoming broadcast delta.
 	OutgoingBroadcastDelta uint32
 
+	// ForceClosePolicy is an optional policy engine that is consulted to
+	// decide whether an expiring HTLC justifies going on-chain. If nil,
+	// the broadcast deltas above are used for all HTLCs.
+	ForceClosePolicy *ForceClosePolicyEngine
+
 	// NewSweepAddr is a function that returns a new address under control
 	// by the wallet. We'll use this to sweep any no-delay outputs as a
 	// result of unilateral channel closes.
@@ -199,6 +207,12 @@ type ChainArbitratorConfig struct {
 
 	// HtlcNotifier is an interface that htlc events are sent to.
 	HtlcNotifier HtlcNotifier
+
+	// ReleaseAnchorReserve is called before anchors are offered to the
+	// sweeper, so any wallet outputs that were locked to reserve them
+	// for anchor fee bumping become available to the sweeper. It may be
+	// nil.
+	ReleaseAnchorReserve func()
 }
 
 // ChainArbitrator is a sub-system that oversees the on-chain resolution of all
@@ -232,6 +246,14 @@ type ChainArbitrator struct {
 	// active channels that it must still watch over.
 	chanSource *channeldb.DB
 
+	// progressNtfns is used to notify subscribers of changes in the
+	// progress of the contract resolvers.
+	progressNtfns *subscribe.Server
+
+	// progressTicker determines how often the progress of the contract
+	// resolvers is polled.
+	progressTicker ticker.Ticker
+
 	quit chan struct{}
 
 	wg sync.WaitGroup
@@ -247,6 +269,8 @@ func NewChainArbitrator(cfg ChainArbitratorConfig,
 		activeChannels: make(map[wire.OutPoint]*ChannelArbitrator),
 		activeWatchers: make(map[wire.OutPoint]*chainWatcher),
 		chanSource:     db,
+		progressNtfns:  subscribe.NewServer(),
+		progressTicker: ticker.New(resolverProgressInterval),
 		quit:           make(chan struct{}),
 	}
 }
@@ -356,6 +380,7 @@ func newActiveChannelArbitrator(channel *channeldb.OpenChannel,
 		ChanPoint:   chanPoint,
 		Channel:     c.getArbChannel(channel),
 		ShortChanID: channel.ShortChanID(),
+		PeerPub:     route.NewVertex(channel.IdentityPub),
 
 		MarkCommitmentBroadcasted: channel.MarkCommitmentBroadcasted,
 		MarkChannelClosed: func(summary *channeldb.ChannelCloseSummary,
@@ -726,6 +751,15 @@ func (c *ChainArbitrator) Start() error {
 		c.dispatchBlocks(blockEpoch)
 	}()
 
+	// Start the notification server for resolver progress, along with the
+	// goroutine that feeds it.
+	if err := c.progressNtfns.Start(); err != nil {
+		return err
+	}
+
+	c.wg.Add(1)
+	go c.watchResolverProgress()
+
 	// TODO(roasbeef): eventually move all breach watching here
 
 	return nil
@@ -963,6 +997,10 @@ func (c *ChainArbitrator) Stop() error {
 
 	c.wg.Wait()
 
+	if err := c.progressNtfns.Stop(); err != nil {
+		log.Errorf("unable to stop resolver progress notifier: %v", err)
+	}
+
 	return nil
 }
 
@@ -595,6 +595,25 @@ func (c *chainWatcher) closeObserver(spendNtfn *chainntnfs.SpendEvent) {
 			return
 		}
 
+		// Before looking at the state hint, we'll check whether this
+		// is one of the cooperative closing transactions we signed.
+		// With the RBF cooperative close protocol, these don't use a
+		// finalized sequence number, so they can't be told apart from
+		// a commitment by looking at the transaction alone.
+		isCoopClose, err := c.isKnownCoopClose(commitSpend)
+		if err != nil {
+			log.Errorf("Unable to fetch coop close txids: %v", err)
+			return
+		}
+		if isCoopClose {
+			err := c.dispatchCooperativeClose(commitSpend)
+			if err != nil {
+				log.Errorf("unable to handle co op close: %v",
+					err)
+			}
+			return
+		}
+
 		// Decode the state hint encoded within the commitment
 		// transaction to determine if this is a revoked state or not.
 		obfuscator := c.stateHintObfuscator
@@ -695,6 +714,25 @@ func (c *chainWatcher) closeObserver(spendNtfn *chainntnfs.SpendEvent) {
 	}
 }
 
+// isKnownCoopClose returns true if the passed spend is one of the cooperative
+// closing transactions recorded for the channel.
+func (c *chainWatcher) isKnownCoopClose(
+	commitSpend *chainntnfs.SpendDetail) (bool, error) {
+
+	txids, err := c.cfg.chanState.CoopCloseTxids()
+	if err != nil {
+		return false, err
+	}
+
+	for _, txid := range txids {
+		if txid == *commitSpend.SpenderTxHash {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
 // handleKnownLocalState checks whether the passed spend is a local state that
 // is known to us (the current state). If so we will act on this state using
 // the passed chainSet. If this is not a known local state, false is returned.
@@ -78,6 +78,149 @@ func TestChainWatcherRemoteUnilateralClose(t *testing.T) {
 	}
 }
 
+// TestChainWatcherRbfCoopClose tests that the chain watcher detects a
+// cooperative closing transaction that doesn't use a finalized sequence
+// number, as long as its txid was recorded in the database.
+func TestChainWatcherRbfCoopClose(t *testing.T) {
+	t.Parallel()
+
+	aliceChannel, _, err := lnwallet.CreateTestChannels(
+		t, channeldb.SingleFunderTweaklessBit,
+	)
+	require.NoError(t, err, "unable to create test channels")
+
+	aliceNotifier := &mock.ChainNotifier{
+		SpendChan: make(chan *chainntnfs.SpendDetail),
+		EpochChan: make(chan *chainntnfs.BlockEpoch),
+		ConfChan:  make(chan *chainntnfs.TxConfirmation),
+	}
+	aliceChainWatcher, err := newChainWatcher(chainWatcherConfig{
+		chanState:           aliceChannel.State(),
+		notifier:            aliceNotifier,
+		signer:              aliceChannel.Signer,
+		extractStateNumHint: lnwallet.GetStateNumHint,
+	})
+	require.NoError(t, err, "unable to create chain watcher")
+	err = aliceChainWatcher.Start()
+	require.NoError(t, err, "unable to start chain watcher")
+	defer aliceChainWatcher.Stop()
+
+	chanEvents := aliceChainWatcher.SubscribeChannelEvents()
+
+	// Create a replaceable closing transaction and record it as one of
+	// the cooperative closing transactions of the channel.
+	closeTx := wire.NewMsgTx(2)
+	closeTx.AddTxIn(&wire.TxIn{
+		PreviousOutPoint: aliceChannel.State().FundingOutpoint,
+		Sequence:         wire.MaxTxInSequenceNum - 2,
+	})
+	closeTx.AddTxOut(&wire.TxOut{
+		Value:    1000,
+		PkScript: aliceChannel.State().LocalShutdownScript,
+	})
+	err = aliceChannel.State().MarkCoopBroadcasted(closeTx, true)
+	require.NoError(t, err)
+
+	closeTxid := closeTx.TxHash()
+	aliceNotifier.SpendChan <- &chainntnfs.SpendDetail{
+		SpenderTxHash: &closeTxid,
+		SpendingTx:    closeTx,
+	}
+
+	select {
+	case closeInfo := <-chanEvents.CooperativeClosure:
+		require.Equal(t, closeTxid, closeInfo.ClosingTXID)
+
+	case <-time.After(time.Second * 15):
+		t.Fatalf("didn't receive cooperative close event")
+	}
+}
+
+// TestChainWatcherUnansweredRbfProposal tests that the chain watcher detects
+// a cooperative close when the remote party broadcasts the closing
+// transaction of our RBF proposal without ever replying to it.
+func TestChainWatcherUnansweredRbfProposal(t *testing.T) {
+	t.Parallel()
+
+	aliceChannel, bobChannel, err := lnwallet.CreateTestChannels(
+		t, channeldb.SingleFunderTweaklessBit,
+	)
+	require.NoError(t, err, "unable to create test channels")
+
+	aliceNotifier := &mock.ChainNotifier{
+		SpendChan: make(chan *chainntnfs.SpendDetail),
+		EpochChan: make(chan *chainntnfs.BlockEpoch),
+		ConfChan:  make(chan *chainntnfs.TxConfirmation),
+	}
+	aliceChainWatcher, err := newChainWatcher(chainWatcherConfig{
+		chanState:           aliceChannel.State(),
+		notifier:            aliceNotifier,
+		signer:              aliceChannel.Signer,
+		extractStateNumHint: lnwallet.GetStateNumHint,
+	})
+	require.NoError(t, err, "unable to create chain watcher")
+	err = aliceChainWatcher.Start()
+	require.NoError(t, err, "unable to start chain watcher")
+	defer aliceChainWatcher.Stop()
+
+	chanEvents := aliceChainWatcher.SubscribeChannelEvents()
+
+	const (
+		fee      = 5000
+		sequence = wire.MaxTxInSequenceNum - 2
+		lockTime = 100
+	)
+	var (
+		aliceScript = bytes.Repeat([]byte{0x01}, 22)
+		bobScript   = bytes.Repeat([]byte{0x02}, 22)
+		aliceOpts   = []lnwallet.ChanCloseOpt{
+			lnwallet.WithCustomSequence(sequence),
+			lnwallet.WithCustomLockTime(lockTime),
+			lnwallet.WithCustomPayer(true),
+		}
+		bobOpts = []lnwallet.ChanCloseOpt{
+			lnwallet.WithCustomSequence(sequence),
+			lnwallet.WithCustomLockTime(lockTime),
+			lnwallet.WithCustomPayer(false),
+		}
+	)
+
+	// Alice signs her proposal and records its txid, just like she does
+	// before sending it to Bob.
+	aliceSig, txid, _, err := aliceChannel.CreateCloseProposal(
+		fee, aliceScript, bobScript, aliceOpts...,
+	)
+	require.NoError(t, err)
+	require.NoError(t, aliceChannel.AddCoopCloseTxid(*txid))
+
+	// Bob countersigns the proposal and broadcasts the closing
+	// transaction without ever sending his signature to Alice.
+	bobSig, _, _, err := bobChannel.CreateCloseProposal(
+		fee, bobScript, aliceScript, bobOpts...,
+	)
+	require.NoError(t, err)
+	closeTx, _, err := bobChannel.CompleteCooperativeClose(
+		bobSig, aliceSig, bobScript, aliceScript, fee, bobOpts...,
+	)
+	require.NoError(t, err)
+
+	closeTxid := closeTx.TxHash()
+	require.Equal(t, *txid, closeTxid)
+
+	aliceNotifier.SpendChan <- &chainntnfs.SpendDetail{
+		SpenderTxHash: &closeTxid,
+		SpendingTx:    closeTx,
+	}
+
+	select {
+	case closeInfo := <-chanEvents.CooperativeClosure:
+		require.Equal(t, closeTxid, closeInfo.ClosingTXID)
+
+	case <-time.After(time.Second * 15):
+		t.Fatalf("didn't receive cooperative close event")
+	}
+}
+
 func addFakeHTLC(t *testing.T, htlcAmount lnwire.MilliSatoshi, id uint64,
 	aliceChannel, bobChannel *lnwallet.LightningChannel) {
 
@@ -23,6 +23,7 @@ import (
 	"github.com/lightningnetwork/lnd/lntypes"
 	"github.com/lightningnetwork/lnd/lnwallet"
 	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing/route"
 	"github.com/lightningnetwork/lnd/sweep"
 )
 
@@ -119,6 +120,10 @@ type ChannelArbitratorConfig struct {
 	// to the switch during contract resolution.
 	ShortChanID lnwire.ShortChannelID
 
+	// PeerPub is the identity public key of the channel peer. It's used to
+	// look up the force close policy that applies to the channel.
+	PeerPub route.Vertex
+
 	// ChainEvents is an active subscription to the chain watcher for this
 	// channel to be notified of any on-chain activity related to this
 	// channel.
@@ -220,6 +225,31 @@ type ContractReport struct {
 	// RecoveredBalance is the total value that has been successfully swept
 	// back to the user's wallet.
 	RecoveredBalance btcutil.Amount
+
+	// ResolverStage describes what the resolver of the output is
+	// currently waiting for.
+	ResolverStage ResolverStage
+
+	// SweepTxid is the txid of the most recent transaction the sweeper
+	// broadcast to claim the output in its current stage, if any.
+	SweepTxid *chainhash.Hash
+
+	// SweepFee is the fee spent so far on sweeping the output in its
+	// current stage, summed up over all broadcast sweep transactions
+	// including replaced ones. If the sweeper batched the output with
+	// others, only its weight-proportional share of the fee is included.
+	SweepFee btcutil.Amount
+
+	// SweepAttempts is the number of sweep transactions that have been
+	// broadcast to claim the output in its current stage, including fee
+	// bumps.
+	SweepAttempts uint32
+
+	// sweepOutpoint is the outpoint that was offered to the sweeper to
+	// claim the output, if any. While a second-level HTLC transaction is
+	// being confirmed, this is the HTLC output on the commitment rather
+	// than Outpoint.
+	sweepOutpoint *wire.OutPoint
 }
 
 // resolverReport creates a resolve report using some of the information in the
@@ -693,7 +723,6 @@ func (c *ChannelArbitrator) relaunchResolvers(commitSet *CommitSet,
 // Report returns htlc reports for the active resolvers.
 func (c *ChannelArbitrator) Report() []*ContractReport {
 	c.activeResolversLock.RLock()
-	defer c.activeResolversLock.RUnlock()
 
 	var reports []*ContractReport
 	for _, resolver := range c.activeResolvers {
@@ -710,6 +739,13 @@ func (c *ChannelArbitrator) Report() []*ContractReport {
 		reports = append(reports, report)
 	}
 
+	c.activeResolversLock.RUnlock()
+
+	// Fill in the details of the outputs that have been handed off to the
+	// sweeper. This is done after releasing the lock, as querying the
+	// sweeper may block.
+	c.addSweepProgress(reports)
+
 	return reports
 }
 
@@ -1182,6 +1218,13 @@ func (c *ChannelArbitrator) stateStep(
 func (c *ChannelArbitrator) sweepAnchors(anchors *lnwallet.AnchorResolutions,
 	heightHint uint32) error {
 
+	// The sweeper can't use wallet outputs that are locked, so we'll make
+	// sure the outputs reserved for anchor fee bumping are released
+	// before offering the anchors.
+	if c.cfg.ReleaseAnchorReserve != nil {
+		c.cfg.ReleaseAnchorReserve()
+	}
+
 	// Use the chan id as the exclusive group. This prevents any of the
 	// anchors from being batched together.
 	exclusiveGroup := c.cfg.ShortChanID.ToUint64()
@@ -1565,6 +1608,29 @@ func (c *ChannelArbitrator) shouldGoOnChain(htlc channeldb.HTLC,
 	return isForwarded || upTime > c.cfg.PaymentsExpirationGracePeriod
 }
 
+// htlcDeadline returns the force close deadline of the given HTLC. If no force
+// close policy engine is configured, the static broadcast deltas are used and
+// the HTLC is never exempt from triggering a force close.
+func (c *ChannelArbitrator) htlcDeadline(htlc channeldb.HTLC) *HtlcDeadline {
+	if c.cfg.ForceClosePolicy != nil {
+		return c.cfg.ForceClosePolicy.Evaluate(c.cfg.PeerPub, htlc)
+	}
+
+	deadline := &HtlcDeadline{
+		Htlc:           htlc,
+		BroadcastDelta: c.cfg.OutgoingBroadcastDelta,
+	}
+	if htlc.Incoming {
+		deadline.BroadcastDelta = c.cfg.IncomingBroadcastDelta
+	}
+	if htlc.RefundTimeout > deadline.BroadcastDelta {
+		deadline.TriggerHeight = htlc.RefundTimeout -
+			deadline.BroadcastDelta
+	}
+
+	return deadline
+}
+
 // checkCommitChainActions is called for each new block connected to the end of
 // the main chain. Given the new block height, this new method will examine all
 // active HTLC's, and determine if we need to go on-chain to claim any of them.
@@ -1592,11 +1658,26 @@ func (c *ChannelArbitrator) checkCommitChainActions(height uint32,
 	for _, htlc := range htlcs.outgoingHTLCs {
 		// We'll need to go on-chain for an outgoing HTLC if it was
 		// never resolved downstream, and it's "close" to timing out.
-		toChain := c.shouldGoOnChain(htlc, c.cfg.OutgoingBroadcastDelta,
+		// The force close policy may exempt the HTLC though, in which
+		// case it's only acted upon if we go on-chain for another
+		// reason.
+		deadline := c.htlcDeadline(htlc)
+		toChain := c.shouldGoOnChain(htlc, deadline.BroadcastDelta,
 			height,
 		)
 
-		if toChain {
+		switch {
+		case toChain && deadline.Exempt():
+			log.Infof("ChannelArbitrator(%v): not going to chain "+
+				"for outgoing htlc %x: exempt by force close "+
+				"policy, reason=%v, amt=%v, on_chain_cost=%v",
+				c.cfg.ChanPoint, htlc.RHash[:],
+				deadline.ExemptReason, htlc.Amt.ToSatoshis(),
+				deadline.OnChainCost)
+
+			toChain = false
+
+		case toChain:
 			// Convert to int64 in case of overflow.
 			remainingBlocks := int64(htlc.RefundTimeout) -
 				int64(height)
@@ -1606,7 +1687,7 @@ func (c *ChannelArbitrator) checkCommitChainActions(height uint32,
 				"blocks_until_expiry=%v, broadcast_delta=%v",
 				c.cfg.ChanPoint, htlc.RHash[:],
 				htlc.RefundTimeout, remainingBlocks,
-				c.cfg.OutgoingBroadcastDelta,
+				deadline.BroadcastDelta,
 			)
 		}
 
@@ -1627,11 +1708,23 @@ func (c *ChannelArbitrator) checkCommitChainActions(height uint32,
 			continue
 		}
 
-		toChain := c.shouldGoOnChain(htlc, c.cfg.IncomingBroadcastDelta,
+		deadline := c.htlcDeadline(htlc)
+		toChain := c.shouldGoOnChain(htlc, deadline.BroadcastDelta,
 			height,
 		)
 
-		if toChain {
+		switch {
+		case toChain && deadline.Exempt():
+			log.Infof("ChannelArbitrator(%v): not going to chain "+
+				"for incoming htlc %x: exempt by force close "+
+				"policy, reason=%v, amt=%v, on_chain_cost=%v",
+				c.cfg.ChanPoint, htlc.RHash[:],
+				deadline.ExemptReason, htlc.Amt.ToSatoshis(),
+				deadline.OnChainCost)
+
+			toChain = false
+
+		case toChain:
 			// Convert to int64 in case of overflow.
 			remainingBlocks := int64(htlc.RefundTimeout) -
 				int64(height)
@@ -1641,7 +1734,7 @@ func (c *ChannelArbitrator) checkCommitChainActions(height uint32,
 				"blocks_until_expiry=%v, broadcast_delta=%v",
 				c.cfg.ChanPoint, htlc.RHash[:],
 				htlc.RefundTimeout, remainingBlocks,
-				c.cfg.IncomingBroadcastDelta,
+				deadline.BroadcastDelta,
 			)
 		}
 
@@ -1681,8 +1774,8 @@ func (c *ChannelArbitrator) checkCommitChainActions(height uint32,
 		// mark it still "live". After we broadcast, we'll monitor it
 		// until the HTLC times out to see if we can also redeem it
 		// on-chain.
-		case !c.shouldGoOnChain(htlc, c.cfg.OutgoingBroadcastDelta,
-			height,
+		case !c.shouldGoOnChain(
+			htlc, c.htlcDeadline(htlc).BroadcastDelta, height,
 		):
 			// TODO(roasbeef): also need to be able to query
 			// circuit map to see if HTLC hasn't been fully
@@ -1852,10 +1945,12 @@ func (c *ChannelArbitrator) checkRemoteDanglingActions(
 	// failed now so we can free up the incoming HTLC.
 	for _, htlc := range pendingRemoteHTLCs {
 		// We'll now check if we need to go to chain in order to cancel
-		// the incoming HTLC.
-		goToChain := c.shouldGoOnChain(htlc, c.cfg.OutgoingBroadcastDelta,
-			height,
-		)
+		// the incoming HTLC. HTLCs exempt by the force close policy
+		// don't justify going to chain on their own.
+		deadline := c.htlcDeadline(htlc)
+		goToChain := c.shouldGoOnChain(
+			htlc, deadline.BroadcastDelta, height,
+		) && !deadline.Exempt()
 
 		// If we don't need to go to chain, and no commitments have
 		// been confirmed, then we can move on. Otherwise, if
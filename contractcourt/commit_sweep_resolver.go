@@ -208,12 +208,17 @@ func (c *commitSweepResolver) Resolve() (ContractResolver, error) {
 		confHeight, unlockHeight)
 
 	// Update report now that we learned the confirmation height.
+	isLocked := c.commitResolution.MaturityDelay > 0 || c.hasCLTV()
+
 	c.reportLock.Lock()
 	c.currentReport.MaturityHeight = unlockHeight
+	if isLocked {
+		c.currentReport.ResolverStage = ResolverStageWaitingForCsv
+	}
 	c.reportLock.Unlock()
 
 	// If there is a csv/cltv lock, we'll wait for that.
-	if c.commitResolution.MaturityDelay > 0 || c.hasCLTV() {
+	if isLocked {
 		// Determine what height we should wait until for the locks to
 		// expire.
 		var waitHeight uint32
@@ -316,6 +321,11 @@ func (c *commitSweepResolver) Resolve() (ContractResolver, error) {
 	// sweeper.
 	c.log.Infof("sweeping commit output")
 
+	c.reportLock.Lock()
+	c.currentReport.ResolverStage = ResolverStageSweeping
+	c.currentReport.sweepOutpoint = &c.commitResolution.SelfOutPoint
+	c.reportLock.Unlock()
+
 	feePref := sweep.FeePreference{ConfTarget: commitOutputConfTarget}
 	resultChan, err := c.Sweeper.SweepInput(inp, sweep.Params{Fee: feePref})
 	if err != nil {
@@ -368,6 +378,8 @@ func (c *commitSweepResolver) Resolve() (ContractResolver, error) {
 		c.currentReport.RecoveredBalance = c.currentReport.LimboBalance
 	}
 	c.currentReport.LimboBalance = 0
+	c.currentReport.ResolverStage = ResolverStageResolved
+	c.currentReport.sweepOutpoint = nil
 	c.reportLock.Unlock()
 	report := c.currentReport.resolverReport(
 		&sweepTxID, channeldb.ResolverTypeCommit, outcome,
@@ -509,6 +521,7 @@ func (c *commitSweepResolver) initReport() {
 		Amount:           amt,
 		LimboBalance:     amt,
 		RecoveredBalance: 0,
+		ResolverStage:    ResolverStageWaitingForCommitConf,
 	}
 }
 
@@ -108,6 +108,7 @@ type mockSweeper struct {
 	sweepTx           *wire.MsgTx
 	sweepErr          error
 	createSweepTxChan chan *wire.MsgTx
+	pendingInputs     map[wire.OutPoint]*sweep.PendingInput
 
 	deadlines []int
 }
@@ -164,6 +165,12 @@ func (s *mockSweeper) UpdateParams(input wire.OutPoint,
 	return result, nil
 }
 
+func (s *mockSweeper) PendingInputs() (map[wire.OutPoint]*sweep.PendingInput,
+	error) {
+
+	return s.pendingInputs, nil
+}
+
 var _ UtxoSweeper = &mockSweeper{}
 
 // TestCommitSweepResolverNoDelay tests resolution of a direct commitment output
@@ -268,10 +275,11 @@ func testCommitSweepResolverDelay(t *testing.T, sweepErr error) {
 
 	report := ctx.resolver.report()
 	expectedReport := ContractReport{
-		Outpoint:     outpoint,
-		Type:         ReportOutputUnencumbered,
-		Amount:       btcutil.Amount(amt),
-		LimboBalance: btcutil.Amount(amt),
+		Outpoint:      outpoint,
+		Type:          ReportOutputUnencumbered,
+		Amount:        btcutil.Amount(amt),
+		LimboBalance:  btcutil.Amount(amt),
+		ResolverStage: ResolverStageWaitingForCommitConf,
 	}
 	if *report != expectedReport {
 		t.Fatalf("unexpected resolver report. want=%v got=%v",
@@ -292,6 +300,9 @@ func testCommitSweepResolverDelay(t *testing.T, sweepErr error) {
 	if report.MaturityHeight != testInitialBlockHeight+2 {
 		t.Fatal("report maturity height incorrect")
 	}
+	if report.ResolverStage != ResolverStageWaitingForCsv {
+		t.Fatalf("unexpected resolver stage: %v", report.ResolverStage)
+	}
 
 	// Notify initial block height. The csv lock is still in effect, so we
 	// don't expect any sweep to happen yet.
@@ -342,6 +353,7 @@ func testCommitSweepResolverDelay(t *testing.T, sweepErr error) {
 		Amount:           btcutil.Amount(amt),
 		MaturityHeight:   testInitialBlockHeight + 2,
 		RecoveredBalance: expectedRecoveredBalance,
+		ResolverStage:    ResolverStageResolved,
 	}
 	if *report != expectedReport {
 		t.Fatalf("unexpected resolver report. want=%v got=%v",
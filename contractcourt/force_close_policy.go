@@ -0,0 +1,255 @@
+package contractcourt
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/input"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+	"github.com/lightningnetwork/lnd/routing/route"
+)
+
+const (
+	// DefaultForceClosePolicyConfTarget is the default confirmation target
+	// used to estimate the on-chain cost of claiming an HTLC.
+	DefaultForceClosePolicyConfTarget = 6
+)
+
+// HtlcExemptReason describes why a force close policy decided that an
+// expiring HTLC must not trigger a force close.
+type HtlcExemptReason uint8
+
+const (
+	// HtlcNotExempt indicates the HTLC is subject to the regular broadcast
+	// deadline.
+	HtlcNotExempt HtlcExemptReason = iota
+
+	// HtlcExemptBelowMinValue indicates the HTLC is worth less than the
+	// minimum value configured by the policy.
+	HtlcExemptBelowMinValue
+
+	// HtlcExemptUneconomical indicates the HTLC is worth less than the
+	// expected cost of claiming it on-chain.
+	HtlcExemptUneconomical
+)
+
+// String returns a human readable version of the exempt reason.
+func (r HtlcExemptReason) String() string {
+	switch r {
+	case HtlcNotExempt:
+		return "NotExempt"
+
+	case HtlcExemptBelowMinValue:
+		return "BelowMinValue"
+
+	case HtlcExemptUneconomical:
+		return "Uneconomical"
+
+	default:
+		return "<unknown reason>"
+	}
+}
+
+// ForceClosePolicy is a set of rules the channel arbitrator consults to
+// decide whether an HTLC that is about to expire justifies going on-chain.
+type ForceClosePolicy struct {
+	// IncomingBroadcastDelta is the number of blocks before the expiry of
+	// an incoming HTLC we know the preimage of at which we'll go on-chain
+	// to claim it. A zero value inherits the default policy.
+	IncomingBroadcastDelta uint32
+
+	// OutgoingBroadcastDelta is the number of blocks after the expiry of
+	// an outgoing HTLC at which we'll go on-chain to time it out. A zero
+	// value inherits the default policy.
+	OutgoingBroadcastDelta uint32
+
+	// MinHtlcValue is the value below which an expiring HTLC is never a
+	// reason to force close the channel.
+	MinHtlcValue btcutil.Amount
+
+	// SkipUneconomical indicates that HTLCs worth less than the expected
+	// cost of claiming them on-chain shouldn't trigger a force close.
+	SkipUneconomical bool
+
+	// ConfTarget is the confirmation target used to estimate the cost of
+	// claiming an HTLC on-chain. A zero value inherits the default policy.
+	ConfTarget uint32
+}
+
+// HtlcDeadline is the outcome of evaluating an HTLC against the force close
+// policy of its channel.
+type HtlcDeadline struct {
+	// Htlc is the HTLC that was evaluated.
+	Htlc channeldb.HTLC
+
+	// BroadcastDelta is the broadcast delta that applies to the HTLC.
+	BroadcastDelta uint32
+
+	// TriggerHeight is the height at which the HTLC would trigger a force
+	// close, unless it is exempt.
+	TriggerHeight uint32
+
+	// OnChainCost is the estimated cost of claiming the HTLC on-chain. It
+	// is only set if the policy skips uneconomical HTLCs.
+	OnChainCost btcutil.Amount
+
+	// ExemptReason is set if the HTLC must not trigger a force close.
+	ExemptReason HtlcExemptReason
+}
+
+// Exempt returns true if the HTLC must not trigger a force close on its own.
+func (h *HtlcDeadline) Exempt() bool {
+	return h.ExemptReason != HtlcNotExempt
+}
+
+// ForceClosePolicyEngine holds the default force close policy along with any
+// per-peer overrides, and evaluates HTLCs against them.
+type ForceClosePolicyEngine struct {
+	defaultPolicy ForceClosePolicy
+
+	estimator chainfee.Estimator
+
+	mu           sync.RWMutex
+	peerPolicies map[route.Vertex]ForceClosePolicy
+}
+
+// NewForceClosePolicyEngine creates a new policy engine with the given
+// default policy. The fee estimator is used to estimate the on-chain cost of
+// HTLCs for policies that skip uneconomical HTLCs.
+func NewForceClosePolicyEngine(defaultPolicy ForceClosePolicy,
+	estimator chainfee.Estimator) (*ForceClosePolicyEngine, error) {
+
+	if defaultPolicy.IncomingBroadcastDelta == 0 ||
+		defaultPolicy.OutgoingBroadcastDelta == 0 {
+
+		return nil, errors.New("default force close policy must set " +
+			"both broadcast deltas")
+	}
+
+	if defaultPolicy.ConfTarget == 0 {
+		defaultPolicy.ConfTarget = DefaultForceClosePolicyConfTarget
+	}
+
+	return &ForceClosePolicyEngine{
+		defaultPolicy: defaultPolicy,
+		estimator:     estimator,
+		peerPolicies:  make(map[route.Vertex]ForceClosePolicy),
+	}, nil
+}
+
+// SetPeerPolicy sets the policy that applies to all channels with the given
+// peer, replacing any existing one.
+func (e *ForceClosePolicyEngine) SetPeerPolicy(peer route.Vertex,
+	policy ForceClosePolicy) {
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.peerPolicies[peer] = policy
+}
+
+// RemovePeerPolicy removes the policy of the given peer, so its channels fall
+// back to the default policy.
+func (e *ForceClosePolicyEngine) RemovePeerPolicy(peer route.Vertex) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	delete(e.peerPolicies, peer)
+}
+
+// PolicyFor returns the effective policy for channels with the given peer.
+func (e *ForceClosePolicyEngine) PolicyFor(peer route.Vertex) ForceClosePolicy {
+	e.mu.RLock()
+	policy, ok := e.peerPolicies[peer]
+	e.mu.RUnlock()
+
+	if !ok {
+		return e.defaultPolicy
+	}
+
+	if policy.IncomingBroadcastDelta == 0 {
+		policy.IncomingBroadcastDelta =
+			e.defaultPolicy.IncomingBroadcastDelta
+	}
+	if policy.OutgoingBroadcastDelta == 0 {
+		policy.OutgoingBroadcastDelta =
+			e.defaultPolicy.OutgoingBroadcastDelta
+	}
+	if policy.ConfTarget == 0 {
+		policy.ConfTarget = e.defaultPolicy.ConfTarget
+	}
+
+	return policy
+}
+
+// Evaluate evaluates the HTLC against the effective policy of the given peer
+// and returns its force close deadline.
+func (e *ForceClosePolicyEngine) Evaluate(peer route.Vertex,
+	htlc channeldb.HTLC) *HtlcDeadline {
+
+	policy := e.PolicyFor(peer)
+
+	deadline := &HtlcDeadline{
+		Htlc:           htlc,
+		BroadcastDelta: policy.OutgoingBroadcastDelta,
+	}
+	if htlc.Incoming {
+		deadline.BroadcastDelta = policy.IncomingBroadcastDelta
+	}
+
+	// Guard against an underflow for HTLCs with a tiny expiry, these will
+	// trigger right away.
+	if htlc.RefundTimeout > deadline.BroadcastDelta {
+		deadline.TriggerHeight = htlc.RefundTimeout -
+			deadline.BroadcastDelta
+	}
+
+	value := htlc.Amt.ToSatoshis()
+	if value < policy.MinHtlcValue {
+		deadline.ExemptReason = HtlcExemptBelowMinValue
+		return deadline
+	}
+
+	if !policy.SkipUneconomical {
+		return deadline
+	}
+
+	// If we can't estimate the cost, we'll err on the side of caution and
+	// treat the HTLC as one worth going on-chain for.
+	cost, err := e.onChainCost(htlc, policy.ConfTarget)
+	if err != nil {
+		log.Warnf("Unable to estimate on-chain cost of htlc=%x: %v",
+			htlc.RHash[:], err)
+
+		return deadline
+	}
+
+	deadline.OnChainCost = cost
+	if value < cost {
+		deadline.ExemptReason = HtlcExemptUneconomical
+	}
+
+	return deadline
+}
+
+// onChainCost estimates the cost of claiming the HTLC on-chain. This covers
+// the HTLC output on the commitment transaction along with the second level
+// transaction needed to claim it. The commitment transaction itself isn't
+// accounted for, as it's shared by all HTLCs on the channel.
+func (e *ForceClosePolicyEngine) onChainCost(htlc channeldb.HTLC,
+	confTarget uint32) (btcutil.Amount, error) {
+
+	feeRate, err := e.estimator.EstimateFeePerKW(confTarget)
+	if err != nil {
+		return 0, err
+	}
+
+	weight := int64(input.HTLCWeight + input.HtlcTimeoutWeight)
+	if htlc.Incoming {
+		weight = input.HTLCWeight + input.HtlcSuccessWeight
+	}
+
+	return feeRate.FeeForWeight(weight), nil
+}
@@ -0,0 +1,203 @@
+package contractcourt
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/clock"
+	"github.com/lightningnetwork/lnd/input"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing/route"
+	"github.com/stretchr/testify/require"
+)
+
+// TestForceClosePolicyEvaluate tests that HTLCs are evaluated against the
+// effective policy of their peer.
+func TestForceClosePolicyEvaluate(t *testing.T) {
+	t.Parallel()
+
+	const feeRate = chainfee.SatPerKWeight(10_000)
+	estimator := chainfee.NewStaticEstimator(feeRate, 0)
+
+	engine, err := NewForceClosePolicyEngine(ForceClosePolicy{
+		IncomingBroadcastDelta: 10,
+		OutgoingBroadcastDelta: 5,
+	}, estimator)
+	require.NoError(t, err)
+
+	var (
+		defaultPeer = route.Vertex{1}
+		strictPeer  = route.Vertex{2}
+		cheapPeer   = route.Vertex{3}
+	)
+	engine.SetPeerPolicy(strictPeer, ForceClosePolicy{
+		OutgoingBroadcastDelta: 20,
+		MinHtlcValue:           10_000,
+	})
+	engine.SetPeerPolicy(cheapPeer, ForceClosePolicy{
+		SkipUneconomical: true,
+	})
+
+	timeoutCost := feeRate.FeeForWeight(
+		input.HTLCWeight + input.HtlcTimeoutWeight,
+	)
+
+	testCases := []struct {
+		name          string
+		peer          route.Vertex
+		htlc          channeldb.HTLC
+		expectedDelta uint32
+		expectedCost  btcutil.Amount
+		expectedWhy   HtlcExemptReason
+	}{{
+		name: "default outgoing",
+		peer: defaultPeer,
+		htlc: channeldb.HTLC{
+			Amt:           1_000,
+			RefundTimeout: 100,
+		},
+		expectedDelta: 5,
+	}, {
+		name: "default incoming",
+		peer: defaultPeer,
+		htlc: channeldb.HTLC{
+			Amt:           1_000,
+			RefundTimeout: 100,
+			Incoming:      true,
+		},
+		expectedDelta: 10,
+	}, {
+		name: "peer below min value",
+		peer: strictPeer,
+		htlc: channeldb.HTLC{
+			Amt:           lnwire.NewMSatFromSatoshis(9_999),
+			RefundTimeout: 100,
+		},
+		expectedDelta: 20,
+		expectedWhy:   HtlcExemptBelowMinValue,
+	}, {
+		name: "peer inherits incoming delta",
+		peer: strictPeer,
+		htlc: channeldb.HTLC{
+			Amt:           lnwire.NewMSatFromSatoshis(10_000),
+			RefundTimeout: 100,
+			Incoming:      true,
+		},
+		expectedDelta: 10,
+	}, {
+		name: "uneconomical",
+		peer: cheapPeer,
+		htlc: channeldb.HTLC{
+			Amt: lnwire.NewMSatFromSatoshis(
+				timeoutCost - 1,
+			),
+			RefundTimeout: 100,
+		},
+		expectedDelta: 5,
+		expectedCost:  timeoutCost,
+		expectedWhy:   HtlcExemptUneconomical,
+	}, {
+		name: "economical",
+		peer: cheapPeer,
+		htlc: channeldb.HTLC{
+			Amt:           lnwire.NewMSatFromSatoshis(timeoutCost),
+			RefundTimeout: 100,
+		},
+		expectedDelta: 5,
+		expectedCost:  timeoutCost,
+	}}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			deadline := engine.Evaluate(tc.peer, tc.htlc)
+
+			require.Equal(
+				t, tc.expectedDelta, deadline.BroadcastDelta,
+			)
+			require.Equal(
+				t, tc.htlc.RefundTimeout-tc.expectedDelta,
+				deadline.TriggerHeight,
+			)
+			require.Equal(t, tc.expectedCost, deadline.OnChainCost)
+			require.Equal(t, tc.expectedWhy, deadline.ExemptReason)
+		})
+	}
+
+	// Once the policy of a peer is removed, the default applies again.
+	engine.RemovePeerPolicy(strictPeer)
+	deadline := engine.Evaluate(strictPeer, channeldb.HTLC{
+		RefundTimeout: 100,
+	})
+	require.EqualValues(t, 5, deadline.BroadcastDelta)
+	require.False(t, deadline.Exempt())
+}
+
+// TestCheckCommitChainActionsForceClosePolicy tests that an expiring HTLC
+// that is exempt by the force close policy doesn't trigger going on-chain.
+func TestCheckCommitChainActionsForceClosePolicy(t *testing.T) {
+	t.Parallel()
+
+	engine, err := NewForceClosePolicyEngine(ForceClosePolicy{
+		IncomingBroadcastDelta: 5,
+		OutgoingBroadcastDelta: 5,
+		MinHtlcValue:           10_000,
+	}, chainfee.NewStaticEstimator(chainfee.FeePerKwFloor, 0))
+	require.NoError(t, err)
+
+	chanArb := &ChannelArbitrator{
+		cfg: ChannelArbitratorConfig{
+			ChainArbitratorConfig: ChainArbitratorConfig{
+				IncomingBroadcastDelta: 5,
+				OutgoingBroadcastDelta: 5,
+				IsForwardedHTLC: func(lnwire.ShortChannelID,
+					uint64) bool {
+
+					return true
+				},
+				Clock: clock.NewDefaultClock(),
+			},
+		},
+	}
+
+	const height = 100
+	smallHtlc := channeldb.HTLC{
+		Amt:           lnwire.NewMSatFromSatoshis(9_999),
+		RefundTimeout: height + 5,
+		HtlcIndex:     1,
+	}
+	htlcs := newHtlcSet([]channeldb.HTLC{smallHtlc})
+
+	// Without a policy engine, the HTLC is within its broadcast delta, so
+	// we'll go on-chain to time it out.
+	actions, err := chanArb.checkCommitChainActions(
+		height, chainTrigger, htlcs,
+	)
+	require.NoError(t, err)
+	require.Len(t, actions[HtlcTimeoutAction], 1)
+
+	// With the policy engine in place, the HTLC is below the minimum value
+	// so no action is taken.
+	chanArb.cfg.ForceClosePolicy = engine
+	actions, err = chanArb.checkCommitChainActions(
+		height, chainTrigger, htlcs,
+	)
+	require.NoError(t, err)
+	require.Empty(t, actions)
+
+	// If another HTLC forces us on-chain, the exempt HTLC is timed out
+	// along with it.
+	largeHtlc := smallHtlc
+	largeHtlc.Amt = lnwire.NewMSatFromSatoshis(10_000)
+	largeHtlc.HtlcIndex = 2
+	htlcs = newHtlcSet([]channeldb.HTLC{smallHtlc, largeHtlc})
+
+	actions, err = chanArb.checkCommitChainActions(
+		height, chainTrigger, htlcs,
+	)
+	require.NoError(t, err)
+	require.Len(t, actions[HtlcTimeoutAction], 2)
+}
@@ -0,0 +1,413 @@
+package contractcourt
+
+import (
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/input"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+)
+
+const (
+	// anchorOutputValue is the value of an anchor output.
+	anchorOutputValue = btcutil.Amount(330)
+)
+
+// SimulatedOutput describes how one of our outputs on the local commitment
+// would be resolved if the channel was force closed.
+type SimulatedOutput struct {
+	// OutPoint is the output on the commitment transaction.
+	OutPoint wire.OutPoint
+
+	// Amount is the value of the output.
+	Amount btcutil.Amount
+
+	// ResolverType is the type of the output.
+	ResolverType channeldb.ResolverType
+
+	// Resolver is the name of the resolver the channel arbitrator would
+	// initially launch for the output.
+	Resolver string
+
+	// SecondLevel is true if the output is resolved through a
+	// second-level HTLC transaction.
+	SecondLevel bool
+
+	// CltvExpiry is the absolute expiry height of an HTLC output.
+	CltvExpiry uint32
+
+	// CsvDelay is the relative delay of the output that is swept into
+	// the wallet.
+	CsvDelay uint32
+
+	// MaturityHeight is the estimated height at which the output, or the
+	// output of its second-level transaction, can be swept into the
+	// wallet. It's zero for outputs that aren't swept.
+	MaturityHeight uint32
+
+	// SweepFee is the estimated fee spent on resolving the output,
+	// including the fees of the second-level transaction and the fees
+	// the wallet contributes to CPFP the commitment.
+	SweepFee btcutil.Amount
+
+	// NetAmount is the amount expected to end up in the wallet. It's
+	// negative if resolving the output costs more than it's worth.
+	NetAmount btcutil.Amount
+}
+
+// ForceCloseSimulation is the outcome of a simulated force close of a
+// channel.
+type ForceCloseSimulation struct {
+	// CloseTx is the commitment transaction that would be broadcast,
+	// without the witness of the funding input.
+	CloseTx *wire.MsgTx
+
+	// CommitFee is the fee paid by the commitment transaction.
+	CommitFee btcutil.Amount
+
+	// FeeRate is the fee rate the sweep fees were estimated at.
+	FeeRate chainfee.SatPerKWeight
+
+	// ConfHeight is the height the commitment is assumed to confirm at.
+	ConfHeight uint32
+
+	// Outputs describes the resolution of each of our outputs.
+	Outputs []SimulatedOutput
+
+	// TotalSweepFee is the sum of all sweep fees.
+	TotalSweepFee btcutil.Amount
+
+	// TotalRecovered is the sum of the net amounts of all outputs.
+	TotalRecovered btcutil.Amount
+}
+
+// SimulateForceClose works out what would happen on chain if the channel was
+// force closed at the given height, without signing or broadcasting the
+// commitment. The outputs are resolved the same way the channel arbitrator
+// would, and the sweep fees are estimated at the given fee rate assuming
+// each output is swept on its own. The lookupPreimage closure is used to
+// determine whether incoming HTLCs can be claimed on chain.
+func SimulateForceClose(chanState *channeldb.OpenChannel,
+	signer input.Signer, bestHeight uint32,
+	feeRate chainfee.SatPerKWeight,
+	lookupPreimage func(lntypes.Hash) (lntypes.Preimage, bool)) (
+	*ForceCloseSimulation, error) {
+
+	// Just like a real force close, we refuse to act on a commitment that
+	// we know may be outdated.
+	if chanState.HasChanStatus(channeldb.ChanStatusLocalDataLoss) {
+		return nil, fmt.Errorf("cannot force close channel with "+
+			"state: %v", chanState.ChanStatus())
+	}
+
+	// The resolutions only depend on the txid of the commitment, which
+	// doesn't commit to the witness, so we don't need to sign it.
+	localCommit := chanState.LocalCommitment
+	commitTx := localCommit.CommitTx.Copy()
+
+	summary, err := lnwallet.NewLocalForceCloseSummary(
+		chanState, signer, commitTx, localCommit.CommitHeight,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	sim := &ForceCloseSimulation{
+		CloseTx:    commitTx,
+		CommitFee:  localCommit.CommitFee,
+		FeeRate:    feeRate,
+		ConfHeight: bestHeight + 1,
+	}
+
+	// Our delayed outputs are subject to an additional CLTV lock if we
+	// initiated a leased channel.
+	var leaseExpiry uint32
+	if chanState.ChanType.HasLeaseExpiration() && chanState.IsInitiator {
+		leaseExpiry = chanState.ThawHeight
+	}
+
+	if summary.AnchorResolution != nil {
+		sim.Outputs = append(
+			sim.Outputs, simulateAnchor(
+				summary.AnchorResolution, feeRate,
+			),
+		)
+	}
+
+	if summary.CommitResolution != nil {
+		sim.Outputs = append(
+			sim.Outputs, simulateCommitOutput(
+				commitTx, summary.CommitResolution,
+				sim.ConfHeight, leaseExpiry, feeRate,
+			),
+		)
+	}
+
+	htlcs := make(map[uint32]channeldb.HTLC, len(localCommit.Htlcs))
+	for _, htlc := range localCommit.Htlcs {
+		if htlc.OutputIndex >= 0 {
+			htlcs[uint32(htlc.OutputIndex)] = htlc
+		}
+	}
+
+	htlcResolutions := summary.HtlcResolutions
+	for i := range htlcResolutions.IncomingHTLCs {
+		resolution := &htlcResolutions.IncomingHTLCs[i]
+
+		output, err := simulateIncomingHtlc(
+			commitTx, chanState.ChanType, resolution, htlcs,
+			sim.ConfHeight, leaseExpiry, feeRate, lookupPreimage,
+		)
+		if err != nil {
+			return nil, err
+		}
+		sim.Outputs = append(sim.Outputs, output)
+	}
+
+	for i := range htlcResolutions.OutgoingHTLCs {
+		resolution := &htlcResolutions.OutgoingHTLCs[i]
+
+		output, err := simulateOutgoingHtlc(
+			commitTx, chanState.ChanType, resolution, bestHeight,
+			sim.ConfHeight, leaseExpiry, feeRate,
+		)
+		if err != nil {
+			return nil, err
+		}
+		sim.Outputs = append(sim.Outputs, output)
+	}
+
+	for _, output := range sim.Outputs {
+		sim.TotalSweepFee += output.SweepFee
+		sim.TotalRecovered += output.NetAmount
+	}
+
+	return sim, nil
+}
+
+// simulateAnchor simulates the resolution of our anchor output. The anchor is
+// only swept if the commitment needs to be bumped to reach the target fee
+// rate, in which case the wallet pays for the difference.
+func simulateAnchor(anchor *lnwallet.AnchorResolution,
+	feeRate chainfee.SatPerKWeight) SimulatedOutput {
+
+	output := SimulatedOutput{
+		OutPoint:     anchor.CommitAnchor,
+		Amount:       anchorOutputValue,
+		ResolverType: channeldb.ResolverTypeAnchor,
+		Resolver:     "anchorResolver",
+	}
+
+	var weightEstimate input.TxWeightEstimator
+	weightEstimate.AddWitnessInput(input.AnchorWitnessSize)
+	weightEstimate.AddP2WKHInput()
+	weightEstimate.AddP2TROutput()
+
+	packageWeight := anchor.CommitWeight + int64(weightEstimate.Weight())
+	cpfpFee := feeRate.FeeForWeight(packageWeight) - anchor.CommitFee
+	if cpfpFee <= 0 {
+		return output
+	}
+
+	output.SweepFee = cpfpFee
+	output.NetAmount = anchorOutputValue - cpfpFee
+
+	return output
+}
+
+// simulateCommitOutput simulates the resolution of our delayed output on the
+// commitment.
+func simulateCommitOutput(commitTx *wire.MsgTx,
+	resolution *lnwallet.CommitOutputResolution, confHeight,
+	leaseExpiry uint32, feeRate chainfee.SatPerKWeight) SimulatedOutput {
+
+	amount := btcutil.Amount(
+		commitTx.TxOut[resolution.SelfOutPoint.Index].Value,
+	)
+	sweepFee := delayedSweepFee(leaseExpiry, feeRate)
+
+	return SimulatedOutput{
+		OutPoint:     resolution.SelfOutPoint,
+		Amount:       amount,
+		ResolverType: channeldb.ResolverTypeCommit,
+		Resolver:     "commitSweepResolver",
+		CsvDelay:     resolution.MaturityDelay,
+		MaturityHeight: maturityHeight(
+			confHeight, resolution.MaturityDelay, leaseExpiry,
+		),
+		SweepFee:  sweepFee,
+		NetAmount: amount - sweepFee,
+	}
+}
+
+// simulateIncomingHtlc simulates the resolution of an incoming HTLC. Only
+// HTLCs for which we know the preimage are claimed, all others are expected
+// to be timed out by the remote party.
+func simulateIncomingHtlc(commitTx *wire.MsgTx,
+	chanType channeldb.ChannelType,
+	resolution *lnwallet.IncomingHtlcResolution,
+	htlcs map[uint32]channeldb.HTLC, confHeight, leaseExpiry uint32,
+	feeRate chainfee.SatPerKWeight,
+	lookupPreimage func(lntypes.Hash) (lntypes.Preimage, bool)) (
+	SimulatedOutput, error) {
+
+	commitOutPoint := resolution.ClaimOutpoint
+	if resolution.SignedSuccessTx != nil {
+		successTx := resolution.SignedSuccessTx
+		commitOutPoint = successTx.TxIn[0].PreviousOutPoint
+	}
+
+	htlc, ok := htlcs[commitOutPoint.Index]
+	if !ok {
+		return SimulatedOutput{}, fmt.Errorf("no HTLC found for "+
+			"output %v", commitOutPoint)
+	}
+
+	output := SimulatedOutput{
+		OutPoint:     commitOutPoint,
+		Amount:       htlc.Amt.ToSatoshis(),
+		ResolverType: channeldb.ResolverTypeIncomingHtlc,
+		Resolver:     "htlcIncomingContestResolver",
+		CltvExpiry:   htlc.RefundTimeout,
+	}
+
+	if _, ok := lookupPreimage(htlc.RHash); !ok {
+		return output, nil
+	}
+
+	output.Resolver = "htlcSuccessResolver"
+	simulateSecondLevel(
+		&output, chanType, resolution.SignedSuccessTx,
+		resolution.SignDetails,
+		input.HtlcSuccessWeightConfirmed,
+		resolution.SweepSignDesc.Output.Value, resolution.CsvDelay,
+		confHeight, leaseExpiry, feeRate,
+	)
+
+	return output, nil
+}
+
+// simulateOutgoingHtlc simulates the resolution of an outgoing HTLC, which is
+// timed out once it expires.
+func simulateOutgoingHtlc(commitTx *wire.MsgTx,
+	chanType channeldb.ChannelType,
+	resolution *lnwallet.OutgoingHtlcResolution, bestHeight, confHeight,
+	leaseExpiry uint32, feeRate chainfee.SatPerKWeight) (SimulatedOutput,
+	error) {
+
+	commitOutPoint := resolution.ClaimOutpoint
+	if resolution.SignedTimeoutTx != nil {
+		timeoutTx := resolution.SignedTimeoutTx
+		commitOutPoint = timeoutTx.TxIn[0].PreviousOutPoint
+	}
+
+	if int(commitOutPoint.Index) >= len(commitTx.TxOut) {
+		return SimulatedOutput{}, fmt.Errorf("invalid HTLC output %v",
+			commitOutPoint)
+	}
+
+	output := SimulatedOutput{
+		OutPoint: commitOutPoint,
+		Amount: btcutil.Amount(
+			commitTx.TxOut[commitOutPoint.Index].Value,
+		),
+		ResolverType: channeldb.ResolverTypeOutgoingHtlc,
+		Resolver:     "htlcOutgoingContestResolver",
+		CltvExpiry:   resolution.Expiry,
+	}
+
+	// Once the HTLC has expired, the arbitrator launches the timeout
+	// resolver right away.
+	if bestHeight >= resolution.Expiry {
+		output.Resolver = "htlcTimeoutResolver"
+	}
+
+	// The timeout transaction can't confirm before the HTLC expires.
+	if resolution.Expiry > confHeight {
+		confHeight = resolution.Expiry
+	}
+
+	simulateSecondLevel(
+		&output, chanType, resolution.SignedTimeoutTx,
+		resolution.SignDetails,
+		input.HtlcTimeoutWeightConfirmed,
+		resolution.SweepSignDesc.Output.Value, resolution.CsvDelay,
+		confHeight, leaseExpiry, feeRate,
+	)
+
+	return output, nil
+}
+
+// simulateSecondLevel fills in the fees and maturity of an HTLC that is
+// resolved through a second-level transaction, which is assumed to confirm at
+// the given height.
+func simulateSecondLevel(output *SimulatedOutput,
+	chanType channeldb.ChannelType, secondLevelTx *wire.MsgTx,
+	signDetails *input.SignDetails,
+	secondLevelWeight int64, secondLevelValue int64, csvDelay,
+	confHeight, leaseExpiry uint32, feeRate chainfee.SatPerKWeight) {
+
+	output.SecondLevel = true
+	output.CsvDelay = csvDelay
+	output.MaturityHeight = maturityHeight(
+		confHeight, csvDelay, leaseExpiry,
+	)
+
+	// The fee of a pre-signed second-level transaction was deducted from
+	// the HTLC value at the fee rate of the commitment.
+	if secondLevelTx != nil {
+		output.SweepFee = output.Amount - btcutil.Amount(
+			secondLevelValue,
+		)
+	}
+
+	// Zero-fee second-level transactions need a wallet input and change
+	// output to pay for their fees. Legacy anchor channels also come with
+	// sign details, but their second-level transactions already pay their
+	// fee out of the HTLC value.
+	if signDetails != nil && chanType.ZeroHtlcTxFee() {
+		var weightEstimate input.TxWeightEstimator
+		weightEstimate.AddP2WKHInput()
+		weightEstimate.AddP2TROutput()
+
+		output.SweepFee += feeRate.FeeForWeight(
+			secondLevelWeight + int64(weightEstimate.Weight()),
+		)
+	}
+
+	output.SweepFee += delayedSweepFee(leaseExpiry, feeRate)
+	output.NetAmount = output.Amount - output.SweepFee
+}
+
+// delayedSweepFee returns the fee of a transaction sweeping a single delayed
+// output into the wallet.
+func delayedSweepFee(leaseExpiry uint32,
+	feeRate chainfee.SatPerKWeight) btcutil.Amount {
+
+	witnessSize := input.ToLocalTimeoutWitnessSize
+	if leaseExpiry > 0 {
+		witnessSize += input.LeaseWitnessScriptSizeOverhead
+	}
+
+	var weightEstimate input.TxWeightEstimator
+	weightEstimate.AddWitnessInput(witnessSize)
+	weightEstimate.AddP2TROutput()
+
+	return feeRate.FeeForWeight(int64(weightEstimate.Weight()))
+}
+
+// maturityHeight returns the height at which an output confirmed at the
+// given height can be spent, taking into account its CSV delay and, for
+// leased channels, its CLTV lock.
+func maturityHeight(confHeight, csvDelay, leaseExpiry uint32) uint32 {
+	height := confHeight + csvDelay
+	if leaseExpiry > height {
+		height = leaseExpiry
+	}
+
+	return height
+}
@@ -0,0 +1,211 @@
+package contractcourt
+
+import (
+	"crypto/sha256"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSimulateForceClose tests that a simulated force close reports all of
+// our outputs on the local commitment along with the resolvers that would
+// handle them.
+func TestSimulateForceClose(t *testing.T) {
+	t.Parallel()
+
+	alice, bob, err := lnwallet.CreateTestChannels(
+		t, channeldb.SingleFunderTweaklessBit|
+			channeldb.AnchorOutputsBit|channeldb.ZeroHtlcTxFeeBit,
+	)
+	require.NoError(t, err)
+
+	const (
+		bestHeight = 100
+		htlcAmt    = lnwire.MilliSatoshi(100_000_000)
+		htlcExpiry = 200
+		feeRate    = chainfee.SatPerKWeight(2500)
+	)
+
+	// Add an outgoing HTLC from Alice to Bob, and an incoming one from
+	// Bob to Alice for which Alice knows the preimage.
+	outgoingPreimage := lntypes.Preimage{1}
+	incomingPreimage := lntypes.Preimage{2}
+
+	outgoing := &lnwire.UpdateAddHTLC{
+		PaymentHash: sha256.Sum256(outgoingPreimage[:]),
+		Amount:      htlcAmt,
+		Expiry:      htlcExpiry,
+	}
+	_, err = alice.AddHTLC(outgoing, nil)
+	require.NoError(t, err)
+	_, err = bob.ReceiveHTLC(outgoing)
+	require.NoError(t, err)
+
+	incoming := &lnwire.UpdateAddHTLC{
+		PaymentHash: sha256.Sum256(incomingPreimage[:]),
+		Amount:      htlcAmt,
+		Expiry:      htlcExpiry,
+	}
+	_, err = bob.AddHTLC(incoming, nil)
+	require.NoError(t, err)
+	_, err = alice.ReceiveHTLC(incoming)
+	require.NoError(t, err)
+
+	require.NoError(t, lnwallet.ForceStateTransition(alice, bob))
+
+	lookupPreimage := func(hash lntypes.Hash) (lntypes.Preimage, bool) {
+		if hash == incomingPreimage.Hash() {
+			return incomingPreimage, true
+		}
+
+		return lntypes.Preimage{}, false
+	}
+
+	chanState := alice.State()
+	sim, err := SimulateForceClose(
+		chanState, alice.Signer, bestHeight, feeRate, lookupPreimage,
+	)
+	require.NoError(t, err)
+
+	// The simulated commitment must be the unsigned local commitment.
+	localCommit := chanState.LocalCommitment
+	require.Equal(t, localCommit.CommitTx.TxHash(), sim.CloseTx.TxHash())
+	require.Empty(t, sim.CloseTx.TxIn[0].Witness)
+	require.Equal(t, localCommit.CommitFee, sim.CommitFee)
+	require.EqualValues(t, bestHeight+1, sim.ConfHeight)
+
+	outputs := make(map[string]SimulatedOutput)
+	var total btcutil.Amount
+	for _, output := range sim.Outputs {
+		outputs[output.Resolver] = output
+		total += output.NetAmount
+	}
+	require.Len(t, outputs, 4)
+	require.Equal(t, total, sim.TotalRecovered)
+
+	commit := outputs["commitSweepResolver"]
+	require.Equal(t, channeldb.ResolverTypeCommit, commit.ResolverType)
+	commitOut := sim.CloseTx.TxOut[commit.OutPoint.Index]
+	require.Equal(t, btcutil.Amount(commitOut.Value), commit.Amount)
+	require.EqualValues(
+		t, sim.ConfHeight+uint32(chanState.LocalChanCfg.CsvDelay),
+		commit.MaturityHeight,
+	)
+	require.Positive(t, commit.SweepFee)
+	require.Equal(t, commit.Amount-commit.SweepFee, commit.NetAmount)
+
+	// The outgoing HTLC hasn't expired yet, so it's watched by the
+	// outgoing contest resolver, and its second-level output matures
+	// after the HTLC expired.
+	timeout := outputs["htlcOutgoingContestResolver"]
+	require.Equal(
+		t, channeldb.ResolverTypeOutgoingHtlc, timeout.ResolverType,
+	)
+	require.True(t, timeout.SecondLevel)
+	require.Equal(t, htlcAmt.ToSatoshis(), timeout.Amount)
+	require.EqualValues(t, htlcExpiry, timeout.CltvExpiry)
+	require.EqualValues(
+		t, htlcExpiry+uint32(chanState.LocalChanCfg.CsvDelay),
+		timeout.MaturityHeight,
+	)
+
+	// We know the preimage of the incoming HTLC, so it's claimed with the
+	// success resolver.
+	success := outputs["htlcSuccessResolver"]
+	require.Equal(
+		t, channeldb.ResolverTypeIncomingHtlc, success.ResolverType,
+	)
+	require.True(t, success.SecondLevel)
+	require.Equal(t, htlcAmt.ToSatoshis(), success.Amount)
+	require.Positive(t, success.NetAmount)
+
+	anchor := outputs["anchorResolver"]
+	require.Equal(t, channeldb.ResolverTypeAnchor, anchor.ResolverType)
+
+	// Without the preimage, the incoming HTLC is only watched and isn't
+	// expected to be recovered.
+	sim, err = SimulateForceClose(
+		chanState, alice.Signer, bestHeight, feeRate,
+		func(lntypes.Hash) (lntypes.Preimage, bool) {
+			return lntypes.Preimage{}, false
+		},
+	)
+	require.NoError(t, err)
+
+	for _, output := range sim.Outputs {
+		if output.ResolverType != channeldb.ResolverTypeIncomingHtlc {
+			continue
+		}
+
+		require.Equal(t, "htlcIncomingContestResolver", output.Resolver)
+		require.Zero(t, output.NetAmount)
+	}
+}
+
+// TestSimulateForceCloseLegacyAnchors tests that the second-level fee of a
+// legacy anchor channel, which is paid out of the HTLC value, isn't counted a
+// second time as if it was paid by a wallet input.
+func TestSimulateForceCloseLegacyAnchors(t *testing.T) {
+	t.Parallel()
+
+	chanType := channeldb.SingleFunderTweaklessBit |
+		channeldb.AnchorOutputsBit
+	alice, bob, err := lnwallet.CreateTestChannels(t, chanType)
+	require.NoError(t, err)
+
+	const (
+		bestHeight = 100
+		htlcAmt    = lnwire.MilliSatoshi(100_000_000)
+		feeRate    = chainfee.SatPerKWeight(2500)
+	)
+
+	outgoing := &lnwire.UpdateAddHTLC{
+		PaymentHash: sha256.Sum256([]byte("preimage")),
+		Amount:      htlcAmt,
+		Expiry:      200,
+	}
+	_, err = alice.AddHTLC(outgoing, nil)
+	require.NoError(t, err)
+	_, err = bob.ReceiveHTLC(outgoing)
+	require.NoError(t, err)
+
+	require.NoError(t, lnwallet.ForceStateTransition(alice, bob))
+
+	chanState := alice.State()
+	sim, err := SimulateForceClose(
+		chanState, alice.Signer, bestHeight, feeRate,
+		func(lntypes.Hash) (lntypes.Preimage, bool) {
+			return lntypes.Preimage{}, false
+		},
+	)
+	require.NoError(t, err)
+
+	var timeout *SimulatedOutput
+	for i, output := range sim.Outputs {
+		if output.ResolverType == channeldb.ResolverTypeOutgoingHtlc {
+			timeout = &sim.Outputs[i]
+		}
+	}
+	require.NotNil(t, timeout)
+	require.True(t, timeout.SecondLevel)
+
+	// The sweep fee is made up of the fee of the timeout transaction,
+	// which was deducted from the HTLC at the commitment fee rate, and the
+	// fee of sweeping its delayed output.
+	timeoutFee := lnwallet.HtlcTimeoutFee(
+		chanType, chainfee.SatPerKWeight(
+			chanState.LocalCommitment.FeePerKw,
+		),
+	)
+	require.Positive(t, timeoutFee)
+	require.Equal(
+		t, timeoutFee+delayedSweepFee(0, feeRate), timeout.SweepFee,
+	)
+	require.Equal(t, timeout.Amount-timeout.SweepFee, timeout.NetAmount)
+}
@@ -419,6 +419,7 @@ func (h *htlcIncomingContestResolver) report() *ContractReport {
 		MaturityHeight: h.htlcExpiry,
 		LimboBalance:   finalAmt,
 		Stage:          1,
+		ResolverStage:  ResolverStageWaitingForPreimage,
 	}
 }
 
@@ -169,6 +169,7 @@ func (h *htlcOutgoingContestResolver) report() *ContractReport {
 		MaturityHeight: h.htlcResolution.Expiry,
 		LimboBalance:   finalAmt,
 		Stage:          1,
+		ResolverStage:  ResolverStageWaitingForExpiry,
 	}
 }
 
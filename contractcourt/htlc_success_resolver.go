@@ -155,6 +155,8 @@ func (h *htlcSuccessResolver) Resolve() (ContractResolver, error) {
 	h.reportLock.Lock()
 	h.currentReport.RecoveredBalance = h.currentReport.LimboBalance
 	h.currentReport.LimboBalance = 0
+	h.currentReport.ResolverStage = ResolverStageResolved
+	h.currentReport.sweepOutpoint = nil
 	h.reportLock.Unlock()
 
 	h.resolved = true
@@ -243,6 +245,12 @@ func (h *htlcSuccessResolver) broadcastReSignedSuccessTx() (
 			h.htlcResolution.SignDetails, h.htlcResolution.Preimage,
 			h.broadcastHeight,
 		)
+
+		h.reportLock.Lock()
+		h.currentReport.ResolverStage =
+			ResolverStageWaitingForSecondLevel
+		h.currentReport.sweepOutpoint = secondLevelInput.OutPoint()
+		h.reportLock.Unlock()
 		_, err := h.Sweeper.SweepInput(
 			&secondLevelInput,
 			sweep.Params{
@@ -309,6 +317,8 @@ func (h *htlcSuccessResolver) broadcastReSignedSuccessTx() (
 	h.reportLock.Lock()
 	h.currentReport.Stage = 2
 	h.currentReport.MaturityHeight = waitHeight
+	h.currentReport.ResolverStage = ResolverStageWaitingForCsv
+	h.currentReport.sweepOutpoint = nil
 	h.reportLock.Unlock()
 
 	if h.hasCLTV() {
@@ -348,6 +358,12 @@ func (h *htlcSuccessResolver) broadcastReSignedSuccessTx() (
 		h.htlcResolution.CsvDelay, h.broadcastHeight,
 		h.htlc.RHash,
 	)
+
+	h.reportLock.Lock()
+	h.currentReport.ResolverStage = ResolverStageSweeping
+	h.currentReport.sweepOutpoint = op
+	h.reportLock.Unlock()
+
 	_, err = h.Sweeper.SweepInput(
 		inp,
 		sweep.Params{
@@ -322,6 +322,12 @@ func (h *htlcTimeoutResolver) sweepSecondLevelTx() error {
 		h.htlcResolution.SignDetails,
 		h.broadcastHeight,
 	)
+
+	h.reportLock.Lock()
+	h.currentReport.ResolverStage = ResolverStageWaitingForSecondLevel
+	h.currentReport.sweepOutpoint = inp.OutPoint()
+	h.reportLock.Unlock()
+
 	_, err := h.Sweeper.SweepInput(
 		&inp, sweep.Params{
 			Fee: sweep.FeePreference{
@@ -489,6 +495,8 @@ func (h *htlcTimeoutResolver) handleCommitSpend(
 		h.reportLock.Lock()
 		h.currentReport.Stage = 2
 		h.currentReport.MaturityHeight = waitHeight
+		h.currentReport.ResolverStage = ResolverStageWaitingForCsv
+		h.currentReport.sweepOutpoint = nil
 		h.reportLock.Unlock()
 
 		if h.hasCLTV() {
@@ -524,6 +532,12 @@ func (h *htlcTimeoutResolver) handleCommitSpend(
 			h.htlcResolution.CsvDelay, h.broadcastHeight,
 			h.htlc.RHash,
 		)
+
+		h.reportLock.Lock()
+		h.currentReport.ResolverStage = ResolverStageSweeping
+		h.currentReport.sweepOutpoint = op
+		h.reportLock.Unlock()
+
 		_, err = h.Sweeper.SweepInput(
 			inp,
 			sweep.Params{
@@ -581,6 +595,8 @@ func (h *htlcTimeoutResolver) handleCommitSpend(
 	h.reportLock.Lock()
 	h.currentReport.RecoveredBalance = h.currentReport.LimboBalance
 	h.currentReport.LimboBalance = 0
+	h.currentReport.ResolverStage = ResolverStageResolved
+	h.currentReport.sweepOutpoint = nil
 	h.reportLock.Unlock()
 
 	amt := btcutil.Amount(h.htlcResolution.SweepSignDesc.Output.Value)
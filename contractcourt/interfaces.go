@@ -65,6 +65,10 @@ type UtxoSweeper interface {
 	// original sweeping transaction, if any.
 	UpdateParams(input wire.OutPoint, params sweep.ParamsUpdate) (
 		chan sweep.Result, error)
+
+	// PendingInputs returns the set of inputs that the UtxoSweeper is
+	// currently attempting to sweep.
+	PendingInputs() (map[wire.OutPoint]*sweep.PendingInput, error)
 }
 
 // HtlcNotifier defines the notification functions that contract court requires.
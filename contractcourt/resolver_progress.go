@@ -0,0 +1,227 @@
+package contractcourt
+
+import (
+	"reflect"
+	"time"
+
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/subscribe"
+)
+
+const (
+	// resolverProgressInterval is the interval at which the chain
+	// arbitrator polls its channel arbitrators for changes in the progress
+	// of their contract resolvers.
+	resolverProgressInterval = 10 * time.Second
+)
+
+// ResolverStage describes what a contract resolver is currently waiting for
+// in order to make progress.
+type ResolverStage uint8
+
+const (
+	// ResolverStageUnknown is used for resolvers that don't report their
+	// stage.
+	ResolverStageUnknown ResolverStage = iota
+
+	// ResolverStageWaitingForCommitConf indicates the resolver is waiting
+	// for the commitment transaction to confirm.
+	ResolverStageWaitingForCommitConf
+
+	// ResolverStageWaitingForPreimage indicates the resolver is waiting
+	// for the preimage of an incoming HTLC.
+	ResolverStageWaitingForPreimage
+
+	// ResolverStageWaitingForExpiry indicates the resolver is waiting for
+	// an outgoing HTLC to expire so it can be timed out.
+	ResolverStageWaitingForExpiry
+
+	// ResolverStageWaitingForSecondLevel indicates the resolver is waiting
+	// for a second-level HTLC transaction to confirm.
+	ResolverStageWaitingForSecondLevel
+
+	// ResolverStageWaitingForCsv indicates the resolver is waiting for a
+	// CSV lock to expire before the output can be swept.
+	ResolverStageWaitingForCsv
+
+	// ResolverStageSweeping indicates the output has been offered to the
+	// sweeper and the resolver is waiting for the sweep to confirm.
+	ResolverStageSweeping
+
+	// ResolverStageResolved indicates the output has been fully resolved.
+	ResolverStageResolved
+)
+
+// String returns a human readable version of the resolver stage.
+func (s ResolverStage) String() string {
+	switch s {
+	case ResolverStageUnknown:
+		return "Unknown"
+
+	case ResolverStageWaitingForCommitConf:
+		return "WaitingForCommitConf"
+
+	case ResolverStageWaitingForPreimage:
+		return "WaitingForPreimage"
+
+	case ResolverStageWaitingForExpiry:
+		return "WaitingForExpiry"
+
+	case ResolverStageWaitingForSecondLevel:
+		return "WaitingForSecondLevel"
+
+	case ResolverStageWaitingForCsv:
+		return "WaitingForCsv"
+
+	case ResolverStageSweeping:
+		return "Sweeping"
+
+	case ResolverStageResolved:
+		return "Resolved"
+
+	default:
+		return "<unknown stage>"
+	}
+}
+
+// ResolverProgressUpdate is sent to subscribers of resolver progress whenever
+// the reports of the resolvers of a channel change. An update without reports
+// signals that the channel no longer has any active resolvers.
+type ResolverProgressUpdate struct {
+	// ChanPoint is the funding outpoint of the channel.
+	ChanPoint wire.OutPoint
+
+	// Reports holds the current reports of all the resolvers of the
+	// channel.
+	Reports []*ContractReport
+}
+
+// addSweepProgress fills in the sweep details of all reports that have an
+// output offered to the sweeper.
+func (c *ChannelArbitrator) addSweepProgress(reports []*ContractReport) {
+	sweeping := false
+	for _, report := range reports {
+		if report.sweepOutpoint != nil {
+			sweeping = true
+			break
+		}
+	}
+	if !sweeping || c.cfg.Sweeper == nil {
+		return
+	}
+
+	pendingInputs, err := c.cfg.Sweeper.PendingInputs()
+	if err != nil {
+		log.Warnf("ChannelArbitrator(%v): unable to fetch pending "+
+			"sweeper inputs: %v", c.cfg.ChanPoint, err)
+
+		return
+	}
+
+	for _, report := range reports {
+		if report.sweepOutpoint == nil {
+			continue
+		}
+
+		pendingInput, ok := pendingInputs[*report.sweepOutpoint]
+		if !ok {
+			continue
+		}
+
+		report.SweepTxid = pendingInput.LastSweepTxid
+		report.SweepFee = pendingInput.SweepFee
+		report.SweepAttempts = uint32(pendingInput.BroadcastAttempts)
+	}
+}
+
+// ResolverProgress returns the current reports of the contract resolvers of
+// all channels that are being resolved, keyed by their funding outpoint.
+func (c *ChainArbitrator) ResolverProgress() (
+	map[wire.OutPoint][]*ContractReport) {
+
+	c.Lock()
+	arbitrators := make(
+		map[wire.OutPoint]*ChannelArbitrator, len(c.activeChannels),
+	)
+	for chanPoint, arbitrator := range c.activeChannels {
+		arbitrators[chanPoint] = arbitrator
+	}
+	c.Unlock()
+
+	progress := make(map[wire.OutPoint][]*ContractReport)
+	for chanPoint, arbitrator := range arbitrators {
+		reports := arbitrator.Report()
+		if len(reports) == 0 {
+			continue
+		}
+
+		progress[chanPoint] = reports
+	}
+
+	return progress
+}
+
+// SubscribeResolverProgress returns a client that receives a
+// ResolverProgressUpdate each time the progress of the contract resolvers of
+// a channel changes.
+func (c *ChainArbitrator) SubscribeResolverProgress() (*subscribe.Client,
+	error) {
+
+	return c.progressNtfns.Subscribe()
+}
+
+// watchResolverProgress periodically polls the progress of all contract
+// resolvers and notifies subscribers of the channels whose progress changed.
+//
+// NOTE: This MUST be run as a goroutine.
+func (c *ChainArbitrator) watchResolverProgress() {
+	defer c.wg.Done()
+
+	c.progressTicker.Resume()
+	defer c.progressTicker.Stop()
+
+	lastProgress := make(map[wire.OutPoint][]*ContractReport)
+	for {
+		select {
+		case <-c.progressTicker.Ticks():
+			progress := c.ResolverProgress()
+
+			for chanPoint, reports := range progress {
+				last := lastProgress[chanPoint]
+				if reflect.DeepEqual(last, reports) {
+					continue
+				}
+
+				c.sendResolverProgress(chanPoint, reports)
+			}
+
+			for chanPoint := range lastProgress {
+				if _, ok := progress[chanPoint]; ok {
+					continue
+				}
+
+				c.sendResolverProgress(chanPoint, nil)
+			}
+
+			lastProgress = progress
+
+		case <-c.quit:
+			return
+		}
+	}
+}
+
+// sendResolverProgress notifies subscribers of the new progress of the
+// resolvers of the given channel.
+func (c *ChainArbitrator) sendResolverProgress(chanPoint wire.OutPoint,
+	reports []*ContractReport) {
+
+	err := c.progressNtfns.SendUpdate(&ResolverProgressUpdate{
+		ChanPoint: chanPoint,
+		Reports:   reports,
+	})
+	if err != nil {
+		log.Warnf("Unable to send resolver progress of "+
+			"ChannelPoint(%v): %v", chanPoint, err)
+	}
+}
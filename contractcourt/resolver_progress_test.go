@@ -0,0 +1,73 @@
+package contractcourt
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/sweep"
+	"github.com/stretchr/testify/require"
+)
+
+// TestReportSweepProgress tests that the reports of resolvers that handed an
+// output to the sweeper are supplemented with the state of the sweep.
+func TestReportSweepProgress(t *testing.T) {
+	t.Parallel()
+
+	var (
+		sweepingOutpoint = wire.OutPoint{Index: 1}
+		waitingOutpoint  = wire.OutPoint{Index: 2}
+		sweepTxid        = chainhash.Hash{3}
+	)
+
+	sweeper := newMockSweeper()
+	sweeper.pendingInputs = map[wire.OutPoint]*sweep.PendingInput{
+		sweepingOutpoint: {
+			OutPoint:          sweepingOutpoint,
+			BroadcastAttempts: 2,
+			LastSweepTxid:     &sweepTxid,
+			SweepFee:          500,
+		},
+	}
+
+	sweepingResolver := &commitSweepResolver{
+		currentReport: ContractReport{
+			Outpoint:      sweepingOutpoint,
+			ResolverStage: ResolverStageSweeping,
+			sweepOutpoint: &sweepingOutpoint,
+		},
+	}
+	waitingResolver := &commitSweepResolver{
+		currentReport: ContractReport{
+			Outpoint:      waitingOutpoint,
+			ResolverStage: ResolverStageWaitingForCsv,
+		},
+	}
+
+	chanArb := &ChannelArbitrator{
+		cfg: ChannelArbitratorConfig{
+			ChainArbitratorConfig: ChainArbitratorConfig{
+				Sweeper: sweeper,
+			},
+		},
+		activeResolvers: []ContractResolver{
+			sweepingResolver, waitingResolver,
+		},
+	}
+
+	reports := chanArb.Report()
+	require.Len(t, reports, 2)
+
+	// The report of the output being swept carries the sweep details.
+	require.Equal(t, ResolverStageSweeping, reports[0].ResolverStage)
+	require.Equal(t, &sweepTxid, reports[0].SweepTxid)
+	require.Equal(t, btcutil.Amount(500), reports[0].SweepFee)
+	require.EqualValues(t, 2, reports[0].SweepAttempts)
+
+	// The report of the output that isn't swept yet has none.
+	require.Equal(t, ResolverStageWaitingForCsv, reports[1].ResolverStage)
+	require.Nil(t, reports[1].SweepTxid)
+	require.Zero(t, reports[1].SweepFee)
+	require.Zero(t, reports[1].SweepAttempts)
+}
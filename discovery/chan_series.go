@@ -1,6 +1,7 @@
 package discovery
 
 import (
+	"errors"
 	"time"
 
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
@@ -11,6 +12,20 @@ import (
 	"github.com/lightningnetwork/lnd/routing/route"
 )
 
+// ChanUpdateTimestamp identifies the latest channel update we know of for a
+// particular channel and direction.
+type ChanUpdateTimestamp struct {
+	// ShortChannelID is the channel the update belongs to.
+	ShortChannelID lnwire.ShortChannelID
+
+	// Direction is 0 if the update was sent by the first node of the
+	// channel, and 1 if it was sent by the second one.
+	Direction uint8
+
+	// Timestamp is the timestamp of the update.
+	Timestamp uint32
+}
+
 // ChannelGraphTimeSeries is an interface that provides time and block based
 // querying into our view of the channel graph. New channels will have
 // monotonically increasing block heights, and new channel updates will have
@@ -60,6 +75,12 @@ type ChannelGraphTimeSeries interface {
 	// channel, then an empty slice will be returned.
 	FetchChanUpdates(chain chainhash.Hash,
 		shortChanID lnwire.ShortChannelID) ([]*lnwire.ChannelUpdate, error)
+
+	// ChanUpdateTimestamps returns the timestamps of the latest channel
+	// updates of all the public channels we know of. We'll use these as
+	// the set of elements we reconcile with a remote peer.
+	ChanUpdateTimestamps(chain chainhash.Hash) ([]ChanUpdateTimestamp,
+		error)
 }
 
 // ChanSeries is an implementation of the ChannelGraphTimeSeries
@@ -122,9 +143,8 @@ func (c *ChanSeries) UpdatesInHorizon(chain chainhash.Hash,
 			continue
 		}
 
-		chanAnn, edge1, edge2, err := netann.CreateChanAnnouncement(
-			channel.Info.AuthProof, channel.Info, channel.Policy1,
-			channel.Policy2,
+		chanAnn, edge1, edge2, err := createChanAnnouncement(
+			channel.Info, channel.Policy1, channel.Policy2,
 		)
 		if err != nil {
 			return nil, err
@@ -264,9 +284,8 @@ func (c *ChanSeries) FetchChanAnns(chain chainhash.Hash,
 			continue
 		}
 
-		chanAnn, edge1, edge2, err := netann.CreateChanAnnouncement(
-			channel.Info.AuthProof, channel.Info, channel.Policy1,
-			channel.Policy2,
+		chanAnn, edge1, edge2, err := createChanAnnouncement(
+			channel.Info, channel.Policy1, channel.Policy2,
 		)
 		if err != nil {
 			return nil, err
@@ -348,6 +367,66 @@ func (c *ChanSeries) FetchChanUpdates(chain chainhash.Hash,
 	return chanUpdates, nil
 }
 
+// ChanUpdateTimestamps returns the timestamps of the latest channel updates of
+// all the public channels we know of. We'll use these as the set of elements
+// we reconcile with a remote peer.
+//
+// NOTE: This is part of the ChannelGraphTimeSeries interface.
+func (c *ChanSeries) ChanUpdateTimestamps(
+	chain chainhash.Hash) ([]ChanUpdateTimestamp, error) {
+
+	var timestamps []ChanUpdateTimestamp
+	err := c.graph.ForEachChannel(func(info *channeldb.ChannelEdgeInfo,
+		e1, e2 *channeldb.ChannelEdgePolicy) error {
+
+		// Skip any channels that haven't been fully advertised, as we
+		// wouldn't be able to send them to the remote peer.
+		if info.AuthProof == nil {
+			return nil
+		}
+
+		scid := lnwire.NewShortChanIDFromInt(info.ChannelID)
+		for direction, edge := range []*channeldb.ChannelEdgePolicy{
+			e1, e2,
+		} {
+			if edge == nil {
+				continue
+			}
+
+			timestamps = append(timestamps, ChanUpdateTimestamp{
+				ShortChannelID: scid,
+				Direction:      uint8(direction),
+				Timestamp:      uint32(edge.LastUpdate.Unix()),
+			})
+		}
+
+		return nil
+	})
+	if err != nil && !errors.Is(err, channeldb.ErrGraphNoEdgesFound) {
+		return nil, err
+	}
+
+	return timestamps, nil
+}
+
+// createChanAnnouncement re-creates the announcement of a channel along with
+// its channel updates, using the announcement version the channel was
+// originally announced with.
+func createChanAnnouncement(chanInfo *channeldb.ChannelEdgeInfo,
+	e1, e2 *channeldb.ChannelEdgePolicy) (lnwire.Message,
+	*lnwire.ChannelUpdate, *lnwire.ChannelUpdate, error) {
+
+	if chanInfo.Version == channeldb.ChanAnnVersion2 {
+		return netann.CreateChanAnnouncement2(
+			chanInfo.AuthProof, chanInfo, e1, e2,
+		)
+	}
+
+	return netann.CreateChanAnnouncement(
+		chanInfo.AuthProof, chanInfo, e1, e2,
+	)
+}
+
 // A compile-time assertion to ensure that ChanSeries meets the
 // ChannelGraphTimeSeries interface.
 var _ ChannelGraphTimeSeries = (*ChanSeries)(nil)
@@ -0,0 +1,364 @@
+package discovery
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing/route"
+	"golang.org/x/time/rate"
+)
+
+// ErrGossipBudgetExceeded signals that a gossip message received from a peer
+// was dropped, as it exceeded the inbound bandwidth budget of either the peer
+// or the node as a whole.
+var ErrGossipBudgetExceeded = errors.New("gossip bandwidth budget exceeded")
+
+// GossipBudgetConfig holds the bandwidth budgets of the gossip we exchange
+// with our peers. All budgets are expressed in bytes per second, and a budget
+// of zero disables the corresponding limit.
+type GossipBudgetConfig struct {
+	// PeerOutbound is the budget of the gossip we send to a single peer.
+	PeerOutbound uint64
+
+	// PeerInbound is the budget of the gossip we accept from a single
+	// peer.
+	PeerInbound uint64
+
+	// GlobalOutbound is the budget of the gossip we send to all of our
+	// peers combined.
+	GlobalOutbound uint64
+
+	// GlobalInbound is the budget of the gossip we accept from all of our
+	// peers combined.
+	GlobalInbound uint64
+}
+
+// GossipBudgetUsage describes the gossip exchanged with one or more peers and
+// how it was affected by the bandwidth budgets.
+type GossipBudgetUsage struct {
+	// MsgsSent is the number of gossip messages sent.
+	MsgsSent uint64
+
+	// BytesSent is the number of bytes of gossip sent.
+	BytesSent uint64
+
+	// MsgsReceived is the number of gossip messages received, including
+	// the ones that were dropped.
+	MsgsReceived uint64
+
+	// BytesReceived is the number of bytes of gossip received, including
+	// the ones that were dropped.
+	BytesReceived uint64
+
+	// MsgsDelayed is the number of outbound messages, such as replies to
+	// gossip queries, that had to wait for the outbound budget before
+	// being sent.
+	MsgsDelayed uint64
+
+	// TotalDelay is the total amount of time outbound messages waited for
+	// the outbound budget.
+	TotalDelay time.Duration
+
+	// OutboundDropped is the number of announcements we didn't relay as
+	// they exceeded the outbound budget.
+	OutboundDropped uint64
+
+	// InboundDropped is the number of announcements we ignored as they
+	// exceeded the inbound budget.
+	InboundDropped uint64
+}
+
+// add returns the sum of both usages.
+func (u GossipBudgetUsage) add(other GossipBudgetUsage) GossipBudgetUsage {
+	return GossipBudgetUsage{
+		MsgsSent:        u.MsgsSent + other.MsgsSent,
+		BytesSent:       u.BytesSent + other.BytesSent,
+		MsgsReceived:    u.MsgsReceived + other.MsgsReceived,
+		BytesReceived:   u.BytesReceived + other.BytesReceived,
+		MsgsDelayed:     u.MsgsDelayed + other.MsgsDelayed,
+		TotalDelay:      u.TotalDelay + other.TotalDelay,
+		OutboundDropped: u.OutboundDropped + other.OutboundDropped,
+		InboundDropped:  u.InboundDropped + other.InboundDropped,
+	}
+}
+
+// String returns a human readable summary of the usage.
+func (u GossipBudgetUsage) String() string {
+	return fmt.Sprintf("sent=%v msgs/%v bytes, received=%v msgs/%v "+
+		"bytes, delayed=%v msgs/%v, dropped=%v outbound/%v inbound",
+		u.MsgsSent, u.BytesSent, u.MsgsReceived, u.BytesReceived,
+		u.MsgsDelayed, u.TotalDelay, u.OutboundDropped,
+		u.InboundDropped)
+}
+
+// newBudgetLimiter creates a rate limiter enforcing a budget of the given
+// number of bytes per second. The burst of the limiter always allows for the
+// largest possible message, so that any message can eventually be sent.
+func newBudgetLimiter(bytesPerSec uint64) *rate.Limiter {
+	if bytesPerSec == 0 {
+		return rate.NewLimiter(rate.Inf, 0)
+	}
+
+	burst := bytesPerSec
+	if burst < lnwire.MaxSliceLength {
+		burst = lnwire.MaxSliceLength
+	}
+
+	return rate.NewLimiter(rate.Limit(bytesPerSec), int(burst))
+}
+
+// reserveNow reserves the given number of bytes from all the limiters, only
+// if they're available right away from each of them.
+func reserveNow(now time.Time, size int, limiters ...*rate.Limiter) bool {
+	reservations := make([]*rate.Reservation, 0, len(limiters))
+	for _, limiter := range limiters {
+		r := limiter.ReserveN(now, size)
+		if r.OK() && r.DelayFrom(now) == 0 {
+			reservations = append(reservations, r)
+			continue
+		}
+
+		// Give back the tokens of all the reservations made so far, as
+		// the message won't be sent after all.
+		r.CancelAt(now)
+		for _, reservation := range reservations {
+			reservation.CancelAt(now)
+		}
+
+		return false
+	}
+
+	return true
+}
+
+// gossipBudget enforces the global gossip bandwidth budgets and keeps track of
+// the budgets of the peers we're exchanging gossip with.
+type gossipBudget struct {
+	cfg GossipBudgetConfig
+
+	outbound *rate.Limiter
+	inbound  *rate.Limiter
+
+	mu sync.Mutex
+
+	// peers holds the budgets of the peers we're currently connected to.
+	peers map[route.Vertex]*peerBudget
+
+	// staleUsage is the usage of the peers we've since disconnected from.
+	staleUsage GossipBudgetUsage
+}
+
+// newGossipBudget creates a new gossipBudget enforcing the given budgets.
+func newGossipBudget(cfg GossipBudgetConfig) *gossipBudget {
+	return &gossipBudget{
+		cfg:      cfg,
+		outbound: newBudgetLimiter(cfg.GlobalOutbound),
+		inbound:  newBudgetLimiter(cfg.GlobalInbound),
+		peers:    make(map[route.Vertex]*peerBudget),
+	}
+}
+
+// forPeer returns the budget of the given peer, creating it if this is the
+// first time we exchange gossip with the peer. A nil gossipBudget returns a
+// nil peerBudget, which doesn't enforce any budget.
+func (b *gossipBudget) forPeer(peer route.Vertex) *peerBudget {
+	if b == nil {
+		return nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	budget, ok := b.peers[peer]
+	if !ok {
+		budget = &peerBudget{
+			global:   b,
+			outbound: newBudgetLimiter(b.cfg.PeerOutbound),
+			inbound:  newBudgetLimiter(b.cfg.PeerInbound),
+		}
+		b.peers[peer] = budget
+	}
+
+	return budget
+}
+
+// removePeer removes the budget of a peer we've disconnected from, holding
+// onto its usage so it's still part of the total usage.
+func (b *gossipBudget) removePeer(peer route.Vertex) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	budget, ok := b.peers[peer]
+	if !ok {
+		return
+	}
+
+	b.staleUsage = b.staleUsage.add(budget.usage())
+	delete(b.peers, peer)
+}
+
+// usage returns the total usage of all the peers we've exchanged gossip with.
+func (b *gossipBudget) usage() GossipBudgetUsage {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	total := b.staleUsage
+	for _, budget := range b.peers {
+		total = total.add(budget.usage())
+	}
+
+	return total
+}
+
+// peerBudget enforces the gossip bandwidth budgets of a single peer, along
+// with the global budgets, and tracks the usage of the peer.
+//
+// NOTE: A nil peerBudget doesn't enforce any budget.
+type peerBudget struct {
+	global *gossipBudget
+
+	outbound *rate.Limiter
+	inbound  *rate.Limiter
+
+	mu    sync.Mutex
+	stats GossipBudgetUsage
+}
+
+// waitOutbound blocks until the given messages fit into the outbound budgets
+// of both the peer and the node. This is used for messages that can't be
+// dropped, such as replies to gossip queries of the peer. False is returned if
+// the quit channel was closed while waiting.
+func (p *peerBudget) waitOutbound(quit <-chan struct{},
+	msgs ...lnwire.Message) bool {
+
+	if p == nil {
+		return true
+	}
+
+	// Reserve the bytes of every message up front. As reservations are
+	// served in order, the delay of the last one is the time we need to
+	// wait for all of them.
+	var (
+		now   = time.Now()
+		size  uint64
+		delay time.Duration
+	)
+	for _, msg := range msgs {
+		n := msgSize(msg)
+		size += n
+
+		for _, limiter := range []*rate.Limiter{
+			p.outbound, p.global.outbound,
+		} {
+			r := limiter.ReserveN(now, int(n))
+			if r.OK() && r.DelayFrom(now) > delay {
+				delay = r.DelayFrom(now)
+			}
+		}
+	}
+
+	p.mu.Lock()
+	p.stats.MsgsSent += uint64(len(msgs))
+	p.stats.BytesSent += size
+	if delay > 0 {
+		p.stats.MsgsDelayed += uint64(len(msgs))
+		p.stats.TotalDelay += delay
+	}
+	p.mu.Unlock()
+
+	if delay == 0 {
+		return true
+	}
+
+	log.Tracef("Delaying %v gossip messages by %v to stay within "+
+		"outbound budget", len(msgs), delay)
+
+	select {
+	case <-time.After(delay):
+		return true
+	case <-quit:
+		return false
+	}
+}
+
+// allowOutbound returns true if the given message, which is an announcement
+// we're relaying to the peer, fits into the outbound budgets of both the peer
+// and the node right away. Announcements that don't fit are dropped.
+func (p *peerBudget) allowOutbound(msg lnwire.Message) bool {
+	if p == nil {
+		return true
+	}
+
+	size := msgSize(msg)
+	allowed := reserveNow(
+		time.Now(), int(size), p.outbound, p.global.outbound,
+	)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !allowed {
+		p.stats.OutboundDropped++
+		return false
+	}
+
+	p.stats.MsgsSent++
+	p.stats.BytesSent += size
+
+	return true
+}
+
+// allowInbound accounts for a gossip message received from the peer. If the
+// message is an unsolicited announcement, true is only returned if it fits
+// into the inbound budgets of both the peer and the node, otherwise it should
+// be dropped. Any other message, such as gossip queries, their replies and the
+// announcements we solicited with them, is always allowed.
+func (p *peerBudget) allowInbound(msg lnwire.Message, solicited bool) bool {
+	if p == nil {
+		return true
+	}
+
+	size := msgSize(msg)
+
+	allowed := true
+	switch msg.(type) {
+	case *lnwire.ChannelAnnouncement,
+		*lnwire.ChannelAnnouncement2,
+		*lnwire.ChannelUpdate,
+		*lnwire.NodeAnnouncement:
+
+		if solicited {
+			break
+		}
+
+		allowed = reserveNow(
+			time.Now(), int(size), p.inbound, p.global.inbound,
+		)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.stats.MsgsReceived++
+	p.stats.BytesReceived += size
+	if !allowed {
+		p.stats.InboundDropped++
+	}
+
+	return allowed
+}
+
+// usage returns the gossip exchanged with the peer so far.
+func (p *peerBudget) usage() GossipBudgetUsage {
+	if p == nil {
+		return GossipBudgetUsage{}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.stats
+}
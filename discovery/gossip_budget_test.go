@@ -0,0 +1,92 @@
+package discovery
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing/route"
+	"github.com/stretchr/testify/require"
+)
+
+// TestGossipBudget asserts that announcements exceeding either the budget of a
+// peer or the global budget are dropped, while replies to gossip queries are
+// delayed instead.
+func TestGossipBudget(t *testing.T) {
+	t.Parallel()
+
+	update := &lnwire.ChannelUpdate{
+		ShortChannelID:  lnwire.NewShortChanIDFromInt(1),
+		ExtraOpaqueData: make([]byte, 0),
+	}
+	updateSize := msgSize(update)
+
+	query := &lnwire.QueryChannelRange{
+		ExtraData: make([]byte, 0),
+	}
+
+	// The burst of each budget allows for the largest possible message, so
+	// we'll use budgets below it to make sure the burst is exhausted by a
+	// known number of updates.
+	numUpdates := lnwire.MaxSliceLength / updateSize
+	budget := newGossipBudget(GossipBudgetConfig{
+		PeerInbound:    1,
+		GlobalOutbound: 1,
+	})
+
+	peer1 := budget.forPeer(route.Vertex{1})
+	peer2 := budget.forPeer(route.Vertex{2})
+	require.Same(t, peer1, budget.forPeer(route.Vertex{1}))
+
+	// Once a peer used up its inbound budget, its announcements are
+	// dropped, while the ones of other peers are still accepted.
+	for i := uint64(0); i < numUpdates; i++ {
+		require.True(t, peer1.allowInbound(update, false))
+	}
+	require.False(t, peer1.allowInbound(update, false))
+	require.True(t, peer2.allowInbound(update, false))
+
+	// Gossip queries and the announcements we solicited with our own
+	// queries are never dropped.
+	require.True(t, peer1.allowInbound(query, false))
+	require.True(t, peer1.allowInbound(update, true))
+
+	usage := peer1.usage()
+	require.Equal(t, numUpdates+3, usage.MsgsReceived)
+	require.Equal(t, uint64(1), usage.InboundDropped)
+
+	// The global outbound budget is shared by all peers, so once it's used
+	// up, announcements aren't relayed to any peer.
+	for i := uint64(0); i < numUpdates; i++ {
+		require.True(t, peer1.allowOutbound(update))
+	}
+	require.False(t, peer1.allowOutbound(update))
+	require.False(t, peer2.allowOutbound(update))
+	require.Equal(t, uint64(1), peer2.usage().OutboundDropped)
+
+	// Replies are delayed until they fit into the budget, unless we're
+	// shutting down.
+	quit := make(chan struct{})
+	close(quit)
+	require.False(t, peer2.waitOutbound(quit, update))
+
+	usage = peer2.usage()
+	require.Equal(t, uint64(1), usage.MsgsDelayed)
+	require.Greater(t, usage.TotalDelay, time.Duration(0))
+
+	// The usage of a peer we've disconnected from is still part of the
+	// total usage.
+	total := budget.usage()
+	budget.removePeer(route.Vertex{1})
+	require.Equal(t, total, budget.usage())
+	require.NotSame(t, peer1, budget.forPeer(route.Vertex{1}))
+
+	// A nil budget doesn't enforce anything.
+	var noBudget *gossipBudget
+	require.True(t, noBudget.forPeer(route.Vertex{1}).allowInbound(
+		update, false,
+	))
+	require.True(t, noBudget.forPeer(route.Vertex{1}).waitOutbound(
+		quit, update,
+	))
+}
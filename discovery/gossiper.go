@@ -305,6 +305,11 @@ type Config struct {
 	// direction.
 	ChannelUpdateInterval time.Duration
 
+	// GossipBudget holds the bandwidth budgets of the gossip we exchange
+	// with our peers, both for replies to gossip queries and announcements
+	// relayed to or received from them.
+	GossipBudget GossipBudgetConfig
+
 	// IsAlias returns true if a given ShortChannelID is an alias for
 	// option_scid_alias channels.
 	IsAlias func(scid lnwire.ShortChannelID) bool
@@ -329,6 +334,11 @@ type Config struct {
 	// to without iterating over the entire set of open channels.
 	FindChannel func(node *btcec.PublicKey, chanID lnwire.ChannelID) (
 		*channeldb.OpenChannel, error)
+
+	// ReportInvalidAnn is called when a peer sends us an announcement
+	// with an invalid signature, which a well behaved peer never relays.
+	// It may be nil.
+	ReportInvalidAnn func(route.Vertex, error)
 }
 
 // processedNetworkMsg is a wrapper around networkMsg and a boolean. It is
@@ -512,6 +522,7 @@ func New(cfg Config, selfKeyDesc *keychain.KeyDescriptor) *AuthenticatedGossiper
 		IgnoreHistoricalFilters: cfg.IgnoreHistoricalFilters,
 		BestHeight:              gossiper.latestHeight,
 		PinnedSyncers:           cfg.PinnedSyncers,
+		Budget:                  cfg.GossipBudget,
 	})
 
 	gossiper.reliableSender = newReliableSender(&reliableSenderCfg{
@@ -519,6 +530,7 @@ func New(cfg Config, selfKeyDesc *keychain.KeyDescriptor) *AuthenticatedGossiper
 		NotifyWhenOffline: cfg.NotifyWhenOffline,
 		MessageStore:      cfg.MessageStore,
 		IsMsgStale:        gossiper.isMsgStale,
+		Budget:            gossiper.syncMgr.budget,
 	})
 
 	return gossiper
@@ -763,6 +775,26 @@ func (d *AuthenticatedGossiper) ProcessRemoteAnnouncement(msg lnwire.Message,
 
 	errChan := make(chan error, 1)
 
+	// Account for the message in the inbound budget of the peer. Any
+	// announcements exceeding it are dropped, as the peer flooding us
+	// shouldn't be able to exhaust our resources. Announcements the peer
+	// sends in reply to our own queries were solicited by us, so they
+	// aren't subject to the budget.
+	var solicited bool
+	syncer, ok := d.syncMgr.GossipSyncer(peer.PubKey())
+	if ok {
+		solicited = syncer.awaitingReplies()
+	}
+
+	budget := d.syncMgr.budget.forPeer(peer.PubKey())
+	if !budget.allowInbound(msg, solicited) {
+		log.Debugf("Dropping %v from peer=%x exceeding inbound gossip "+
+			"budget", msg.MsgType(), peer.PubKey())
+
+		errChan <- ErrGossipBudgetExceeded
+		return errChan
+	}
+
 	// For messages in the known set of channel series queries, we'll
 	// dispatch the message directly to the GossipSyncer, and skip the main
 	// processing loop.
@@ -770,7 +802,9 @@ func (d *AuthenticatedGossiper) ProcessRemoteAnnouncement(msg lnwire.Message,
 	case *lnwire.QueryShortChanIDs,
 		*lnwire.QueryChannelRange,
 		*lnwire.ReplyChannelRange,
-		*lnwire.ReplyShortChanIDsEnd:
+		*lnwire.ReplyShortChanIDsEnd,
+		*lnwire.ReconcileSketch,
+		*lnwire.ReconcileDiff:
 
 		syncer, ok := d.syncMgr.GossipSyncer(peer.PubKey())
 		if !ok {
@@ -828,6 +862,19 @@ func (d *AuthenticatedGossiper) ProcessRemoteAnnouncement(msg lnwire.Message,
 			errChan <- ownErr
 			return errChan
 		}
+
+	case *lnwire.ChannelAnnouncement2:
+		ownKey := d.selfKey.SerializeCompressed()
+		ownErr := fmt.Errorf("ignoring remote ChannelAnnouncement2 " +
+			"for own channel")
+
+		if bytes.Equal(m.NodeID1[:], ownKey) ||
+			bytes.Equal(m.NodeID2[:], ownKey) {
+
+			log.Warn(ownErr)
+			errChan <- ownErr
+			return errChan
+		}
 	}
 
 	nMsg := &networkMsg{
@@ -978,27 +1025,12 @@ func (d *deDupedAnnouncements) addMsg(message networkMsg) {
 	switch msg := message.msg.(type) {
 
 	// Channel announcements are identified by the short channel id field.
+	// Both versions of the announcement share the same key space.
 	case *lnwire.ChannelAnnouncement:
-		deDupKey := msg.ShortChannelID
-		sender := route.NewVertex(message.source)
-
-		mws, ok := d.channelAnnouncements[deDupKey]
-		if !ok {
-			mws = msgWithSenders{
-				msg:     msg,
-				isLocal: !message.isRemote,
-				senders: make(map[route.Vertex]struct{}),
-			}
-			mws.senders[sender] = struct{}{}
-
-			d.channelAnnouncements[deDupKey] = mws
-
-			return
-		}
+		d.addChanAnnouncement(message, msg.ShortChannelID)
 
-		mws.msg = msg
-		mws.senders[sender] = struct{}{}
-		d.channelAnnouncements[deDupKey] = mws
+	case *lnwire.ChannelAnnouncement2:
+		d.addChanAnnouncement(message, msg.ShortChannelID)
 
 	// Channel updates are identified by the (short channel id,
 	// channelflags) tuple.
@@ -1093,6 +1125,32 @@ func (d *deDupedAnnouncements) addMsg(message networkMsg) {
 	}
 }
 
+// addChanAnnouncement adds a channel announcement of either version to the
+// batch, replacing any announcement of the same channel.
+func (d *deDupedAnnouncements) addChanAnnouncement(message networkMsg,
+	deDupKey lnwire.ShortChannelID) {
+
+	sender := route.NewVertex(message.source)
+
+	mws, ok := d.channelAnnouncements[deDupKey]
+	if !ok {
+		mws = msgWithSenders{
+			msg:     message.msg,
+			isLocal: !message.isRemote,
+			senders: make(map[route.Vertex]struct{}),
+		}
+		mws.senders[sender] = struct{}{}
+
+		d.channelAnnouncements[deDupKey] = mws
+
+		return
+	}
+
+	mws.msg = message.msg
+	mws.senders[sender] = struct{}{}
+	d.channelAnnouncements[deDupKey] = mws
+}
+
 // AddMsgs is a helper method to add multiple messages to the announcement
 // batch.
 func (d *deDupedAnnouncements) AddMsgs(msgs ...networkMsg) {
@@ -1555,6 +1613,9 @@ func (d *AuthenticatedGossiper) isRecentlyRejectedMsg(msg lnwire.Message,
 	case *lnwire.ChannelAnnouncement:
 		scid = m.ShortChannelID.ToUint64()
 
+	case *lnwire.ChannelAnnouncement2:
+		scid = m.ShortChannelID.ToUint64()
+
 	default:
 		return false
 	}
@@ -1998,6 +2059,11 @@ func (d *AuthenticatedGossiper) processNetworkAnnouncement(
 	case *lnwire.ChannelAnnouncement:
 		return d.handleChanAnnouncement(nMsg, msg, schedulerOp)
 
+	// A new taproot channel announcement has arrived, which is handled just
+	// like a regular one, apart from the validation of its proof.
+	case *lnwire.ChannelAnnouncement2:
+		return d.handleChanAnnouncement2(nMsg, msg, schedulerOp)
+
 	// A new authenticated channel edge update has arrived. This indicates
 	// that the directional information for an already known channel has
 	// been updated.
@@ -2287,6 +2353,29 @@ func (d *AuthenticatedGossiper) latestHeight() uint32 {
 	return d.bestHeight
 }
 
+// reportInvalidAnnouncement reports the peer that sent us an invalid
+// announcement, but only if the peer is one of the given authors of the
+// announcement. Peers relay the announcements of other nodes, so an invalid
+// announcement authored by someone else doesn't mean that the peer that
+// relayed it misbehaves.
+func (d *AuthenticatedGossiper) reportInvalidAnnouncement(nMsg *networkMsg,
+	err error, authors ...[33]byte) {
+
+	if !nMsg.isRemote || nMsg.source == nil ||
+		d.cfg.ReportInvalidAnn == nil {
+
+		return
+	}
+
+	source := route.NewVertex(nMsg.source)
+	for _, author := range authors {
+		if author == source {
+			d.cfg.ReportInvalidAnn(source, err)
+			return
+		}
+	}
+}
+
 // handleNodeAnnouncement processes a new node announcement.
 func (d *AuthenticatedGossiper) handleNodeAnnouncement(nMsg *networkMsg,
 	nodeAnn *lnwire.NodeAnnouncement,
@@ -2431,6 +2520,9 @@ func (d *AuthenticatedGossiper) handleChanAnnouncement(nMsg *networkMsg,
 		if err := routing.ValidateChannelAnn(ann); err != nil {
 			err := fmt.Errorf("unable to validate announcement: "+
 				"%v", err)
+			d.reportInvalidAnnouncement(
+				nMsg, err, ann.NodeID1, ann.NodeID2,
+			)
 
 			key := newRejectCacheKey(
 				ann.ShortChannelID.ToUint64(),
@@ -2558,7 +2650,178 @@ func (d *AuthenticatedGossiper) handleChanAnnouncement(nMsg *networkMsg,
 
 	// If we earlier received any ChannelUpdates for this channel, we can
 	// now process them, as the channel is added to the graph.
-	shortChanID := ann.ShortChannelID.ToUint64()
+	d.reprocessPrematureUpdates(ann.ShortChannelID.ToUint64())
+
+	// Channel announcement was successfully processed and now it might be
+	// broadcast to other connected nodes if it was an announcement with
+	// proof (remote).
+	var announcements []networkMsg
+
+	if proof != nil {
+		announcements = append(announcements, networkMsg{
+			peer:     nMsg.peer,
+			isRemote: nMsg.isRemote,
+			source:   nMsg.source,
+			msg:      ann,
+		})
+	}
+
+	nMsg.err <- nil
+
+	log.Debugf("Processed ChannelAnnouncement: peer=%v, short_chan_id=%v",
+		nMsg.peer, ann.ShortChannelID.ToUint64())
+
+	return announcements, true
+}
+
+// handleChanAnnouncement2 processes a new taproot channel announcement.
+func (d *AuthenticatedGossiper) handleChanAnnouncement2(nMsg *networkMsg,
+	ann *lnwire.ChannelAnnouncement2,
+	ops []batch.SchedulerOption) ([]networkMsg, bool) {
+
+	scid := ann.ShortChannelID.ToUint64()
+
+	log.Debugf("Processing ChannelAnnouncement2: peer=%v, "+
+		"short_chan_id=%v", nMsg.peer, scid)
+
+	// rejectAnn adds the announcement to the reject cache and returns the
+	// error to the caller.
+	rejectAnn := func(err error) ([]networkMsg, bool) {
+		key := newRejectCacheKey(scid, sourceToPub(nMsg.source))
+		_, _ = d.recentRejects.Put(key, &cachedReject{})
+
+		nMsg.err <- err
+		return nil, false
+	}
+
+	// We never create taproot announcements for our own channels, so we
+	// only expect to receive them from remote peers.
+	if !nMsg.isRemote {
+		err := errors.New("local ChannelAnnouncement2 not supported")
+		nMsg.err <- err
+		return nil, false
+	}
+
+	// We'll ignore any channel announcements that target any chain other
+	// than the set of chains we know of.
+	if !bytes.Equal(ann.ChainHash[:], d.cfg.ChainHash[:]) {
+		err := fmt.Errorf("ignoring ChannelAnnouncement2 from "+
+			"chain=%v, gossiper on chain=%v", ann.ChainHash,
+			d.cfg.ChainHash)
+		log.Errorf(err.Error())
+
+		return rejectAnn(err)
+	}
+
+	// Since the router accepts alias SCIDs, not erroring out would be a
+	// DoS vector.
+	if d.cfg.IsAlias(ann.ShortChannelID) {
+		err := fmt.Errorf("ignoring remote alias channel=%v",
+			ann.ShortChannelID)
+		log.Errorf(err.Error())
+
+		return rejectAnn(err)
+	}
+
+	// If the advertised inclusionary block is beyond our knowledge of the
+	// chain tip, then we'll ignore it for now.
+	d.Lock()
+	if d.isPremature(ann.ShortChannelID, 0, nMsg) {
+		log.Warnf("Announcement for chan_id=(%v), is premature: "+
+			"advertises height %v, only height %v is known",
+			scid, ann.ShortChannelID.BlockHeight, d.bestHeight)
+		d.Unlock()
+		nMsg.err <- nil
+		return nil, false
+	}
+	d.Unlock()
+
+	// At this point, we'll now ask the router if this is a zombie/known
+	// edge. If so we can skip all the processing below.
+	if d.cfg.Router.IsKnownEdge(ann.ShortChannelID) {
+		nMsg.err <- nil
+		return nil, true
+	}
+
+	if err := routing.ValidateChannelAnn2(ann); err != nil {
+		err := fmt.Errorf("unable to validate announcement: %v", err)
+		log.Error(err)
+		d.reportInvalidAnnouncement(
+			nMsg, err, ann.NodeID1, ann.NodeID2,
+		)
+
+		return rejectAnn(err)
+	}
+
+	var featureBuf bytes.Buffer
+	if err := ann.Features.Encode(&featureBuf); err != nil {
+		log.Errorf("unable to encode features: %v", err)
+		nMsg.err <- err
+		return nil, false
+	}
+
+	edge := &channeldb.ChannelEdgeInfo{
+		ChannelID:     scid,
+		ChainHash:     ann.ChainHash,
+		NodeKey1Bytes: ann.NodeID1,
+		NodeKey2Bytes: ann.NodeID2,
+		Features:      featureBuf.Bytes(),
+		Version:       channeldb.ChanAnnVersion2,
+		AuthProof: &channeldb.ChannelAuthProof{
+			SchnorrSigBytes: ann.Signature[:],
+		},
+		Capacity:        ann.Capacity,
+		MerkleRootHash:  ann.MerkleRootHash,
+		ExtraOpaqueData: ann.ExtraOpaqueData,
+	}
+	if ann.BitcoinKey1 != nil && ann.BitcoinKey2 != nil {
+		edge.BitcoinKey1Bytes = *ann.BitcoinKey1
+		edge.BitcoinKey2Bytes = *ann.BitcoinKey2
+	}
+
+	log.Debugf("Adding taproot edge for short_chan_id: %v", scid)
+
+	// Before we add the edge to the database, we obtain the mutex for this
+	// channel ID, just like for regular channel announcements.
+	d.channelMtx.Lock(scid)
+	err := d.cfg.Router.AddEdge(edge, ops...)
+	d.channelMtx.Unlock(scid)
+	if err != nil {
+		log.Debugf("Router rejected edge for short_chan_id(%v): %v",
+			scid, err)
+
+		// Unlike regular announcements, a taproot announcement always
+		// carries its full proof, so there's nothing more to extract
+		// from an edge we already know about.
+		if routing.IsError(err, routing.ErrIgnored) {
+			nMsg.err <- nil
+			return nil, true
+		}
+
+		return rejectAnn(err)
+	}
+
+	// If we earlier received any ChannelUpdates for this channel, we can
+	// now process them, as the channel is added to the graph.
+	d.reprocessPrematureUpdates(scid)
+
+	nMsg.err <- nil
+
+	log.Debugf("Processed ChannelAnnouncement2: peer=%v, "+
+		"short_chan_id=%v", nMsg.peer, scid)
+
+	return []networkMsg{{
+		peer:     nMsg.peer,
+		isRemote: nMsg.isRemote,
+		source:   nMsg.source,
+		msg:      ann,
+	}}, true
+}
+
+// reprocessPrematureUpdates sends any ChannelUpdates that were received for
+// the given channel before it was added to the graph back into the gossiper
+// to be processed.
+func (d *AuthenticatedGossiper) reprocessPrematureUpdates(shortChanID uint64) {
 	var channelUpdates []*processedNetworkMsg
 
 	earlyChanUpdates, err := d.prematureChannelUpdates.Get(shortChanID)
@@ -2611,27 +2874,6 @@ func (d *AuthenticatedGossiper) handleChanAnnouncement(nMsg *networkMsg,
 			}
 		}(cu.msg)
 	}
-
-	// Channel announcement was successfully processed and now it might be
-	// broadcast to other connected nodes if it was an announcement with
-	// proof (remote).
-	var announcements []networkMsg
-
-	if proof != nil {
-		announcements = append(announcements, networkMsg{
-			peer:     nMsg.peer,
-			isRemote: nMsg.isRemote,
-			source:   nMsg.source,
-			msg:      ann,
-		})
-	}
-
-	nMsg.err <- nil
-
-	log.Debugf("Processed ChannelAnnouncement: peer=%v, short_chan_id=%v",
-		nMsg.peer, ann.ShortChannelID.ToUint64())
-
-	return announcements, true
 }
 
 // handleChanUpdate processes a new channel update.
@@ -2840,6 +3082,9 @@ func (d *AuthenticatedGossiper) handleChanUpdate(nMsg *networkMsg,
 			spew.Sdump(upd.ShortChannelID), err)
 
 		log.Error(rErr)
+		d.reportInvalidAnnouncement(
+			nMsg, rErr, route.NewVertex(pubKey),
+		)
 		nMsg.err <- rErr
 		return nil, false
 	}
@@ -15,6 +15,7 @@ import (
 
 	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr/musig2"
 	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/wire"
@@ -698,6 +699,84 @@ func createChannelAnnouncement(blockHeight uint32, key1, key2 *btcec.PrivateKey,
 	return a, nil
 }
 
+// createRemoteChannelAnnouncement2 creates a taproot channel announcement of
+// the remote nodes, signed by the MuSig2 aggregate of their node and bitcoin
+// keys.
+func createRemoteChannelAnnouncement2(
+	blockHeight uint32) (*lnwire.ChannelAnnouncement2, error) {
+
+	var bitcoinKey1, bitcoinKey2 [33]byte
+	copy(bitcoinKey1[:], bitcoinKeyPub1.SerializeCompressed())
+	copy(bitcoinKey2[:], bitcoinKeyPub2.SerializeCompressed())
+
+	a := &lnwire.ChannelAnnouncement2{
+		ShortChannelID: lnwire.ShortChannelID{
+			BlockHeight: blockHeight,
+		},
+		Features:        testFeatures,
+		Capacity:        100000,
+		BitcoinKey1:     &bitcoinKey1,
+		BitcoinKey2:     &bitcoinKey2,
+		ExtraOpaqueData: make([]byte, 0),
+	}
+	copy(a.NodeID1[:], remoteKeyPub1.SerializeCompressed())
+	copy(a.NodeID2[:], remoteKeyPriv2.PubKey().SerializeCompressed())
+
+	digest, err := a.DigestToSign()
+	if err != nil {
+		return nil, err
+	}
+
+	privKeys := []*btcec.PrivateKey{
+		remoteKeyPriv1, remoteKeyPriv2, bitcoinKeyPriv1,
+		bitcoinKeyPriv2,
+	}
+	pubKeys := make([]*btcec.PublicKey, 0, len(privKeys))
+	for _, privKey := range privKeys {
+		pubKeys = append(pubKeys, privKey.PubKey())
+	}
+
+	nonces := make([]*musig2.Nonces, 0, len(privKeys))
+	pubNonces := make([][musig2.PubNonceSize]byte, 0, len(privKeys))
+	for _, pubKey := range pubKeys {
+		nonce, err := musig2.GenNonces(musig2.WithPublicKey(pubKey))
+		if err != nil {
+			return nil, err
+		}
+
+		nonces = append(nonces, nonce)
+		pubNonces = append(pubNonces, nonce.PubNonce)
+	}
+
+	combinedNonce, err := musig2.AggregateNonces(pubNonces)
+	if err != nil {
+		return nil, err
+	}
+
+	partialSigs := make([]*musig2.PartialSignature, 0, len(privKeys))
+	for i, privKey := range privKeys {
+		partialSig, err := musig2.Sign(
+			nonces[i].SecNonce, privKey, combinedNonce, pubKeys,
+			*digest,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		partialSigs = append(partialSigs, partialSig)
+	}
+
+	sig := musig2.CombineSigs(partialSigs[0].R, partialSigs)
+	a.Signature, err = lnwire.NewSigFromSchnorrRawSignature(
+		sig.Serialize(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
+
 func mockFindChannel(node *btcec.PublicKey, chanID lnwire.ChannelID) (
 	*channeldb.OpenChannel, error) {
 
@@ -946,6 +1025,66 @@ func TestProcessAnnouncement(t *testing.T) {
 
 // TestPrematureAnnouncement checks that premature announcements are not
 // propagated to the router subsystem.
+// TestProcessChannelAnnouncement2 checks that a valid remote taproot channel
+// announcement is added to the graph and broadcast, while a tampered one is
+// rejected.
+func TestProcessChannelAnnouncement2(t *testing.T) {
+	t.Parallel()
+
+	ctx, err := createTestCtx(t, 1)
+	require.NoError(t, err, "can't create context")
+
+	nodePeer := &mockPeer{remoteKeyPriv1.PubKey(), nil, nil}
+
+	// An announcement whose signature doesn't cover its contents must be
+	// rejected.
+	invalidAnn, err := createRemoteChannelAnnouncement2(0)
+	require.NoError(t, err, "can't create channel announcement")
+	invalidAnn.Capacity++
+
+	select {
+	case err = <-ctx.gossiper.ProcessRemoteAnnouncement(
+		invalidAnn, nodePeer,
+	):
+	case <-time.After(2 * time.Second):
+		t.Fatal("remote announcement not processed")
+	}
+	require.ErrorContains(t, err, "unable to validate announcement")
+
+	// A valid one is added to the graph and broadcast.
+	ca, err := createRemoteChannelAnnouncement2(1)
+	require.NoError(t, err, "can't create channel announcement")
+
+	select {
+	case err = <-ctx.gossiper.ProcessRemoteAnnouncement(ca, nodePeer):
+	case <-time.After(2 * time.Second):
+		t.Fatal("remote announcement not processed")
+	}
+	require.NoError(t, err, "can't process remote announcement")
+
+	select {
+	case msg := <-ctx.broadcastedMessage:
+		require.Equal(t, ca, msg.msg)
+	case <-time.After(2 * trickleDelay):
+		t.Fatal("announcement wasn't broadcast")
+	}
+
+	ctx.router.mu.Lock()
+	info, ok := ctx.router.infos[ca.ShortChannelID.ToUint64()]
+	ctx.router.mu.Unlock()
+	require.True(t, ok, "edge wasn't added to router")
+	require.Equal(t, channeldb.ChanAnnVersion2, info.Version)
+	require.Equal(t, ca.Signature[:], info.AuthProof.SchnorrSigBytes)
+	require.Equal(t, *ca.BitcoinKey1, info.BitcoinKey1Bytes)
+
+	// The announcement can be re-created from the stored edge.
+	chanAnn, _, _, err := netann.CreateChanAnnouncement2(
+		info.AuthProof, &info, nil, nil,
+	)
+	require.NoError(t, err)
+	require.NoError(t, routing.ValidateChannelAnn2(chanAnn))
+}
+
 func TestPrematureAnnouncement(t *testing.T) {
 	t.Parallel()
 
@@ -4109,6 +4248,86 @@ func TestRejectCacheChannelAnn(t *testing.T) {
 	}
 }
 
+// TestReportInvalidAnn asserts that peers sending us announcements with
+// invalid signatures that they authored are reported, while peers that merely
+// relay them aren't.
+func TestReportInvalidAnn(t *testing.T) {
+	t.Parallel()
+
+	ctx, err := createTestCtx(t, 0)
+	require.NoError(t, err, "can't create context")
+
+	type report struct {
+		peer route.Vertex
+		err  error
+	}
+	reports := make(chan report, 1)
+	ctx.gossiper.cfg.ReportInvalidAnn = func(peer route.Vertex,
+		err error) {
+
+		reports <- report{peer, err}
+	}
+
+	remotePeer := &mockPeer{remoteKeyPriv1.PubKey(), nil, nil}
+
+	// Tamper with the announcement after it was signed, which invalidates
+	// its signatures.
+	ca, err := createRemoteChannelAnnouncement(0)
+	require.NoError(t, err, "can't create channel announcement")
+	ca.ExtraOpaqueData = []byte("tampered")
+
+	// A peer that isn't one of the nodes of the channel only relayed the
+	// announcement, so it isn't reported.
+	relayKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	relayPeer := &mockPeer{relayKey.PubKey(), nil, nil}
+
+	select {
+	case err = <-ctx.gossiper.ProcessRemoteAnnouncement(ca, relayPeer):
+		require.Error(t, err)
+	case <-time.After(2 * time.Second):
+		t.Fatal("did not process remote announcement")
+	}
+
+	select {
+	case r := <-reports:
+		t.Fatalf("unexpected report: %v", r.err)
+	default:
+	}
+
+	select {
+	case err = <-ctx.gossiper.ProcessRemoteAnnouncement(ca, remotePeer):
+		require.Error(t, err)
+	case <-time.After(2 * time.Second):
+		t.Fatal("did not process remote announcement")
+	}
+
+	select {
+	case r := <-reports:
+		require.Equal(t, route.NewVertex(remotePeer.pk), r.peer)
+		require.Error(t, r.err)
+	case <-time.After(2 * time.Second):
+		t.Fatal("invalid announcement not reported")
+	}
+
+	// Valid announcements aren't reported.
+	ca, err = createRemoteChannelAnnouncement(1)
+	require.NoError(t, err, "can't create channel announcement")
+
+	select {
+	case err = <-ctx.gossiper.ProcessRemoteAnnouncement(ca, remotePeer):
+		require.NoError(t, err)
+	case <-time.After(2 * time.Second):
+		t.Fatal("did not process remote announcement")
+	}
+
+	select {
+	case r := <-reports:
+		t.Fatalf("unexpected report: %v", r.err)
+	default:
+	}
+}
+
 // TestFutureMsgCacheEviction checks that when the cache's capacity is reached,
 // saving one more item will evict the oldest item.
 func TestFutureMsgCacheEviction(t *testing.T) {
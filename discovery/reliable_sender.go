@@ -29,6 +29,10 @@ type reliableSenderCfg struct {
 	// IsMsgStale determines whether a message retrieved from the backing
 	// MessageStore is seen as stale by the current graph.
 	IsMsgStale func(lnwire.Message) bool
+
+	// Budget is the gossip bandwidth budget of our peers. Messages are
+	// only sent to a peer once they fit into its outbound budget.
+	Budget *gossipBudget
 }
 
 // peerManager contains the set of channels required for the peerHandler to
@@ -237,6 +241,11 @@ out:
 		default:
 		}
 
+		budget := s.cfg.Budget.forPeer(peerPubKey)
+		if !budget.waitOutbound(s.quit, msg) {
+			return
+		}
+
 		if err := peer.SendMessage(false, msg); err != nil {
 			log.Errorf("Unable to send %v message for channel=%v "+
 				"to %x: %v", msg.MsgType(), shortChanID,
@@ -300,6 +309,11 @@ out:
 			// have a ShortChannelID field.
 			shortChanID, _ := msgShortChanID(msg)
 
+			budget := s.cfg.Budget.forPeer(peerPubKey)
+			if !budget.waitOutbound(s.quit, msg) {
+				return
+			}
+
 			if err := peer.SendMessage(false, msg); err != nil {
 				log.Errorf("Unable to send %v message for "+
 					"channel=%v to %x: %v", msg.MsgType(),
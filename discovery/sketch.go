@@ -0,0 +1,277 @@
+package discovery
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+const (
+	// sketchCellSize is the serialized size of a single cell of a
+	// setSketch: a 4 byte count, followed by the 8 byte XOR of the
+	// elements and the 8 byte XOR of their checksums.
+	sketchCellSize = 4 + 8 + 8
+
+	// sketchNumHashes is the number of cells each element of a setSketch
+	// is added to. The cells are split into as many partitions, and each
+	// element is added to exactly one cell of each partition.
+	sketchNumHashes = 3
+
+	// defaultSketchCells is the number of cells of the sketches we send to
+	// our peers. With three hash functions, a sketch can be decoded with
+	// high probability as long as the difference between both sets is
+	// below roughly 80% of its number of cells.
+	defaultSketchCells = 3000
+
+	// maxSketchCells is the maximum number of cells of a sketch that fit
+	// into a single ReconcileSketch message.
+	maxSketchCells = lnwire.MaxReconcileSketchBytes / sketchCellSize /
+		sketchNumHashes * sketchNumHashes
+)
+
+var (
+	// sketchSeeds are the seeds of the hash functions mapping an element
+	// to the cells of a setSketch.
+	sketchSeeds = [sketchNumHashes]uint64{
+		0x6a09e667f3bcc908, 0xbb67ae8584caa73b, 0x3c6ef372fe94f82b,
+	}
+
+	// sketchChecksumSeed is the seed of the hash function used to detect
+	// cells that only hold a single element.
+	sketchChecksumSeed uint64 = 0xa54ff53a5f1d36f1
+)
+
+// mix64 is the finalizer of the splitmix64 generator, which is used as a fast
+// hash function of 64-bit integers with good avalanche properties.
+func mix64(x uint64) uint64 {
+	x = (x ^ (x >> 30)) * 0xbf58476d1ce4e5b9
+	x = (x ^ (x >> 27)) * 0x94d049bb133111eb
+
+	return x ^ (x >> 31)
+}
+
+// chanUpdateElement returns the set element that represents the channel
+// update of the given channel and direction with the given timestamp. The
+// upper 32 bits are a hash of the channel and direction, while the lower 32
+// bits are the timestamp of the update. This allows both parties to tell
+// apart updates they're missing from updates they know an older or newer
+// version of.
+func chanUpdateElement(scid lnwire.ShortChannelID, direction uint8,
+	timestamp uint32) uint64 {
+
+	return chanUpdateKey(scid, direction)<<32 | uint64(timestamp)
+}
+
+// chanUpdateKey returns the hash of the channel and direction of a channel
+// update that is stored in the upper 32 bits of its set element.
+func chanUpdateKey(scid lnwire.ShortChannelID, direction uint8) uint64 {
+	return mix64(mix64(scid.ToUint64())^uint64(direction)) >> 32
+}
+
+// splitChanUpdateElement splits a set element into the hash of the channel
+// and direction of the update it represents, and the timestamp of the update.
+func splitChanUpdateElement(element uint64) (uint32, uint32) {
+	return uint32(element >> 32), uint32(element)
+}
+
+// sketchCell is a single cell of a setSketch.
+type sketchCell struct {
+	// count is the number of elements that were added to the cell, minus
+	// the number of elements that were removed from it.
+	count int32
+
+	// keySum is the XOR of all the elements of the cell.
+	keySum uint64
+
+	// hashSum is the XOR of the checksums of all the elements of the cell.
+	hashSum uint64
+}
+
+// pure returns true if the cell holds exactly one element, either added or
+// removed.
+func (c *sketchCell) pure() bool {
+	return (c.count == 1 || c.count == -1) &&
+		c.hashSum == mix64(c.keySum^sketchChecksumSeed)
+}
+
+// empty returns true if the cell doesn't hold any elements.
+func (c *sketchCell) empty() bool {
+	return c.count == 0 && c.keySum == 0 && c.hashSum == 0
+}
+
+// setSketch is a fixed size sketch of a set of 64-bit elements, implemented as
+// an invertible bloom lookup table. Like a minisketch, subtracting the
+// sketches of two sets yields a sketch of their symmetric difference, which
+// can be decoded as long as the difference is small enough compared to the
+// size of the sketch, no matter how large the sets themselves are.
+type setSketch struct {
+	cells []sketchCell
+}
+
+// newSetSketch creates an empty sketch with the given number of cells, rounded
+// up to a multiple of the number of hash functions.
+func newSetSketch(numCells int) *setSketch {
+	if numCells < sketchNumHashes {
+		numCells = sketchNumHashes
+	}
+	if rem := numCells % sketchNumHashes; rem != 0 {
+		numCells += sketchNumHashes - rem
+	}
+
+	return &setSketch{
+		cells: make([]sketchCell, numCells),
+	}
+}
+
+// numCells returns the number of cells of the sketch.
+func (s *setSketch) numCells() int {
+	return len(s.cells)
+}
+
+// add adds an element to the sketch.
+func (s *setSketch) add(element uint64) {
+	s.update(element, 1)
+}
+
+// update adds or removes an element from the sketch, depending on the sign of
+// the given count.
+func (s *setSketch) update(element uint64, count int32) {
+	checksum := mix64(element ^ sketchChecksumSeed)
+	partitionSize := uint64(len(s.cells) / sketchNumHashes)
+
+	for i, seed := range sketchSeeds {
+		idx := uint64(i)*partitionSize +
+			mix64(element^seed)%partitionSize
+
+		cell := &s.cells[idx]
+		cell.count += count
+		cell.keySum ^= element
+		cell.hashSum ^= checksum
+	}
+}
+
+// subtract returns a new sketch of the symmetric difference between the set
+// of this sketch and the set of the other sketch. Both sketches must have the
+// same number of cells.
+func (s *setSketch) subtract(other *setSketch) (*setSketch, error) {
+	if len(s.cells) != len(other.cells) {
+		return nil, fmt.Errorf("sketch sizes don't match: %v vs %v",
+			len(s.cells), len(other.cells))
+	}
+
+	diff := &setSketch{
+		cells: make([]sketchCell, len(s.cells)),
+	}
+	for i := range s.cells {
+		diff.cells[i] = sketchCell{
+			count:   s.cells[i].count - other.cells[i].count,
+			keySum:  s.cells[i].keySum ^ other.cells[i].keySum,
+			hashSum: s.cells[i].hashSum ^ other.cells[i].hashSum,
+		}
+	}
+
+	return diff, nil
+}
+
+// decode recovers the elements of a sketch obtained through subtract. The
+// elements that were only part of the set of the receiver of subtract are
+// returned first, followed by the elements that were only part of the other
+// set. False is returned if the difference is too large to be recovered from
+// the sketch.
+//
+// NOTE: The sketch is consumed in the process.
+func (s *setSketch) decode() ([]uint64, []uint64, bool) {
+	var (
+		local, remote []uint64
+		pending       = make([]int, 0, len(s.cells))
+	)
+	for i := range s.cells {
+		if s.cells[i].pure() {
+			pending = append(pending, i)
+		}
+	}
+
+	// Repeatedly peel off the elements of pure cells. Removing an element
+	// may in turn leave other cells with a single element.
+	for len(pending) > 0 {
+		idx := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+
+		cell := s.cells[idx]
+		if !cell.pure() {
+			continue
+		}
+
+		element := cell.keySum
+		if cell.count == 1 {
+			local = append(local, element)
+		} else {
+			remote = append(remote, element)
+		}
+		s.update(element, -cell.count)
+
+		partitionSize := uint64(len(s.cells) / sketchNumHashes)
+		for i, seed := range sketchSeeds {
+			next := int(uint64(i)*partitionSize +
+				mix64(element^seed)%partitionSize)
+
+			if s.cells[next].pure() {
+				pending = append(pending, next)
+			}
+		}
+	}
+
+	// The difference was fully recovered only if no elements are left.
+	for i := range s.cells {
+		if !s.cells[i].empty() {
+			return nil, nil, false
+		}
+	}
+
+	return local, remote, true
+}
+
+// serialize returns the wire encoding of the sketch.
+func (s *setSketch) serialize() []byte {
+	b := make([]byte, len(s.cells)*sketchCellSize)
+	for i, cell := range s.cells {
+		offset := i * sketchCellSize
+		binary.BigEndian.PutUint32(b[offset:], uint32(cell.count))
+		binary.BigEndian.PutUint64(b[offset+4:], cell.keySum)
+		binary.BigEndian.PutUint64(b[offset+12:], cell.hashSum)
+	}
+
+	return b
+}
+
+// deserializeSetSketch parses a sketch from its wire encoding.
+func deserializeSetSketch(b []byte) (*setSketch, error) {
+	numCells := len(b) / sketchCellSize
+	switch {
+	case len(b)%sketchCellSize != 0:
+		return nil, fmt.Errorf("invalid sketch length %v", len(b))
+
+	case numCells == 0 || numCells%sketchNumHashes != 0:
+		return nil, fmt.Errorf("invalid number of sketch cells %v",
+			numCells)
+	}
+
+	s := &setSketch{
+		cells: make([]sketchCell, numCells),
+	}
+	for i := range s.cells {
+		offset := i * sketchCellSize
+		s.cells[i] = sketchCell{
+			count: int32(binary.BigEndian.Uint32(b[offset:])),
+			keySum: binary.BigEndian.Uint64(
+				b[offset+4:],
+			),
+			hashSum: binary.BigEndian.Uint64(
+				b[offset+12:],
+			),
+		}
+	}
+
+	return s, nil
+}
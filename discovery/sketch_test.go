@@ -0,0 +1,98 @@
+package discovery
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestSetSketchDecode asserts that the symmetric difference of two large sets
+// can be recovered from their sketches as long as it's small enough, and that
+// decoding fails otherwise.
+func TestSetSketchDecode(t *testing.T) {
+	t.Parallel()
+
+	r := rand.New(rand.NewSource(1))
+
+	sortElements := func(elements []uint64) []uint64 {
+		sort.Slice(elements, func(i, j int) bool {
+			return elements[i] < elements[j]
+		})
+		return elements
+	}
+
+	// newSets creates two sets that share a large number of elements, and
+	// each have a number of elements of their own.
+	newSets := func(numShared, numDiff int) ([]uint64, []uint64, []uint64,
+		[]uint64) {
+
+		var shared, onlyA, onlyB []uint64
+		for i := 0; i < numShared; i++ {
+			shared = append(shared, r.Uint64())
+		}
+		for i := 0; i < numDiff; i++ {
+			onlyA = append(onlyA, r.Uint64())
+			onlyB = append(onlyB, r.Uint64())
+		}
+
+		setA := append(append([]uint64{}, shared...), onlyA...)
+		setB := append(append([]uint64{}, shared...), onlyB...)
+
+		return setA, setB, sortElements(onlyA), sortElements(onlyB)
+	}
+
+	newSketch := func(numCells int, set []uint64) *setSketch {
+		sketch := newSetSketch(numCells)
+		for _, element := range set {
+			sketch.add(element)
+		}
+		return sketch
+	}
+
+	setA, setB, onlyA, onlyB := newSets(10000, 100)
+	sketchA := newSketch(600, setA)
+	sketchB := newSketch(600, setB)
+
+	// The sketch must survive a round trip through its wire encoding.
+	decodedB, err := deserializeSetSketch(sketchB.serialize())
+	require.NoError(t, err)
+	require.Equal(t, sketchB, decodedB)
+
+	diff, err := sketchA.subtract(decodedB)
+	require.NoError(t, err)
+
+	local, remote, ok := diff.decode()
+	require.True(t, ok)
+	require.Equal(t, onlyA, sortElements(local))
+	require.Equal(t, onlyB, sortElements(remote))
+
+	// Identical sets result in an empty difference.
+	diff, err = sketchA.subtract(newSketch(600, setA))
+	require.NoError(t, err)
+
+	local, remote, ok = diff.decode()
+	require.True(t, ok)
+	require.Empty(t, local)
+	require.Empty(t, remote)
+
+	// A difference larger than the sketch can't be decoded.
+	setA, setB, _, _ = newSets(1000, 500)
+	diff, err = newSketch(600, setA).subtract(newSketch(600, setB))
+	require.NoError(t, err)
+
+	_, _, ok = diff.decode()
+	require.False(t, ok)
+
+	// Sketches of different sizes can't be subtracted.
+	_, err = newSketch(600, setA).subtract(newSketch(300, setA))
+	require.Error(t, err)
+
+	// Sketches with a partial cell, or a number of cells that isn't a
+	// multiple of the number of hash functions, are rejected.
+	_, err = deserializeSetSketch(make([]byte, sketchCellSize*3+1))
+	require.Error(t, err)
+	_, err = deserializeSetSketch(make([]byte, sketchCellSize*4))
+	require.Error(t, err)
+}
@@ -97,6 +97,10 @@ type SyncManagerCfg struct {
 	// ActiveSync upon connection. These peers will never transition to
 	// PassiveSync.
 	PinnedSyncers PinnedSyncers
+
+	// Budget holds the bandwidth budgets of the gossip we exchange with our
+	// peers.
+	Budget GossipBudgetConfig
 }
 
 // SyncManager is a subsystem of the gossiper that manages the gossip syncers
@@ -151,6 +155,14 @@ type SyncManager struct {
 	// duration of the connection.
 	pinnedActiveSyncers map[route.Vertex]*GossipSyncer
 
+	// staleBandwidth is the bandwidth used by the syncers of peers that
+	// have since disconnected, for each sync mode.
+	staleBandwidth map[SyncMode]SyncBandwidth
+
+	// budget enforces the bandwidth budgets of the gossip we exchange with
+	// our peers.
+	budget *gossipBudget
+
 	wg   sync.WaitGroup
 	quit chan struct{}
 }
@@ -168,7 +180,9 @@ func newSyncManager(cfg *SyncManagerCfg) *SyncManager {
 		pinnedActiveSyncers: make(
 			map[route.Vertex]*GossipSyncer, len(cfg.PinnedSyncers),
 		),
-		quit: make(chan struct{}),
+		staleBandwidth: make(map[SyncMode]SyncBandwidth),
+		budget:         newGossipBudget(cfg.Budget),
+		quit:           make(chan struct{}),
 	}
 }
 
@@ -495,6 +509,9 @@ func (m *SyncManager) createGossipSyncer(peer lnpeer.Peer) *GossipSyncer {
 		bestHeight:                m.cfg.BestHeight,
 		markGraphSynced:           m.markGraphSynced,
 		maxQueryChanRangeReplies:  maxQueryChanRangeReplies,
+		reconcile:                 supportsGossipReconcile(peer),
+		sketchCells:               defaultSketchCells,
+		budget:                    m.budget.forPeer(nodeID),
 	})
 
 	// Gossip syncers are initialized by default in a PassiveSync type
@@ -527,6 +544,13 @@ func (m *SyncManager) removeGossipSyncer(peer route.Vertex) {
 	// to prevent blocking the SyncManager.
 	go s.Stop()
 
+	// Hold onto the bandwidth used by the syncer, so it's still accounted
+	// for once the peer is gone.
+	for mode, bandwidth := range s.Bandwidth() {
+		m.staleBandwidth[mode] = m.staleBandwidth[mode].add(bandwidth)
+	}
+	m.budget.removePeer(peer)
+
 	// If it's a non-active syncer, then we can just exit now.
 	if _, ok := m.inactiveSyncers[peer]; ok {
 		delete(m.inactiveSyncers, peer)
@@ -771,6 +795,79 @@ func (m *SyncManager) gossipSyncers() map[route.Vertex]*GossipSyncer {
 	return syncers
 }
 
+// Bandwidth returns the total bandwidth used to sync the channel graph with
+// all peers we've been connected to, for each sync mode that has been used.
+func (m *SyncManager) Bandwidth() map[SyncMode]SyncBandwidth {
+	m.syncersMu.Lock()
+	defer m.syncersMu.Unlock()
+
+	total := make(map[SyncMode]SyncBandwidth, len(m.staleBandwidth))
+	for mode, bandwidth := range m.staleBandwidth {
+		total[mode] = bandwidth
+	}
+
+	for _, syncers := range []map[route.Vertex]*GossipSyncer{
+		m.inactiveSyncers, m.activeSyncers, m.pinnedActiveSyncers,
+	} {
+		for _, syncer := range syncers {
+			for mode, bandwidth := range syncer.Bandwidth() {
+				total[mode] = total[mode].add(bandwidth)
+			}
+		}
+	}
+
+	return total
+}
+
+// SyncerBudgetUsage describes the gossip exchanged with a single peer and how
+// it was affected by the bandwidth budgets.
+type SyncerBudgetUsage struct {
+	// Peer is the public key of the peer.
+	Peer route.Vertex
+
+	// SyncType is the current sync type of the peer's syncer.
+	SyncType SyncerType
+
+	// Usage is the gossip exchanged with the peer.
+	Usage GossipBudgetUsage
+}
+
+// BudgetUsage returns the total gossip exchanged with all peers we've been
+// connected to, along with the usage of each of the syncers of our current
+// peers.
+func (m *SyncManager) BudgetUsage() (GossipBudgetUsage, []SyncerBudgetUsage) {
+	m.syncersMu.Lock()
+	defer m.syncersMu.Unlock()
+
+	var syncers []SyncerBudgetUsage
+	for _, syncerSet := range []map[route.Vertex]*GossipSyncer{
+		m.inactiveSyncers, m.activeSyncers, m.pinnedActiveSyncers,
+	} {
+		for peer, syncer := range syncerSet {
+			syncers = append(syncers, SyncerBudgetUsage{
+				Peer:     peer,
+				SyncType: syncer.SyncType(),
+				Usage:    syncer.BudgetUsage(),
+			})
+		}
+	}
+
+	return m.budget.usage(), syncers
+}
+
+// supportsGossipReconcile returns true if both we and the given peer support
+// synchronizing channel updates through set reconciliation.
+func supportsGossipReconcile(peer lnpeer.Peer) bool {
+	localFeatures := peer.LocalFeatures()
+	remoteFeatures := peer.RemoteFeatures()
+	if localFeatures == nil || remoteFeatures == nil {
+		return false
+	}
+
+	return localFeatures.HasFeature(lnwire.GossipReconcileOptional) &&
+		remoteFeatures.HasFeature(lnwire.GossipReconcileOptional)
+}
+
 // markGraphSynced allows us to report that the initial historical sync has
 // completed.
 func (m *SyncManager) markGraphSynced() {
@@ -1,6 +1,7 @@
 package discovery
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"math"
@@ -121,6 +122,14 @@ const (
 	// initial state for pinned syncers, as well as a fallthrough case for
 	// chansSynced allowing fully synced peers to facilitate requests.
 	syncerIdle
+
+	// waitingReconcileReply is the state we enter instead of
+	// waitingQueryRangeReply if both we and the remote peer support set
+	// reconciliation. We'll stay in this state after sending out the
+	// sketch of our set of channel updates until the remote peer sends us
+	// a ReconcileDiff message, which indicates they've sent us all the
+	// updates we're missing.
+	waitingReconcileReply
 )
 
 // String returns a human readable string describing the target syncerState.
@@ -144,11 +153,79 @@ func (s syncerState) String() string {
 	case syncerIdle:
 		return "syncerIdle"
 
+	case waitingReconcileReply:
+		return "waitingReconcileReply"
+
 	default:
 		return "UNKNOWN STATE"
 	}
 }
 
+// SyncMode denotes the method used to synchronize the channel graph with a
+// remote peer.
+type SyncMode uint8
+
+const (
+	// LegacySync denotes a sync through QueryChannelRange and
+	// QueryShortChanIDs messages, which exchange the full set of short
+	// channel IDs known to the remote peer.
+	LegacySync SyncMode = iota
+
+	// ReconcileSync denotes a sync through ReconcileSketch and
+	// ReconcileDiff messages, which only exchange the difference between
+	// the sets of channel updates known to both peers.
+	ReconcileSync
+)
+
+// String returns a human readable string describing the target SyncMode.
+func (m SyncMode) String() string {
+	switch m {
+	case LegacySync:
+		return "LegacySync"
+	case ReconcileSync:
+		return "ReconcileSync"
+	default:
+		return fmt.Sprintf("unknown sync mode %d", m)
+	}
+}
+
+// SyncBandwidth tracks the messages exchanged with a remote peer to
+// synchronize the channel graph using a particular SyncMode. Messages sent
+// include the queries and replies of the sync protocol, as well as any
+// announcements sent in response to them. Messages received only include the
+// queries and replies, as announcements are processed by the gossiper.
+type SyncBandwidth struct {
+	// MsgsSent is the number of messages sent to the remote peer.
+	MsgsSent uint64
+
+	// BytesSent is the total size of the messages sent to the remote peer.
+	BytesSent uint64
+
+	// MsgsReceived is the number of messages received from the remote
+	// peer.
+	MsgsReceived uint64
+
+	// BytesReceived is the total size of the messages received from the
+	// remote peer.
+	BytesReceived uint64
+}
+
+// add returns the sum of both bandwidth counters.
+func (b SyncBandwidth) add(other SyncBandwidth) SyncBandwidth {
+	return SyncBandwidth{
+		MsgsSent:      b.MsgsSent + other.MsgsSent,
+		BytesSent:     b.BytesSent + other.BytesSent,
+		MsgsReceived:  b.MsgsReceived + other.MsgsReceived,
+		BytesReceived: b.BytesReceived + other.BytesReceived,
+	}
+}
+
+// String returns a human readable summary of the bandwidth counters.
+func (b SyncBandwidth) String() string {
+	return fmt.Sprintf("sent=%v msgs/%v bytes, received=%v msgs/%v bytes",
+		b.MsgsSent, b.BytesSent, b.MsgsReceived, b.BytesReceived)
+}
+
 const (
 	// DefaultMaxUndelayedQueryReplies specifies how many gossip queries we
 	// will respond to immediately before starting to delay responses.
@@ -252,6 +329,11 @@ type gossipSyncerCfg struct {
 	// write error is encountered.
 	sendToPeerSync func(...lnwire.Message) error
 
+	// budget is the gossip bandwidth budget of the remote peer. Replies to
+	// the queries of the peer are delayed until they fit into it, while
+	// announcements exceeding it aren't relayed to the peer at all.
+	budget *peerBudget
+
 	// maxUndelayedQueryReplies specifies how many gossip queries we will
 	// respond to immediately before starting to delay responses.
 	maxUndelayedQueryReplies int
@@ -287,6 +369,15 @@ type gossipSyncerCfg struct {
 	// maxQueryChanRangeReplies is the maximum number of replies we'll allow
 	// for a single QueryChannelRange request.
 	maxQueryChanRangeReplies uint32
+
+	// reconcile is true if both we and the remote peer support
+	// synchronizing channel updates through set reconciliation, in which
+	// case we'll use it instead of channel range queries.
+	reconcile bool
+
+	// sketchCells is the number of cells of the sketches of our set of
+	// channel updates that we send to the remote peer.
+	sketchCells int
 }
 
 // GossipSyncer is a struct that handles synchronizing the channel graph state
@@ -373,6 +464,23 @@ type GossipSyncer struct {
 	// state.
 	newChansToQuery []lnwire.ShortChannelID
 
+	// reconcileSet is the set of channel updates we've sent a sketch of to
+	// the remote peer, indexed by their set element. This field is
+	// primarily used within the waitingReconcileReply state.
+	reconcileSet map[uint64]ChanUpdateTimestamp
+
+	// reconcileFailed is set once the remote peer was unable to decode the
+	// sketch we sent, signaling that we should fall back to channel range
+	// queries for the current sync.
+	reconcileFailed bool
+
+	// bandwidthMtx guards the bandwidth map below.
+	bandwidthMtx sync.Mutex
+
+	// bandwidth tracks the messages exchanged with the remote peer to
+	// synchronize the channel graph, for each sync mode.
+	bandwidth map[SyncMode]SyncBandwidth
+
 	cfg gossipSyncerCfg
 
 	// rateLimiter dictates the frequency with which we will reply to gossip
@@ -415,9 +523,14 @@ func newGossipSyncer(cfg gossipSyncerCfg) *GossipSyncer {
 		interval, cfg.maxUndelayedQueryReplies,
 	)
 
+	if cfg.sketchCells <= 0 {
+		cfg.sketchCells = defaultSketchCells
+	}
+
 	return &GossipSyncer{
 		cfg:                cfg,
 		rateLimiter:        rateLimiter,
+		bandwidth:          make(map[SyncMode]SyncBandwidth),
 		syncTransitionReqs: make(chan *syncTransitionReq),
 		historicalSyncReqs: make(chan *historicalSyncReq),
 		gossipMsgs:         make(chan lnwire.Message, 100),
@@ -478,6 +591,21 @@ func (g *GossipSyncer) channelGraphSyncer() {
 		// understand, as we'll as responding to any other queries by
 		// them.
 		case syncingChans:
+			// If both of us support set reconciliation, we'll send
+			// the remote peer the sketch of our channel updates
+			// instead, unless they already failed to decode it.
+			if g.cfg.reconcile && !g.reconcileFailed {
+				if err := g.sendReconcileSketch(); err != nil {
+					log.Errorf("Unable to send reconcile "+
+						"sketch: %v", err)
+					return
+				}
+
+				g.setSyncState(waitingReconcileReply)
+				continue
+			}
+			g.reconcileFailed = false
+
 			// If we're in this state, then we'll send the remote
 			// peer our opening QueryChannelRange message.
 			queryRangeMsg, err := g.genChanRangeQuery(
@@ -489,6 +617,7 @@ func (g *GossipSyncer) channelGraphSyncer() {
 				return
 			}
 
+			g.countSent(LegacySync, queryRangeMsg)
 			err = g.cfg.sendToPeer(queryRangeMsg)
 			if err != nil {
 				log.Errorf("Unable to send chan range "+
@@ -588,6 +717,31 @@ func (g *GossipSyncer) channelGraphSyncer() {
 				return
 			}
 
+		// In this state, we've sent out the sketch of our channel
+		// updates and are waiting for the remote peer to send us the
+		// updates we're missing, followed by a ReconcileDiff message.
+		case waitingReconcileReply:
+			select {
+			case msg := <-g.gossipMsgs:
+				diff, ok := msg.(*lnwire.ReconcileDiff)
+				if ok {
+					err := g.processReconcileDiff(diff)
+					if err != nil {
+						log.Errorf("Unable to "+
+							"process reconcile "+
+							"diff: %v", err)
+						return
+					}
+					continue
+				}
+
+				log.Warnf("Unexpected message: %T in state=%v",
+					msg, state)
+
+			case <-g.quit:
+				return
+			}
+
 		// This is our final terminal state where we'll only reply to
 		// any further queries by the remote peer.
 		case chansSynced:
@@ -598,6 +752,11 @@ func (g *GossipSyncer) channelGraphSyncer() {
 			}
 			g.Unlock()
 
+			log.Debugf("GossipSyncer(%x): sync bandwidth: "+
+				"legacy=(%v), reconcile=(%v)", g.cfg.peerPub[:],
+				g.bandwidthFor(LegacySync),
+				g.bandwidthFor(ReconcileSync))
+
 			// If we haven't yet sent out our update horizon, and
 			// we want to receive real-time channel updates, we'll
 			// do so now.
@@ -739,11 +898,13 @@ func (g *GossipSyncer) synchronizeChanIDs() (bool, error) {
 
 	// With our chunk obtained, we'll send over our next query, then return
 	// false indicating that we're net yet fully synced.
-	err := g.cfg.sendToPeer(&lnwire.QueryShortChanIDs{
+	query := &lnwire.QueryShortChanIDs{
 		ChainHash:    g.cfg.chainHash,
 		EncodingType: lnwire.EncodingSortedPlain,
 		ShortChanIDs: queryChunk,
-	})
+	}
+	g.countSent(LegacySync, query)
+	err := g.cfg.sendToPeer(query)
 
 	return false, err
 }
@@ -982,6 +1143,12 @@ func (g *GossipSyncer) replyPeerQueries(msg lnwire.Message) error {
 	case *lnwire.QueryShortChanIDs:
 		return g.replyShortChanIDs(msg)
 
+	// If the remote peer is syncing through set reconciliation, we'll
+	// decode the difference between our sets and send them the updates
+	// they're missing.
+	case *lnwire.ReconcileSketch:
+		return g.replyReconcileSketch(msg)
+
 	default:
 		return fmt.Errorf("unknown message: %T", msg)
 	}
@@ -1002,14 +1169,17 @@ func (g *GossipSyncer) replyChanRangeQuery(query *lnwire.QueryChannelRange) erro
 			"chain=%v, we're on chain=%v", query.ChainHash,
 			g.cfg.chainHash)
 
-		return g.cfg.sendToPeerSync(&lnwire.ReplyChannelRange{
+		reply := &lnwire.ReplyChannelRange{
 			ChainHash:        query.ChainHash,
 			FirstBlockHeight: query.FirstBlockHeight,
 			NumBlocks:        query.NumBlocks,
 			Complete:         0,
 			EncodingType:     g.cfg.encodingType,
 			ShortChanIDs:     nil,
-		})
+		}
+		g.countSent(LegacySync, reply)
+
+		return g.sendToPeerSync(reply)
 	}
 
 	log.Infof("GossipSyncer(%x): filtering chan range: start_height=%v, "+
@@ -1047,14 +1217,17 @@ func (g *GossipSyncer) replyChanRangeQuery(query *lnwire.QueryChannelRange) erro
 			complete = 1
 		}
 
-		return g.cfg.sendToPeerSync(&lnwire.ReplyChannelRange{
+		reply := &lnwire.ReplyChannelRange{
 			ChainHash:        query.ChainHash,
 			NumBlocks:        numBlocks,
 			FirstBlockHeight: firstHeight,
 			Complete:         complete,
 			EncodingType:     g.cfg.encodingType,
 			ShortChanIDs:     channelChunk,
-		})
+		}
+		g.countSent(LegacySync, reply)
+
+		return g.sendToPeerSync(reply)
 	}
 
 	var (
@@ -1136,10 +1309,13 @@ func (g *GossipSyncer) replyShortChanIDs(query *lnwire.QueryShortChanIDs) error
 			"chain=%v, we're on chain=%v", query.ChainHash,
 			g.cfg.chainHash)
 
-		return g.cfg.sendToPeerSync(&lnwire.ReplyShortChanIDsEnd{
+		reply := &lnwire.ReplyShortChanIDsEnd{
 			ChainHash: query.ChainHash,
 			Complete:  0,
-		})
+		}
+		g.countSent(LegacySync, reply)
+
+		return g.sendToPeerSync(reply)
 	}
 
 	if len(query.ShortChanIDs) == 0 {
@@ -1167,7 +1343,8 @@ func (g *GossipSyncer) replyShortChanIDs(query *lnwire.QueryShortChanIDs) error
 	// each one individually and synchronously to throttle the sends and
 	// perform buffering of responses in the syncer as opposed to the peer.
 	for _, msg := range replyMsgs {
-		err := g.cfg.sendToPeerSync(msg)
+		g.countSent(LegacySync, msg)
+		err := g.sendToPeerSync(msg)
 		if err != nil {
 			return err
 		}
@@ -1175,10 +1352,323 @@ func (g *GossipSyncer) replyShortChanIDs(query *lnwire.QueryShortChanIDs) error
 
 	// Regardless of whether we had any messages to reply with, send over
 	// the sentinel message to signal that the stream has terminated.
-	return g.cfg.sendToPeerSync(&lnwire.ReplyShortChanIDsEnd{
+	reply := &lnwire.ReplyShortChanIDsEnd{
 		ChainHash: query.ChainHash,
 		Complete:  1,
+	}
+	g.countSent(LegacySync, reply)
+
+	return g.sendToPeerSync(reply)
+}
+
+// chanUpdateSet returns the set of channel updates we know of, indexed by the
+// set element that represents them.
+func (g *GossipSyncer) chanUpdateSet() (map[uint64]ChanUpdateTimestamp,
+	error) {
+
+	timestamps, err := g.cfg.channelSeries.ChanUpdateTimestamps(
+		g.cfg.chainHash,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	set := make(map[uint64]ChanUpdateTimestamp, len(timestamps))
+	for _, ts := range timestamps {
+		element := chanUpdateElement(
+			ts.ShortChannelID, ts.Direction, ts.Timestamp,
+		)
+		set[element] = ts
+	}
+
+	return set, nil
+}
+
+// sendReconcileSketch sends the remote peer the sketch of the set of channel
+// updates we know of, kicking off a sync through set reconciliation.
+func (g *GossipSyncer) sendReconcileSketch() error {
+	set, err := g.chanUpdateSet()
+	if err != nil {
+		return err
+	}
+
+	sketch := newSetSketch(g.cfg.sketchCells)
+	for element := range set {
+		sketch.add(element)
+	}
+
+	log.Infof("GossipSyncer(%x): sending sketch of %v chan updates with "+
+		"%v cells", g.cfg.peerPub[:], len(set), sketch.numCells())
+
+	g.reconcileSet = set
+
+	msg := lnwire.NewReconcileSketch(g.cfg.chainHash, sketch.serialize())
+	g.countSent(ReconcileSync, msg)
+
+	return g.cfg.sendToPeer(msg)
+}
+
+// processReconcileDiff is called once the remote peer has replied to our
+// sketch. By then, they've sent us all the channel updates we were missing,
+// so we'll send them the ones they requested in return and consider ourselves
+// synced. If they were unable to decode our sketch, we'll fall back to
+// channel range queries.
+func (g *GossipSyncer) processReconcileDiff(msg *lnwire.ReconcileDiff) error {
+	set := g.reconcileSet
+	g.reconcileSet = nil
+
+	if msg.Complete == 0 {
+		log.Infof("GossipSyncer(%x): remote peer unable to reconcile, "+
+			"falling back to chan range query", g.cfg.peerPub[:])
+
+		g.reconcileFailed = true
+		g.setSyncState(syncingChans)
+
+		return nil
+	}
+
+	// Gather the channels of the updates the remote peer is missing. We
+	// ignore any elements that aren't part of our set, as we can't tell
+	// which update they stand for.
+	chanIDSet := make(map[lnwire.ShortChannelID]struct{})
+	for _, element := range msg.Elements {
+		update, ok := set[element]
+		if !ok {
+			continue
+		}
+
+		chanIDSet[update.ShortChannelID] = struct{}{}
+	}
+
+	if len(chanIDSet) > 0 {
+		chanIDs := sortedChanIDs(chanIDSet)
+
+		log.Infof("GossipSyncer(%x): sending %v chans requested by "+
+			"remote peer", g.cfg.peerPub[:], len(chanIDs))
+
+		msgs, err := g.cfg.channelSeries.FetchChanAnns(
+			g.cfg.chainHash, chanIDs,
+		)
+		if err != nil {
+			return fmt.Errorf("unable to fetch chan anns: %v", err)
+		}
+
+		for _, msg := range msgs {
+			g.countSent(ReconcileSync, msg)
+			if err := g.sendToPeerSync(msg); err != nil {
+				return err
+			}
+		}
+	}
+
+	log.Infof("GossipSyncer(%x): reconciled chan updates with remote "+
+		"peer", g.cfg.peerPub[:])
+
+	g.setSyncState(chansSynced)
+
+	// Ensure that the sync manager becomes aware that the historical sync
+	// completed so synced_to_graph is updated over rpc.
+	g.cfg.markGraphSynced()
+
+	return nil
+}
+
+// replyReconcileSketch will be dispatched in response to a sketch of the
+// channel updates known to the remote peer. We'll subtract it from the sketch
+// of our own set to recover the difference between both sets, send the remote
+// peer any updates they're missing, and ask them for the ones we're missing in
+// turn.
+func (g *GossipSyncer) replyReconcileSketch(msg *lnwire.ReconcileSketch) error {
+	sendDiff := func(diff *lnwire.ReconcileDiff) error {
+		g.countSent(ReconcileSync, diff)
+		return g.sendToPeerSync(diff)
+	}
+
+	// If we're not on the same chain, or didn't negotiate set
+	// reconciliation, we'll signal the remote peer to fall back to channel
+	// range queries.
+	failure := &lnwire.ReconcileDiff{
+		ChainHash: msg.ChainHash,
+		Complete:  0,
+	}
+	if g.cfg.chainHash != msg.ChainHash {
+		log.Warnf("Remote peer requested ReconcileSketch for "+
+			"chain=%v, we're on chain=%v", msg.ChainHash,
+			g.cfg.chainHash)
+
+		return sendDiff(failure)
+	}
+	if !g.cfg.reconcile {
+		log.Warnf("GossipSyncer(%x): received reconcile sketch "+
+			"without negotiating set reconciliation",
+			g.cfg.peerPub[:])
+
+		return sendDiff(failure)
+	}
+
+	remoteSketch, err := deserializeSetSketch(msg.Sketch)
+	if err != nil {
+		log.Warnf("GossipSyncer(%x): invalid reconcile sketch: %v",
+			g.cfg.peerPub[:], err)
+
+		return sendDiff(failure)
+	}
+
+	set, err := g.chanUpdateSet()
+	if err != nil {
+		return err
+	}
+
+	localSketch := newSetSketch(remoteSketch.numCells())
+	for element := range set {
+		localSketch.add(element)
+	}
+
+	diffSketch, err := localSketch.subtract(remoteSketch)
+	if err != nil {
+		return err
+	}
+
+	localOnly, remoteOnly, ok := diffSketch.decode()
+	if !ok || len(remoteOnly) > lnwire.MaxReconcileDiffElements {
+		log.Infof("GossipSyncer(%x): unable to decode reconcile "+
+			"sketch with %v cells", g.cfg.peerPub[:],
+			remoteSketch.numCells())
+
+		return sendDiff(failure)
+	}
+
+	log.Infof("GossipSyncer(%x): decoded reconcile sketch, local_only=%v, "+
+		"remote_only=%v", g.cfg.peerPub[:], len(localOnly),
+		len(remoteOnly))
+
+	// The elements of both sides that belong to the same channel and
+	// direction are different versions of the same update. We'll index
+	// them by the hash of the channel and direction, so that only the
+	// newer version of each update is exchanged.
+	newestTimestamps := func(elements []uint64) map[uint32]uint32 {
+		newest := make(map[uint32]uint32, len(elements))
+		for _, element := range elements {
+			key, timestamp := splitChanUpdateElement(element)
+			if prev, ok := newest[key]; !ok || timestamp > prev {
+				newest[key] = timestamp
+			}
+		}
+
+		return newest
+	}
+	localNewest := newestTimestamps(localOnly)
+	remoteNewest := newestTimestamps(remoteOnly)
+
+	// If the remote peer knows an older version of one of our updates,
+	// they already know the channel, so it's enough to send them the
+	// update itself. Otherwise, we'll send them the full set of
+	// announcements for the channel.
+	var (
+		annChanIDs    = make(map[lnwire.ShortChannelID]struct{})
+		updateChanIDs = make(map[lnwire.ShortChannelID][2]bool)
+	)
+	for _, element := range localOnly {
+		update, ok := set[element]
+		if !ok {
+			continue
+		}
+
+		key, timestamp := splitChanUpdateElement(element)
+		remoteTimestamp, ok := remoteNewest[key]
+		switch {
+		case !ok:
+			annChanIDs[update.ShortChannelID] = struct{}{}
+
+		case remoteTimestamp < timestamp:
+			directions := updateChanIDs[update.ShortChannelID]
+			directions[update.Direction] = true
+			updateChanIDs[update.ShortChannelID] = directions
+		}
+	}
+
+	if len(annChanIDs) > 0 {
+		msgs, err := g.cfg.channelSeries.FetchChanAnns(
+			g.cfg.chainHash, sortedChanIDs(annChanIDs),
+		)
+		if err != nil {
+			return fmt.Errorf("unable to fetch chan anns: %v", err)
+		}
+
+		for _, msg := range msgs {
+			g.countSent(ReconcileSync, msg)
+			if err := g.sendToPeerSync(msg); err != nil {
+				return err
+			}
+		}
+	}
+
+	updateChanSet := make(map[lnwire.ShortChannelID]struct{})
+	for chanID := range updateChanIDs {
+		if _, ok := annChanIDs[chanID]; !ok {
+			updateChanSet[chanID] = struct{}{}
+		}
+	}
+	for _, chanID := range sortedChanIDs(updateChanSet) {
+		updates, err := g.cfg.channelSeries.FetchChanUpdates(
+			g.cfg.chainHash, chanID,
+		)
+		if err != nil {
+			return fmt.Errorf("unable to fetch chan updates for "+
+				"%v: %v", chanID, err)
+		}
+
+		directions := updateChanIDs[chanID]
+		for _, update := range updates {
+			direction := update.ChannelFlags &
+				lnwire.ChanUpdateDirection
+			if !directions[direction] {
+				continue
+			}
+
+			g.countSent(ReconcileSync, update)
+			if err := g.sendToPeerSync(update); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Finally, we'll ask the remote peer for the updates we don't know of,
+	// or only know an older version of.
+	diff := &lnwire.ReconcileDiff{
+		ChainHash: msg.ChainHash,
+		Complete:  1,
+	}
+	for _, element := range remoteOnly {
+		key, timestamp := splitChanUpdateElement(element)
+		if localTimestamp, ok := localNewest[key]; ok &&
+			localTimestamp >= timestamp {
+
+			continue
+		}
+
+		diff.Elements = append(diff.Elements, element)
+	}
+	sort.Slice(diff.Elements, func(i, j int) bool {
+		return diff.Elements[i] < diff.Elements[j]
+	})
+
+	return sendDiff(diff)
+}
+
+// sortedChanIDs returns the channels of the given set in ascending order.
+func sortedChanIDs(
+	chanIDSet map[lnwire.ShortChannelID]struct{}) []lnwire.ShortChannelID {
+
+	chanIDs := make([]lnwire.ShortChannelID, 0, len(chanIDSet))
+	for chanID := range chanIDSet {
+		chanIDs = append(chanIDs, chanID)
+	}
+	sort.Slice(chanIDs, func(i, j int) bool {
+		return chanIDs[i].ToUint64() < chanIDs[j].ToUint64()
 	})
+
+	return chanIDs
 }
 
 // ApplyGossipFilter applies a gossiper filter sent by the remote node to the
@@ -1226,7 +1716,7 @@ func (g *GossipSyncer) ApplyGossipFilter(filter *lnwire.GossipTimestampRange) er
 		defer g.wg.Done()
 
 		for _, msg := range newUpdatestoSend {
-			err := g.cfg.sendToPeerSync(msg)
+			err := g.sendToPeerSync(msg)
 			switch {
 			case err == ErrGossipSyncerExiting:
 				return
@@ -1267,8 +1757,6 @@ func (g *GossipSyncer) FilterGossipMsgs(msgs ...msgWithSenders) {
 	// TODO(roasbeef): need to ensure that peer still online...send msg to
 	// gossiper on peer termination to signal peer disconnect?
 
-	var err error
-
 	// Before we filter out the messages, we'll construct an index over the
 	// set of channel announcements and channel updates. This will allow us
 	// to quickly check if we should forward a chan ann, based on the known
@@ -1300,6 +1788,35 @@ func (g *GossipSyncer) FilterGossipMsgs(msgs ...msgWithSenders) {
 			(t.After(startTime) && t.Before(endTime))
 	}
 
+	// chanAnnPassesFilter determines whether the announcement of the given
+	// channel should be sent, based on the timestamps of its updates.
+	chanAnnPassesFilter := func(scid lnwire.ShortChannelID) bool {
+		// First, we'll check if the channel updates are in this
+		// message batch.
+		chanUpdates, ok := chanUpdateIndex[scid]
+		if !ok {
+			// If not, we'll attempt to query the database to see
+			// if we know of the updates.
+			var err error
+			chanUpdates, err = g.cfg.channelSeries.FetchChanUpdates(
+				g.cfg.chainHash, scid,
+			)
+			if err != nil {
+				log.Warnf("no channel updates found for "+
+					"short_chan_id=%v", scid)
+				return false
+			}
+		}
+
+		for _, chanUpdate := range chanUpdates {
+			if passesFilter(chanUpdate.Timestamp) {
+				return true
+			}
+		}
+
+		return len(chanUpdates) == 0
+	}
+
 	msgsToSend := make([]lnwire.Message, 0, len(msgs))
 	for _, msg := range msgs {
 		// If the target peer is the peer that sent us this message,
@@ -1315,31 +1832,13 @@ func (g *GossipSyncer) FilterGossipMsgs(msgs ...msgWithSenders) {
 		// message if the channel updates for the channel are between
 		// our time range.
 		case *lnwire.ChannelAnnouncement:
-			// First, we'll check if the channel updates are in
-			// this message batch.
-			chanUpdates, ok := chanUpdateIndex[msg.ShortChannelID]
-			if !ok {
-				// If not, we'll attempt to query the database
-				// to see if we know of the updates.
-				chanUpdates, err = g.cfg.channelSeries.FetchChanUpdates(
-					g.cfg.chainHash, msg.ShortChannelID,
-				)
-				if err != nil {
-					log.Warnf("no channel updates found for "+
-						"short_chan_id=%v",
-						msg.ShortChannelID)
-					continue
-				}
-			}
-
-			for _, chanUpdate := range chanUpdates {
-				if passesFilter(chanUpdate.Timestamp) {
-					msgsToSend = append(msgsToSend, msg)
-					break
-				}
+			if chanAnnPassesFilter(msg.ShortChannelID) {
+				msgsToSend = append(msgsToSend, msg)
 			}
 
-			if len(chanUpdates) == 0 {
+		// Taproot channel announcements are filtered the same way.
+		case *lnwire.ChannelAnnouncement2:
+			if chanAnnPassesFilter(msg.ShortChannelID) {
 				msgsToSend = append(msgsToSend, msg)
 			}
 
@@ -1359,6 +1858,22 @@ func (g *GossipSyncer) FilterGossipMsgs(msgs ...msgWithSenders) {
 		}
 	}
 
+	// Relaying announcements isn't essential to the peer, as it can
+	// always query for them later on, so we'll drop the ones that don't
+	// fit into its outbound budget.
+	allowedMsgs := msgsToSend[:0]
+	for _, msg := range msgsToSend {
+		if g.cfg.budget.allowOutbound(msg) {
+			allowedMsgs = append(allowedMsgs, msg)
+		}
+	}
+	if len(allowedMsgs) != len(msgsToSend) {
+		log.Debugf("GossipSyncer(%x): dropped %v gossip msgs "+
+			"exceeding outbound budget", g.cfg.peerPub[:],
+			len(msgsToSend)-len(allowedMsgs))
+	}
+	msgsToSend = allowedMsgs
+
 	log.Tracef("GossipSyncer(%x): filtered gossip msgs: set=%v, sent=%v",
 		g.cfg.peerPub[:], len(msgs), len(msgsToSend))
 
@@ -1375,6 +1890,11 @@ func (g *GossipSyncer) ProcessQueryMsg(msg lnwire.Message, peerQuit <-chan struc
 	var msgChan chan lnwire.Message
 	switch msg.(type) {
 	case *lnwire.QueryChannelRange, *lnwire.QueryShortChanIDs:
+		g.countReceived(LegacySync, msg)
+		msgChan = g.queryMsgs
+
+	case *lnwire.ReconcileSketch:
+		g.countReceived(ReconcileSync, msg)
 		msgChan = g.queryMsgs
 
 	// Reply messages should only be expected in states where we're waiting
@@ -1387,6 +1907,15 @@ func (g *GossipSyncer) ProcessQueryMsg(msg lnwire.Message, peerQuit <-chan struc
 			return fmt.Errorf("received unexpected query reply "+
 				"message %T", msg)
 		}
+		g.countReceived(LegacySync, msg)
+		msgChan = g.gossipMsgs
+
+	case *lnwire.ReconcileDiff:
+		if g.syncState() != waitingReconcileReply {
+			return fmt.Errorf("received unexpected reconcile "+
+				"reply message %T", msg)
+		}
+		g.countReceived(ReconcileSync, msg)
 		msgChan = g.gossipMsgs
 
 	default:
@@ -1412,6 +1941,18 @@ func (g *GossipSyncer) syncState() syncerState {
 	return syncerState(atomic.LoadUint32(&g.state))
 }
 
+// awaitingReplies returns true if we've queried the remote peer for
+// announcements, and are waiting for it to send them to us.
+func (g *GossipSyncer) awaitingReplies() bool {
+	switch g.syncState() {
+	case waitingQueryChanReply, waitingReconcileReply:
+		return true
+
+	default:
+		return false
+	}
+}
+
 // ResetSyncedSignal returns a channel that will be closed in order to serve as
 // a signal for when the GossipSyncer has reached its chansSynced state.
 func (g *GossipSyncer) ResetSyncedSignal() chan struct{} {
@@ -1551,3 +2092,86 @@ func (g *GossipSyncer) handleHistoricalSync(req *historicalSyncReq) {
 	g.setSyncState(syncingChans)
 	close(req.doneChan)
 }
+
+// msgSize returns the size of the given message on the wire, excluding the
+// overhead of the transport.
+func msgSize(msg lnwire.Message) uint64 {
+	var b bytes.Buffer
+	n, err := lnwire.WriteMessage(&b, msg, 0)
+	if err != nil {
+		return 0
+	}
+
+	return uint64(n)
+}
+
+// sendToPeerSync sends the given messages to the remote peer, blocking until
+// they've been sent. As these messages can't be dropped, we'll first wait
+// until they fit into the outbound gossip budget.
+func (g *GossipSyncer) sendToPeerSync(msgs ...lnwire.Message) error {
+	if !g.cfg.budget.waitOutbound(g.quit, msgs...) {
+		return ErrGossipSyncerExiting
+	}
+
+	return g.cfg.sendToPeerSync(msgs...)
+}
+
+// countSent accounts the given messages sent to the remote peer towards the
+// bandwidth of the given sync mode.
+func (g *GossipSyncer) countSent(mode SyncMode, msgs ...lnwire.Message) {
+	var size uint64
+	for _, msg := range msgs {
+		size += msgSize(msg)
+	}
+
+	g.bandwidthMtx.Lock()
+	defer g.bandwidthMtx.Unlock()
+
+	bandwidth := g.bandwidth[mode]
+	bandwidth.MsgsSent += uint64(len(msgs))
+	bandwidth.BytesSent += size
+	g.bandwidth[mode] = bandwidth
+}
+
+// countReceived accounts the given message received from the remote peer
+// towards the bandwidth of the given sync mode.
+func (g *GossipSyncer) countReceived(mode SyncMode, msg lnwire.Message) {
+	size := msgSize(msg)
+
+	g.bandwidthMtx.Lock()
+	defer g.bandwidthMtx.Unlock()
+
+	bandwidth := g.bandwidth[mode]
+	bandwidth.MsgsReceived++
+	bandwidth.BytesReceived += size
+	g.bandwidth[mode] = bandwidth
+}
+
+// BudgetUsage returns the gossip exchanged with the remote peer and how it was
+// affected by the bandwidth budgets.
+func (g *GossipSyncer) BudgetUsage() GossipBudgetUsage {
+	return g.cfg.budget.usage()
+}
+
+// bandwidthFor returns the bandwidth used to sync with the remote peer using
+// the given sync mode.
+func (g *GossipSyncer) bandwidthFor(mode SyncMode) SyncBandwidth {
+	g.bandwidthMtx.Lock()
+	defer g.bandwidthMtx.Unlock()
+
+	return g.bandwidth[mode]
+}
+
+// Bandwidth returns the bandwidth used to sync with the remote peer, for each
+// sync mode that has been used.
+func (g *GossipSyncer) Bandwidth() map[SyncMode]SyncBandwidth {
+	g.bandwidthMtx.Lock()
+	defer g.bandwidthMtx.Unlock()
+
+	bandwidth := make(map[SyncMode]SyncBandwidth, len(g.bandwidth))
+	for mode, b := range g.bandwidth {
+		bandwidth[mode] = b
+	}
+
+	return bandwidth
+}
@@ -53,6 +53,8 @@ type mockChannelGraphTimeSeries struct {
 
 	updateReq  chan lnwire.ShortChannelID
 	updateResp chan []*lnwire.ChannelUpdate
+
+	updateTimestamps []ChanUpdateTimestamp
 }
 
 func newMockChannelGraphTimeSeries(
@@ -144,6 +146,12 @@ func (m *mockChannelGraphTimeSeries) FetchChanUpdates(chain chainhash.Hash,
 	return <-m.updateResp, nil
 }
 
+func (m *mockChannelGraphTimeSeries) ChanUpdateTimestamps(
+	chain chainhash.Hash) ([]ChanUpdateTimestamp, error) {
+
+	return m.updateTimestamps, nil
+}
+
 var _ ChannelGraphTimeSeries = (*mockChannelGraphTimeSeries)(nil)
 
 // newTestSyncer creates a new test instance of a GossipSyncer. A buffered
@@ -2396,3 +2404,265 @@ func TestGossipSyncerMaxChannelRangeReplies(t *testing.T) {
 		},
 	}, nil))
 }
+
+// TestGossipSyncerReplyReconcileSketch tests that in response to a sketch of
+// the remote peer's channel updates, we'll send them the updates they're
+// missing or only know an older version of, followed by a ReconcileDiff
+// requesting the updates we're missing.
+func TestGossipSyncerReplyReconcileSketch(t *testing.T) {
+	t.Parallel()
+
+	msgChan, syncer, chanSeries := newTestSyncer(
+		lnwire.NewShortChanIDFromInt(10), defaultEncoding,
+		defaultChunkSize,
+	)
+	syncer.cfg.reconcile = true
+
+	var (
+		chan1 = lnwire.NewShortChanIDFromInt(1)
+		chan2 = lnwire.NewShortChanIDFromInt(2)
+		chan3 = lnwire.NewShortChanIDFromInt(3)
+		chan4 = lnwire.NewShortChanIDFromInt(4)
+		chan5 = lnwire.NewShortChanIDFromInt(5)
+	)
+
+	// Both of us know the same version of the update of the first
+	// channel. We know a newer update for the second channel, the remote
+	// peer knows a newer one for the fifth channel, and each of us knows
+	// a channel the other one doesn't.
+	chanSeries.updateTimestamps = []ChanUpdateTimestamp{
+		{ShortChannelID: chan1, Direction: 0, Timestamp: 100},
+		{ShortChannelID: chan2, Direction: 0, Timestamp: 200},
+		{ShortChannelID: chan3, Direction: 1, Timestamp: 300},
+		{ShortChannelID: chan5, Direction: 0, Timestamp: 200},
+	}
+
+	remoteSketch := newSetSketch(60)
+	remoteSketch.add(chanUpdateElement(chan1, 0, 100))
+	remoteSketch.add(chanUpdateElement(chan2, 0, 100))
+	remoteSketch.add(chanUpdateElement(chan4, 0, 400))
+	remoteSketch.add(chanUpdateElement(chan5, 0, 300))
+
+	// As the remote peer doesn't know of the third channel, we'll send
+	// them its announcements. For the second channel, we'll only send the
+	// update they're missing.
+	chan3Anns := []lnwire.Message{
+		&lnwire.ChannelAnnouncement{ShortChannelID: chan3},
+		&lnwire.ChannelUpdate{
+			ShortChannelID: chan3,
+			ChannelFlags:   lnwire.ChanUpdateDirection,
+			Timestamp:      300,
+		},
+	}
+	chanSeries.annResp <- chan3Anns
+
+	chan2Update := &lnwire.ChannelUpdate{
+		ShortChannelID: chan2,
+		Timestamp:      200,
+	}
+	chanSeries.updateResp <- []*lnwire.ChannelUpdate{
+		chan2Update,
+		{
+			ShortChannelID: chan2,
+			ChannelFlags:   lnwire.ChanUpdateDirection,
+			Timestamp:      150,
+		},
+	}
+
+	err := syncer.replyReconcileSketch(lnwire.NewReconcileSketch(
+		syncer.cfg.chainHash, remoteSketch.serialize(),
+	))
+	require.NoError(t, err)
+
+	require.Equal(t, []lnwire.ShortChannelID{chan3}, <-chanSeries.annReq)
+	require.Equal(t, chan2, <-chanSeries.updateReq)
+
+	// Finally, we'll ask for the fourth channel, and the newer update of
+	// the fifth one.
+	requested := []uint64{
+		chanUpdateElement(chan4, 0, 400),
+		chanUpdateElement(chan5, 0, 300),
+	}
+	sort.Slice(requested, func(i, j int) bool {
+		return requested[i] < requested[j]
+	})
+
+	expectedMsgs := []lnwire.Message{
+		chan3Anns[0], chan3Anns[1], chan2Update, &lnwire.ReconcileDiff{
+			ChainHash: syncer.cfg.chainHash,
+			Complete:  1,
+			Elements:  requested,
+		},
+	}
+	for _, expectedMsg := range expectedMsgs {
+		select {
+		case msgs := <-msgChan:
+			require.Equal(t, []lnwire.Message{expectedMsg}, msgs)
+
+		case <-time.After(time.Second * 15):
+			t.Fatalf("expected to send %T", expectedMsg)
+		}
+	}
+
+	bandwidth := syncer.Bandwidth()
+	require.EqualValues(t, 4, bandwidth[ReconcileSync].MsgsSent)
+	require.NotZero(t, bandwidth[ReconcileSync].BytesSent)
+	require.Zero(t, bandwidth[LegacySync].MsgsSent)
+
+	// A sketch with a difference that's too large to decode makes us ask
+	// the remote peer to fall back to channel range queries.
+	remoteSketch = newSetSketch(3)
+	for i := uint64(0); i < 10; i++ {
+		remoteSketch.add(i)
+	}
+
+	err = syncer.replyReconcileSketch(lnwire.NewReconcileSketch(
+		syncer.cfg.chainHash, remoteSketch.serialize(),
+	))
+	require.NoError(t, err)
+
+	select {
+	case msgs := <-msgChan:
+		require.Equal(t, []lnwire.Message{&lnwire.ReconcileDiff{
+			ChainHash: syncer.cfg.chainHash,
+		}}, msgs)
+
+	case <-time.After(time.Second * 15):
+		t.Fatalf("expected to send ReconcileDiff")
+	}
+}
+
+// TestGossipSyncerReconcileSync tests that a syncer that negotiated set
+// reconciliation syncs by sending a sketch of its channel updates, replies to
+// the updates requested by the remote peer, and falls back to channel range
+// queries if the remote peer is unable to decode its sketch.
+func TestGossipSyncerReconcileSync(t *testing.T) {
+	t.Parallel()
+
+	newReconcileSyncer := func() (chan []lnwire.Message, *GossipSyncer,
+		*mockChannelGraphTimeSeries) {
+
+		msgChan, syncer, chanSeries := newTestSyncer(
+			lnwire.NewShortChanIDFromInt(10), defaultEncoding,
+			defaultChunkSize,
+		)
+		syncer.cfg.reconcile = true
+		syncer.cfg.sketchCells = 60
+		syncer.setSyncState(syncingChans)
+		syncer.setSyncType(PassiveSync)
+
+		return msgChan, syncer, chanSeries
+	}
+
+	chan1 := lnwire.NewShortChanIDFromInt(1)
+	updateTimestamps := []ChanUpdateTimestamp{
+		{ShortChannelID: chan1, Direction: 0, Timestamp: 100},
+		{ShortChannelID: chan1, Direction: 1, Timestamp: 200},
+	}
+
+	// receiveSketch waits for the syncer to send its sketch, and asserts
+	// that it covers exactly our set of channel updates.
+	receiveSketch := func(msgChan chan []lnwire.Message,
+		syncer *GossipSyncer) {
+
+		var msgs []lnwire.Message
+		select {
+		case msgs = <-msgChan:
+		case <-time.After(time.Second * 15):
+			t.Fatalf("expected to send ReconcileSketch")
+		}
+		require.Len(t, msgs, 1)
+		require.IsType(t, &lnwire.ReconcileSketch{}, msgs[0])
+
+		sketch, err := deserializeSetSketch(
+			msgs[0].(*lnwire.ReconcileSketch).Sketch,
+		)
+		require.NoError(t, err)
+
+		expected := newSetSketch(sketch.numCells())
+		for _, ts := range updateTimestamps {
+			expected.add(chanUpdateElement(
+				ts.ShortChannelID, ts.Direction, ts.Timestamp,
+			))
+		}
+		require.Equal(t, expected, sketch)
+
+		require.Eventually(t, func() bool {
+			return syncer.syncState() == waitingReconcileReply
+		}, time.Second*15, time.Millisecond*10)
+	}
+
+	// If the remote peer is able to reconcile our sets, we'll send them
+	// the channels they requested and consider ourselves synced.
+	msgChan, syncer, chanSeries := newReconcileSyncer()
+	chanSeries.updateTimestamps = updateTimestamps
+	syncer.Start()
+	defer syncer.Stop()
+
+	receiveSketch(msgChan, syncer)
+
+	chanAnns := []lnwire.Message{
+		&lnwire.ChannelAnnouncement{ShortChannelID: chan1},
+	}
+	chanSeries.annResp <- chanAnns
+
+	err := syncer.ProcessQueryMsg(&lnwire.ReconcileDiff{
+		Complete: 1,
+		Elements: []uint64{
+			chanUpdateElement(chan1, 1, 200),
+
+			// Unknown elements are ignored.
+			chanUpdateElement(chan1, 1, 300),
+		},
+	}, nil)
+	require.NoError(t, err)
+
+	select {
+	case chanIDs := <-chanSeries.annReq:
+		require.Equal(t, []lnwire.ShortChannelID{chan1}, chanIDs)
+
+	case <-time.After(time.Second * 15):
+		t.Fatalf("expected chan ann request")
+	}
+
+	select {
+	case msgs := <-msgChan:
+		require.Equal(t, chanAnns, msgs)
+
+	case <-time.After(time.Second * 15):
+		t.Fatalf("expected to send chan anns")
+	}
+
+	require.Eventually(t, func() bool {
+		return syncer.syncState() == chansSynced
+	}, time.Second*15, time.Millisecond*10)
+
+	bandwidth := syncer.Bandwidth()
+	require.EqualValues(t, 2, bandwidth[ReconcileSync].MsgsSent)
+	require.EqualValues(t, 1, bandwidth[ReconcileSync].MsgsReceived)
+
+	// If the remote peer is unable to reconcile our sets, we'll fall back
+	// to a channel range query.
+	msgChan, syncer, chanSeries = newReconcileSyncer()
+	chanSeries.updateTimestamps = updateTimestamps
+	syncer.Start()
+	defer syncer.Stop()
+
+	receiveSketch(msgChan, syncer)
+
+	err = syncer.ProcessQueryMsg(&lnwire.ReconcileDiff{}, nil)
+	require.NoError(t, err)
+
+	select {
+	case msgs := <-msgChan:
+		require.Len(t, msgs, 1)
+		require.IsType(t, &lnwire.QueryChannelRange{}, msgs[0])
+
+	case <-time.After(time.Second * 15):
+		t.Fatalf("expected to send QueryChannelRange")
+	}
+
+	require.Eventually(t, func() bool {
+		return syncer.syncState() == waitingQueryRangeReply
+	}, time.Second*15, time.Millisecond*10)
+}
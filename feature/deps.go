@@ -2,6 +2,7 @@ package feature
 
 import (
 	"fmt"
+	"sort"
 
 	"github.com/lightningnetwork/lnd/lnwire"
 )
@@ -75,6 +76,15 @@ var deps = depDesc{
 	lnwire.ZeroConfOptional: {
 		lnwire.ScidAliasOptional: {},
 	},
+	lnwire.SimpleCloseOptional: {
+		lnwire.ShutdownAnySegwitOptional: {},
+	},
+	lnwire.DynamicCommitmentsOptional: {
+		lnwire.QuiescenceOptional: {},
+	},
+	lnwire.GossipReconcileOptional: {
+		lnwire.GossipQueriesOptional: {},
+	},
 }
 
 // ValidateDeps asserts that a feature vector sets all features and their
@@ -147,3 +157,44 @@ func mapToOptional(bit lnwire.FeatureBit) lnwire.FeatureBit {
 	}
 	return bit
 }
+
+// Dependencies returns the features the given feature directly depends on,
+// expressed as optional bits and sorted by bit.
+func Dependencies(bit lnwire.FeatureBit) []lnwire.FeatureBit {
+	return sortedBits(deps[mapToOptional(bit)])
+}
+
+// DependencyChain returns all features the given feature depends on, either
+// directly or transitively, expressed as optional bits and sorted by bit.
+func DependencyChain(bit lnwire.FeatureBit) []lnwire.FeatureBit {
+	chain := make(featureSet)
+
+	var collect func(lnwire.FeatureBit)
+	collect = func(bit lnwire.FeatureBit) {
+		for dep := range deps[mapToOptional(bit)] {
+			if _, ok := chain[dep]; ok {
+				continue
+			}
+
+			chain[dep] = struct{}{}
+			collect(dep)
+		}
+	}
+	collect(bit)
+
+	return sortedBits(chain)
+}
+
+// sortedBits returns the bits of a feature set in ascending order.
+func sortedBits(features featureSet) []lnwire.FeatureBit {
+	bits := make([]lnwire.FeatureBit, 0, len(features))
+	for bit := range features {
+		bits = append(bits, bit)
+	}
+
+	sort.Slice(bits, func(i, j int) bool {
+		return bits[i] < bits[j]
+	})
+
+	return bits
+}
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/stretchr/testify/require"
 )
 
 type depTest struct {
@@ -164,3 +165,25 @@ func testValidateDeps(t *testing.T, test depTest) {
 			test.expErr, err)
 	}
 }
+
+// TestDependencyChain asserts that the direct and transitive dependencies of
+// features are reported as optional bits.
+func TestDependencyChain(t *testing.T) {
+	t.Parallel()
+
+	require.Empty(t, Dependencies(lnwire.TLVOnionPayloadOptional))
+	require.Empty(t, DependencyChain(lnwire.TLVOnionPayloadRequired))
+
+	require.Equal(t, []lnwire.FeatureBit{
+		lnwire.PaymentAddrOptional,
+	}, Dependencies(lnwire.MPPRequired))
+	require.Equal(t, []lnwire.FeatureBit{
+		lnwire.TLVOnionPayloadOptional, lnwire.PaymentAddrOptional,
+	}, DependencyChain(lnwire.MPPRequired))
+
+	require.Equal(t, []lnwire.FeatureBit{
+		lnwire.StaticRemoteKeyOptional,
+		lnwire.AnchorsZeroFeeHtlcTxOptional,
+		lnwire.ExplicitChannelTypeOptional,
+	}, DependencyChain(lnwire.ScriptEnforcedLeaseOptional))
+}
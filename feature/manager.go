@@ -3,6 +3,8 @@ package feature
 import (
 	"errors"
 	"fmt"
+	"sort"
+	"sync"
 
 	"github.com/lightningnetwork/lnd/lnwire"
 )
@@ -15,6 +17,14 @@ var (
 	// ErrFeatureConfigured is returned if an attempt is made to unset a
 	// feature that was configured at startup.
 	ErrFeatureConfigured = errors.New("can't unset configured feature")
+
+	// ErrRequiredFeature is returned if an attempt is made to toggle a
+	// required feature bit at runtime.
+	ErrRequiredFeature = errors.New("can't toggle required feature bit")
+
+	// ErrFeatureUnsupported is returned if an attempt is made to enable a
+	// standard feature in a set that it wasn't advertised in at startup.
+	ErrFeatureUnsupported = errors.New("feature not supported in set")
 )
 
 // Config houses any runtime modifications to the default set descriptors. For
@@ -56,6 +66,27 @@ type Config struct {
 	// segwit witness versions for co-op closes.
 	NoAnySegwit bool
 
+	// NoPeerStorage unsets any bits that signal support for storing
+	// backup blobs on behalf of our channel peers.
+	NoPeerStorage bool
+
+	// NoRbfCoopClose unsets any bits that signal support for the simple
+	// cooperative close protocol, which allows closing transactions to
+	// be replaced with higher fee versions.
+	NoRbfCoopClose bool
+
+	// NoQuiescence unsets any bits that signal support for the stfu
+	// message, which is used to make a channel quiescent.
+	NoQuiescence bool
+
+	// NoDynamicCommitments unsets any bits that signal support for
+	// upgrading the commitment of an open channel.
+	NoDynamicCommitments bool
+
+	// NoGossipReconcile unsets any bits that signal support for
+	// synchronizing channel updates using set reconciliation.
+	NoGossipReconcile bool
+
 	// CustomFeatures is a set of custom features to advertise in each
 	// set.
 	CustomFeatures map[Set][]lnwire.FeatureBit
@@ -64,10 +95,18 @@ type Config struct {
 // Manager is responsible for generating feature vectors for different requested
 // feature sets.
 type Manager struct {
-	// fsets is a static map of feature set to raw feature vectors. Requests
-	// are fulfilled by cloning these internal feature vectors.
+	// mu protects fsets, which can be updated at runtime.
+	mu sync.RWMutex
+
+	// fsets is a map of feature set to raw feature vectors. Requests are
+	// fulfilled by cloning these internal feature vectors.
 	fsets map[Set]*lnwire.RawFeatureVector
 
+	// startupSets holds the feature vectors of each set as they were
+	// built at startup. Standard features can only be enabled at runtime
+	// in the sets they were advertised in at startup.
+	startupSets map[Set]*lnwire.RawFeatureVector
+
 	// configFeatures is a set of custom features that were "hard set" in
 	// lnd's config that cannot be updated at runtime (as is the case with
 	// our "standard" features that are defined in LND).
@@ -171,6 +210,26 @@ func newManager(cfg Config, desc setDesc) (*Manager, error) {
 			raw.Unset(lnwire.ShutdownAnySegwitOptional)
 			raw.Unset(lnwire.ShutdownAnySegwitRequired)
 		}
+		if cfg.NoPeerStorage {
+			raw.Unset(lnwire.ProvideStorageOptional)
+			raw.Unset(lnwire.ProvideStorageRequired)
+		}
+		if cfg.NoRbfCoopClose {
+			raw.Unset(lnwire.SimpleCloseOptional)
+			raw.Unset(lnwire.SimpleCloseRequired)
+		}
+		if cfg.NoQuiescence {
+			raw.Unset(lnwire.QuiescenceOptional)
+			raw.Unset(lnwire.QuiescenceRequired)
+		}
+		if cfg.NoDynamicCommitments {
+			raw.Unset(lnwire.DynamicCommitmentsOptional)
+			raw.Unset(lnwire.DynamicCommitmentsRequired)
+		}
+		if cfg.NoGossipReconcile {
+			raw.Unset(lnwire.GossipReconcileOptional)
+			raw.Unset(lnwire.GossipReconcileRequired)
+		}
 
 		for _, custom := range cfg.CustomFeatures[set] {
 			if custom > set.Maximum() {
@@ -208,8 +267,14 @@ func newManager(cfg Config, desc setDesc) (*Manager, error) {
 		}
 	}
 
+	startupSets := make(map[Set]*lnwire.RawFeatureVector, len(fsets))
+	for set, raw := range fsets {
+		startupSets[set] = raw.Clone()
+	}
+
 	return &Manager{
 		fsets:          fsets,
+		startupSets:    startupSets,
 		configFeatures: configFeatures,
 	}, nil
 }
@@ -217,6 +282,15 @@ func newManager(cfg Config, desc setDesc) (*Manager, error) {
 // GetRaw returns a raw feature vector for the passed set. If no set is known,
 // an empty raw feature vector is returned.
 func (m *Manager) GetRaw(set Set) *lnwire.RawFeatureVector {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.getRaw(set)
+}
+
+// getRaw returns a copy of the raw feature vector for the passed set. The
+// caller MUST hold the mutex.
+func (m *Manager) getRaw(set Set) *lnwire.RawFeatureVector {
 	if fv, ok := m.fsets[set]; ok {
 		return fv.Clone()
 	}
@@ -224,7 +298,8 @@ func (m *Manager) GetRaw(set Set) *lnwire.RawFeatureVector {
 	return lnwire.NewRawFeatureVector()
 }
 
-// setRaw sets a new raw feature vector for the given set.
+// setRaw sets a new raw feature vector for the given set. The caller MUST
+// hold the mutex.
 func (m *Manager) setRaw(set Set, raw *lnwire.RawFeatureVector) {
 	m.fsets[set] = raw
 }
@@ -238,6 +313,9 @@ func (m *Manager) Get(set Set) *lnwire.FeatureVector {
 
 // ListSets returns a list of the feature sets that our node supports.
 func (m *Manager) ListSets() []Set {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
 	var sets []Set
 
 	for set := range m.fsets {
@@ -255,6 +333,9 @@ func (m *Manager) ListSets() []Set {
 func (m *Manager) UpdateFeatureSets(
 	updates map[Set]*lnwire.RawFeatureVector) error {
 
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
 	for set, newFeatures := range updates {
 		if !set.valid() {
 			return fmt.Errorf("%w: set: %d", ErrUnknownSet, set)
@@ -264,7 +345,7 @@ func (m *Manager) UpdateFeatureSets(
 			return err
 		}
 
-		if err := m.Get(set).ValidateUpdate(
+		if err := m.getRaw(set).ValidateUpdate(
 			newFeatures, set.Maximum(),
 		); err != nil {
 			return err
@@ -297,3 +378,207 @@ func (m *Manager) UpdateFeatureSets(
 
 	return nil
 }
+
+// Toggle describes enabling or disabling an optional feature bit in a set.
+type Toggle struct {
+	// Set is the set the feature bit is toggled in.
+	Set Set
+
+	// Bit is the optional feature bit to toggle.
+	Bit lnwire.FeatureBit
+
+	// Enable is true if the bit should be set, and false if it should be
+	// unset.
+	Enable bool
+}
+
+// ToggleFeatures enables or disables optional feature bits at runtime.
+// Standard features can only be enabled in the sets they were advertised in
+// at startup, and features configured at startup can't be disabled. Required
+// bits can't be toggled at all. The resulting sets must satisfy all feature
+// dependencies. Either all toggles are applied or none, and the updated
+// feature vectors are returned by set.
+func (m *Manager) ToggleFeatures(
+	toggles []Toggle) (map[Set]*lnwire.RawFeatureVector, error) {
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	updates := make(map[Set]*lnwire.RawFeatureVector)
+	for _, toggle := range toggles {
+		set, bit := toggle.Set, toggle.Bit
+
+		if !set.valid() {
+			return nil, fmt.Errorf("%w: set: %d", ErrUnknownSet,
+				set)
+		}
+
+		if bit.IsRequired() {
+			return nil, fmt.Errorf("%w: %d", ErrRequiredFeature,
+				bit)
+		}
+
+		raw, ok := updates[set]
+		if !ok {
+			raw = m.getRaw(set)
+			updates[set] = raw
+		}
+
+		if !toggle.Enable {
+			cfgFeatures, ok := m.configFeatures[set]
+			if ok && cfgFeatures.IsSet(bit) {
+				return nil, fmt.Errorf("%w: %d",
+					ErrFeatureConfigured, bit)
+			}
+
+			raw.Unset(bit)
+			continue
+		}
+
+		if bit > set.Maximum() {
+			return nil, fmt.Errorf("feature bit %d: %w %v", bit,
+				lnwire.ErrFeatureBitMaximum, set.Maximum())
+		}
+
+		_, known := lnwire.Features[bit]
+		if known && !m.supportedAtStartup(set, bit) {
+			return nil, fmt.Errorf("%w %v: %d",
+				ErrFeatureUnsupported, set, bit)
+		}
+
+		if err := raw.SafeSet(bit); err != nil {
+			return nil, fmt.Errorf("unable to set feature bit "+
+				"%d: %w", bit, err)
+		}
+	}
+
+	for set, raw := range updates {
+		fv := lnwire.NewFeatureVector(raw, lnwire.Features)
+		if err := ValidateDeps(fv); err != nil {
+			return nil, fmt.Errorf("invalid update of %v: %w", set,
+				err)
+		}
+	}
+
+	for set, raw := range updates {
+		m.setRaw(set, raw.Clone())
+	}
+
+	return updates, nil
+}
+
+// supportedAtStartup returns true if either variant of the given feature bit
+// was set in the set at startup. The caller MUST hold the mutex.
+func (m *Manager) supportedAtStartup(set Set, bit lnwire.FeatureBit) bool {
+	startup, ok := m.startupSets[set]
+	if !ok {
+		return false
+	}
+
+	optional := mapToOptional(bit)
+
+	return startup.IsSet(optional) || startup.IsSet(optional^0x01)
+}
+
+// Status describes a feature of a set.
+type Status struct {
+	// Bit is the feature bit that is set, or the optional bit of the
+	// feature if neither variant is set.
+	Bit lnwire.FeatureBit
+
+	// Name is the name of the feature, if known.
+	Name string
+
+	// Known is true if the feature is a standard one known to lnd.
+	Known bool
+
+	// Enabled is true if either variant of the feature bit is set.
+	Enabled bool
+
+	// Configured is true if the feature was configured as a custom feature
+	// at startup, and thus can't be disabled.
+	Configured bool
+
+	// Toggleable is true if the feature can be enabled or disabled at
+	// runtime using ToggleFeatures, as long as the dependencies of the
+	// resulting set are satisfied.
+	Toggleable bool
+
+	// Dependencies are the features this feature directly depends on.
+	Dependencies []lnwire.FeatureBit
+
+	// DependencyChain are all the features this feature depends on,
+	// directly or transitively.
+	DependencyChain []lnwire.FeatureBit
+}
+
+// ListFeatures returns the status of all standard features, and any custom
+// features set, in the given set, sorted by feature bit.
+func (m *Manager) ListFeatures(set Set) []Status {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	raw := m.getRaw(set)
+	cfgFeatures := m.configFeatures[set]
+
+	// Collect the optional bits of all features we know of or that are
+	// set.
+	optionalBits := make(map[lnwire.FeatureBit]struct{})
+	for bit := range lnwire.Features {
+		optionalBits[mapToOptional(bit)] = struct{}{}
+	}
+	fv := lnwire.NewFeatureVector(raw, lnwire.Features)
+	for bit := range fv.Features() {
+		optionalBits[mapToOptional(bit)] = struct{}{}
+	}
+
+	statuses := make([]Status, 0, len(optionalBits))
+	for optional := range optionalBits {
+		required := optional ^ 0x01
+
+		status := Status{
+			Bit:             optional,
+			Dependencies:    Dependencies(optional),
+			DependencyChain: DependencyChain(optional),
+		}
+		switch {
+		case raw.IsSet(required):
+			status.Bit = required
+			status.Enabled = true
+
+		case raw.IsSet(optional):
+			status.Enabled = true
+		}
+
+		status.Name, status.Known = lnwire.Features[status.Bit]
+		if !status.Known {
+			status.Name = "unknown"
+		}
+
+		status.Configured = cfgFeatures != nil &&
+			cfgFeatures.IsSet(status.Bit)
+
+		// Required and configured features can never be toggled,
+		// while enabled and custom features can always be. Standard
+		// features can only be enabled where they were at startup.
+		switch {
+		case status.Bit.IsRequired(), status.Configured:
+
+		case status.Enabled, !status.Known:
+			status.Toggleable = true
+
+		default:
+			status.Toggleable = m.supportedAtStartup(
+				set, status.Bit,
+			)
+		}
+
+		statuses = append(statuses, status)
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Bit < statuses[j].Bit
+	})
+
+	return statuses
+}
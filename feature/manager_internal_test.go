@@ -289,3 +289,111 @@ func TestUpdateFeatureSets(t *testing.T) {
 		})
 	}
 }
+
+// TestToggleFeatures asserts that optional features can be toggled at runtime
+// within the constraints of the startup configuration and the feature
+// dependencies, and that failed toggles leave the sets unchanged.
+func TestToggleFeatures(t *testing.T) {
+	t.Parallel()
+
+	setDesc := setDesc{
+		lnwire.DataLossProtectRequired: {
+			SetInit: {},
+		},
+		lnwire.TLVOnionPayloadOptional: {
+			SetInit:    {},
+			SetNodeAnn: {},
+		},
+		lnwire.PaymentAddrOptional: {
+			SetInit: {},
+		},
+	}
+	customBit := lnwire.FeatureBit(1001)
+	featureMgr, err := newManager(Config{
+		CustomFeatures: map[Set][]lnwire.FeatureBit{
+			SetInit: {customBit},
+		},
+	}, setDesc)
+	require.NoError(t, err)
+
+	toggle := func(toggles ...Toggle) error {
+		_, err := featureMgr.ToggleFeatures(toggles)
+		return err
+	}
+
+	// Required bits, configured features and features that weren't
+	// advertised in a set at startup can't be toggled.
+	require.ErrorIs(t, toggle(Toggle{
+		Set: SetInit, Bit: lnwire.DataLossProtectRequired,
+	}), ErrRequiredFeature)
+	require.ErrorIs(t, toggle(Toggle{
+		Set: SetInit, Bit: customBit,
+	}), ErrFeatureConfigured)
+	require.ErrorIs(t, toggle(Toggle{
+		Set: SetNodeAnn, Bit: lnwire.PaymentAddrOptional, Enable: true,
+	}), ErrFeatureUnsupported)
+	require.ErrorIs(t, toggle(Toggle{
+		Set: setSentinel, Bit: lnwire.PaymentAddrOptional,
+	}), ErrUnknownSet)
+
+	// Disabling a feature others depend on fails, and doesn't apply the
+	// other toggles of the same call.
+	err = toggle(Toggle{
+		Set: SetInit, Bit: lnwire.FeatureBit(2001), Enable: true,
+	}, Toggle{
+		Set: SetInit, Bit: lnwire.TLVOnionPayloadOptional,
+	})
+	require.ErrorAs(t, err, &ErrMissingFeatureDep{})
+	require.False(t, featureMgr.GetRaw(SetInit).IsSet(2001))
+
+	// Disabling the dependent feature as well succeeds, after which both
+	// can be enabled again.
+	updates, err := featureMgr.ToggleFeatures([]Toggle{{
+		Set: SetInit, Bit: lnwire.TLVOnionPayloadOptional,
+	}, {
+		Set: SetInit, Bit: lnwire.PaymentAddrOptional,
+	}})
+	require.NoError(t, err)
+	require.Len(t, updates, 1)
+	require.True(t, updates[SetInit].Equals(lnwire.NewRawFeatureVector(
+		lnwire.DataLossProtectRequired, customBit,
+	)))
+	require.True(t, featureMgr.GetRaw(SetInit).Equals(updates[SetInit]))
+
+	require.NoError(t, toggle(Toggle{
+		Set: SetInit, Bit: lnwire.TLVOnionPayloadOptional, Enable: true,
+	}, Toggle{
+		Set: SetInit, Bit: lnwire.PaymentAddrOptional, Enable: true,
+	}))
+
+	// The status of the features reflects what can be toggled.
+	statuses := make(map[lnwire.FeatureBit]Status)
+	for _, status := range featureMgr.ListFeatures(SetInit) {
+		statuses[status.Bit] = status
+	}
+
+	require.Equal(t, Status{
+		Bit:        lnwire.DataLossProtectRequired,
+		Name:       "data-loss-protect",
+		Known:      true,
+		Enabled:    true,
+		Toggleable: false,
+	}, trimDeps(statuses[lnwire.DataLossProtectRequired]))
+	require.True(t, statuses[customBit].Configured)
+	require.False(t, statuses[customBit].Toggleable)
+	require.True(t, statuses[lnwire.PaymentAddrOptional].Toggleable)
+	require.Equal(t, []lnwire.FeatureBit{lnwire.TLVOnionPayloadOptional},
+		statuses[lnwire.PaymentAddrOptional].Dependencies)
+	require.False(t, statuses[lnwire.AnchorsZeroFeeHtlcTxOptional].Enabled)
+	require.False(
+		t, statuses[lnwire.AnchorsZeroFeeHtlcTxOptional].Toggleable,
+	)
+}
+
+// trimDeps clears the dependencies of a status.
+func trimDeps(status Status) Status {
+	status.Dependencies = nil
+	status.DependencyChain = nil
+
+	return status
+}
@@ -19,6 +19,12 @@ var (
 	// peer of the channel does not support it.
 	errUnsupportedChannelType = errors.New("requested channel type " +
 		"not supported")
+
+	// errNoSupportedChannelType is an error returned when none of the
+	// channel types of a list of acceptable channel types is supported by
+	// both peers of the channel.
+	errNoSupportedChannelType = errors.New("none of the requested " +
+		"channel types is supported")
 )
 
 // negotiateCommitmentType negotiates the commitment type of a newly opened
@@ -58,6 +64,34 @@ func negotiateCommitmentType(channelType *lnwire.ChannelType, local,
 	return false, chanType, commitType, nil
 }
 
+// selectChannelType returns the index of the first channel type of an ordered
+// list of acceptable channel types that can be explicitly negotiated with the
+// remote peer. As public channels can't use the option-scid-alias channel
+// type, such channel types are skipped for public channels.
+func selectChannelType(channelTypes []lnwire.ChannelType, local,
+	remote *lnwire.FeatureVector, private bool) (int, error) {
+
+	if !hasFeatures(local, remote, lnwire.ExplicitChannelTypeOptional) {
+		return 0, errUnsupportedExplicitNegotiation
+	}
+
+	for i, channelType := range channelTypes {
+		features := lnwire.RawFeatureVector(channelType)
+		if !private && features.IsSet(lnwire.ScidAliasRequired) {
+			continue
+		}
+
+		_, err := explicitNegotiateCommitmentType(
+			channelType, local, remote,
+		)
+		if err == nil {
+			return i, nil
+		}
+	}
+
+	return 0, errNoSupportedChannelType
+}
+
 // explicitNegotiateCommitmentType attempts to explicitly negotiate for a
 // specific channel type. Since the channel type is comprised of a set of even
 // feature bits, we also make sure each feature is supported by both peers. An
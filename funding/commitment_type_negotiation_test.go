@@ -415,3 +415,108 @@ func TestCommitmentTypeNegotiation(t *testing.T) {
 		}
 	}
 }
+
+// TestSelectChannelType tests that the first channel type of a list that is
+// supported by both peers is selected.
+func TestSelectChannelType(t *testing.T) {
+	t.Parallel()
+
+	var (
+		tweakless = lnwire.ChannelType(*lnwire.NewRawFeatureVector(
+			lnwire.StaticRemoteKeyRequired,
+		))
+		anchors = lnwire.ChannelType(*lnwire.NewRawFeatureVector(
+			lnwire.StaticRemoteKeyRequired,
+			lnwire.AnchorsZeroFeeHtlcTxRequired,
+		))
+		anchorsScid = lnwire.ChannelType(*lnwire.NewRawFeatureVector(
+			lnwire.ScidAliasRequired,
+			lnwire.StaticRemoteKeyRequired,
+			lnwire.AnchorsZeroFeeHtlcTxRequired,
+		))
+		lease = lnwire.ChannelType(*lnwire.NewRawFeatureVector(
+			lnwire.ScriptEnforcedLeaseRequired,
+			lnwire.StaticRemoteKeyRequired,
+			lnwire.AnchorsZeroFeeHtlcTxRequired,
+		))
+	)
+
+	allFeatures := lnwire.NewFeatureVector(lnwire.NewRawFeatureVector(
+		lnwire.StaticRemoteKeyOptional,
+		lnwire.AnchorsZeroFeeHtlcTxOptional,
+		lnwire.ScidAliasOptional,
+		lnwire.ExplicitChannelTypeOptional,
+	), nil)
+	noExplicit := lnwire.NewFeatureVector(lnwire.NewRawFeatureVector(
+		lnwire.StaticRemoteKeyOptional,
+		lnwire.AnchorsZeroFeeHtlcTxOptional,
+	), nil)
+
+	testCases := []struct {
+		name         string
+		channelTypes []lnwire.ChannelType
+		remote       *lnwire.FeatureVector
+		private      bool
+		expectsIdx   int
+		expectsErr   error
+	}{
+		{
+			name:         "first supported",
+			channelTypes: []lnwire.ChannelType{anchors, tweakless},
+			remote:       allFeatures,
+			expectsIdx:   0,
+		},
+		{
+			name:         "skip unsupported",
+			channelTypes: []lnwire.ChannelType{lease, tweakless},
+			remote:       allFeatures,
+			expectsIdx:   1,
+		},
+		{
+			name: "skip scid alias for public channel",
+			channelTypes: []lnwire.ChannelType{
+				anchorsScid, anchors,
+			},
+			remote:     allFeatures,
+			expectsIdx: 1,
+		},
+		{
+			name: "scid alias for private channel",
+			channelTypes: []lnwire.ChannelType{
+				anchorsScid, anchors,
+			},
+			remote:     allFeatures,
+			private:    true,
+			expectsIdx: 0,
+		},
+		{
+			name:         "none supported",
+			channelTypes: []lnwire.ChannelType{lease},
+			remote:       allFeatures,
+			expectsErr:   errNoSupportedChannelType,
+		},
+		{
+			name:         "no explicit negotiation",
+			channelTypes: []lnwire.ChannelType{anchors},
+			remote:       noExplicit,
+			expectsErr:   errUnsupportedExplicitNegotiation,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			idx, err := selectChannelType(
+				testCase.channelTypes, allFeatures,
+				testCase.remote, testCase.private,
+			)
+			require.ErrorIs(t, err, testCase.expectsErr)
+			if testCase.expectsErr == nil {
+				require.Equal(t, testCase.expectsIdx, idx)
+			}
+		})
+	}
+}
@@ -0,0 +1,894 @@
+package funding
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnpeer"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/zpay32"
+)
+
+const (
+	// leaseRequestTimeout is the time we wait for the reply to a lease
+	// request before giving up. Nodes that don't know about liquidity ads
+	// ignore the request, so this is the error they'll end up with.
+	leaseRequestTimeout = time.Minute
+
+	// leasePurchaseTimeout is how long we'll track a lease we bought
+	// after receiving the offer for it if we don't pay its fee. Its
+	// invoice expires way earlier, after which the seller won't open the
+	// channel anymore.
+	leasePurchaseTimeout = 24 * time.Hour
+
+	// leaseExpiryTolerance is the number of blocks the lease expiry
+	// offered by a seller may be below the one we expect, to account for
+	// the seller lagging behind our view of the chain.
+	leaseExpiryTolerance = 6
+
+	// leaseFundingConfTarget is the confirmation target used to estimate
+	// the fee rate of the funding transactions of the leases we sell.
+	leaseFundingConfTarget = 6
+)
+
+var (
+	// ErrNoLiquidityAd is returned when a lease is requested from a node
+	// that doesn't advertise any liquidity.
+	ErrNoLiquidityAd = errors.New("node doesn't advertise liquidity")
+
+	// ErrLeaseRejected is returned when a lease request doesn't match the
+	// liquidity ad of the node it was sent to.
+	ErrLeaseRejected = errors.New("lease request rejected")
+
+	// ErrLeaseRequestTimeout is returned when the node we requested a
+	// lease from didn't reply in time.
+	ErrLeaseRequestTimeout = errors.New("lease request timed out")
+
+	// ErrInvalidLeaseOffer is returned when the offer received for a lease
+	// request doesn't match the request.
+	ErrInvalidLeaseOffer = errors.New("invalid lease offer")
+
+	// ErrLeaseInvoiceCanceled is returned when the invoice for the fee of
+	// a lease we sold is canceled, e.g. because it expired unpaid.
+	ErrLeaseInvoiceCanceled = errors.New("lease invoice canceled")
+)
+
+// PendingLease is a channel lease that was agreed on through a liquidity ad,
+// but whose channel isn't pending yet. Once the channel is pending, the lease
+// can be found through the channel's lease expiry.
+type PendingLease struct {
+	// LeaseID identifies the lease throughout the request flow.
+	LeaseID [32]byte
+
+	// Peer is the node the lease was sold to or bought from.
+	Peer *btcec.PublicKey
+
+	// Sold is true if we sold the lease, meaning we fund the lease
+	// channel, and false if we bought it.
+	Sold bool
+
+	// LeaseAmt is the capacity of the lease channel.
+	LeaseAmt btcutil.Amount
+
+	// LeaseDuration is the number of blocks the funds of the seller are
+	// locked for once the lease channel is opened.
+	LeaseDuration uint32
+
+	// Fee is the fee paid for the lease.
+	Fee btcutil.Amount
+
+	// LeaseExpiry is the earliest absolute height the lease can expire at.
+	LeaseExpiry uint32
+
+	// PaymentRequest is the invoice for the lease fee.
+	PaymentRequest string
+
+	// Paid is true once the seller received the lease fee. It's only
+	// tracked for the leases we sold.
+	Paid bool
+
+	// OpenErr is the error we ran into opening the channel of a lease we
+	// sold after the lease fee was paid, if any. The opening is retried
+	// on restart.
+	OpenErr error
+
+	// created is the time the lease was agreed on.
+	created time.Time
+
+	// paymentHash is the payment hash of the invoice for the lease fee.
+	paymentHash lntypes.Hash
+}
+
+// soldLease returns the database representation of a lease we sold.
+func (l *PendingLease) soldLease() *channeldb.SoldLease {
+	return &channeldb.SoldLease{
+		LeaseID:        l.LeaseID,
+		Buyer:          l.Peer,
+		LeaseAmt:       l.LeaseAmt,
+		LeaseDuration:  l.LeaseDuration,
+		Fee:            l.Fee,
+		LeaseExpiry:    l.LeaseExpiry,
+		PaymentRequest: l.PaymentRequest,
+		PaymentHash:    l.paymentHash,
+		CreatedAt:      l.created,
+	}
+}
+
+// boughtLease returns the database representation of a lease we bought.
+func (l *PendingLease) boughtLease() *channeldb.BoughtLease {
+	return &channeldb.BoughtLease{
+		LeaseID:        l.LeaseID,
+		Seller:         l.Peer,
+		LeaseAmt:       l.LeaseAmt,
+		LeaseDuration:  l.LeaseDuration,
+		Fee:            l.Fee,
+		LeaseExpiry:    l.LeaseExpiry,
+		PaymentRequest: l.PaymentRequest,
+		PaymentHash:    l.paymentHash,
+		CreatedAt:      l.created,
+	}
+}
+
+// leaseKey identifies a lease by the peer it's negotiated with and the lease
+// ID, as the latter is chosen by the buyer.
+type leaseKey struct {
+	peer serializedPubKey
+	id   [32]byte
+}
+
+// newLeaseKey creates the key of the lease with the given peer and ID.
+func newLeaseKey(peer *btcec.PublicKey, id [32]byte) leaseKey {
+	return leaseKey{
+		peer: newSerializedKey(peer),
+		id:   id,
+	}
+}
+
+// leaseRequest is a lease request we sent that awaits the reply of the seller.
+type leaseRequest struct {
+	msg *lnwire.LeaseRequest
+
+	// bestHeight is our best height at the time of the request, which the
+	// offered lease expiry is checked against.
+	bestHeight uint32
+
+	resp chan *PendingLease
+	err  chan error
+}
+
+// leaseChannelType returns the channel type of the lease channels we open.
+func leaseChannelType() lnwire.ChannelType {
+	return lnwire.ChannelType(*lnwire.NewRawFeatureVector(
+		lnwire.StaticRemoteKeyRequired,
+		lnwire.AnchorsZeroFeeHtlcTxRequired,
+		lnwire.ScriptEnforcedLeaseRequired,
+	))
+}
+
+// RequestLease requests a channel lease of the given amount and duration from
+// a peer advertising a liquidity ad. If the peer accepts the request, the
+// returned lease carries the invoice for the lease fee, and the peer opens
+// the lease channel once it's paid.
+func (f *Manager) RequestLease(peer lnpeer.Peer, amt btcutil.Amount,
+	duration uint32, maxFee btcutil.Amount) (*PendingLease, error) {
+
+	_, bestHeight, err := f.cfg.Wallet.Cfg.ChainIO.GetBestBlock()
+	if err != nil {
+		return nil, err
+	}
+
+	req := &leaseRequest{
+		msg: &lnwire.LeaseRequest{
+			LeaseID:       f.nextPendingChanID(),
+			ChainHash:     *f.cfg.Wallet.Cfg.NetParams.GenesisHash,
+			LeaseAmt:      amt,
+			LeaseDuration: duration,
+			MaxFee:        maxFee,
+		},
+		bestHeight: uint32(bestHeight),
+		resp:       make(chan *PendingLease, 1),
+		err:        make(chan error, 1),
+	}
+
+	key := newLeaseKey(peer.IdentityKey(), req.msg.LeaseID)
+	f.leaseMtx.Lock()
+	f.leaseRequests[key] = req
+	f.leaseMtx.Unlock()
+
+	defer func() {
+		f.leaseMtx.Lock()
+		delete(f.leaseRequests, key)
+		f.leaseMtx.Unlock()
+	}()
+
+	log.Infof("Requesting lease(%x) of %v for %d blocks from %x",
+		req.msg.LeaseID[:], amt, duration,
+		peer.IdentityKey().SerializeCompressed())
+
+	if err := peer.SendMessage(true, req.msg); err != nil {
+		return nil, err
+	}
+
+	select {
+	case lease := <-req.resp:
+		return lease, nil
+
+	case err := <-req.err:
+		return nil, err
+
+	case <-time.After(leaseRequestTimeout):
+		return nil, ErrLeaseRequestTimeout
+
+	case <-f.quit:
+		return nil, ErrFundingManagerShuttingDown
+	}
+}
+
+// PendingLeases returns the leases we sold or bought whose channel isn't
+// pending yet, oldest first.
+func (f *Manager) PendingLeases() []PendingLease {
+	f.leaseMtx.Lock()
+	defer f.leaseMtx.Unlock()
+
+	leases := make([]PendingLease, 0, len(f.pendingLeases))
+	for _, lease := range f.pendingLeases {
+		leases = append(leases, *lease)
+	}
+	sort.Slice(leases, func(i, j int) bool {
+		return leases[i].created.Before(leases[j].created)
+	})
+
+	return leases
+}
+
+// isLeaseRequest returns true if we sent a lease request with the given ID to
+// the peer that awaits a reply.
+func (f *Manager) isLeaseRequest(peer *btcec.PublicKey, id [32]byte) bool {
+	f.leaseMtx.Lock()
+	defer f.leaseMtx.Unlock()
+
+	_, ok := f.leaseRequests[newLeaseKey(peer, id)]
+	return ok
+}
+
+// handleLeaseRequest handles a lease request received from a peer. If it
+// matches our liquidity ad, we reply with an offer carrying the invoice for
+// the lease fee and open the lease channel once it's paid. Otherwise, the
+// request is rejected with an error.
+func (f *Manager) handleLeaseRequest(peer lnpeer.Peer,
+	msg *lnwire.LeaseRequest) {
+
+	peerKey := peer.IdentityKey()
+	offer, err := f.offerLease(peerKey, msg)
+	if err != nil {
+		log.Infof("Rejecting lease request(%x) from %x: %v",
+			msg.LeaseID[:], peerKey.SerializeCompressed(), err)
+
+		// Only the reasons of rejected requests are sent to the peer,
+		// any other error is internal.
+		data := lnwire.ErrorData("lease request failed due to " +
+			"internal error")
+		if errors.Is(err, ErrLeaseRejected) {
+			data = lnwire.ErrorData(err.Error())
+		}
+
+		errMsg := &lnwire.Error{
+			ChanID: msg.LeaseID,
+			Data:   data,
+		}
+		if err := peer.SendMessage(false, errMsg); err != nil {
+			log.Errorf("Unable to send lease rejection to peer: "+
+				"%v", err)
+		}
+
+		return
+	}
+
+	if err := peer.SendMessage(false, offer); err != nil {
+		log.Errorf("Unable to send lease offer to peer: %v", err)
+	}
+}
+
+// offerLease validates a lease request against our liquidity ad. If it
+// matches, the lease is tracked until it's paid, at which point we open the
+// lease channel, and the offer to reply with is returned.
+func (f *Manager) offerLease(peerKey *btcec.PublicKey,
+	msg *lnwire.LeaseRequest) (*lnwire.LeaseOffer, error) {
+
+	var ad *lnwire.LiquidityAd
+	if f.cfg.LiquidityAd != nil {
+		ad = f.cfg.LiquidityAd()
+	}
+	if ad == nil {
+		return nil, fmt.Errorf("%w: %v", ErrLeaseRejected,
+			ErrNoLiquidityAd)
+	}
+
+	chainHash := *f.cfg.Wallet.Cfg.NetParams.GenesisHash
+	switch {
+	case msg.ChainHash != chainHash:
+		return nil, fmt.Errorf("%w: unknown chain %v", ErrLeaseRejected,
+			msg.ChainHash)
+
+	case msg.LeaseAmt < ad.MinLeaseAmt || msg.LeaseAmt > ad.MaxLeaseAmt:
+		return nil, fmt.Errorf("%w: lease amount %v outside of the "+
+			"advertised range [%v, %v]", ErrLeaseRejected,
+			msg.LeaseAmt, ad.MinLeaseAmt, ad.MaxLeaseAmt)
+
+	case msg.LeaseDuration != ad.LeaseDuration:
+		return nil, fmt.Errorf("%w: lease duration %d doesn't match "+
+			"the advertised one of %d blocks", ErrLeaseRejected,
+			msg.LeaseDuration, ad.LeaseDuration)
+	}
+
+	fee := ad.Fee(msg.LeaseAmt)
+	if fee > msg.MaxFee {
+		return nil, fmt.Errorf("%w: lease fee %v above max fee %v",
+			ErrLeaseRejected, fee, msg.MaxFee)
+	}
+
+	key := newLeaseKey(peerKey, msg.LeaseID)
+	if err := f.checkLeaseCapacity(key, msg.LeaseAmt); err != nil {
+		return nil, err
+	}
+
+	_, bestHeight, err := f.cfg.Wallet.Cfg.ChainIO.GetBestBlock()
+	if err != nil {
+		return nil, err
+	}
+
+	memo := fmt.Sprintf("lease of %v for %d blocks", msg.LeaseAmt,
+		msg.LeaseDuration)
+	payReq, hash, err := f.cfg.AddLeaseInvoice(fee, memo)
+	if err != nil {
+		return nil, err
+	}
+
+	lease := &PendingLease{
+		LeaseID:        msg.LeaseID,
+		Peer:           peerKey,
+		Sold:           true,
+		LeaseAmt:       msg.LeaseAmt,
+		LeaseDuration:  msg.LeaseDuration,
+		Fee:            fee,
+		LeaseExpiry:    uint32(bestHeight) + msg.LeaseDuration,
+		PaymentRequest: payReq,
+		created:        time.Now(),
+		paymentHash:    hash,
+	}
+
+	// The lease is persisted before it's offered, so that we still open
+	// its channel if the fee is paid while we're down.
+	err = f.cfg.Wallet.Cfg.Database.PutSoldLease(lease.soldLease())
+	if err != nil {
+		return nil, err
+	}
+
+	f.leaseMtx.Lock()
+	f.pendingLeases[key] = lease
+	f.leaseMtx.Unlock()
+
+	f.wg.Add(1)
+	go f.fulfillLease(key, lease, false)
+
+	return &lnwire.LeaseOffer{
+		LeaseID:        msg.LeaseID,
+		Fee:            fee,
+		LeaseExpiry:    lease.LeaseExpiry,
+		PaymentRequest: []byte(payReq),
+	}, nil
+}
+
+// checkLeaseCapacity checks whether we can offer another lease of the given
+// amount. The lease ID must not be in use by the peer already, the number of
+// unpaid leases must stay within our limits, and the confirmed funds of the
+// wallet must cover the lease on top of the other leases we sold whose
+// channel isn't pending yet.
+func (f *Manager) checkLeaseCapacity(key leaseKey, amt btcutil.Amount) error {
+	utxos, err := f.cfg.Wallet.ListUnspentWitnessFromDefaultAccount(
+		1, math.MaxInt32,
+	)
+	if err != nil {
+		return err
+	}
+
+	var balance btcutil.Amount
+	for _, utxo := range utxos {
+		balance += utxo.Value
+	}
+
+	f.leaseMtx.Lock()
+	defer f.leaseMtx.Unlock()
+
+	if _, ok := f.pendingLeases[key]; ok {
+		return fmt.Errorf("%w: duplicate lease id %x",
+			ErrLeaseRejected, key.id[:])
+	}
+
+	var (
+		unpaid, unpaidByPeer uint32
+		reserved             btcutil.Amount
+	)
+	for otherKey, lease := range f.pendingLeases {
+		if !lease.Sold {
+			continue
+		}
+
+		reserved += lease.LeaseAmt
+		if lease.Paid {
+			continue
+		}
+
+		unpaid++
+		if otherKey.peer == key.peer {
+			unpaidByPeer++
+		}
+	}
+
+	maxUnpaid := f.cfg.MaxUnpaidLeases
+	maxUnpaidByPeer := f.cfg.MaxUnpaidLeasesPerPeer
+	switch {
+	case maxUnpaid != 0 && unpaid >= maxUnpaid:
+		return fmt.Errorf("%w: too many unpaid leases",
+			ErrLeaseRejected)
+
+	case maxUnpaidByPeer != 0 && unpaidByPeer >= maxUnpaidByPeer:
+		return fmt.Errorf("%w: too many unpaid leases of peer",
+			ErrLeaseRejected)
+
+	case reserved+amt > balance:
+		return fmt.Errorf("%w: not enough funds to lease %v",
+			ErrLeaseRejected, amt)
+	}
+
+	return nil
+}
+
+// resumeSoldLeases resumes the leases we sold whose channel wasn't pending
+// yet when we last went down. Their channels are opened once their fee is
+// paid, which may well have happened in the meantime.
+func (f *Manager) resumeSoldLeases() error {
+	soldLeases, err := f.cfg.Wallet.Cfg.Database.FetchSoldLeases()
+	if err != nil {
+		return err
+	}
+
+	for _, sold := range soldLeases {
+		lease := &PendingLease{
+			LeaseID:        sold.LeaseID,
+			Peer:           sold.Buyer,
+			Sold:           true,
+			LeaseAmt:       sold.LeaseAmt,
+			LeaseDuration:  sold.LeaseDuration,
+			Fee:            sold.Fee,
+			LeaseExpiry:    sold.LeaseExpiry,
+			PaymentRequest: sold.PaymentRequest,
+			created:        sold.CreatedAt,
+			paymentHash:    sold.PaymentHash,
+		}
+		key := newLeaseKey(lease.Peer, lease.LeaseID)
+
+		log.Infof("Resuming lease(%x) sold to %x", lease.LeaseID[:],
+			key.peer[:])
+
+		f.leaseMtx.Lock()
+		f.pendingLeases[key] = lease
+		f.leaseMtx.Unlock()
+
+		f.wg.Add(1)
+		go f.fulfillLease(key, lease, true)
+	}
+
+	return nil
+}
+
+// removeSoldLease stops tracking a lease we sold, either because it wasn't
+// paid or because its channel is pending.
+func (f *Manager) removeSoldLease(key leaseKey, lease *PendingLease) {
+	f.leaseMtx.Lock()
+	delete(f.pendingLeases, key)
+	f.leaseMtx.Unlock()
+
+	err := f.cfg.Wallet.Cfg.Database.DeleteSoldLease(lease.soldLease())
+	if err != nil {
+		log.Errorf("Unable to delete lease(%x): %v", lease.LeaseID[:],
+			err)
+	}
+}
+
+// hasLeaseChannel returns true if we already initiated a channel to the buyer
+// of a lease we sold that fulfills it. This guards against opening a second
+// channel for a resumed lease if we went down after its channel became
+// pending, but before the lease was removed.
+func (f *Manager) hasLeaseChannel(lease *PendingLease) (bool, error) {
+	channels, err := f.cfg.Wallet.Cfg.Database.FetchOpenChannels(
+		lease.Peer,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	for _, channel := range channels {
+		switch {
+		case !channel.IsInitiator:
+		case !channel.ChanType.HasLeaseExpiration():
+		case channel.Capacity != lease.LeaseAmt:
+		case channel.ThawHeight < lease.LeaseExpiry:
+
+		default:
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// fulfillLease waits for the fee of a lease we sold to be paid, and then
+// opens the lease channel to the buyer. If the lease was resumed on startup,
+// we first make sure its channel wasn't opened already.
+//
+// NOTE: This MUST be run as a goroutine.
+func (f *Manager) fulfillLease(key leaseKey, lease *PendingLease,
+	resumed bool) {
+
+	defer f.wg.Done()
+
+	err := f.cfg.WaitForLeasePayment(lease.paymentHash, f.quit)
+	switch {
+	// The lease is kept around unless its invoice was canceled, as the fee
+	// may still be paid, e.g. if we're merely shutting down.
+	case err != nil && !errors.Is(err, ErrLeaseInvoiceCanceled):
+		log.Debugf("Stopped waiting for payment of lease(%x): %v",
+			lease.LeaseID[:], err)
+
+		return
+
+	case err != nil:
+		log.Infof("Dropping unpaid lease(%x): %v", lease.LeaseID[:],
+			err)
+		f.removeSoldLease(key, lease)
+
+		return
+	}
+
+	f.leaseMtx.Lock()
+	lease.Paid = true
+	f.leaseMtx.Unlock()
+
+	// setOpenErr records the error we ran into opening the channel of the
+	// paid lease, so that it shows up among the pending leases.
+	setOpenErr := func(err error) {
+		log.Errorf("Unable to open channel of lease(%x): %v",
+			lease.LeaseID[:], err)
+
+		f.leaseMtx.Lock()
+		lease.OpenErr = err
+		f.leaseMtx.Unlock()
+	}
+
+	if resumed {
+		opened, err := f.hasLeaseChannel(lease)
+		if err != nil {
+			setOpenErr(err)
+			return
+		}
+
+		if opened {
+			log.Infof("Channel of lease(%x) already opened",
+				lease.LeaseID[:])
+			f.removeSoldLease(key, lease)
+
+			return
+		}
+	}
+
+	log.Infof("Lease(%x) paid, waiting for peer %x to open the lease "+
+		"channel", lease.LeaseID[:], key.peer[:])
+
+	peerChan := make(chan lnpeer.Peer, 1)
+	f.cfg.NotifyWhenOnline([33]byte(key.peer), peerChan)
+
+	var peer lnpeer.Peer
+	select {
+	case peer = <-peerChan:
+	case <-f.quit:
+		return
+	}
+
+	feeRate, err := f.cfg.FeeEstimator.EstimateFeePerKW(
+		leaseFundingConfTarget,
+	)
+	if err != nil {
+		setOpenErr(err)
+		return
+	}
+
+	_, bestHeight, err := f.cfg.Wallet.Cfg.ChainIO.GetBestBlock()
+	if err != nil {
+		setOpenErr(err)
+		return
+	}
+
+	// The lease lasts its full duration from now on, which can't be
+	// earlier than the expiry we offered.
+	leaseExpiry := uint32(bestHeight) + lease.LeaseDuration
+	if leaseExpiry < lease.LeaseExpiry {
+		leaseExpiry = lease.LeaseExpiry
+	}
+
+	chanType := leaseChannelType()
+	initMsg := &InitFundingMsg{
+		Peer:            peer,
+		TargetPubkey:    peer.IdentityKey(),
+		ChainHash:       *f.cfg.Wallet.Cfg.NetParams.GenesisHash,
+		LocalFundingAmt: lease.LeaseAmt,
+		FundingFeePerKw: feeRate,
+		MinConfs:        1,
+		ChannelType:     &chanType,
+		LeaseExpiry:     leaseExpiry,
+		Memo:            []byte("liquidity ad lease"),
+		Updates:         make(chan *lnrpc.OpenStatusUpdate, 2),
+		Err:             make(chan error, 1),
+	}
+
+	select {
+	case f.fundingRequests <- initMsg:
+	case <-f.quit:
+		return
+	}
+
+	select {
+	case <-initMsg.Updates:
+		log.Infof("Channel of lease(%x) pending with expiry at "+
+			"height %d", lease.LeaseID[:], leaseExpiry)
+		f.removeSoldLease(key, lease)
+
+	case err := <-initMsg.Err:
+		setOpenErr(err)
+
+	case <-f.quit:
+	}
+}
+
+// handleLeaseOffer handles the offer received for a lease request we sent. If
+// it matches our request, the lease is tracked until the seller opens its
+// channel and is handed back to the caller of RequestLease.
+func (f *Manager) handleLeaseOffer(peer lnpeer.Peer, msg *lnwire.LeaseOffer) {
+	peerKey := peer.IdentityKey()
+	key := newLeaseKey(peerKey, msg.LeaseID)
+
+	f.leaseMtx.Lock()
+	req, ok := f.leaseRequests[key]
+	delete(f.leaseRequests, key)
+	f.leaseMtx.Unlock()
+
+	if !ok {
+		log.Warnf("Received offer for unknown lease request(%x) "+
+			"from %x", msg.LeaseID[:],
+			peerKey.SerializeCompressed())
+		return
+	}
+
+	paymentHash, err := f.validateLeaseOffer(peerKey, req, msg)
+	if err != nil {
+		req.err <- fmt.Errorf("%w: %v", ErrInvalidLeaseOffer, err)
+		return
+	}
+
+	lease := &PendingLease{
+		LeaseID:        msg.LeaseID,
+		Peer:           peerKey,
+		LeaseAmt:       req.msg.LeaseAmt,
+		LeaseDuration:  req.msg.LeaseDuration,
+		Fee:            msg.Fee,
+		LeaseExpiry:    msg.LeaseExpiry,
+		PaymentRequest: string(msg.PaymentRequest),
+		created:        time.Now(),
+		paymentHash:    paymentHash,
+	}
+
+	// The lease is persisted before it's handed out for payment, so that
+	// we still accept its channel if the seller opens it while we're
+	// down.
+	err = f.cfg.Wallet.Cfg.Database.PutBoughtLease(lease.boughtLease())
+	if err != nil {
+		req.err <- err
+		return
+	}
+
+	f.leaseMtx.Lock()
+	f.pendingLeases[key] = lease
+	f.leaseMtx.Unlock()
+
+	req.resp <- lease
+}
+
+// validateLeaseOffer checks that a lease offer matches our request, and that
+// its invoice pays the offered fee to the seller. The payment hash of the
+// invoice is returned.
+func (f *Manager) validateLeaseOffer(peerKey *btcec.PublicKey,
+	req *leaseRequest, msg *lnwire.LeaseOffer) (lntypes.Hash, error) {
+
+	if msg.Fee > req.msg.MaxFee {
+		return lntypes.Hash{}, fmt.Errorf("fee %v above max fee %v",
+			msg.Fee, req.msg.MaxFee)
+	}
+
+	minExpiry := req.bestHeight + req.msg.LeaseDuration
+	if msg.LeaseExpiry+leaseExpiryTolerance < minExpiry {
+		return lntypes.Hash{}, fmt.Errorf("lease expiry %d below "+
+			"expected expiry %d", msg.LeaseExpiry, minExpiry)
+	}
+
+	invoice, err := zpay32.Decode(
+		string(msg.PaymentRequest), &f.cfg.Wallet.Cfg.NetParams,
+	)
+	if err != nil {
+		return lntypes.Hash{}, fmt.Errorf("invalid payment request: "+
+			"%w", err)
+	}
+
+	feeMSat := lnwire.NewMSatFromSatoshis(msg.Fee)
+	switch {
+	case invoice.MilliSat == nil || *invoice.MilliSat != feeMSat:
+		return lntypes.Hash{}, fmt.Errorf("invoice amount doesn't "+
+			"match fee %v", msg.Fee)
+
+	case !invoice.Destination.IsEqual(peerKey):
+		return lntypes.Hash{}, errors.New("invoice isn't payable to " +
+			"the seller")
+	}
+
+	return *invoice.PaymentHash, nil
+}
+
+// failLeaseRequest fails the lease request an error received from a peer
+// refers to, returning false if the error isn't tied to any of our lease
+// requests.
+func (f *Manager) failLeaseRequest(peerKey *btcec.PublicKey,
+	msg *lnwire.Error) bool {
+
+	key := newLeaseKey(peerKey, msg.ChanID)
+
+	f.leaseMtx.Lock()
+	req, ok := f.leaseRequests[key]
+	delete(f.leaseRequests, key)
+	f.leaseMtx.Unlock()
+
+	if !ok {
+		return false
+	}
+
+	req.err <- fmt.Errorf("lease request rejected by %x: %v",
+		peerKey.SerializeCompressed(), msg.Error())
+
+	return true
+}
+
+// matchLeasePurchase returns the lease we bought from the peer that a lease
+// channel it proposes to open fulfills, if any. The channel has to match the
+// leased amount, and may not expire earlier than offered.
+func (f *Manager) matchLeasePurchase(peerKey *btcec.PublicKey,
+	amt btcutil.Amount, leaseExpiry uint32) *PendingLease {
+
+	peer := newSerializedKey(peerKey)
+
+	f.leaseMtx.Lock()
+	defer f.leaseMtx.Unlock()
+
+	for key, lease := range f.pendingLeases {
+		switch {
+		case lease.Sold || key.peer != peer:
+			continue
+
+		case amt != lease.LeaseAmt || leaseExpiry < lease.LeaseExpiry:
+			continue
+		}
+
+		return lease
+	}
+
+	return nil
+}
+
+// completeLeasePurchase stops tracking a lease we bought once its channel is
+// pending.
+func (f *Manager) completeLeasePurchase(lease *PendingLease) {
+	log.Infof("Channel of lease(%x) bought from %x pending",
+		lease.LeaseID[:], lease.Peer.SerializeCompressed())
+
+	f.removeBoughtLease(lease)
+}
+
+// pruneLeasePurchases stops tracking the leases we bought whose channel the
+// seller didn't open in time. Leases whose fee we paid, or are still paying,
+// are kept though, as the seller may open their channel at any time.
+func (f *Manager) pruneLeasePurchases() {
+	var expired []*PendingLease
+
+	f.leaseMtx.Lock()
+	for _, lease := range f.pendingLeases {
+		age := time.Since(lease.created)
+		if lease.Sold || age < leasePurchaseTimeout {
+			continue
+		}
+
+		expired = append(expired, lease)
+	}
+	f.leaseMtx.Unlock()
+
+	for _, lease := range expired {
+		paid, err := f.cfg.LeaseFeePaid(lease.paymentHash)
+		if err != nil {
+			log.Errorf("Unable to check payment of lease(%x): %v",
+				lease.LeaseID[:], err)
+			continue
+		}
+		if paid {
+			continue
+		}
+
+		log.Warnf("Lease(%x) bought from %x wasn't paid in time",
+			lease.LeaseID[:], lease.Peer.SerializeCompressed())
+		f.removeBoughtLease(lease)
+	}
+}
+
+// resumeBoughtLeases resumes tracking the leases we bought whose channel
+// wasn't pending yet when we last went down, so that we still accept their
+// channels.
+func (f *Manager) resumeBoughtLeases() error {
+	boughtLeases, err := f.cfg.Wallet.Cfg.Database.FetchBoughtLeases()
+	if err != nil {
+		return err
+	}
+
+	f.leaseMtx.Lock()
+	defer f.leaseMtx.Unlock()
+
+	for _, bought := range boughtLeases {
+		lease := &PendingLease{
+			LeaseID:        bought.LeaseID,
+			Peer:           bought.Seller,
+			LeaseAmt:       bought.LeaseAmt,
+			LeaseDuration:  bought.LeaseDuration,
+			Fee:            bought.Fee,
+			LeaseExpiry:    bought.LeaseExpiry,
+			PaymentRequest: bought.PaymentRequest,
+			created:        bought.CreatedAt,
+			paymentHash:    bought.PaymentHash,
+		}
+		key := newLeaseKey(lease.Peer, lease.LeaseID)
+
+		log.Infof("Resuming lease(%x) bought from %x",
+			lease.LeaseID[:], key.peer[:])
+
+		f.pendingLeases[key] = lease
+	}
+
+	return nil
+}
+
+// removeBoughtLease stops tracking a lease we bought, either because its
+// channel is pending or because we never paid for it.
+func (f *Manager) removeBoughtLease(lease *PendingLease) {
+	f.leaseMtx.Lock()
+	delete(f.pendingLeases, newLeaseKey(lease.Peer, lease.LeaseID))
+	f.leaseMtx.Unlock()
+
+	err := f.cfg.Wallet.Cfg.Database.DeleteBoughtLease(
+		lease.boughtLease(),
+	)
+	if err != nil {
+		log.Errorf("Unable to delete lease(%x): %v", lease.LeaseID[:],
+			err)
+	}
+}
@@ -27,6 +27,7 @@ import (
 	"github.com/lightningnetwork/lnd/labels"
 	"github.com/lightningnetwork/lnd/lnpeer"
 	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lntypes"
 	"github.com/lightningnetwork/lnd/lnwallet"
 	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
 	"github.com/lightningnetwork/lnd/lnwallet/chanfunding"
@@ -169,6 +170,19 @@ type reservationWithCtx struct {
 	// the channel.
 	channelType *lnwire.ChannelType
 
+	// initMsg is the request that started the funding flow if we are the
+	// initiator of the channel.
+	initMsg *InitFundingMsg
+
+	// fallbackTypes are the channel types that are proposed next, in
+	// order, if the remote peer rejects the proposed channel type. They
+	// are cleared once the remote peer accepted the channel.
+	fallbackTypes []lnwire.ChannelType
+
+	// leasePurchase is the lease we bought that the channel fulfills, if
+	// the remote peer opens it as the seller of a liquidity ad lease.
+	leasePurchase *PendingLease
+
 	updateMtx   sync.RWMutex
 	lastUpdated time.Time
 
@@ -291,10 +305,22 @@ type InitFundingMsg struct {
 	// support explicit channel type negotiation.
 	ChannelType *lnwire.ChannelType
 
+	// ChannelTypes is an ordered list of acceptable channel types. The
+	// first one supported by both sides is proposed, and if the remote
+	// peer rejects it, the funding flow is restarted with the next ones.
+	// It requires both sides to support explicit channel type negotiation
+	// and can't be combined with ChannelType or ChanFunder.
+	ChannelTypes []lnwire.ChannelType
+
 	// Memo is any arbitrary information we wish to store locally about the
 	// channel that will be useful to our future selves.
 	Memo []byte
 
+	// LeaseExpiry is the absolute expiration height of a script enforced
+	// lease channel that isn't funded through a shim, such as the ones we
+	// open for leases sold through our liquidity ad.
+	LeaseExpiry uint32
+
 	// Updates is a channel which updates to the opening status of the
 	// channel are sent on.
 	Updates chan *lnrpc.OpenStatusUpdate
@@ -528,6 +554,38 @@ type Config struct {
 	// AliasManager is an implementation of the aliasHandler interface that
 	// abstracts away the handling of many alias functions.
 	AliasManager aliasHandler
+
+	// LiquidityAd returns the liquidity ad we currently advertise, or nil
+	// if we don't sell channel leases. If not set, all lease requests are
+	// rejected.
+	LiquidityAd func() *lnwire.LiquidityAd
+
+	// AddLeaseInvoice adds an invoice for the fee of a channel lease we
+	// sell, returning its payment request and payment hash.
+	AddLeaseInvoice func(fee btcutil.Amount, memo string) (string,
+		lntypes.Hash, error)
+
+	// WaitForLeasePayment blocks until the invoice with the given payment
+	// hash is settled. ErrLeaseInvoiceCanceled is returned if the invoice
+	// is canceled, e.g. because it expired, and another error if the quit
+	// channel is closed.
+	WaitForLeasePayment func(hash lntypes.Hash,
+		quit <-chan struct{}) error
+
+	// LeaseFeePaid returns true if our payment of the invoice with the
+	// given payment hash, the fee of a lease we bought, succeeded or is
+	// still in flight.
+	LeaseFeePaid func(hash lntypes.Hash) (bool, error)
+
+	// MaxUnpaidLeases is the maximum number of leases sold through our
+	// liquidity ad whose fee isn't paid yet. Further lease requests are
+	// rejected until some of them are paid or expire. Zero disables the
+	// limit.
+	MaxUnpaidLeases uint32
+
+	// MaxUnpaidLeasesPerPeer is the maximum number of leases sold to a
+	// single peer whose fee isn't paid yet. Zero disables the limit.
+	MaxUnpaidLeasesPerPeer uint32
 }
 
 // Manager acts as an orchestrator/bridge between the wallet's
@@ -592,6 +650,17 @@ type Manager struct {
 	handleChannelReadyMtx      sync.RWMutex
 	handleChannelReadyBarriers map[lnwire.ChannelID]struct{}
 
+	// leaseRequests are the lease requests we sent that await the reply
+	// of the seller.
+	leaseRequests map[leaseKey]*leaseRequest
+
+	// pendingLeases are the leases we sold or bought whose channel isn't
+	// pending yet.
+	pendingLeases map[leaseKey]*PendingLease
+
+	// leaseMtx guards both of the lease maps above.
+	leaseMtx sync.Mutex
+
 	quit chan struct{}
 	wg   sync.WaitGroup
 }
@@ -659,7 +728,9 @@ func NewFundingManager(cfg Config) (*Manager, error) {
 		handleChannelReadyBarriers: make(
 			map[lnwire.ChannelID]struct{},
 		),
-		quit: make(chan struct{}),
+		leaseRequests: make(map[leaseKey]*leaseRequest),
+		pendingLeases: make(map[leaseKey]*PendingLease),
+		quit:          make(chan struct{}),
 	}, nil
 }
 
@@ -735,6 +806,13 @@ func (f *Manager) start() error {
 		go f.advanceFundingState(channel, chanID, nil)
 	}
 
+	if err := f.resumeSoldLeases(); err != nil {
+		return err
+	}
+	if err := f.resumeBoughtLeases(); err != nil {
+		return err
+	}
+
 	f.wg.Add(1) // TODO(roasbeef): tune
 	go f.reservationCoordinator()
 
@@ -861,8 +939,10 @@ func (f *Manager) failFundingFlow(peer lnpeer.Peer, tempChanID [32]byte,
 	}
 
 	// In case the case where the reservation existed, send the funding
-	// error on the error channel.
-	if ctx != nil {
+	// error on the error channel, unless we're going to retry the funding
+	// flow with another channel type.
+	retry := ctx != nil && len(ctx.fallbackTypes) > 0
+	if ctx != nil && !retry {
 		ctx.err <- fundingErr
 	}
 
@@ -894,6 +974,49 @@ func (f *Manager) failFundingFlow(peer lnpeer.Peer, tempChanID [32]byte,
 	if err := peer.SendMessage(false, errMsg); err != nil {
 		log.Errorf("unable to send error message to peer %v", err)
 	}
+
+	if retry {
+		f.retryFundingFlow(ctx, fundingErr)
+	}
+}
+
+// retryFundingFlow restarts a funding flow that failed before the remote peer
+// accepted the channel, proposing the next of the acceptable channel types
+// the caller provided.
+//
+// NOTE: This MUST be called from the reservationCoordinator goroutine.
+func (f *Manager) retryFundingFlow(resCtx *reservationWithCtx,
+	reason error) {
+
+	log.Infof("Retrying funding flow with peer %x using the next "+
+		"acceptable channel type after failure: %v",
+		resCtx.peer.IdentityKey().SerializeCompressed(), reason)
+
+	retryMsg := *resCtx.initMsg
+	retryMsg.ChannelTypes = resCtx.fallbackTypes
+	f.handleInitFundingMsg(&retryMsg)
+}
+
+// preferChannelType moves the given channel type to the front of a list of
+// channel types, if the list contains it.
+func preferChannelType(channelTypes []lnwire.ChannelType,
+	preferred lnwire.ChannelType) []lnwire.ChannelType {
+
+	preferredFeatures := lnwire.RawFeatureVector(preferred)
+	for i, channelType := range channelTypes {
+		features := lnwire.RawFeatureVector(channelType)
+		if !features.Equals(&preferredFeatures) {
+			continue
+		}
+
+		reordered := make([]lnwire.ChannelType, 0, len(channelTypes))
+		reordered = append(reordered, channelType)
+		reordered = append(reordered, channelTypes[:i]...)
+
+		return append(reordered, channelTypes[i+1:]...)
+	}
+
+	return channelTypes
 }
 
 // reservationCoordinator is the primary goroutine tasked with progressing the
@@ -931,12 +1054,19 @@ func (f *Manager) reservationCoordinator() {
 
 			case *lnwire.Error:
 				f.handleErrorMsg(fmsg.peer, msg)
+
+			case *lnwire.LeaseRequest:
+				f.handleLeaseRequest(fmsg.peer, msg)
+
+			case *lnwire.LeaseOffer:
+				f.handleLeaseOffer(fmsg.peer, msg)
 			}
 		case req := <-f.fundingRequests:
 			f.handleInitFundingMsg(req)
 
 		case <-zombieSweepTicker.C:
 			f.pruneZombieReservations()
+			f.pruneLeasePurchases()
 
 		case <-f.quit:
 			return
@@ -1537,6 +1667,24 @@ func (f *Manager) handleFundingOpen(peer lnpeer.Peer,
 		return
 	}
 
+	// A lease channel that wasn't registered through a shim may fulfill a
+	// lease we bought through the liquidity ad of the peer, in which case
+	// the lease expiry is taken from the request.
+	var (
+		leasePurchase *PendingLease
+		leaseExpiry   uint32
+	)
+	isLease := commitType == lnwallet.CommitmentTypeScriptEnforcedLease
+	if isLease && msg.LeaseExpiry != nil {
+		leaseExpiry = uint32(*msg.LeaseExpiry)
+		leasePurchase = f.matchLeasePurchase(
+			peer.IdentityKey(), amt, leaseExpiry,
+		)
+	}
+	if leasePurchase == nil {
+		leaseExpiry = 0
+	}
+
 	req := &lnwallet.InitFundingReserveMsg{
 		ChainHash:        &msg.ChainHash,
 		PendingChanID:    msg.PendingChannelID,
@@ -1553,6 +1701,7 @@ func (f *Manager) handleFundingOpen(peer lnpeer.Peer,
 		ZeroConf:         zeroConf,
 		OptionScidAlias:  scid,
 		ScidAliasFeature: scidFeatureVal,
+		LeaseExpiry:      leaseExpiry,
 	}
 
 	reservation, err := f.cfg.Wallet.InitChannelReservation(req)
@@ -1732,6 +1881,7 @@ func (f *Manager) handleFundingOpen(peer lnpeer.Peer,
 		remoteChanReserve: chanReserve,
 		maxLocalCsv:       f.cfg.MaxLocalCSVDelay,
 		channelType:       msg.ChannelType,
+		leasePurchase:     leasePurchase,
 		err:               make(chan error, 1),
 		peer:              peer,
 	}
@@ -1856,6 +2006,12 @@ func (f *Manager) handleFundingAccept(peer lnpeer.Peer,
 		proposedFeatures := lnwire.RawFeatureVector(*resCtx.channelType)
 		ackedFeatures := lnwire.RawFeatureVector(*msg.ChannelType)
 		if !proposedFeatures.Equals(&ackedFeatures) {
+			// If the remote peer answered with another channel
+			// type that we'd accept, we'll propose that one next.
+			resCtx.fallbackTypes = preferChannelType(
+				resCtx.fallbackTypes, *msg.ChannelType,
+			)
+
 			err := errors.New("channel type mismatch")
 			f.failFundingFlow(peer, msg.PendingChannelID, err)
 			return
@@ -1915,6 +2071,10 @@ func (f *Manager) handleFundingAccept(peer lnpeer.Peer,
 		}
 	}
 
+	// The remote peer accepted our channel type, so we won't fall back to
+	// another one if the funding flow fails from here on.
+	resCtx.fallbackTypes = nil
+
 	// The required number of confirmations should not be greater than the
 	// maximum number of confirmations required by the ChainNotifier to
 	// properly dispatch confirmations.
@@ -2244,6 +2404,12 @@ func (f *Manager) handleFundingCreated(peer lnpeer.Peer,
 	// Get forwarding policy before deleting the reservation context.
 	forwardingPolicy := resCtx.forwardingPolicy
 
+	// If the channel fulfills a lease we bought, the lease is now tracked
+	// through the pending channel.
+	if resCtx.leasePurchase != nil {
+		f.completeLeasePurchase(resCtx.leasePurchase)
+	}
+
 	// The channel is marked IsPending in the database, and can be removed
 	// from the set of active reservations.
 	f.deleteReservationCtx(peerKey, msg.PendingChannelID)
@@ -4055,6 +4221,23 @@ func (f *Manager) handleInitFundingMsg(msg *InitFundingMsg) {
 		return
 	}
 
+	// If the caller provided a list of acceptable channel types, we'll
+	// propose the first one that both of us support, and keep the ones
+	// after it to fall back to if the remote peer rejects it.
+	channelType := msg.ChannelType
+	var fallbackTypes []lnwire.ChannelType
+	if len(msg.ChannelTypes) > 0 {
+		idx, err := f.selectInitChannelType(msg)
+		if err != nil {
+			log.Errorf("channel type selection failed: %v", err)
+			msg.Err <- err
+			return
+		}
+
+		channelType = &msg.ChannelTypes[idx]
+		fallbackTypes = msg.ChannelTypes[idx+1:]
+	}
+
 	// Initialize a funding reservation with the local wallet. If the
 	// wallet doesn't have enough funds to commit to this channel, then the
 	// request will fail, and be aborted.
@@ -4063,7 +4246,7 @@ func (f *Manager) handleInitFundingMsg(msg *InitFundingMsg) {
 	// format we can use with this peer. This is dependent on *both* us and
 	// the remote peer are signaling the proper feature bit.
 	_, chanType, commitType, err := negotiateCommitmentType(
-		msg.ChannelType, msg.Peer.LocalFeatures(),
+		channelType, msg.Peer.LocalFeatures(),
 		msg.Peer.RemoteFeatures(), true,
 	)
 	if err != nil {
@@ -4142,6 +4325,7 @@ func (f *Manager) handleInitFundingMsg(msg *InitFundingMsg) {
 		OptionScidAlias:   scid,
 		ScidAliasFeature:  scidFeatureVal,
 		Memo:              msg.Memo,
+		LeaseExpiry:       msg.LeaseExpiry,
 	}
 
 	reservation, err := f.cfg.Wallet.InitChannelReservation(req)
@@ -4247,7 +4431,9 @@ func (f *Manager) handleInitFundingMsg(msg *InitFundingMsg) {
 		remoteMaxHtlcs:    maxHtlcs,
 		remoteChanReserve: chanReserve,
 		maxLocalCsv:       maxCSV,
-		channelType:       msg.ChannelType,
+		channelType:       channelType,
+		initMsg:           msg,
+		fallbackTypes:     fallbackTypes,
 		reservation:       reservation,
 		peer:              msg.Peer,
 		updates:           msg.Updates,
@@ -4333,6 +4519,25 @@ func (f *Manager) handleInitFundingMsg(msg *InitFundingMsg) {
 	}
 }
 
+// selectInitChannelType returns the index of the channel type to propose out
+// of the list of acceptable channel types of a funding request.
+func (f *Manager) selectInitChannelType(msg *InitFundingMsg) (int, error) {
+	switch {
+	case msg.ChannelType != nil:
+		return 0, errors.New("channel type and list of channel types " +
+			"are mutually exclusive")
+
+	case msg.ChanFunder != nil:
+		return 0, errors.New("list of channel types can't be used " +
+			"with a custom channel funder")
+	}
+
+	return selectChannelType(
+		msg.ChannelTypes, msg.Peer.LocalFeatures(),
+		msg.Peer.RemoteFeatures(), msg.Private,
+	)
+}
+
 // handleWarningMsg processes the warning which was received from remote peer.
 func (f *Manager) handleWarningMsg(peer lnpeer.Peer, msg *lnwire.Warning) {
 	log.Warnf("received warning message from peer %x: %v",
@@ -4346,6 +4551,12 @@ func (f *Manager) handleErrorMsg(peer lnpeer.Peer, msg *lnwire.Error) {
 	chanID := msg.ChanID
 	peerKey := peer.IdentityKey()
 
+	// The error may be the rejection of a lease request we sent, which
+	// isn't tied to any funding workflow yet.
+	if f.failLeaseRequest(peerKey, msg) {
+		return
+	}
+
 	// First, we'll attempt to retrieve and cancel the funding workflow
 	// that this error was tied to. If we're unable to do so, then we'll
 	// exit early as this was an unwarranted error.
@@ -4363,6 +4574,21 @@ func (f *Manager) handleErrorMsg(peer lnpeer.Peer, msg *lnwire.Error) {
 	)
 	log.Errorf(fundingErr.Error())
 
+	// If the remote peer rejected a channel we initiated and there are
+	// channel types left to fall back to, we'll retry with the next one,
+	// or the one the remote peer counter-offered if we'd accept it.
+	if len(resCtx.fallbackTypes) > 0 {
+		counterOffer, ok := chanacceptor.ParseCounterOffer(msg.Error())
+		if ok {
+			resCtx.fallbackTypes = preferChannelType(
+				resCtx.fallbackTypes, counterOffer,
+			)
+		}
+
+		f.retryFundingFlow(resCtx, fundingErr)
+		return
+	}
+
 	// If this was a PSBT funding flow, the remote likely timed out because
 	// we waited too long. Return a nice error message to the user in that
 	// case so the user knows what's the problem.
@@ -4506,7 +4732,8 @@ func (f *Manager) getReservationCtx(peerKey *btcec.PublicKey,
 // by the pendingChanID and given peer is pending, meaning it is in the process
 // of being funded. After the funding transaction has been confirmed, the
 // channel will receive a new, permanent channel ID, and will no longer be
-// considered pending.
+// considered pending. The identifiers of the lease requests we sent that await
+// a reply are considered pending as well.
 func (f *Manager) IsPendingChannel(pendingChanID [32]byte,
 	peer lnpeer.Peer) bool {
 
@@ -4515,7 +4742,7 @@ func (f *Manager) IsPendingChannel(pendingChanID [32]byte,
 	_, ok := f.activeReservations[peerIDKey][pendingChanID]
 	f.resMtx.RUnlock()
 
-	return ok
+	return ok || f.isLeaseRequest(peer.IdentityKey(), pendingChanID)
 }
 
 func copyPubKey(pub *btcec.PublicKey) *btcec.PublicKey {
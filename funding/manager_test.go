@@ -31,9 +31,11 @@ import (
 	"github.com/lightningnetwork/lnd/lnpeer"
 	"github.com/lightningnetwork/lnd/lnrpc"
 	"github.com/lightningnetwork/lnd/lntest/mock"
+	"github.com/lightningnetwork/lnd/lntypes"
 	"github.com/lightningnetwork/lnd/lnwallet"
 	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
 	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/zpay32"
 	"github.com/stretchr/testify/require"
 )
 
@@ -232,6 +234,28 @@ func (m *mockZeroConfAcceptor) Accept(
 	}
 }
 
+// mockChanTypeAcceptor rejects channels that don't use the expected channel
+// type, counter-offering that type instead.
+type mockChanTypeAcceptor struct {
+	chanType lnwire.ChannelType
+}
+
+func (m *mockChanTypeAcceptor) Accept(
+	req *acpt.ChannelAcceptRequest) *acpt.ChannelAcceptResponse {
+
+	expected := lnwire.RawFeatureVector(m.chanType)
+	requested := lnwire.RawFeatureVector(*req.OpenChanMsg.ChannelType)
+	if requested.Equals(&expected) {
+		return &acpt.ChannelAcceptResponse{}
+	}
+
+	return acpt.NewChannelAcceptResponse(
+		false, acpt.NewCounterOfferError(
+			errors.New("unwanted channel type"), m.chanType,
+		), nil, 0, 0, 0, 0, 0, 0, false,
+	)
+}
+
 type newChannelMsg struct {
 	channel *channeldb.OpenChannel
 	err     chan error
@@ -627,9 +651,14 @@ func recreateAliceFundingManager(t *testing.T, alice *testNode) {
 			FeeRate:       1000,
 			TimeLockDelta: 10,
 		},
-		DefaultMinHtlcIn:       5,
-		RequiredRemoteMaxValue: oldCfg.RequiredRemoteMaxValue,
-		ReportShortChanID:      oldCfg.ReportShortChanID,
+		DefaultMinHtlcIn:          5,
+		NumRequiredConfs:          oldCfg.NumRequiredConfs,
+		RequiredRemoteDelay:       oldCfg.RequiredRemoteDelay,
+		RequiredRemoteChanReserve: oldCfg.RequiredRemoteChanReserve,
+		RequiredRemoteMaxValue:    oldCfg.RequiredRemoteMaxValue,
+		RequiredRemoteMaxHTLCs:    oldCfg.RequiredRemoteMaxHTLCs,
+		WatchNewChannel:           oldCfg.WatchNewChannel,
+		ReportShortChanID:         oldCfg.ReportShortChanID,
 		PublishTransaction: func(txn *wire.MsgTx, _ string) error {
 			publishChan <- txn
 			return nil
@@ -639,10 +668,18 @@ func recreateAliceFundingManager(t *testing.T, alice *testNode) {
 		},
 		ZombieSweeperInterval:    oldCfg.ZombieSweeperInterval,
 		ReservationTimeout:       oldCfg.ReservationTimeout,
+		MaxChanSize:              oldCfg.MaxChanSize,
+		MaxLocalCSVDelay:         oldCfg.MaxLocalCSVDelay,
+		MaxPendingChannels:       oldCfg.MaxPendingChannels,
+		NotifyOpenChannelEvent:   oldCfg.NotifyOpenChannelEvent,
 		OpenChannelPredicate:     chainedAcceptor,
+		RegisteredChains:         oldCfg.RegisteredChains,
 		DeleteAliasEdge:          oldCfg.DeleteAliasEdge,
 		AliasManager:             oldCfg.AliasManager,
 		UpdateForwardingPolicies: oldCfg.UpdateForwardingPolicies,
+		LeaseFeePaid:             oldCfg.LeaseFeePaid,
+		NotifyPendingOpenChannelEvent: oldCfg.
+			NotifyPendingOpenChannelEvent,
 	})
 	require.NoError(t, err, "failed recreating aliceFundingManager")
 
@@ -918,6 +955,12 @@ func assertFundingMsgSent(t *testing.T, msgChan chan lnwire.Message,
 		sentMsg, ok = msg.(*lnwire.ChannelReady)
 	case "Error":
 		sentMsg, ok = msg.(*lnwire.Error)
+	case "OpenChannel":
+		sentMsg, ok = msg.(*lnwire.OpenChannel)
+	case "LeaseRequest":
+		sentMsg, ok = msg.(*lnwire.LeaseRequest)
+	case "LeaseOffer":
+		sentMsg, ok = msg.(*lnwire.LeaseOffer)
 	default:
 		t.Fatalf("unknown message type: %s", msgType)
 	}
@@ -4462,6 +4505,585 @@ func TestFundingManagerZeroConf(t *testing.T) {
 	assertNoFwdingPolicy(t, alice, bob, fundingOp)
 }
 
+// TestFundingManagerChannelTypeFallback tests that a funding flow using a list
+// of acceptable channel types is restarted with the channel type the remote
+// peer counter-offers when it rejects the proposed one.
+func TestFundingManagerChannelTypeFallback(t *testing.T) {
+	t.Parallel()
+
+	alice, bob := setupFundingManagers(t)
+	t.Cleanup(func() {
+		tearDownFundingManagers(t, alice, bob)
+	})
+
+	featureBits := []lnwire.FeatureBit{
+		lnwire.ScidAliasOptional,
+		lnwire.ExplicitChannelTypeOptional,
+		lnwire.StaticRemoteKeyOptional,
+		lnwire.AnchorsZeroFeeHtlcTxOptional,
+	}
+	alice.localFeatures = featureBits
+	alice.remoteFeatures = featureBits
+	bob.localFeatures = featureBits
+	bob.remoteFeatures = featureBits
+
+	anchors := lnwire.ChannelType(*lnwire.NewRawFeatureVector(
+		lnwire.StaticRemoteKeyRequired,
+		lnwire.AnchorsZeroFeeHtlcTxRequired,
+	))
+	anchorsScid := lnwire.ChannelType(*lnwire.NewRawFeatureVector(
+		lnwire.ScidAliasRequired,
+		lnwire.StaticRemoteKeyRequired,
+		lnwire.AnchorsZeroFeeHtlcTxRequired,
+	))
+	tweakless := lnwire.ChannelType(*lnwire.NewRawFeatureVector(
+		lnwire.StaticRemoteKeyRequired,
+	))
+
+	// Bob only accepts tweakless channels.
+	bob.fundingMgr.cfg.OpenChannelPredicate = &mockChanTypeAcceptor{
+		chanType: tweakless,
+	}
+
+	// Alice prefers anchor channels, but accepts all three channel types.
+	errChan := make(chan error, 1)
+	initReq := &InitFundingMsg{
+		Peer:            bob,
+		TargetPubkey:    bob.privKey.PubKey(),
+		ChainHash:       *fundingNetParams.GenesisHash,
+		LocalFundingAmt: 500000,
+		FundingFeePerKw: 1000,
+		Private:         true,
+		ChannelTypes: []lnwire.ChannelType{
+			anchors, anchorsScid, tweakless,
+		},
+		Updates: make(chan *lnrpc.OpenStatusUpdate),
+		Err:     errChan,
+	}
+	alice.fundingMgr.InitFundingWorkflow(initReq)
+
+	// assertOpenChannel asserts that Alice proposes a channel of the given
+	// type.
+	assertOpenChannel := func(chanType lnwire.ChannelType) {
+		t.Helper()
+
+		var msg lnwire.Message
+		select {
+		case msg = <-alice.msgChan:
+		case err := <-errChan:
+			t.Fatalf("error init funding workflow: %v", err)
+		case <-time.After(time.Second * 5):
+			t.Fatalf("alice did not send OpenChannel message")
+		}
+
+		openChannel, ok := msg.(*lnwire.OpenChannel)
+		require.True(t, ok)
+		require.NotNil(t, openChannel.ChannelType)
+
+		expected := lnwire.RawFeatureVector(chanType)
+		proposed := lnwire.RawFeatureVector(*openChannel.ChannelType)
+		require.True(t, proposed.Equals(&expected))
+
+		bob.fundingMgr.ProcessFundingMsg(openChannel, alice)
+	}
+
+	// Alice first proposes an anchor channel, which Bob rejects.
+	assertOpenChannel(anchors)
+	errMsg := assertFundingMsgSent(t, bob.msgChan, "Error")
+	alice.fundingMgr.ProcessFundingMsg(errMsg, bob)
+
+	// Following Bob's counter-offer, Alice skips the second channel type
+	// and proposes a tweakless channel, which Bob accepts.
+	assertOpenChannel(tweakless)
+	acceptChannel := assertFundingMsgSent(t, bob.msgChan, "AcceptChannel")
+	alice.fundingMgr.ProcessFundingMsg(acceptChannel, bob)
+	assertFundingMsgSent(t, alice.msgChan, "FundingCreated")
+
+	// The rejected attempt wasn't reported to the caller.
+	select {
+	case err := <-errChan:
+		t.Fatalf("unexpected funding error: %v", err)
+	default:
+	}
+	assertNumPendingReservations(t, alice, bobPubKey, 1)
+}
+
+// setupLeaseSeller makes Bob sell leases through the given liquidity ad to
+// Alice, waiting for the payment of the lease fees with the given function.
+func setupLeaseSeller(alice, bob *testNode, ad *lnwire.LiquidityAd,
+	waitForPayment func(lntypes.Hash, <-chan struct{}) error) {
+
+	featureBits := []lnwire.FeatureBit{
+		lnwire.ExplicitChannelTypeOptional,
+		lnwire.StaticRemoteKeyOptional,
+		lnwire.AnchorsZeroFeeHtlcTxOptional,
+		lnwire.ScriptEnforcedLeaseOptional,
+	}
+	alice.localFeatures = featureBits
+	alice.remoteFeatures = featureBits
+	bob.localFeatures = featureBits
+	bob.remoteFeatures = featureBits
+
+	bob.fundingMgr.cfg.LiquidityAd = func() *lnwire.LiquidityAd {
+		return ad
+	}
+	bob.fundingMgr.cfg.AddLeaseInvoice = func(fee btcutil.Amount,
+		memo string) (string, lntypes.Hash, error) {
+
+		var preimage lntypes.Preimage
+		hash := preimage.Hash()
+		invoice, err := zpay32.NewInvoice(
+			fundingNetParams.Params, hash, time.Now(),
+			zpay32.Amount(lnwire.NewMSatFromSatoshis(fee)),
+			zpay32.Description(memo),
+		)
+		if err != nil {
+			return "", hash, err
+		}
+
+		payReq, err := invoice.Encode(zpay32.MessageSigner{
+			SignCompact: func(msg []byte) ([]byte, error) {
+				return ecdsa.SignCompact(
+					bobPrivKey, chainhash.HashB(msg), true,
+				)
+			},
+		})
+
+		return payReq, hash, err
+	}
+	bob.fundingMgr.cfg.WaitForLeasePayment = waitForPayment
+}
+
+// TestFundingManagerLiquidityAdLease tests that a channel lease can be bought
+// through the liquidity ad of a peer, which opens the lease channel once the
+// lease fee is paid.
+func TestFundingManagerLiquidityAdLease(t *testing.T) {
+	t.Parallel()
+
+	alice, bob := setupFundingManagers(t)
+	t.Cleanup(func() {
+		tearDownFundingManagers(t, alice, bob)
+	})
+
+	// Bob sells leases of up to 1 BTC, and creates invoices for the lease
+	// fees that are paid once we close the paid channel.
+	const leaseDuration = 144
+	ad := &lnwire.LiquidityAd{
+		LeaseDuration: leaseDuration,
+		FeeBase:       1000,
+		FeeRate:       10_000,
+		MinLeaseAmt:   100_000,
+		MaxLeaseAmt:   1_000_000,
+	}
+	paid := make(chan struct{})
+	setupLeaseSeller(alice, bob, ad, func(_ lntypes.Hash,
+		quit <-chan struct{}) error {
+
+		select {
+		case <-paid:
+			return nil
+		case <-quit:
+			return ErrFundingManagerShuttingDown
+		}
+	})
+
+	// requestLease lets Alice request a lease from Bob, relaying the
+	// request to Bob and returning the reply he sent.
+	requestLease := func(amt btcutil.Amount) (lnwire.Message,
+		chan *PendingLease, chan error) {
+
+		leaseChan := make(chan *PendingLease, 1)
+		errChan := make(chan error, 1)
+		go func() {
+			lease, err := alice.fundingMgr.RequestLease(
+				bob, amt, leaseDuration, 20_000,
+			)
+			if err != nil {
+				errChan <- err
+				return
+			}
+			leaseChan <- lease
+		}()
+
+		req := assertFundingMsgSent(t, alice.msgChan, "LeaseRequest")
+		bob.fundingMgr.ProcessFundingMsg(req, alice)
+
+		select {
+		case reply := <-bob.msgChan:
+			return reply, leaseChan, errChan
+		case <-time.After(time.Second * 5):
+			t.Fatalf("bob did not reply to lease request")
+		}
+
+		return nil, nil, nil
+	}
+
+	// Bob rejects a lease above the advertised amount.
+	reply, _, errChan := requestLease(2_000_000)
+	require.IsType(t, &lnwire.Error{}, reply)
+	alice.fundingMgr.ProcessFundingMsg(reply, bob)
+	select {
+	case err := <-errChan:
+		require.ErrorContains(t, err, "outside of the advertised range")
+	case <-time.After(time.Second * 5):
+		t.Fatalf("lease request not rejected")
+	}
+
+	// A lease within the advertised range is offered for the advertised
+	// fee.
+	reply, leaseChan, errChan := requestLease(500_000)
+	require.IsType(t, &lnwire.LeaseOffer{}, reply)
+	alice.fundingMgr.ProcessFundingMsg(reply, bob)
+
+	var lease *PendingLease
+	select {
+	case lease = <-leaseChan:
+	case err := <-errChan:
+		t.Fatalf("lease request failed: %v", err)
+	case <-time.After(time.Second * 5):
+		t.Fatalf("lease not offered")
+	}
+	require.Equal(t, ad.Fee(500_000), lease.Fee)
+	require.EqualValues(
+		t, fundingBroadcastHeight+leaseDuration, lease.LeaseExpiry,
+	)
+	require.Len(t, alice.fundingMgr.PendingLeases(), 1)
+	require.Len(t, bob.fundingMgr.PendingLeases(), 1)
+	require.True(t, bob.fundingMgr.PendingLeases()[0].Sold)
+
+	// Once the fee is paid, Bob opens the lease channel, which Alice
+	// accepts as it fulfills the lease she bought.
+	close(paid)
+	openChannel := assertFundingMsgSent(t, bob.msgChan, "OpenChannel")
+	openMsg := openChannel.(*lnwire.OpenChannel)
+	require.NotNil(t, openMsg.LeaseExpiry)
+	require.EqualValues(t, lease.LeaseExpiry, *openMsg.LeaseExpiry)
+	require.EqualValues(t, 500_000, openMsg.FundingAmount)
+
+	alice.fundingMgr.ProcessFundingMsg(openChannel, bob)
+	acceptChannel := assertFundingMsgSent(t, alice.msgChan, "AcceptChannel")
+	bob.fundingMgr.ProcessFundingMsg(acceptChannel, alice)
+	fundingCreated := assertFundingMsgSent(
+		t, bob.msgChan, "FundingCreated",
+	)
+	alice.fundingMgr.ProcessFundingMsg(fundingCreated, bob)
+	fundingSigned := assertFundingMsgSent(t, alice.msgChan, "FundingSigned")
+	bob.fundingMgr.ProcessFundingMsg(fundingSigned, alice)
+
+	// With the channel pending, neither of them tracks the lease anymore.
+	require.Eventually(t, func() bool {
+		return len(alice.fundingMgr.PendingLeases()) == 0 &&
+			len(bob.fundingMgr.PendingLeases()) == 0
+	}, time.Second*5, time.Millisecond*50)
+
+	// The lease expiry is enforced by the pending channel.
+	pendingChans, err := alice.fundingMgr.cfg.Wallet.Cfg.Database.
+		FetchPendingChannels()
+	require.NoError(t, err)
+	require.Len(t, pendingChans, 1)
+	require.EqualValues(t, lease.LeaseExpiry, pendingChans[0].ThawHeight)
+	require.True(t, pendingChans[0].ChanType.HasLeaseExpiration())
+}
+
+// TestFundingManagerLeaseLimits tests that lease requests are rejected once
+// too many leases are unpaid, or if the wallet can't fund the lease on top of
+// the other leases we sold.
+func TestFundingManagerLeaseLimits(t *testing.T) {
+	t.Parallel()
+
+	alice, bob := setupFundingManagers(t)
+	t.Cleanup(func() {
+		tearDownFundingManagers(t, alice, bob)
+	})
+
+	// The wallet of Bob holds 10 BTC, and none of the leases he sells are
+	// paid.
+	ad := &lnwire.LiquidityAd{
+		LeaseDuration: 144,
+		MinLeaseAmt:   100_000,
+		MaxLeaseAmt:   10 * btcutil.SatoshiPerBitcoin,
+	}
+	setupLeaseSeller(alice, bob, ad, func(_ lntypes.Hash,
+		quit <-chan struct{}) error {
+
+		<-quit
+		return ErrFundingManagerShuttingDown
+	})
+	bob.fundingMgr.cfg.MaxUnpaidLeases = 2
+	bob.fundingMgr.cfg.MaxUnpaidLeasesPerPeer = 1
+
+	carolKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	carolPubKey := carolKey.PubKey()
+
+	var nextID byte
+	offerLease := func(peer *btcec.PublicKey, amt btcutil.Amount) error {
+		nextID++
+		_, err := bob.fundingMgr.offerLease(peer, &lnwire.LeaseRequest{
+			LeaseID:       [32]byte{nextID},
+			ChainHash:     *fundingNetParams.GenesisHash,
+			LeaseAmt:      amt,
+			LeaseDuration: ad.LeaseDuration,
+			MaxFee:        btcutil.SatoshiPerBitcoin,
+		})
+
+		return err
+	}
+
+	require.NoError(t, offerLease(alicePubKey, btcutil.SatoshiPerBitcoin))
+
+	// Alice already has an unpaid lease.
+	err = offerLease(alicePubKey, btcutil.SatoshiPerBitcoin)
+	require.ErrorIs(t, err, ErrLeaseRejected)
+	require.ErrorContains(t, err, "too many unpaid leases of peer")
+
+	// The wallet can't fund another 9.5 BTC on top of the lease of Alice.
+	err = offerLease(bobPubKey, 95*btcutil.SatoshiPerBitcoin/10)
+	require.ErrorIs(t, err, ErrLeaseRejected)
+	require.ErrorContains(t, err, "not enough funds")
+
+	require.NoError(t, offerLease(bobPubKey, btcutil.SatoshiPerBitcoin))
+
+	// With two unpaid leases, no further leases are sold.
+	err = offerLease(carolPubKey, btcutil.SatoshiPerBitcoin)
+	require.ErrorIs(t, err, ErrLeaseRejected)
+	require.ErrorContains(t, err, "too many unpaid leases")
+
+	// The offered leases are persisted.
+	soldLeases, err := bob.fundingMgr.cfg.Wallet.Cfg.Database.
+		FetchSoldLeases()
+	require.NoError(t, err)
+	require.Len(t, soldLeases, 2)
+}
+
+// TestFundingManagerResumeSoldLease tests that the leases we sold are resumed
+// on startup, opening the channels of the paid ones unless they were opened
+// already, and dropping the ones whose invoice was canceled.
+func TestFundingManagerResumeSoldLease(t *testing.T) {
+	t.Parallel()
+
+	alice, bob := setupFundingManagers(t)
+	t.Cleanup(func() {
+		tearDownFundingManagers(t, alice, bob)
+	})
+
+	const leaseDuration = 144
+	ad := &lnwire.LiquidityAd{
+		LeaseDuration: leaseDuration,
+		MinLeaseAmt:   100_000,
+		MaxLeaseAmt:   1_000_000,
+	}
+	canceledHash := lntypes.Hash{1}
+	setupLeaseSeller(alice, bob, ad, func(hash lntypes.Hash,
+		_ <-chan struct{}) error {
+
+		if hash == canceledHash {
+			return ErrLeaseInvoiceCanceled
+		}
+
+		return nil
+	})
+
+	// Bob went down with a paid lease and a lease whose invoice was
+	// canceled since.
+	leaseExpiry := uint32(fundingBroadcastHeight + leaseDuration)
+	paidLease := &channeldb.SoldLease{
+		LeaseID:       [32]byte{1},
+		Buyer:         alicePubKey,
+		LeaseAmt:      500_000,
+		LeaseDuration: leaseDuration,
+		LeaseExpiry:   leaseExpiry,
+		PaymentHash:   lntypes.Hash{2},
+		CreatedAt:     time.Unix(100, 0),
+	}
+	canceledLease := &channeldb.SoldLease{
+		LeaseID:       [32]byte{2},
+		Buyer:         alicePubKey,
+		LeaseAmt:      500_000,
+		LeaseDuration: leaseDuration,
+		LeaseExpiry:   leaseExpiry,
+		PaymentHash:   canceledHash,
+		CreatedAt:     time.Unix(100, 0),
+	}
+	db := bob.fundingMgr.cfg.Wallet.Cfg.Database
+	require.NoError(t, db.PutSoldLease(paidLease))
+	require.NoError(t, db.PutSoldLease(canceledLease))
+
+	// Alice bought the paid lease.
+	purchaseKey := newLeaseKey(bobPubKey, paidLease.LeaseID)
+	alice.fundingMgr.leaseMtx.Lock()
+	alice.fundingMgr.pendingLeases[purchaseKey] = &PendingLease{
+		LeaseID:     paidLease.LeaseID,
+		Peer:        bobPubKey,
+		LeaseAmt:    paidLease.LeaseAmt,
+		LeaseExpiry: leaseExpiry,
+		created:     time.Now(),
+	}
+	alice.fundingMgr.leaseMtx.Unlock()
+
+	require.NoError(t, bob.fundingMgr.resumeSoldLeases())
+
+	// Bob opens the channel of the paid lease.
+	openChannel := assertFundingMsgSent(t, bob.msgChan, "OpenChannel")
+	openMsg := openChannel.(*lnwire.OpenChannel)
+	require.NotNil(t, openMsg.LeaseExpiry)
+	require.EqualValues(t, leaseExpiry, *openMsg.LeaseExpiry)
+
+	alice.fundingMgr.ProcessFundingMsg(openChannel, bob)
+	acceptChannel := assertFundingMsgSent(t, alice.msgChan, "AcceptChannel")
+	bob.fundingMgr.ProcessFundingMsg(acceptChannel, alice)
+	fundingCreated := assertFundingMsgSent(
+		t, bob.msgChan, "FundingCreated",
+	)
+	alice.fundingMgr.ProcessFundingMsg(fundingCreated, bob)
+	fundingSigned := assertFundingMsgSent(t, alice.msgChan, "FundingSigned")
+	bob.fundingMgr.ProcessFundingMsg(fundingSigned, alice)
+
+	// Neither of the leases is tracked anymore.
+	assertNoSoldLeases := func() {
+		require.Eventually(t, func() bool {
+			soldLeases, err := db.FetchSoldLeases()
+
+			return err == nil && len(soldLeases) == 0 &&
+				len(bob.fundingMgr.PendingLeases()) == 0
+		}, time.Second*5, time.Millisecond*50)
+	}
+	assertNoSoldLeases()
+
+	// If Bob went down after the channel of the paid lease was pending,
+	// but before the lease was removed, the channel isn't opened again.
+	require.NoError(t, db.PutSoldLease(paidLease))
+	require.NoError(t, bob.fundingMgr.resumeSoldLeases())
+	assertNoSoldLeases()
+
+	select {
+	case msg := <-bob.msgChan:
+		t.Fatalf("unexpected message: %v", msg.MsgType())
+	case <-time.After(time.Millisecond * 100):
+	}
+}
+
+// TestFundingManagerResumeBoughtLease tests that the leases we bought are
+// resumed on startup, so that we still accept their channels, and that only
+// the ones we didn't pay for are pruned once they're old.
+func TestFundingManagerResumeBoughtLease(t *testing.T) {
+	t.Parallel()
+
+	alice, bob := setupFundingManagers(t)
+	t.Cleanup(func() {
+		tearDownFundingManagers(t, alice, bob)
+	})
+
+	const leaseDuration = 144
+	ad := &lnwire.LiquidityAd{
+		LeaseDuration: leaseDuration,
+		MinLeaseAmt:   100_000,
+		MaxLeaseAmt:   1_000_000,
+	}
+	paid := make(chan struct{})
+	setupLeaseSeller(alice, bob, ad, func(_ lntypes.Hash,
+		quit <-chan struct{}) error {
+
+		select {
+		case <-paid:
+			return nil
+		case <-quit:
+			return ErrFundingManagerShuttingDown
+		}
+	})
+
+	// Alice buys a lease from Bob.
+	leaseChan := make(chan *PendingLease, 1)
+	errChan := make(chan error, 1)
+	go func() {
+		lease, err := alice.fundingMgr.RequestLease(
+			bob, 500_000, leaseDuration, 20_000,
+		)
+		if err != nil {
+			errChan <- err
+			return
+		}
+		leaseChan <- lease
+	}()
+
+	req := assertFundingMsgSent(t, alice.msgChan, "LeaseRequest")
+	bob.fundingMgr.ProcessFundingMsg(req, alice)
+	offer := assertFundingMsgSent(t, bob.msgChan, "LeaseOffer")
+	alice.fundingMgr.ProcessFundingMsg(offer, bob)
+
+	var lease *PendingLease
+	select {
+	case lease = <-leaseChan:
+	case err := <-errChan:
+		t.Fatalf("lease request failed: %v", err)
+	case <-time.After(time.Second * 5):
+		t.Fatalf("lease not offered")
+	}
+
+	// Alice went down with the paid lease and a lease she never paid for,
+	// both of which are older than the purchase timeout.
+	db := alice.fundingMgr.cfg.Wallet.Cfg.Database
+	paidLease := lease.boughtLease()
+	paidLease.CreatedAt = time.Unix(100, 0)
+	require.NoError(t, db.PutBoughtLease(paidLease))
+
+	unpaidLease := &channeldb.BoughtLease{
+		LeaseID:       [32]byte{1},
+		Seller:        bobPubKey,
+		LeaseAmt:      100_000,
+		LeaseDuration: leaseDuration,
+		LeaseExpiry:   lease.LeaseExpiry,
+		PaymentHash:   lntypes.Hash{1},
+		CreatedAt:     paidLease.CreatedAt,
+	}
+	require.NoError(t, db.PutBoughtLease(unpaidLease))
+
+	alice.fundingMgr.cfg.LeaseFeePaid = func(hash lntypes.Hash) (bool,
+		error) {
+
+		return hash == lease.paymentHash, nil
+	}
+	recreateAliceFundingManager(t, alice)
+	require.Len(t, alice.fundingMgr.PendingLeases(), 2)
+
+	// Only the lease she didn't pay for is pruned.
+	alice.fundingMgr.pruneLeasePurchases()
+	leases := alice.fundingMgr.PendingLeases()
+	require.Len(t, leases, 1)
+	require.Equal(t, lease.LeaseID, leases[0].LeaseID)
+
+	boughtLeases, err := db.FetchBoughtLeases()
+	require.NoError(t, err)
+	require.Equal(t, []*channeldb.BoughtLease{paidLease}, boughtLeases)
+
+	// Once Bob receives the fee, he opens the lease channel, which Alice
+	// still accepts.
+	close(paid)
+	openChannel := assertFundingMsgSent(t, bob.msgChan, "OpenChannel")
+	alice.fundingMgr.ProcessFundingMsg(openChannel, bob)
+	acceptChannel := assertFundingMsgSent(t, alice.msgChan, "AcceptChannel")
+	bob.fundingMgr.ProcessFundingMsg(acceptChannel, alice)
+	fundingCreated := assertFundingMsgSent(
+		t, bob.msgChan, "FundingCreated",
+	)
+	alice.fundingMgr.ProcessFundingMsg(fundingCreated, bob)
+	fundingSigned := assertFundingMsgSent(t, alice.msgChan, "FundingSigned")
+	bob.fundingMgr.ProcessFundingMsg(fundingSigned, alice)
+
+	// With the channel pending, the lease isn't tracked anymore.
+	require.Eventually(t, func() bool {
+		boughtLeases, err := db.FetchBoughtLeases()
+
+		return err == nil && len(boughtLeases) == 0 &&
+			len(alice.fundingMgr.PendingLeases()) == 0
+	}, time.Second*5, time.Millisecond*50)
+
+	pendingChans, err := db.FetchPendingChannels()
+	require.NoError(t, err)
+	require.Len(t, pendingChans, 1)
+	require.EqualValues(t, lease.LeaseExpiry, pendingChans[0].ThawHeight)
+}
+
 // TestCommitmentTypeFundmaxSanityCheck was introduced as a way of reminding
 // developers of new channel commitment types to also consider the channel
 // opening behavior with a specified fundmax flag. To give a hypothetical
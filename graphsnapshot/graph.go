@@ -0,0 +1,413 @@
+package graphsnapshot
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/lightningnetwork/lnd/batch"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/netann"
+	"github.com/lightningnetwork/lnd/routing"
+)
+
+// GraphSource is the channel graph a snapshot is taken of.
+type GraphSource interface {
+	// ForEachChannel iterates through all the channel edges stored within
+	// the graph.
+	ForEachChannel(cb func(*channeldb.ChannelEdgeInfo,
+		*channeldb.ChannelEdgePolicy,
+		*channeldb.ChannelEdgePolicy) error) error
+
+	// ForEachNode iterates through all the stored vertices/nodes in the
+	// graph.
+	ForEachNode(cb func(kvdb.RTx, *channeldb.LightningNode) error) error
+}
+
+// GraphSink is the channel graph a snapshot is imported into.
+type GraphSink interface {
+	// AddTrustedEdge adds a channel whose capacity and funding outpoint
+	// are trusted to the graph, without looking them up on chain.
+	AddTrustedEdge(edge *channeldb.ChannelEdgeInfo) error
+
+	// UpdateEdge updates the policy of one of the directions of a
+	// channel.
+	UpdateEdge(policy *channeldb.ChannelEdgePolicy,
+		op ...batch.SchedulerOption) error
+
+	// AddNode updates the information of a node of the graph.
+	AddNode(node *channeldb.LightningNode,
+		op ...batch.SchedulerOption) error
+}
+
+// Export takes a snapshot of the public part of the given channel graph. Only
+// channels with an authentication proof and nodes with a signed announcement
+// are included, so that every element of the snapshot can be verified.
+func Export(graph GraphSource, chainHash chainhash.Hash) (*Snapshot, error) {
+	snapshot := &Snapshot{
+		Version:   DefaultVersion,
+		ChainHash: chainHash,
+		Timestamp: time.Now(),
+	}
+
+	err := graph.ForEachChannel(func(info *channeldb.ChannelEdgeInfo,
+		e1, e2 *channeldb.ChannelEdgePolicy) error {
+
+		// Private channels and channels whose announcement signatures
+		// we haven't exchanged yet can't be part of the snapshot.
+		if info.AuthProof == nil || info.AuthProof.IsEmpty() {
+			return nil
+		}
+
+		channel := Channel{
+			Capacity:     info.Capacity,
+			ChannelPoint: info.ChannelPoint,
+		}
+
+		var (
+			update1, update2 *lnwire.ChannelUpdate
+			err              error
+		)
+		if info.Version == channeldb.ChanAnnVersion2 {
+			channel.Announcement, update1, update2, err =
+				netann.CreateChanAnnouncement2(
+					info.AuthProof, info, e1, e2,
+				)
+		} else {
+			channel.Announcement, update1, update2, err =
+				netann.CreateChanAnnouncement(
+					info.AuthProof, info, e1, e2,
+				)
+		}
+		if err != nil {
+			return fmt.Errorf("unable to create announcement for "+
+				"channel %v: %w", info.ChannelID, err)
+		}
+
+		if update1 != nil {
+			channel.Updates = append(channel.Updates, update1)
+		}
+		if update2 != nil {
+			channel.Updates = append(channel.Updates, update2)
+		}
+
+		snapshot.Channels = append(snapshot.Channels, channel)
+
+		return nil
+	})
+	if err != nil && err != channeldb.ErrGraphNoEdgesFound {
+		return nil, err
+	}
+
+	err = graph.ForEachNode(func(_ kvdb.RTx,
+		node *channeldb.LightningNode) error {
+
+		if !node.HaveNodeAnnouncement || len(node.AuthSigBytes) == 0 {
+			return nil
+		}
+
+		nodeAnn, err := node.NodeAnnouncement(true)
+		if err != nil {
+			return fmt.Errorf("unable to create announcement for "+
+				"node %x: %w", node.PubKeyBytes, err)
+		}
+
+		snapshot.Nodes = append(snapshot.Nodes, nodeAnn)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return snapshot, nil
+}
+
+// ImportSummary describes the outcome of importing a snapshot. Elements of
+// the snapshot are skipped if the graph already has the same or a more recent
+// version of them.
+type ImportSummary struct {
+	// ChannelsImported is the number of channels added to the graph.
+	ChannelsImported int
+
+	// ChannelsSkipped is the number of channels the graph already knew
+	// of.
+	ChannelsSkipped int
+
+	// UpdatesImported is the number of channel updates applied.
+	UpdatesImported int
+
+	// UpdatesSkipped is the number of channel updates that were outdated.
+	UpdatesSkipped int
+
+	// NodesImported is the number of node announcements applied.
+	NodesImported int
+
+	// NodesSkipped is the number of node announcements that were
+	// outdated.
+	NodesSkipped int
+}
+
+// Verify checks the signatures of all the announcements of the snapshot, and
+// that all of them belong to the given chain. Other than the capacity and the
+// funding outpoint of the channels, which aren't signed, this proves the
+// authenticity of the snapshot.
+func Verify(snapshot *Snapshot, chainHash chainhash.Hash) error {
+	if snapshot.ChainHash != chainHash {
+		return fmt.Errorf("snapshot is for chain %v, expected %v",
+			snapshot.ChainHash, chainHash)
+	}
+
+	for _, channel := range snapshot.Channels {
+		var (
+			nodeID1, nodeID2 [33]byte
+			annChainHash     chainhash.Hash
+			err              error
+		)
+		switch ann := channel.Announcement.(type) {
+		case *lnwire.ChannelAnnouncement:
+			nodeID1, nodeID2 = ann.NodeID1, ann.NodeID2
+			annChainHash = ann.ChainHash
+			err = routing.ValidateChannelAnn(ann)
+
+		case *lnwire.ChannelAnnouncement2:
+			nodeID1, nodeID2 = ann.NodeID1, ann.NodeID2
+			annChainHash = ann.ChainHash
+			err = routing.ValidateChannelAnn2(ann)
+
+		default:
+			err = fmt.Errorf("unknown channel announcement type "+
+				"%T", ann)
+		}
+		if err != nil {
+			return err
+		}
+
+		if annChainHash != chainHash {
+			return fmt.Errorf("channel %v is for chain %v",
+				channel.ChannelPoint, annChainHash)
+		}
+
+		scid, err := channel.ShortChannelID()
+		if err != nil {
+			return err
+		}
+
+		for _, update := range channel.Updates {
+			if update.ShortChannelID != scid {
+				return fmt.Errorf("update for channel %v "+
+					"doesn't match channel %v",
+					update.ShortChannelID, scid)
+			}
+
+			nodeID := nodeID1
+			if update.ChannelFlags&lnwire.ChanUpdateDirection != 0 {
+				nodeID = nodeID2
+			}
+
+			pubKey, err := btcec.ParsePubKey(nodeID[:])
+			if err != nil {
+				return err
+			}
+
+			err = routing.ValidateChannelUpdateAnn(
+				pubKey, channel.Capacity, update,
+			)
+			if err != nil {
+				return fmt.Errorf("invalid update for channel "+
+					"%v: %w", scid, err)
+			}
+		}
+	}
+
+	for _, node := range snapshot.Nodes {
+		if err := routing.ValidateNodeAnn(node); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Import adds the channels, channel updates and node announcements of the
+// snapshot to the graph. The snapshot is trusted, so its signatures should be
+// checked with Verify beforehand if the snapshot doesn't come from a trusted
+// source. Elements the graph already knows the same or a more recent version
+// of are skipped.
+func Import(graph GraphSink, snapshot *Snapshot) (*ImportSummary, error) {
+	var summary ImportSummary
+
+	// The channels are imported first, as updates and node announcements
+	// are only accepted once the graph knows of the channels they're for.
+	for _, channel := range snapshot.Channels {
+		channel := channel
+
+		edge, err := edgeInfoFromChannel(&channel)
+		if err != nil {
+			return nil, err
+		}
+
+		err = graph.AddTrustedEdge(edge)
+		switch {
+		case isSkipped(err):
+			summary.ChannelsSkipped++
+
+		case err != nil:
+			return nil, fmt.Errorf("unable to import channel %v: "+
+				"%w", edge.ChannelID, err)
+
+		default:
+			summary.ChannelsImported++
+		}
+
+		for _, update := range channel.Updates {
+			err := graph.UpdateEdge(edgePolicyFromUpdate(update))
+			switch {
+			case isSkipped(err):
+				summary.UpdatesSkipped++
+
+			case err != nil:
+				return nil, fmt.Errorf("unable to import "+
+					"update for channel %v: %w",
+					edge.ChannelID, err)
+
+			default:
+				summary.UpdatesImported++
+			}
+		}
+	}
+
+	for _, nodeAnn := range snapshot.Nodes {
+		err := graph.AddNode(nodeFromAnn(nodeAnn))
+		switch {
+		case isSkipped(err):
+			summary.NodesSkipped++
+
+		case err != nil:
+			return nil, fmt.Errorf("unable to import node %x: %w",
+				nodeAnn.NodeID, err)
+
+		default:
+			summary.NodesImported++
+		}
+	}
+
+	return &summary, nil
+}
+
+// isSkipped returns true if the error signals that an element of the
+// snapshot wasn't added to the graph as it already knew of it.
+func isSkipped(err error) bool {
+	return routing.IsError(err, routing.ErrIgnored, routing.ErrOutdated)
+}
+
+// edgeInfoFromChannel converts a channel of a snapshot to its database
+// representation.
+func edgeInfoFromChannel(channel *Channel) (*channeldb.ChannelEdgeInfo,
+	error) {
+
+	var featureBuf bytes.Buffer
+
+	switch ann := channel.Announcement.(type) {
+	case *lnwire.ChannelAnnouncement:
+		if err := ann.Features.Encode(&featureBuf); err != nil {
+			return nil, err
+		}
+
+		return &channeldb.ChannelEdgeInfo{
+			ChannelID:        ann.ShortChannelID.ToUint64(),
+			ChainHash:        ann.ChainHash,
+			NodeKey1Bytes:    ann.NodeID1,
+			NodeKey2Bytes:    ann.NodeID2,
+			BitcoinKey1Bytes: ann.BitcoinKey1,
+			BitcoinKey2Bytes: ann.BitcoinKey2,
+			AuthProof: &channeldb.ChannelAuthProof{
+				NodeSig1Bytes: ann.NodeSig1.ToSignatureBytes(),
+				NodeSig2Bytes: ann.NodeSig2.ToSignatureBytes(),
+				BitcoinSig1Bytes: ann.BitcoinSig1.
+					ToSignatureBytes(),
+				BitcoinSig2Bytes: ann.BitcoinSig2.
+					ToSignatureBytes(),
+			},
+			Features:        featureBuf.Bytes(),
+			Capacity:        channel.Capacity,
+			ChannelPoint:    channel.ChannelPoint,
+			ExtraOpaqueData: ann.ExtraOpaqueData,
+		}, nil
+
+	case *lnwire.ChannelAnnouncement2:
+		if err := ann.Features.Encode(&featureBuf); err != nil {
+			return nil, err
+		}
+
+		edge := &channeldb.ChannelEdgeInfo{
+			ChannelID:     ann.ShortChannelID.ToUint64(),
+			ChainHash:     ann.ChainHash,
+			NodeKey1Bytes: ann.NodeID1,
+			NodeKey2Bytes: ann.NodeID2,
+			Features:      featureBuf.Bytes(),
+			Version:       channeldb.ChanAnnVersion2,
+			AuthProof: &channeldb.ChannelAuthProof{
+				SchnorrSigBytes: ann.Signature[:],
+			},
+			Capacity:        channel.Capacity,
+			ChannelPoint:    channel.ChannelPoint,
+			MerkleRootHash:  ann.MerkleRootHash,
+			ExtraOpaqueData: ann.ExtraOpaqueData,
+		}
+		if ann.BitcoinKey1 != nil && ann.BitcoinKey2 != nil {
+			edge.BitcoinKey1Bytes = *ann.BitcoinKey1
+			edge.BitcoinKey2Bytes = *ann.BitcoinKey2
+		}
+
+		return edge, nil
+
+	default:
+		return nil, fmt.Errorf("unknown channel announcement type %T",
+			ann)
+	}
+}
+
+// edgePolicyFromUpdate converts a channel update of a snapshot to its
+// database representation.
+func edgePolicyFromUpdate(
+	update *lnwire.ChannelUpdate) *channeldb.ChannelEdgePolicy {
+
+	timestamp := time.Unix(int64(update.Timestamp), 0)
+
+	return &channeldb.ChannelEdgePolicy{
+		SigBytes:                  update.Signature.ToSignatureBytes(),
+		ChannelID:                 update.ShortChannelID.ToUint64(),
+		LastUpdate:                timestamp,
+		MessageFlags:              update.MessageFlags,
+		ChannelFlags:              update.ChannelFlags,
+		TimeLockDelta:             update.TimeLockDelta,
+		MinHTLC:                   update.HtlcMinimumMsat,
+		MaxHTLC:                   update.HtlcMaximumMsat,
+		FeeBaseMSat:               lnwire.MilliSatoshi(update.BaseFee),
+		FeeProportionalMillionths: lnwire.MilliSatoshi(update.FeeRate),
+		ExtraOpaqueData:           update.ExtraOpaqueData,
+	}
+}
+
+// nodeFromAnn converts a node announcement of a snapshot to its database
+// representation.
+func nodeFromAnn(nodeAnn *lnwire.NodeAnnouncement) *channeldb.LightningNode {
+	return &channeldb.LightningNode{
+		HaveNodeAnnouncement: true,
+		LastUpdate:           time.Unix(int64(nodeAnn.Timestamp), 0),
+		Addresses:            nodeAnn.Addresses,
+		PubKeyBytes:          nodeAnn.NodeID,
+		Alias:                nodeAnn.Alias.String(),
+		AuthSigBytes:         nodeAnn.Signature.ToSignatureBytes(),
+		Features: lnwire.NewFeatureVector(
+			nodeAnn.Features, lnwire.Features,
+		),
+		Color:           nodeAnn.RGBColor,
+		ExtraOpaqueData: nodeAnn.ExtraOpaqueData,
+	}
+}
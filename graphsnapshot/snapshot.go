@@ -0,0 +1,292 @@
+package graphsnapshot
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"time"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// Version denotes the version of a graph snapshot. Based on this version, we
+// know how to encode and decode the snapshot.
+type Version byte
+
+const (
+	// DefaultVersion is the default version of a graph snapshot. The
+	// serialized format for this version is: magic || version || chain
+	// hash || timestamp || numChannels || channels... || numNodes ||
+	// nodes...
+	//
+	// Each channel is serialized as: capacity || channel point ||
+	// announcement || numUpdates || updates..., and each node as its
+	// announcement. All announcements are encoded as a 2 byte length
+	// followed by the wire encoding of the message, including its type.
+	DefaultVersion Version = 0
+)
+
+var (
+	// snapshotMagic is the magic prefix of every serialized graph snapshot,
+	// which allows us to quickly reject files that aren't snapshots.
+	snapshotMagic = [4]byte{'l', 'n', 'g', 's'}
+
+	// ErrInvalidMagic is returned when decoding data that doesn't start
+	// with the magic prefix of graph snapshots.
+	ErrInvalidMagic = errors.New("data is not a graph snapshot")
+)
+
+// Channel is a single public channel of a graph snapshot, along with the
+// latest channel updates of its directions.
+type Channel struct {
+	// Announcement is the signed announcement of the channel, which is
+	// either a *lnwire.ChannelAnnouncement or a
+	// *lnwire.ChannelAnnouncement2.
+	Announcement lnwire.Message
+
+	// Capacity is the capacity of the channel.
+	Capacity btcutil.Amount
+
+	// ChannelPoint is the funding outpoint of the channel. Since neither
+	// the capacity nor the funding outpoint are part of the announcement,
+	// including them allows the channel to be imported without looking up
+	// its funding transaction.
+	ChannelPoint wire.OutPoint
+
+	// Updates are the signed channel updates of the directions of the
+	// channel that have been announced, up to one per direction.
+	Updates []*lnwire.ChannelUpdate
+}
+
+// ShortChannelID returns the short channel ID of the channel.
+func (c *Channel) ShortChannelID() (lnwire.ShortChannelID, error) {
+	switch ann := c.Announcement.(type) {
+	case *lnwire.ChannelAnnouncement:
+		return ann.ShortChannelID, nil
+
+	case *lnwire.ChannelAnnouncement2:
+		return ann.ShortChannelID, nil
+
+	default:
+		return lnwire.ShortChannelID{}, fmt.Errorf("unknown channel "+
+			"announcement type %T", c.Announcement)
+	}
+}
+
+// Snapshot is a compact snapshot of the public channel graph. As it's made up
+// of the signed announcements of the channels and nodes, the authenticity of
+// every element can be verified when importing it.
+type Snapshot struct {
+	// Version is the version that should be observed when encoding the
+	// snapshot.
+	Version Version
+
+	// ChainHash is the genesis hash of the chain the channels of the
+	// snapshot belong to.
+	ChainHash chainhash.Hash
+
+	// Timestamp is the time the snapshot was taken at.
+	Timestamp time.Time
+
+	// Channels is the set of public channels of the graph.
+	Channels []Channel
+
+	// Nodes is the set of signed node announcements of the graph.
+	Nodes []*lnwire.NodeAnnouncement
+}
+
+// Encode serializes the snapshot into the passed io.Writer.
+func (s *Snapshot) Encode(w io.Writer) error {
+	// The only version that we know how to encode atm is version 0.
+	// Attempts to encode any other version will result in an error.
+	if s.Version != DefaultVersion {
+		return fmt.Errorf("unable to encode unknown snapshot version "+
+			"%v", s.Version)
+	}
+
+	if uint64(len(s.Channels)) > math.MaxUint32 ||
+		uint64(len(s.Nodes)) > math.MaxUint32 {
+
+		return fmt.Errorf("snapshot too large")
+	}
+
+	var b bytes.Buffer
+	err := lnwire.WriteElements(
+		&b, snapshotMagic[:], byte(s.Version), s.ChainHash[:],
+		uint64(s.Timestamp.Unix()), uint32(len(s.Channels)),
+	)
+	if err != nil {
+		return err
+	}
+
+	for _, channel := range s.Channels {
+		if len(channel.Updates) > 2 {
+			return fmt.Errorf("channel has %v updates",
+				len(channel.Updates))
+		}
+
+		err := lnwire.WriteElements(
+			&b, channel.Capacity, channel.ChannelPoint,
+		)
+		if err != nil {
+			return err
+		}
+
+		if err := writeMessage(&b, channel.Announcement); err != nil {
+			return err
+		}
+
+		numUpdates := uint8(len(channel.Updates))
+		if err := lnwire.WriteElements(&b, numUpdates); err != nil {
+			return err
+		}
+		for _, update := range channel.Updates {
+			if err := writeMessage(&b, update); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := lnwire.WriteElements(&b, uint32(len(s.Nodes))); err != nil {
+		return err
+	}
+	for _, node := range s.Nodes {
+		if err := writeMessage(&b, node); err != nil {
+			return err
+		}
+	}
+
+	_, err = w.Write(b.Bytes())
+	return err
+}
+
+// Decode deserializes a snapshot from the passed io.Reader.
+func (s *Snapshot) Decode(r io.Reader) error {
+	var (
+		magic     [4]byte
+		version   byte
+		timestamp uint64
+	)
+	err := lnwire.ReadElements(r, magic[:], &version, s.ChainHash[:])
+	if err != nil {
+		return err
+	}
+
+	if magic != snapshotMagic {
+		return ErrInvalidMagic
+	}
+
+	s.Version = Version(version)
+	if s.Version != DefaultVersion {
+		return fmt.Errorf("unable to decode unknown snapshot version "+
+			"%v", s.Version)
+	}
+
+	var numChannels uint32
+	if err := lnwire.ReadElements(r, &timestamp, &numChannels); err != nil {
+		return err
+	}
+	s.Timestamp = time.Unix(int64(timestamp), 0)
+
+	// We don't preallocate the channels, as the number of channels isn't
+	// authenticated and could be arbitrarily large.
+	s.Channels = nil
+	for i := uint32(0); i < numChannels; i++ {
+		var channel Channel
+		err := lnwire.ReadElements(
+			r, &channel.Capacity, &channel.ChannelPoint,
+		)
+		if err != nil {
+			return err
+		}
+
+		channel.Announcement, err = readMessage(r)
+		if err != nil {
+			return err
+		}
+
+		switch channel.Announcement.(type) {
+		case *lnwire.ChannelAnnouncement, *lnwire.ChannelAnnouncement2:
+		default:
+			return fmt.Errorf("expected channel announcement, got "+
+				"%v", channel.Announcement.MsgType())
+		}
+
+		var numUpdates uint8
+		if err := lnwire.ReadElements(r, &numUpdates); err != nil {
+			return err
+		}
+		if numUpdates > 2 {
+			return fmt.Errorf("channel has %v updates", numUpdates)
+		}
+
+		for j := uint8(0); j < numUpdates; j++ {
+			msg, err := readMessage(r)
+			if err != nil {
+				return err
+			}
+
+			update, ok := msg.(*lnwire.ChannelUpdate)
+			if !ok {
+				return fmt.Errorf("expected channel update, "+
+					"got %v", msg.MsgType())
+			}
+			channel.Updates = append(channel.Updates, update)
+		}
+
+		s.Channels = append(s.Channels, channel)
+	}
+
+	var numNodes uint32
+	if err := lnwire.ReadElements(r, &numNodes); err != nil {
+		return err
+	}
+
+	s.Nodes = nil
+	for i := uint32(0); i < numNodes; i++ {
+		msg, err := readMessage(r)
+		if err != nil {
+			return err
+		}
+
+		node, ok := msg.(*lnwire.NodeAnnouncement)
+		if !ok {
+			return fmt.Errorf("expected node announcement, got %v",
+				msg.MsgType())
+		}
+		s.Nodes = append(s.Nodes, node)
+	}
+
+	return nil
+}
+
+// writeMessage writes the wire encoding of the message, prefixed by its
+// length.
+func writeMessage(w *bytes.Buffer, msg lnwire.Message) error {
+	var b bytes.Buffer
+	if _, err := lnwire.WriteMessage(&b, msg, 0); err != nil {
+		return err
+	}
+
+	return lnwire.WriteElements(w, uint16(b.Len()), b.Bytes())
+}
+
+// readMessage reads a message written by writeMessage.
+func readMessage(r io.Reader) (lnwire.Message, error) {
+	var msgLen uint16
+	if err := lnwire.ReadElements(r, &msgLen); err != nil {
+		return nil, err
+	}
+
+	msgBytes := make([]byte, msgLen)
+	if _, err := io.ReadFull(r, msgBytes); err != nil {
+		return nil, err
+	}
+
+	return lnwire.ReadMessage(bytes.NewReader(msgBytes), 0)
+}
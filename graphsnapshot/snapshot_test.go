@@ -0,0 +1,245 @@
+package graphsnapshot
+
+import (
+	"bytes"
+	"image/color"
+	"net"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/batch"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/stretchr/testify/require"
+)
+
+var testChainHash = *chaincfg.MainNetParams.GenesisHash
+
+// mockGraphSink is a GraphSink that records the elements of a snapshot that
+// are imported.
+type mockGraphSink struct {
+	edges    []*channeldb.ChannelEdgeInfo
+	policies []*channeldb.ChannelEdgePolicy
+	nodes    []*channeldb.LightningNode
+}
+
+func (m *mockGraphSink) AddTrustedEdge(edge *channeldb.ChannelEdgeInfo) error {
+	m.edges = append(m.edges, edge)
+	return nil
+}
+
+func (m *mockGraphSink) UpdateEdge(policy *channeldb.ChannelEdgePolicy,
+	_ ...batch.SchedulerOption) error {
+
+	m.policies = append(m.policies, policy)
+	return nil
+}
+
+func (m *mockGraphSink) AddNode(node *channeldb.LightningNode,
+	_ ...batch.SchedulerOption) error {
+
+	m.nodes = append(m.nodes, node)
+	return nil
+}
+
+// sign signs the double sha256 of the data with the given key.
+func sign(t *testing.T, privKey *btcec.PrivateKey, data []byte) lnwire.Sig {
+	t.Helper()
+
+	sig, err := lnwire.NewSigFromSignature(
+		ecdsa.Sign(privKey, chainhash.DoubleHashB(data)),
+	)
+	require.NoError(t, err)
+
+	return sig
+}
+
+// newTestSnapshot creates a snapshot of a single signed channel between two
+// nodes, along with the updates of both directions and the announcements of
+// both nodes.
+func newTestSnapshot(t *testing.T) *Snapshot {
+	t.Helper()
+
+	var privKeys []*btcec.PrivateKey
+	for i := 0; i < 4; i++ {
+		privKey, err := btcec.NewPrivateKey()
+		require.NoError(t, err)
+
+		privKeys = append(privKeys, privKey)
+	}
+
+	var nodeIDs, bitcoinKeys [2][33]byte
+	for i := 0; i < 2; i++ {
+		copy(nodeIDs[i][:], privKeys[i].PubKey().SerializeCompressed())
+		copy(
+			bitcoinKeys[i][:],
+			privKeys[i+2].PubKey().SerializeCompressed(),
+		)
+	}
+
+	scid := lnwire.NewShortChanIDFromInt(1000 << 40)
+	chanAnn := &lnwire.ChannelAnnouncement{
+		Features:        lnwire.NewRawFeatureVector(),
+		ChainHash:       testChainHash,
+		ShortChannelID:  scid,
+		NodeID1:         nodeIDs[0],
+		NodeID2:         nodeIDs[1],
+		BitcoinKey1:     bitcoinKeys[0],
+		BitcoinKey2:     bitcoinKeys[1],
+		ExtraOpaqueData: make([]byte, 0),
+	}
+	data, err := chanAnn.DataToSign()
+	require.NoError(t, err)
+
+	chanAnn.NodeSig1 = sign(t, privKeys[0], data)
+	chanAnn.NodeSig2 = sign(t, privKeys[1], data)
+	chanAnn.BitcoinSig1 = sign(t, privKeys[2], data)
+	chanAnn.BitcoinSig2 = sign(t, privKeys[3], data)
+
+	channel := Channel{
+		Announcement: chanAnn,
+		Capacity:     100000,
+		ChannelPoint: wire.OutPoint{Index: 1},
+	}
+
+	var nodes []*lnwire.NodeAnnouncement
+	for i := 0; i < 2; i++ {
+		update := &lnwire.ChannelUpdate{
+			ChainHash:       testChainHash,
+			ShortChannelID:  scid,
+			Timestamp:       1000,
+			MessageFlags:    lnwire.ChanUpdateRequiredMaxHtlc,
+			ChannelFlags:    lnwire.ChanUpdateChanFlags(i),
+			TimeLockDelta:   40,
+			HtlcMinimumMsat: 1000,
+			HtlcMaximumMsat: 10000000,
+			BaseFee:         1000,
+			FeeRate:         uint32(i),
+			ExtraOpaqueData: make([]byte, 0),
+		}
+		data, err := update.DataToSign()
+		require.NoError(t, err)
+
+		update.Signature = sign(t, privKeys[i], data)
+		channel.Updates = append(channel.Updates, update)
+
+		node := &lnwire.NodeAnnouncement{
+			Features:  lnwire.NewRawFeatureVector(),
+			Timestamp: 1000,
+			NodeID:    nodeIDs[i],
+			RGBColor:  color.RGBA{R: uint8(i)},
+			Addresses: []net.Addr{&net.TCPAddr{
+				IP:   net.IP{10, 0, 0, byte(i)},
+				Port: 9735,
+			}},
+			ExtraOpaqueData: make([]byte, 0),
+		}
+		data, err = node.DataToSign()
+		require.NoError(t, err)
+
+		node.Signature = sign(t, privKeys[i], data)
+		nodes = append(nodes, node)
+	}
+
+	return &Snapshot{
+		Version:   DefaultVersion,
+		ChainHash: testChainHash,
+		Channels:  []Channel{channel},
+		Nodes:     nodes,
+	}
+}
+
+// TestSnapshotEncoding asserts that a snapshot survives a round trip through
+// its binary encoding, and that its signatures are verified.
+func TestSnapshotEncoding(t *testing.T) {
+	t.Parallel()
+
+	snapshot := newTestSnapshot(t)
+	require.NoError(t, Verify(snapshot, testChainHash))
+
+	var b bytes.Buffer
+	require.NoError(t, snapshot.Encode(&b))
+
+	var decoded Snapshot
+	require.NoError(t, decoded.Decode(bytes.NewReader(b.Bytes())))
+	require.Equal(t, snapshot.Channels, decoded.Channels)
+	require.Equal(t, snapshot.Nodes, decoded.Nodes)
+	require.NoError(t, Verify(&decoded, testChainHash))
+
+	// A snapshot of another chain is rejected.
+	require.Error(t, Verify(&decoded, chainhash.Hash{1}))
+
+	// Any change to a signed field invalidates the snapshot.
+	decoded.Channels[0].Updates[1].FeeRate++
+	require.Error(t, Verify(&decoded, testChainHash))
+
+	// Data that isn't a snapshot or a snapshot of an unknown version can't
+	// be decoded.
+	encoded := b.Bytes()
+	encoded[0] ^= 1
+	err := decoded.Decode(bytes.NewReader(encoded))
+	require.ErrorIs(t, err, ErrInvalidMagic)
+
+	encoded[0] ^= 1
+	encoded[4] = 1
+	require.Error(t, decoded.Decode(bytes.NewReader(encoded)))
+}
+
+// TestExportImport asserts that a snapshot exported from a channel graph
+// holds the original signed announcements, and that all of them are imported
+// into another graph.
+func TestExportImport(t *testing.T) {
+	t.Parallel()
+
+	snapshot := newTestSnapshot(t)
+
+	db, err := channeldb.MakeTestDB(t)
+	require.NoError(t, err)
+	graph := db.ChannelGraph()
+
+	// Populate the graph with the contents of the snapshot, along with a
+	// private channel that must not be exported.
+	edge, err := edgeInfoFromChannel(&snapshot.Channels[0])
+	require.NoError(t, err)
+	require.NoError(t, graph.AddChannelEdge(edge))
+
+	privateEdge := *edge
+	privateEdge.ChannelID++
+	privateEdge.AuthProof = nil
+	require.NoError(t, graph.AddChannelEdge(&privateEdge))
+
+	for _, update := range snapshot.Channels[0].Updates {
+		err := graph.UpdateEdgePolicy(edgePolicyFromUpdate(update))
+		require.NoError(t, err)
+	}
+	for _, node := range snapshot.Nodes {
+		require.NoError(t, graph.AddLightningNode(nodeFromAnn(node)))
+	}
+
+	exported, err := Export(graph, testChainHash)
+	require.NoError(t, err)
+	require.Len(t, exported.Channels, 1)
+	require.Len(t, exported.Nodes, 2)
+	require.Equal(t, snapshot.Channels, exported.Channels)
+	require.NoError(t, Verify(exported, testChainHash))
+
+	sink := &mockGraphSink{}
+	summary, err := Import(sink, exported)
+	require.NoError(t, err)
+	require.Equal(t, &ImportSummary{
+		ChannelsImported: 1,
+		UpdatesImported:  2,
+		NodesImported:    2,
+	}, summary)
+
+	require.Len(t, sink.edges, 1)
+	require.Equal(t, edge.ChannelPoint, sink.edges[0].ChannelPoint)
+	require.Equal(t, edge.Capacity, sink.edges[0].Capacity)
+	require.Equal(t, edge.AuthProof, sink.edges[0].AuthProof)
+	require.Len(t, sink.policies, 2)
+	require.Len(t, sink.nodes, 2)
+}
@@ -0,0 +1,455 @@
+package htlcswitch
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// CommitUpgrade describes the changes to the commitment of a channel that we
+// propose to the remote party. Only the parameters that are set are changed.
+type CommitUpgrade struct {
+	// ChanType is the new type of the channel. Only the bits that
+	// determine the commitment format may differ from the current type.
+	ChanType *channeldb.ChannelType
+
+	// LocalDustLimit is our new dust limit, below which outputs aren't
+	// materialized on our commitment.
+	LocalDustLimit *btcutil.Amount
+
+	// RemoteCsvDelay is the new relative time lock the remote party must
+	// use for its own outputs on its commitment.
+	RemoteCsvDelay *uint16
+
+	// RemoteMaxAcceptedHtlcs is the new maximum number of HTLCs the remote
+	// party may offer to us at any time.
+	RemoteMaxAcceptedHtlcs *uint16
+}
+
+// commitUpgradeReq contains a commitment upgrade along with an error channel
+// that will be used by the channelLink to report whether the upgrade has been
+// locked in.
+type commitUpgradeReq struct {
+	upgrade *CommitUpgrade
+	err     chan error
+}
+
+// pendingCommitUpgrade tracks a commitment upgrade that has been proposed by
+// either party, but isn't locked in yet.
+type pendingCommitUpgrade struct {
+	// params are the commitment parameters the channel is upgraded to.
+	params *channeldb.CommitParams
+
+	// initiator is true if we proposed the upgrade.
+	initiator bool
+
+	// applied is true once the upgrade has been applied to the channel
+	// state, after which all new commitments use the new parameters.
+	applied bool
+
+	// err is notified once the upgrade is locked in or was rejected. It's
+	// only set if we proposed the upgrade.
+	err chan error
+}
+
+// newDynPropose creates the message that proposes to upgrade the commitment of
+// the channel from the current to the passed parameters. Only the parameters
+// that differ are included, unless full is set. In that case all parameters
+// that can be upgraded are included, which allows the remote party to
+// determine the parameters of an upgrade it may have missed.
+func newDynPropose(chanID lnwire.ChannelID, current,
+	params *channeldb.CommitParams, full bool) *lnwire.DynPropose {
+
+	msg := &lnwire.DynPropose{
+		ChanID: chanID,
+	}
+
+	if full || params.ChanType != current.ChanType {
+		chanType := lnwallet.CommitFormatFeatures(params.ChanType)
+		msg.ChannelType = &chanType
+	}
+
+	local, remote := params.LocalChanCfg, params.RemoteChanCfg
+	if full || local.DustLimit != current.LocalChanCfg.DustLimit {
+		msg.DustLimit = &local.DustLimit
+	}
+	if full || remote.CsvDelay != current.RemoteChanCfg.CsvDelay {
+		msg.CsvDelay = &remote.CsvDelay
+	}
+	if full || remote.MaxAcceptedHtlcs !=
+		current.RemoteChanCfg.MaxAcceptedHtlcs {
+
+		msg.MaxAcceptedHTLCs = &remote.MaxAcceptedHtlcs
+	}
+
+	return msg
+}
+
+// upgradedParams returns the commitment parameters that result from applying
+// the passed upgrade to the current parameters.
+func upgradedParams(current channeldb.CommitParams,
+	upgrade *CommitUpgrade) *channeldb.CommitParams {
+
+	params := current
+	if upgrade.ChanType != nil {
+		params.ChanType = *upgrade.ChanType
+	}
+	if upgrade.LocalDustLimit != nil {
+		params.LocalChanCfg.DustLimit = *upgrade.LocalDustLimit
+	}
+	if upgrade.RemoteCsvDelay != nil {
+		params.RemoteChanCfg.CsvDelay = *upgrade.RemoteCsvDelay
+	}
+	if upgrade.RemoteMaxAcceptedHtlcs != nil {
+		params.RemoteChanCfg.MaxAcceptedHtlcs =
+			*upgrade.RemoteMaxAcceptedHtlcs
+	}
+
+	return &params
+}
+
+// proposedParams returns the commitment parameters that result from applying
+// the upgrade proposed by the remote party to the current parameters. The
+// fields of the proposal are relative to the remote party, so its dust limit
+// is the dust limit of the remote commitment.
+func proposedParams(current channeldb.CommitParams,
+	msg *lnwire.DynPropose) (*channeldb.CommitParams, error) {
+
+	params := current
+	if msg.ChannelType != nil {
+		chanType, err := lnwallet.UpgradedChannelType(
+			current.ChanType, *msg.ChannelType,
+		)
+		if err != nil {
+			return nil, err
+		}
+		params.ChanType = chanType
+	}
+	if msg.DustLimit != nil {
+		params.RemoteChanCfg.DustLimit = *msg.DustLimit
+	}
+	if msg.CsvDelay != nil {
+		params.LocalChanCfg.CsvDelay = *msg.CsvDelay
+	}
+	if msg.MaxAcceptedHTLCs != nil {
+		params.LocalChanCfg.MaxAcceptedHtlcs = *msg.MaxAcceptedHTLCs
+	}
+
+	return &params, nil
+}
+
+// handleCommitUpgradeRequest proposes the requested commitment upgrade to the
+// remote party. The channel must be quiescent with us as the initiator.
+func (l *channelLink) handleCommitUpgradeRequest(req *commitUpgradeReq) {
+	if !l.quiescer.isQuiescent() || !l.quiescer.isInitiator() {
+		req.err <- ErrNotQuiescenceInitiator
+		return
+	}
+	if l.pendingUpgrade != nil {
+		req.err <- lnwallet.ErrCommitUpgradePending
+		return
+	}
+
+	// If we can't propose the upgrade, the protocol for which we made the
+	// channel quiescent is complete.
+	failRequest := func(err error) {
+		if resumeErr := l.quiescer.resume(); resumeErr != nil {
+			l.log.Errorf("unable to resume quiescence: %v",
+				resumeErr)
+		}
+		req.err <- err
+	}
+
+	current := l.channel.State().CommitParams()
+	params := upgradedParams(current, req.upgrade)
+
+	// We validate the upgrade ourselves first, so that we don't propose
+	// an upgrade we wouldn't be able to apply once accepted.
+	err := l.channel.ValidateCommitUpgrade(params, l.cfg.MaxLocalCSVDelay)
+	if err != nil {
+		failRequest(err)
+		return
+	}
+
+	msg := newDynPropose(l.ChanID(), &current, params, false)
+	if err := l.cfg.Peer.SendMessage(false, msg); err != nil {
+		failRequest(err)
+		return
+	}
+
+	l.log.Infof("proposed commitment upgrade to type %v", params.ChanType)
+
+	l.pendingUpgrade = &pendingCommitUpgrade{
+		params:    params,
+		initiator: true,
+		err:       req.err,
+	}
+}
+
+// handleDynPropose processes a commitment upgrade proposed by the remote
+// party. We reply with dyn_ack if the upgrade is acceptable, and with
+// dyn_reject otherwise.
+func (l *channelLink) handleDynPropose(msg *lnwire.DynPropose,
+	resync bool) {
+
+	if l.cfg.DisallowDynamicCommits {
+		l.stfuFailf("dynamic commitments not negotiated")
+		return
+	}
+
+	current := l.channel.State().CommitParams()
+	params, err := proposedParams(current, msg)
+
+	switch {
+	// After a reconnection, the remote party resends the proposal of an
+	// upgrade it already applied, as we may have missed it. If we applied
+	// it as well, there's nothing left to do.
+	case resync && err == nil && reflect.DeepEqual(params, &current):
+		l.sendDynAck()
+		return
+
+	// Otherwise, we apply the upgrade right away, as the remote party
+	// will continue with the commitment dance that locks it in.
+	case resync:
+		if err == nil {
+			err = l.channel.UpgradeCommitment(
+				params, l.cfg.MaxLocalCSVDelay,
+			)
+		}
+		if err != nil {
+			l.fail(
+				LinkFailureError{code: ErrInvalidUpdate},
+				"unable to apply commitment upgrade of "+
+					"remote party: %v", err,
+			)
+			return
+		}
+		l.sendDynAck()
+		return
+
+	case !l.quiescer.isQuiescent() || l.quiescer.isInitiator():
+		l.stfuFailf("received dyn_propose while not quiescent")
+		return
+
+	case l.pendingUpgrade != nil:
+		l.stfuFailf("received dyn_propose while upgrade pending")
+		return
+	}
+
+	if err == nil {
+		err = l.channel.ValidateCommitUpgrade(
+			params, l.cfg.MaxLocalCSVDelay,
+		)
+	}
+	if err != nil {
+		l.log.Infof("rejecting commitment upgrade: %v", err)
+
+		rejectErr := l.cfg.Peer.SendMessage(false, &lnwire.DynReject{
+			ChanID: l.ChanID(),
+			Reason: lnwire.WarningData(err.Error()),
+		})
+		if rejectErr != nil {
+			l.fail(LinkFailureError{code: ErrInternalError},
+				"unable to send dyn_reject: %v", rejectErr)
+			return
+		}
+
+		// The protocol for which the channel was made quiescent is
+		// complete.
+		if err := l.quiescer.resume(); err != nil {
+			l.log.Errorf("unable to resume quiescence: %v", err)
+		}
+
+		return
+	}
+
+	l.log.Infof("accepted commitment upgrade to type %v", params.ChanType)
+
+	// The upgrade is applied once the remote party sends its signature
+	// for our first commitment using the new parameters.
+	l.pendingUpgrade = &pendingCommitUpgrade{
+		params: params,
+	}
+	l.sendDynAck()
+}
+
+// sendDynAck accepts the commitment upgrade proposed by the remote party.
+func (l *channelLink) sendDynAck() {
+	err := l.cfg.Peer.SendMessage(false, &lnwire.DynAck{
+		ChanID: l.ChanID(),
+	})
+	if err != nil {
+		l.fail(LinkFailureError{code: ErrInternalError},
+			"unable to send dyn_ack: %v", err)
+	}
+}
+
+// handleDynAck processes the acceptance of our commitment upgrade proposal.
+// We apply the upgrade and sign a new commitment using the new parameters.
+func (l *channelLink) handleDynAck() {
+	upgrade := l.pendingUpgrade
+
+	switch {
+	// If we resent the proposal of an upgrade we already applied after a
+	// reconnection, there's nothing left to do.
+	case upgrade == nil && l.upgradeResent:
+		l.upgradeResent = false
+		return
+
+	case upgrade == nil || !upgrade.initiator || upgrade.applied:
+		l.stfuFailf("received unexpected dyn_ack")
+		return
+	}
+
+	err := l.channel.UpgradeCommitment(
+		upgrade.params, l.cfg.MaxLocalCSVDelay,
+	)
+	if err != nil {
+		l.pendingUpgrade = nil
+		upgrade.err <- err
+
+		l.fail(LinkFailureError{code: ErrInternalError},
+			"unable to apply commitment upgrade: %v", err)
+		return
+	}
+	upgrade.applied = true
+
+	l.updateCommitTxOrFail()
+}
+
+// handleDynReject processes the rejection of our commitment upgrade proposal,
+// which completes the protocol for which the channel was made quiescent.
+func (l *channelLink) handleDynReject(msg *lnwire.DynReject) {
+	upgrade := l.pendingUpgrade
+	if upgrade == nil || !upgrade.initiator || upgrade.applied {
+		l.stfuFailf("received unexpected dyn_reject")
+		return
+	}
+
+	l.pendingUpgrade = nil
+	upgrade.err <- fmt.Errorf("%w: %v", ErrCommitUpgradeRejected,
+		string(msg.Reason))
+
+	if err := l.quiescer.resume(); err != nil {
+		l.log.Errorf("unable to resume quiescence: %v", err)
+	}
+}
+
+// applyAcceptedUpgrade applies the commitment upgrade we accepted from the
+// remote party, once it signed our first commitment using the new parameters.
+func (l *channelLink) applyAcceptedUpgrade() bool {
+	upgrade := l.pendingUpgrade
+	if upgrade == nil || upgrade.initiator || upgrade.applied {
+		return true
+	}
+
+	err := l.channel.UpgradeCommitment(
+		upgrade.params, l.cfg.MaxLocalCSVDelay,
+	)
+	if err != nil {
+		l.fail(LinkFailureError{code: ErrInternalError},
+			"unable to apply commitment upgrade: %v", err)
+		return false
+	}
+	upgrade.applied = true
+
+	return true
+}
+
+// checkCommitUpgrade completes a pending commitment upgrade once both parties
+// revoked their commitments that use the prior parameters, after which
+// quiescence is resumed.
+func (l *channelLink) checkCommitUpgrade() {
+	upgrade := l.pendingUpgrade
+	if upgrade == nil || !upgrade.applied ||
+		l.channel.CommitUpgradePending() {
+
+		return
+	}
+
+	l.log.Infof("commitment upgrade to type %v locked in",
+		upgrade.params.ChanType)
+
+	l.pendingUpgrade = nil
+	if upgrade.err != nil {
+		upgrade.err <- nil
+	}
+
+	// The channel type of the static channel backup may have changed, so
+	// other sub-systems are notified of the upgrade.
+	l.cfg.NotifyUpgradedChannel(*l.ChannelPoint())
+
+	if err := l.quiescer.resume(); err != nil {
+		l.log.Errorf("unable to resume quiescence: %v", err)
+	}
+}
+
+// resendCommitUpgrade resends the proposal of a commitment upgrade that isn't
+// locked in yet after a reconnection, as the remote party may not have
+// received it before the connection was lost.
+func (l *channelLink) resendCommitUpgrade() error {
+	if !l.channel.CommitUpgradePending() {
+		return nil
+	}
+
+	state := l.channel.State()
+	params := state.CommitParams()
+	msg := newDynPropose(l.ChanID(), &params, &params, true)
+
+	l.log.Infof("resending commitment upgrade to type %v",
+		params.ChanType)
+
+	if err := l.cfg.Peer.SendMessage(false, msg); err != nil {
+		return err
+	}
+	l.upgradeResent = true
+
+	return nil
+}
+
+// UpgradeCommitment upgrades the commitment of the channel using the dynamic
+// commitments protocol. The channel is made quiescent with us as the
+// initiator, after which the upgrade is proposed to the remote party. If
+// accepted, both parties sign new commitments using the new parameters. The
+// call blocks until the upgrade is locked in or rejected, after which
+// quiescence is resumed.
+//
+// NOTE: Part of the ChannelUpdateHandler interface.
+func (l *channelLink) UpgradeCommitment(upgrade *CommitUpgrade) error {
+	if l.cfg.DisallowDynamicCommits {
+		return ErrDynamicCommitmentsNotSupported
+	}
+
+	initiator, err := l.Quiesce()
+	if err != nil {
+		return err
+	}
+
+	// If the remote party requested quiescence at the same time, it leads
+	// the protocol for which the channel is quiescent and will resume it.
+	if !initiator {
+		return ErrNotQuiescenceInitiator
+	}
+
+	errChan := make(chan error, 1)
+
+	select {
+	case l.commitUpgradeRequests <- &commitUpgradeReq{
+		upgrade: upgrade,
+		err:     errChan,
+	}:
+	case <-l.quit:
+		return ErrLinkShuttingDown
+	}
+
+	select {
+	case err := <-errChan:
+		return err
+	case <-l.quit:
+		return ErrLinkShuttingDown
+	}
+}
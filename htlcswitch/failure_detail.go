@@ -54,6 +54,10 @@ const (
 	// OutgoingFailureForwardsDisabled is returned when the switch is
 	// configured to disallow forwards.
 	OutgoingFailureForwardsDisabled
+
+	// OutgoingFailureRateLimited is returned when a forward exceeds the
+	// rate limit of its incoming peer or outgoing channel.
+	OutgoingFailureRateLimited
 )
 
 // FailureString returns the string representation of a failure detail.
@@ -91,6 +95,9 @@ func (fd OutgoingFailure) FailureString() string {
 	case OutgoingFailureForwardsDisabled:
 		return "node configured to disallow forwards"
 
+	case OutgoingFailureRateLimited:
+		return "htlc forwarding rate limit exceeded"
+
 	default:
 		return "unknown failure detail"
 	}
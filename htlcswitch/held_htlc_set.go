@@ -63,6 +63,16 @@ func (h *heldHtlcSet) pop(key models.CircuitKey) (InterceptedForward, error) {
 	return intercepted, nil
 }
 
+// get returns the specified forward without removing it from the set.
+func (h *heldHtlcSet) get(key models.CircuitKey) (InterceptedForward, error) {
+	intercepted, ok := h.set[key]
+	if !ok {
+		return nil, fmt.Errorf("fwd %v not found", key)
+	}
+
+	return intercepted, nil
+}
+
 // exists tests whether the specified forward is part of the set.
 func (h *heldHtlcSet) exists(key models.CircuitKey) bool {
 	_, ok := h.set[key]
@@ -11,6 +11,7 @@ import (
 	"github.com/lightningnetwork/lnd/htlcswitch/hop"
 	"github.com/lightningnetwork/lnd/lntypes"
 	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/record"
 	"github.com/lightningnetwork/lnd/subscribe"
 )
 
@@ -146,6 +147,14 @@ type HtlcInfo struct {
 
 	// OutgoingAmt is the amount of the htlc on our outgoing channel.
 	OutgoingAmt lnwire.MilliSatoshi
+
+	// IncomingWireCustomRecords are the custom records that were attached
+	// to the update_add_htlc message on our incoming channel.
+	IncomingWireCustomRecords record.CustomSet
+
+	// OutgoingWireCustomRecords are the custom records that were attached
+	// to the update_add_htlc message on our outgoing channel.
+	OutgoingWireCustomRecords record.CustomSet
 }
 
 // String returns a string representation of a htlc.
@@ -447,12 +456,31 @@ func newHtlcKey(pkt *htlcPacket) HtlcKey {
 
 // newHtlcInfo returns HtlcInfo for the packet provided.
 func newHtlcInfo(pkt *htlcPacket) HtlcInfo {
-	return HtlcInfo{
-		IncomingTimeLock: pkt.incomingTimeout,
-		OutgoingTimeLock: pkt.outgoingTimeout,
-		IncomingAmt:      pkt.incomingAmount,
-		OutgoingAmt:      pkt.amount,
+	info := HtlcInfo{
+		IncomingTimeLock:          pkt.incomingTimeout,
+		OutgoingTimeLock:          pkt.outgoingTimeout,
+		IncomingAmt:               pkt.incomingAmount,
+		OutgoingAmt:               pkt.amount,
+		IncomingWireCustomRecords: pkt.inWireCustomRecords,
 	}
+
+	if htlc, ok := pkt.htlc.(*lnwire.UpdateAddHTLC); ok {
+		info.OutgoingWireCustomRecords = outWireCustomRecords(htlc)
+	}
+
+	return info
+}
+
+// outWireCustomRecords returns the custom records that are attached to an
+// outgoing htlc. The records are produced locally, so a parse failure is only
+// logged.
+func outWireCustomRecords(htlc *lnwire.UpdateAddHTLC) record.CustomSet {
+	records, err := record.ParseWireCustomRecords(htlc.ExtraData)
+	if err != nil {
+		log.Errorf("Unable to parse outgoing custom records: %v", err)
+	}
+
+	return records
 }
 
 // getEventType returns the htlc type based on the fields set in the htlc
@@ -4,6 +4,7 @@ import (
 	"crypto/sha256"
 	"fmt"
 	"sync"
+	"sync/atomic"
 
 	"github.com/go-errors/errors"
 	"github.com/lightningnetwork/lnd/chainntnfs"
@@ -11,6 +12,7 @@ import (
 	"github.com/lightningnetwork/lnd/htlcswitch/hop"
 	"github.com/lightningnetwork/lnd/lntypes"
 	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/record"
 )
 
 var (
@@ -22,6 +24,10 @@ var (
 	// code is attempted.
 	ErrUnsupportedFailureCode = errors.New("unsupported failure code")
 
+	// ErrMissingModifications is returned when an intercepted packet is
+	// resumed with modifications, but none are specified.
+	ErrMissingModifications = errors.New("missing forward modifications")
+
 	errBlockStreamStopped = errors.New("block epoch stream stopped")
 )
 
@@ -30,8 +36,12 @@ var (
 // intercepts forward requests. A reference to the Switch is held in order
 // to communicate back the interception result where the options are:
 // Resume - forwards the original request to the switch as is.
+// ResumeModified - forwards an altered request to the switch.
 // Settle - routes UpdateFulfillHTLC to the originating link.
 // Fail - routes UpdateFailHTLC to the originating link.
+// Multiple interceptors can be registered. They are consulted in priority
+// order and a resumed packet is offered to the next interested interceptor
+// before it is handed to the switch.
 type InterceptableSwitch struct {
 	// htlcSwitch is the underline switch
 	htlcSwitch *Switch
@@ -48,18 +58,28 @@ type InterceptableSwitch struct {
 
 	// interceptorRegistration is a channel that we use to synchronize
 	// client connect and disconnect.
-	interceptorRegistration chan ForwardInterceptor
+	interceptorRegistration chan *interceptorUpdate
 
 	// requireInterceptor indicates whether processing should block if no
 	// interceptor is connected.
 	requireInterceptor bool
 
-	// interceptor is the handler for intercepted packets.
-	interceptor ForwardInterceptor
+	// interceptors is the priority ordered chain of handlers for
+	// intercepted packets.
+	interceptors interceptorRegistry
+
+	// lastInterceptorID is the id that was assigned to the most recently
+	// registered interceptor. It must be accessed atomically.
+	lastInterceptorID uint64
 
 	// heldHtlcSet keeps track of outstanding intercepted forwards.
 	heldHtlcSet *heldHtlcSet
 
+	// holders tracks the interceptor that a held forward was last offered
+	// to. Forwards that are held while no interceptor is connected don't
+	// have an entry.
+	holders map[models.CircuitKey]interceptorRank
+
 	// cltvRejectDelta defines the number of blocks before the expiry of the
 	// htlc where we no longer intercept it and instead cancel it back.
 	cltvRejectDelta uint32
@@ -87,6 +107,18 @@ type InterceptableSwitch struct {
 	quit chan struct{}
 }
 
+// interceptorUpdate registers or unregisters an interceptor.
+type interceptorUpdate struct {
+	// id identifies the interceptor.
+	id InterceptorID
+
+	// registration describes the interceptor to register. If nil, the
+	// interceptor is unregistered.
+	registration *InterceptorRegistration
+
+	errChan chan error
+}
+
 type interceptedPackets struct {
 	packets  []*htlcPacket
 	linkQuit chan struct{}
@@ -105,16 +137,46 @@ const (
 
 	// FwdActionFail fails the intercepted packet back to the sender.
 	FwdActionFail
+
+	// FwdActionResumeModified forwards the intercepted packet to the
+	// switch after applying the specified modifications.
+	FwdActionResumeModified
 )
 
+// FwdModifications describes how an intercepted packet is altered before it
+// is forwarded. Unset fields leave the packet unchanged.
+type FwdModifications struct {
+	// OutgoingAmount overrides the amount that is forwarded to the next
+	// hop. It must not exceed the incoming amount.
+	OutgoingAmount *lnwire.MilliSatoshi
+
+	// OutgoingChanID overrides the requested outgoing channel. Because
+	// the onion is constructed for the original next hop, the channel
+	// usually needs to be with the same peer.
+	OutgoingChanID *lnwire.ShortChannelID
+
+	// OutgoingWireCustomRecords replaces the custom records that are
+	// attached to the outgoing update_add_htlc message. A non-nil empty
+	// set removes all records.
+	OutgoingWireCustomRecords record.CustomSet
+}
+
 // FwdResolution defines the action to be taken on an intercepted packet.
 type FwdResolution struct {
 	// Key is the incoming circuit key of the htlc.
 	Key models.CircuitKey
 
+	// Interceptor is the interceptor that resolves the htlc. It must be
+	// the interceptor that the htlc was last offered to.
+	Interceptor InterceptorID
+
 	// Action is the action to take on the intercepted htlc.
 	Action FwdAction
 
+	// Modifications are the changes to apply to the htlc if Action is
+	// FwdActionResumeModified.
+	Modifications *FwdModifications
+
 	// Preimage is the preimage that is to be used for settling if Action is
 	// FwdActionSettle.
 	Preimage lntypes.Preimage
@@ -129,8 +191,8 @@ type FwdResolution struct {
 }
 
 type fwdResolution struct {
-	resolution *FwdResolution
-	errChan    chan error
+	resolutions []*FwdResolution
+	errChan     chan error
 }
 
 // InterceptableSwitchConfig contains the configuration of InterceptableSwitch.
@@ -156,7 +218,8 @@ type InterceptableSwitchConfig struct {
 	CltvInterceptDelta uint32
 
 	// RequireInterceptor indicates whether processing should block if no
-	// interceptor is connected.
+	// interceptor is connected. If interceptors are connected, but none of
+	// them is interested in a packet, the packet is processed normally.
 	RequireInterceptor bool
 }
 
@@ -174,8 +237,9 @@ func NewInterceptableSwitch(cfg *InterceptableSwitchConfig) (
 		htlcSwitch:              cfg.Switch,
 		intercepted:             make(chan *interceptedPackets),
 		onchainIntercepted:      make(chan InterceptedForward),
-		interceptorRegistration: make(chan ForwardInterceptor),
+		interceptorRegistration: make(chan *interceptorUpdate),
 		heldHtlcSet:             newHeldHtlcSet(),
+		holders:                 make(map[models.CircuitKey]interceptorRank),
 		resolutionChan:          make(chan *fwdResolution),
 		requireInterceptor:      cfg.RequireInterceptor,
 		cltvRejectDelta:         cfg.CltvRejectDelta,
@@ -187,16 +251,77 @@ func NewInterceptableSwitch(cfg *InterceptableSwitchConfig) (
 }
 
 // SetInterceptor sets the ForwardInterceptor to be used. A nil argument
-// unregisters the current interceptor.
+// unregisters the current interceptor. The interceptor is registered with the
+// default priority under DefaultInterceptorID and intercepts all packets.
 func (s *InterceptableSwitch) SetInterceptor(
 	interceptor ForwardInterceptor) {
 
+	var registration *InterceptorRegistration
+	if interceptor != nil {
+		registration = &InterceptorRegistration{
+			Interceptor: interceptor,
+		}
+	}
+
+	err := s.updateInterceptors(DefaultInterceptorID, registration)
+	if err != nil {
+		log.Errorf("Cannot set interceptor: %v", err)
+	}
+}
+
+// RegisterInterceptor adds an interceptor to the chain of interceptors. The
+// returned id identifies the interceptor in resolutions and must be used to
+// unregister it.
+func (s *InterceptableSwitch) RegisterInterceptor(
+	registration *InterceptorRegistration) (InterceptorID, error) {
+
+	if registration.Interceptor == nil {
+		return 0, ErrNilInterceptor
+	}
+
+	if err := registration.Filter.Validate(); err != nil {
+		return 0, err
+	}
+
+	id := InterceptorID(atomic.AddUint64(&s.lastInterceptorID, 1))
+	if err := s.updateInterceptors(id, registration); err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
+
+// UnregisterInterceptor removes the interceptor with the given id from the
+// chain. Packets held by it are offered to the next interested interceptor.
+func (s *InterceptableSwitch) UnregisterInterceptor(id InterceptorID) error {
+	return s.updateInterceptors(id, nil)
+}
+
+// updateInterceptors hands an interceptor (de-)registration to the main loop.
+func (s *InterceptableSwitch) updateInterceptors(id InterceptorID,
+	registration *InterceptorRegistration) error {
+
+	update := &interceptorUpdate{
+		id:           id,
+		registration: registration,
+		errChan:      make(chan error, 1),
+	}
+
 	// Synchronize setting the handler with the main loop to prevent race
 	// conditions.
 	select {
-	case s.interceptorRegistration <- interceptor:
+	case s.interceptorRegistration <- update:
 
 	case <-s.quit:
+		return errors.New("switch shutting down")
+	}
+
+	select {
+	case err := <-update.errChan:
+		return err
+
+	case <-s.quit:
+		return errors.New("switch shutting down")
 	}
 }
 
@@ -249,8 +374,8 @@ func (s *InterceptableSwitch) run() error {
 	for {
 		select {
 		// An interceptor registration or de-registration came in.
-		case interceptor := <-s.interceptorRegistration:
-			s.setInterceptor(interceptor)
+		case update := <-s.interceptorRegistration:
+			s.updateInterceptor(update)
 
 		case packets := <-s.intercepted:
 			var notIntercepted []*htlcPacket
@@ -288,7 +413,7 @@ func (s *InterceptableSwitch) run() error {
 			}
 
 		case res := <-s.resolutionChan:
-			res.errChan <- s.resolve(res.resolution)
+			res.errChan <- s.resolveBatch(res.resolutions)
 
 		case currentBlock, ok := <-s.blockEpochStream.Epochs:
 			if !ok {
@@ -311,6 +436,8 @@ func (s *InterceptableSwitch) failExpiredHtlcs() {
 	s.heldHtlcSet.popAutoFails(
 		uint32(s.currentHeight),
 		func(fwd InterceptedForward) {
+			delete(s.holders, fwd.Packet().IncomingCircuit)
+
 			err := fwd.FailWithCode(
 				lnwire.CodeTemporaryChannelFailure,
 			)
@@ -321,8 +448,15 @@ func (s *InterceptableSwitch) failExpiredHtlcs() {
 	)
 }
 
-func (s *InterceptableSwitch) sendForward(fwd InterceptedForward) {
-	err := s.interceptor(fwd.Packet())
+// sendForward offers the forward to the given interceptor and records it as
+// the holder of the forward.
+func (s *InterceptableSwitch) sendForward(reg *registeredInterceptor,
+	fwd InterceptedForward) {
+
+	packet := fwd.Packet()
+	s.holders[packet.IncomingCircuit] = reg.rank
+
+	err := reg.interceptor(packet)
 	if err != nil {
 		// Only log the error. If we couldn't send the packet, we assume
 		// that the interceptor will reconnect so that we can retry.
@@ -330,53 +464,174 @@ func (s *InterceptableSwitch) sendForward(fwd InterceptedForward) {
 	}
 }
 
-func (s *InterceptableSwitch) setInterceptor(interceptor ForwardInterceptor) {
-	s.interceptor = interceptor
+// release removes the forward from the set of held forwards.
+func (s *InterceptableSwitch) release(key models.CircuitKey) {
+	if _, err := s.heldHtlcSet.pop(key); err != nil {
+		log.Errorf("Cannot release held forward: %v", err)
+	}
 
-	// Replay all currently held htlcs. When an interceptor is not required,
-	// there may be none because they've been cleared after the previous
-	// disconnect.
-	if interceptor != nil {
-		log.Debugf("Interceptor connected")
+	delete(s.holders, key)
+}
 
-		s.heldHtlcSet.forEach(s.sendForward)
+// updateInterceptor registers or unregisters an interceptor. The caller is
+// notified before held htlcs are handed over, so that an interceptor that is
+// invoked synchronously doesn't block its own registration.
+func (s *InterceptableSwitch) updateInterceptor(update *interceptorUpdate) {
+	if update.registration == nil {
+		if !s.interceptors.remove(update.id) {
+			// Clearing the default interceptor is always allowed,
+			// even if it was never set.
+			if update.id == DefaultInterceptorID {
+				update.errChan <- nil
+			} else {
+				update.errChan <- ErrInterceptorNotFound
+			}
+
+			return
+		}
+
+		update.errChan <- nil
+		s.releaseHeld(update.id)
 
 		return
 	}
 
+	reg := &registeredInterceptor{
+		rank: interceptorRank{
+			priority: update.registration.Priority,
+			id:       update.id,
+		},
+		interceptor: update.registration.Interceptor,
+		filter:      update.registration.Filter,
+	}
+	s.interceptors.add(reg)
+
+	update.errChan <- nil
+
+	log.Debugf("Interceptor %v connected: priority=%v", update.id,
+		update.registration.Priority)
+
+	// Replay the held htlcs that this interceptor is interested in and
+	// that aren't held by another connected interceptor. When an
+	// interceptor is not required, there may be none because they've been
+	// cleared after the previous disconnect. Htlcs that already passed
+	// this interceptor's position in the chain are skipped.
+	s.heldHtlcSet.forEach(func(fwd InterceptedForward) {
+		packet := fwd.Packet()
+
+		rank, ok := s.holders[packet.IncomingCircuit]
+		if ok {
+			holder := s.interceptors.get(rank.id)
+			if holder != nil && holder != reg {
+				return
+			}
+
+			if reg.rank.before(rank) {
+				return
+			}
+		}
+
+		if !reg.filter.Matches(packet) {
+			return
+		}
+
+		s.sendForward(reg, fwd)
+	})
+}
+
+// releaseHeld hands the forwards held by a disconnected interceptor to the
+// next interested interceptor in the chain. Forwards for which there is none
+// are retained if an interceptor is required and resumed otherwise.
+func (s *InterceptableSwitch) releaseHeld(id InterceptorID) {
+	var orphans []InterceptedForward
+	s.heldHtlcSet.forEach(func(fwd InterceptedForward) {
+		packet := fwd.Packet()
+
+		rank, ok := s.holders[packet.IncomingCircuit]
+		if !ok || rank.id != id {
+			return
+		}
+
+		next := s.interceptors.next(packet, &rank, true)
+		if next != nil {
+			s.sendForward(next, fwd)
+
+			return
+		}
+
+		orphans = append(orphans, fwd)
+	})
+
 	// The interceptor disconnects. If an interceptor is required, keep the
 	// held htlcs.
 	if s.requireInterceptor {
-		log.Infof("Interceptor disconnected, retaining held packets")
+		log.Infof("Interceptor %v disconnected, retaining %v held "+
+			"packets", id, len(orphans))
 
 		return
 	}
 
 	// Interceptor is not required. Release held forwards.
-	log.Infof("Interceptor disconnected, resolving held packets")
+	log.Infof("Interceptor %v disconnected, resolving %v held packets",
+		id, len(orphans))
+
+	for _, fwd := range orphans {
+		s.release(fwd.Packet().IncomingCircuit)
 
-	s.heldHtlcSet.popAll(func(fwd InterceptedForward) {
 		err := fwd.Resume()
 		if err != nil {
 			log.Errorf("Failed to resume hold forward %v", err)
 		}
-	})
+	}
+}
+
+// resolveBatch applies a batch of resolutions. All resolutions are attempted,
+// the first error encountered is returned.
+func (s *InterceptableSwitch) resolveBatch(resolutions []*FwdResolution) error {
+	var firstErr error
+	for _, res := range resolutions {
+		err := s.resolve(res)
+		if err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("cannot resolve %v: %w", res.Key,
+				err)
+		}
+	}
+
+	return firstErr
 }
 
 func (s *InterceptableSwitch) resolve(res *FwdResolution) error {
-	intercepted, err := s.heldHtlcSet.pop(res.Key)
+	// Only the interceptor that currently holds the forward may resolve
+	// it.
+	rank, ok := s.holders[res.Key]
+	if !ok || rank.id != res.Interceptor {
+		return ErrFwdNotExists
+	}
+
+	intercepted, err := s.heldHtlcSet.get(res.Key)
 	if err != nil {
 		return err
 	}
 
 	switch res.Action {
 	case FwdActionResume:
-		return intercepted.Resume()
+		return s.resume(intercepted, rank, nil)
+
+	case FwdActionResumeModified:
+		if res.Modifications == nil {
+			return ErrMissingModifications
+		}
+
+		return s.resume(intercepted, rank, res.Modifications)
 
 	case FwdActionSettle:
+		s.release(res.Key)
+
 		return intercepted.Settle(res.Preimage)
 
 	case FwdActionFail:
+		s.release(res.Key)
+
 		if len(res.FailureMessage) > 0 {
 			return intercepted.Fail(res.FailureMessage)
 		}
@@ -388,11 +643,53 @@ func (s *InterceptableSwitch) resolve(res *FwdResolution) error {
 	}
 }
 
+// resume offers a forward that was resumed by its holder to the next
+// interested interceptor in the chain. If there is none, the forward is handed
+// to the switch. Modifications are applied first, so that later interceptors
+// see the altered packet.
+func (s *InterceptableSwitch) resume(fwd InterceptedForward,
+	rank interceptorRank, mods *FwdModifications) error {
+
+	key := fwd.Packet().IncomingCircuit
+
+	if mods != nil {
+		modifiable, ok := fwd.(*interceptedForward)
+		if !ok {
+			s.release(key)
+
+			return fwd.ResumeModified(mods)
+		}
+
+		// A forward with invalid modifications remains held, so that
+		// the interceptor can try again.
+		if err := modifiable.modify(mods); err != nil {
+			return err
+		}
+	}
+
+	next := s.interceptors.next(fwd.Packet(), &rank, false)
+	if next != nil {
+		s.sendForward(next, fwd)
+
+		return nil
+	}
+
+	s.release(key)
+
+	return fwd.Resume()
+}
+
 // Resolve resolves an intercepted packet.
 func (s *InterceptableSwitch) Resolve(res *FwdResolution) error {
+	return s.ResolveBatch([]*FwdResolution{res})
+}
+
+// ResolveBatch resolves a batch of intercepted packets in one pass. All
+// resolutions are attempted, the first error encountered is returned.
+func (s *InterceptableSwitch) ResolveBatch(resolutions []*FwdResolution) error {
 	internalRes := &fwdResolution{
-		resolution: res,
-		errChan:    make(chan error, 1),
+		resolutions: resolutions,
+		errChan:     make(chan error, 1),
 	}
 
 	select {
@@ -512,7 +809,7 @@ func (s *InterceptableSwitch) forward(
 
 	// If there is no interceptor currently registered, configuration and packet
 	// replay status determine how the packet is handled.
-	if s.interceptor == nil {
+	if s.interceptors.empty() {
 		// Process normally if an interceptor is not required.
 		if !s.requireInterceptor {
 			return false, nil
@@ -542,13 +839,21 @@ func (s *InterceptableSwitch) forward(
 		return true, nil
 	}
 
-	// There is an interceptor registered. We can forward the packet right now.
-	// Hold it in the queue too to track what is outstanding.
+	// There are interceptors registered. Offer the packet to the first one
+	// in the chain that is interested in it. If there is none, process it
+	// normally.
+	reg := s.interceptors.next(fwd.Packet(), nil, true)
+	if reg == nil {
+		return false, nil
+	}
+
+	// We can forward the packet right now. Hold it in the queue too to
+	// track what is outstanding.
 	if err := s.heldHtlcSet.push(inKey, fwd); err != nil {
 		return false, err
 	}
 
-	s.sendForward(fwd)
+	s.sendForward(reg, fwd)
 
 	return true, nil
 }
@@ -605,6 +910,8 @@ func (f *interceptedForward) Packet() InterceptedPacket {
 		CustomRecords:  f.packet.customRecords,
 		OnionBlob:      f.htlc.OnionBlob,
 		AutoFailHeight: f.autoFailHeight,
+
+		InWireCustomRecords: f.packet.inWireCustomRecords,
 	}
 }
 
@@ -615,6 +922,59 @@ func (f *interceptedForward) Resume() error {
 	return f.htlcSwitch.ForwardPackets(nil, f.packet)
 }
 
+// ResumeModified resumes the default behavior after applying the given
+// modifications to the outgoing htlc.
+func (f *interceptedForward) ResumeModified(mods *FwdModifications) error {
+	if err := f.modify(mods); err != nil {
+		return err
+	}
+
+	return f.Resume()
+}
+
+// modify applies the given modifications to the outgoing htlc. The packet is
+// left untouched if the modifications are invalid.
+func (f *interceptedForward) modify(mods *FwdModifications) error {
+	// Work on a copy of the outgoing htlc so that a failed validation
+	// doesn't leave a partially modified packet behind.
+	htlc := *f.htlc
+
+	if mods.OutgoingAmount != nil {
+		amt := *mods.OutgoingAmount
+		if amt == 0 {
+			return errors.New("outgoing amount must be positive")
+		}
+
+		if amt > f.packet.incomingAmount {
+			return fmt.Errorf("outgoing amount %v exceeds incoming "+
+				"amount %v", amt, f.packet.incomingAmount)
+		}
+
+		htlc.Amount = amt
+	}
+
+	if mods.OutgoingWireCustomRecords != nil {
+		extraData, err := record.EncodeWireCustomRecords(
+			mods.OutgoingWireCustomRecords,
+		)
+		if err != nil {
+			return err
+		}
+
+		htlc.ExtraData = extraData
+	}
+
+	f.htlc = &htlc
+	f.packet.htlc = &htlc
+	f.packet.amount = htlc.Amount
+
+	if mods.OutgoingChanID != nil {
+		f.packet.outgoingChanID = *mods.OutgoingChanID
+	}
+
+	return nil
+}
+
 // Fail notifies the intention to Fail an existing hold forward with an
 // encrypted failure reason.
 func (f *interceptedForward) Fail(reason []byte) error {
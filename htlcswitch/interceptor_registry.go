@@ -0,0 +1,224 @@
+package htlcswitch
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+var (
+	// ErrNilInterceptor is returned when an interceptor registration
+	// doesn't contain an interceptor callback.
+	ErrNilInterceptor = errors.New("interceptor callback missing")
+
+	// ErrInterceptorNotFound is returned when the caller tries to
+	// unregister an interceptor that isn't registered.
+	ErrInterceptorNotFound = errors.New("interceptor not found")
+)
+
+// InterceptorID uniquely identifies a registered interceptor.
+type InterceptorID uint64
+
+// DefaultInterceptorID is the id of the interceptor that is registered
+// through SetInterceptor. Resolutions that don't specify an interceptor are
+// attributed to it.
+const DefaultInterceptorID InterceptorID = 0
+
+// InterceptorFilter restricts the set of htlcs that are offered to an
+// interceptor. The zero value matches all htlcs.
+type InterceptorFilter struct {
+	// IncomingChanIDs, if non-empty, limits interception to htlcs that
+	// arrive on one of the listed channels.
+	IncomingChanIDs []lnwire.ShortChannelID
+
+	// MinIncomingAmount, if non-zero, is the minimum incoming amount of
+	// an htlc to be intercepted.
+	MinIncomingAmount lnwire.MilliSatoshi
+
+	// MaxIncomingAmount, if non-zero, is the maximum incoming amount of
+	// an htlc to be intercepted.
+	MaxIncomingAmount lnwire.MilliSatoshi
+
+	// CustomRecords, if non-empty, limits interception to htlcs whose
+	// payload contains at least one of the listed custom record types.
+	CustomRecords []uint64
+}
+
+// Validate checks that the filter is internally consistent.
+func (f *InterceptorFilter) Validate() error {
+	if f.MaxIncomingAmount != 0 &&
+		f.MinIncomingAmount > f.MaxIncomingAmount {
+
+		return fmt.Errorf("min incoming amount %v exceeds max "+
+			"incoming amount %v", f.MinIncomingAmount,
+			f.MaxIncomingAmount)
+	}
+
+	return nil
+}
+
+// Matches returns true if the given packet passes the filter.
+func (f *InterceptorFilter) Matches(packet InterceptedPacket) bool {
+	if len(f.IncomingChanIDs) > 0 {
+		var found bool
+		for _, chanID := range f.IncomingChanIDs {
+			if chanID == packet.IncomingCircuit.ChanID {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return false
+		}
+	}
+
+	if packet.IncomingAmount < f.MinIncomingAmount {
+		return false
+	}
+
+	if f.MaxIncomingAmount != 0 &&
+		packet.IncomingAmount > f.MaxIncomingAmount {
+
+		return false
+	}
+
+	if len(f.CustomRecords) > 0 {
+		var found bool
+		for _, recordType := range f.CustomRecords {
+			if _, ok := packet.CustomRecords[recordType]; ok {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+// InterceptorRegistration describes an interceptor that is to be registered
+// with the InterceptableSwitch.
+type InterceptorRegistration struct {
+	// Interceptor is called for every htlc that is offered to this
+	// interceptor.
+	Interceptor ForwardInterceptor
+
+	// Priority determines the order in which interceptors are consulted.
+	// Interceptors with a higher priority are offered an htlc first. If
+	// an interceptor resumes the htlc, it is offered to the next matching
+	// interceptor. Interceptors with equal priority are consulted in
+	// registration order.
+	Priority uint32
+
+	// Filter restricts the htlcs that are offered to this interceptor.
+	Filter InterceptorFilter
+}
+
+// interceptorRank determines the position of an interceptor in the chain of
+// registered interceptors.
+type interceptorRank struct {
+	priority uint32
+	id       InterceptorID
+}
+
+// before returns true if an interceptor with rank r is consulted before an
+// interceptor with rank o.
+func (r interceptorRank) before(o interceptorRank) bool {
+	if r.priority != o.priority {
+		return r.priority > o.priority
+	}
+
+	return r.id < o.id
+}
+
+// registeredInterceptor is an interceptor that is part of the chain.
+type registeredInterceptor struct {
+	rank        interceptorRank
+	interceptor ForwardInterceptor
+	filter      InterceptorFilter
+}
+
+// interceptorRegistry keeps the registered interceptors ordered by rank. It
+// isn't safe for concurrent use and is only accessed from the main loop of
+// the InterceptableSwitch.
+type interceptorRegistry struct {
+	interceptors []*registeredInterceptor
+}
+
+// add inserts the interceptor into the chain, replacing a previously
+// registered interceptor with the same id.
+func (r *interceptorRegistry) add(reg *registeredInterceptor) {
+	r.remove(reg.rank.id)
+
+	r.interceptors = append(r.interceptors, reg)
+	sort.Slice(r.interceptors, func(i, j int) bool {
+		return r.interceptors[i].rank.before(r.interceptors[j].rank)
+	})
+}
+
+// remove deletes the interceptor with the given id from the chain. It returns
+// false if there was no such interceptor.
+func (r *interceptorRegistry) remove(id InterceptorID) bool {
+	for i, reg := range r.interceptors {
+		if reg.rank.id != id {
+			continue
+		}
+
+		r.interceptors = append(
+			r.interceptors[:i], r.interceptors[i+1:]...,
+		)
+
+		return true
+	}
+
+	return false
+}
+
+// get returns the interceptor with the given id or nil if it isn't
+// registered.
+func (r *interceptorRegistry) get(id InterceptorID) *registeredInterceptor {
+	for _, reg := range r.interceptors {
+		if reg.rank.id == id {
+			return reg
+		}
+	}
+
+	return nil
+}
+
+// empty returns true if no interceptors are registered.
+func (r *interceptorRegistry) empty() bool {
+	return len(r.interceptors) == 0
+}
+
+// next returns the first interceptor in the chain that matches the packet and
+// that isn't consulted before the given rank. If after is nil, the chain is
+// searched from the start. If inclusive is false, an interceptor with exactly
+// the given rank is skipped as well.
+func (r *interceptorRegistry) next(packet InterceptedPacket,
+	after *interceptorRank, inclusive bool) *registeredInterceptor {
+
+	for _, reg := range r.interceptors {
+		if after != nil {
+			if reg.rank.before(*after) {
+				continue
+			}
+
+			if !inclusive && reg.rank == *after {
+				continue
+			}
+		}
+
+		if reg.filter.Matches(packet) {
+			return reg
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,159 @@
+package htlcswitch
+
+import (
+	"testing"
+
+	"github.com/lightningnetwork/lnd/channeldb/models"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/record"
+	"github.com/stretchr/testify/require"
+)
+
+// TestInterceptorFilter tests matching packets against interceptor filters.
+func TestInterceptorFilter(t *testing.T) {
+	t.Parallel()
+
+	chanID := lnwire.NewShortChanIDFromInt(1)
+	packet := InterceptedPacket{
+		IncomingCircuit: models.CircuitKey{ChanID: chanID},
+		IncomingAmount:  1000,
+		CustomRecords: record.CustomSet{
+			record.CustomTypeStart: {1},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		filter  InterceptorFilter
+		matches bool
+	}{
+		{
+			name:    "empty filter",
+			matches: true,
+		},
+		{
+			name: "matching channel",
+			filter: InterceptorFilter{
+				IncomingChanIDs: []lnwire.ShortChannelID{
+					lnwire.NewShortChanIDFromInt(2), chanID,
+				},
+			},
+			matches: true,
+		},
+		{
+			name: "other channel",
+			filter: InterceptorFilter{
+				IncomingChanIDs: []lnwire.ShortChannelID{
+					lnwire.NewShortChanIDFromInt(2),
+				},
+			},
+		},
+		{
+			name: "amount in range",
+			filter: InterceptorFilter{
+				MinIncomingAmount: 1000,
+				MaxIncomingAmount: 1000,
+			},
+			matches: true,
+		},
+		{
+			name: "amount too low",
+			filter: InterceptorFilter{
+				MinIncomingAmount: 1001,
+			},
+		},
+		{
+			name: "amount too high",
+			filter: InterceptorFilter{
+				MaxIncomingAmount: 999,
+			},
+		},
+		{
+			name: "matching custom record",
+			filter: InterceptorFilter{
+				CustomRecords: []uint64{
+					record.CustomTypeStart + 1,
+					record.CustomTypeStart,
+				},
+			},
+			matches: true,
+		},
+		{
+			name: "missing custom record",
+			filter: InterceptorFilter{
+				CustomRecords: []uint64{
+					record.CustomTypeStart + 1,
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			require.NoError(t, test.filter.Validate())
+			require.Equal(t, test.matches, test.filter.Matches(packet))
+		})
+	}
+
+	invalid := InterceptorFilter{
+		MinIncomingAmount: 2,
+		MaxIncomingAmount: 1,
+	}
+	require.Error(t, invalid.Validate())
+}
+
+// TestInterceptorRegistryOrder tests that interceptors are consulted by
+// priority and registration order.
+func TestInterceptorRegistryOrder(t *testing.T) {
+	t.Parallel()
+
+	newReg := func(priority uint32, id InterceptorID,
+		filter InterceptorFilter) *registeredInterceptor {
+
+		return &registeredInterceptor{
+			rank: interceptorRank{
+				priority: priority,
+				id:       id,
+			},
+			filter: filter,
+		}
+	}
+
+	var registry interceptorRegistry
+	require.True(t, registry.empty())
+
+	lowFilter := InterceptorFilter{MaxIncomingAmount: 100}
+	low := newReg(0, 1, lowFilter)
+	first := newReg(5, 2, InterceptorFilter{})
+	second := newReg(5, 3, InterceptorFilter{})
+
+	registry.add(low)
+	registry.add(second)
+	registry.add(first)
+	require.Equal(t, []*registeredInterceptor{first, second, low},
+		registry.interceptors)
+
+	packet := InterceptedPacket{IncomingAmount: 50}
+	require.Equal(t, first, registry.next(packet, nil, true))
+	require.Equal(t, first, registry.next(packet, &first.rank, true))
+	require.Equal(t, second, registry.next(packet, &first.rank, false))
+	require.Equal(t, low, registry.next(packet, &second.rank, false))
+	require.Nil(t, registry.next(packet, &low.rank, false))
+
+	// The low priority interceptor isn't interested in large htlcs.
+	packet.IncomingAmount = 500
+	require.Nil(t, registry.next(packet, &second.rank, false))
+
+	// Re-registering an interceptor replaces it.
+	replacement := newReg(10, 3, InterceptorFilter{})
+	registry.add(replacement)
+	require.Equal(t, []*registeredInterceptor{replacement, first, low},
+		registry.interceptors)
+	require.Equal(t, replacement, registry.get(3))
+
+	require.True(t, registry.remove(3))
+	require.False(t, registry.remove(3))
+	require.Nil(t, registry.get(3))
+}
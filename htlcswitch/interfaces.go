@@ -67,6 +67,39 @@ type dustHandler interface {
 	// getDustClosure returns a closure that can evaluate whether a passed
 	// HTLC is dust.
 	getDustClosure() dustClosure
+
+	// exceedsFeeExposure returns whether adding an HTLC of the given
+	// amount, along with the passed dust still held by the mailbox, would
+	// push the fee exposure of either commitment over the link's limit.
+	exceedsFeeExposure(amt lnwire.MilliSatoshi, incoming bool,
+		localMailDust, remoteMailDust lnwire.MilliSatoshi) bool
+
+	// recordFeeExposureRejection records that an HTLC add was rejected as
+	// it'd push the fee exposure of the link over its limit.
+	recordFeeExposureRejection()
+}
+
+// FeeExposureStats describes the fee exposure of a channel, which is the value
+// that goes to miners if one of its commitments confirms.
+type FeeExposureStats struct {
+	// LocalExposure is the current fee exposure of the local commitment.
+	LocalExposure lnwire.MilliSatoshi
+
+	// RemoteExposure is the current fee exposure of the remote
+	// commitment.
+	RemoteExposure lnwire.MilliSatoshi
+
+	// MaxFeeExposure is the limit of the fee exposure of either
+	// commitment. A zero value means there is no limit.
+	MaxFeeExposure lnwire.MilliSatoshi
+
+	// RejectedAdds is the number of HTLC adds that were rejected as
+	// they'd exceed the limit.
+	RejectedAdds uint64
+
+	// RejectedFeeUpdates is the number of fee updates that were rejected
+	// as they'd exceed the limit.
+	RejectedFeeUpdates uint64
 }
 
 // scidAliasHandler is an interface that the ChannelLink implements so it can
@@ -136,6 +169,36 @@ type ChannelUpdateHandler interface {
 	// clean. This can be used with dynamic commitment negotiation or coop
 	// close negotiation which require a clean channel state.
 	ShutdownIfChannelClean() error
+
+	// DisableAdds stops the link from accepting new incoming and outgoing
+	// HTLC adds, so that the channel can drain its in-flight HTLCs. Any
+	// other updates are still processed.
+	DisableAdds()
+
+	// EnableAdds allows the link to accept new HTLC adds again after they
+	// were disabled with DisableAdds.
+	EnableAdds()
+
+	// FeeExposureStats returns the current fee exposure of the channel
+	// along with the number of updates that were rejected for exceeding
+	// its limit.
+	FeeExposureStats() FeeExposureStats
+
+	// Quiesce makes the channel quiescent using the stfu handshake, and
+	// blocks until it is. While quiescent, neither side sends any
+	// updates, which allows protocols that need a stable channel state
+	// to take place. It returns true if we're the initiator of
+	// quiescence, which is the party that leads such a protocol.
+	Quiesce() (bool, error)
+
+	// Resume ends quiescence of the channel once the protocol for which
+	// it was made quiescent completed.
+	Resume() error
+
+	// UpgradeCommitment upgrades the commitment type and parameters of
+	// the channel without closing it, and blocks until the upgrade is
+	// locked in by both parties or rejected by the remote party.
+	UpgradeCommitment(upgrade *CommitUpgrade) error
 }
 
 // ChannelLink is an interface which represents the subsystem for managing the
@@ -264,8 +327,19 @@ type InterceptableHtlcForwarder interface {
 	// SetInterceptor sets a ForwardInterceptor.
 	SetInterceptor(interceptor ForwardInterceptor)
 
+	// RegisterInterceptor adds an interceptor with a priority and a
+	// filter to the chain of interceptors.
+	RegisterInterceptor(
+		registration *InterceptorRegistration) (InterceptorID, error)
+
+	// UnregisterInterceptor removes a previously registered interceptor.
+	UnregisterInterceptor(id InterceptorID) error
+
 	// Resolve resolves an intercepted packet.
 	Resolve(res *FwdResolution) error
+
+	// ResolveBatch resolves a batch of intercepted packets.
+	ResolveBatch(resolutions []*FwdResolution) error
 }
 
 // ForwardInterceptor is a function that is invoked from the switch for every
@@ -307,6 +381,10 @@ type InterceptedPacket struct {
 	// were included in the payload.
 	CustomRecords record.CustomSet
 
+	// InWireCustomRecords are records in the custom type range that were
+	// attached to the incoming update_add_htlc message.
+	InWireCustomRecords record.CustomSet
+
 	// OnionBlob is the onion packet for the next hop
 	OnionBlob [lnwire.OnionPacketSize]byte
 
@@ -329,6 +407,11 @@ type InterceptedForward interface {
 	// this htlc which usually means forward it.
 	Resume() error
 
+	// ResumeModified notifies the intention to resume an existing hold
+	// forward with a modified outgoing amount, outgoing channel or set of
+	// custom records.
+	ResumeModified(mods *FwdModifications) error
+
 	// Settle notifies the intention to settle an existing hold
 	// forward with a given preimage.
 	Settle(lntypes.Preimage) error
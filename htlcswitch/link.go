@@ -4,6 +4,7 @@ import (
 	"bytes"
 	crand "crypto/rand"
 	"crypto/sha256"
+	goErrors "errors"
 	"fmt"
 	prand "math/rand"
 	"sync"
@@ -21,6 +22,7 @@ import (
 	"github.com/lightningnetwork/lnd/contractcourt"
 	"github.com/lightningnetwork/lnd/htlcswitch/hodl"
 	"github.com/lightningnetwork/lnd/htlcswitch/hop"
+	"github.com/lightningnetwork/lnd/input"
 	"github.com/lightningnetwork/lnd/invoices"
 	"github.com/lightningnetwork/lnd/lnpeer"
 	"github.com/lightningnetwork/lnd/lntypes"
@@ -28,6 +30,7 @@ import (
 	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/queue"
+	"github.com/lightningnetwork/lnd/record"
 	"github.com/lightningnetwork/lnd/ticker"
 )
 
@@ -277,10 +280,36 @@ type ChannelLinkConfig struct {
 	// initiator of the channel.
 	MaxFeeAllocation float64
 
+	// MaxFeeExposure is the highest fee exposure we'll allow on either
+	// commitment of the channel. The fee exposure is the commitment fee
+	// along with the value of the anchors and the trimmed HTLCs, which all
+	// go to miners if the commitment confirms. HTLC adds and fee updates
+	// that would exceed it are rejected. A zero value disables the limit.
+	MaxFeeExposure lnwire.MilliSatoshi
+
 	// MaxAnchorsCommitFeeRate is the max commitment fee rate we'll use as
 	// the initiator for channels of the anchor type.
 	MaxAnchorsCommitFeeRate chainfee.SatPerKWeight
 
+	// DisallowQuiescence is true if the quiescence feature wasn't
+	// negotiated with the peer, in which case the channel can't be made
+	// quiescent and any stfu message of the peer is a protocol violation.
+	DisallowQuiescence bool
+
+	// QuiescenceTimeout is the time the channel may spend quiescing or
+	// quiescent before the link is failed and the connection recycled. A
+	// zero value disables the timeout.
+	QuiescenceTimeout time.Duration
+
+	// DisallowDynamicCommits is true if the dynamic commitments feature
+	// wasn't negotiated with the peer, in which case the commitment of the
+	// channel can't be upgraded.
+	DisallowDynamicCommits bool
+
+	// MaxLocalCSVDelay is the maximum CSV delay we accept for our own
+	// funds when the commitment of the channel is upgraded.
+	MaxLocalCSVDelay uint16
+
 	// NotifyActiveLink allows the link to tell the ChannelNotifier when a
 	// link is first started.
 	NotifyActiveLink func(wire.OutPoint)
@@ -297,6 +326,10 @@ type ChannelLinkConfig struct {
 	// ChannelNotifier when a channel link become inactive.
 	NotifyInactiveLinkEvent func(wire.OutPoint)
 
+	// NotifyUpgradedChannel allows the link to tell the ChannelNotifier
+	// when the commitment of a channel was upgraded.
+	NotifyUpgradedChannel func(wire.OutPoint)
+
 	// HtlcNotifier is an instance of a htlcNotifier which we will pipe htlc
 	// events through.
 	HtlcNotifier htlcNotifier
@@ -311,6 +344,12 @@ type ChannelLinkConfig struct {
 	GetAliases func(base lnwire.ShortChannelID) []lnwire.ShortChannelID
 }
 
+// resumeReq contains an error channel that will be used by the channelLink to
+// report whether quiescence of the channel could be resumed.
+type resumeReq struct {
+	err chan error
+}
+
 // shutdownReq contains an error channel that will be used by the channelLink
 // to send an error if shutdown failed. If shutdown succeeded, the channel will
 // be closed.
@@ -329,6 +368,19 @@ type channelLink struct {
 	reestablished int32
 	shutdown      int32
 
+	// addsDisabled is set to 1 while the link doesn't accept any new
+	// HTLC adds, which is used to drain the channel before it's closed.
+	addsDisabled int32
+
+	// feeExposureRejectedAdds counts the HTLC adds that were rejected as
+	// they'd push the fee exposure of the channel over its limit.
+	feeExposureRejectedAdds atomic.Uint64
+
+	// feeExposureRejectedFeeUpdates counts the fee updates that were
+	// rejected as they'd push the fee exposure of the channel over its
+	// limit.
+	feeExposureRejectedFeeUpdates atomic.Uint64
+
 	// failed should be set to true in case a link error happens, making
 	// sure we don't process any more updates.
 	failed bool
@@ -382,6 +434,37 @@ type channelLink struct {
 	// service shutdown requests from ShutdownIfChannelClean calls.
 	shutdownRequest chan *shutdownReq
 
+	// quiescer tracks the state of the stfu handshake that makes the
+	// channel quiescent.
+	quiescer *quiescer
+
+	// quiesceRequests is a channel that the channelLink will listen on to
+	// service requests from Quiesce calls. The passed channel is sent on
+	// once the channel is quiescent.
+	quiesceRequests chan chan bool
+
+	// resumeRequests is a channel that the channelLink will listen on to
+	// service requests from Resume calls.
+	resumeRequests chan *resumeReq
+
+	// commitUpgradeRequests is a channel that the channelLink will listen
+	// on to service requests from UpgradeCommitment calls.
+	commitUpgradeRequests chan *commitUpgradeReq
+
+	// pendingUpgrade is the commitment upgrade that is currently being
+	// negotiated, if any.
+	pendingUpgrade *pendingCommitUpgrade
+
+	// upgradeResync is true until the first message of the remote party
+	// after the channel was reestablished has been processed. Only that
+	// message may resend a commitment upgrade outside of quiescence.
+	upgradeResync bool
+
+	// upgradeResent is true if we resent the proposal of a commitment
+	// upgrade after the channel was reestablished, and are awaiting its
+	// acknowledgement.
+	upgradeResent bool
+
 	// updateFeeTimer is the timer responsible for updating the link's
 	// commitment fee every time it fires.
 	updateFeeTimer *time.Timer
@@ -422,15 +505,30 @@ func NewChannelLink(cfg ChannelLinkConfig,
 
 	logPrefix := fmt.Sprintf("ChannelLink(%v):", channel.ChannelPoint())
 
+	chanID := lnwire.NewChanIDFromOutPoint(channel.ChannelPoint())
+	quiescer := newQuiescer(quiescerCfg{
+		chanID:                 chanID,
+		channelInitiator:       channel.IsInitiator(),
+		hasPendingLocalUpdates: channel.HasPendingLocalUpdates,
+		sendMsg: func(msg lnwire.Message) error {
+			return cfg.Peer.SendMessage(false, msg)
+		},
+		timeout: cfg.QuiescenceTimeout,
+	})
+
 	return &channelLink{
-		cfg:             cfg,
-		channel:         channel,
-		shortChanID:     channel.ShortChanID(),
-		shutdownRequest: make(chan *shutdownReq),
-		hodlMap:         make(map[models.CircuitKey]hodlHtlc),
-		hodlQueue:       queue.NewConcurrentQueue(10),
-		log:             build.NewPrefixLog(logPrefix, log),
-		quit:            make(chan struct{}),
+		cfg:                   cfg,
+		channel:               channel,
+		shortChanID:           channel.ShortChanID(),
+		shutdownRequest:       make(chan *shutdownReq),
+		quiescer:              quiescer,
+		quiesceRequests:       make(chan chan bool),
+		resumeRequests:        make(chan *resumeReq),
+		commitUpgradeRequests: make(chan *commitUpgradeReq),
+		hodlMap:               make(map[models.CircuitKey]hodlHtlc),
+		hodlQueue:             queue.NewConcurrentQueue(10),
+		log:                   build.NewPrefixLog(logPrefix, log),
+		quit:                  make(chan struct{}),
 	}
 }
 
@@ -798,6 +896,14 @@ func (l *channelLink) syncChanStates() error {
 			return err
 		}
 
+		// A commitment upgrade that isn't locked in yet is resent
+		// first, as the remote party must apply it before processing
+		// any commitment that uses the new parameters.
+		if err := l.resendCommitUpgrade(); err != nil {
+			return err
+		}
+		l.upgradeResync = true
+
 		if len(msgsToReSend) > 0 {
 			l.log.Infof("sending %v updates to synchronize the "+
 				"state", len(msgsToReSend))
@@ -1139,6 +1245,14 @@ func (l *channelLink) htlcManager() {
 		go l.fwdPkgGarbager()
 	}
 
+	// If the connection was lost before we signed the first commitment
+	// using the parameters of a commitment upgrade, we'll do so now.
+	if l.channel.CommitUpgradePending() && l.channel.OweCommitment() {
+		if !l.updateCommitTxOrFail() {
+			return
+		}
+	}
+
 	for {
 		// We must always check if we failed at some point processing
 		// the last update before processing the next.
@@ -1162,6 +1276,24 @@ func (l *channelLink) htlcManager() {
 				"PendingLocalUpdateCount")
 		}
 
+		// If the channel is quiescing, send stfu as soon as none of
+		// our updates are pending anymore.
+		if err := l.quiescer.trySendStfu(); err != nil {
+			l.fail(LinkFailureError{code: ErrInternalError},
+				"unable to send stfu: %v", err)
+			return
+		}
+
+		// While the channel is quiescing or quiescent, we can't send
+		// any updates, so we leave the packets from the switch and the
+		// htlc resolutions queued until quiescence is resumed.
+		downstream := l.downstream
+		hodlQueue := l.hodlQueue.ChanOut()
+		if !l.quiescer.canSendUpdates() {
+			downstream = nil
+			hodlQueue = nil
+		}
+
 		select {
 		// Our update fee timer has fired, so we'll check the network
 		// fee to see if we should adjust our commitment fee.
@@ -1174,6 +1306,12 @@ func (l *channelLink) htlcManager() {
 				continue
 			}
 
+			// We can't update the fee while the channel is
+			// quiescing or quiescent.
+			if !l.quiescer.canSendUpdates() {
+				continue
+			}
+
 			// If we are the initiator, then we'll sample the
 			// current fee rate to get into the chain within 3
 			// blocks.
@@ -1251,7 +1389,7 @@ func (l *channelLink) htlcManager() {
 		// A message from the switch was just received. This indicates
 		// that the link is an intermediate hop in a multi-hop HTLC
 		// circuit.
-		case pkt := <-l.downstream:
+		case pkt := <-downstream:
 			l.handleDownstreamPkt(pkt)
 
 		// A message from the connected peer was just received. This
@@ -1260,9 +1398,15 @@ func (l *channelLink) htlcManager() {
 		case msg := <-l.upstream:
 			l.handleUpstreamMsg(msg)
 
+			// The message may have completed the commitment
+			// dance that locks in a pending commitment upgrade.
+			if !l.failed {
+				l.checkCommitUpgrade()
+			}
+
 		// A htlc resolution is received. This means that we now have a
 		// resolution for a previously accepted htlc.
-		case hodlItem := <-l.hodlQueue.ChanOut():
+		case hodlItem := <-hodlQueue:
 			htlcResolution := hodlItem.(invoices.HtlcResolution)
 			err := l.processHodlQueue(htlcResolution)
 			switch err {
@@ -1302,6 +1446,30 @@ func (l *channelLink) htlcManager() {
 			// an error and continue.
 			req.err <- ErrLinkFailedShutdown
 
+		case resp := <-l.quiesceRequests:
+			if err := l.quiescer.request(resp); err != nil {
+				l.fail(LinkFailureError{code: ErrInternalError},
+					"unable to send stfu: %v", err)
+				return
+			}
+
+		case req := <-l.resumeRequests:
+			req.err <- l.quiescer.resume()
+
+		case req := <-l.commitUpgradeRequests:
+			l.handleCommitUpgradeRequest(req)
+
+		case <-l.quiescer.timeoutChan():
+			l.fail(
+				LinkFailureError{
+					code:          ErrRemoteUnresponsive,
+					FailureAction: LinkFailureDisconnect,
+				},
+				"channel quiescent for longer than %v",
+				l.cfg.QuiescenceTimeout,
+			)
+			return
+
 		case <-l.quit:
 			return
 		}
@@ -1442,6 +1610,19 @@ func (l *channelLink) handleDownstreamUpdateAdd(pkt *htlcPacket) error {
 		return nil
 	}
 
+	// If the channel is being drained, we won't add any new HTLCs, so
+	// we'll cancel the payment right away.
+	if l.isAddsDisabled() {
+		l.log.Debugf("rejecting downstream add, channel is draining")
+
+		l.mailBox.FailAdd(pkt)
+
+		return NewDetailedLinkError(
+			lnwire.NewTemporaryChannelFailure(nil),
+			OutgoingFailureLinkNotEligible,
+		)
+	}
+
 	// A new payment has been initiated via the downstream channel,
 	// so we add the new HTLC to our local log, then update the
 	// commitment chains.
@@ -1492,10 +1673,12 @@ func (l *channelLink) handleDownstreamUpdateAdd(pkt *htlcPacket) error {
 	l.cfg.HtlcNotifier.NotifyForwardingEvent(
 		newHtlcKey(pkt),
 		HtlcInfo{
-			IncomingTimeLock: pkt.incomingTimeout,
-			IncomingAmt:      pkt.incomingAmount,
-			OutgoingTimeLock: htlc.Expiry,
-			OutgoingAmt:      htlc.Amount,
+			IncomingTimeLock:          pkt.incomingTimeout,
+			IncomingAmt:               pkt.incomingAmount,
+			OutgoingTimeLock:          htlc.Expiry,
+			OutgoingAmt:               htlc.Amount,
+			IncomingWireCustomRecords: pkt.inWireCustomRecords,
+			OutgoingWireCustomRecords: outWireCustomRecords(htlc),
 		},
 		getEventType(pkt),
 	)
@@ -1743,9 +1926,38 @@ func (l *channelLink) cleanupSpuriousResponse(pkt *htlcPacket) {
 // updates from the upstream peer. The upstream peer is the peer whom we have a
 // direct channel with, updating our respective commitment chains.
 func (l *channelLink) handleUpstreamMsg(msg lnwire.Message) {
+	// Once the remote party sent stfu, it must not send any more updates
+	// until quiescence is resumed.
+	switch msg.(type) {
+	case *lnwire.UpdateAddHTLC, *lnwire.UpdateFulfillHTLC,
+		*lnwire.UpdateFailMalformedHTLC, *lnwire.UpdateFailHTLC,
+		*lnwire.UpdateFee:
+
+		if !l.quiescer.canRecvUpdates() {
+			l.stfuFailf("received %T after stfu", msg)
+			return
+		}
+	}
+
+	// A commitment upgrade may only be resent outside of quiescence as
+	// the first message after the channel was reestablished.
+	resync := l.upgradeResync
+	l.upgradeResync = false
+
 	switch msg := msg.(type) {
 
 	case *lnwire.UpdateAddHTLC:
+		// Custom wire records are attached as a tlv stream. Make sure
+		// that it is well-formed before we accept the htlc, so that it
+		// can be parsed when the htlc is forwarded.
+		_, err := record.ParseWireCustomRecords(msg.ExtraData)
+		if err != nil {
+			l.fail(LinkFailureError{code: ErrInvalidUpdate},
+				"invalid custom records on upstream add HTLC: "+
+					"%v", err)
+			return
+		}
+
 		// We just received an add request from an upstream peer, so we
 		// add it to our state machine, then add the HTLC to our
 		// "settle" list in the event that we know the preimage.
@@ -1934,6 +2146,12 @@ func (l *channelLink) handleUpstreamMsg(msg lnwire.Message) {
 		// memory back to the runtime.
 		l.uncommittedPreimages = nil
 
+		// If we accepted a commitment upgrade of the remote party, this
+		// is the first commitment using the new parameters.
+		if !l.applyAcceptedUpgrade() {
+			return
+		}
+
 		// We just received a new updates to our local commitment
 		// chain, validate this new commitment, closing the link if
 		// invalid.
@@ -2077,7 +2295,21 @@ func (l *channelLink) handleUpstreamMsg(msg lnwire.Message) {
 		}
 
 		l.processRemoteSettleFails(fwdPkg, settleFails)
-		l.processRemoteAdds(fwdPkg, adds)
+
+		// Processing the adds may result in us failing or settling
+		// them, which we can't do while the channel is quiescing or
+		// quiescent. In that case they're processed once quiescence is
+		// resumed.
+		if l.quiescer.canSendUpdates() {
+			l.processRemoteAdds(fwdPkg, adds)
+		} else {
+			l.quiescer.onResume(func() {
+				l.processRemoteAdds(fwdPkg, adds)
+				if !l.failed && l.channel.OweCommitment() {
+					l.updateCommitTxOrFail()
+				}
+			})
+		}
 
 		// If the link failed during processing the adds, we must
 		// return to ensure we won't attempted to update the state
@@ -2103,6 +2335,18 @@ func (l *channelLink) handleUpstreamMsg(msg lnwire.Message) {
 		// We received fee update from peer. If we are the initiator we
 		// will fail the channel, if not we will apply the update.
 		fee := chainfee.SatPerKWeight(msg.FeePerKw)
+
+		// The HTLCs trimmed by the new feerate would be lost to
+		// miners, so we'll fail the channel if the fee exposure
+		// becomes too high.
+		if l.feeRateExceedsFeeExposure(fee) {
+			l.feeExposureRejectedFeeUpdates.Add(1)
+			l.fail(LinkFailureError{code: ErrInvalidUpdate},
+				"fee update to %v exceeds max fee exposure "+
+					"of %v", fee, l.cfg.MaxFeeExposure)
+			return
+		}
+
 		if err := l.channel.ReceiveUpdateFee(fee); err != nil {
 			l.fail(LinkFailureError{code: ErrInvalidUpdate},
 				"error receiving fee update: %v", err)
@@ -2112,6 +2356,32 @@ func (l *channelLink) handleUpstreamMsg(msg lnwire.Message) {
 		// Update the mailbox's feerate as well.
 		l.mailBox.SetFeeRate(fee)
 
+	case *lnwire.Stfu:
+		if l.cfg.DisallowQuiescence {
+			l.stfuFailf("quiescence not negotiated")
+			return
+		}
+
+		err := l.quiescer.recvStfu(msg)
+		var violation *errStfuViolation
+		if goErrors.As(err, &violation) {
+			l.stfuFailf("%v", violation.reason)
+			return
+		}
+		if err != nil {
+			l.fail(LinkFailureError{code: ErrInternalError},
+				"unable to handle stfu: %v", err)
+		}
+
+	case *lnwire.DynPropose:
+		l.handleDynPropose(msg, resync)
+
+	case *lnwire.DynAck:
+		l.handleDynAck()
+
+	case *lnwire.DynReject:
+		l.handleDynReject(msg)
+
 	// In the case where we receive a warning message from our peer, just
 	// log it and move on. We choose not to disconnect from our peer,
 	// although we "MAY" do so according to the specification.
@@ -2383,9 +2653,37 @@ func (l *channelLink) Bandwidth() lnwire.MilliSatoshi {
 // forwards or other payments may use the available slot, so it should be
 // considered best-effort.
 func (l *channelLink) MayAddOutgoingHtlc(amt lnwire.MilliSatoshi) error {
+	if l.isAddsDisabled() {
+		return ErrLinkAddsDisabled
+	}
+
 	return l.channel.MayAddOutgoingHtlc(amt)
 }
 
+// DisableAdds stops the link from accepting new incoming and outgoing HTLC
+// adds, so that the channel can drain its in-flight HTLCs.
+//
+// NOTE: Part of the ChannelUpdateHandler interface.
+func (l *channelLink) DisableAdds() {
+	if atomic.CompareAndSwapInt32(&l.addsDisabled, 0, 1) {
+		l.log.Info("disabling htlc adds to drain channel")
+	}
+}
+
+// EnableAdds allows the link to accept new HTLC adds again.
+//
+// NOTE: Part of the ChannelUpdateHandler interface.
+func (l *channelLink) EnableAdds() {
+	if atomic.CompareAndSwapInt32(&l.addsDisabled, 1, 0) {
+		l.log.Info("re-enabling htlc adds")
+	}
+}
+
+// isAddsDisabled returns true if the link doesn't accept new HTLC adds.
+func (l *channelLink) isAddsDisabled() bool {
+	return atomic.LoadInt32(&l.addsDisabled) == 1
+}
+
 // getDustSum is a wrapper method that calls the underlying channel's dust sum
 // method.
 //
@@ -2394,6 +2692,104 @@ func (l *channelLink) getDustSum(remote bool) lnwire.MilliSatoshi {
 	return l.channel.GetDustSum(remote)
 }
 
+// exceedsFeeExposure returns whether adding an HTLC of the given amount, along
+// with the passed dust still held by the mailbox, would push the fee exposure
+// of either commitment over the link's limit at the current or next feerate.
+//
+// NOTE: Part of the dustHandler interface.
+func (l *channelLink) exceedsFeeExposure(amt lnwire.MilliSatoshi,
+	incoming bool, localMailDust, remoteMailDust lnwire.MilliSatoshi) bool {
+
+	if l.cfg.MaxFeeExposure == 0 {
+		return false
+	}
+
+	isDust := l.getDustClosure()
+	feeRates := []chainfee.SatPerKWeight{
+		l.channel.CommitFeeRate(), l.channel.NextFeeRate(),
+	}
+	for _, feeRate := range feeRates {
+		for _, remote := range []bool{false, true} {
+			exposure := l.channel.FeeExposure(remote, feeRate)
+
+			if remote {
+				exposure += remoteMailDust
+			} else {
+				exposure += localMailDust
+			}
+
+			// Incoming HTLCs are already part of the commitment,
+			// so only outgoing ones are added. A dust HTLC adds
+			// its full value, while any other HTLC adds the fee
+			// of its output.
+			switch {
+			case incoming:
+
+			case isDust(feeRate, false, !remote, amt.ToSatoshis()):
+				exposure += amt
+
+			default:
+				exposure += lnwire.NewMSatFromSatoshis(
+					feeRate.FeeForWeight(input.HTLCWeight),
+				)
+			}
+
+			if exposure > l.cfg.MaxFeeExposure {
+				l.log.Debugf("htlc of %v exceeds max fee "+
+					"exposure of %v at feerate %v", amt,
+					l.cfg.MaxFeeExposure, feeRate)
+
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// feeRateExceedsFeeExposure returns whether applying the given feerate would
+// push the fee exposure of either commitment over the link's limit.
+func (l *channelLink) feeRateExceedsFeeExposure(
+	feeRate chainfee.SatPerKWeight) bool {
+
+	if l.cfg.MaxFeeExposure == 0 {
+		return false
+	}
+
+	for _, remote := range []bool{false, true} {
+		exposure := l.channel.FeeExposure(remote, feeRate)
+		if exposure > l.cfg.MaxFeeExposure {
+			return true
+		}
+	}
+
+	return false
+}
+
+// recordFeeExposureRejection records that an HTLC add was rejected as it'd
+// push the fee exposure of the link over its limit.
+//
+// NOTE: Part of the dustHandler interface.
+func (l *channelLink) recordFeeExposureRejection() {
+	l.feeExposureRejectedAdds.Add(1)
+}
+
+// FeeExposureStats returns the current fee exposure of the channel along with
+// the number of updates that were rejected for exceeding its limit.
+//
+// NOTE: Part of the ChannelUpdateHandler interface.
+func (l *channelLink) FeeExposureStats() FeeExposureStats {
+	feeRate := l.channel.CommitFeeRate()
+
+	return FeeExposureStats{
+		LocalExposure:      l.channel.FeeExposure(false, feeRate),
+		RemoteExposure:     l.channel.FeeExposure(true, feeRate),
+		MaxFeeExposure:     l.cfg.MaxFeeExposure,
+		RejectedAdds:       l.feeExposureRejectedAdds.Load(),
+		RejectedFeeUpdates: l.feeExposureRejectedFeeUpdates.Load(),
+	}
+}
+
 // getFeeRate is a wrapper method that retrieves the underlying channel's
 // feerate.
 //
@@ -2750,6 +3146,60 @@ func (l *channelLink) HandleChannelUpdate(message lnwire.Message) {
 	l.mailBox.AddMessage(message)
 }
 
+// Quiesce makes the channel quiescent using the stfu handshake, and blocks
+// until it is. We stop sending updates right away, and send stfu as soon as
+// none of our updates are pending anymore. Once the remote party replied with
+// stfu as well, neither side sends any updates until Resume is called. It
+// returns true if we're the initiator of quiescence, which is the party that
+// leads the protocol for which the channel was made quiescent.
+//
+// NOTE: Part of the ChannelUpdateHandler interface.
+func (l *channelLink) Quiesce() (bool, error) {
+	if l.cfg.DisallowQuiescence {
+		return false, ErrQuiescenceNotSupported
+	}
+
+	resp := make(chan bool, 1)
+
+	select {
+	case l.quiesceRequests <- resp:
+	case <-l.quit:
+		return false, ErrLinkShuttingDown
+	}
+
+	select {
+	case initiator := <-resp:
+		return initiator, nil
+	case <-l.quit:
+		return false, ErrLinkShuttingDown
+	}
+}
+
+// Resume ends quiescence of the channel, after which updates are sent again.
+// It must only be called once the protocol for which the channel was made
+// quiescent completed, as that's when the remote party considers quiescence
+// to be over as well. Quiescence also ends when the connection is lost.
+//
+// NOTE: Part of the ChannelUpdateHandler interface.
+func (l *channelLink) Resume() error {
+	errChan := make(chan error, 1)
+
+	select {
+	case l.resumeRequests <- &resumeReq{
+		err: errChan,
+	}:
+	case <-l.quit:
+		return ErrLinkShuttingDown
+	}
+
+	select {
+	case err := <-errChan:
+		return err
+	case <-l.quit:
+		return ErrLinkShuttingDown
+	}
+}
+
 // ShutdownIfChannelClean triggers a link shutdown if the channel is in a clean
 // state and errors if the channel has lingering updates.
 //
@@ -2785,6 +3235,15 @@ func (l *channelLink) updateChannelFee(feePerKw chainfee.SatPerKWeight) error {
 		return nil
 	}
 
+	// We also skip the update if it'd push the fee exposure of the channel
+	// over its limit. We'll try again once the feerate or the HTLCs on the
+	// channel change.
+	if l.feeRateExceedsFeeExposure(feePerKw) {
+		l.log.Warnf("skipping fee update to %v, max fee exposure of "+
+			"%v exceeded", feePerKw, l.cfg.MaxFeeExposure)
+		return nil
+	}
+
 	// First, we'll update the local fee on our commitment.
 	if err := l.channel.UpdateFee(feePerKw); err != nil {
 		return err
@@ -3022,6 +3481,19 @@ func (l *channelLink) processRemoteAdds(fwdPkg *channeldb.FwdPkg,
 			continue
 		}
 
+		// If the channel is being drained, we won't accept any new
+		// HTLCs from the remote party, so we'll fail it back.
+		if l.isAddsDisabled() {
+			failure := NewLinkError(
+				lnwire.NewTemporaryChannelFailure(nil),
+			)
+			l.sendHTLCError(pd, failure, obfuscator, false)
+
+			l.log.Debugf("rejecting incoming add, channel is " +
+				"draining")
+			continue
+		}
+
 		heightNow := l.cfg.BestHeight()
 
 		pld, err := chanIterator.HopPayload()
@@ -3077,6 +3549,18 @@ func (l *channelLink) processRemoteAdds(fwdPkg *channeldb.FwdPkg,
 				continue
 			}
 
+			// Extract the custom records that were attached to the
+			// incoming add, so that they are available to the
+			// interceptor and htlc event subscribers. The records
+			// were validated when the add was received.
+			inWireRecords, err := record.ParseWireCustomRecords(
+				pd.ExtraData,
+			)
+			if err != nil {
+				l.log.Warnf("unable to parse custom records of "+
+					"htlc %v: %v", pd.HtlcIndex, err)
+			}
+
 			switch fwdPkg.State {
 			case channeldb.FwdStateProcessed:
 				// This add was not forwarded on the previous
@@ -3119,6 +3603,8 @@ func (l *channelLink) processRemoteAdds(fwdPkg *channeldb.FwdPkg,
 					incomingTimeout: pd.Timeout,
 					outgoingTimeout: fwdInfo.OutgoingCTLV,
 					customRecords:   pld.CustomRecords(),
+
+					inWireCustomRecords: inWireRecords,
 				}
 				switchPackets = append(
 					switchPackets, updatePacket,
@@ -3143,7 +3629,7 @@ func (l *channelLink) processRemoteAdds(fwdPkg *channeldb.FwdPkg,
 			// _next_ hop using the hop iterator decoded for the
 			// current hop.
 			buf := bytes.NewBuffer(addMsg.OnionBlob[0:0])
-			err := chanIterator.EncodeNextHop(buf)
+			err = chanIterator.EncodeNextHop(buf)
 			if err != nil {
 				l.log.Errorf("unable to encode the "+
 					"remaining route %v", err)
@@ -3183,6 +3669,8 @@ func (l *channelLink) processRemoteAdds(fwdPkg *channeldb.FwdPkg,
 					incomingTimeout: pd.Timeout,
 					outgoingTimeout: fwdInfo.OutgoingCTLV,
 					customRecords:   pld.CustomRecords(),
+
+					inWireCustomRecords: inWireRecords,
 				}
 
 				fwdPkg.FwdFilter.Set(idx)
@@ -3441,6 +3929,23 @@ func (l *channelLink) sendMalformedHTLCError(htlcIndex uint64,
 	})
 }
 
+// stfuFailf fails the link as the remote party violated the quiescence
+// protocol. As this doesn't warrant closing the channel, we only send a warning
+// and disconnect, which ends quiescence.
+func (l *channelLink) stfuFailf(format string, a ...interface{}) {
+	reason := fmt.Sprintf(format, a...)
+
+	l.fail(
+		LinkFailureError{
+			code:          ErrStfuViolation,
+			FailureAction: LinkFailureDisconnect,
+			SendData:      []byte(reason),
+			Warning:       true,
+		},
+		"quiescence protocol violation: %v", reason,
+	)
+}
+
 // fail is a function which is used to encapsulate the action necessary for
 // properly failing the link. It takes a LinkFailureError, which will be passed
 // to the OnChannelFailure closure, in order for it to determine if we should
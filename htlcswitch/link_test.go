@@ -7,6 +7,7 @@ import (
 	"encoding/binary"
 	"fmt"
 	"io"
+	"math"
 	"net"
 	"reflect"
 	"runtime"
@@ -1895,6 +1896,7 @@ func newSingleLinkTestHarness(t *testing.T, chanAmt, chanReserve btcutil.Amount)
 		NotifyActiveChannel:     func(wire.OutPoint) {},
 		NotifyInactiveChannel:   func(wire.OutPoint) {},
 		NotifyInactiveLinkEvent: func(wire.OutPoint) {},
+		NotifyUpgradedChannel:   func(wire.OutPoint) {},
 		HtlcNotifier:            aliceSwitch.cfg.HtlcNotifier,
 		GetAliases:              getAliases,
 	}
@@ -3792,6 +3794,68 @@ func TestChannelLinkShutdownDuringForward(t *testing.T) {
 	}
 }
 
+// TestChannelLinkFeeExposure tests that the link rejects HTLC adds and fee
+// updates that would push the fee exposure of its channel over the limit.
+func TestChannelLinkFeeExposure(t *testing.T) {
+	t.Parallel()
+
+	const chanAmt = btcutil.SatoshiPerBitcoin * 5
+	const chanReserve = btcutil.SatoshiPerBitcoin * 1
+	aliceLink, _, _, start, _, err :=
+		newSingleLinkTestHarness(t, chanAmt, chanReserve)
+	require.NoError(t, err)
+
+	coreLink := aliceLink.(*channelLink)
+	feeRate := coreLink.channel.CommitFeeRate()
+
+	// We'll allow for 1000 sats of dust on top of the current exposure of
+	// either commitment.
+	baseExposure := coreLink.channel.FeeExposure(false, feeRate)
+	remoteExposure := coreLink.channel.FeeExposure(true, feeRate)
+	if remoteExposure > baseExposure {
+		baseExposure = remoteExposure
+	}
+	maxExposure := baseExposure + lnwire.NewMSatFromSatoshis(1000)
+	coreLink.cfg.MaxFeeExposure = maxExposure
+
+	require.NoError(t, start())
+	defer aliceLink.Stop()
+
+	// A dust HTLC that stays within the limit is accepted, unless the dust
+	// already held by the mailbox pushes it over.
+	dustAmt := lnwire.NewMSatFromSatoshis(1)
+	require.False(t, coreLink.exceedsFeeExposure(dustAmt, false, 0, 0))
+	require.True(t, coreLink.exceedsFeeExposure(
+		dustAmt, false, maxExposure-baseExposure, 0,
+	))
+
+	// Incoming HTLCs are already part of the commitment, so their amount
+	// isn't added again.
+	require.False(t, coreLink.exceedsFeeExposure(
+		maxExposure-baseExposure, true, 0, 0,
+	))
+
+	// The current feerate stays within the limit, while a much higher one
+	// exceeds it.
+	require.False(t, coreLink.feeRateExceedsFeeExposure(feeRate))
+	require.True(t, coreLink.feeRateExceedsFeeExposure(feeRate*10))
+
+	// Evaluating the limit doesn't count as a rejection, only actually
+	// rejecting an add does.
+	stats := aliceLink.FeeExposureStats()
+	require.Equal(t, maxExposure, stats.MaxFeeExposure)
+	require.Equal(
+		t, coreLink.channel.FeeExposure(false, feeRate),
+		stats.LocalExposure,
+	)
+	require.Zero(t, stats.RejectedAdds)
+	require.Zero(t, stats.RejectedFeeUpdates)
+
+	coreLink.recordFeeExposureRejection()
+	stats = aliceLink.FeeExposureStats()
+	require.EqualValues(t, 1, stats.RejectedAdds)
+}
+
 // TestChannelLinkUpdateCommitFee tests that when a new block comes in, the
 // channel link properly checks to see if it should update the commitment fee.
 func TestChannelLinkUpdateCommitFee(t *testing.T) {
@@ -4337,6 +4401,7 @@ func (h *persistentLinkHarness) restartLink(
 		NotifyActiveChannel:     func(wire.OutPoint) {},
 		NotifyInactiveChannel:   func(wire.OutPoint) {},
 		NotifyInactiveLinkEvent: func(wire.OutPoint) {},
+		NotifyUpgradedChannel:   func(wire.OutPoint) {},
 		HtlcNotifier:            aliceSwitch.cfg.HtlcNotifier,
 		SyncStates:              syncStates,
 		GetAliases:              getAliases,
@@ -6444,6 +6509,72 @@ func TestShutdownIfChannelClean(t *testing.T) {
 	}
 }
 
+// TestLinkDisableAdds tests that a link with disabled adds rejects new
+// incoming and outgoing HTLCs, while still processing other updates.
+func TestLinkDisableAdds(t *testing.T) {
+	t.Parallel()
+
+	const chanAmt = btcutil.SatoshiPerBitcoin * 5
+	const chanReserve = btcutil.SatoshiPerBitcoin * 1
+	aliceLink, bobChannel, _, start, _, err :=
+		newSingleLinkTestHarness(t, chanAmt, chanReserve)
+	require.NoError(t, err)
+
+	var (
+		coreLink  = aliceLink.(*channelLink)
+		aliceMsgs = coreLink.cfg.Peer.(*mockPeer).sentMsgs
+	)
+
+	err = start()
+	require.NoError(t, err)
+
+	ctx := linkTestContext{
+		t:          t,
+		aliceLink:  aliceLink,
+		bobChannel: bobChannel,
+		aliceMsgs:  aliceMsgs,
+	}
+
+	aliceLink.DisableAdds()
+	require.ErrorIs(
+		t, aliceLink.MayAddOutgoingHtlc(1000), ErrLinkAddsDisabled,
+	)
+
+	// An HTLC sent by Bob is failed back once it's locked in, even though
+	// Alice knows the invoice.
+	htlc := generateHtlc(t, coreLink, 0)
+
+	// <---add-----
+	ctx.sendHtlcBobToAlice(htlc)
+	// <---sig-----
+	ctx.sendCommitSigBobToAlice(1)
+	// ----rev---->
+	ctx.receiveRevAndAckAliceToBob()
+	// ----sig---->
+	ctx.receiveCommitSigAliceToBob(1)
+	// <---rev-----
+	ctx.sendRevAndAckBobToAlice()
+	// ----fail--->
+	ctx.receiveFailAliceToBob()
+	// ----sig---->
+	ctx.receiveCommitSigAliceToBob(0)
+	// <---rev-----
+	ctx.sendRevAndAckBobToAlice()
+	// <---sig-----
+	ctx.sendCommitSigBobToAlice(0)
+	// ----rev---->
+	ctx.receiveRevAndAckAliceToBob()
+
+	// An HTLC forwarded to Alice's link isn't sent to Bob.
+	htlc, _ = generateHtlcAndInvoice(t, 1)
+	ctx.sendHtlcAliceToBob(1, htlc)
+	ctx.assertNoMsgFromAlice(100 * time.Millisecond)
+
+	// Once adds are enabled again, new outgoing HTLCs are allowed.
+	aliceLink.EnableAdds()
+	require.NoError(t, aliceLink.MayAddOutgoingHtlc(1000))
+}
+
 // TestPipelineSettle tests that a link should only pipeline a settle if the
 // related add is fully locked-in meaning it is on both sides' commitment txns.
 func TestPipelineSettle(t *testing.T) {
@@ -6730,3 +6861,257 @@ func TestChannelLinkShortFailureRelay(t *testing.T) {
 	default:
 	}
 }
+
+// TestChannelLinkQuiescence tests that the link can be made quiescent by both
+// the remote party and a local request, and that quiescence can be resumed.
+func TestChannelLinkQuiescence(t *testing.T) {
+	t.Parallel()
+
+	const chanAmt = btcutil.SatoshiPerBitcoin * 5
+	const chanReserve = btcutil.SatoshiPerBitcoin * 1
+	aliceLink, _, _, start, _, err :=
+		newSingleLinkTestHarness(t, chanAmt, chanReserve)
+	require.NoError(t, err)
+	require.NoError(t, start())
+	defer aliceLink.Stop()
+
+	alicePeer := aliceLink.(*channelLink).cfg.Peer.(*mockPeer)
+	chanID := aliceLink.ChanID()
+
+	receiveStfu := func() *lnwire.Stfu {
+		t.Helper()
+
+		select {
+		case msg := <-alicePeer.sentMsgs:
+			stfu, ok := msg.(*lnwire.Stfu)
+			require.Truef(t, ok, "expected stfu, got %T", msg)
+			require.Equal(t, chanID, stfu.ChanID)
+
+			return stfu
+
+		case <-time.After(5 * time.Second):
+			t.Fatalf("stfu not sent")
+		}
+
+		return nil
+	}
+
+	// The channel isn't quiescent yet, so there's nothing to resume.
+	require.ErrorIs(t, aliceLink.Resume(), ErrChannelNotQuiescent)
+
+	// When the remote party requests quiescence, the link replies right
+	// away as none of its updates are pending.
+	aliceLink.HandleChannelUpdate(lnwire.NewStfu(chanID, true))
+	require.False(t, receiveStfu().Initiator)
+	require.NoError(t, aliceLink.Resume())
+
+	// Once resumed, the channel can be made quiescent again, this time by
+	// a local request.
+	type quiesceResult struct {
+		initiator bool
+		err       error
+	}
+	results := make(chan quiesceResult, 1)
+	go func() {
+		initiator, err := aliceLink.Quiesce()
+		results <- quiesceResult{initiator, err}
+	}()
+
+	require.True(t, receiveStfu().Initiator)
+	aliceLink.HandleChannelUpdate(lnwire.NewStfu(chanID, false))
+
+	select {
+	case result := <-results:
+		require.NoError(t, result.err)
+		require.True(t, result.initiator)
+
+	case <-time.After(5 * time.Second):
+		t.Fatalf("channel not quiescent")
+	}
+
+	require.NoError(t, aliceLink.Resume())
+}
+
+// TestChannelLinkCommitUpgrade tests that the link upgrades the commitment of
+// the channel using the dynamic commitments protocol, and resumes quiescence
+// once the upgrade is locked in.
+func TestChannelLinkCommitUpgrade(t *testing.T) {
+	t.Parallel()
+
+	const chanAmt = btcutil.SatoshiPerBitcoin * 5
+	const chanReserve = btcutil.SatoshiPerBitcoin * 1
+	aliceLink, bobChannel, _, start, _, err :=
+		newSingleLinkTestHarness(t, chanAmt, chanReserve)
+	require.NoError(t, err)
+
+	// Other sub-systems are notified once the upgrade is locked in.
+	coreLink := aliceLink.(*channelLink)
+	upgraded := make(chan wire.OutPoint, 1)
+	coreLink.cfg.NotifyUpgradedChannel = func(chanPoint wire.OutPoint) {
+		upgraded <- chanPoint
+	}
+
+	require.NoError(t, start())
+	defer aliceLink.Stop()
+
+	alicePeer := coreLink.cfg.Peer.(*mockPeer)
+	chanID := aliceLink.ChanID()
+
+	receiveMsg := func() lnwire.Message {
+		t.Helper()
+
+		select {
+		case msg := <-alicePeer.sentMsgs:
+			return msg
+
+		case <-time.After(5 * time.Second):
+			t.Fatalf("message not sent")
+		}
+
+		return nil
+	}
+
+	chanType := channeldb.SingleFunderTweaklessBit |
+		channeldb.AnchorOutputsBit | channeldb.ZeroHtlcTxFeeBit
+	dustLimit := btcutil.Amount(354)
+
+	result := make(chan error, 1)
+	go func() {
+		result <- aliceLink.UpgradeCommitment(&CommitUpgrade{
+			ChanType:       &chanType,
+			LocalDustLimit: &dustLimit,
+		})
+	}()
+
+	// Alice first makes the channel quiescent, after which she proposes
+	// the upgrade.
+	stfu, ok := receiveMsg().(*lnwire.Stfu)
+	require.True(t, ok)
+	require.True(t, stfu.Initiator)
+	aliceLink.HandleChannelUpdate(lnwire.NewStfu(chanID, false))
+
+	propose, ok := receiveMsg().(*lnwire.DynPropose)
+	require.True(t, ok)
+	require.Equal(t, chanID, propose.ChanID)
+	require.Equal(t, dustLimit, *propose.DustLimit)
+	require.Nil(t, propose.CsvDelay)
+	require.Nil(t, propose.MaxAcceptedHTLCs)
+	require.NotNil(t, propose.ChannelType)
+
+	// Bob accepts the upgrade, after which both parties sign commitments
+	// using the new parameters.
+	bobParams := bobChannel.State().CommitParams()
+	params, err := proposedParams(bobParams, propose)
+	require.NoError(t, err)
+	require.Equal(t, chanType, params.ChanType)
+
+	err = bobChannel.UpgradeCommitment(params, 2016)
+	require.NoError(t, err)
+	aliceLink.HandleChannelUpdate(&lnwire.DynAck{ChanID: chanID})
+
+	require.NoError(t, handleStateUpdate(coreLink, bobChannel))
+
+	select {
+	case err := <-result:
+		require.NoError(t, err)
+
+	case <-time.After(5 * time.Second):
+		t.Fatalf("commitment upgrade not locked in")
+	}
+
+	require.Equal(t, chanType, coreLink.channel.State().ChanType)
+	require.Equal(
+		t, dustLimit, coreLink.channel.State().LocalChanCfg.DustLimit,
+	)
+	require.False(t, bobChannel.CommitUpgradePending())
+
+	select {
+	case chanPoint := <-upgraded:
+		require.Equal(t, *aliceLink.ChannelPoint(), chanPoint)
+
+	case <-time.After(5 * time.Second):
+		t.Fatalf("upgrade not notified")
+	}
+
+	// Quiescence was resumed once the upgrade was locked in.
+	require.ErrorIs(t, aliceLink.Resume(), ErrChannelNotQuiescent)
+}
+
+// TestChannelLinkCommitUpgradeRemote tests that the link rejects commitment
+// upgrades proposed by the remote party that it can't accept, and applies the
+// ones it accepted once the remote party signs a new commitment.
+func TestChannelLinkCommitUpgradeRemote(t *testing.T) {
+	t.Parallel()
+
+	const chanAmt = btcutil.SatoshiPerBitcoin * 5
+	const chanReserve = btcutil.SatoshiPerBitcoin * 1
+	aliceLink, bobChannel, batchTick, start, _, err :=
+		newSingleLinkTestHarness(t, chanAmt, chanReserve)
+	require.NoError(t, err)
+	require.NoError(t, start())
+	defer aliceLink.Stop()
+
+	coreLink := aliceLink.(*channelLink)
+	alicePeer := coreLink.cfg.Peer.(*mockPeer)
+	chanID := aliceLink.ChanID()
+
+	receiveMsg := func() lnwire.Message {
+		t.Helper()
+
+		select {
+		case msg := <-alicePeer.sentMsgs:
+			return msg
+
+		case <-time.After(5 * time.Second):
+			t.Fatalf("message not sent")
+		}
+
+		return nil
+	}
+
+	quiesce := func() {
+		t.Helper()
+
+		aliceLink.HandleChannelUpdate(lnwire.NewStfu(chanID, true))
+		stfu, ok := receiveMsg().(*lnwire.Stfu)
+		require.True(t, ok)
+		require.False(t, stfu.Initiator)
+	}
+
+	// Bob proposes a CSV delay for Alice's funds that exceeds her limit,
+	// which she rejects. This ends quiescence.
+	quiesce()
+	csvDelay := uint16(math.MaxUint16)
+	aliceLink.HandleChannelUpdate(&lnwire.DynPropose{
+		ChanID:   chanID,
+		CsvDelay: &csvDelay,
+	})
+	_, ok := receiveMsg().(*lnwire.DynReject)
+	require.True(t, ok)
+	require.ErrorIs(t, aliceLink.Resume(), ErrChannelNotQuiescent)
+
+	// Bob now proposes to upgrade the channel to anchors, which Alice
+	// accepts.
+	quiesce()
+	chanType := channeldb.SingleFunderTweaklessBit |
+		channeldb.AnchorOutputsBit | channeldb.ZeroHtlcTxFeeBit
+	chanTypeFeatures := lnwallet.CommitFormatFeatures(chanType)
+	aliceLink.HandleChannelUpdate(&lnwire.DynPropose{
+		ChanID:      chanID,
+		ChannelType: &chanTypeFeatures,
+	})
+	_, ok = receiveMsg().(*lnwire.DynAck)
+	require.True(t, ok)
+
+	// The upgrade is applied once Bob signs a commitment using the new
+	// parameters.
+	params := bobChannel.State().CommitParams()
+	params.ChanType = chanType
+	require.NoError(t, bobChannel.UpgradeCommitment(&params, 2016))
+	require.NoError(t, updateState(batchTick, coreLink, bobChannel, false))
+
+	require.Eventually(t, func() bool {
+		return !coreLink.channel.CommitUpgradePending()
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Equal(t, chanType, coreLink.channel.State().ChanType)
+}
@@ -8,6 +8,37 @@ var (
 
 	// ErrLinkFailedShutdown signals that a requested shutdown failed.
 	ErrLinkFailedShutdown = errors.New("link failed to shutdown")
+
+	// ErrLinkAddsDisabled signals that the link doesn't accept new HTLC
+	// adds, as the channel is being drained.
+	ErrLinkAddsDisabled = errors.New("link adds disabled, channel " +
+		"draining")
+
+	// ErrQuiescenceNotSupported signals that the channel can't be made
+	// quiescent, as the quiescence feature wasn't negotiated with the
+	// peer.
+	ErrQuiescenceNotSupported = errors.New("quiescence not supported by " +
+		"peer")
+
+	// ErrChannelNotQuiescent signals that quiescence can't be resumed, as
+	// the channel isn't quiescent.
+	ErrChannelNotQuiescent = errors.New("channel not quiescent")
+
+	// ErrNotQuiescenceInitiator signals that the channel was made
+	// quiescent by the remote party, so we can't lead a protocol that
+	// requires quiescence.
+	ErrNotQuiescenceInitiator = errors.New("not the initiator of " +
+		"quiescence")
+
+	// ErrDynamicCommitmentsNotSupported signals that the commitment of the
+	// channel can't be upgraded, as the dynamic commitments feature wasn't
+	// negotiated with the peer.
+	ErrDynamicCommitmentsNotSupported = errors.New("dynamic commitments " +
+		"not supported by peer")
+
+	// ErrCommitUpgradeRejected signals that the remote party rejected our
+	// proposal to upgrade the commitment of the channel.
+	ErrCommitUpgradeRejected = errors.New("commitment upgrade rejected")
 )
 
 // errorCode encodes the possible types of errors that will make us fail the
@@ -51,6 +82,10 @@ const (
 	// circuit map. This is non-fatal and will resolve itself (usually
 	// within several minutes).
 	ErrCircuitError
+
+	// ErrStfuViolation indicates that the remote peer didn't follow the
+	// rules of the quiescence protocol.
+	ErrStfuViolation
 )
 
 // LinkFailureAction is an enum-like type that describes the action that should
@@ -89,6 +124,11 @@ type LinkFailureError struct {
 	// SendData is a byte slice that will be sent to the peer. If nil a
 	// generic error will be sent.
 	SendData []byte
+
+	// Warning indicates that the data should be sent to the peer as a
+	// warning rather than an error, as the failure doesn't warrant the
+	// channel to be closed.
+	Warning bool
 }
 
 // A compile time check to ensure LinkFailureError implements the error
@@ -118,6 +158,8 @@ func (e LinkFailureError) Error() string {
 		return "unable to resume channel, recovery required"
 	case ErrCircuitError:
 		return "non-fatal circuit map error"
+	case ErrStfuViolation:
+		return "quiescence protocol violation"
 	default:
 		return "unknown error"
 	}
@@ -138,7 +180,8 @@ func (e LinkFailureError) ShouldSendToPeer() bool {
 		ErrInvalidUpdate,
 		ErrInvalidCommitment,
 		ErrInvalidRevocation,
-		ErrRecoveryError:
+		ErrRecoveryError,
+		ErrStfuViolation:
 
 		return true
 
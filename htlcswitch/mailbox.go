@@ -135,12 +135,23 @@ type memoryMailBox struct {
 	addIndex map[CircuitKey]*list.Element
 	addHead  *list.Element
 
+	// heldPkts holds the Adds that the switch queued because they exceeded
+	// a rate limit, ordered by their release time. They're kept outside of
+	// addPkts until released, so that they don't hold back any other Adds.
+	heldPkts  *list.List
+	heldIndex map[CircuitKey]*list.Element
+
 	pktMtx  sync.Mutex
 	pktCond *sync.Cond
 
 	pktOutbox chan *htlcPacket
 	pktReset  chan chan struct{}
 
+	// pktAdded is signaled when a packet is added while the courier has
+	// nothing to deliver but held Adds, which wakes it up if it's waiting
+	// for the release of one of them.
+	pktAdded chan struct{}
+
 	wireShutdown chan struct{}
 	pktShutdown  chan struct{}
 	quit         chan struct{}
@@ -164,12 +175,15 @@ func newMemoryMailBox(cfg *mailBoxConfig) *memoryMailBox {
 		wireMessages:  list.New(),
 		repPkts:       list.New(),
 		addPkts:       list.New(),
+		heldPkts:      list.New(),
 		messageOutbox: make(chan lnwire.Message),
 		pktOutbox:     make(chan *htlcPacket),
 		msgReset:      make(chan chan struct{}, 1),
 		pktReset:      make(chan chan struct{}, 1),
+		pktAdded:      make(chan struct{}, 1),
 		repIndex:      make(map[CircuitKey]*list.Element),
 		addIndex:      make(map[CircuitKey]*list.Element),
+		heldIndex:     make(map[CircuitKey]*list.Element),
 		wireShutdown:  make(chan struct{}),
 		pktShutdown:   make(chan struct{}),
 		quit:          make(chan struct{}),
@@ -301,6 +315,13 @@ func (m *memoryMailBox) AckPacket(inKey CircuitKey) bool {
 		return true
 	}
 
+	if entry, ok := m.heldIndex[inKey]; ok {
+		m.heldPkts.Remove(entry)
+		delete(m.heldIndex, inKey)
+
+		return true
+	}
+
 	return false
 }
 
@@ -365,6 +386,48 @@ func (p *pktWithExpiry) deadline(clock clock.Clock) <-chan time.Time {
 	return clock.TickAfter(p.expiry.Sub(clock.Now()))
 }
 
+// release returns a channel that ticks once the packet may be delivered.
+func (p *pktWithExpiry) release(clock clock.Clock) <-chan time.Time {
+	return clock.TickAfter(p.pkt.holdUntil.Sub(clock.Now()))
+}
+
+// releaseHeldAdds moves the held Adds whose release time has passed into the
+// queue of Adds. Each Add is inserted among the undelivered Adds by its
+// expiry, so that the head of the queue remains the next Add to expire.
+//
+// NOTE: This method MUST be called with the pktCond lock held.
+func (m *memoryMailBox) releaseHeldAdds(now time.Time) {
+	for e := m.heldPkts.Front(); e != nil; e = m.heldPkts.Front() {
+		held := e.Value.(*pktWithExpiry)
+		if held.pkt.holdUntil.After(now) {
+			return
+		}
+
+		inKey := held.pkt.inKey()
+		m.heldPkts.Remove(e)
+		delete(m.heldIndex, inKey)
+
+		next := m.addHead
+		for next != nil &&
+			!next.Value.(*pktWithExpiry).expiry.After(held.expiry) {
+
+			next = next.Next()
+		}
+
+		var entry *list.Element
+		if next != nil {
+			entry = m.addPkts.InsertBefore(held, next)
+		} else {
+			entry = m.addPkts.PushBack(held)
+		}
+		m.addIndex[inKey] = entry
+
+		if m.addHead == nil || m.addHead == next {
+			m.addHead = entry
+		}
+	}
+}
+
 // mailCourier is a dedicated goroutine whose job is to reliably deliver
 // messages of a particular type. There are two types of couriers: wire
 // couriers, and mail couriers. Depending on the passed courierType, this
@@ -402,7 +465,9 @@ func (m *memoryMailBox) mailCourier(cType courierType) {
 
 		case pktCourier:
 			m.pktCond.L.Lock()
-			for m.repHead == nil && m.addHead == nil {
+			for m.repHead == nil && m.addHead == nil &&
+				m.heldPkts.Front() == nil {
+
 				m.pktCond.Wait()
 
 				select {
@@ -429,6 +494,7 @@ func (m *memoryMailBox) mailCourier(cType courierType) {
 			nextRepEl *list.Element
 			nextAdd   *pktWithExpiry
 			nextAddEl *list.Element
+			nextHeld  *pktWithExpiry
 			nextMsg   lnwire.Message
 		)
 		switch cType {
@@ -450,6 +516,15 @@ func (m *memoryMailBox) mailCourier(cType courierType) {
 			// pending Add if it's present. Due to clock
 			// monotonicity, we know that the head of the Adds is
 			// the next to expire.
+			//
+			// Any held Adds that are due are released first, and
+			// we'll also peek at the next held Add so that we can
+			// release it once it's due.
+			m.releaseHeldAdds(m.cfg.clock.Now())
+			if front := m.heldPkts.Front(); front != nil {
+				nextHeld = front.Value.(*pktWithExpiry)
+			}
+
 			if m.repHead != nil {
 				nextRep = m.repHead.Value.(*htlcPacket)
 				nextRepEl = m.repHead
@@ -492,6 +567,7 @@ func (m *memoryMailBox) mailCourier(cType courierType) {
 				addOutbox chan *htlcPacket
 				add       *htlcPacket
 				deadline  <-chan time.Time
+				released  <-chan time.Time
 			)
 
 			// Prioritize delivery of Settle/Fail packets over Adds.
@@ -504,11 +580,10 @@ func (m *memoryMailBox) mailCourier(cType courierType) {
 			// NOTE: Both types are eventually delivered over the
 			// same channel, but we can control which is delivered
 			// by exclusively making one nil and the other non-nil.
-			// We know from our loop condition that at least one
-			// nextRep and nextAdd are non-nil.
+			// Both may be nil if we only have held Adds.
 			if nextRep != nil {
 				pktOutbox = m.pktOutbox
-			} else {
+			} else if nextAdd != nil {
 				addOutbox = m.pktOutbox
 			}
 
@@ -528,6 +603,14 @@ func (m *memoryMailBox) mailCourier(cType courierType) {
 				deadline = nextAdd.deadline(m.cfg.clock)
 			}
 
+			// If the switch queued an Add because it exceeded a
+			// rate limit, we'll wake up once it may be delivered.
+			// Held Adds can't expire before their release, as the
+			// switch only holds them for less than the expiry.
+			if nextHeld != nil {
+				released = nextHeld.release(m.cfg.clock)
+			}
+
 			select {
 			case pktOutbox <- nextRep:
 				m.pktCond.L.Lock()
@@ -547,6 +630,15 @@ func (m *memoryMailBox) mailCourier(cType courierType) {
 				}
 				m.pktCond.L.Unlock()
 
+			// The next held Add has been released, so we'll loop
+			// back around to move it into the queue of Adds.
+			case <-released:
+
+			// A packet was added while we might have nothing to
+			// deliver but held Adds, so we'll loop back around to
+			// pick it up.
+			case <-m.pktAdded:
+
 			case <-deadline:
 				log.Debugf("Expiring add htlc with "+
 					"keystone=%v", add.keystone())
@@ -592,6 +684,14 @@ func (m *memoryMailBox) AddMessage(msg lnwire.Message) error {
 // interface.
 func (m *memoryMailBox) AddPacket(pkt *htlcPacket) error {
 	m.pktCond.L.Lock()
+
+	// If there's nothing to deliver but held Adds, the courier may be
+	// waiting for the release of the next one, so it needs to be woken up
+	// to pick up the new packet. Otherwise, it picks it up once it
+	// delivered the current one.
+	onlyHeld := m.repHead == nil && m.addHead == nil &&
+		m.heldPkts.Front() != nil
+
 	switch htlc := pkt.htlc.(type) {
 	// Split off Settle/Fail packets into the repPkts queue.
 	case *lnwire.UpdateFulfillHTLC, *lnwire.UpdateFailHTLC:
@@ -613,10 +713,25 @@ func (m *memoryMailBox) AddPacket(pkt *htlcPacket) error {
 			return ErrPacketAlreadyExists
 		}
 
-		entry := m.addPkts.PushBack(&pktWithExpiry{
+		if _, ok := m.heldIndex[pkt.inKey()]; ok {
+			m.pktCond.L.Unlock()
+			return ErrPacketAlreadyExists
+		}
+
+		now := m.cfg.clock.Now()
+		addPkt := &pktWithExpiry{
 			pkt:    pkt,
-			expiry: m.cfg.clock.Now().Add(m.cfg.expiry),
-		})
+			expiry: now.Add(m.cfg.expiry),
+		}
+
+		// Adds that are held by the rate limiter are kept aside, in
+		// the order of their release, until they may be delivered.
+		if pkt.holdUntil.After(now) {
+			m.addHeldPkt(addPkt)
+			break
+		}
+
+		entry := m.addPkts.PushBack(addPkt)
 		m.addIndex[pkt.inKey()] = entry
 		if m.addHead == nil {
 			m.addHead = entry
@@ -632,9 +747,40 @@ func (m *memoryMailBox) AddPacket(pkt *htlcPacket) error {
 	// additional packets to consume.
 	m.pktCond.Signal()
 
+	if onlyHeld {
+		select {
+		case m.pktAdded <- struct{}{}:
+		default:
+		}
+	}
+
 	return nil
 }
 
+// addHeldPkt inserts an Add that is held by the rate limiter into the list of
+// held Adds, which is ordered by release time.
+//
+// NOTE: This method MUST be called with the pktCond lock held.
+func (m *memoryMailBox) addHeldPkt(addPkt *pktWithExpiry) {
+	prev := m.heldPkts.Back()
+	for prev != nil {
+		held := prev.Value.(*pktWithExpiry)
+		if !held.pkt.holdUntil.After(addPkt.pkt.holdUntil) {
+			break
+		}
+
+		prev = prev.Prev()
+	}
+
+	var entry *list.Element
+	if prev != nil {
+		entry = m.heldPkts.InsertAfter(addPkt, prev)
+	} else {
+		entry = m.heldPkts.PushFront(addPkt)
+	}
+	m.heldIndex[addPkt.pkt.inKey()] = entry
+}
+
 // SetFeeRate sets the memoryMailBox's feerate for use in DustPackets.
 func (m *memoryMailBox) SetFeeRate(feeRate chainfee.SatPerKWeight) {
 	m.pktCond.L.Lock()
@@ -668,8 +814,17 @@ func (m *memoryMailBox) DustPackets() (lnwire.MilliSatoshi,
 
 	// Run through the map of HTLC's and determine the dust sum with calls
 	// to the memoryMailBox's isDust closure. Note that all mailbox packets
-	// are outgoing so the second argument to isDust will be false.
+	// are outgoing so the second argument to isDust will be false. Held
+	// Adds are included as they'll be delivered eventually.
+	entries := make([]*list.Element, 0, len(m.addIndex)+len(m.heldIndex))
 	for _, e := range m.addIndex {
+		entries = append(entries, e)
+	}
+	for _, e := range m.heldIndex {
+		entries = append(entries, e)
+	}
+
+	for _, e := range entries {
 		addPkt := e.Value.(*pktWithExpiry).pkt
 
 		// Evaluate whether this HTLC is dust on the local commitment.
@@ -533,6 +533,89 @@ func TestMailBoxAddExpiry(t *testing.T) {
 	ctx.checkFails(secondBatch)
 }
 
+// TestMailBoxHeldAdd asserts that the mailbox doesn't deliver an Add that was
+// queued by the rate limiter before its release time, and that Adds queued
+// after it aren't held back.
+func TestMailBoxHeldAdd(t *testing.T) {
+	t.Parallel()
+
+	var (
+		startTime   = time.Now()
+		releaseTime = startTime.Add(10 * time.Second)
+	)
+
+	ctx := newMailboxContext(t, startTime, time.Minute)
+
+	// Add a packet that is held until the release time, followed by a
+	// packet that isn't held at all.
+	held := &htlcPacket{
+		incomingChanID: lnwire.NewShortChanIDFromInt(1),
+		incomingHTLCID: 0,
+		htlc:           &lnwire.UpdateAddHTLC{ID: 0},
+		holdUntil:      releaseTime,
+	}
+	unheld := &htlcPacket{
+		incomingChanID: lnwire.NewShortChanIDFromInt(1),
+		incomingHTLCID: 1,
+		htlc:           &lnwire.UpdateAddHTLC{ID: 1},
+	}
+	require.NoError(t, ctx.mailbox.AddPacket(held))
+	require.NoError(t, ctx.mailbox.AddPacket(unheld))
+
+	// Only the packet that isn't held is delivered right away.
+	ctx.receivePkts([]*htlcPacket{unheld})
+
+	select {
+	case pkt := <-ctx.mailbox.PacketOutBox():
+		t.Fatalf("unexpected delivery of %v", pkt.inKey())
+
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// A held packet counts as a duplicate as well.
+	require.ErrorIs(
+		t, ctx.mailbox.AddPacket(held), ErrPacketAlreadyExists,
+	)
+
+	// Once the release time is reached, the held packet is delivered and
+	// nothing is failed back.
+	ctx.clock.SetTime(releaseTime)
+	ctx.receivePkts([]*htlcPacket{held})
+	ctx.checkFails(nil)
+}
+
+// TestMailBoxHeldAddAck asserts that an Add held by the rate limiter can be
+// removed from the mailbox before its release, after which it's never
+// delivered.
+func TestMailBoxHeldAddAck(t *testing.T) {
+	t.Parallel()
+
+	var (
+		startTime   = time.Now()
+		releaseTime = startTime.Add(10 * time.Second)
+	)
+
+	ctx := newMailboxContext(t, startTime, time.Minute)
+
+	held := &htlcPacket{
+		incomingChanID: lnwire.NewShortChanIDFromInt(1),
+		incomingHTLCID: 0,
+		htlc:           &lnwire.UpdateAddHTLC{ID: 0},
+		holdUntil:      releaseTime,
+	}
+	require.NoError(t, ctx.mailbox.AddPacket(held))
+	require.True(t, ctx.mailbox.AckPacket(held.inKey()))
+
+	ctx.clock.SetTime(releaseTime)
+
+	select {
+	case pkt := <-ctx.mailbox.PacketOutBox():
+		t.Fatalf("unexpected delivery of %v", pkt.inKey())
+
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
 // TestMailBoxDuplicateAddPacket asserts that the mailbox returns an
 // ErrPacketAlreadyExists failure when two htlcPackets are added with identical
 // incoming circuit keys.
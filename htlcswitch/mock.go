@@ -816,6 +816,14 @@ func (f *mockChannelLink) getDustSum(remote bool) lnwire.MilliSatoshi {
 	return 0
 }
 
+func (f *mockChannelLink) exceedsFeeExposure(amt lnwire.MilliSatoshi,
+	incoming bool, localMailDust, remoteMailDust lnwire.MilliSatoshi) bool {
+
+	return false
+}
+
+func (f *mockChannelLink) recordFeeExposureRejection() {}
+
 func (f *mockChannelLink) getFeeRate() chainfee.SatPerKWeight {
 	return 0
 }
@@ -852,6 +860,22 @@ func (f *mockChannelLink) Stats() (
 	return 0, 0, 0
 }
 
+func (f *mockChannelLink) FeeExposureStats() FeeExposureStats {
+	return FeeExposureStats{}
+}
+
+func (f *mockChannelLink) Quiesce() (bool, error) {
+	return false, ErrQuiescenceNotSupported
+}
+
+func (f *mockChannelLink) Resume() error {
+	return ErrChannelNotQuiescent
+}
+
+func (f *mockChannelLink) UpgradeCommitment(*CommitUpgrade) error {
+	return ErrDynamicCommitmentsNotSupported
+}
+
 func (f *mockChannelLink) AttachMailBox(mailBox MailBox) {
 	f.mailBox = mailBox
 	f.packets = mailBox.PacketOutBox()
@@ -899,6 +923,8 @@ func (f *mockChannelLink) Stop()                                        {}
 func (f *mockChannelLink) EligibleToForward() bool                      { return f.eligible }
 func (f *mockChannelLink) MayAddOutgoingHtlc(lnwire.MilliSatoshi) error { return nil }
 func (f *mockChannelLink) ShutdownIfChannelClean() error                { return nil }
+func (f *mockChannelLink) DisableAdds()                                 {}
+func (f *mockChannelLink) EnableAdds()                                  {}
 func (f *mockChannelLink) setLiveShortChanID(sid lnwire.ShortChannelID) { f.shortChanID = sid }
 func (f *mockChannelLink) IsUnadvertised() bool                         { return f.unadvertised }
 func (f *mockChannelLink) UpdateShortChanID() (lnwire.ShortChannelID, error) {
@@ -1,6 +1,8 @@
 package htlcswitch
 
 import (
+	"time"
+
 	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/htlcswitch/hop"
 	"github.com/lightningnetwork/lnd/lnwire"
@@ -97,12 +99,21 @@ type htlcPacket struct {
 	// were included in the payload.
 	customRecords record.CustomSet
 
+	// inWireCustomRecords are the records in the custom type range that
+	// were attached to the incoming update_add_htlc message.
+	inWireCustomRecords record.CustomSet
+
 	// originalOutgoingChanID is used when sending back failure messages.
 	// It is only used for forwarded Adds on option_scid_alias channels.
 	// This is to avoid possible confusion if a payer uses the public SCID
 	// but receives a channel_update with the alias SCID. Instead, the
 	// payer should receive a channel_update with the public SCID.
 	originalOutgoingChanID lnwire.ShortChannelID
+
+	// holdUntil is set by the switch when a forwarded Add exceeds a rate
+	// limit and is queued instead of failed. The mailbox of the outgoing
+	// link won't deliver the Add before this time.
+	holdUntil time.Time
 }
 
 // inKey returns the circuit key used to identify the incoming htlc.
@@ -0,0 +1,239 @@
+package htlcswitch
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+const (
+	// DefaultQuiescenceTimeout is the default time a channel may spend
+	// quiescing or quiescent before the link is failed and the connection
+	// recycled.
+	DefaultQuiescenceTimeout = time.Minute
+)
+
+// errStfuViolation is returned when the remote party doesn't follow the rules
+// of the quiescence protocol.
+type errStfuViolation struct {
+	reason string
+}
+
+// Error returns a human readable description of the violation.
+//
+// NOTE: Part of the error interface.
+func (e *errStfuViolation) Error() string {
+	return fmt.Sprintf("quiescence protocol violation: %v", e.reason)
+}
+
+// quiescerCfg holds the dependencies of a quiescer.
+type quiescerCfg struct {
+	// chanID is the channel that is made quiescent.
+	chanID lnwire.ChannelID
+
+	// channelInitiator is true if we funded the channel. If both parties
+	// request quiescence at the same time, the funder is considered the
+	// initiator.
+	channelInitiator bool
+
+	// hasPendingLocalUpdates returns true if any of our updates isn't yet
+	// irrevocably committed to by both commitments. We can't send stfu
+	// until that's no longer the case.
+	hasPendingLocalUpdates func() bool
+
+	// sendMsg sends a message to the remote party.
+	sendMsg func(lnwire.Message) error
+
+	// timeout is the time the channel may spend quiescing or quiescent.
+	// A zero value disables the timeout.
+	timeout time.Duration
+}
+
+// quiescer tracks the state of the stfu handshake of a channel. Once we either
+// requested quiescence or received stfu from the remote party, we stop sending
+// updates. As soon as none of our updates are pending anymore we send stfu
+// ourselves, after which the channel is quiescent once the remote party's stfu
+// has been received as well. Quiescence lasts until it's explicitly resumed or
+// the connection is lost.
+//
+// NOTE: The quiescer isn't safe for concurrent use, it's only accessed by the
+// link's htlcManager goroutine.
+type quiescer struct {
+	cfg quiescerCfg
+
+	// localRequested is true if a local subsystem requested quiescence.
+	localRequested bool
+
+	// sent is true once we've sent stfu.
+	sent bool
+
+	// received is true once we've received stfu.
+	received bool
+
+	// localInitiator is the initiator flag of the stfu we sent.
+	localInitiator bool
+
+	// remoteInitiator is the initiator flag of the stfu we received.
+	remoteInitiator bool
+
+	// waiters are notified of whether we're the initiator once the
+	// channel is quiescent.
+	waiters []chan bool
+
+	// resumeHooks are executed once quiescence is resumed, and hold the
+	// work that would've resulted in updates while the channel was
+	// quiescing.
+	resumeHooks []func()
+
+	// timer fires when the channel has been quiescing or quiescent for
+	// longer than the configured timeout.
+	timer *time.Timer
+}
+
+// newQuiescer creates a new quiescer for a channel that isn't quiescing.
+func newQuiescer(cfg quiescerCfg) *quiescer {
+	return &quiescer{
+		cfg: cfg,
+	}
+}
+
+// active returns true if the channel is quiescing or quiescent.
+func (q *quiescer) active() bool {
+	return q.localRequested || q.sent || q.received
+}
+
+// canSendUpdates returns true if we're allowed to send updates.
+func (q *quiescer) canSendUpdates() bool {
+	return !q.active()
+}
+
+// canRecvUpdates returns true if the remote party is allowed to send updates.
+func (q *quiescer) canRecvUpdates() bool {
+	return !q.received
+}
+
+// isQuiescent returns true if both parties have sent stfu.
+func (q *quiescer) isQuiescent() bool {
+	return q.sent && q.received
+}
+
+// isInitiator returns true if we're the initiator of quiescence, which is the
+// party that leads the protocol for which the channel was made quiescent.
+func (q *quiescer) isInitiator() bool {
+	switch {
+	case q.localInitiator && q.remoteInitiator:
+		return q.cfg.channelInitiator
+
+	default:
+		return q.localInitiator
+	}
+}
+
+// timeoutChan returns a channel that's sent on if the channel has been
+// quiescing or quiescent for too long. It returns nil if the timeout isn't
+// running.
+func (q *quiescer) timeoutChan() <-chan time.Time {
+	if q.timer == nil {
+		return nil
+	}
+
+	return q.timer.C
+}
+
+// startTimer starts the timeout if it isn't running yet.
+func (q *quiescer) startTimer() {
+	if q.timer != nil || q.cfg.timeout == 0 {
+		return
+	}
+
+	q.timer = time.NewTimer(q.cfg.timeout)
+}
+
+// request registers a local request for quiescence. The passed channel is sent
+// on once the channel is quiescent, indicating whether we're the initiator.
+// The channel must be buffered.
+func (q *quiescer) request(resp chan bool) error {
+	q.waiters = append(q.waiters, resp)
+	q.localRequested = true
+	q.startTimer()
+
+	return q.trySendStfu()
+}
+
+// recvStfu processes a stfu message of the remote party.
+func (q *quiescer) recvStfu(msg *lnwire.Stfu) error {
+	if q.received {
+		return &errStfuViolation{reason: "stfu received twice"}
+	}
+
+	q.received = true
+	q.remoteInitiator = msg.Initiator
+	q.startTimer()
+
+	return q.trySendStfu()
+}
+
+// trySendStfu sends stfu if quiescence was requested by either party, and none
+// of our updates are pending anymore. Once the channel is quiescent, any
+// waiters are notified.
+func (q *quiescer) trySendStfu() error {
+	if !q.sent && q.active() && !q.cfg.hasPendingLocalUpdates() {
+		// We're the initiator unless we're replying to the stfu of the
+		// remote party.
+		initiator := !q.received
+
+		err := q.cfg.sendMsg(lnwire.NewStfu(q.cfg.chanID, initiator))
+		if err != nil {
+			return err
+		}
+
+		q.sent = true
+		q.localInitiator = initiator
+	}
+
+	q.notifyWaiters()
+
+	return nil
+}
+
+// notifyWaiters notifies the waiters if the channel is quiescent.
+func (q *quiescer) notifyWaiters() {
+	if !q.isQuiescent() {
+		return
+	}
+
+	initiator := q.isInitiator()
+	for _, waiter := range q.waiters {
+		waiter <- initiator
+	}
+	q.waiters = nil
+}
+
+// onResume registers a hook that's executed once quiescence is resumed.
+func (q *quiescer) onResume(hook func()) {
+	q.resumeHooks = append(q.resumeHooks, hook)
+}
+
+// resume ends quiescence and executes the registered resume hooks. It fails
+// if the channel isn't quiescent.
+func (q *quiescer) resume() error {
+	if !q.isQuiescent() {
+		return ErrChannelNotQuiescent
+	}
+
+	if q.timer != nil {
+		q.timer.Stop()
+	}
+
+	hooks := q.resumeHooks
+	*q = quiescer{
+		cfg: q.cfg,
+	}
+
+	for _, hook := range hooks {
+		hook()
+	}
+
+	return nil
+}
@@ -0,0 +1,120 @@
+package htlcswitch
+
+import (
+	"testing"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/stretchr/testify/require"
+)
+
+// quiescerHarness is a quiescer along with the messages it sent and a
+// toggle for whether the channel has pending local updates.
+type quiescerHarness struct {
+	*quiescer
+
+	pendingUpdates bool
+	sent           []*lnwire.Stfu
+}
+
+func newQuiescerHarness(channelInitiator bool) *quiescerHarness {
+	h := &quiescerHarness{}
+	h.quiescer = newQuiescer(quiescerCfg{
+		channelInitiator: channelInitiator,
+		hasPendingLocalUpdates: func() bool {
+			return h.pendingUpdates
+		},
+		sendMsg: func(msg lnwire.Message) error {
+			h.sent = append(h.sent, msg.(*lnwire.Stfu))
+			return nil
+		},
+	})
+
+	return h
+}
+
+// TestQuiescerWaitsForPendingUpdates tests that stfu isn't sent until none of
+// our updates are pending, while no more updates may be sent in the meantime.
+func TestQuiescerWaitsForPendingUpdates(t *testing.T) {
+	t.Parallel()
+
+	h := newQuiescerHarness(true)
+	h.pendingUpdates = true
+
+	resp := make(chan bool, 1)
+	require.NoError(t, h.request(resp))
+	require.Empty(t, h.sent)
+	require.False(t, h.canSendUpdates())
+	require.True(t, h.canRecvUpdates())
+
+	// Once our updates are locked in, stfu is sent.
+	h.pendingUpdates = false
+	require.NoError(t, h.trySendStfu())
+	require.Len(t, h.sent, 1)
+	require.True(t, h.sent[0].Initiator)
+	require.False(t, h.isQuiescent())
+
+	// The channel is quiescent once the remote party replies.
+	reply := lnwire.NewStfu(lnwire.ChannelID{}, false)
+	require.NoError(t, h.recvStfu(reply))
+	require.True(t, h.isQuiescent())
+	require.False(t, h.canRecvUpdates())
+	require.True(t, <-resp)
+}
+
+// TestQuiescerInitiator tests which party is considered the initiator of
+// quiescence.
+func TestQuiescerInitiator(t *testing.T) {
+	t.Parallel()
+
+	// If we reply to the stfu of the remote party, it's the initiator.
+	h := newQuiescerHarness(true)
+	require.NoError(t, h.recvStfu(lnwire.NewStfu(lnwire.ChannelID{}, true)))
+	require.Len(t, h.sent, 1)
+	require.False(t, h.sent[0].Initiator)
+	require.True(t, h.isQuiescent())
+	require.False(t, h.isInitiator())
+
+	// If both parties requested quiescence at the same time, the funder
+	// of the channel is the initiator.
+	for _, channelInitiator := range []bool{true, false} {
+		h := newQuiescerHarness(channelInitiator)
+		resp := make(chan bool, 1)
+		require.NoError(t, h.request(resp))
+		require.True(t, h.sent[0].Initiator)
+
+		err := h.recvStfu(lnwire.NewStfu(lnwire.ChannelID{}, true))
+		require.NoError(t, err)
+		require.Equal(t, channelInitiator, <-resp)
+	}
+}
+
+// TestQuiescerResume tests that quiescence can only be resumed once the
+// channel is quiescent, and that the resume hooks are executed.
+func TestQuiescerResume(t *testing.T) {
+	t.Parallel()
+
+	h := newQuiescerHarness(true)
+	require.ErrorIs(t, h.resume(), ErrChannelNotQuiescent)
+
+	resp := make(chan bool, 1)
+	require.NoError(t, h.request(resp))
+	require.ErrorIs(t, h.resume(), ErrChannelNotQuiescent)
+
+	var resumed bool
+	h.onResume(func() {
+		resumed = true
+	})
+
+	reply := lnwire.NewStfu(lnwire.ChannelID{}, false)
+	require.NoError(t, h.recvStfu(reply))
+	require.NoError(t, h.resume())
+	require.True(t, resumed)
+	require.True(t, h.canSendUpdates())
+	require.True(t, h.canRecvUpdates())
+
+	// Receiving stfu twice without resuming in between is a protocol
+	// violation.
+	require.NoError(t, h.recvStfu(lnwire.NewStfu(lnwire.ChannelID{}, true)))
+	err := h.recvStfu(lnwire.NewStfu(lnwire.ChannelID{}, true))
+	require.IsType(t, &errStfuViolation{}, err)
+}
@@ -0,0 +1,409 @@
+package htlcswitch
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/lightningnetwork/lnd/clock"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// RateLimitPolicy determines what the switch does with a forwarded HTLC that
+// exceeds one of its configured rate limits.
+type RateLimitPolicy uint8
+
+const (
+	// RateLimitFail fails over-limit HTLCs back to the incoming link with
+	// a temporary channel failure.
+	RateLimitFail RateLimitPolicy = iota
+
+	// RateLimitQueue holds over-limit HTLCs in the mailbox of the outgoing
+	// link until the limits allow them to be forwarded. HTLCs that can't
+	// be released before the mailbox delivery timeout are failed back as
+	// with RateLimitFail.
+	RateLimitQueue
+)
+
+// String returns a human readable representation of the policy.
+func (p RateLimitPolicy) String() string {
+	switch p {
+	case RateLimitFail:
+		return "fail"
+
+	case RateLimitQueue:
+		return "queue"
+
+	default:
+		return "unknown"
+	}
+}
+
+// RateLimit is a pair of token bucket limits applied to forwarded HTLCs. A
+// zero value for either field disables that particular limit.
+type RateLimit struct {
+	// HtlcsPerSecond is the sustained number of HTLCs per second that may
+	// be forwarded. Bursts of up to one second worth of HTLCs are allowed.
+	HtlcsPerSecond uint32
+
+	// MsatPerMinute is the sustained amount per minute that may be
+	// forwarded. Bursts of up to one minute worth of value are allowed.
+	MsatPerMinute lnwire.MilliSatoshi
+}
+
+// enabled returns true if at least one of the limits is set.
+func (r RateLimit) enabled() bool {
+	return r.HtlcsPerSecond != 0 || r.MsatPerMinute != 0
+}
+
+// RateLimitConfig houses the rate limits the switch applies to forwarded
+// HTLCs. Locally initiated payments are never rate limited.
+type RateLimitConfig struct {
+	// Peer is the limit applied to the HTLCs forwarded from each incoming
+	// peer, across all of the channels we have with it.
+	Peer RateLimit
+
+	// Channel is the limit applied to the HTLCs forwarded over each
+	// outgoing channel.
+	Channel RateLimit
+
+	// Policy determines what happens to HTLCs that exceed a limit.
+	Policy RateLimitPolicy
+}
+
+// RateLimitCounters tracks the outcome of the rate limit checks of a single
+// peer or channel.
+type RateLimitCounters struct {
+	// Forwarded is the number of HTLCs that were within the limit.
+	Forwarded uint64
+
+	// Queued is the number of over-limit HTLCs that were held in the
+	// mailbox of the outgoing link before being forwarded.
+	Queued uint64
+
+	// Rejected is the number of over-limit HTLCs that were failed back.
+	Rejected uint64
+}
+
+// RateLimitStats is a snapshot of the rate limit counters of the switch.
+type RateLimitStats struct {
+	// Policy is the policy applied to over-limit HTLCs.
+	Policy RateLimitPolicy
+
+	// Peers holds the counters of each incoming peer, keyed by its
+	// compressed public key.
+	Peers map[[33]byte]RateLimitCounters
+
+	// Channels holds the counters of each outgoing channel.
+	Channels map[lnwire.ShortChannelID]RateLimitCounters
+}
+
+// tokenBucket is a token bucket that refills continuously at a fixed rate up
+// to its capacity. The token count is allowed to go negative, which is how
+// queued HTLCs reserve their share of future capacity.
+type tokenBucket struct {
+	// rate is the number of tokens added per second.
+	rate float64
+
+	// capacity is the maximum number of tokens the bucket can hold.
+	capacity float64
+
+	tokens float64
+	last   time.Time
+}
+
+// newTokenBucket creates a full token bucket that refills capacity tokens
+// every interval.
+func newTokenBucket(capacity float64, interval time.Duration,
+	now time.Time) *tokenBucket {
+
+	return &tokenBucket{
+		rate:     capacity / interval.Seconds(),
+		capacity: capacity,
+		tokens:   capacity,
+		last:     now,
+	}
+}
+
+// refill adds the tokens accrued since the last refill.
+func (b *tokenBucket) refill(now time.Time) {
+	if now.After(b.last) {
+		elapsed := now.Sub(b.last).Seconds()
+		b.tokens = math.Min(b.capacity, b.tokens+elapsed*b.rate)
+		b.last = now
+	}
+}
+
+// delay returns how long it will take until n tokens can be taken from the
+// bucket. False is returned if n exceeds the capacity of the bucket, as such a
+// request can never be satisfied.
+func (b *tokenBucket) delay(n float64) (time.Duration, bool) {
+	if n > b.capacity {
+		return 0, false
+	}
+
+	if b.tokens >= n {
+		return 0, true
+	}
+
+	missing := n - b.tokens
+
+	return time.Duration(missing / b.rate * float64(time.Second)), true
+}
+
+// take removes n tokens from the bucket.
+func (b *tokenBucket) take(n float64) {
+	b.tokens -= n
+}
+
+// full returns true if the bucket has been refilled to its capacity.
+func (b *tokenBucket) full(now time.Time) bool {
+	b.refill(now)
+
+	return b.tokens >= b.capacity
+}
+
+// rateLimiter enforces a RateLimit for a single peer or channel.
+type rateLimiter struct {
+	// htlcs is the bucket of the HTLC count limit, nil if disabled.
+	htlcs *tokenBucket
+
+	// msat is the bucket of the amount limit, nil if disabled.
+	msat *tokenBucket
+
+	counters RateLimitCounters
+}
+
+// newRateLimiter creates a rate limiter enforcing the given limit.
+func newRateLimiter(limit RateLimit, now time.Time) *rateLimiter {
+	var r rateLimiter
+	if limit.HtlcsPerSecond != 0 {
+		r.htlcs = newTokenBucket(
+			float64(limit.HtlcsPerSecond), time.Second, now,
+		)
+	}
+	if limit.MsatPerMinute != 0 {
+		r.msat = newTokenBucket(
+			float64(limit.MsatPerMinute), time.Minute, now,
+		)
+	}
+
+	return &r
+}
+
+// delay returns how long an HTLC of the given amount has to wait before it is
+// within the limit. False is returned if it will never be.
+func (r *rateLimiter) delay(now time.Time,
+	amt lnwire.MilliSatoshi) (time.Duration, bool) {
+
+	var maxDelay time.Duration
+	if r.htlcs != nil {
+		r.htlcs.refill(now)
+
+		delay, ok := r.htlcs.delay(1)
+		if !ok {
+			return 0, false
+		}
+		if delay > maxDelay {
+			maxDelay = delay
+		}
+	}
+
+	if r.msat != nil {
+		r.msat.refill(now)
+
+		delay, ok := r.msat.delay(float64(amt))
+		if !ok {
+			return 0, false
+		}
+		if delay > maxDelay {
+			maxDelay = delay
+		}
+	}
+
+	return maxDelay, true
+}
+
+// take charges an HTLC of the given amount against the limit.
+func (r *rateLimiter) take(amt lnwire.MilliSatoshi) {
+	if r.htlcs != nil {
+		r.htlcs.take(1)
+	}
+	if r.msat != nil {
+		r.msat.take(float64(amt))
+	}
+}
+
+// full returns true if all buckets of the limiter have been refilled, in which
+// case it behaves just like a newly created limiter.
+func (r *rateLimiter) full(now time.Time) bool {
+	if r.htlcs != nil && !r.htlcs.full(now) {
+		return false
+	}
+
+	return r.msat == nil || r.msat.full(now)
+}
+
+// countQueued records an HTLC that was queued. If this limiter wasn't the
+// reason for the queueing, it counts as forwarded.
+func (r *rateLimiter) countQueued(overLimit bool) {
+	if overLimit {
+		r.counters.Queued++
+	} else {
+		r.counters.Forwarded++
+	}
+}
+
+// htlcRateLimiter applies the per-peer and per-channel rate limits of the
+// switch to forwarded HTLCs.
+type htlcRateLimiter struct {
+	cfg   RateLimitConfig
+	clock clock.Clock
+
+	// maxDelay is the longest an HTLC may be queued for. Over-limit HTLCs
+	// that would have to wait longer are rejected.
+	maxDelay time.Duration
+
+	mu       sync.Mutex
+	peers    map[[33]byte]*rateLimiter
+	channels map[lnwire.ShortChannelID]*rateLimiter
+}
+
+// newHtlcRateLimiter creates a new rate limiter for forwarded HTLCs.
+func newHtlcRateLimiter(cfg RateLimitConfig, clock clock.Clock,
+	maxDelay time.Duration) *htlcRateLimiter {
+
+	return &htlcRateLimiter{
+		cfg:      cfg,
+		clock:    clock,
+		maxDelay: maxDelay,
+		peers:    make(map[[33]byte]*rateLimiter),
+		channels: make(map[lnwire.ShortChannelID]*rateLimiter),
+	}
+}
+
+// admit checks whether an HTLC arriving from the given peer and leaving over
+// the given channel is within the configured limits. If it is, the zero time
+// is returned. If it isn't but may be queued, the time at which it may be
+// released is returned. Otherwise false is returned and the HTLC should be
+// failed back.
+func (h *htlcRateLimiter) admit(peer [33]byte,
+	outgoingChan lnwire.ShortChannelID, incomingAmt,
+	outgoingAmt lnwire.MilliSatoshi) (time.Time, bool) {
+
+	if !h.cfg.Peer.enabled() && !h.cfg.Channel.enabled() {
+		return time.Time{}, true
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	now := h.clock.Now()
+
+	peerLimiter, ok := h.peers[peer]
+	if !ok {
+		peerLimiter = newRateLimiter(h.cfg.Peer, now)
+		h.peers[peer] = peerLimiter
+	}
+
+	chanLimiter, ok := h.channels[outgoingChan]
+	if !ok {
+		chanLimiter = newRateLimiter(h.cfg.Channel, now)
+		h.channels[outgoingChan] = chanLimiter
+	}
+
+	peerDelay, peerOk := peerLimiter.delay(now, incomingAmt)
+	chanDelay, chanOk := chanLimiter.delay(now, outgoingAmt)
+
+	delay := peerDelay
+	if chanDelay > delay {
+		delay = chanDelay
+	}
+
+	// Only the limiters that are actually over their limit account for
+	// the rejection or the queueing of the HTLC.
+	peerOver := !peerOk || peerDelay > 0
+	chanOver := !chanOk || chanDelay > 0
+
+	switch {
+	case !peerOver && !chanOver:
+		peerLimiter.counters.Forwarded++
+		chanLimiter.counters.Forwarded++
+
+	case h.cfg.Policy != RateLimitQueue || !peerOk || !chanOk ||
+		delay >= h.maxDelay:
+
+		if peerOver {
+			peerLimiter.counters.Rejected++
+		}
+		if chanOver {
+			chanLimiter.counters.Rejected++
+		}
+
+		return time.Time{}, false
+
+	default:
+		peerLimiter.countQueued(peerOver)
+		chanLimiter.countQueued(chanOver)
+	}
+
+	// Charge the HTLC against both limits. For queued HTLCs this reserves
+	// capacity ahead of time, pushing back any HTLCs that come after.
+	peerLimiter.take(incomingAmt)
+	chanLimiter.take(outgoingAmt)
+
+	if delay == 0 {
+		return time.Time{}, true
+	}
+
+	return now.Add(delay), true
+}
+
+// prune removes the limiters of the peers and channels that no longer have an
+// active link once they've been refilled. As a refilled limiter behaves like a
+// new one, a peer can't reset its limits by reconnecting. Only the counters of
+// the removed limiters are lost.
+func (h *htlcRateLimiter) prune(livePeers map[[33]byte]struct{},
+	liveChans map[lnwire.ShortChannelID]struct{}) {
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	now := h.clock.Now()
+
+	for peer, limiter := range h.peers {
+		if _, ok := livePeers[peer]; ok || !limiter.full(now) {
+			continue
+		}
+
+		delete(h.peers, peer)
+	}
+
+	for scid, limiter := range h.channels {
+		if _, ok := liveChans[scid]; ok || !limiter.full(now) {
+			continue
+		}
+
+		delete(h.channels, scid)
+	}
+}
+
+// stats returns a snapshot of the rate limit counters.
+func (h *htlcRateLimiter) stats() *RateLimitStats {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	stats := &RateLimitStats{
+		Policy:   h.cfg.Policy,
+		Peers:    make(map[[33]byte]RateLimitCounters, len(h.peers)),
+		Channels: make(map[lnwire.ShortChannelID]RateLimitCounters),
+	}
+	for peer, limiter := range h.peers {
+		stats.Peers[peer] = limiter.counters
+	}
+	for scid, limiter := range h.channels {
+		stats.Channels[scid] = limiter.counters
+	}
+
+	return stats
+}
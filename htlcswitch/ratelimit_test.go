@@ -0,0 +1,190 @@
+package htlcswitch
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/clock"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/stretchr/testify/require"
+)
+
+// TestHtlcRateLimiterFail asserts that HTLCs exceeding either the peer or the
+// channel limit are rejected under the fail policy, and that the limits
+// recover as time passes.
+func TestHtlcRateLimiterFail(t *testing.T) {
+	t.Parallel()
+
+	var (
+		startTime = time.Now()
+		testClock = clock.NewTestClock(startTime)
+		peerA     = [33]byte{1}
+		peerB     = [33]byte{2}
+		chan1     = lnwire.NewShortChanIDFromInt(1)
+		chan2     = lnwire.NewShortChanIDFromInt(2)
+	)
+
+	limiter := newHtlcRateLimiter(RateLimitConfig{
+		Peer: RateLimit{
+			HtlcsPerSecond: 2,
+		},
+		Channel: RateLimit{
+			MsatPerMinute: 6000,
+		},
+		Policy: RateLimitFail,
+	}, testClock, time.Minute)
+
+	// The first two HTLCs of peer A fit within its burst.
+	for i := 0; i < 2; i++ {
+		holdUntil, ok := limiter.admit(peerA, chan1, 1000, 1000)
+		require.True(t, ok)
+		require.True(t, holdUntil.IsZero())
+	}
+
+	// The third one exceeds the peer limit and is rejected.
+	_, ok := limiter.admit(peerA, chan2, 1000, 1000)
+	require.False(t, ok)
+
+	// Peer B isn't affected by the limit of peer A, but its HTLC exceeds
+	// the amount limit of the first channel.
+	_, ok = limiter.admit(peerB, chan1, 5000, 5000)
+	require.False(t, ok)
+
+	// An HTLC that exceeds the capacity of a bucket can never be
+	// forwarded.
+	_, ok = limiter.admit(peerB, chan2, 7000, 7000)
+	require.False(t, ok)
+
+	// After half a second, peer A has accrued another token.
+	testClock.SetTime(startTime.Add(500 * time.Millisecond))
+	_, ok = limiter.admit(peerA, chan2, 1000, 1000)
+	require.True(t, ok)
+
+	stats := limiter.stats()
+	require.Equal(t, RateLimitFail, stats.Policy)
+	require.Equal(t, RateLimitCounters{
+		Forwarded: 3,
+		Rejected:  1,
+	}, stats.Peers[peerA])
+	require.Equal(t, RateLimitCounters{}, stats.Peers[peerB])
+	require.Equal(t, RateLimitCounters{
+		Forwarded: 2,
+		Rejected:  1,
+	}, stats.Channels[chan1])
+	require.Equal(t, RateLimitCounters{
+		Forwarded: 1,
+		Rejected:  1,
+	}, stats.Channels[chan2])
+}
+
+// TestHtlcRateLimiterQueue asserts that over-limit HTLCs are queued with
+// increasing release times under the queue policy, and rejected once they
+// can't be released before the maximum delay.
+func TestHtlcRateLimiterQueue(t *testing.T) {
+	t.Parallel()
+
+	var (
+		startTime = time.Now()
+		testClock = clock.NewTestClock(startTime)
+		peer      = [33]byte{1}
+		scid      = lnwire.NewShortChanIDFromInt(1)
+	)
+
+	limiter := newHtlcRateLimiter(RateLimitConfig{
+		Channel: RateLimit{
+			HtlcsPerSecond: 1,
+		},
+		Policy: RateLimitQueue,
+	}, testClock, 3*time.Second)
+
+	// The first HTLC is forwarded right away.
+	holdUntil, ok := limiter.admit(peer, scid, 1000, 1000)
+	require.True(t, ok)
+	require.True(t, holdUntil.IsZero())
+
+	// The next two are queued, each reserving the next available token.
+	for i := 1; i <= 2; i++ {
+		holdUntil, ok = limiter.admit(peer, scid, 1000, 1000)
+		require.True(t, ok)
+		require.Equal(
+			t, startTime.Add(time.Duration(i)*time.Second),
+			holdUntil,
+		)
+	}
+
+	// The fourth would have to wait for the maximum delay, so it's
+	// rejected instead.
+	_, ok = limiter.admit(peer, scid, 1000, 1000)
+	require.False(t, ok)
+
+	stats := limiter.stats()
+	require.Equal(t, RateLimitQueue, stats.Policy)
+	require.Equal(t, RateLimitCounters{
+		Forwarded: 1,
+		Queued:    2,
+		Rejected:  1,
+	}, stats.Channels[scid])
+
+	// The peer has no limit of its own, so it counts all HTLCs that made
+	// it through as forwarded.
+	require.Equal(t, RateLimitCounters{
+		Forwarded: 3,
+	}, stats.Peers[peer])
+}
+
+// TestHtlcRateLimiterPrune asserts that the limiters of peers and channels
+// without a link are only removed once they've been refilled, and that the
+// limiters of live peers and channels are kept.
+func TestHtlcRateLimiterPrune(t *testing.T) {
+	t.Parallel()
+
+	var (
+		startTime = time.Now()
+		testClock = clock.NewTestClock(startTime)
+		peerA     = [33]byte{1}
+		peerB     = [33]byte{2}
+		chan1     = lnwire.NewShortChanIDFromInt(1)
+		chan2     = lnwire.NewShortChanIDFromInt(2)
+	)
+
+	limiter := newHtlcRateLimiter(RateLimitConfig{
+		Peer: RateLimit{
+			HtlcsPerSecond: 1,
+		},
+		Channel: RateLimit{
+			HtlcsPerSecond: 1,
+		},
+		Policy: RateLimitFail,
+	}, testClock, time.Minute)
+
+	_, ok := limiter.admit(peerA, chan1, 1000, 1000)
+	require.True(t, ok)
+	_, ok = limiter.admit(peerB, chan2, 1000, 1000)
+	require.True(t, ok)
+
+	// Only peer A and the first channel are still live. The limiters of
+	// peer B and the second channel aren't removed before they've been
+	// refilled, as the peer could otherwise reset its limit by
+	// reconnecting.
+	livePeers := map[[33]byte]struct{}{peerA: {}}
+	liveChans := map[lnwire.ShortChannelID]struct{}{chan1: {}}
+	limiter.prune(livePeers, liveChans)
+
+	stats := limiter.stats()
+	require.Len(t, stats.Peers, 2)
+	require.Len(t, stats.Channels, 2)
+
+	_, ok = limiter.admit(peerB, chan2, 1000, 1000)
+	require.False(t, ok)
+
+	// Once refilled, the limiters of peer B and the second channel are
+	// removed.
+	testClock.SetTime(startTime.Add(time.Second))
+	limiter.prune(livePeers, liveChans)
+
+	stats = limiter.stats()
+	require.Contains(t, stats.Peers, peerA)
+	require.NotContains(t, stats.Peers, peerB)
+	require.Contains(t, stats.Channels, chan1)
+	require.NotContains(t, stats.Channels, chan2)
+}
@@ -79,6 +79,11 @@ var (
 	// threshold.
 	errDustThresholdExceeded = errors.New("dust threshold exceeded")
 
+	// errFeeExposureExceeded is only surfaced to callers of SendHTLC and
+	// signals that sending the HTLC would exceed the outgoing link's max
+	// fee exposure.
+	errFeeExposureExceeded = errors.New("max fee exposure exceeded")
+
 	// DefaultDustThreshold is the default threshold after which we'll fail
 	// payments if they are dust. This is currently set to 500m msats.
 	DefaultDustThreshold = lnwire.MilliSatoshi(500_000_000)
@@ -116,6 +121,18 @@ type ChanClose struct {
 	// DeliveryScript is an optional delivery script to pay funds out to.
 	DeliveryScript lnwire.DeliveryAddress
 
+	// DrainTimeout, if non-zero, requests that the channel is drained
+	// before the cooperative closure starts. Instead of failing if HTLCs
+	// are still in flight, the channel stops accepting new HTLCs and the
+	// shutdown begins once the in-flight HTLCs are resolved. The close
+	// fails if this doesn't happen within the timeout.
+	DrainTimeout time.Duration
+
+	// DrainDeadline is the time by which the channel must have been
+	// drained. It's set by the peer once it starts draining the channel,
+	// such that the deadline is kept if draining has to be resumed.
+	DrainDeadline time.Time
+
 	// Updates is used by request creator to receive the notifications about
 	// execution of the close channel request.
 	Updates chan interface{}
@@ -222,6 +239,10 @@ type Config struct {
 
 	// IsAlias returns whether or not a given SCID is an alias.
 	IsAlias func(scid lnwire.ShortChannelID) bool
+
+	// RateLimits are the per-peer and per-channel rate limits applied to
+	// forwarded HTLCs.
+	RateLimits RateLimitConfig
 }
 
 // Switch is the central messaging bus for all incoming/outgoing HTLCs.
@@ -347,6 +368,9 @@ type Switch struct {
 	// key includes the value itself and also any other aliases. This MUST
 	// be accessed with the indexMtx.
 	baseIndex map[lnwire.ShortChannelID]lnwire.ShortChannelID
+
+	// rateLimiter enforces the rate limits of forwarded HTLCs.
+	rateLimiter *htlcRateLimiter
 }
 
 // New creates the new instance of htlc switch.
@@ -384,6 +408,10 @@ func New(cfg Config, currentHeight uint32) (*Switch, error) {
 	s.aliasToReal = make(map[lnwire.ShortChannelID]lnwire.ShortChannelID)
 	s.baseIndex = make(map[lnwire.ShortChannelID]lnwire.ShortChannelID)
 
+	s.rateLimiter = newHtlcRateLimiter(
+		cfg.RateLimits, s.cfg.Clock, s.cfg.MailboxDeliveryTimeout,
+	)
+
 	s.mailOrchestrator = newMailOrchestrator(&mailOrchConfig{
 		forwardPackets:    s.ForwardPackets,
 		clock:             s.cfg.Clock,
@@ -581,6 +609,28 @@ func (s *Switch) SendHTLC(firstHop lnwire.ShortChannelID, attemptID uint64,
 		return errDustThresholdExceeded
 	}
 
+	// Also evaluate whether this HTLC would push the fee exposure of the
+	// outgoing link over its limit.
+	if s.evaluateFeeExposure(link, htlc.Amount, false) {
+		link.recordFeeExposureRejection()
+
+		linkErr := NewLinkError(
+			&lnwire.FailTemporaryChannelFailure{},
+		)
+		s.cfg.HtlcNotifier.NotifyLinkFailEvent(
+			newHtlcKey(packet),
+			HtlcInfo{
+				OutgoingTimeLock: htlc.Expiry,
+				OutgoingAmt:      htlc.Amount,
+			},
+			HtlcEventTypeSend,
+			linkErr,
+			false,
+		)
+
+		return errFeeExposureExceeded
+	}
+
 	circuit := newPaymentCircuit(&htlc.PaymentHash, packet)
 	actions, err := s.circuits.CommitCircuits(circuit)
 	if err != nil {
@@ -1271,6 +1321,51 @@ func (s *Switch) handlePacketForward(packet *htlcPacket) error {
 			return s.failAddPacket(packet, linkErr)
 		}
 
+		// Finally, make sure the HTLC doesn't push the fee exposure of
+		// either link over its limit.
+		var exceededLink ChannelLink
+		switch {
+		case s.evaluateFeeExposure(
+			incomingLink, packet.incomingAmount, true,
+		):
+			exceededLink = incomingLink
+
+		case s.evaluateFeeExposure(destination, packet.amount, false):
+			exceededLink = destination
+		}
+		if exceededLink != nil {
+			exceededLink.recordFeeExposureRejection()
+
+			linkErr := NewLinkError(
+				&lnwire.FailTemporaryChannelFailure{},
+			)
+
+			return s.failAddPacket(packet, linkErr)
+		}
+
+		// Lastly, check the HTLC against the rate limits of the
+		// incoming peer and the outgoing channel. Depending on the
+		// configured policy, an over-limit HTLC is either failed back
+		// or queued in the mailbox of the destination link.
+		holdUntil, ok := s.rateLimiter.admit(
+			incomingLink.Peer().PubKey(), destination.ShortChanID(),
+			packet.incomingAmount, packet.amount,
+		)
+		if !ok {
+			log.Debugf("Rate limit exceeded for HTLC(%x) from "+
+				"IncomingChanID(%v) to OutgoingChanID(%v)",
+				htlc.PaymentHash[:], packet.incomingChanID,
+				destination.ShortChanID())
+
+			linkErr := NewDetailedLinkError(
+				&lnwire.FailTemporaryChannelFailure{},
+				OutgoingFailureRateLimited,
+			)
+
+			return s.failAddPacket(packet, linkErr)
+		}
+		packet.holdUntil = holdUntil
+
 		// Send the packet to the destination channel link which
 		// manages the channel.
 		packet.outgoingChanID = destination.ShortChanID()
@@ -1664,11 +1759,14 @@ func (s *Switch) teardownCircuit(pkt *htlcPacket) error {
 // directing the specified closure type. If the closure type is CloseRegular,
 // targetFeePerKw parameter should be the ideal fee-per-kw that will be used as
 // a starting point for close negotiation. The deliveryScript parameter is an
-// optional parameter which sets a user specified script to close out to.
+// optional parameter which sets a user specified script to close out to. A
+// non-zero drainTimeout requests that in-flight HTLCs are resolved before the
+// cooperative closure starts.
 func (s *Switch) CloseLink(chanPoint *wire.OutPoint,
 	closeType contractcourt.ChannelCloseType,
 	targetFeePerKw, maxFee chainfee.SatPerKWeight,
-	deliveryScript lnwire.DeliveryAddress) (chan interface{}, chan error) {
+	deliveryScript lnwire.DeliveryAddress,
+	drainTimeout time.Duration) (chan interface{}, chan error) {
 
 	// TODO(roasbeef) abstract out the close updates.
 	updateChan := make(chan interface{}, 2)
@@ -1681,6 +1779,7 @@ func (s *Switch) CloseLink(chanPoint *wire.OutPoint,
 		TargetFeePerKw: targetFeePerKw,
 		MaxFee:         maxFee,
 		DeliveryScript: deliveryScript,
+		DrainTimeout:   drainTimeout,
 		Err:            errChan,
 	}
 
@@ -1902,6 +2001,10 @@ out:
 				newSatRecv    btcutil.Amount
 			)
 
+			// The rate limiters of peers and channels that are
+			// gone are pruned as well.
+			s.pruneRateLimiters()
+
 			// Next, we'll run through all the registered links and
 			// compute their up-to-date forwarding stats.
 			s.indexMtx.RLock()
@@ -2717,6 +2820,31 @@ func (s *Switch) commitCircuits(circuits ...*PaymentCircuit) (
 	return s.circuits.CommitCircuits(circuits...)
 }
 
+// pruneRateLimiters removes the rate limiters of the peers and channels that
+// no longer have a link in the switch.
+func (s *Switch) pruneRateLimiters() {
+	s.indexMtx.RLock()
+	livePeers := make(map[[33]byte]struct{}, len(s.interfaceIndex))
+	for peer := range s.interfaceIndex {
+		livePeers[peer] = struct{}{}
+	}
+	liveChans := make(
+		map[lnwire.ShortChannelID]struct{}, len(s.forwardingIndex),
+	)
+	for scid := range s.forwardingIndex {
+		liveChans[scid] = struct{}{}
+	}
+	s.indexMtx.RUnlock()
+
+	s.rateLimiter.prune(livePeers, liveChans)
+}
+
+// RateLimitStats returns a snapshot of the rate limit counters of the peers
+// and channels forwarded through the switch.
+func (s *Switch) RateLimitStats() *RateLimitStats {
+	return s.rateLimiter.stats()
+}
+
 // FlushForwardingEvents flushes out the set of pending forwarding events to
 // the persistent log. This will be used by the switch to periodically flush
 // out the set of forwarding events to disk. External callers can also use this
@@ -2821,6 +2949,24 @@ func (s *Switch) evaluateDustThreshold(link ChannelLink,
 	return false
 }
 
+// evaluateFeeExposure determines whether adding an HTLC of the given amount to
+// the link would push the fee exposure of its channel over the link's limit.
+// Like evaluateDustThreshold, this accounts for the dust still held by the
+// link's mailbox, and if incoming is true the amount isn't added as it's
+// already part of the commitment.
+func (s *Switch) evaluateFeeExposure(link ChannelLink,
+	amount lnwire.MilliSatoshi, incoming bool) bool {
+
+	mailbox := s.mailOrchestrator.GetOrCreateMailBox(
+		link.ChanID(), link.ShortChanID(),
+	)
+	localMailDust, remoteMailDust := mailbox.DustPackets()
+
+	return link.exceedsFeeExposure(
+		amount, incoming, localMailDust, remoteMailDust,
+	)
+}
+
 // failMailboxUpdate is passed to the mailbox orchestrator which in turn passes
 // it to individual mailboxes. It allows the mailboxes to construct a
 // FailureMessage when failing back HTLC's due to expiry and may include an
@@ -16,12 +16,14 @@ import (
 	"github.com/lightningnetwork/lnd/chainntnfs"
 	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/channeldb/models"
+	"github.com/lightningnetwork/lnd/clock"
 	"github.com/lightningnetwork/lnd/contractcourt"
 	"github.com/lightningnetwork/lnd/htlcswitch/hodl"
 	"github.com/lightningnetwork/lnd/htlcswitch/hop"
 	"github.com/lightningnetwork/lnd/lntest/mock"
 	"github.com/lightningnetwork/lnd/lntypes"
 	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/record"
 	"github.com/lightningnetwork/lnd/ticker"
 	"github.com/stretchr/testify/require"
 )
@@ -980,6 +982,102 @@ func TestSwitchForward(t *testing.T) {
 	}
 }
 
+// TestSwitchForwardRateLimit asserts that the switch fails back forwarded
+// HTLCs that exceed the rate limit of their incoming peer.
+func TestSwitchForwardRateLimit(t *testing.T) {
+	t.Parallel()
+
+	alicePeer, err := newMockServer(
+		t, "alice", testStartingHeight, nil, testDefaultDelta,
+	)
+	require.NoError(t, err)
+	bobPeer, err := newMockServer(
+		t, "bob", testStartingHeight, nil, testDefaultDelta,
+	)
+	require.NoError(t, err)
+
+	s, err := initSwitchWithTempDB(t, testStartingHeight)
+	require.NoError(t, err)
+
+	// Only allow a single HTLC per second from each peer. We use a test
+	// clock so that no tokens are accrued while the test runs.
+	s.rateLimiter = newHtlcRateLimiter(RateLimitConfig{
+		Peer: RateLimit{
+			HtlcsPerSecond: 1,
+		},
+	}, clock.NewTestClock(time.Now()), time.Minute)
+
+	require.NoError(t, s.Start())
+	defer func() {
+		require.NoError(t, s.Stop())
+	}()
+
+	chanID1, chanID2, aliceChanID, bobChanID := genIDs()
+
+	aliceChannelLink := newMockChannelLink(
+		s, chanID1, aliceChanID, emptyScid, alicePeer, true, false,
+		false, false,
+	)
+	bobChannelLink := newMockChannelLink(
+		s, chanID2, bobChanID, emptyScid, bobPeer, true, false, false,
+		false,
+	)
+	require.NoError(t, s.AddLink(aliceChannelLink))
+	require.NoError(t, s.AddLink(bobChannelLink))
+
+	newPacket := func(htlcID uint64) *htlcPacket {
+		return &htlcPacket{
+			incomingChanID: aliceChannelLink.ShortChanID(),
+			incomingHTLCID: htlcID,
+			outgoingChanID: bobChannelLink.ShortChanID(),
+			obfuscator:     NewMockObfuscator(),
+			htlc: &lnwire.UpdateAddHTLC{
+				PaymentHash: [32]byte{byte(htlcID)},
+				Amount:      1,
+			},
+		}
+	}
+
+	// The first HTLC is within the limit and reaches bob.
+	require.NoError(t, s.ForwardPackets(nil, newPacket(0)))
+
+	select {
+	case <-bobChannelLink.packets:
+	case <-time.After(time.Second):
+		t.Fatal("request was not propagated to destination")
+	}
+
+	// The second one exceeds the limit of alice and is failed back.
+	require.NoError(t, s.ForwardPackets(nil, newPacket(1)))
+
+	select {
+	case pkt := <-aliceChannelLink.packets:
+		failHtlc, ok := pkt.htlc.(*lnwire.UpdateFailHTLC)
+		require.True(t, ok)
+
+		fwdErr, err := newMockDeobfuscator().DecryptError(
+			failHtlc.Reason,
+		)
+		require.NoError(t, err)
+		require.IsType(
+			t, &lnwire.FailTemporaryChannelFailure{},
+			fwdErr.WireMessage(),
+		)
+
+	case <-time.After(time.Second):
+		t.Fatal("failure was not propagated to source")
+	}
+
+	stats := s.RateLimitStats()
+	require.Equal(t, RateLimitCounters{
+		Forwarded: 1,
+		Rejected:  1,
+	}, stats.Peers[alicePeer.PubKey()])
+	require.Equal(t, RateLimitCounters{
+		Forwarded: 1,
+	}, stats.Channels[bobChannelLink.ShortChanID()])
+}
+
 func TestSwitchForwardFailAfterFullAdd(t *testing.T) {
 	t.Parallel()
 
@@ -4255,6 +4353,234 @@ func TestInterceptableSwitchWatchDog(t *testing.T) {
 	}))
 }
 
+// TestInterceptableSwitchChain tests that multiple interceptors are consulted
+// in priority order, that filters are respected and that an htlc can be
+// resumed with modifications.
+func TestInterceptableSwitchChain(t *testing.T) {
+	t.Parallel()
+
+	c := newInterceptableSwitchTestContext(t)
+	defer c.finish()
+
+	notifier := &mock.ChainNotifier{
+		EpochChan: make(chan *chainntnfs.BlockEpoch, 1),
+	}
+	notifier.EpochChan <- &chainntnfs.BlockEpoch{Height: testStartingHeight}
+
+	switchForwardInterceptor, err := NewInterceptableSwitch(
+		&InterceptableSwitchConfig{
+			Switch:             c.s,
+			CltvRejectDelta:    c.cltvRejectDelta,
+			CltvInterceptDelta: c.cltvInterceptDelta,
+			Notifier:           notifier,
+		},
+	)
+	require.NoError(t, err)
+	require.NoError(t, switchForwardInterceptor.Start())
+	defer func() {
+		require.NoError(t, switchForwardInterceptor.Stop())
+	}()
+
+	// The interceptors are buffered because the next interceptor in the
+	// chain is invoked synchronously when an htlc is resolved.
+	newInterceptor := func() *mockForwardInterceptor {
+		return &mockForwardInterceptor{
+			t:               t,
+			interceptedChan: make(chan InterceptedPacket, 1),
+		}
+	}
+
+	const customRecordType = record.CustomTypeStart + 1
+
+	// Register a low priority interceptor for all htlcs and a high
+	// priority interceptor that is only interested in htlcs carrying a
+	// custom record.
+	low, high := newInterceptor(), newInterceptor()
+	lowID, err := switchForwardInterceptor.RegisterInterceptor(
+		&InterceptorRegistration{
+			Interceptor: low.InterceptForwardHtlc,
+			Priority:    1,
+		},
+	)
+	require.NoError(t, err)
+
+	highID, err := switchForwardInterceptor.RegisterInterceptor(
+		&InterceptorRegistration{
+			Interceptor: high.InterceptForwardHtlc,
+			Priority:    2,
+			Filter: InterceptorFilter{
+				CustomRecords: []uint64{customRecordType},
+			},
+		},
+	)
+	require.NoError(t, err)
+
+	// An inconsistent filter is rejected.
+	_, err = switchForwardInterceptor.RegisterInterceptor(
+		&InterceptorRegistration{
+			Interceptor: high.InterceptForwardHtlc,
+			Filter: InterceptorFilter{
+				MinIncomingAmount: 2,
+				MaxIncomingAmount: 1,
+			},
+		},
+	)
+	require.Error(t, err)
+
+	linkQuit := make(chan struct{})
+
+	createPacket := func() *htlcPacket {
+		packet := c.createTestPacket()
+		packet.incomingAmount = 1000
+		packet.amount = 900
+		packet.htlc.(*lnwire.UpdateAddHTLC).Amount = 900
+		packet.customRecords = record.CustomSet{
+			customRecordType: {1},
+		}
+		packet.inWireCustomRecords = record.CustomSet{
+			customRecordType: {4},
+		}
+
+		return packet
+	}
+
+	// A packet with the custom record is offered to the high priority
+	// interceptor first.
+	require.NoError(t, switchForwardInterceptor.ForwardPackets(
+		linkQuit, false, createPacket(),
+	))
+	intercepted := high.getIntercepted()
+
+	// The custom records of the incoming update_add_htlc are exposed to
+	// the interceptor.
+	require.Equal(t, record.CustomSet{customRecordType: {4}},
+		intercepted.InWireCustomRecords)
+
+	// The low priority interceptor can't resolve an htlc that it doesn't
+	// hold.
+	require.ErrorIs(t, switchForwardInterceptor.Resolve(&FwdResolution{
+		Key:         intercepted.IncomingCircuit,
+		Interceptor: lowID,
+		Action:      FwdActionResume,
+	}), ErrFwdNotExists)
+
+	// Modifications that exceed the incoming amount are rejected, the
+	// htlc remains held.
+	tooMuch := lnwire.MilliSatoshi(1001)
+	require.Error(t, switchForwardInterceptor.Resolve(&FwdResolution{
+		Key:         intercepted.IncomingCircuit,
+		Interceptor: highID,
+		Action:      FwdActionResumeModified,
+		Modifications: &FwdModifications{
+			OutgoingAmount: &tooMuch,
+		},
+	}))
+
+	// Resume the htlc with a lower amount and a custom record on the
+	// outgoing update_add_htlc.
+	outAmt := lnwire.MilliSatoshi(800)
+	wireRecords := record.CustomSet{customRecordType: {2, 3}}
+	require.NoError(t, switchForwardInterceptor.Resolve(&FwdResolution{
+		Key:         intercepted.IncomingCircuit,
+		Interceptor: highID,
+		Action:      FwdActionResumeModified,
+		Modifications: &FwdModifications{
+			OutgoingAmount:            &outAmt,
+			OutgoingWireCustomRecords: wireRecords,
+		},
+	}))
+
+	// The low priority interceptor is consulted next and sees the
+	// modified htlc.
+	intercepted = low.getIntercepted()
+	require.Equal(t, outAmt, intercepted.OutgoingAmount)
+	assertOutgoingLinkReceive(t, c.bobChannelLink, false)
+
+	require.NoError(t, switchForwardInterceptor.Resolve(&FwdResolution{
+		Key:         intercepted.IncomingCircuit,
+		Interceptor: lowID,
+		Action:      FwdActionResume,
+	}))
+
+	// The htlc reaches the outgoing link with the modifications applied.
+	receivedPkt := assertOutgoingLinkReceive(t, c.bobChannelLink, true)
+	receivedHtlc := receivedPkt.htlc.(*lnwire.UpdateAddHTLC)
+	require.Equal(t, outAmt, receivedHtlc.Amount)
+	require.Equal(t, outAmt, receivedPkt.amount)
+
+	expectedExtraData, err := record.EncodeWireCustomRecords(wireRecords)
+	require.NoError(t, err)
+	require.Equal(t, expectedExtraData, receivedHtlc.ExtraData)
+
+	require.NoError(t, switchForwardInterceptor.ForwardPackets(
+		linkQuit, false,
+		c.createSettlePacket(receivedPkt.outgoingHTLCID),
+	))
+	assertOutgoingLinkReceive(t, c.aliceChannelLink, true)
+	assertNumCircuits(t, c.s, 0, 0)
+
+	// A packet without the custom record skips the high priority
+	// interceptor.
+	packet := createPacket()
+	packet.customRecords = nil
+	require.NoError(t, switchForwardInterceptor.ForwardPackets(
+		linkQuit, false, packet,
+	))
+	intercepted = low.getIntercepted()
+
+	// Settle the htlc.
+	require.NoError(t, switchForwardInterceptor.Resolve(&FwdResolution{
+		Key:         intercepted.IncomingCircuit,
+		Interceptor: lowID,
+		Action:      FwdActionSettle,
+		Preimage:    c.preimage,
+	}))
+	assertOutgoingLinkReceive(t, c.bobChannelLink, false)
+	assertOutgoingLinkReceive(t, c.aliceChannelLink, true)
+
+	// When the high priority interceptor disconnects while holding an
+	// htlc, the htlc is offered to the next interceptor.
+	require.NoError(t, switchForwardInterceptor.ForwardPackets(
+		linkQuit, false, createPacket(),
+	))
+	intercepted = high.getIntercepted()
+
+	require.NoError(t, switchForwardInterceptor.UnregisterInterceptor(
+		highID,
+	))
+	require.Equal(t, intercepted, low.getIntercepted())
+
+	// The disconnected interceptor can no longer resolve the htlc.
+	require.ErrorIs(t, switchForwardInterceptor.Resolve(&FwdResolution{
+		Key:         intercepted.IncomingCircuit,
+		Interceptor: highID,
+		Action:      FwdActionResume,
+	}), ErrFwdNotExists)
+
+	// Resolve a batch that fails the held htlc and contains an unknown
+	// htlc. The known htlc is still resolved.
+	err = switchForwardInterceptor.ResolveBatch([]*FwdResolution{
+		{
+			Key:         intercepted.IncomingCircuit,
+			Interceptor: lowID,
+			Action:      FwdActionFail,
+			FailureCode: lnwire.CodeTemporaryChannelFailure,
+		},
+		{
+			Key:    models.CircuitKey{HtlcID: 1000},
+			Action: FwdActionResume,
+		},
+	})
+	require.ErrorIs(t, err, ErrFwdNotExists)
+	assertOutgoingLinkReceive(t, c.bobChannelLink, false)
+	assertOutgoingLinkReceive(t, c.aliceChannelLink, true)
+	assertNumCircuits(t, c.s, 0, 0)
+
+	require.ErrorIs(t, switchForwardInterceptor.UnregisterInterceptor(
+		highID,
+	), ErrInterceptorNotFound)
+}
+
 // TestSwitchDustForwarding tests that the switch properly fails HTLC's which
 // have incoming or outgoing links that breach their dust thresholds.
 func TestSwitchDustForwarding(t *testing.T) {
@@ -152,6 +152,28 @@ func GenFundingPkScript(aPub, bPub []byte, amt int64) ([]byte, *wire.TxOut, erro
 	return witnessScript, wire.NewTxOut(amt, pkScript), nil
 }
 
+// GenTaprootFundingScript creates the P2TR script of a funding output whose
+// internal key is the MuSig2 aggregate of the two passed keys. If a tapscript
+// merkle root is passed, the output key commits to it. Otherwise a BIP-0086
+// tweak is applied, so the output can only be spent through the key path.
+func GenTaprootFundingScript(aPub, bPub *btcec.PublicKey,
+	merkleRoot []byte) ([]byte, error) {
+
+	tweaks := &MuSig2Tweaks{
+		TaprootBIP0086Tweak: len(merkleRoot) == 0,
+		TaprootTweak:        merkleRoot,
+	}
+	combinedKey, err := MuSig2CombineKeys(
+		MuSig2Version100RC2, []*btcec.PublicKey{aPub, bPub}, true,
+		tweaks,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to combine keys: %w", err)
+	}
+
+	return txscript.PayToTaprootScript(combinedKey.FinalKey)
+}
+
 // SpendMultiSig generates the witness stack required to redeem the 2-of-2 p2wsh
 // multi-sig output.
 func SpendMultiSig(witnessScript, pubA []byte, sigA Signature,
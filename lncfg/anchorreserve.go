@@ -0,0 +1,68 @@
+package lncfg
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	// DefaultAnchorReserveConfTarget is the default confirmation target
+	// used to estimate the fee rate anchor channels need to be bumped to.
+	DefaultAnchorReserveConfTarget = 6
+
+	// DefaultAnchorReserveInterval is the default interval at which the
+	// anchor reserve is re-planned.
+	DefaultAnchorReserveInterval = 10 * time.Minute
+
+	// DefaultAnchorReserveLeaseDuration is the default duration of the
+	// leases of outputs reserved for anchor fee bumping.
+	DefaultAnchorReserveLeaseDuration = time.Hour
+)
+
+// AnchorReserve holds the configuration of the planner that models the
+// wallet reserve needed to fee bump all anchor channels if they were force
+// closed at once.
+//
+//nolint:lll
+type AnchorReserve struct {
+	ConfTarget uint32 `long:"conftarget" description:"The confirmation target used to estimate the fee rate all anchor channels would need to be bumped to if they were force closed at once."`
+
+	Interval time.Duration `long:"interval" description:"The interval at which the required reserve is re-planned and compared against the wallet balance."`
+
+	AutoLock bool `long:"autolock" description:"Lock the wallet outputs that are assigned to anchor channels, so they aren't spent by other wallet operations. The outputs are released as soon as a channel is being closed."`
+
+	LeaseDuration time.Duration `long:"leaseduration" description:"The duration of the locks of reserved outputs. Locks are extended on every planning run, so this must be longer than the interval."`
+}
+
+// DefaultAnchorReserve returns the default anchor reserve configuration.
+func DefaultAnchorReserve() *AnchorReserve {
+	return &AnchorReserve{
+		ConfTarget:    DefaultAnchorReserveConfTarget,
+		Interval:      DefaultAnchorReserveInterval,
+		LeaseDuration: DefaultAnchorReserveLeaseDuration,
+	}
+}
+
+// Validate checks the values configured for the anchor reserve planner.
+//
+// NOTE: Part of the Validator interface.
+func (a *AnchorReserve) Validate() error {
+	if a.ConfTarget < 1 {
+		return errors.New("anchorreserve.conftarget must be at least 1")
+	}
+
+	if a.Interval <= 0 {
+		return errors.New("anchorreserve.interval must be positive")
+	}
+
+	if a.LeaseDuration <= a.Interval {
+		return errors.New("anchorreserve.leaseduration must be " +
+			"longer than anchorreserve.interval")
+	}
+
+	return nil
+}
+
+// Compile-time constraint to ensure AnchorReserve implements the Validator
+// interface.
+var _ Validator = (*AnchorReserve)(nil)
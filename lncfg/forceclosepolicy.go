@@ -0,0 +1,125 @@
+package lncfg
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/lightningnetwork/lnd/contractcourt"
+	"github.com/lightningnetwork/lnd/routing/route"
+)
+
+// ForceClosePolicy holds the rules the channel arbitrator consults to decide
+// whether an expiring HTLC justifies force closing a channel.
+//
+//nolint:lll
+type ForceClosePolicy struct {
+	MinHtlcValue int64 `long:"minhtlcvalue" description:"The value in satoshis below which an expiring HTLC is never a reason to force close a channel. Such HTLCs are still resolved on-chain if the channel is force closed for another reason."`
+
+	SkipUneconomical bool `long:"skipuneconomical" description:"Don't force close a channel over an expiring HTLC that is worth less than the estimated fee of claiming it on-chain."`
+
+	ConfTarget uint32 `long:"conftarget" description:"The confirmation target used to estimate the on-chain cost of claiming an HTLC."`
+
+	PeerPoliciesRaw []string `long:"peerpolicy" description:"A per-peer override of the broadcast deltas and minimum HTLC value in the format <pubkey>:<incoming delta>:<outgoing delta>:<min htlc value>. A delta of zero uses the default one. The flag can be specified multiple times to add multiple peers."`
+
+	// PeerPolicies holds the parsed per-peer policies.
+	PeerPolicies map[route.Vertex]contractcourt.ForceClosePolicy
+}
+
+// DefaultForceClosePolicy returns the default force close policy
+// configuration.
+func DefaultForceClosePolicy() *ForceClosePolicy {
+	return &ForceClosePolicy{
+		ConfTarget: contractcourt.DefaultForceClosePolicyConfTarget,
+	}
+}
+
+// Policy returns the default force close policy described by the config.
+func (f *ForceClosePolicy) Policy() contractcourt.ForceClosePolicy {
+	return contractcourt.ForceClosePolicy{
+		IncomingBroadcastDelta: DefaultIncomingBroadcastDelta,
+		OutgoingBroadcastDelta: DefaultOutgoingBroadcastDelta,
+		MinHtlcValue:           btcutil.Amount(f.MinHtlcValue),
+		SkipUneconomical:       f.SkipUneconomical,
+		ConfTarget:             f.ConfTarget,
+	}
+}
+
+// Validate checks the force close policy values and parses the per-peer
+// policies.
+//
+// NOTE: Part of the Validator interface.
+func (f *ForceClosePolicy) Validate() error {
+	if f.MinHtlcValue < 0 {
+		return errors.New("forceclosepolicy.minhtlcvalue must not be " +
+			"negative")
+	}
+
+	if f.ConfTarget < 1 {
+		return errors.New("forceclosepolicy.conftarget must be at " +
+			"least 1")
+	}
+
+	f.PeerPolicies = make(map[route.Vertex]contractcourt.ForceClosePolicy)
+	for _, raw := range f.PeerPoliciesRaw {
+		peer, policy, err := f.parsePeerPolicy(raw)
+		if err != nil {
+			return fmt.Errorf("invalid forceclosepolicy.peerpolicy "+
+				"%q: %w", raw, err)
+		}
+
+		f.PeerPolicies[peer] = *policy
+	}
+
+	return nil
+}
+
+// parsePeerPolicy parses a single per-peer policy. The fields that can't be
+// set per peer are inherited from the global policy.
+func (f *ForceClosePolicy) parsePeerPolicy(raw string) (route.Vertex,
+	*contractcourt.ForceClosePolicy, error) {
+
+	parts := strings.Split(raw, ":")
+	if len(parts) != 4 {
+		return route.Vertex{}, nil, errors.New("expected format " +
+			"<pubkey>:<incoming delta>:<outgoing delta>:<min " +
+			"htlc value>")
+	}
+
+	peer, err := route.NewVertexFromStr(parts[0])
+	if err != nil {
+		return route.Vertex{}, nil, err
+	}
+
+	incomingDelta, err := strconv.ParseUint(parts[1], 10, 32)
+	if err != nil {
+		return route.Vertex{}, nil, fmt.Errorf("incoming delta: %w",
+			err)
+	}
+
+	outgoingDelta, err := strconv.ParseUint(parts[2], 10, 32)
+	if err != nil {
+		return route.Vertex{}, nil, fmt.Errorf("outgoing delta: %w",
+			err)
+	}
+
+	minValue, err := strconv.ParseUint(parts[3], 10, 63)
+	if err != nil {
+		return route.Vertex{}, nil, fmt.Errorf("min htlc value: %w",
+			err)
+	}
+
+	return peer, &contractcourt.ForceClosePolicy{
+		IncomingBroadcastDelta: uint32(incomingDelta),
+		OutgoingBroadcastDelta: uint32(outgoingDelta),
+		MinHtlcValue:           btcutil.Amount(minValue),
+		SkipUneconomical:       f.SkipUneconomical,
+		ConfTarget:             f.ConfTarget,
+	}, nil
+}
+
+// Compile-time constraint to ensure ForceClosePolicy implements the Validator
+// interface.
+var _ Validator = (*ForceClosePolicy)(nil)
@@ -18,6 +18,24 @@ type Gossip struct {
 	ChannelUpdateInterval time.Duration `long:"channel-update-interval" description:"The interval used to determine how often lnd should allow a burst of new updates for a specific channel and direction."`
 
 	SubBatchDelay time.Duration `long:"sub-batch-delay" description:"The duration to wait before sending the next announcement batch if there are multiple. Use a small value if there are a lot announcements and they need to be broadcast quickly."`
+
+	PeerOutboundBudget uint64 `long:"peer-outbound-budget" description:"The maximum number of bytes per second of gossip that lnd will send to a single peer. Replies to gossip queries exceeding the budget are delayed, while relayed announcements are dropped. Set to 0 to disable the limit."`
+
+	PeerInboundBudget uint64 `long:"peer-inbound-budget" description:"The maximum number of bytes per second of unsolicited announcements that lnd will accept from a single peer. Announcements exceeding the budget are dropped, replies to our own gossip queries are not limited. Set to 0 to disable the limit."`
+
+	GlobalOutboundBudget uint64 `long:"global-outbound-budget" description:"The maximum number of bytes per second of gossip that lnd will send to all peers combined. Replies to gossip queries exceeding the budget are delayed, while relayed announcements are dropped. Set to 0 to disable the limit."`
+
+	GlobalInboundBudget uint64 `long:"global-inbound-budget" description:"The maximum number of bytes per second of unsolicited announcements that lnd will accept from all peers combined. Announcements exceeding the budget are dropped, replies to our own gossip queries are not limited. Set to 0 to disable the limit."`
+}
+
+// Budget returns the gossip bandwidth budgets set in the config.
+func (g *Gossip) Budget() discovery.GossipBudgetConfig {
+	return discovery.GossipBudgetConfig{
+		PeerOutbound:   g.PeerOutboundBudget,
+		PeerInbound:    g.PeerInboundBudget,
+		GlobalOutbound: g.GlobalOutboundBudget,
+		GlobalInbound:  g.GlobalInboundBudget,
+	}
 }
 
 // Parse the pubkeys for the pinned syncers.
@@ -2,7 +2,12 @@ package lncfg
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/lightningnetwork/lnd/routing/route"
 )
 
 var (
@@ -16,6 +21,33 @@ var (
 //nolint:lll
 type Htlcswitch struct {
 	MailboxDeliveryTimeout time.Duration `long:"mailboxdeliverytimeout" description:"The timeout value when delivering HTLCs to a channel link. Setting this value too small will result in local payment failures if large number of payments are sent over a short period."`
+
+	MaxFeeExposure uint64 `long:"maxfeeexposure" description:"The maximum fee exposure in satoshis of either commitment of a channel. The fee exposure is the commitment fee along with the value of the anchors and the HTLCs trimmed to dust, which all go to miners if the commitment confirms. HTLC adds that would exceed it at the current or next feerate are rejected, and so are our own fee updates. A fee update of the remote channel initiator that exceeds it fails the channel, which may lead to a force close. Disabled if set to 0, which is the default."`
+
+	PeerMaxFeeExposureRaw []string `long:"peermaxfeeexposure" description:"A per-peer override of the maximum fee exposure in the format <pubkey>:<satoshis>. The flag can be specified multiple times to add multiple peers."`
+
+	// PeerMaxFeeExposure holds the parsed per-peer maximum fee exposures.
+	PeerMaxFeeExposure map[route.Vertex]btcutil.Amount
+
+	PeerHtlcsPerSec uint32 `long:"peerhtlcspersec" description:"The maximum sustained number of HTLCs per second that are forwarded from a single incoming peer, with bursts of up to one second worth of HTLCs. Set to 0 to disable the limit."`
+
+	PeerMsatPerMin uint64 `long:"peermsatpermin" description:"The maximum sustained amount in millisatoshis per minute that is forwarded from a single incoming peer, with bursts of up to one minute worth of value. Set to 0 to disable the limit."`
+
+	ChanHtlcsPerSec uint32 `long:"chanhtlcspersec" description:"The maximum sustained number of HTLCs per second that are forwarded over a single outgoing channel, with bursts of up to one second worth of HTLCs. Set to 0 to disable the limit."`
+
+	ChanMsatPerMin uint64 `long:"chanmsatpermin" description:"The maximum sustained amount in millisatoshis per minute that is forwarded over a single outgoing channel, with bursts of up to one minute worth of value. Set to 0 to disable the limit."`
+
+	RateLimitQueue bool `long:"ratelimitqueue" description:"Queue forwarded HTLCs that exceed a rate limit in the mailbox of the outgoing channel until they are within the limit, instead of failing them back right away. HTLCs that can't be forwarded within the mailbox delivery timeout are still failed back."`
+}
+
+// MaxFeeExposureFor returns the maximum fee exposure of the channels with the
+// given peer.
+func (h *Htlcswitch) MaxFeeExposureFor(peer route.Vertex) btcutil.Amount {
+	if maxExposure, ok := h.PeerMaxFeeExposure[peer]; ok {
+		return maxExposure
+	}
+
+	return btcutil.Amount(h.MaxFeeExposure)
 }
 
 // Validate checks the values configured for htlcswitch.
@@ -30,5 +62,28 @@ func (h *Htlcswitch) Validate() error {
 			MaxMailboxDeliveryTimeout)
 	}
 
+	h.PeerMaxFeeExposure = make(map[route.Vertex]btcutil.Amount)
+	for _, raw := range h.PeerMaxFeeExposureRaw {
+		parts := strings.Split(raw, ":")
+		if len(parts) != 2 {
+			return fmt.Errorf("invalid peermaxfeeexposure %q: "+
+				"expected format <pubkey>:<satoshis>", raw)
+		}
+
+		peer, err := route.NewVertexFromStr(parts[0])
+		if err != nil {
+			return fmt.Errorf("invalid peermaxfeeexposure %q: %w",
+				raw, err)
+		}
+
+		maxExposure, err := strconv.ParseUint(parts[1], 10, 63)
+		if err != nil {
+			return fmt.Errorf("invalid peermaxfeeexposure %q: %w",
+				raw, err)
+		}
+
+		h.PeerMaxFeeExposure[peer] = btcutil.Amount(maxExposure)
+	}
+
 	return nil
 }
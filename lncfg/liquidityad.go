@@ -0,0 +1,87 @@
+package lncfg
+
+import (
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+const (
+	// DefaultLeaseDuration is the default number of blocks the funds of a
+	// lease sold through our liquidity ad are locked for, which is about
+	// four weeks.
+	DefaultLeaseDuration = 4032
+
+	// DefaultMaxUnpaidLeases is the default maximum number of leases sold
+	// through our liquidity ad whose fee isn't paid yet.
+	DefaultMaxUnpaidLeases = 20
+
+	// DefaultMaxUnpaidLeasesPerPeer is the default maximum number of
+	// leases sold to a single peer whose fee isn't paid yet.
+	DefaultMaxUnpaidLeasesPerPeer = 2
+)
+
+// LiquidityAd holds the configuration of the liquidity ad we advertise in our
+// node announcement to sell channel leases.
+//
+//nolint:lll
+type LiquidityAd struct {
+	LeaseDuration uint32 `long:"leaseduration" description:"The number of blocks the funds of a sold lease are locked in the lease channel for."`
+
+	FeeBase int64 `long:"feebase" description:"The fixed fee in satoshis charged for a lease."`
+
+	FeeRate uint32 `long:"feerate" description:"The fee charged proportionally to the leased amount, in parts per million."`
+
+	MinLeaseAmt int64 `long:"minleaseamt" description:"The minimum amount in satoshis that can be leased."`
+
+	MaxLeaseAmt int64 `long:"maxleaseamt" description:"The maximum amount in satoshis that can be leased. Set to a non-zero value to advertise the liquidity ad, which sells leases of the on-chain funds of the wallet to any node paying the lease fee. The ad can also be updated at runtime with the UpdateLiquidityAd RPC."`
+
+	MaxUnpaidLeases uint32 `long:"maxunpaidleases" description:"The maximum number of sold leases whose fee isn't paid yet. Further lease requests are rejected until some of them are paid or their invoices expire. Set to 0 to disable the limit."`
+
+	MaxUnpaidLeasesPerPeer uint32 `long:"maxunpaidleasesperpeer" description:"The maximum number of leases sold to a single peer whose fee isn't paid yet. Set to 0 to disable the limit."`
+}
+
+// DefaultLiquidityAd returns the default liquidity ad configuration.
+func DefaultLiquidityAd() *LiquidityAd {
+	return &LiquidityAd{
+		LeaseDuration:          DefaultLeaseDuration,
+		MaxUnpaidLeases:        DefaultMaxUnpaidLeases,
+		MaxUnpaidLeasesPerPeer: DefaultMaxUnpaidLeasesPerPeer,
+	}
+}
+
+// Ad returns the liquidity ad to advertise, or nil if none is configured.
+func (l *LiquidityAd) Ad() *lnwire.LiquidityAd {
+	if l.MaxLeaseAmt == 0 {
+		return nil
+	}
+
+	return &lnwire.LiquidityAd{
+		LeaseDuration: l.LeaseDuration,
+		FeeBase:       btcutil.Amount(l.FeeBase),
+		FeeRate:       l.FeeRate,
+		MinLeaseAmt:   btcutil.Amount(l.MinLeaseAmt),
+		MaxLeaseAmt:   btcutil.Amount(l.MaxLeaseAmt),
+	}
+}
+
+// Validate checks the values configured for the liquidity ad.
+//
+// NOTE: Part of the Validator interface.
+func (l *LiquidityAd) Validate() error {
+	ad := l.Ad()
+	if ad == nil {
+		return nil
+	}
+
+	if err := ad.Validate(); err != nil {
+		return fmt.Errorf("liquidityad: %w", err)
+	}
+
+	return nil
+}
+
+// Compile-time constraint to ensure LiquidityAd implements the Validator
+// interface.
+var _ Validator = (*LiquidityAd)(nil)
@@ -0,0 +1,46 @@
+package lncfg
+
+import (
+	"errors"
+	"time"
+
+	"github.com/lightningnetwork/lnd/peerpolicy"
+)
+
+// PeerPolicy holds the configuration of the policy that decides which peers
+// we accept connections from.
+//
+//nolint:lll
+type PeerPolicy struct {
+	AllowListOnly bool `long:"allowlistonly" description:"Only accept inbound connections from peers whose pubkey or IP address is on the allow list. The allow list is managed with the AddPeerPolicyEntry and RemovePeerPolicyEntry RPCs. Outbound connections aren't affected."`
+
+	MaxConnsPerIP uint32 `long:"maxconnsperip" description:"The maximum number of inbound connections accepted from a single IP address. Connections from loopback addresses, such as the ones forwarded by a local Tor daemon, and from allow-listed peers aren't limited. Set to 0 to disable the limit."`
+
+	AutoBanThreshold uint32 `long:"autobanthreshold" description:"The number of invalid messages a peer can send within the auto ban duration before it's banned for the auto ban duration. Allow-listed peers and peers we have open or pending channels with are never banned automatically. Only messages the peer authored itself or that couldn't be decoded are counted, not announcements it relayed. Set to 0 to disable automatic bans, which is the default."`
+
+	AutoBanDuration time.Duration `long:"autobanduration" description:"The duration of automatic bans, and the window in which invalid messages are counted towards one."`
+}
+
+// DefaultPeerPolicy returns the default peer policy configuration.
+func DefaultPeerPolicy() *PeerPolicy {
+	return &PeerPolicy{
+		AutoBanThreshold: peerpolicy.DefaultAutoBanThreshold,
+		AutoBanDuration:  peerpolicy.DefaultAutoBanDuration,
+	}
+}
+
+// Validate checks the values configured for the peer policy.
+//
+// NOTE: Part of the Validator interface.
+func (p *PeerPolicy) Validate() error {
+	if p.AutoBanThreshold > 0 && p.AutoBanDuration <= 0 {
+		return errors.New("peerpolicy.autobanduration must be " +
+			"positive")
+	}
+
+	return nil
+}
+
+// Compile-time constraint to ensure PeerPolicy implements the Validator
+// interface.
+var _ Validator = (*PeerPolicy)(nil)
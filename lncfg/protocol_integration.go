@@ -46,6 +46,31 @@ type ProtocolOptions struct {
 	// NoOptionAnySegwit should be set to true if we don't want to use any
 	// Taproot (and beyond) addresses for co-op closing.
 	NoOptionAnySegwit bool `long:"no-any-segwit" description:"disallow using any segiwt witness version as a co-op close address"`
+
+	// OptionPeerStorage should be set if we want to signal the
+	// provide-storage feature bit, and exchange encrypted channel backups
+	// with our channel peers.
+	OptionPeerStorage bool `long:"peer-storage" description:"enable storing encrypted channel backups with channel peers, and storing their backups in return"`
+
+	// OptionRbfCoopClose should be set if we want to signal the
+	// simple-close feature bit, and close channels with peers that support
+	// it using the RBF cooperative close protocol.
+	OptionRbfCoopClose bool `long:"rbf-coop-close" description:"enable the RBF cooperative close protocol, which allows each side to fee bump its own closing transaction"`
+
+	// OptionQuiescence should be set if we want to signal the quiescence
+	// feature bit, and allow channels with peers that support it to be
+	// made quiescent using the stfu message.
+	OptionQuiescence bool `long:"quiescence" description:"enable the quiescence protocol, which allows all updates of a channel to be paused"`
+
+	// OptionDynamicCommitments should be set if we want to signal the
+	// dynamic commitments feature bit, and allow the commitment type and
+	// parameters of channels with peers that support it to be upgraded.
+	OptionDynamicCommitments bool `long:"dynamic-commitments" description:"enable upgrading the commitment type and parameters of open channels"`
+
+	// OptionGossipReconcile should be set if we want to signal the gossip
+	// reconciliation feature bit, and synchronize the channel updates of
+	// the network with peers that support it using set reconciliation.
+	OptionGossipReconcile bool `long:"gossip-reconcile" description:"enable synchronizing channel updates with peers using set reconciliation instead of channel range queries"`
 }
 
 // Wumbo returns true if lnd should permit the creation and acceptance of wumbo
@@ -81,3 +106,30 @@ func (l *ProtocolOptions) ZeroConf() bool {
 func (l *ProtocolOptions) NoAnySegwit() bool {
 	return l.NoOptionAnySegwit
 }
+
+// PeerStorage returns true if we have enabled the provide-storage feature bit.
+func (l *ProtocolOptions) PeerStorage() bool {
+	return l.OptionPeerStorage
+}
+
+// RbfCoopClose returns true if we have enabled the simple-close feature bit.
+func (l *ProtocolOptions) RbfCoopClose() bool {
+	return l.OptionRbfCoopClose
+}
+
+// Quiescence returns true if we have enabled the quiescence feature bit.
+func (l *ProtocolOptions) Quiescence() bool {
+	return l.OptionQuiescence
+}
+
+// DynamicCommitments returns true if we have enabled the dynamic commitments
+// feature bit.
+func (l *ProtocolOptions) DynamicCommitments() bool {
+	return l.OptionDynamicCommitments
+}
+
+// GossipReconcile returns true if we have enabled the gossip reconciliation
+// feature bit.
+func (l *ProtocolOptions) GossipReconcile() bool {
+	return l.OptionGossipReconcile
+}
@@ -0,0 +1,182 @@
+package lncfg
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+const (
+	// DefaultRemoteBackupMinBackoff is the default initial back off used
+	// when an upload of the channel backup to a remote sink failed.
+	DefaultRemoteBackupMinBackoff = 5 * time.Second
+
+	// DefaultRemoteBackupMaxBackoff is the default maximum back off used
+	// between two upload attempts to a remote sink.
+	DefaultRemoteBackupMaxBackoff = 10 * time.Minute
+
+	// DefaultRemoteBackupTimeout is the default time a single upload
+	// attempt to a remote sink is allowed to take.
+	DefaultRemoteBackupTimeout = time.Minute
+
+	// DefaultRemoteBackupS3Key is the default object key the channel
+	// backup is stored under in an S3 bucket.
+	DefaultRemoteBackupS3Key = "channel.backup"
+)
+
+// RemoteBackup holds the configuration of the remote destinations the
+// multi-channel backup file is uploaded to each time it is updated.
+//
+//nolint:lll
+type RemoteBackup struct {
+	S3 *S3Backup `group:"s3" namespace:"s3"`
+
+	WebDAV *WebDAVBackup `group:"webdav" namespace:"webdav"`
+
+	Exec *ExecBackup `group:"exec" namespace:"exec"`
+
+	MinBackoff time.Duration `long:"minbackoff" description:"The initial back off to wait before retrying a failed upload to a remote backup sink. The back off is doubled after each consecutive failure."`
+
+	MaxBackoff time.Duration `long:"maxbackoff" description:"The maximum back off to wait between two upload attempts to a remote backup sink."`
+
+	MaxAttempts uint32 `long:"maxattempts" description:"The maximum number of attempts to upload a single backup to a remote sink before giving up until the next backup update. Set to 0 to retry until the upload succeeds or a newer backup is available."`
+
+	Timeout time.Duration `long:"timeout" description:"The maximum time a single upload attempt to a remote backup sink may take."`
+}
+
+// S3Backup holds the configuration of an S3-compatible object store the
+// channel backup is uploaded to.
+//
+//nolint:lll
+type S3Backup struct {
+	Active bool `long:"active" description:"Upload the multi-channel backup to an S3-compatible object store."`
+
+	Endpoint string `long:"endpoint" description:"The URL of the S3-compatible endpoint, for example https://s3.us-east-1.amazonaws.com. Objects are addressed path-style as <endpoint>/<bucket>/<key>."`
+
+	Region string `long:"region" description:"The region used to sign requests to the endpoint."`
+
+	Bucket string `long:"bucket" description:"The bucket the backup is stored in."`
+
+	Key string `long:"key" description:"The object key the backup is stored under."`
+
+	AccessKeyID string `long:"accesskeyid" description:"The access key ID used to authenticate to the endpoint."`
+
+	SecretAccessKey string `long:"secretaccesskey" description:"The secret access key used to authenticate to the endpoint."`
+}
+
+// WebDAVBackup holds the configuration of a WebDAV server the channel backup
+// is uploaded to.
+//
+//nolint:lll
+type WebDAVBackup struct {
+	Active bool `long:"active" description:"Upload the multi-channel backup to a WebDAV server."`
+
+	URL string `long:"url" description:"The full URL of the file the backup is written to. The backup is first uploaded to a temporary file next to it which is then moved into place."`
+
+	Username string `long:"username" description:"The username used for HTTP basic authentication, if any."`
+
+	Password string `long:"password" description:"The password used for HTTP basic authentication, if any."`
+}
+
+// ExecBackup holds the configuration of an external command that is handed
+// the channel backup each time it is updated.
+//
+//nolint:lll
+type ExecBackup struct {
+	Active bool `long:"active" description:"Run an external command each time the multi-channel backup is updated."`
+
+	Command string `long:"command" description:"The command to run. The packed multi-channel backup is passed to it on stdin, a non-zero exit code marks the upload as failed."`
+
+	Args []string `long:"arg" description:"An argument to pass to the command. Can be specified multiple times."`
+}
+
+// DefaultRemoteBackup returns the default remote backup configuration with
+// all sinks disabled.
+func DefaultRemoteBackup() *RemoteBackup {
+	return &RemoteBackup{
+		S3: &S3Backup{
+			Key: DefaultRemoteBackupS3Key,
+		},
+		WebDAV:     &WebDAVBackup{},
+		Exec:       &ExecBackup{},
+		MinBackoff: DefaultRemoteBackupMinBackoff,
+		MaxBackoff: DefaultRemoteBackupMaxBackoff,
+		Timeout:    DefaultRemoteBackupTimeout,
+	}
+}
+
+// Validate checks the values configured for the remote backup sinks.
+//
+// NOTE: Part of the Validator interface.
+func (r *RemoteBackup) Validate() error {
+	if r.MinBackoff <= 0 {
+		return errors.New("remotebackup.minbackoff must be positive")
+	}
+
+	if r.MaxBackoff < r.MinBackoff {
+		return errors.New("remotebackup.maxbackoff must not be smaller " +
+			"than remotebackup.minbackoff")
+	}
+
+	if r.Timeout <= 0 {
+		return errors.New("remotebackup.timeout must be positive")
+	}
+
+	if r.S3.Active {
+		if err := validateHTTPURL(r.S3.Endpoint); err != nil {
+			return fmt.Errorf("remotebackup.s3.endpoint: %w", err)
+		}
+
+		switch {
+		case r.S3.Region == "":
+			return errors.New("remotebackup.s3.region must be set")
+
+		case r.S3.Bucket == "":
+			return errors.New("remotebackup.s3.bucket must be set")
+
+		case r.S3.Key == "":
+			return errors.New("remotebackup.s3.key must be set")
+
+		case r.S3.AccessKeyID == "" || r.S3.SecretAccessKey == "":
+			return errors.New("remotebackup.s3.accesskeyid and " +
+				"remotebackup.s3.secretaccesskey must be set")
+		}
+	}
+
+	if r.WebDAV.Active {
+		if err := validateHTTPURL(r.WebDAV.URL); err != nil {
+			return fmt.Errorf("remotebackup.webdav.url: %w", err)
+		}
+	}
+
+	if r.Exec.Active && r.Exec.Command == "" {
+		return errors.New("remotebackup.exec.command must be set")
+	}
+
+	return nil
+}
+
+// validateHTTPURL makes sure the given string is an absolute http or https
+// URL.
+func validateHTTPURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("unsupported scheme %q, must be http or "+
+			"https", u.Scheme)
+	}
+
+	if u.Host == "" {
+		return errors.New("missing host")
+	}
+
+	return nil
+}
+
+// Compile-time constraint to ensure RemoteBackup implements the Validator
+// interface.
+var _ Validator = (*RemoteBackup)(nil)
@@ -0,0 +1,66 @@
+package lncfg
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/lightningnetwork/lnd/routing/route"
+	"github.com/lightningnetwork/lnd/wirecapture"
+)
+
+// WireCapture holds the configuration of the capture of the messages
+// exchanged with selected peers.
+//
+//nolint:lll
+type WireCapture struct {
+	Peers []string `long:"peer" description:"The hex-encoded pubkey of a peer whose decrypted wire messages are captured to a file. Can be specified multiple times. Captures can be replayed against a node with lncli replaycapture."`
+
+	Dir string `long:"dir" description:"The directory capture files are written to. Defaults to the wirecapture directory within the data directory."`
+
+	MaxFileSize int64 `long:"maxfilesize" description:"The size in bytes after which the capture file of a peer is rotated."`
+
+	MaxFiles int `long:"maxfiles" description:"The number of rotated capture files kept per peer, in addition to the current one."`
+}
+
+// DefaultWireCapture returns the default wire capture configuration.
+func DefaultWireCapture() *WireCapture {
+	return &WireCapture{
+		MaxFileSize: wirecapture.DefaultMaxFileSize,
+		MaxFiles:    wirecapture.DefaultMaxFiles,
+	}
+}
+
+// PeerSet returns the pubkeys of the peers to capture the messages of.
+func (w *WireCapture) PeerSet() (map[route.Vertex]struct{}, error) {
+	peers := make(map[route.Vertex]struct{}, len(w.Peers))
+	for _, peer := range w.Peers {
+		vertex, err := route.NewVertexFromStr(peer)
+		if err != nil {
+			return nil, fmt.Errorf("invalid wirecapture.peer %v: "+
+				"%w", peer, err)
+		}
+		peers[vertex] = struct{}{}
+	}
+
+	return peers, nil
+}
+
+// Validate checks the values configured for the wire capture.
+//
+// NOTE: Part of the Validator interface.
+func (w *WireCapture) Validate() error {
+	if w.MaxFileSize <= 0 {
+		return errors.New("wirecapture.maxfilesize must be positive")
+	}
+
+	if w.MaxFiles <= 0 {
+		return errors.New("wirecapture.maxfiles must be positive")
+	}
+
+	_, err := w.PeerSet()
+	return err
+}
+
+// Compile-time constraint to ensure WireCapture implements the Validator
+// interface.
+var _ Validator = (*WireCapture)(nil)
@@ -95,6 +95,26 @@ type LightningClient interface {
 	// the client in which any events relevant to the state of peers are sent
 	// over. Events include peers going online and offline.
 	SubscribePeerEvents(ctx context.Context, in *PeerEventSubscription, opts ...grpc.CallOption) (Lightning_SubscribePeerEventsClient, error)
+	// SubscribePeerStats creates a uni-directional stream from the server to the
+	// client over which the traffic statistics of the connected peers are sent
+	// periodically. Each update also carries the errors stored for the peer since
+	// the previous update.
+	SubscribePeerStats(ctx context.Context, in *PeerStatsSubscription, opts ...grpc.CallOption) (Lightning_SubscribePeerStatsClient, error)
+	// lncli: `peerpolicy add`
+	// AddPeerPolicyEntry adds a node or a range of IP addresses to either the ban
+	// list or the allow list of the peer connection policy, replacing any entry
+	// of the list for the same node or range. Connected peers that are banned as
+	// a result are disconnected.
+	AddPeerPolicyEntry(ctx context.Context, in *AddPeerPolicyEntryRequest, opts ...grpc.CallOption) (*AddPeerPolicyEntryResponse, error)
+	// lncli: `peerpolicy remove`
+	// RemovePeerPolicyEntry removes a node or a range of IP addresses from either
+	// the ban list or the allow list of the peer connection policy.
+	RemovePeerPolicyEntry(ctx context.Context, in *RemovePeerPolicyEntryRequest, opts ...grpc.CallOption) (*RemovePeerPolicyEntryResponse, error)
+	// lncli: `peerpolicy list`
+	// ListPeerPolicy returns the active entries of the ban list and the allow
+	// list of the peer connection policy, including the bans of peers that were
+	// banned automatically for sending invalid messages.
+	ListPeerPolicy(ctx context.Context, in *ListPeerPolicyRequest, opts ...grpc.CallOption) (*ListPeerPolicyResponse, error)
 	// lncli: `getinfo`
 	// GetInfo returns general information concerning the lightning node including
 	// it's identity pubkey, alias, the chains it is connected to, and information
@@ -170,6 +190,32 @@ type LightningClient interface {
 	// closure transaction is confirmed, or a manual fee rate. If neither are
 	// specified, then a default lax, block confirmation target is used.
 	CloseChannel(ctx context.Context, in *CloseChannelRequest, opts ...grpc.CallOption) (Lightning_CloseChannelClient, error)
+	// lncli: `simulateforceclose`
+	// SimulateForceClose performs a dry run of a force close of an active
+	// channel. It returns the commitment transaction that would be broadcast,
+	// how each of our outputs on it would be resolved, when they mature and the
+	// fees needed to sweep them at the current fee estimate. Nothing is signed
+	// or broadcast.
+	SimulateForceClose(ctx context.Context, in *SimulateForceCloseRequest, opts ...grpc.CallOption) (*SimulateForceCloseResponse, error)
+	// lncli: `bumpcoopclosefee`
+	// BumpCloseFee replaces the closing transaction of a channel that is being
+	// cooperatively closed with the simple close protocol with one paying a
+	// higher fee rate. The fee is paid from our own channel balance. The new
+	// closing transaction is broadcast once the remote peer signed it.
+	BumpCloseFee(ctx context.Context, in *BumpCloseFeeRequest, opts ...grpc.CallOption) (*BumpCloseFeeResponse, error)
+	// lncli: `listhtlcdeadlines`
+	// ListHtlcDeadlines lists the HTLCs of open channels that are approaching
+	// the height at which they'd trigger a force close of their channel, as
+	// determined by the force close policy. HTLCs the policy exempts from
+	// triggering a force close are included and marked as such.
+	ListHtlcDeadlines(ctx context.Context, in *ListHtlcDeadlinesRequest, opts ...grpc.CallOption) (*ListHtlcDeadlinesResponse, error)
+	// lncli: `subscriberesolverprogress`
+	// SubscribeResolverProgress creates a uni-directional stream from the server
+	// to the client over which the progress of the on-chain resolution of force
+	// closed channels is sent. The current progress of all channels being
+	// resolved is sent first, followed by an update each time the progress of a
+	// channel changes.
+	SubscribeResolverProgress(ctx context.Context, in *ResolverProgressSubscription, opts ...grpc.CallOption) (Lightning_SubscribeResolverProgressClient, error)
 	// lncli: `abandonchannel`
 	// AbandonChannel removes all channel state from the database except for a
 	// close summary. This method can be used to get rid of permanently unusable
@@ -178,7 +224,32 @@ type LightningClient interface {
 	// never broadcast. Only available for non-externally funded channels in dev
 	// build.
 	AbandonChannel(ctx context.Context, in *AbandonChannelRequest, opts ...grpc.CallOption) (*AbandonChannelResponse, error)
+	// lncli: `upgradechannel`
+	// UpgradeChannelCommitment upgrades the commitment format and parameters of
+	// an open channel without closing it, using the dynamic commitments
+	// protocol. The call blocks until the upgrade is locked in by both parties
+	// or rejected by the remote peer. The static channel backup of the channel
+	// is refreshed once the upgrade is locked in.
+	UpgradeChannelCommitment(ctx context.Context, in *UpgradeChannelCommitmentRequest, opts ...grpc.CallOption) (*UpgradeChannelCommitmentResponse, error)
+	// lncli: `updateliquidityad`
+	// UpdateLiquidityAd replaces the liquidity ad advertised in our node
+	// announcement, through which other nodes can lease inbound liquidity from
+	// the on-chain funds of our wallet for a fee. The ad can also be removed to
+	// stop selling leases.
+	UpdateLiquidityAd(ctx context.Context, in *UpdateLiquidityAdRequest, opts ...grpc.CallOption) (*UpdateLiquidityAdResponse, error)
+	// lncli: `requestlease`
+	// RequestLease requests a channel lease from a connected peer advertising a
+	// liquidity ad. The peer replies with an offer that includes an invoice for
+	// the lease fee. Once the invoice is paid, the peer opens a lease channel to
+	// us whose funds are locked on its side until the lease expires.
+	RequestLease(ctx context.Context, in *RequestLeaseRequest, opts ...grpc.CallOption) (*RequestLeaseResponse, error)
+	// lncli: `listleases`
+	// ListLeases returns the liquidity ad we advertise along with the channel
+	// leases we bought or sold, both those active as lease channels and those
+	// still waiting on the payment of their fee or the opening of their channel.
+	ListLeases(ctx context.Context, in *ListLeasesRequest, opts ...grpc.CallOption) (*ListLeasesResponse, error)
 	// Deprecated: Do not use.
+	//
 	// lncli: `sendpayment`
 	// Deprecated, use routerrpc.SendPaymentV2. SendPayment dispatches a
 	// bi-directional streaming RPC for sending payments through the Lightning
@@ -192,6 +263,7 @@ type LightningClient interface {
 	// hash (if any) to be encoded as hex strings.
 	SendPaymentSync(ctx context.Context, in *SendRequest, opts ...grpc.CallOption) (*SendResponse, error)
 	// Deprecated: Do not use.
+	//
 	// lncli: `sendtoroute`
 	// Deprecated, use routerrpc.SendToRouteV2. SendToRoute is a bi-directional
 	// streaming RPC for sending payment through the Lightning Network. This
@@ -283,6 +355,28 @@ type LightningClient interface {
 	// GetNetworkInfo returns some basic stats about the known channel graph from
 	// the point of view of the node.
 	GetNetworkInfo(ctx context.Context, in *NetworkInfoRequest, opts ...grpc.CallOption) (*NetworkInfo, error)
+	// lncli: `gossipbudget`
+	// GossipBudgetUsage returns the gossip exchanged with our peers and how it
+	// was affected by the configured gossip bandwidth budgets, both in total and
+	// for the gossip syncer of each connected peer. It also returns the bandwidth
+	// used to sync the channel graph with each sync mode.
+	GossipBudgetUsage(ctx context.Context, in *GossipBudgetUsageRequest, opts ...grpc.CallOption) (*GossipBudgetUsageResponse, error)
+	// lncli: `exportgraphsnapshot`
+	// ExportGraphSnapshot returns a snapshot of the public channel graph in a
+	// compact, versioned binary format. The snapshot holds the signed
+	// announcements of all public channels and nodes, so that it can be verified
+	// when it's imported by another node with ImportGraphSnapshot.
+	ExportGraphSnapshot(ctx context.Context, in *ExportGraphSnapshotRequest, opts ...grpc.CallOption) (*GraphSnapshot, error)
+	// lncli: `importgraphsnapshot`
+	// ImportGraphSnapshot adds the channels and nodes of a snapshot created with
+	// ExportGraphSnapshot to the channel graph. The signatures of all
+	// announcements are verified, while the capacity and funding outpoint of the
+	// channels are trusted, so that the graph can be bootstrapped without looking
+	// up the funding transactions of the channels. Channels and nodes already
+	// known are only updated if the snapshot holds more recent announcements.
+	// Any announcements that are newer than the snapshot are then received from
+	// peers through regular gossip syncing.
+	ImportGraphSnapshot(ctx context.Context, in *ImportGraphSnapshotRequest, opts ...grpc.CallOption) (*ImportGraphSnapshotResponse, error)
 	// lncli: `stop`
 	// StopDaemon will send a shutdown request to the interrupt handler, triggering
 	// a graceful shutdown of the daemon.
@@ -353,6 +447,11 @@ type LightningClient interface {
 	// ups, but the updated set of encrypted multi-chan backups with the closed
 	// channel(s) removed.
 	SubscribeChannelBackups(ctx context.Context, in *ChannelBackupSubscription, opts ...grpc.CallOption) (Lightning_SubscribeChannelBackupsClient, error)
+	// lncli: `backupsinkstatus`
+	// BackupSinkStatus returns the upload status of every remote sink the
+	// multi-channel backup is uploaded to, including the time of the last
+	// successful upload to each sink.
+	BackupSinkStatus(ctx context.Context, in *BackupSinkStatusRequest, opts ...grpc.CallOption) (*BackupSinkStatusResponse, error)
 	// lncli: `bakemacaroon`
 	// BakeMacaroon allows the creation of a new macaroon with custom read and
 	// write permissions. No first-party caveats are added since this can be done
@@ -597,6 +696,65 @@ func (x *lightningSubscribePeerEventsClient) Recv() (*PeerEvent, error) {
 	return m, nil
 }
 
+func (c *lightningClient) SubscribePeerStats(ctx context.Context, in *PeerStatsSubscription, opts ...grpc.CallOption) (Lightning_SubscribePeerStatsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[2], "/lnrpc.Lightning/SubscribePeerStats", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &lightningSubscribePeerStatsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Lightning_SubscribePeerStatsClient interface {
+	Recv() (*PeerStatsUpdate, error)
+	grpc.ClientStream
+}
+
+type lightningSubscribePeerStatsClient struct {
+	grpc.ClientStream
+}
+
+func (x *lightningSubscribePeerStatsClient) Recv() (*PeerStatsUpdate, error) {
+	m := new(PeerStatsUpdate)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *lightningClient) AddPeerPolicyEntry(ctx context.Context, in *AddPeerPolicyEntryRequest, opts ...grpc.CallOption) (*AddPeerPolicyEntryResponse, error) {
+	out := new(AddPeerPolicyEntryResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/AddPeerPolicyEntry", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) RemovePeerPolicyEntry(ctx context.Context, in *RemovePeerPolicyEntryRequest, opts ...grpc.CallOption) (*RemovePeerPolicyEntryResponse, error) {
+	out := new(RemovePeerPolicyEntryResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/RemovePeerPolicyEntry", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) ListPeerPolicy(ctx context.Context, in *ListPeerPolicyRequest, opts ...grpc.CallOption) (*ListPeerPolicyResponse, error) {
+	out := new(ListPeerPolicyResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/ListPeerPolicy", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *lightningClient) GetInfo(ctx context.Context, in *GetInfoRequest, opts ...grpc.CallOption) (*GetInfoResponse, error) {
 	out := new(GetInfoResponse)
 	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/GetInfo", in, out, opts...)
@@ -634,7 +792,7 @@ func (c *lightningClient) ListChannels(ctx context.Context, in *ListChannelsRequ
 }
 
 func (c *lightningClient) SubscribeChannelEvents(ctx context.Context, in *ChannelEventSubscription, opts ...grpc.CallOption) (Lightning_SubscribeChannelEventsClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[2], "/lnrpc.Lightning/SubscribeChannelEvents", opts...)
+	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[3], "/lnrpc.Lightning/SubscribeChannelEvents", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -684,7 +842,7 @@ func (c *lightningClient) OpenChannelSync(ctx context.Context, in *OpenChannelRe
 }
 
 func (c *lightningClient) OpenChannel(ctx context.Context, in *OpenChannelRequest, opts ...grpc.CallOption) (Lightning_OpenChannelClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[3], "/lnrpc.Lightning/OpenChannel", opts...)
+	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[4], "/lnrpc.Lightning/OpenChannel", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -734,7 +892,7 @@ func (c *lightningClient) FundingStateStep(ctx context.Context, in *FundingTrans
 }
 
 func (c *lightningClient) ChannelAcceptor(ctx context.Context, opts ...grpc.CallOption) (Lightning_ChannelAcceptorClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[4], "/lnrpc.Lightning/ChannelAcceptor", opts...)
+	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[5], "/lnrpc.Lightning/ChannelAcceptor", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -765,7 +923,7 @@ func (x *lightningChannelAcceptorClient) Recv() (*ChannelAcceptRequest, error) {
 }
 
 func (c *lightningClient) CloseChannel(ctx context.Context, in *CloseChannelRequest, opts ...grpc.CallOption) (Lightning_CloseChannelClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[5], "/lnrpc.Lightning/CloseChannel", opts...)
+	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[6], "/lnrpc.Lightning/CloseChannel", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -796,6 +954,65 @@ func (x *lightningCloseChannelClient) Recv() (*CloseStatusUpdate, error) {
 	return m, nil
 }
 
+func (c *lightningClient) SimulateForceClose(ctx context.Context, in *SimulateForceCloseRequest, opts ...grpc.CallOption) (*SimulateForceCloseResponse, error) {
+	out := new(SimulateForceCloseResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/SimulateForceClose", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) BumpCloseFee(ctx context.Context, in *BumpCloseFeeRequest, opts ...grpc.CallOption) (*BumpCloseFeeResponse, error) {
+	out := new(BumpCloseFeeResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/BumpCloseFee", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) ListHtlcDeadlines(ctx context.Context, in *ListHtlcDeadlinesRequest, opts ...grpc.CallOption) (*ListHtlcDeadlinesResponse, error) {
+	out := new(ListHtlcDeadlinesResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/ListHtlcDeadlines", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) SubscribeResolverProgress(ctx context.Context, in *ResolverProgressSubscription, opts ...grpc.CallOption) (Lightning_SubscribeResolverProgressClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[7], "/lnrpc.Lightning/SubscribeResolverProgress", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &lightningSubscribeResolverProgressClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Lightning_SubscribeResolverProgressClient interface {
+	Recv() (*ResolverProgressUpdate, error)
+	grpc.ClientStream
+}
+
+type lightningSubscribeResolverProgressClient struct {
+	grpc.ClientStream
+}
+
+func (x *lightningSubscribeResolverProgressClient) Recv() (*ResolverProgressUpdate, error) {
+	m := new(ResolverProgressUpdate)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func (c *lightningClient) AbandonChannel(ctx context.Context, in *AbandonChannelRequest, opts ...grpc.CallOption) (*AbandonChannelResponse, error) {
 	out := new(AbandonChannelResponse)
 	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/AbandonChannel", in, out, opts...)
@@ -805,9 +1022,45 @@ func (c *lightningClient) AbandonChannel(ctx context.Context, in *AbandonChannel
 	return out, nil
 }
 
+func (c *lightningClient) UpgradeChannelCommitment(ctx context.Context, in *UpgradeChannelCommitmentRequest, opts ...grpc.CallOption) (*UpgradeChannelCommitmentResponse, error) {
+	out := new(UpgradeChannelCommitmentResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/UpgradeChannelCommitment", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) UpdateLiquidityAd(ctx context.Context, in *UpdateLiquidityAdRequest, opts ...grpc.CallOption) (*UpdateLiquidityAdResponse, error) {
+	out := new(UpdateLiquidityAdResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/UpdateLiquidityAd", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) RequestLease(ctx context.Context, in *RequestLeaseRequest, opts ...grpc.CallOption) (*RequestLeaseResponse, error) {
+	out := new(RequestLeaseResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/RequestLease", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) ListLeases(ctx context.Context, in *ListLeasesRequest, opts ...grpc.CallOption) (*ListLeasesResponse, error) {
+	out := new(ListLeasesResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/ListLeases", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // Deprecated: Do not use.
 func (c *lightningClient) SendPayment(ctx context.Context, opts ...grpc.CallOption) (Lightning_SendPaymentClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[6], "/lnrpc.Lightning/SendPayment", opts...)
+	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[8], "/lnrpc.Lightning/SendPayment", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -848,7 +1101,7 @@ func (c *lightningClient) SendPaymentSync(ctx context.Context, in *SendRequest,
 
 // Deprecated: Do not use.
 func (c *lightningClient) SendToRoute(ctx context.Context, opts ...grpc.CallOption) (Lightning_SendToRouteClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[7], "/lnrpc.Lightning/SendToRoute", opts...)
+	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[9], "/lnrpc.Lightning/SendToRoute", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -915,7 +1168,7 @@ func (c *lightningClient) LookupInvoice(ctx context.Context, in *PaymentHash, op
 }
 
 func (c *lightningClient) SubscribeInvoices(ctx context.Context, in *InvoiceSubscription, opts ...grpc.CallOption) (Lightning_SubscribeInvoicesClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[8], "/lnrpc.Lightning/SubscribeInvoices", opts...)
+	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[10], "/lnrpc.Lightning/SubscribeInvoices", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -1036,6 +1289,33 @@ func (c *lightningClient) GetNetworkInfo(ctx context.Context, in *NetworkInfoReq
 	return out, nil
 }
 
+func (c *lightningClient) GossipBudgetUsage(ctx context.Context, in *GossipBudgetUsageRequest, opts ...grpc.CallOption) (*GossipBudgetUsageResponse, error) {
+	out := new(GossipBudgetUsageResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/GossipBudgetUsage", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) ExportGraphSnapshot(ctx context.Context, in *ExportGraphSnapshotRequest, opts ...grpc.CallOption) (*GraphSnapshot, error) {
+	out := new(GraphSnapshot)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/ExportGraphSnapshot", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) ImportGraphSnapshot(ctx context.Context, in *ImportGraphSnapshotRequest, opts ...grpc.CallOption) (*ImportGraphSnapshotResponse, error) {
+	out := new(ImportGraphSnapshotResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/ImportGraphSnapshot", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *lightningClient) StopDaemon(ctx context.Context, in *StopRequest, opts ...grpc.CallOption) (*StopResponse, error) {
 	out := new(StopResponse)
 	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/StopDaemon", in, out, opts...)
@@ -1046,7 +1326,7 @@ func (c *lightningClient) StopDaemon(ctx context.Context, in *StopRequest, opts
 }
 
 func (c *lightningClient) SubscribeChannelGraph(ctx context.Context, in *GraphTopologySubscription, opts ...grpc.CallOption) (Lightning_SubscribeChannelGraphClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[9], "/lnrpc.Lightning/SubscribeChannelGraph", opts...)
+	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[11], "/lnrpc.Lightning/SubscribeChannelGraph", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -1150,7 +1430,7 @@ func (c *lightningClient) RestoreChannelBackups(ctx context.Context, in *Restore
 }
 
 func (c *lightningClient) SubscribeChannelBackups(ctx context.Context, in *ChannelBackupSubscription, opts ...grpc.CallOption) (Lightning_SubscribeChannelBackupsClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[10], "/lnrpc.Lightning/SubscribeChannelBackups", opts...)
+	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[12], "/lnrpc.Lightning/SubscribeChannelBackups", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -1181,6 +1461,15 @@ func (x *lightningSubscribeChannelBackupsClient) Recv() (*ChanBackupSnapshot, er
 	return m, nil
 }
 
+func (c *lightningClient) BackupSinkStatus(ctx context.Context, in *BackupSinkStatusRequest, opts ...grpc.CallOption) (*BackupSinkStatusResponse, error) {
+	out := new(BackupSinkStatusResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/BackupSinkStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *lightningClient) BakeMacaroon(ctx context.Context, in *BakeMacaroonRequest, opts ...grpc.CallOption) (*BakeMacaroonResponse, error) {
 	out := new(BakeMacaroonResponse)
 	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/BakeMacaroon", in, out, opts...)
@@ -1227,7 +1516,7 @@ func (c *lightningClient) CheckMacaroonPermissions(ctx context.Context, in *Chec
 }
 
 func (c *lightningClient) RegisterRPCMiddleware(ctx context.Context, opts ...grpc.CallOption) (Lightning_RegisterRPCMiddlewareClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[11], "/lnrpc.Lightning/RegisterRPCMiddleware", opts...)
+	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[13], "/lnrpc.Lightning/RegisterRPCMiddleware", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -1267,7 +1556,7 @@ func (c *lightningClient) SendCustomMessage(ctx context.Context, in *SendCustomM
 }
 
 func (c *lightningClient) SubscribeCustomMessages(ctx context.Context, in *SubscribeCustomMessagesRequest, opts ...grpc.CallOption) (Lightning_SubscribeCustomMessagesClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[12], "/lnrpc.Lightning/SubscribeCustomMessages", opts...)
+	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[14], "/lnrpc.Lightning/SubscribeCustomMessages", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -1397,6 +1686,26 @@ type LightningServer interface {
 	// the client in which any events relevant to the state of peers are sent
 	// over. Events include peers going online and offline.
 	SubscribePeerEvents(*PeerEventSubscription, Lightning_SubscribePeerEventsServer) error
+	// SubscribePeerStats creates a uni-directional stream from the server to the
+	// client over which the traffic statistics of the connected peers are sent
+	// periodically. Each update also carries the errors stored for the peer since
+	// the previous update.
+	SubscribePeerStats(*PeerStatsSubscription, Lightning_SubscribePeerStatsServer) error
+	// lncli: `peerpolicy add`
+	// AddPeerPolicyEntry adds a node or a range of IP addresses to either the ban
+	// list or the allow list of the peer connection policy, replacing any entry
+	// of the list for the same node or range. Connected peers that are banned as
+	// a result are disconnected.
+	AddPeerPolicyEntry(context.Context, *AddPeerPolicyEntryRequest) (*AddPeerPolicyEntryResponse, error)
+	// lncli: `peerpolicy remove`
+	// RemovePeerPolicyEntry removes a node or a range of IP addresses from either
+	// the ban list or the allow list of the peer connection policy.
+	RemovePeerPolicyEntry(context.Context, *RemovePeerPolicyEntryRequest) (*RemovePeerPolicyEntryResponse, error)
+	// lncli: `peerpolicy list`
+	// ListPeerPolicy returns the active entries of the ban list and the allow
+	// list of the peer connection policy, including the bans of peers that were
+	// banned automatically for sending invalid messages.
+	ListPeerPolicy(context.Context, *ListPeerPolicyRequest) (*ListPeerPolicyResponse, error)
 	// lncli: `getinfo`
 	// GetInfo returns general information concerning the lightning node including
 	// it's identity pubkey, alias, the chains it is connected to, and information
@@ -1472,6 +1781,32 @@ type LightningServer interface {
 	// closure transaction is confirmed, or a manual fee rate. If neither are
 	// specified, then a default lax, block confirmation target is used.
 	CloseChannel(*CloseChannelRequest, Lightning_CloseChannelServer) error
+	// lncli: `simulateforceclose`
+	// SimulateForceClose performs a dry run of a force close of an active
+	// channel. It returns the commitment transaction that would be broadcast,
+	// how each of our outputs on it would be resolved, when they mature and the
+	// fees needed to sweep them at the current fee estimate. Nothing is signed
+	// or broadcast.
+	SimulateForceClose(context.Context, *SimulateForceCloseRequest) (*SimulateForceCloseResponse, error)
+	// lncli: `bumpcoopclosefee`
+	// BumpCloseFee replaces the closing transaction of a channel that is being
+	// cooperatively closed with the simple close protocol with one paying a
+	// higher fee rate. The fee is paid from our own channel balance. The new
+	// closing transaction is broadcast once the remote peer signed it.
+	BumpCloseFee(context.Context, *BumpCloseFeeRequest) (*BumpCloseFeeResponse, error)
+	// lncli: `listhtlcdeadlines`
+	// ListHtlcDeadlines lists the HTLCs of open channels that are approaching
+	// the height at which they'd trigger a force close of their channel, as
+	// determined by the force close policy. HTLCs the policy exempts from
+	// triggering a force close are included and marked as such.
+	ListHtlcDeadlines(context.Context, *ListHtlcDeadlinesRequest) (*ListHtlcDeadlinesResponse, error)
+	// lncli: `subscriberesolverprogress`
+	// SubscribeResolverProgress creates a uni-directional stream from the server
+	// to the client over which the progress of the on-chain resolution of force
+	// closed channels is sent. The current progress of all channels being
+	// resolved is sent first, followed by an update each time the progress of a
+	// channel changes.
+	SubscribeResolverProgress(*ResolverProgressSubscription, Lightning_SubscribeResolverProgressServer) error
 	// lncli: `abandonchannel`
 	// AbandonChannel removes all channel state from the database except for a
 	// close summary. This method can be used to get rid of permanently unusable
@@ -1480,7 +1815,32 @@ type LightningServer interface {
 	// never broadcast. Only available for non-externally funded channels in dev
 	// build.
 	AbandonChannel(context.Context, *AbandonChannelRequest) (*AbandonChannelResponse, error)
+	// lncli: `upgradechannel`
+	// UpgradeChannelCommitment upgrades the commitment format and parameters of
+	// an open channel without closing it, using the dynamic commitments
+	// protocol. The call blocks until the upgrade is locked in by both parties
+	// or rejected by the remote peer. The static channel backup of the channel
+	// is refreshed once the upgrade is locked in.
+	UpgradeChannelCommitment(context.Context, *UpgradeChannelCommitmentRequest) (*UpgradeChannelCommitmentResponse, error)
+	// lncli: `updateliquidityad`
+	// UpdateLiquidityAd replaces the liquidity ad advertised in our node
+	// announcement, through which other nodes can lease inbound liquidity from
+	// the on-chain funds of our wallet for a fee. The ad can also be removed to
+	// stop selling leases.
+	UpdateLiquidityAd(context.Context, *UpdateLiquidityAdRequest) (*UpdateLiquidityAdResponse, error)
+	// lncli: `requestlease`
+	// RequestLease requests a channel lease from a connected peer advertising a
+	// liquidity ad. The peer replies with an offer that includes an invoice for
+	// the lease fee. Once the invoice is paid, the peer opens a lease channel to
+	// us whose funds are locked on its side until the lease expires.
+	RequestLease(context.Context, *RequestLeaseRequest) (*RequestLeaseResponse, error)
+	// lncli: `listleases`
+	// ListLeases returns the liquidity ad we advertise along with the channel
+	// leases we bought or sold, both those active as lease channels and those
+	// still waiting on the payment of their fee or the opening of their channel.
+	ListLeases(context.Context, *ListLeasesRequest) (*ListLeasesResponse, error)
 	// Deprecated: Do not use.
+	//
 	// lncli: `sendpayment`
 	// Deprecated, use routerrpc.SendPaymentV2. SendPayment dispatches a
 	// bi-directional streaming RPC for sending payments through the Lightning
@@ -1494,6 +1854,7 @@ type LightningServer interface {
 	// hash (if any) to be encoded as hex strings.
 	SendPaymentSync(context.Context, *SendRequest) (*SendResponse, error)
 	// Deprecated: Do not use.
+	//
 	// lncli: `sendtoroute`
 	// Deprecated, use routerrpc.SendToRouteV2. SendToRoute is a bi-directional
 	// streaming RPC for sending payment through the Lightning Network. This
@@ -1585,6 +1946,28 @@ type LightningServer interface {
 	// GetNetworkInfo returns some basic stats about the known channel graph from
 	// the point of view of the node.
 	GetNetworkInfo(context.Context, *NetworkInfoRequest) (*NetworkInfo, error)
+	// lncli: `gossipbudget`
+	// GossipBudgetUsage returns the gossip exchanged with our peers and how it
+	// was affected by the configured gossip bandwidth budgets, both in total and
+	// for the gossip syncer of each connected peer. It also returns the bandwidth
+	// used to sync the channel graph with each sync mode.
+	GossipBudgetUsage(context.Context, *GossipBudgetUsageRequest) (*GossipBudgetUsageResponse, error)
+	// lncli: `exportgraphsnapshot`
+	// ExportGraphSnapshot returns a snapshot of the public channel graph in a
+	// compact, versioned binary format. The snapshot holds the signed
+	// announcements of all public channels and nodes, so that it can be verified
+	// when it's imported by another node with ImportGraphSnapshot.
+	ExportGraphSnapshot(context.Context, *ExportGraphSnapshotRequest) (*GraphSnapshot, error)
+	// lncli: `importgraphsnapshot`
+	// ImportGraphSnapshot adds the channels and nodes of a snapshot created with
+	// ExportGraphSnapshot to the channel graph. The signatures of all
+	// announcements are verified, while the capacity and funding outpoint of the
+	// channels are trusted, so that the graph can be bootstrapped without looking
+	// up the funding transactions of the channels. Channels and nodes already
+	// known are only updated if the snapshot holds more recent announcements.
+	// Any announcements that are newer than the snapshot are then received from
+	// peers through regular gossip syncing.
+	ImportGraphSnapshot(context.Context, *ImportGraphSnapshotRequest) (*ImportGraphSnapshotResponse, error)
 	// lncli: `stop`
 	// StopDaemon will send a shutdown request to the interrupt handler, triggering
 	// a graceful shutdown of the daemon.
@@ -1655,6 +2038,11 @@ type LightningServer interface {
 	// ups, but the updated set of encrypted multi-chan backups with the closed
 	// channel(s) removed.
 	SubscribeChannelBackups(*ChannelBackupSubscription, Lightning_SubscribeChannelBackupsServer) error
+	// lncli: `backupsinkstatus`
+	// BackupSinkStatus returns the upload status of every remote sink the
+	// multi-channel backup is uploaded to, including the time of the last
+	// successful upload to each sink.
+	BackupSinkStatus(context.Context, *BackupSinkStatusRequest) (*BackupSinkStatusResponse, error)
 	// lncli: `bakemacaroon`
 	// BakeMacaroon allows the creation of a new macaroon with custom read and
 	// write permissions. No first-party caveats are added since this can be done
@@ -1760,6 +2148,18 @@ func (UnimplementedLightningServer) ListPeers(context.Context, *ListPeersRequest
 func (UnimplementedLightningServer) SubscribePeerEvents(*PeerEventSubscription, Lightning_SubscribePeerEventsServer) error {
 	return status.Errorf(codes.Unimplemented, "method SubscribePeerEvents not implemented")
 }
+func (UnimplementedLightningServer) SubscribePeerStats(*PeerStatsSubscription, Lightning_SubscribePeerStatsServer) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribePeerStats not implemented")
+}
+func (UnimplementedLightningServer) AddPeerPolicyEntry(context.Context, *AddPeerPolicyEntryRequest) (*AddPeerPolicyEntryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddPeerPolicyEntry not implemented")
+}
+func (UnimplementedLightningServer) RemovePeerPolicyEntry(context.Context, *RemovePeerPolicyEntryRequest) (*RemovePeerPolicyEntryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemovePeerPolicyEntry not implemented")
+}
+func (UnimplementedLightningServer) ListPeerPolicy(context.Context, *ListPeerPolicyRequest) (*ListPeerPolicyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListPeerPolicy not implemented")
+}
 func (UnimplementedLightningServer) GetInfo(context.Context, *GetInfoRequest) (*GetInfoResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetInfo not implemented")
 }
@@ -1796,9 +2196,33 @@ func (UnimplementedLightningServer) ChannelAcceptor(Lightning_ChannelAcceptorSer
 func (UnimplementedLightningServer) CloseChannel(*CloseChannelRequest, Lightning_CloseChannelServer) error {
 	return status.Errorf(codes.Unimplemented, "method CloseChannel not implemented")
 }
+func (UnimplementedLightningServer) SimulateForceClose(context.Context, *SimulateForceCloseRequest) (*SimulateForceCloseResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SimulateForceClose not implemented")
+}
+func (UnimplementedLightningServer) BumpCloseFee(context.Context, *BumpCloseFeeRequest) (*BumpCloseFeeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BumpCloseFee not implemented")
+}
+func (UnimplementedLightningServer) ListHtlcDeadlines(context.Context, *ListHtlcDeadlinesRequest) (*ListHtlcDeadlinesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListHtlcDeadlines not implemented")
+}
+func (UnimplementedLightningServer) SubscribeResolverProgress(*ResolverProgressSubscription, Lightning_SubscribeResolverProgressServer) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeResolverProgress not implemented")
+}
 func (UnimplementedLightningServer) AbandonChannel(context.Context, *AbandonChannelRequest) (*AbandonChannelResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method AbandonChannel not implemented")
 }
+func (UnimplementedLightningServer) UpgradeChannelCommitment(context.Context, *UpgradeChannelCommitmentRequest) (*UpgradeChannelCommitmentResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpgradeChannelCommitment not implemented")
+}
+func (UnimplementedLightningServer) UpdateLiquidityAd(context.Context, *UpdateLiquidityAdRequest) (*UpdateLiquidityAdResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateLiquidityAd not implemented")
+}
+func (UnimplementedLightningServer) RequestLease(context.Context, *RequestLeaseRequest) (*RequestLeaseResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RequestLease not implemented")
+}
+func (UnimplementedLightningServer) ListLeases(context.Context, *ListLeasesRequest) (*ListLeasesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListLeases not implemented")
+}
 func (UnimplementedLightningServer) SendPayment(Lightning_SendPaymentServer) error {
 	return status.Errorf(codes.Unimplemented, "method SendPayment not implemented")
 }
@@ -1853,6 +2277,15 @@ func (UnimplementedLightningServer) QueryRoutes(context.Context, *QueryRoutesReq
 func (UnimplementedLightningServer) GetNetworkInfo(context.Context, *NetworkInfoRequest) (*NetworkInfo, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetNetworkInfo not implemented")
 }
+func (UnimplementedLightningServer) GossipBudgetUsage(context.Context, *GossipBudgetUsageRequest) (*GossipBudgetUsageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GossipBudgetUsage not implemented")
+}
+func (UnimplementedLightningServer) ExportGraphSnapshot(context.Context, *ExportGraphSnapshotRequest) (*GraphSnapshot, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExportGraphSnapshot not implemented")
+}
+func (UnimplementedLightningServer) ImportGraphSnapshot(context.Context, *ImportGraphSnapshotRequest) (*ImportGraphSnapshotResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ImportGraphSnapshot not implemented")
+}
 func (UnimplementedLightningServer) StopDaemon(context.Context, *StopRequest) (*StopResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method StopDaemon not implemented")
 }
@@ -1886,6 +2319,9 @@ func (UnimplementedLightningServer) RestoreChannelBackups(context.Context, *Rest
 func (UnimplementedLightningServer) SubscribeChannelBackups(*ChannelBackupSubscription, Lightning_SubscribeChannelBackupsServer) error {
 	return status.Errorf(codes.Unimplemented, "method SubscribeChannelBackups not implemented")
 }
+func (UnimplementedLightningServer) BackupSinkStatus(context.Context, *BackupSinkStatusRequest) (*BackupSinkStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BackupSinkStatus not implemented")
+}
 func (UnimplementedLightningServer) BakeMacaroon(context.Context, *BakeMacaroonRequest) (*BakeMacaroonResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method BakeMacaroon not implemented")
 }
@@ -2205,6 +2641,81 @@ func (x *lightningSubscribePeerEventsServer) Send(m *PeerEvent) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func _Lightning_SubscribePeerStats_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(PeerStatsSubscription)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(LightningServer).SubscribePeerStats(m, &lightningSubscribePeerStatsServer{stream})
+}
+
+type Lightning_SubscribePeerStatsServer interface {
+	Send(*PeerStatsUpdate) error
+	grpc.ServerStream
+}
+
+type lightningSubscribePeerStatsServer struct {
+	grpc.ServerStream
+}
+
+func (x *lightningSubscribePeerStatsServer) Send(m *PeerStatsUpdate) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Lightning_AddPeerPolicyEntry_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddPeerPolicyEntryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).AddPeerPolicyEntry(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/AddPeerPolicyEntry",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).AddPeerPolicyEntry(ctx, req.(*AddPeerPolicyEntryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_RemovePeerPolicyEntry_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemovePeerPolicyEntryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).RemovePeerPolicyEntry(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/RemovePeerPolicyEntry",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).RemovePeerPolicyEntry(ctx, req.(*RemovePeerPolicyEntryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_ListPeerPolicy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPeerPolicyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).ListPeerPolicy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/ListPeerPolicy",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).ListPeerPolicy(ctx, req.(*ListPeerPolicyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Lightning_GetInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(GetInfoRequest)
 	if err := dec(in); err != nil {
@@ -2438,6 +2949,81 @@ func (x *lightningCloseChannelServer) Send(m *CloseStatusUpdate) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func _Lightning_SimulateForceClose_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SimulateForceCloseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).SimulateForceClose(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/SimulateForceClose",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).SimulateForceClose(ctx, req.(*SimulateForceCloseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_BumpCloseFee_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BumpCloseFeeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).BumpCloseFee(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/BumpCloseFee",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).BumpCloseFee(ctx, req.(*BumpCloseFeeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_ListHtlcDeadlines_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListHtlcDeadlinesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).ListHtlcDeadlines(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/ListHtlcDeadlines",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).ListHtlcDeadlines(ctx, req.(*ListHtlcDeadlinesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_SubscribeResolverProgress_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ResolverProgressSubscription)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(LightningServer).SubscribeResolverProgress(m, &lightningSubscribeResolverProgressServer{stream})
+}
+
+type Lightning_SubscribeResolverProgressServer interface {
+	Send(*ResolverProgressUpdate) error
+	grpc.ServerStream
+}
+
+type lightningSubscribeResolverProgressServer struct {
+	grpc.ServerStream
+}
+
+func (x *lightningSubscribeResolverProgressServer) Send(m *ResolverProgressUpdate) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 func _Lightning_AbandonChannel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(AbandonChannelRequest)
 	if err := dec(in); err != nil {
@@ -2456,6 +3042,78 @@ func _Lightning_AbandonChannel_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Lightning_UpgradeChannelCommitment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpgradeChannelCommitmentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).UpgradeChannelCommitment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/UpgradeChannelCommitment",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).UpgradeChannelCommitment(ctx, req.(*UpgradeChannelCommitmentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_UpdateLiquidityAd_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateLiquidityAdRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).UpdateLiquidityAd(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/UpdateLiquidityAd",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).UpdateLiquidityAd(ctx, req.(*UpdateLiquidityAdRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_RequestLease_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RequestLeaseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).RequestLease(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/RequestLease",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).RequestLease(ctx, req.(*RequestLeaseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_ListLeases_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListLeasesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).ListLeases(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/ListLeases",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).ListLeases(ctx, req.(*ListLeasesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Lightning_SendPayment_Handler(srv interface{}, stream grpc.ServerStream) error {
 	return srv.(LightningServer).SendPayment(&lightningSendPaymentServer{stream})
 }
@@ -2799,6 +3457,60 @@ func _Lightning_GetNetworkInfo_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Lightning_GossipBudgetUsage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GossipBudgetUsageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).GossipBudgetUsage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/GossipBudgetUsage",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).GossipBudgetUsage(ctx, req.(*GossipBudgetUsageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_ExportGraphSnapshot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportGraphSnapshotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).ExportGraphSnapshot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/ExportGraphSnapshot",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).ExportGraphSnapshot(ctx, req.(*ExportGraphSnapshotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_ImportGraphSnapshot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ImportGraphSnapshotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).ImportGraphSnapshot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/ImportGraphSnapshot",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).ImportGraphSnapshot(ctx, req.(*ImportGraphSnapshotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Lightning_StopDaemon_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(StopRequest)
 	if err := dec(in); err != nil {
@@ -3003,6 +3715,24 @@ func (x *lightningSubscribeChannelBackupsServer) Send(m *ChanBackupSnapshot) err
 	return x.ServerStream.SendMsg(m)
 }
 
+func _Lightning_BackupSinkStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BackupSinkStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).BackupSinkStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/BackupSinkStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).BackupSinkStatus(ctx, req.(*BackupSinkStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Lightning_BakeMacaroon_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(BakeMacaroonRequest)
 	if err := dec(in); err != nil {
@@ -3253,6 +3983,18 @@ var Lightning_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ListPeers",
 			Handler:    _Lightning_ListPeers_Handler,
 		},
+		{
+			MethodName: "AddPeerPolicyEntry",
+			Handler:    _Lightning_AddPeerPolicyEntry_Handler,
+		},
+		{
+			MethodName: "RemovePeerPolicyEntry",
+			Handler:    _Lightning_RemovePeerPolicyEntry_Handler,
+		},
+		{
+			MethodName: "ListPeerPolicy",
+			Handler:    _Lightning_ListPeerPolicy_Handler,
+		},
 		{
 			MethodName: "GetInfo",
 			Handler:    _Lightning_GetInfo_Handler,
@@ -3285,10 +4027,38 @@ var Lightning_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "FundingStateStep",
 			Handler:    _Lightning_FundingStateStep_Handler,
 		},
+		{
+			MethodName: "SimulateForceClose",
+			Handler:    _Lightning_SimulateForceClose_Handler,
+		},
+		{
+			MethodName: "BumpCloseFee",
+			Handler:    _Lightning_BumpCloseFee_Handler,
+		},
+		{
+			MethodName: "ListHtlcDeadlines",
+			Handler:    _Lightning_ListHtlcDeadlines_Handler,
+		},
 		{
 			MethodName: "AbandonChannel",
 			Handler:    _Lightning_AbandonChannel_Handler,
 		},
+		{
+			MethodName: "UpgradeChannelCommitment",
+			Handler:    _Lightning_UpgradeChannelCommitment_Handler,
+		},
+		{
+			MethodName: "UpdateLiquidityAd",
+			Handler:    _Lightning_UpdateLiquidityAd_Handler,
+		},
+		{
+			MethodName: "RequestLease",
+			Handler:    _Lightning_RequestLease_Handler,
+		},
+		{
+			MethodName: "ListLeases",
+			Handler:    _Lightning_ListLeases_Handler,
+		},
 		{
 			MethodName: "SendPaymentSync",
 			Handler:    _Lightning_SendPaymentSync_Handler,
@@ -3349,6 +4119,18 @@ var Lightning_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetNetworkInfo",
 			Handler:    _Lightning_GetNetworkInfo_Handler,
 		},
+		{
+			MethodName: "GossipBudgetUsage",
+			Handler:    _Lightning_GossipBudgetUsage_Handler,
+		},
+		{
+			MethodName: "ExportGraphSnapshot",
+			Handler:    _Lightning_ExportGraphSnapshot_Handler,
+		},
+		{
+			MethodName: "ImportGraphSnapshot",
+			Handler:    _Lightning_ImportGraphSnapshot_Handler,
+		},
 		{
 			MethodName: "StopDaemon",
 			Handler:    _Lightning_StopDaemon_Handler,
@@ -3385,6 +4167,10 @@ var Lightning_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "RestoreChannelBackups",
 			Handler:    _Lightning_RestoreChannelBackups_Handler,
 		},
+		{
+			MethodName: "BackupSinkStatus",
+			Handler:    _Lightning_BackupSinkStatus_Handler,
+		},
 		{
 			MethodName: "BakeMacaroon",
 			Handler:    _Lightning_BakeMacaroon_Handler,
@@ -3429,6 +4215,11 @@ var Lightning_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _Lightning_SubscribePeerEvents_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "SubscribePeerStats",
+			Handler:       _Lightning_SubscribePeerStats_Handler,
+			ServerStreams: true,
+		},
 		{
 			StreamName:    "SubscribeChannelEvents",
 			Handler:       _Lightning_SubscribeChannelEvents_Handler,
@@ -3450,6 +4241,11 @@ var Lightning_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _Lightning_CloseChannel_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "SubscribeResolverProgress",
+			Handler:       _Lightning_SubscribeResolverProgress_Handler,
+			ServerStreams: true,
+		},
 		{
 			StreamName:    "SendPayment",
 			Handler:       _Lightning_SendPayment_Handler,
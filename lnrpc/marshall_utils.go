@@ -4,6 +4,7 @@ import (
 	"encoding/hex"
 	"errors"
 	fmt "fmt"
+	"math"
 
 	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/chaincfg"
@@ -179,3 +180,34 @@ func MarshalOutPoint(op *wire.OutPoint) *OutPoint {
 		OutputIndex: op.Index,
 	}
 }
+
+// MarshallChannelType converts a channel type to its proto counterpart.
+func MarshallChannelType(chanType lnwire.ChannelType) *ChannelType {
+	featureBits := chanType.FeatureBits()
+	bits := make([]uint32, 0, len(featureBits))
+	for _, bit := range featureBits {
+		bits = append(bits, uint32(bit))
+	}
+
+	return &ChannelType{FeatureBits: bits}
+}
+
+// UnmarshallChannelType converts a proto channel type to a channel type. As a
+// channel type is comprised of the required bits of its features, an error is
+// returned if any of the feature bits is optional.
+func UnmarshallChannelType(chanType *ChannelType) (lnwire.ChannelType,
+	error) {
+
+	fv := lnwire.NewRawFeatureVector()
+	for _, bit := range chanType.FeatureBits {
+		featureBit := lnwire.FeatureBit(bit)
+		if bit > math.MaxUint16 || !featureBit.IsRequired() {
+			return lnwire.ChannelType{}, fmt.Errorf("invalid "+
+				"channel type feature bit: %v", bit)
+		}
+
+		fv.Set(featureBit)
+	}
+
+	return lnwire.ChannelType(*fv), nil
+}
@@ -6,6 +6,7 @@ package peersrpc
 import (
 	"net"
 
+	"github.com/lightningnetwork/lnd/feature"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/netann"
 )
@@ -29,4 +30,12 @@ type Config struct {
 	// vector should be provided.
 	UpdateNodeAnnouncement func(features *lnwire.RawFeatureVector,
 		mods ...netann.NodeAnnModifier) error
+
+	// ListFeatures returns the status of the features of a feature set.
+	ListFeatures func(set feature.Set) []feature.Status
+
+	// ToggleFeatures enables or disables optional feature bits at
+	// runtime, broadcasting a new node announcement if its features
+	// changed.
+	ToggleFeatures func(toggles []feature.Toggle) error
 }
@@ -22,6 +22,18 @@ type PeersClient interface {
 	// UpdateNodeAnnouncement allows the caller to update the node parameters
 	// and broadcasts a new version of the node announcement to its peers.
 	UpdateNodeAnnouncement(ctx context.Context, in *NodeAnnouncementUpdateRequest, opts ...grpc.CallOption) (*NodeAnnouncementUpdateResponse, error)
+	// lncli: peers listfeatures
+	// ListFeatures lists the standard features and any custom features of each
+	// feature set, along with whether they are enabled, whether they can be
+	// toggled at runtime and the features they depend on.
+	ListFeatures(ctx context.Context, in *ListFeaturesRequest, opts ...grpc.CallOption) (*ListFeaturesResponse, error)
+	// lncli: peers updatefeatures
+	// UpdateFeatures enables or disables optional feature bits at runtime.
+	// Standard features can only be enabled in the sets they were advertised in
+	// at startup, and the resulting sets must satisfy all feature dependencies.
+	// If the node announcement features change, a new node announcement is
+	// broadcast. Init features only apply to new connections.
+	UpdateFeatures(ctx context.Context, in *UpdateFeaturesRequest, opts ...grpc.CallOption) (*UpdateFeaturesResponse, error)
 }
 
 type peersClient struct {
@@ -41,6 +53,24 @@ func (c *peersClient) UpdateNodeAnnouncement(ctx context.Context, in *NodeAnnoun
 	return out, nil
 }
 
+func (c *peersClient) ListFeatures(ctx context.Context, in *ListFeaturesRequest, opts ...grpc.CallOption) (*ListFeaturesResponse, error) {
+	out := new(ListFeaturesResponse)
+	err := c.cc.Invoke(ctx, "/peersrpc.Peers/ListFeatures", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *peersClient) UpdateFeatures(ctx context.Context, in *UpdateFeaturesRequest, opts ...grpc.CallOption) (*UpdateFeaturesResponse, error) {
+	out := new(UpdateFeaturesResponse)
+	err := c.cc.Invoke(ctx, "/peersrpc.Peers/UpdateFeatures", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // PeersServer is the server API for Peers service.
 // All implementations must embed UnimplementedPeersServer
 // for forward compatibility
@@ -49,6 +79,18 @@ type PeersServer interface {
 	// UpdateNodeAnnouncement allows the caller to update the node parameters
 	// and broadcasts a new version of the node announcement to its peers.
 	UpdateNodeAnnouncement(context.Context, *NodeAnnouncementUpdateRequest) (*NodeAnnouncementUpdateResponse, error)
+	// lncli: peers listfeatures
+	// ListFeatures lists the standard features and any custom features of each
+	// feature set, along with whether they are enabled, whether they can be
+	// toggled at runtime and the features they depend on.
+	ListFeatures(context.Context, *ListFeaturesRequest) (*ListFeaturesResponse, error)
+	// lncli: peers updatefeatures
+	// UpdateFeatures enables or disables optional feature bits at runtime.
+	// Standard features can only be enabled in the sets they were advertised in
+	// at startup, and the resulting sets must satisfy all feature dependencies.
+	// If the node announcement features change, a new node announcement is
+	// broadcast. Init features only apply to new connections.
+	UpdateFeatures(context.Context, *UpdateFeaturesRequest) (*UpdateFeaturesResponse, error)
 	mustEmbedUnimplementedPeersServer()
 }
 
@@ -59,6 +101,12 @@ type UnimplementedPeersServer struct {
 func (UnimplementedPeersServer) UpdateNodeAnnouncement(context.Context, *NodeAnnouncementUpdateRequest) (*NodeAnnouncementUpdateResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method UpdateNodeAnnouncement not implemented")
 }
+func (UnimplementedPeersServer) ListFeatures(context.Context, *ListFeaturesRequest) (*ListFeaturesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListFeatures not implemented")
+}
+func (UnimplementedPeersServer) UpdateFeatures(context.Context, *UpdateFeaturesRequest) (*UpdateFeaturesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateFeatures not implemented")
+}
 func (UnimplementedPeersServer) mustEmbedUnimplementedPeersServer() {}
 
 // UnsafePeersServer may be embedded to opt out of forward compatibility for this service.
@@ -90,6 +138,42 @@ func _Peers_UpdateNodeAnnouncement_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Peers_ListFeatures_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListFeaturesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PeersServer).ListFeatures(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/peersrpc.Peers/ListFeatures",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PeersServer).ListFeatures(ctx, req.(*ListFeaturesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Peers_UpdateFeatures_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateFeaturesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PeersServer).UpdateFeatures(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/peersrpc.Peers/UpdateFeatures",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PeersServer).UpdateFeatures(ctx, req.(*UpdateFeaturesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Peers_ServiceDesc is the grpc.ServiceDesc for Peers service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -101,6 +185,14 @@ var Peers_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "UpdateNodeAnnouncement",
 			Handler:    _Peers_UpdateNodeAnnouncement_Handler,
 		},
+		{
+			MethodName: "ListFeatures",
+			Handler:    _Peers_ListFeatures_Handler,
+		},
+		{
+			MethodName: "UpdateFeatures",
+			Handler:    _Peers_UpdateFeatures_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "peersrpc/peers.proto",
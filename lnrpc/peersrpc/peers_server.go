@@ -34,6 +34,14 @@ var (
 			Entity: "peers",
 			Action: "write",
 		}},
+		"/peersrpc.Peers/ListFeatures": {{
+			Entity: "peers",
+			Action: "read",
+		}},
+		"/peersrpc.Peers/UpdateFeatures": {{
+			Entity: "peers",
+			Action: "write",
+		}},
 	}
 )
 
@@ -400,3 +408,151 @@ func (s *Server) UpdateNodeAnnouncement(_ context.Context,
 
 	return resp, nil
 }
+
+// unmarshallFeatureSet converts an RPC feature set to its feature package
+// counterpart.
+func unmarshallFeatureSet(set FeatureSet) (feature.Set, error) {
+	switch set {
+	case FeatureSet_SET_INIT:
+		return feature.SetInit, nil
+
+	case FeatureSet_SET_LEGACY_GLOBAL:
+		return feature.SetLegacyGlobal, nil
+
+	case FeatureSet_SET_NODE_ANN:
+		return feature.SetNodeAnn, nil
+
+	case FeatureSet_SET_INVOICE:
+		return feature.SetInvoice, nil
+
+	case FeatureSet_SET_INVOICE_AMP:
+		return feature.SetInvoiceAmp, nil
+
+	default:
+		return 0, fmt.Errorf("unknown feature set: %v", set)
+	}
+}
+
+// marshallFeatureSetStatus returns the status of the features of a set.
+func (s *Server) marshallFeatureSetStatus(set FeatureSet) (*FeatureSetStatus,
+	error) {
+
+	featureSet, err := unmarshallFeatureSet(set)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := s.cfg.ListFeatures(featureSet)
+	rpcStatus := &FeatureSetStatus{
+		Set:      set,
+		Features: make([]*FeatureStatus, 0, len(statuses)),
+	}
+	for _, status := range statuses {
+		deps := marshallFeatureBits(status.Dependencies)
+		chain := marshallFeatureBits(status.DependencyChain)
+
+		rpcStatus.Features = append(rpcStatus.Features, &FeatureStatus{
+			Bit:             uint32(status.Bit),
+			Name:            status.Name,
+			IsKnown:         status.Known,
+			Enabled:         status.Enabled,
+			IsRequired:      status.Bit.IsRequired(),
+			Configured:      status.Configured,
+			Toggleable:      status.Toggleable,
+			Dependencies:    deps,
+			DependencyChain: chain,
+		})
+	}
+
+	return rpcStatus, nil
+}
+
+// marshallFeatureBits converts a list of feature bits to their RPC
+// representation.
+func marshallFeatureBits(bits []lnwire.FeatureBit) []uint32 {
+	rpcBits := make([]uint32, 0, len(bits))
+	for _, bit := range bits {
+		rpcBits = append(rpcBits, uint32(bit))
+	}
+
+	return rpcBits
+}
+
+// ListFeatures lists the standard features and any custom features of each
+// feature set, along with whether they are enabled, whether they can be
+// toggled at runtime and the features they depend on.
+func (s *Server) ListFeatures(_ context.Context,
+	_ *ListFeaturesRequest) (*ListFeaturesResponse, error) {
+
+	resp := &ListFeaturesResponse{}
+	for i := 0; i < len(FeatureSet_name); i++ {
+		status, err := s.marshallFeatureSetStatus(FeatureSet(i))
+		if err != nil {
+			return nil, err
+		}
+		resp.Sets = append(resp.Sets, status)
+	}
+
+	return resp, nil
+}
+
+// UpdateFeatures enables or disables optional feature bits at runtime. If the
+// node announcement features change, a new node announcement is broadcast.
+func (s *Server) UpdateFeatures(_ context.Context,
+	req *UpdateFeaturesRequest) (*UpdateFeaturesResponse, error) {
+
+	if len(req.Updates) == 0 {
+		return nil, fmt.Errorf("no feature updates specified")
+	}
+
+	var (
+		toggles     []feature.Toggle
+		updatedSets = make(map[FeatureSet]struct{})
+		resp        = &UpdateFeaturesResponse{}
+	)
+	for _, update := range req.Updates {
+		set, err := unmarshallFeatureSet(update.Set)
+		if err != nil {
+			return nil, err
+		}
+
+		toggle := feature.Toggle{
+			Set: set,
+			Bit: lnwire.FeatureBit(update.FeatureBit),
+		}
+		switch update.Action {
+		case UpdateAction_ADD:
+			toggle.Enable = true
+
+		case UpdateAction_REMOVE:
+
+		default:
+			return nil, fmt.Errorf("invalid update action (%v) "+
+				"for bit %v", update.Action, update.FeatureBit)
+		}
+
+		toggles = append(toggles, toggle)
+		updatedSets[update.Set] = struct{}{}
+		if set == feature.SetNodeAnn {
+			resp.NodeAnnouncementUpdated = true
+		}
+	}
+
+	if err := s.cfg.ToggleFeatures(toggles); err != nil {
+		return nil, fmt.Errorf("unable to update features: %w", err)
+	}
+
+	for i := 0; i < len(FeatureSet_name); i++ {
+		if _, ok := updatedSets[FeatureSet(i)]; !ok {
+			continue
+		}
+
+		status, err := s.marshallFeatureSetStatus(FeatureSet(i))
+		if err != nil {
+			return nil, err
+		}
+		resp.Sets = append(resp.Sets, status)
+	}
+
+	return resp, nil
+}
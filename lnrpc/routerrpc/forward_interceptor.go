@@ -8,6 +8,7 @@ import (
 	"github.com/lightningnetwork/lnd/lnrpc"
 	"github.com/lightningnetwork/lnd/lntypes"
 	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/record"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
@@ -20,25 +21,44 @@ var (
 	// ErrMissingPreimage is an error returned when the caller tries to settle
 	// a forward and doesn't provide a preimage.
 	ErrMissingPreimage = errors.New("missing preimage")
+
+	// ErrMissingRegistration is returned when the first message on an
+	// HtlcInterceptorV2 stream isn't a registration.
+	ErrMissingRegistration = errors.New("interceptor registration must " +
+		"be the first message")
+
+	// ErrDuplicateRegistration is returned when an HtlcInterceptorV2
+	// stream attempts to register more than once.
+	ErrDuplicateRegistration = errors.New("interceptor already registered")
 )
 
+// interceptRequestSender is the part of the interceptor streams that sends
+// intercepted htlcs to the client.
+type interceptRequestSender interface {
+	Send(*ForwardHtlcInterceptRequest) error
+}
+
 // forwardInterceptor is a helper struct that handles the lifecycle of an rpc
 // interceptor streaming session.
 // It is created when the stream opens and disconnects when the stream closes.
 type forwardInterceptor struct {
 	// stream is the bidirectional RPC stream
-	stream Router_HtlcInterceptorServer
+	stream interceptRequestSender
 
 	htlcSwitch htlcswitch.InterceptableHtlcForwarder
+
+	// id identifies the interceptor in resolutions.
+	id htlcswitch.InterceptorID
 }
 
 // newForwardInterceptor creates a new forwardInterceptor.
 func newForwardInterceptor(htlcSwitch htlcswitch.InterceptableHtlcForwarder,
-	stream Router_HtlcInterceptorServer) *forwardInterceptor {
+	stream interceptRequestSender) *forwardInterceptor {
 
 	return &forwardInterceptor{
 		htlcSwitch: htlcSwitch,
 		stream:     stream,
+		id:         htlcswitch.DefaultInterceptorID,
 	}
 }
 
@@ -48,13 +68,13 @@ func newForwardInterceptor(htlcSwitch htlcswitch.InterceptableHtlcForwarder,
 // to read from the client stream.
 // To coordinate all this and make sure it is safe for concurrent access all
 // packets are sent to the main where they are handled.
-func (r *forwardInterceptor) run() error {
+func (r *forwardInterceptor) run(stream Router_HtlcInterceptorServer) error {
 	// Register our interceptor so we receive all forwarded packets.
 	r.htlcSwitch.SetInterceptor(r.onIntercept)
 	defer r.htlcSwitch.SetInterceptor(nil)
 
 	for {
-		resp, err := r.stream.Recv()
+		resp, err := stream.Recv()
 		if err != nil {
 			return err
 		}
@@ -65,6 +85,112 @@ func (r *forwardInterceptor) run() error {
 	}
 }
 
+// runV2 is the equivalent of run for an HtlcInterceptorV2 stream. It waits
+// for the client to register the interceptor and then applies the batches of
+// resolutions that the client sends.
+func (r *forwardInterceptor) runV2(
+	stream Router_HtlcInterceptorV2Server) error {
+
+	req, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+
+	register := req.GetRegister()
+	if register == nil {
+		return status.Error(
+			codes.InvalidArgument, ErrMissingRegistration.Error(),
+		)
+	}
+
+	registration, err := unmarshallInterceptorRegistration(register)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	registration.Interceptor = r.onIntercept
+
+	r.id, err = r.htlcSwitch.RegisterInterceptor(registration)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err := r.htlcSwitch.UnregisterInterceptor(r.id)
+		if err != nil {
+			log.Errorf("Cannot unregister interceptor %v: %v",
+				r.id, err)
+		}
+	}()
+
+	for {
+		req, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+
+		batch := req.GetResolutions()
+		if batch == nil {
+			return status.Error(
+				codes.InvalidArgument,
+				ErrDuplicateRegistration.Error(),
+			)
+		}
+
+		resolutions := make(
+			[]*htlcswitch.FwdResolution, 0,
+			len(batch.Resolutions),
+		)
+		for _, in := range batch.Resolutions {
+			res, err := r.unmarshallResolution(in)
+			if err != nil {
+				return err
+			}
+
+			resolutions = append(resolutions, res)
+		}
+
+		if err := r.htlcSwitch.ResolveBatch(resolutions); err != nil {
+			return err
+		}
+	}
+}
+
+// unmarshallInterceptorRegistration converts an rpc interceptor registration
+// into its switch equivalent. The interceptor callback is left unset.
+func unmarshallInterceptorRegistration(
+	in *InterceptorRegistration) (*htlcswitch.InterceptorRegistration,
+	error) {
+
+	registration := &htlcswitch.InterceptorRegistration{
+		Priority: in.Priority,
+	}
+
+	if in.Filter == nil {
+		return registration, nil
+	}
+
+	filter := &registration.Filter
+	for _, chanID := range in.Filter.IncomingChanIds {
+		filter.IncomingChanIDs = append(
+			filter.IncomingChanIDs,
+			lnwire.NewShortChanIDFromInt(chanID),
+		)
+	}
+
+	filter.MinIncomingAmount = lnwire.MilliSatoshi(
+		in.Filter.MinIncomingAmountMsat,
+	)
+	filter.MaxIncomingAmount = lnwire.MilliSatoshi(
+		in.Filter.MaxIncomingAmountMsat,
+	)
+	filter.CustomRecords = in.Filter.CustomRecordTypes
+
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+
+	return registration, nil
+}
+
 // onIntercept is the function that is called by the switch for every forwarded
 // packet. Our interceptor makes sure we hold the packet and then signal to the
 // main loop to handle the packet. We only return true if we were able
@@ -91,6 +217,7 @@ func (r *forwardInterceptor) onIntercept(
 		CustomRecords:           htlc.CustomRecords,
 		OnionBlob:               htlc.OnionBlob[:],
 		AutoFailHeight:          htlc.AutoFailHeight,
+		InWireCustomRecords:     htlc.InWireCustomRecords,
 	}
 
 	return r.stream.Send(interceptionRequest)
@@ -100,8 +227,21 @@ func (r *forwardInterceptor) onIntercept(
 func (r *forwardInterceptor) resolveFromClient(
 	in *ForwardHtlcInterceptResponse) error {
 
+	res, err := r.unmarshallResolution(in)
+	if err != nil {
+		return err
+	}
+
+	return r.htlcSwitch.Resolve(res)
+}
+
+// unmarshallResolution converts a resolution arrived from the client into a
+// resolution for the switch.
+func (r *forwardInterceptor) unmarshallResolution(
+	in *ForwardHtlcInterceptResponse) (*htlcswitch.FwdResolution, error) {
+
 	if in.IncomingCircuitKey == nil {
-		return status.Errorf(codes.InvalidArgument,
+		return nil, status.Errorf(codes.InvalidArgument,
 			"CircuitKey missing from ForwardHtlcInterceptResponse")
 	}
 
@@ -112,18 +252,53 @@ func (r *forwardInterceptor) resolveFromClient(
 		HtlcID: in.IncomingCircuitKey.HtlcId,
 	}
 
+	res := &htlcswitch.FwdResolution{
+		Key:         circuitKey,
+		Interceptor: r.id,
+	}
+
 	switch in.Action {
 	case ResolveHoldForwardAction_RESUME:
-		return r.htlcSwitch.Resolve(&htlcswitch.FwdResolution{
-			Key:    circuitKey,
-			Action: htlcswitch.FwdActionResume,
-		})
+		res.Action = htlcswitch.FwdActionResume
+
+		return res, nil
+
+	case ResolveHoldForwardAction_RESUME_MODIFIED:
+		mods := &htlcswitch.FwdModifications{}
+
+		if in.OutAmountMsat != 0 {
+			amt := lnwire.MilliSatoshi(in.OutAmountMsat)
+			mods.OutgoingAmount = &amt
+		}
+
+		if in.OutChanId != 0 {
+			chanID := lnwire.NewShortChanIDFromInt(in.OutChanId)
+			mods.OutgoingChanID = &chanID
+		}
+
+		if len(in.OutWireCustomRecords) > 0 {
+			records := record.CustomSet(in.OutWireCustomRecords)
+			if err := records.Validate(); err != nil {
+				return nil, status.Error(
+					codes.InvalidArgument, err.Error(),
+				)
+			}
+
+			mods.OutgoingWireCustomRecords = records
+		}
+
+		res.Action = htlcswitch.FwdActionResumeModified
+		res.Modifications = mods
+
+		return res, nil
 
 	case ResolveHoldForwardAction_FAIL:
+		res.Action = htlcswitch.FwdActionFail
+
 		// Fail with an encrypted reason.
 		if in.FailureMessage != nil {
 			if in.FailureCode != 0 {
-				return status.Errorf(
+				return nil, status.Errorf(
 					codes.InvalidArgument,
 					"failure message and failure code "+
 						"are mutually exclusive",
@@ -136,64 +311,56 @@ func (r *forwardInterceptor) resolveFromClient(
 			if len(in.FailureMessage) !=
 				lnwire.FailureMessageLength+32+2+2 {
 
-				return status.Errorf(
+				return nil, status.Errorf(
 					codes.InvalidArgument,
 					"failure message length invalid",
 				)
 			}
 
-			return r.htlcSwitch.Resolve(&htlcswitch.FwdResolution{
-				Key:            circuitKey,
-				Action:         htlcswitch.FwdActionFail,
-				FailureMessage: in.FailureMessage,
-			})
+			res.FailureMessage = in.FailureMessage
+
+			return res, nil
 		}
 
-		var code lnwire.FailCode
 		switch in.FailureCode {
 		case lnrpc.Failure_INVALID_ONION_HMAC:
-			code = lnwire.CodeInvalidOnionHmac
+			res.FailureCode = lnwire.CodeInvalidOnionHmac
 
 		case lnrpc.Failure_INVALID_ONION_KEY:
-			code = lnwire.CodeInvalidOnionKey
+			res.FailureCode = lnwire.CodeInvalidOnionKey
 
 		case lnrpc.Failure_INVALID_ONION_VERSION:
-			code = lnwire.CodeInvalidOnionVersion
+			res.FailureCode = lnwire.CodeInvalidOnionVersion
 
 		// Default to TemporaryChannelFailure.
 		case 0, lnrpc.Failure_TEMPORARY_CHANNEL_FAILURE:
-			code = lnwire.CodeTemporaryChannelFailure
+			res.FailureCode = lnwire.CodeTemporaryChannelFailure
 
 		default:
-			return status.Errorf(
+			return nil, status.Errorf(
 				codes.InvalidArgument,
 				"unsupported failure code: %v", in.FailureCode,
 			)
 		}
 
-		return r.htlcSwitch.Resolve(&htlcswitch.FwdResolution{
-			Key:         circuitKey,
-			Action:      htlcswitch.FwdActionFail,
-			FailureCode: code,
-		})
+		return res, nil
 
 	case ResolveHoldForwardAction_SETTLE:
 		if in.Preimage == nil {
-			return ErrMissingPreimage
+			return nil, ErrMissingPreimage
 		}
 		preimage, err := lntypes.MakePreimage(in.Preimage)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
-		return r.htlcSwitch.Resolve(&htlcswitch.FwdResolution{
-			Key:      circuitKey,
-			Action:   htlcswitch.FwdActionSettle,
-			Preimage: preimage,
-		})
+		res.Action = htlcswitch.FwdActionSettle
+		res.Preimage = preimage
+
+		return res, nil
 
 	default:
-		return status.Errorf(
+		return nil, status.Errorf(
 			codes.InvalidArgument,
 			"unrecognized resolve action %v", in.Action,
 		)
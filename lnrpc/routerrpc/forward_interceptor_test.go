@@ -0,0 +1,89 @@
+package routerrpc
+
+import (
+	"testing"
+
+	"github.com/lightningnetwork/lnd/htlcswitch"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/record"
+	"github.com/stretchr/testify/require"
+)
+
+// TestUnmarshallResumeModified tests the conversion of a modified resume
+// resolution into its switch equivalent.
+func TestUnmarshallResumeModified(t *testing.T) {
+	t.Parallel()
+
+	interceptor := &forwardInterceptor{id: 3}
+
+	in := &ForwardHtlcInterceptResponse{
+		IncomingCircuitKey: &CircuitKey{ChanId: 1, HtlcId: 2},
+		Action:             ResolveHoldForwardAction_RESUME_MODIFIED,
+		OutAmountMsat:      1000,
+		OutChanId:          5,
+		OutWireCustomRecords: map[uint64][]byte{
+			record.CustomTypeStart: {1, 2},
+		},
+	}
+
+	res, err := interceptor.unmarshallResolution(in)
+	require.NoError(t, err)
+
+	outAmt := lnwire.MilliSatoshi(1000)
+	outChanID := lnwire.NewShortChanIDFromInt(5)
+	require.Equal(t, htlcswitch.InterceptorID(3), res.Interceptor)
+	require.Equal(t, htlcswitch.FwdActionResumeModified, res.Action)
+	require.Equal(t, &htlcswitch.FwdModifications{
+		OutgoingAmount: &outAmt,
+		OutgoingChanID: &outChanID,
+		OutgoingWireCustomRecords: record.CustomSet{
+			record.CustomTypeStart: {1, 2},
+		},
+	}, res.Modifications)
+
+	// Records outside of the custom range are rejected.
+	in.OutWireCustomRecords = map[uint64][]byte{1: {1}}
+	_, err = interceptor.unmarshallResolution(in)
+	require.Error(t, err)
+}
+
+// TestUnmarshallInterceptorRegistration tests the conversion of an
+// interceptor registration into its switch equivalent.
+func TestUnmarshallInterceptorRegistration(t *testing.T) {
+	t.Parallel()
+
+	registration, err := unmarshallInterceptorRegistration(
+		&InterceptorRegistration{
+			Priority: 7,
+			Filter: &InterceptFilter{
+				IncomingChanIds:       []uint64{1},
+				MinIncomingAmountMsat: 10,
+				MaxIncomingAmountMsat: 20,
+				CustomRecordTypes: []uint64{
+					record.CustomTypeStart,
+				},
+			},
+		},
+	)
+	require.NoError(t, err)
+	require.Equal(t, &htlcswitch.InterceptorRegistration{
+		Priority: 7,
+		Filter: htlcswitch.InterceptorFilter{
+			IncomingChanIDs: []lnwire.ShortChannelID{
+				lnwire.NewShortChanIDFromInt(1),
+			},
+			MinIncomingAmount: 10,
+			MaxIncomingAmount: 20,
+			CustomRecords:     []uint64{record.CustomTypeStart},
+		},
+	}, registration)
+
+	// An empty amount range is rejected.
+	_, err = unmarshallInterceptorRegistration(&InterceptorRegistration{
+		Filter: &InterceptFilter{
+			MinIncomingAmountMsat: 20,
+			MaxIncomingAmountMsat: 10,
+		},
+	})
+	require.Error(t, err)
+}
@@ -101,6 +101,10 @@ type RouterBackend struct {
 	// SetChannelAuto exposes the ability to restore automatic channel state
 	// management after manually setting channel status.
 	SetChannelAuto func(wire.OutPoint) error
+
+	// FetchRateLimitStats returns a snapshot of the rate limit counters of
+	// the htlc switch.
+	FetchRateLimitStats func() *htlcswitch.RateLimitStats
 }
 
 // MissionControl defines the mission control dependencies of routerrpc.
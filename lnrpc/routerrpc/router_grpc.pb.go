@@ -95,11 +95,23 @@ type RouterClient interface {
 	// In case of interception, the htlc can be either settled, cancelled or
 	// resumed later by using the ResolveHoldForward endpoint.
 	HtlcInterceptor(ctx context.Context, opts ...grpc.CallOption) (Router_HtlcInterceptorClient, error)
+	// HtlcInterceptorV2 dispatches a bi-directional streaming RPC that registers
+	// an additional htlc interceptor. The first message on the stream must
+	// register the interceptor with a priority and an optional filter. Multiple
+	// interceptors can be connected concurrently. Forwarded htlcs are offered to
+	// the highest priority interceptor whose filter matches. If it resumes the
+	// htlc, it is offered to the next matching interceptor before it is
+	// forwarded. Resolutions are sent in batches.
+	HtlcInterceptorV2(ctx context.Context, opts ...grpc.CallOption) (Router_HtlcInterceptorV2Client, error)
 	// UpdateChanStatus attempts to manually set the state of a channel
 	// (enabled, disabled, or auto). A manual "disable" request will cause the
 	// channel to stay disabled until a subsequent manual request of either
 	// "enable" or "auto".
 	UpdateChanStatus(ctx context.Context, in *UpdateChanStatusRequest, opts ...grpc.CallOption) (*UpdateChanStatusResponse, error)
+	// GetSwitchStats returns the statistics of the htlc switch. This includes
+	// the outcome of the rate limit checks of forwarded htlcs for every incoming
+	// peer and outgoing channel.
+	GetSwitchStats(ctx context.Context, in *GetSwitchStatsRequest, opts ...grpc.CallOption) (*GetSwitchStatsResponse, error)
 }
 
 type routerClient struct {
@@ -426,6 +438,37 @@ func (x *routerHtlcInterceptorClient) Recv() (*ForwardHtlcInterceptRequest, erro
 	return m, nil
 }
 
+func (c *routerClient) HtlcInterceptorV2(ctx context.Context, opts ...grpc.CallOption) (Router_HtlcInterceptorV2Client, error) {
+	stream, err := c.cc.NewStream(ctx, &Router_ServiceDesc.Streams[7], "/routerrpc.Router/HtlcInterceptorV2", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &routerHtlcInterceptorV2Client{stream}
+	return x, nil
+}
+
+type Router_HtlcInterceptorV2Client interface {
+	Send(*HtlcInterceptorV2Request) error
+	Recv() (*ForwardHtlcInterceptRequest, error)
+	grpc.ClientStream
+}
+
+type routerHtlcInterceptorV2Client struct {
+	grpc.ClientStream
+}
+
+func (x *routerHtlcInterceptorV2Client) Send(m *HtlcInterceptorV2Request) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *routerHtlcInterceptorV2Client) Recv() (*ForwardHtlcInterceptRequest, error) {
+	m := new(ForwardHtlcInterceptRequest)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func (c *routerClient) UpdateChanStatus(ctx context.Context, in *UpdateChanStatusRequest, opts ...grpc.CallOption) (*UpdateChanStatusResponse, error) {
 	out := new(UpdateChanStatusResponse)
 	err := c.cc.Invoke(ctx, "/routerrpc.Router/UpdateChanStatus", in, out, opts...)
@@ -435,6 +478,15 @@ func (c *routerClient) UpdateChanStatus(ctx context.Context, in *UpdateChanStatu
 	return out, nil
 }
 
+func (c *routerClient) GetSwitchStats(ctx context.Context, in *GetSwitchStatsRequest, opts ...grpc.CallOption) (*GetSwitchStatsResponse, error) {
+	out := new(GetSwitchStatsResponse)
+	err := c.cc.Invoke(ctx, "/routerrpc.Router/GetSwitchStats", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // RouterServer is the server API for Router service.
 // All implementations must embed UnimplementedRouterServer
 // for forward compatibility
@@ -515,11 +567,23 @@ type RouterServer interface {
 	// In case of interception, the htlc can be either settled, cancelled or
 	// resumed later by using the ResolveHoldForward endpoint.
 	HtlcInterceptor(Router_HtlcInterceptorServer) error
+	// HtlcInterceptorV2 dispatches a bi-directional streaming RPC that registers
+	// an additional htlc interceptor. The first message on the stream must
+	// register the interceptor with a priority and an optional filter. Multiple
+	// interceptors can be connected concurrently. Forwarded htlcs are offered to
+	// the highest priority interceptor whose filter matches. If it resumes the
+	// htlc, it is offered to the next matching interceptor before it is
+	// forwarded. Resolutions are sent in batches.
+	HtlcInterceptorV2(Router_HtlcInterceptorV2Server) error
 	// UpdateChanStatus attempts to manually set the state of a channel
 	// (enabled, disabled, or auto). A manual "disable" request will cause the
 	// channel to stay disabled until a subsequent manual request of either
 	// "enable" or "auto".
 	UpdateChanStatus(context.Context, *UpdateChanStatusRequest) (*UpdateChanStatusResponse, error)
+	// GetSwitchStats returns the statistics of the htlc switch. This includes
+	// the outcome of the rate limit checks of forwarded htlcs for every incoming
+	// peer and outgoing channel.
+	GetSwitchStats(context.Context, *GetSwitchStatsRequest) (*GetSwitchStatsResponse, error)
 	mustEmbedUnimplementedRouterServer()
 }
 
@@ -578,9 +642,15 @@ func (UnimplementedRouterServer) TrackPayment(*TrackPaymentRequest, Router_Track
 func (UnimplementedRouterServer) HtlcInterceptor(Router_HtlcInterceptorServer) error {
 	return status.Errorf(codes.Unimplemented, "method HtlcInterceptor not implemented")
 }
+func (UnimplementedRouterServer) HtlcInterceptorV2(Router_HtlcInterceptorV2Server) error {
+	return status.Errorf(codes.Unimplemented, "method HtlcInterceptorV2 not implemented")
+}
 func (UnimplementedRouterServer) UpdateChanStatus(context.Context, *UpdateChanStatusRequest) (*UpdateChanStatusResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method UpdateChanStatus not implemented")
 }
+func (UnimplementedRouterServer) GetSwitchStats(context.Context, *GetSwitchStatsRequest) (*GetSwitchStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSwitchStats not implemented")
+}
 func (UnimplementedRouterServer) mustEmbedUnimplementedRouterServer() {}
 
 // UnsafeRouterServer may be embedded to opt out of forward compatibility for this service.
@@ -926,6 +996,32 @@ func (x *routerHtlcInterceptorServer) Recv() (*ForwardHtlcInterceptResponse, err
 	return m, nil
 }
 
+func _Router_HtlcInterceptorV2_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(RouterServer).HtlcInterceptorV2(&routerHtlcInterceptorV2Server{stream})
+}
+
+type Router_HtlcInterceptorV2Server interface {
+	Send(*ForwardHtlcInterceptRequest) error
+	Recv() (*HtlcInterceptorV2Request, error)
+	grpc.ServerStream
+}
+
+type routerHtlcInterceptorV2Server struct {
+	grpc.ServerStream
+}
+
+func (x *routerHtlcInterceptorV2Server) Send(m *ForwardHtlcInterceptRequest) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *routerHtlcInterceptorV2Server) Recv() (*HtlcInterceptorV2Request, error) {
+	m := new(HtlcInterceptorV2Request)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func _Router_UpdateChanStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(UpdateChanStatusRequest)
 	if err := dec(in); err != nil {
@@ -944,6 +1040,24 @@ func _Router_UpdateChanStatus_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Router_GetSwitchStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSwitchStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RouterServer).GetSwitchStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/routerrpc.Router/GetSwitchStats",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RouterServer).GetSwitchStats(ctx, req.(*GetSwitchStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Router_ServiceDesc is the grpc.ServiceDesc for Router service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -995,6 +1109,10 @@ var Router_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "UpdateChanStatus",
 			Handler:    _Router_UpdateChanStatus_Handler,
 		},
+		{
+			MethodName: "GetSwitchStats",
+			Handler:    _Router_GetSwitchStats_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -1033,6 +1151,12 @@ var Router_ServiceDesc = grpc.ServiceDesc{
 			ServerStreams: true,
 			ClientStreams: true,
 		},
+		{
+			StreamName:    "HtlcInterceptorV2",
+			Handler:       _Router_HtlcInterceptorV2_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
 	},
 	Metadata: "routerrpc/router.proto",
 }
@@ -1,12 +1,14 @@
 package routerrpc
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"sort"
 	"sync/atomic"
 	"time"
 
@@ -14,6 +16,7 @@ import (
 	"github.com/btcsuite/btcd/wire"
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/htlcswitch"
 	"github.com/lightningnetwork/lnd/lnrpc"
 	"github.com/lightningnetwork/lnd/lntypes"
 	"github.com/lightningnetwork/lnd/lnwire"
@@ -124,10 +127,18 @@ var (
 			Entity: "offchain",
 			Action: "write",
 		}},
+		"/routerrpc.Router/HtlcInterceptorV2": {{
+			Entity: "offchain",
+			Action: "write",
+		}},
 		"/routerrpc.Router/UpdateChanStatus": {{
 			Entity: "offchain",
 			Action: "write",
 		}},
+		"/routerrpc.Router/GetSwitchStats": {{
+			Entity: "offchain",
+			Action: "read",
+		}},
 	}
 
 	// DefaultRouterMacFilename is the default name of the router macaroon
@@ -1065,7 +1076,16 @@ func (s *Server) HtlcInterceptor(stream Router_HtlcInterceptorServer) error {
 	// run the forward interceptor.
 	return newForwardInterceptor(
 		s.cfg.RouterBackend.InterceptableForwarder, stream,
-	).run()
+	).run(stream)
+}
+
+// HtlcInterceptorV2 is a bidirectional stream that registers an additional
+// interceptor with a priority and a filter. Unlike HtlcInterceptor, multiple
+// of these streams can be active at the same time.
+func (s *Server) HtlcInterceptorV2(stream Router_HtlcInterceptorV2Server) error {
+	return newForwardInterceptor(
+		s.cfg.RouterBackend.InterceptableForwarder, stream,
+	).runV2(stream)
 }
 
 func extractOutPoint(req *UpdateChanStatusRequest) (*wire.OutPoint, error) {
@@ -1108,3 +1128,72 @@ func (s *Server) UpdateChanStatus(ctx context.Context,
 	}
 	return &UpdateChanStatusResponse{}, nil
 }
+
+// GetSwitchStats returns the statistics of the htlc switch, including the
+// outcome of the rate limit checks of every incoming peer and outgoing
+// channel.
+func (s *Server) GetSwitchStats(_ context.Context,
+	_ *GetSwitchStatsRequest) (*GetSwitchStatsResponse, error) {
+
+	stats := s.cfg.RouterBackend.FetchRateLimitStats()
+
+	var policy RateLimitPolicy
+	switch stats.Policy {
+	case htlcswitch.RateLimitFail:
+		policy = RateLimitPolicy_RATE_LIMIT_FAIL
+
+	case htlcswitch.RateLimitQueue:
+		policy = RateLimitPolicy_RATE_LIMIT_QUEUE
+
+	default:
+		return nil, fmt.Errorf("unknown rate limit policy: %v",
+			stats.Policy)
+	}
+
+	resp := &GetSwitchStatsResponse{
+		RateLimitPolicy: policy,
+	}
+
+	for peer, counters := range stats.Peers {
+		peer := peer
+		resp.PeerRateLimits = append(
+			resp.PeerRateLimits, &PeerRateLimitStats{
+				Peer:     peer[:],
+				Counters: rpcRateLimitCounters(counters),
+			},
+		)
+	}
+	sort.Slice(resp.PeerRateLimits, func(i, j int) bool {
+		return bytes.Compare(
+			resp.PeerRateLimits[i].Peer,
+			resp.PeerRateLimits[j].Peer,
+		) < 0
+	})
+
+	for scid, counters := range stats.Channels {
+		resp.ChannelRateLimits = append(
+			resp.ChannelRateLimits, &ChannelRateLimitStats{
+				ChanId:   scid.ToUint64(),
+				Counters: rpcRateLimitCounters(counters),
+			},
+		)
+	}
+	sort.Slice(resp.ChannelRateLimits, func(i, j int) bool {
+		return resp.ChannelRateLimits[i].ChanId <
+			resp.ChannelRateLimits[j].ChanId
+	})
+
+	return resp, nil
+}
+
+// rpcRateLimitCounters converts the rate limit counters of the switch to their
+// rpc representation.
+func rpcRateLimitCounters(
+	counters htlcswitch.RateLimitCounters) *RateLimitCounters {
+
+	return &RateLimitCounters{
+		Forwarded: counters.Forwarded,
+		Queued:    counters.Queued,
+		Rejected:  counters.Rejected,
+	}
+}
@@ -122,10 +122,12 @@ func rpcHtlcEvent(htlcEvent interface{}) (*HtlcEvent, error) {
 // switch's htlc info struct.
 func rpcInfo(info htlcswitch.HtlcInfo) *HtlcInfo {
 	return &HtlcInfo{
-		IncomingTimelock: info.IncomingTimeLock,
-		OutgoingTimelock: info.OutgoingTimeLock,
-		IncomingAmtMsat:  uint64(info.IncomingAmt),
-		OutgoingAmtMsat:  uint64(info.OutgoingAmt),
+		IncomingTimelock:          info.IncomingTimeLock,
+		OutgoingTimelock:          info.OutgoingTimeLock,
+		IncomingAmtMsat:           uint64(info.IncomingAmt),
+		OutgoingAmtMsat:           uint64(info.OutgoingAmt),
+		IncomingWireCustomRecords: info.IncomingWireCustomRecords,
+		OutgoingWireCustomRecords: info.OutgoingWireCustomRecords,
 	}
 }
 
@@ -254,6 +256,9 @@ func rpcOutgoingFailure(failureDetail htlcswitch.OutgoingFailure) (
 	case htlcswitch.OutgoingFailureForwardsDisabled:
 		return FailureDetail_FORWARDS_DISABLED, nil
 
+	case htlcswitch.OutgoingFailureRateLimited:
+		return FailureDetail_RATE_LIMITED, nil
+
 	default:
 		return 0, fmt.Errorf("unknown outgoing failure "+
 			"detail: %v", failureDetail.FailureString())
@@ -7,6 +7,7 @@ import (
 	"github.com/btcsuite/btcd/chaincfg"
 	"github.com/lightningnetwork/lnd/keychain"
 	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/lnwallet/anchorreserve"
 	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
 	"github.com/lightningnetwork/lnd/macaroons"
 	"github.com/lightningnetwork/lnd/sweep"
@@ -71,4 +72,8 @@ type Config struct {
 	// CurrentNumAnchorChans returns the current number of non-private
 	// anchor channels the wallet should be ready to fee bump if needed.
 	CurrentNumAnchorChans func() (int, error)
+
+	// AnchorReserve plans the wallet reserve needed to fee bump all anchor
+	// channels at once.
+	AnchorReserve *anchorreserve.Manager
 }
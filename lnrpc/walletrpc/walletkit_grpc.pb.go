@@ -53,6 +53,18 @@ type WalletKitClient interface {
 	// in the wallet in order to fee bump anchor channels if necessary. The value
 	// scales with the number of public anchor channels but is capped at a maximum.
 	RequiredReserve(ctx context.Context, in *RequiredReserveRequest, opts ...grpc.CallOption) (*RequiredReserveResponse, error)
+	// lncli: `wallet anchorreserve`
+	// AnchorReserve plans the wallet reserve needed to fee bump all anchor
+	// channels if they were force closed at once at the current fee estimate.
+	// Unlike RequiredReserve, the reserve is derived from the actual commitments
+	// of the channels, and the wallet outputs are assigned to the individual
+	// channels as the sweeper would need them.
+	AnchorReserve(ctx context.Context, in *AnchorReserveRequest, opts ...grpc.CallOption) (*AnchorReservePlan, error)
+	// SubscribeAnchorReserve returns a stream that receives a new anchor reserve
+	// plan each time the periodic planning changes the required reserve, the
+	// deficit or the set of reserved outputs. The current plan is sent first.
+	// This can be used to get notified when the wallet becomes under-reserved.
+	SubscribeAnchorReserve(ctx context.Context, in *SubscribeAnchorReserveRequest, opts ...grpc.CallOption) (WalletKit_SubscribeAnchorReserveClient, error)
 	// ListAddresses retrieves all the addresses along with their balance. An
 	// account name filter can be provided to filter through all of the
 	// wallet accounts and return the addresses of only those matching.
@@ -327,6 +339,47 @@ func (c *walletKitClient) RequiredReserve(ctx context.Context, in *RequiredReser
 	return out, nil
 }
 
+func (c *walletKitClient) AnchorReserve(ctx context.Context, in *AnchorReserveRequest, opts ...grpc.CallOption) (*AnchorReservePlan, error) {
+	out := new(AnchorReservePlan)
+	err := c.cc.Invoke(ctx, "/walletrpc.WalletKit/AnchorReserve", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletKitClient) SubscribeAnchorReserve(ctx context.Context, in *SubscribeAnchorReserveRequest, opts ...grpc.CallOption) (WalletKit_SubscribeAnchorReserveClient, error) {
+	stream, err := c.cc.NewStream(ctx, &WalletKit_ServiceDesc.Streams[0], "/walletrpc.WalletKit/SubscribeAnchorReserve", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &walletKitSubscribeAnchorReserveClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type WalletKit_SubscribeAnchorReserveClient interface {
+	Recv() (*AnchorReservePlan, error)
+	grpc.ClientStream
+}
+
+type walletKitSubscribeAnchorReserveClient struct {
+	grpc.ClientStream
+}
+
+func (x *walletKitSubscribeAnchorReserveClient) Recv() (*AnchorReservePlan, error) {
+	m := new(AnchorReservePlan)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func (c *walletKitClient) ListAddresses(ctx context.Context, in *ListAddressesRequest, opts ...grpc.CallOption) (*ListAddressesResponse, error) {
 	out := new(ListAddressesResponse)
 	err := c.cc.Invoke(ctx, "/walletrpc.WalletKit/ListAddresses", in, out, opts...)
@@ -509,6 +562,18 @@ type WalletKitServer interface {
 	// in the wallet in order to fee bump anchor channels if necessary. The value
 	// scales with the number of public anchor channels but is capped at a maximum.
 	RequiredReserve(context.Context, *RequiredReserveRequest) (*RequiredReserveResponse, error)
+	// lncli: `wallet anchorreserve`
+	// AnchorReserve plans the wallet reserve needed to fee bump all anchor
+	// channels if they were force closed at once at the current fee estimate.
+	// Unlike RequiredReserve, the reserve is derived from the actual commitments
+	// of the channels, and the wallet outputs are assigned to the individual
+	// channels as the sweeper would need them.
+	AnchorReserve(context.Context, *AnchorReserveRequest) (*AnchorReservePlan, error)
+	// SubscribeAnchorReserve returns a stream that receives a new anchor reserve
+	// plan each time the periodic planning changes the required reserve, the
+	// deficit or the set of reserved outputs. The current plan is sent first.
+	// This can be used to get notified when the wallet becomes under-reserved.
+	SubscribeAnchorReserve(*SubscribeAnchorReserveRequest, WalletKit_SubscribeAnchorReserveServer) error
 	// ListAddresses retrieves all the addresses along with their balance. An
 	// account name filter can be provided to filter through all of the
 	// wallet accounts and return the addresses of only those matching.
@@ -726,6 +791,12 @@ func (UnimplementedWalletKitServer) ListAccounts(context.Context, *ListAccountsR
 func (UnimplementedWalletKitServer) RequiredReserve(context.Context, *RequiredReserveRequest) (*RequiredReserveResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method RequiredReserve not implemented")
 }
+func (UnimplementedWalletKitServer) AnchorReserve(context.Context, *AnchorReserveRequest) (*AnchorReservePlan, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AnchorReserve not implemented")
+}
+func (UnimplementedWalletKitServer) SubscribeAnchorReserve(*SubscribeAnchorReserveRequest, WalletKit_SubscribeAnchorReserveServer) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeAnchorReserve not implemented")
+}
 func (UnimplementedWalletKitServer) ListAddresses(context.Context, *ListAddressesRequest) (*ListAddressesResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListAddresses not implemented")
 }
@@ -949,6 +1020,45 @@ func _WalletKit_RequiredReserve_Handler(srv interface{}, ctx context.Context, de
 	return interceptor(ctx, in, info, handler)
 }
 
+func _WalletKit_AnchorReserve_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AnchorReserveRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletKitServer).AnchorReserve(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/walletrpc.WalletKit/AnchorReserve",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletKitServer).AnchorReserve(ctx, req.(*AnchorReserveRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WalletKit_SubscribeAnchorReserve_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeAnchorReserveRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(WalletKitServer).SubscribeAnchorReserve(m, &walletKitSubscribeAnchorReserveServer{stream})
+}
+
+type WalletKit_SubscribeAnchorReserveServer interface {
+	Send(*AnchorReservePlan) error
+	grpc.ServerStream
+}
+
+type walletKitSubscribeAnchorReserveServer struct {
+	grpc.ServerStream
+}
+
+func (x *walletKitSubscribeAnchorReserveServer) Send(m *AnchorReservePlan) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 func _WalletKit_ListAddresses_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ListAddressesRequest)
 	if err := dec(in); err != nil {
@@ -1280,6 +1390,10 @@ var WalletKit_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "RequiredReserve",
 			Handler:    _WalletKit_RequiredReserve_Handler,
 		},
+		{
+			MethodName: "AnchorReserve",
+			Handler:    _WalletKit_AnchorReserve_Handler,
+		},
 		{
 			MethodName: "ListAddresses",
 			Handler:    _WalletKit_ListAddresses_Handler,
@@ -1345,6 +1459,12 @@ var WalletKit_ServiceDesc = grpc.ServiceDesc{
 			Handler:    _WalletKit_FinalizePsbt_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SubscribeAnchorReserve",
+			Handler:       _WalletKit_SubscribeAnchorReserve_Handler,
+			ServerStreams: true,
+		},
+	},
 	Metadata: "walletrpc/walletkit.proto",
 }
@@ -36,6 +36,7 @@ import (
 	"github.com/lightningnetwork/lnd/lnrpc"
 	"github.com/lightningnetwork/lnd/lnrpc/signrpc"
 	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/lnwallet/anchorreserve"
 	"github.com/lightningnetwork/lnd/lnwallet/btcwallet"
 	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
 	"github.com/lightningnetwork/lnd/macaroons"
@@ -156,6 +157,14 @@ var (
 			Entity: "onchain",
 			Action: "read",
 		}},
+		"/walletrpc.WalletKit/AnchorReserve": {{
+			Entity: "onchain",
+			Action: "read",
+		}},
+		"/walletrpc.WalletKit/SubscribeAnchorReserve": {{
+			Entity: "onchain",
+			Action: "read",
+		}},
 		"/walletrpc.WalletKit/ImportAccount": {{
 			Entity: "onchain",
 			Action: "write",
@@ -1596,6 +1605,108 @@ func (w *WalletKit) RequiredReserve(ctx context.Context,
 	}, nil
 }
 
+// AnchorReserve plans the wallet reserve needed to fee bump all anchor
+// channels if they were force closed at once at the current fee estimate.
+func (w *WalletKit) AnchorReserve(_ context.Context,
+	_ *AnchorReserveRequest) (*AnchorReservePlan, error) {
+
+	plan, err := w.cfg.AnchorReserve.Plan()
+	if err != nil {
+		return nil, err
+	}
+
+	return w.marshallAnchorReservePlan(plan), nil
+}
+
+// SubscribeAnchorReserve streams a new anchor reserve plan each time the
+// periodic planning changes its outcome, starting with the current plan.
+func (w *WalletKit) SubscribeAnchorReserve(_ *SubscribeAnchorReserveRequest,
+	stream WalletKit_SubscribeAnchorReserveServer) error {
+
+	client, err := w.cfg.AnchorReserve.SubscribePlans()
+	if err != nil {
+		return err
+	}
+	defer client.Cancel()
+
+	plan, err := w.cfg.AnchorReserve.Plan()
+	if err != nil {
+		return err
+	}
+
+	err = stream.Send(w.marshallAnchorReservePlan(plan))
+	if err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case update := <-client.Updates():
+			plan, ok := update.(*anchorreserve.Plan)
+			if !ok {
+				return fmt.Errorf("unexpected anchor reserve "+
+					"update: %T", update)
+			}
+
+			err := stream.Send(w.marshallAnchorReservePlan(plan))
+			if err != nil {
+				return err
+			}
+
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+
+		case <-client.Quit():
+			return errors.New("anchor reserve subscription " +
+				"terminated")
+		}
+	}
+}
+
+// marshallAnchorReservePlan converts an anchor reserve plan into its RPC
+// representation.
+func (w *WalletKit) marshallAnchorReservePlan(
+	plan *anchorreserve.Plan) *AnchorReservePlan {
+
+	rpcPlan := &AnchorReservePlan{
+		SatPerKw:            uint64(plan.FeeRate),
+		ConfTarget:          w.cfg.AnchorReserve.ConfTarget(),
+		RequiredReserveSat:  int64(plan.RequiredReserve),
+		AvailableBalanceSat: int64(plan.AvailableBalance),
+		DeficitSat:          int64(plan.Deficit),
+		UnderReserved:       plan.UnderReserved(),
+		AutoLock:            w.cfg.AnchorReserve.AutoLock(),
+	}
+
+	for _, chanReserve := range plan.Channels {
+		rpcChanReserve := &ChannelAnchorReserve{
+			ChannelPoint:   chanReserve.ChanPoint.String(),
+			CommitWeight:   chanReserve.CommitWeight,
+			CommitFeeSat:   int64(chanReserve.CommitFee),
+			NumHtlcs:       uint32(chanReserve.NumHTLCs),
+			RequiredFeeSat: int64(chanReserve.RequiredFee),
+			Covered:        chanReserve.Covered,
+		}
+		for i := range chanReserve.Utxos {
+			rpcChanReserve.Utxos = append(
+				rpcChanReserve.Utxos,
+				lnrpc.MarshalOutPoint(&chanReserve.Utxos[i]),
+			)
+		}
+
+		rpcPlan.Channels = append(rpcPlan.Channels, rpcChanReserve)
+	}
+
+	for _, op := range w.cfg.AnchorReserve.LockedOutputs() {
+		op := op
+		rpcPlan.LockedUtxos = append(
+			rpcPlan.LockedUtxos, lnrpc.MarshalOutPoint(&op),
+		)
+	}
+
+	return rpcPlan
+}
+
 // ListAddresses retrieves all the addresses along with their balance. An
 // account name filter can be provided to filter through all of the
 // wallet accounts and return the addresses of only those matching.
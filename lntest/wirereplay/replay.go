@@ -0,0 +1,243 @@
+package wirereplay
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/lightningnetwork/lnd/brontide"
+	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/wirecapture"
+)
+
+// DefaultDialTimeout is the default timeout for connecting to the node.
+const DefaultDialTimeout = 30 * time.Second
+
+// ErrDisconnected is returned when the node closes the connection before all
+// messages have been replayed.
+var ErrDisconnected = errors.New("connection closed by node")
+
+// Config holds the configuration of a replay.
+type Config struct {
+	// Addr is the address of the node to replay the capture against.
+	Addr *lnwire.NetAddress
+
+	// Key is the identity key used to connect to the node. If nil, an
+	// ephemeral key is generated.
+	Key keychain.SingleKeyECDH
+
+	// Direction selects the captured messages that are sent to the node.
+	// Replaying the inbound messages, the default, lets the node take the
+	// role of the node that made the capture, while replaying outbound
+	// messages lets it take the role of the captured peer.
+	Direction wirecapture.Direction
+
+	// Speed scales the delays in between the captured messages. A speed
+	// of 2 replays the capture twice as fast as it was recorded. If zero,
+	// messages are sent without any delay.
+	Speed float64
+
+	// Linger is how long the connection is kept open after the last
+	// message was sent, so that the responses of the node can be
+	// observed.
+	Linger time.Duration
+
+	// DialTimeout is the timeout for connecting to the node. If zero,
+	// DefaultDialTimeout is used.
+	DialTimeout time.Duration
+
+	// OnMessage, if non-nil, is called with every message received from
+	// the node. Messages that can't be decoded are passed as nil along
+	// with the decoding error.
+	OnMessage func(lnwire.Message, error)
+}
+
+// Result summarizes a replay.
+type Result struct {
+	// Sent is the number of captured messages sent to the node.
+	Sent int
+
+	// Skipped is the number of captured messages in the replayed direction
+	// that weren't sent, because they are pings or pongs which are
+	// answered live instead.
+	Skipped int
+
+	// Received is the number of messages received from the node.
+	Received int
+}
+
+// skipRecord returns true if a captured message in the replayed direction
+// shouldn't be sent. Pings and pongs are tied to the timing of the original
+// connection, so we answer the pings of the node ourselves instead.
+func skipRecord(record *wirecapture.Record) bool {
+	switch record.MsgType() {
+	case lnwire.MsgPing, lnwire.MsgPong:
+		return true
+
+	default:
+		return false
+	}
+}
+
+// replayConn wraps the connection to the node, serializing writes as both the
+// replay and the answers to pings write to it.
+type replayConn struct {
+	*brontide.Conn
+
+	mu sync.Mutex
+}
+
+// send writes a raw message to the connection.
+func (c *replayConn) send(msg []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.WriteMessage(msg); err != nil {
+		return err
+	}
+
+	_, err := c.Flush()
+	return err
+}
+
+// readMessages reads the messages sent by the node until the connection is
+// closed, answering pings and counting the messages received.
+func readMessages(conn *replayConn, cfg *Config, received *int) error {
+	for {
+		rawMsg, err := conn.ReadNextMessage()
+		if err != nil {
+			return err
+		}
+		*received++
+
+		msg, err := lnwire.ReadMessage(bytes.NewReader(rawMsg), 0)
+		if cfg.OnMessage != nil {
+			cfg.OnMessage(msg, err)
+		}
+
+		ping, ok := msg.(*lnwire.Ping)
+		if !ok {
+			continue
+		}
+
+		var b bytes.Buffer
+		pong := lnwire.NewPong(make([]byte, ping.NumPongBytes))
+		if _, err := lnwire.WriteMessage(&b, pong, 0); err != nil {
+			return err
+		}
+		if err := conn.send(b.Bytes()); err != nil {
+			return err
+		}
+	}
+}
+
+// Replay connects to a node and sends it the captured messages of the
+// configured direction, in order and as they were captured, including the
+// init message and messages that can't be decoded. Note that messages
+// carrying signatures only verify against a node with the same keys and state
+// as the node that made the capture, so replays are best suited to reproduce
+// issues that occur before signatures are checked, or against a node
+// restored to that state.
+func Replay(ctx context.Context, cfg *Config,
+	records []*wirecapture.Record) (*Result, error) {
+
+	key := cfg.Key
+	if key == nil {
+		privKey, err := btcec.NewPrivateKey()
+		if err != nil {
+			return nil, err
+		}
+		key = &keychain.PrivKeyECDH{PrivKey: privKey}
+	}
+
+	dialTimeout := cfg.DialTimeout
+	if dialTimeout == 0 {
+		dialTimeout = DefaultDialTimeout
+	}
+
+	brontideConn, err := brontide.Dial(
+		key, cfg.Addr, dialTimeout, net.DialTimeout,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to connect to %v: %w", cfg.Addr,
+			err)
+	}
+	conn := &replayConn{Conn: brontideConn}
+
+	var (
+		result  Result
+		readErr error
+	)
+	readDone := make(chan struct{})
+	go func() {
+		defer close(readDone)
+		readErr = readMessages(conn, cfg, &result.Received)
+	}()
+
+	// Once the replay is done, we'll close the connection and wait for the
+	// reader to exit, so that the result is final.
+	finish := func(err error) (*Result, error) {
+		conn.Close()
+		<-readDone
+
+		return &result, err
+	}
+
+	// wait blocks for the given duration, returning an error if the
+	// replay should be aborted.
+	wait := func(d time.Duration) error {
+		timer := time.NewTimer(d)
+		defer timer.Stop()
+
+		select {
+		case <-timer.C:
+			return nil
+
+		case <-readDone:
+			return fmt.Errorf("%w after %d messages: %v",
+				ErrDisconnected, result.Sent, readErr)
+
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	var last time.Time
+	for _, record := range records {
+		if record.Direction != cfg.Direction {
+			continue
+		}
+
+		if skipRecord(record) {
+			result.Skipped++
+			continue
+		}
+
+		if cfg.Speed > 0 && !last.IsZero() {
+			delay := record.Timestamp.Sub(last)
+			delay = time.Duration(float64(delay) / cfg.Speed)
+			if err := wait(delay); err != nil {
+				return finish(err)
+			}
+		}
+		last = record.Timestamp
+
+		if err := conn.send(record.Msg); err != nil {
+			return finish(fmt.Errorf("unable to send message %d: "+
+				"%w", result.Sent, err))
+		}
+		result.Sent++
+	}
+
+	if err := wait(cfg.Linger); err != nil {
+		return finish(err)
+	}
+
+	return finish(nil)
+}
@@ -0,0 +1,137 @@
+package wirereplay
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/lightningnetwork/lnd/brontide"
+	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/wirecapture"
+	"github.com/stretchr/testify/require"
+)
+
+// encodeMsg returns the raw encoding of a wire message.
+func encodeMsg(t *testing.T, msg lnwire.Message) []byte {
+	t.Helper()
+
+	var b bytes.Buffer
+	_, err := lnwire.WriteMessage(&b, msg, 0)
+	require.NoError(t, err)
+
+	return b.Bytes()
+}
+
+// TestReplay asserts that the captured messages of the replayed direction are
+// sent to the node in order, and that pings of the node are answered.
+func TestReplay(t *testing.T) {
+	t.Parallel()
+
+	privKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	nodeKey := &keychain.PrivKeyECDH{PrivKey: privKey}
+
+	listener, err := brontide.NewListener(nodeKey, "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	initMsg := encodeMsg(t, lnwire.NewInitMessage(
+		lnwire.NewRawFeatureVector(), lnwire.NewRawFeatureVector(),
+	))
+	invalidMsg := []byte{0x00, 0x10, 0xff}
+	start := time.Unix(1000, 0)
+	records := []*wirecapture.Record{{
+		Timestamp: start,
+		Direction: wirecapture.Inbound,
+		Msg:       initMsg,
+	}, {
+		Timestamp: start,
+		Direction: wirecapture.Outbound,
+		Msg:       initMsg,
+	}, {
+		Timestamp: start.Add(time.Second),
+		Direction: wirecapture.Inbound,
+		Msg:       encodeMsg(t, lnwire.NewPong(nil)),
+	}, {
+		Timestamp: start.Add(2 * time.Second),
+		Direction: wirecapture.Inbound,
+		Msg:       invalidMsg,
+	}}
+
+	// The node sends us a ping once it received the init message, and
+	// then reads the invalid message and our pong.
+	ping := encodeMsg(t, &lnwire.Ping{NumPongBytes: 3})
+	nodeReceived := make(chan [][]byte, 1)
+	nodeErr := make(chan error, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			nodeErr <- err
+			return
+		}
+		defer conn.Close()
+		brontideConn := conn.(*brontide.Conn)
+
+		var received [][]byte
+		for i := 0; i < 3; i++ {
+			msg, err := brontideConn.ReadNextMessage()
+			if err != nil {
+				nodeErr <- err
+				return
+			}
+			received = append(received, msg)
+
+			if i > 0 {
+				continue
+			}
+
+			err = brontideConn.WriteMessage(ping)
+			if err == nil {
+				_, err = brontideConn.Flush()
+			}
+			if err != nil {
+				nodeErr <- err
+				return
+			}
+		}
+
+		nodeReceived <- received
+	}()
+
+	var nodeMsgs []lnwire.Message
+	result, err := Replay(context.Background(), &Config{
+		Addr: &lnwire.NetAddress{
+			IdentityKey: privKey.PubKey(),
+			Address:     listener.Addr(),
+		},
+		Speed:  1000,
+		Linger: time.Second,
+		OnMessage: func(msg lnwire.Message, err error) {
+			nodeMsgs = append(nodeMsgs, msg)
+		},
+	}, records)
+
+	// The node closing the connection after the replay ends the linger
+	// period early.
+	require.ErrorIs(t, err, ErrDisconnected)
+	require.Equal(t, &Result{Sent: 2, Skipped: 1, Received: 1}, result)
+	require.Len(t, nodeMsgs, 1)
+	require.IsType(t, &lnwire.Ping{}, nodeMsgs[0])
+
+	var received [][]byte
+	select {
+	case received = <-nodeReceived:
+	case err := <-nodeErr:
+		t.Fatalf("node failed: %v", err)
+	}
+
+	// The order of the pong and the invalid message depends on when the
+	// ping is received.
+	require.Equal(t, initMsg, received[0])
+	require.ElementsMatch(t, [][]byte{
+		invalidMsg, encodeMsg(t, lnwire.NewPong(make([]byte, 3))),
+	}, received[1:])
+}
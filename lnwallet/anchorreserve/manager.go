@@ -0,0 +1,496 @@
+package anchorreserve
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/wire"
+	base "github.com/btcsuite/btcwallet/wallet"
+	"github.com/btcsuite/btcwallet/wtxmgr"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+	"github.com/lightningnetwork/lnd/subscribe"
+	"github.com/lightningnetwork/lnd/sweep"
+	"github.com/lightningnetwork/lnd/ticker"
+)
+
+var (
+	// LockID is the binary representation of the SHA256 hash of the
+	// string "lnd-anchor-reserve-lock-id" and is used for the UTXO leases
+	// of outputs that are reserved for anchor fee bumping. The ID
+	// corresponds to the hex value of
+	// c8d8e7df15e1136d722ede634f082b537ef9ab884c83d3e44bf0d4d172f8c08f.
+	LockID = wtxmgr.LockID{
+		0xc8, 0xd8, 0xe7, 0xdf, 0x15, 0xe1, 0x13, 0x6d,
+		0x72, 0x2e, 0xde, 0x63, 0x4f, 0x08, 0x2b, 0x53,
+		0x7e, 0xf9, 0xab, 0x88, 0x4c, 0x83, 0xd3, 0xe4,
+		0x4b, 0xf0, 0xd4, 0xd1, 0x72, 0xf8, 0xc0, 0x8f,
+	}
+
+	// ErrManagerShuttingDown is returned when the manager is asked for a
+	// plan while it is shutting down.
+	ErrManagerShuttingDown = errors.New("anchor reserve manager shutting " +
+		"down")
+)
+
+// WalletController is the subset of the lnwallet.WalletController that the
+// manager needs to inspect and lease wallet outputs.
+type WalletController interface {
+	// ListUnspentWitness returns all unspent witness outputs of the
+	// given account with a number of confirmations in the given range.
+	ListUnspentWitness(minConfs, maxConfs int32,
+		accountFilter string) ([]*lnwallet.Utxo, error)
+
+	// LeaseOutput locks an output to the given ID for the given
+	// duration.
+	LeaseOutput(id wtxmgr.LockID, op wire.OutPoint,
+		duration time.Duration) (time.Time, []byte, btcutil.Amount,
+		error)
+
+	// ReleaseOutput unlocks an output that was locked to the given ID.
+	ReleaseOutput(id wtxmgr.LockID, op wire.OutPoint) error
+
+	// ListLeasedOutputs returns a list of all currently locked outputs.
+	ListLeasedOutputs() ([]*base.ListLeasedOutputResult, error)
+}
+
+// Config houses all the dependencies of the Manager.
+type Config struct {
+	// FetchChannels returns all open channels of the node.
+	FetchChannels func() ([]*channeldb.OpenChannel, error)
+
+	// IsClosing returns true if any channel is in the process of being
+	// closed on-chain and may need wallet outputs to sweep its anchor or
+	// HTLC outputs.
+	IsClosing func() (bool, error)
+
+	// Wallet is used to list and lease the outputs of the wallet.
+	Wallet WalletController
+
+	// CoinSelectionLocker is used to make sure the outputs we lease aren't
+	// selected by a concurrent coin selection.
+	CoinSelectionLocker sweep.CoinSelectionLocker
+
+	// FeeEstimator is used to estimate the fee rate the commitments need
+	// to be bumped to.
+	FeeEstimator chainfee.Estimator
+
+	// ConfTarget is the confirmation target used for the fee estimate.
+	ConfTarget uint32
+
+	// AutoLock indicates whether the outputs assigned to anchor channels
+	// should be leased, so they aren't spent by other wallet operations.
+	AutoLock bool
+
+	// LeaseDuration is the duration of the leases of reserved outputs.
+	// Leases are extended on each planning run, so this should be longer
+	// than the interval of the Ticker. Expiring leases make sure outputs
+	// don't stay locked forever if lnd is shut down.
+	LeaseDuration time.Duration
+
+	// Ticker is used to periodically re-run the reserve planning.
+	Ticker ticker.Ticker
+}
+
+// Manager periodically plans the wallet reserve needed to fee bump all anchor
+// channels, notifies subscribers when the outcome changes, and optionally
+// leases the assigned wallet outputs so they remain available for fee
+// bumping.
+type Manager struct {
+	started sync.Once
+	stopped sync.Once
+
+	cfg Config
+
+	// mu guards the fields below and serializes planning runs with the
+	// release of the reserve.
+	mu sync.Mutex
+
+	// plan is the outcome of the latest planning run.
+	plan *Plan
+
+	// locked is the set of outputs that are currently leased by us.
+	locked map[wire.OutPoint]struct{}
+
+	ntfnServer *subscribe.Server
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewManager creates a new anchor reserve manager.
+func NewManager(cfg Config) *Manager {
+	return &Manager{
+		cfg:        cfg,
+		locked:     make(map[wire.OutPoint]struct{}),
+		ntfnServer: subscribe.NewServer(),
+		quit:       make(chan struct{}),
+	}
+}
+
+// Start runs an initial planning run and starts the periodic planning.
+func (m *Manager) Start() error {
+	var startErr error
+	m.started.Do(func() {
+		log.Info("Anchor reserve manager starting")
+
+		if err := m.ntfnServer.Start(); err != nil {
+			startErr = err
+			return
+		}
+
+		// Pick up the leases of a previous run, so they're either
+		// extended or released by the first planning run.
+		if err := m.loadLeases(); err != nil {
+			startErr = err
+			return
+		}
+
+		if _, err := m.evaluate(); err != nil {
+			log.Errorf("Unable to plan anchor reserve: %v", err)
+		}
+
+		m.cfg.Ticker.Resume()
+
+		m.wg.Add(1)
+		go m.planner()
+	})
+
+	return startErr
+}
+
+// Stop stops the periodic planning. Leased outputs remain locked until their
+// leases expire.
+func (m *Manager) Stop() error {
+	var stopErr error
+	m.stopped.Do(func() {
+		log.Info("Anchor reserve manager shutting down...")
+		defer log.Debug("Anchor reserve manager shutdown complete")
+
+		m.cfg.Ticker.Stop()
+		close(m.quit)
+		m.wg.Wait()
+
+		stopErr = m.ntfnServer.Stop()
+	})
+
+	return stopErr
+}
+
+// planner re-runs the reserve planning on every tick.
+//
+// NOTE: This MUST be run as a goroutine.
+func (m *Manager) planner() {
+	defer m.wg.Done()
+
+	for {
+		select {
+		case <-m.cfg.Ticker.Ticks():
+			if _, err := m.evaluate(); err != nil {
+				log.Errorf("Unable to plan anchor reserve: %v",
+					err)
+			}
+
+		case <-m.quit:
+			return
+		}
+	}
+}
+
+// Plan runs a fresh planning run and returns its outcome.
+func (m *Manager) Plan() (*Plan, error) {
+	select {
+	case <-m.quit:
+		return nil, ErrManagerShuttingDown
+	default:
+	}
+
+	return m.evaluate()
+}
+
+// LockedOutputs returns the outputs that are currently leased for anchor fee
+// bumping.
+func (m *Manager) LockedOutputs() []wire.OutPoint {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	outpoints := make([]wire.OutPoint, 0, len(m.locked))
+	for op := range m.locked {
+		outpoints = append(outpoints, op)
+	}
+
+	return outpoints
+}
+
+// AutoLock returns true if the manager leases the outputs it reserves.
+func (m *Manager) AutoLock() bool {
+	return m.cfg.AutoLock
+}
+
+// ConfTarget returns the confirmation target used to plan the reserve.
+func (m *Manager) ConfTarget() uint32 {
+	return m.cfg.ConfTarget
+}
+
+// SubscribePlans returns a client that receives a new *Plan each time a
+// planning run changes the required reserve, the deficit or the set of
+// reserved outputs.
+func (m *Manager) SubscribePlans() (*subscribe.Client, error) {
+	return m.ntfnServer.Subscribe()
+}
+
+// ReleaseReserve releases all outputs leased for anchor fee bumping, making
+// them available to the sweeper. This must be called before anchors are
+// offered to the sweeper, as leased outputs are hidden from its coin
+// selection. The outputs won't be locked again as long as any channel is
+// being closed.
+func (m *Manager) ReleaseReserve() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if len(m.locked) == 0 {
+		return
+	}
+
+	log.Infof("Releasing %d outputs reserved for anchor fee bumping",
+		len(m.locked))
+
+	err := m.cfg.CoinSelectionLocker.WithCoinSelectLock(func() error {
+		m.releaseLeases(m.locked)
+		return nil
+	})
+	if err != nil {
+		log.Errorf("Unable to release anchor reserve: %v", err)
+	}
+}
+
+// loadLeases populates the set of locked outputs with the leases we hold in
+// the wallet.
+func (m *Manager) loadLeases() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.cfg.CoinSelectionLocker.WithCoinSelectLock(func() error {
+		leases, err := m.cfg.Wallet.ListLeasedOutputs()
+		if err != nil {
+			return fmt.Errorf("unable to list leased outputs: %w",
+				err)
+		}
+
+		for _, lease := range leases {
+			if lease.LockID == LockID {
+				m.locked[lease.Outpoint] = struct{}{}
+			}
+		}
+
+		return nil
+	})
+}
+
+// evaluate runs a planning run, updates the leases of the reserved outputs if
+// needed and notifies subscribers if the outcome changed.
+func (m *Manager) evaluate() (*Plan, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	channels, err := m.cfg.FetchChannels()
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch channels: %w", err)
+	}
+
+	closing, err := m.cfg.IsClosing()
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch closing channels: %w",
+			err)
+	}
+
+	feeRate, err := m.cfg.FeeEstimator.EstimateFeePerKW(m.cfg.ConfTarget)
+	if err != nil {
+		return nil, fmt.Errorf("unable to estimate fee rate: %w", err)
+	}
+
+	// If a channel is being closed, its anchor or HTLC outputs may need
+	// to be swept. As the sweeper can't use leased outputs, we'll hold
+	// off locking until all those channels are resolved.
+	lock := m.cfg.AutoLock && !closing
+
+	var plan *Plan
+	err = m.cfg.CoinSelectionLocker.WithCoinSelectLock(func() error {
+		utxos, err := m.listUtxos()
+		if err != nil {
+			return err
+		}
+
+		plan = NewPlan(channels, utxos, feeRate)
+
+		if !lock {
+			m.releaseLeases(m.locked)
+			return nil
+		}
+
+		m.updateLeases(plan.ReservedUtxos)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	prevPlan := m.plan
+	m.plan = plan
+
+	if !planChanged(prevPlan, plan) {
+		return plan, nil
+	}
+
+	if plan.UnderReserved() {
+		log.Warnf("Wallet is under-reserved for anchor fee bumping: "+
+			"%v required to bump %d channels at %v, missing %v",
+			plan.RequiredReserve, len(plan.Channels), plan.FeeRate,
+			plan.Deficit)
+	} else {
+		log.Debugf("Wallet reserve of %v covers the %v required to "+
+			"bump %d anchor channels at %v", plan.AvailableBalance,
+			plan.RequiredReserve, len(plan.Channels), plan.FeeRate)
+	}
+
+	if err := m.ntfnServer.SendUpdate(plan); err != nil {
+		log.Errorf("Unable to send anchor reserve update: %v", err)
+	}
+
+	return plan, nil
+}
+
+// listUtxos returns all confirmed outputs of the default wallet account that
+// can be used for fee bumping, including the ones we leased ourselves, which
+// are hidden from the wallet's list of unspent outputs.
+//
+// NOTE: The coin selection lock must be held.
+func (m *Manager) listUtxos() ([]*lnwallet.Utxo, error) {
+	utxos, err := m.cfg.Wallet.ListUnspentWitness(
+		1, math.MaxInt32, lnwallet.DefaultAccountName,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list unspent outputs: %w",
+			err)
+	}
+
+	if len(m.locked) == 0 {
+		return utxos, nil
+	}
+
+	leases, err := m.cfg.Wallet.ListLeasedOutputs()
+	if err != nil {
+		return nil, fmt.Errorf("unable to list leased outputs: %w", err)
+	}
+
+	for _, lease := range leases {
+		if _, ok := m.locked[lease.Outpoint]; !ok {
+			continue
+		}
+
+		// Leases are kept for spent outputs until they expire, so
+		// we'll refresh the lease to find out whether the output can
+		// still be used.
+		_, _, _, err := m.cfg.Wallet.LeaseOutput(
+			LockID, lease.Outpoint, m.cfg.LeaseDuration,
+		)
+		if err != nil {
+			log.Debugf("Dropping reserved output %v: %v",
+				lease.Outpoint, err)
+
+			_ = m.cfg.Wallet.ReleaseOutput(LockID, lease.Outpoint)
+			delete(m.locked, lease.Outpoint)
+			continue
+		}
+
+		utxos = append(utxos, &lnwallet.Utxo{
+			Value:    btcutil.Amount(lease.Value),
+			PkScript: lease.PkScript,
+			OutPoint: lease.Outpoint,
+		})
+	}
+
+	return utxos, nil
+}
+
+// updateLeases leases the given outputs and releases all other outputs we
+// have leased before.
+//
+// NOTE: The coin selection lock must be held.
+func (m *Manager) updateLeases(reserved []wire.OutPoint) {
+	stale := make(map[wire.OutPoint]struct{}, len(m.locked))
+	for op := range m.locked {
+		stale[op] = struct{}{}
+	}
+
+	for _, op := range reserved {
+		delete(stale, op)
+
+		// Outputs we leased before were already refreshed when they
+		// were listed.
+		if _, ok := m.locked[op]; ok {
+			continue
+		}
+
+		_, _, _, err := m.cfg.Wallet.LeaseOutput(
+			LockID, op, m.cfg.LeaseDuration,
+		)
+		if err != nil {
+			log.Warnf("Unable to reserve output %v for anchor fee "+
+				"bumping: %v", op, err)
+			continue
+		}
+
+		log.Debugf("Reserved output %v for anchor fee bumping", op)
+		m.locked[op] = struct{}{}
+	}
+
+	m.releaseLeases(stale)
+}
+
+// releaseLeases releases the leases of the given outputs.
+//
+// NOTE: The coin selection lock must be held.
+func (m *Manager) releaseLeases(outpoints map[wire.OutPoint]struct{}) {
+	for op := range outpoints {
+		err := m.cfg.Wallet.ReleaseOutput(LockID, op)
+		if err != nil && !errors.Is(err, wtxmgr.ErrUnknownOutput) {
+			log.Warnf("Unable to release reserved output %v: %v",
+				op, err)
+			continue
+		}
+
+		log.Debugf("Released reserved output %v", op)
+		delete(m.locked, op)
+	}
+}
+
+// planChanged returns true if the outcome of the new plan differs from the
+// previous one in a way that is relevant to subscribers.
+func planChanged(prev, next *Plan) bool {
+	if prev == nil {
+		return true
+	}
+
+	if prev.RequiredReserve != next.RequiredReserve ||
+		prev.Deficit != next.Deficit ||
+		len(prev.Channels) != len(next.Channels) ||
+		len(prev.ReservedUtxos) != len(next.ReservedUtxos) {
+
+		return true
+	}
+
+	for i := range prev.ReservedUtxos {
+		if prev.ReservedUtxos[i] != next.ReservedUtxos[i] {
+			return true
+		}
+	}
+
+	return false
+}
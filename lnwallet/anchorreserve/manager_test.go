@@ -0,0 +1,289 @@
+package anchorreserve
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/wire"
+	base "github.com/btcsuite/btcwallet/wallet"
+	"github.com/btcsuite/btcwallet/wtxmgr"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+	"github.com/lightningnetwork/lnd/ticker"
+	"github.com/stretchr/testify/require"
+)
+
+// mockWallet is an in-memory WalletController that hides leased outputs from
+// the list of unspent outputs, just like btcwallet does.
+type mockWallet struct {
+	sync.Mutex
+
+	utxos  map[wire.OutPoint]*lnwallet.Utxo
+	leases map[wire.OutPoint]wtxmgr.LockID
+}
+
+func newMockWallet(utxos ...*lnwallet.Utxo) *mockWallet {
+	w := &mockWallet{
+		utxos:  make(map[wire.OutPoint]*lnwallet.Utxo),
+		leases: make(map[wire.OutPoint]wtxmgr.LockID),
+	}
+	for _, utxo := range utxos {
+		w.utxos[utxo.OutPoint] = utxo
+	}
+
+	return w
+}
+
+func (w *mockWallet) ListUnspentWitness(_, _ int32,
+	_ string) ([]*lnwallet.Utxo, error) {
+
+	w.Lock()
+	defer w.Unlock()
+
+	var utxos []*lnwallet.Utxo
+	for op, utxo := range w.utxos {
+		if _, ok := w.leases[op]; ok {
+			continue
+		}
+		utxos = append(utxos, utxo)
+	}
+
+	return utxos, nil
+}
+
+func (w *mockWallet) LeaseOutput(id wtxmgr.LockID, op wire.OutPoint,
+	_ time.Duration) (time.Time, []byte, btcutil.Amount, error) {
+
+	w.Lock()
+	defer w.Unlock()
+
+	utxo, ok := w.utxos[op]
+	if !ok {
+		return time.Time{}, nil, 0, wtxmgr.ErrUnknownOutput
+	}
+	if lockID, ok := w.leases[op]; ok && lockID != id {
+		return time.Time{}, nil, 0, wtxmgr.ErrOutputAlreadyLocked
+	}
+	w.leases[op] = id
+
+	return time.Time{}, utxo.PkScript, utxo.Value, nil
+}
+
+func (w *mockWallet) ReleaseOutput(id wtxmgr.LockID, op wire.OutPoint) error {
+	w.Lock()
+	defer w.Unlock()
+
+	if lockID, ok := w.leases[op]; ok && lockID != id {
+		return wtxmgr.ErrOutputUnlockNotAllowed
+	}
+	delete(w.leases, op)
+
+	return nil
+}
+
+func (w *mockWallet) ListLeasedOutputs() ([]*base.ListLeasedOutputResult,
+	error) {
+
+	w.Lock()
+	defer w.Unlock()
+
+	var leases []*base.ListLeasedOutputResult
+	for op, id := range w.leases {
+		var value int64
+		if utxo, ok := w.utxos[op]; ok {
+			value = int64(utxo.Value)
+		}
+
+		leases = append(leases, &base.ListLeasedOutputResult{
+			LockedOutput: &wtxmgr.LockedOutput{
+				Outpoint: op,
+				LockID:   id,
+			},
+			Value: value,
+		})
+	}
+
+	return leases, nil
+}
+
+// leased returns the outputs leased to the given ID.
+func (w *mockWallet) leased(id wtxmgr.LockID) []wire.OutPoint {
+	w.Lock()
+	defer w.Unlock()
+
+	var outpoints []wire.OutPoint
+	for op, lockID := range w.leases {
+		if lockID == id {
+			outpoints = append(outpoints, op)
+		}
+	}
+
+	return outpoints
+}
+
+// spend removes the given output from the wallet.
+func (w *mockWallet) spend(op wire.OutPoint) {
+	w.Lock()
+	defer w.Unlock()
+
+	delete(w.utxos, op)
+}
+
+// mockCoinSelectionLocker is a CoinSelectionLocker that doesn't lock.
+type mockCoinSelectionLocker struct{}
+
+func (m *mockCoinSelectionLocker) WithCoinSelectLock(f func() error) error {
+	return f()
+}
+
+// managerHarness bundles a manager with its mocked dependencies.
+type managerHarness struct {
+	t *testing.T
+
+	mu       sync.Mutex
+	channels []*channeldb.OpenChannel
+	closing  bool
+
+	wallet  *mockWallet
+	ticker  *ticker.Force
+	manager *Manager
+}
+
+func newManagerHarness(t *testing.T, autoLock bool,
+	channels []*channeldb.OpenChannel,
+	utxos ...*lnwallet.Utxo) *managerHarness {
+
+	h := &managerHarness{
+		t:        t,
+		channels: channels,
+		wallet:   newMockWallet(utxos...),
+		ticker:   ticker.NewForce(time.Hour),
+	}
+
+	h.manager = NewManager(Config{
+		FetchChannels: func() ([]*channeldb.OpenChannel, error) {
+			h.mu.Lock()
+			defer h.mu.Unlock()
+
+			return h.channels, nil
+		},
+		IsClosing: func() (bool, error) {
+			h.mu.Lock()
+			defer h.mu.Unlock()
+
+			return h.closing, nil
+		},
+		Wallet:              h.wallet,
+		CoinSelectionLocker: &mockCoinSelectionLocker{},
+		FeeEstimator: chainfee.NewStaticEstimator(
+			10_000, chainfee.FeePerKwFloor,
+		),
+		ConfTarget:    6,
+		AutoLock:      autoLock,
+		LeaseDuration: time.Hour,
+		Ticker:        h.ticker,
+	})
+
+	require.NoError(t, h.manager.Start())
+	t.Cleanup(func() {
+		require.NoError(t, h.manager.Stop())
+	})
+
+	return h
+}
+
+// TestManagerAutoLock tests that the manager leases the reserved outputs,
+// releases them once they're no longer needed and doesn't lock any outputs
+// while a channel is being closed.
+func TestManagerAutoLock(t *testing.T) {
+	t.Parallel()
+
+	const feeRate = chainfee.SatPerKWeight(10_000)
+
+	channel := testChannel(1, testAnchorType, 1000, 0)
+	fee := channelReserve(channel, feeRate).RequiredFee
+
+	h := newManagerHarness(
+		t, true, []*channeldb.OpenChannel{channel},
+		testUtxo(10, fee*2), testUtxo(11, fee),
+	)
+
+	// The largest output should be leased on startup.
+	require.Equal(
+		t, []wire.OutPoint{testOutPoint(10)}, h.wallet.leased(LockID),
+	)
+	require.Equal(
+		t, []wire.OutPoint{testOutPoint(10)}, h.manager.LockedOutputs(),
+	)
+
+	// Even though the leased output is hidden from the list of unspent
+	// outputs, it should still be part of the plan.
+	plan, err := h.manager.Plan()
+	require.NoError(t, err)
+	require.False(t, plan.UnderReserved())
+	require.Equal(t, fee*3, plan.AvailableBalance)
+	require.Equal(t, []wire.OutPoint{testOutPoint(10)}, plan.ReservedUtxos)
+
+	// Once the leased output is spent, the next output is leased instead.
+	h.wallet.spend(testOutPoint(10))
+	plan, err = h.manager.Plan()
+	require.NoError(t, err)
+	require.Equal(t, []wire.OutPoint{testOutPoint(11)}, plan.ReservedUtxos)
+	require.Equal(
+		t, []wire.OutPoint{testOutPoint(11)}, h.wallet.leased(LockID),
+	)
+
+	// Releasing the reserve unlocks all outputs.
+	h.manager.ReleaseReserve()
+	require.Empty(t, h.wallet.leased(LockID))
+	require.Empty(t, h.manager.LockedOutputs())
+
+	// While the channel is being closed, the output isn't locked again.
+	h.mu.Lock()
+	h.closing = true
+	h.channels = nil
+	h.mu.Unlock()
+
+	_, err = h.manager.Plan()
+	require.NoError(t, err)
+	require.Empty(t, h.wallet.leased(LockID))
+}
+
+// TestManagerSubscribe tests that subscribers are notified when the outcome
+// of a planning run changes.
+func TestManagerSubscribe(t *testing.T) {
+	t.Parallel()
+
+	h := newManagerHarness(t, false, nil, testUtxo(10, 1000))
+
+	client, err := h.manager.SubscribePlans()
+	require.NoError(t, err)
+	defer client.Cancel()
+
+	// Adding an expensive channel makes the wallet under-reserved, which
+	// should be sent to subscribers on the next tick.
+	h.mu.Lock()
+	h.channels = []*channeldb.OpenChannel{
+		testChannel(1, testAnchorType, 0, 10),
+	}
+	h.mu.Unlock()
+
+	h.ticker.Force <- time.Now()
+
+	select {
+	case update := <-client.Updates():
+		plan, ok := update.(*Plan)
+		require.True(t, ok)
+		require.True(t, plan.UnderReserved())
+		require.Len(t, plan.Channels, 1)
+
+	case <-time.After(time.Second * 5):
+		t.Fatalf("no plan update received")
+	}
+
+	// Without auto locking, no outputs are leased.
+	require.Empty(t, h.wallet.leased(LockID))
+}
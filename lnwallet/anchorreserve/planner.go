@@ -0,0 +1,253 @@
+package anchorreserve
+
+import (
+	"sort"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/input"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+)
+
+const (
+	// anchorValue is the value of an anchor output. It's spent along with
+	// the wallet inputs of the CPFP transaction, so it reduces the amount
+	// the wallet needs to contribute.
+	anchorValue = btcutil.Amount(330)
+)
+
+// cpfpChildWeight returns the weight of a CPFP child transaction that spends
+// an anchor output along with a single wallet input, paying the remainder to a
+// change output.
+func cpfpChildWeight() int64 {
+	var weightEstimate input.TxWeightEstimator
+	weightEstimate.AddWitnessInput(input.AnchorWitnessSize)
+	weightEstimate.AddP2WKHInput()
+	weightEstimate.AddP2TROutput()
+
+	return int64(weightEstimate.Weight())
+}
+
+// secondLevelWeight returns the weight of a zero-fee second-level HTLC
+// transaction, including the wallet input and the change output that are
+// needed to pay its fee. We assume the larger of the HTLC success and timeout
+// transactions.
+func secondLevelWeight() int64 {
+	var weightEstimate input.TxWeightEstimator
+	weightEstimate.AddP2WKHInput()
+	weightEstimate.AddP2TROutput()
+
+	return input.HtlcSuccessWeightConfirmed +
+		int64(weightEstimate.Weight())
+}
+
+// ChannelReserve describes the wallet funds needed to get the commitment of a
+// single anchor channel confirmed if it were force closed right now.
+type ChannelReserve struct {
+	// ChanPoint is the funding outpoint of the channel.
+	ChanPoint wire.OutPoint
+
+	// CommitWeight is the estimated weight of the commitment that is the
+	// most expensive to get confirmed.
+	CommitWeight int64
+
+	// CommitFee is the fee already paid by that commitment.
+	CommitFee btcutil.Amount
+
+	// NumHTLCs is the number of non-dust HTLCs on our commitment that need
+	// to be swept through second-level transactions which are funded by
+	// the wallet.
+	NumHTLCs int
+
+	// RequiredFee is the amount the wallet needs to contribute to bump the
+	// commitment, and any second-level HTLC transactions, to the target
+	// fee rate.
+	RequiredFee btcutil.Amount
+
+	// Utxos are the wallet outputs that were assigned to cover the
+	// required fee of this channel.
+	Utxos []wire.OutPoint
+
+	// Covered is true if the assigned outputs cover the required fee.
+	Covered bool
+}
+
+// Plan is the outcome of a reserve planning run. It models the worst case
+// that all anchor channels are force closed simultaneously at the current fee
+// estimate. As the sweeper never batches anchor sweeps of different channels,
+// each channel needs its own set of wallet outputs to be bumped.
+type Plan struct {
+	// FeeRate is the fee rate all commitments need to be bumped to.
+	FeeRate chainfee.SatPerKWeight
+
+	// Channels describes the reserve needed by each anchor channel, in
+	// descending order of the required fee.
+	Channels []ChannelReserve
+
+	// RequiredReserve is the total amount the wallet needs to contribute
+	// to bump all anchor channels.
+	RequiredReserve btcutil.Amount
+
+	// AvailableBalance is the total value of the confirmed wallet outputs
+	// that can be used for fee bumping.
+	AvailableBalance btcutil.Amount
+
+	// Deficit is the amount of the required fees that can't be covered by
+	// the wallet outputs.
+	Deficit btcutil.Amount
+
+	// ReservedUtxos are all wallet outputs that were assigned to any of
+	// the channels.
+	ReservedUtxos []wire.OutPoint
+}
+
+// UnderReserved returns true if the wallet isn't able to bump all anchor
+// channels at once.
+func (p *Plan) UnderReserved() bool {
+	return p.Deficit > 0
+}
+
+// NewPlan creates a reserve plan for the given channels, assuming all of them
+// need to be bumped to the given fee rate using the given wallet outputs.
+// Channels that don't have anchor outputs are ignored.
+func NewPlan(channels []*channeldb.OpenChannel, utxos []*lnwallet.Utxo,
+	feeRate chainfee.SatPerKWeight) *Plan {
+
+	plan := &Plan{
+		FeeRate: feeRate,
+	}
+
+	for _, channel := range channels {
+		if !channel.ChanType.HasAnchors() {
+			continue
+		}
+
+		chanReserve := channelReserve(channel, feeRate)
+		plan.Channels = append(plan.Channels, chanReserve)
+		plan.RequiredReserve += chanReserve.RequiredFee
+	}
+
+	// We'll assign the largest outputs to the most expensive channels
+	// first, which minimizes the number of outputs each of the expensive
+	// channels needs.
+	sort.SliceStable(plan.Channels, func(i, j int) bool {
+		return plan.Channels[i].RequiredFee >
+			plan.Channels[j].RequiredFee
+	})
+
+	sortedUtxos := make([]*lnwallet.Utxo, len(utxos))
+	copy(sortedUtxos, utxos)
+	sort.SliceStable(sortedUtxos, func(i, j int) bool {
+		return sortedUtxos[i].Value > sortedUtxos[j].Value
+	})
+
+	for _, utxo := range sortedUtxos {
+		plan.AvailableBalance += utxo.Value
+	}
+
+	for i := range plan.Channels {
+		chanReserve := &plan.Channels[i]
+
+		// A channel whose commitment already pays enough fees doesn't
+		// need any wallet outputs.
+		if chanReserve.RequiredFee == 0 {
+			chanReserve.Covered = true
+			continue
+		}
+
+		var assigned btcutil.Amount
+		for len(sortedUtxos) > 0 && assigned < chanReserve.RequiredFee {
+			utxo := sortedUtxos[0]
+			sortedUtxos = sortedUtxos[1:]
+
+			assigned += utxo.Value
+			chanReserve.Utxos = append(
+				chanReserve.Utxos, utxo.OutPoint,
+			)
+			plan.ReservedUtxos = append(
+				plan.ReservedUtxos, utxo.OutPoint,
+			)
+		}
+
+		if assigned >= chanReserve.RequiredFee {
+			chanReserve.Covered = true
+			continue
+		}
+
+		plan.Deficit += chanReserve.RequiredFee - assigned
+	}
+
+	return plan
+}
+
+// channelReserve computes the reserve needed by a single anchor channel.
+func channelReserve(channel *channeldb.OpenChannel,
+	feeRate chainfee.SatPerKWeight) ChannelReserve {
+
+	chanReserve := ChannelReserve{
+		ChanPoint: channel.FundingOutpoint,
+	}
+
+	// We don't know which of the commitments will end up being the one
+	// that needs to be bumped, so we'll assume the most expensive one.
+	for _, commit := range []channeldb.ChannelCommitment{
+		channel.LocalCommitment, channel.RemoteCommitment,
+	} {
+		commitWeight := int64(input.AnchorCommitWeight) +
+			int64(numNonDustHTLCs(commit.Htlcs))*input.HTLCWeight
+
+		fee := cpfpFee(commitWeight, commit.CommitFee, feeRate)
+		if fee >= chanReserve.RequiredFee {
+			chanReserve.CommitWeight = commitWeight
+			chanReserve.CommitFee = commit.CommitFee
+			chanReserve.RequiredFee = fee
+		}
+	}
+
+	// For zero-fee HTLC channels, the second-level transactions of the
+	// HTLCs on our commitment don't carry any fees, so they need to be
+	// funded by the wallet as well.
+	if channel.ChanType.ZeroHtlcTxFee() {
+		chanReserve.NumHTLCs = numNonDustHTLCs(
+			channel.LocalCommitment.Htlcs,
+		)
+		htlcFee := feeRate.FeeForWeight(secondLevelWeight())
+		chanReserve.RequiredFee += btcutil.Amount(
+			chanReserve.NumHTLCs,
+		) * htlcFee
+	}
+
+	return chanReserve
+}
+
+// cpfpFee returns the amount the wallet needs to contribute to a CPFP child
+// to bump a commitment of the given weight and fee to the given fee rate.
+func cpfpFee(commitWeight int64, commitFee btcutil.Amount,
+	feeRate chainfee.SatPerKWeight) btcutil.Amount {
+
+	packageFee := feeRate.FeeForWeight(commitWeight + cpfpChildWeight())
+
+	// The commitment already pays its share of the package fee, and the
+	// anchor value is spent as part of the child.
+	required := packageFee - commitFee - anchorValue
+	if required < 0 {
+		return 0
+	}
+
+	return required
+}
+
+// numNonDustHTLCs returns the number of HTLCs that have an output on the
+// commitment.
+func numNonDustHTLCs(htlcs []channeldb.HTLC) int {
+	var numHTLCs int
+	for _, htlc := range htlcs {
+		if htlc.OutputIndex >= 0 {
+			numHTLCs++
+		}
+	}
+
+	return numHTLCs
+}
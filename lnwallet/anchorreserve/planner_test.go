@@ -0,0 +1,160 @@
+package anchorreserve
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/input"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+	"github.com/stretchr/testify/require"
+)
+
+// testAnchorType is the channel type of the anchor channels used in the
+// tests.
+const testAnchorType = channeldb.AnchorOutputsBit |
+	channeldb.SingleFunderTweaklessBit
+
+// testOutPoint returns a unique outpoint for the given index.
+func testOutPoint(index uint32) wire.OutPoint {
+	return wire.OutPoint{
+		Hash:  chainhash.Hash{byte(index)},
+		Index: index,
+	}
+}
+
+// testChannel creates a channel of the given type with the given commitment
+// fee and number of non-dust HTLCs on both commitments.
+func testChannel(index uint32, chanType channeldb.ChannelType,
+	commitFee btcutil.Amount, numHTLCs int) *channeldb.OpenChannel {
+
+	htlcs := make([]channeldb.HTLC, 0, numHTLCs+1)
+	for i := 0; i < numHTLCs; i++ {
+		htlcs = append(htlcs, channeldb.HTLC{OutputIndex: int32(i)})
+	}
+
+	// Dust HTLCs don't have an output on the commitment.
+	htlcs = append(htlcs, channeldb.HTLC{OutputIndex: -1})
+
+	commit := channeldb.ChannelCommitment{
+		CommitFee: commitFee,
+		Htlcs:     htlcs,
+	}
+
+	return &channeldb.OpenChannel{
+		ChanType:         chanType,
+		FundingOutpoint:  testOutPoint(index),
+		LocalCommitment:  commit,
+		RemoteCommitment: commit,
+	}
+}
+
+// testUtxo creates a wallet output with the given value.
+func testUtxo(index uint32, value btcutil.Amount) *lnwallet.Utxo {
+	return &lnwallet.Utxo{
+		AddressType: lnwallet.WitnessPubKey,
+		Value:       value,
+		OutPoint:    testOutPoint(index),
+	}
+}
+
+// TestChannelReserve tests the reserve computed for a single channel.
+func TestChannelReserve(t *testing.T) {
+	t.Parallel()
+
+	const feeRate = chainfee.SatPerKWeight(10_000)
+
+	commitWeight := int64(input.AnchorCommitWeight) + 2*input.HTLCWeight
+	cpfpWeight := commitWeight + cpfpChildWeight()
+
+	// A channel with anchors needs to pay the package fee, minus the fee
+	// paid by the commitment and the anchor value.
+	channel := testChannel(0, testAnchorType, 1000, 2)
+	reserve := channelReserve(channel, feeRate)
+	require.Equal(t, commitWeight, reserve.CommitWeight)
+	require.Zero(t, reserve.NumHTLCs)
+	require.Equal(
+		t, feeRate.FeeForWeight(cpfpWeight)-1000-anchorValue,
+		reserve.RequiredFee,
+	)
+
+	// With zero-fee HTLC transactions, the second-level transactions need
+	// to be funded as well.
+	zeroFee := testAnchorType | channeldb.ZeroHtlcTxFeeBit
+	reserve = channelReserve(testChannel(0, zeroFee, 1000, 2), feeRate)
+	require.Equal(t, 2, reserve.NumHTLCs)
+	require.Equal(
+		t, feeRate.FeeForWeight(cpfpWeight)-1000-anchorValue+
+			2*feeRate.FeeForWeight(secondLevelWeight()),
+		reserve.RequiredFee,
+	)
+
+	// A commitment that already pays enough fees doesn't need a reserve.
+	channel = testChannel(0, testAnchorType, 100_000, 0)
+	reserve = channelReserve(channel, feeRate)
+	require.Zero(t, reserve.RequiredFee)
+}
+
+// TestNewPlan tests that wallet outputs are assigned to the channels that
+// need them, and that the deficit is computed correctly.
+func TestNewPlan(t *testing.T) {
+	t.Parallel()
+
+	const feeRate = chainfee.SatPerKWeight(10_000)
+
+	channels := []*channeldb.OpenChannel{
+		// A legacy channel, which is ignored.
+		testChannel(1, channeldb.SingleFunderTweaklessBit, 0, 0),
+
+		// A cheap and an expensive anchor channel.
+		testChannel(2, testAnchorType, 1000, 0),
+		testChannel(3, testAnchorType, 1000, 10),
+	}
+
+	cheapFee := channelReserve(channels[1], feeRate).RequiredFee
+	expensiveFee := channelReserve(channels[2], feeRate).RequiredFee
+
+	// With a single large output, only the expensive channel is covered.
+	utxos := []*lnwallet.Utxo{
+		testUtxo(10, expensiveFee+cheapFee),
+	}
+	plan := NewPlan(channels, utxos, feeRate)
+
+	require.Len(t, plan.Channels, 2)
+	require.Equal(t, cheapFee+expensiveFee, plan.RequiredReserve)
+	require.Equal(t, cheapFee+expensiveFee, plan.AvailableBalance)
+
+	require.Equal(
+		t, channels[2].FundingOutpoint, plan.Channels[0].ChanPoint,
+	)
+	require.True(t, plan.Channels[0].Covered)
+	require.Equal(
+		t, []wire.OutPoint{testOutPoint(10)}, plan.Channels[0].Utxos,
+	)
+
+	require.Equal(
+		t, channels[1].FundingOutpoint, plan.Channels[1].ChanPoint,
+	)
+	require.False(t, plan.Channels[1].Covered)
+	require.Empty(t, plan.Channels[1].Utxos)
+
+	require.True(t, plan.UnderReserved())
+	require.Equal(t, cheapFee, plan.Deficit)
+
+	// With an additional small output, both channels are covered.
+	utxos = append(utxos, testUtxo(11, cheapFee))
+	plan = NewPlan(channels, utxos, feeRate)
+
+	require.False(t, plan.UnderReserved())
+	require.Zero(t, plan.Deficit)
+	require.Equal(
+		t, []wire.OutPoint{testOutPoint(10), testOutPoint(11)},
+		plan.ReservedUtxos,
+	)
+	require.Equal(
+		t, []wire.OutPoint{testOutPoint(11)}, plan.Channels[1].Utxos,
+	)
+}
@@ -48,6 +48,18 @@ var (
 	// ErrInvalidShutdownScript is returned when we receive an address from
 	// a peer that isn't either a p2wsh or p2tr address.
 	ErrInvalidShutdownScript = fmt.Errorf("invalid shutdown script")
+
+	// ErrNotSimpleClose is returned when an RBF closing transaction is
+	// requested for a channel that isn't closed with the simple
+	// cooperative close protocol.
+	ErrNotSimpleClose = fmt.Errorf("channel isn't closed with simple " +
+		"close protocol")
+
+	// ErrFeeRateNotIncreased is returned when a new RBF closing
+	// transaction is requested that doesn't pay a higher fee rate than
+	// the last one we proposed.
+	ErrFeeRateNotIncreased = fmt.Errorf("fee rate must exceed the fee " +
+		"rate of the prior closing transaction")
 )
 
 // closeState represents all the possible states the channel closer state
@@ -79,6 +91,15 @@ const (
 	// causes a shift into the closeFinished state.
 	closeFeeNegotiation
 
+	// closeRbfNegotiation replaces closeFeeNegotiation when the channel is
+	// closed with the simple cooperative close protocol. In this state,
+	// either party can propose its own closing transaction paying its own
+	// fee by sending a ClosingComplete message, which the other party
+	// countersigns with a ClosingSig message. Each party can later replace
+	// its closing transaction with one paying a higher fee, so we remain
+	// in this state until the channel is closed on chain.
+	closeRbfNegotiation
+
 	// closeFinished is the final state of the state machine. In this state, a
 	// side has accepted a fee offer and has broadcast the valid closing
 	// transaction to the network. During this phase, the closing transaction
@@ -105,6 +126,11 @@ type Channel interface {
 	// transaction has been broadcast.
 	MarkCoopBroadcasted(*wire.MsgTx, bool) error
 
+	// AddCoopCloseTxid persistently records the txid of a closing
+	// transaction that the remote party may broadcast without us, so that
+	// it's recognized as a cooperative close once it confirms.
+	AddCoopCloseTxid(chainhash.Hash) error
+
 	// IsInitiator returns true we are the initiator of the channel.
 	IsInitiator() bool
 
@@ -135,14 +161,24 @@ type Channel interface {
 	// of a valid signature, the chainhash of the final txid, and our final
 	// balance in the created state.
 	CreateCloseProposal(proposedFee btcutil.Amount, localDeliveryScript []byte,
-		remoteDeliveryScript []byte) (input.Signature, *chainhash.Hash,
-		btcutil.Amount, error)
+		remoteDeliveryScript []byte,
+		closeOpts ...lnwallet.ChanCloseOpt) (input.Signature,
+		*chainhash.Hash, btcutil.Amount, error)
 
 	// CompleteCooperativeClose persistently "completes" the cooperative
 	// close by producing a fully signed co-op close transaction.
 	CompleteCooperativeClose(localSig, remoteSig input.Signature,
 		localDeliveryScript, remoteDeliveryScript []byte,
-		proposedFee btcutil.Amount) (*wire.MsgTx, btcutil.Amount, error)
+		proposedFee btcutil.Amount,
+		closeOpts ...lnwallet.ChanCloseOpt) (*wire.MsgTx,
+		btcutil.Amount, error)
+
+	// CoopCloseOutputs returns whether the co-op close transaction paying
+	// the proposed fee has an output for the local and the remote party
+	// respectively.
+	CoopCloseOutputs(proposedFee btcutil.Amount, localDeliveryScript,
+		remoteDeliveryScript []byte,
+		closeOpts ...lnwallet.ChanCloseOpt) (bool, bool, error)
 }
 
 // CoopFeeEstimator is used to estimate the fee of a co-op close transaction.
@@ -187,6 +223,26 @@ type ChanCloseCfg struct {
 	// FeeEstimator is used to estimate the absolute starting co-op close
 	// fee.
 	FeeEstimator CoopFeeEstimator
+
+	// SimpleClose is true if the channel should be closed with the simple
+	// cooperative close protocol, where each party pays the fee of its
+	// own replaceable closing transaction, rather than negotiating a
+	// single fee with ClosingSigned messages.
+	SimpleClose bool
+}
+
+// rbfProposal is a closing transaction we proposed with a ClosingComplete
+// message, which is awaiting the signature of the remote party.
+type rbfProposal struct {
+	// msg is the ClosingComplete message we sent.
+	msg *lnwire.ClosingComplete
+
+	// sig is our signature for the variant of the closing transaction we
+	// expect the remote party to sign.
+	sig lnwire.Sig
+
+	// feeRate is the fee rate the closing transaction pays.
+	feeRate chainfee.SatPerKWeight
 }
 
 // ChanCloser is a state machine that handles the cooperative channel closure
@@ -256,6 +312,16 @@ type ChanCloser struct {
 
 	// locallyInitiated is true if we initiated the channel close.
 	locallyInitiated bool
+
+	// lastRbfFeeRate is the fee rate of the last closing transaction we
+	// proposed with the simple cooperative close protocol. Any new
+	// proposal must pay a higher fee rate.
+	lastRbfFeeRate chainfee.SatPerKWeight
+
+	// pendingRbfProposal is our latest closing transaction proposed with
+	// the simple cooperative close protocol that hasn't been signed by the
+	// remote party yet.
+	pendingRbfProposal *rbfProposal
 }
 
 // calcCoopCloseFee computes an "ideal" absolute co-op close fee given the
@@ -419,20 +485,32 @@ func (c *ChanCloser) ShutdownChan() (*lnwire.Shutdown, error) {
 	return shutdownMsg, nil
 }
 
-// ClosingTx returns the fully signed, final closing transaction.
+// ClosingTx returns the fully signed, final closing transaction. With the
+// simple cooperative close protocol, this is the latest closing transaction
+// that was broadcast, which may still be replaced.
 //
 // NOTE: This transaction is only available if the state machine is in the
-// closeFinished state.
+// closeFinished state, or once a closing transaction was broadcast in the
+// closeRbfNegotiation state.
 func (c *ChanCloser) ClosingTx() (*wire.MsgTx, error) {
 	// If the state machine hasn't finished closing the channel, then we'll
 	// return an error as we haven't yet computed the closing tx.
-	if c.state != closeFinished {
+	switch {
+	case c.state == closeFinished:
+	case c.state == closeRbfNegotiation && c.closingTx != nil:
+	default:
 		return nil, ErrChanCloseNotFinished
 	}
 
 	return c.closingTx, nil
 }
 
+// SimpleClose returns true if the channel is closed with the simple
+// cooperative close protocol.
+func (c *ChanCloser) SimpleClose() bool {
+	return c.cfg.SimpleClose
+}
+
 // CloseRequest returns the original close request that prompted the creation
 // of the state machine.
 //
@@ -577,6 +655,16 @@ func (c *ChanCloser) ProcessCloseMsg(msg lnwire.Message) ([]lnwire.Message,
 		msgsToSend := make([]lnwire.Message, 0, 2)
 		msgsToSend = append(msgsToSend, localShutdown)
 
+		// With the simple close protocol, there's no fee negotiation.
+		// Instead, both parties may now propose their own closing
+		// transaction. As we didn't request the close, we'll leave it
+		// up to the remote party to send the first proposal.
+		if c.cfg.SimpleClose {
+			c.state = closeRbfNegotiation
+
+			return msgsToSend, false, nil
+		}
+
 		// After the other party receives this message, we'll actually start
 		// the final stage of the closure process: fee negotiation. So we'll
 		// update our internal state to reflect this, so we can handle the next
@@ -623,6 +711,31 @@ func (c *ChanCloser) ProcessCloseMsg(msg lnwire.Message) ([]lnwire.Message,
 		// record their preferred delivery closing script.
 		c.remoteDeliveryScript = shutdownMsg.Address
 
+		// With the simple close protocol, we'll propose our closing
+		// transaction at our ideal fee rate if the close was requested
+		// locally.
+		if c.cfg.SimpleClose {
+			c.state = closeRbfNegotiation
+			c.initFeeBaseline()
+
+			chancloserLog.Infof("ChannelPoint(%v): shutdown "+
+				"response received, entering rbf negotiation",
+				c.chanPoint)
+
+			if c.closeReq == nil {
+				return nil, false, nil
+			}
+
+			closeComplete, _, err := c.ProposeRbfClose(
+				c.idealFeeRate,
+			)
+			if err != nil {
+				return nil, false, err
+			}
+
+			return []lnwire.Message{closeComplete}, false, nil
+		}
+
 		// At this point, we can now start the fee negotiation state, by
 		// constructing and sending our initial signature for what we think the
 		// closing transaction should look like.
@@ -755,6 +868,23 @@ func (c *ChanCloser) ProcessCloseMsg(msg lnwire.Message) ([]lnwire.Message,
 		matchingOffer := c.priorFeeOffers[remoteProposedFee]
 		return []lnwire.Message{matchingOffer}, true, nil
 
+	// With the simple close protocol, we either receive a closing
+	// transaction proposed by the remote party, or the signature for the
+	// closing transaction we proposed.
+	case closeRbfNegotiation:
+		switch msg := msg.(type) {
+		case *lnwire.ClosingComplete:
+			return c.handleClosingComplete(msg)
+
+		case *lnwire.ClosingSig:
+			return c.handleClosingSig(msg)
+
+		default:
+			return nil, false, fmt.Errorf("expected "+
+				"lnwire.ClosingComplete or lnwire.ClosingSig, "+
+				"instead have %v", spew.Sdump(msg))
+		}
+
 	// If we received a message while in the closeFinished state, then this
 	// should only be the remote party echoing the last ClosingSigned message
 	// that we agreed on.
@@ -808,6 +938,312 @@ func (c *ChanCloser) proposeCloseSigned(fee btcutil.Amount) (*lnwire.ClosingSign
 	return closeSignedMsg, nil
 }
 
+// rbfCloseOpts returns the options used to create a closing transaction with
+// the simple close protocol. The closing transaction signals replaceability,
+// and its fee is paid by the closer.
+func rbfCloseOpts(lockTime uint32, localIsCloser bool) []lnwallet.ChanCloseOpt {
+	return []lnwallet.ChanCloseOpt{
+		lnwallet.WithCustomSequence(wire.MaxTxInSequenceNum - 2),
+		lnwallet.WithCustomLockTime(lockTime),
+		lnwallet.WithCustomPayer(localIsCloser),
+	}
+}
+
+// ProposeRbfClose creates a new closing transaction paying the given fee rate
+// with the simple close protocol. The fee is paid from our own output. The
+// returned ClosingComplete message should be sent to the remote party, which
+// will respond with its signature. The txid of the new closing transaction is
+// returned as well.
+func (c *ChanCloser) ProposeRbfClose(feeRate chainfee.SatPerKWeight) (
+	*lnwire.ClosingComplete, *chainhash.Hash, error) {
+
+	if !c.cfg.SimpleClose {
+		return nil, nil, ErrNotSimpleClose
+	}
+
+	if c.state != closeRbfNegotiation {
+		return nil, nil, ErrChanCloseNotFinished
+	}
+
+	if feeRate <= c.lastRbfFeeRate {
+		return nil, nil, fmt.Errorf("%w: %v <= %v",
+			ErrFeeRateNotIncreased, feeRate, c.lastRbfFeeRate)
+	}
+
+	// We'll estimate the absolute fee assuming our output is present, as
+	// we're the one paying for the closing transaction.
+	localTxOut := &wire.TxOut{PkScript: c.localDeliveryScript}
+	var remoteTxOut *wire.TxOut
+	if !c.cfg.Channel.RemoteBalanceDust() {
+		remoteTxOut = &wire.TxOut{PkScript: c.remoteDeliveryScript}
+	}
+	fee := c.cfg.FeeEstimator.EstimateFee(
+		0, localTxOut, remoteTxOut, feeRate,
+	)
+
+	opts := rbfCloseOpts(c.negotiationHeight, true)
+	hasLocal, hasRemote, err := c.cfg.Channel.CoopCloseOutputs(
+		fee, c.localDeliveryScript, c.remoteDeliveryScript, opts...,
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rawSig, txid, _, err := c.cfg.Channel.CreateCloseProposal(
+		fee, c.localDeliveryScript, c.remoteDeliveryScript, opts...,
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+	sig, err := lnwire.NewSigFromSignature(rawSig)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// We only sign the variant of the closing transaction we expect the
+	// remote party to sign as well, as both sides derive the outputs
+	// from the same balances.
+	closingComplete := &lnwire.ClosingComplete{
+		ChannelID:    c.cid,
+		CloserScript: c.localDeliveryScript,
+		CloseeScript: c.remoteDeliveryScript,
+		FeeSatoshis:  fee,
+		LockTime:     c.negotiationHeight,
+	}
+	switch {
+	case hasLocal && hasRemote:
+		closingComplete.ClosingSigs.CloserAndClosee = &sig
+
+	case hasLocal:
+		closingComplete.ClosingSigs.CloserNoClosee = &sig
+
+	case hasRemote:
+		closingComplete.ClosingSigs.NoCloserClosee = &sig
+
+	default:
+		return nil, nil, fmt.Errorf("closing transaction has no " +
+			"outputs")
+	}
+
+	// Once the remote party countersigns the proposal, it can broadcast
+	// the closing transaction without ever sending us its signature. The
+	// closing transaction doesn't use a final sequence either, so we'll
+	// record its txid before handing out our signature in order to
+	// recognize it as a cooperative close.
+	if err := c.cfg.Channel.AddCoopCloseTxid(*txid); err != nil {
+		return nil, nil, err
+	}
+
+	c.lastRbfFeeRate = feeRate
+	c.pendingRbfProposal = &rbfProposal{
+		msg:     closingComplete,
+		sig:     sig,
+		feeRate: feeRate,
+	}
+
+	chancloserLog.Infof("ChannelPoint(%v): proposing rbf closing tx %v "+
+		"with fee of %v sat (fee_rate=%v)", c.chanPoint, txid,
+		int64(fee), feeRate)
+
+	return closingComplete, txid, nil
+}
+
+// handleClosingComplete handles a closing transaction proposed by the remote
+// party with the simple close protocol. If the proposal is valid, we
+// countersign and broadcast the closing transaction, and return the
+// ClosingSig message carrying our signature.
+func (c *ChanCloser) handleClosingComplete(
+	msg *lnwire.ClosingComplete) ([]lnwire.Message, bool, error) {
+
+	// The remote party must use the scripts exchanged with the Shutdown
+	// messages.
+	if !bytes.Equal(msg.CloserScript, c.remoteDeliveryScript) ||
+		!bytes.Equal(msg.CloseeScript, c.localDeliveryScript) {
+
+		return nil, false, fmt.Errorf("closing scripts don't match " +
+			"shutdown scripts")
+	}
+
+	// As the remote party is the closer, it pays the whole fee. We'll
+	// determine which variant of the closing transaction we expect it to
+	// have signed.
+	opts := rbfCloseOpts(msg.LockTime, false)
+	hasLocal, hasRemote, err := c.cfg.Channel.CoopCloseOutputs(
+		msg.FeeSatoshis, c.localDeliveryScript,
+		c.remoteDeliveryScript, opts...,
+	)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var (
+		remoteWireSig *lnwire.Sig
+		closingSigs   lnwire.ClosingSigs
+	)
+	switch {
+	case hasLocal && hasRemote:
+		remoteWireSig = msg.ClosingSigs.CloserAndClosee
+
+	case hasRemote:
+		remoteWireSig = msg.ClosingSigs.CloserNoClosee
+
+	case hasLocal:
+		remoteWireSig = msg.ClosingSigs.NoCloserClosee
+	}
+	if remoteWireSig == nil {
+		return nil, false, fmt.Errorf("closing complete doesn't " +
+			"contain signature for expected closing transaction")
+	}
+
+	localRawSig, _, _, err := c.cfg.Channel.CreateCloseProposal(
+		msg.FeeSatoshis, c.localDeliveryScript, c.remoteDeliveryScript,
+		opts...,
+	)
+	if err != nil {
+		return nil, false, err
+	}
+	localSig, err := lnwire.NewSigFromSignature(localRawSig)
+	if err != nil {
+		return nil, false, err
+	}
+
+	switch {
+	case hasLocal && hasRemote:
+		closingSigs.CloserAndClosee = &localSig
+
+	case hasRemote:
+		closingSigs.CloserNoClosee = &localSig
+
+	case hasLocal:
+		closingSigs.NoCloserClosee = &localSig
+	}
+
+	remoteSig, err := remoteWireSig.ToSignature()
+	if err != nil {
+		return nil, false, err
+	}
+
+	closeFin, err := c.completeRbfClose(
+		localRawSig, remoteSig, msg.FeeSatoshis, opts,
+	)
+	if err != nil {
+		return nil, false, err
+	}
+
+	closingSig := &lnwire.ClosingSig{
+		ChannelID:    c.cid,
+		CloserScript: msg.CloserScript,
+		CloseeScript: msg.CloseeScript,
+		FeeSatoshis:  msg.FeeSatoshis,
+		LockTime:     msg.LockTime,
+		ClosingSigs:  closingSigs,
+	}
+
+	return []lnwire.Message{closingSig}, closeFin, nil
+}
+
+// handleClosingSig handles the signature of the remote party for the closing
+// transaction we proposed with the simple close protocol. The fully signed
+// closing transaction is then broadcast.
+func (c *ChanCloser) handleClosingSig(msg *lnwire.ClosingSig) ([]lnwire.Message,
+	bool, error) {
+
+	proposal := c.pendingRbfProposal
+	if proposal == nil {
+		return nil, false, fmt.Errorf("received closing sig without " +
+			"pending closing transaction")
+	}
+
+	// The remote party must sign our latest proposal, with the same
+	// variant we signed.
+	proposed := proposal.msg
+	if msg.FeeSatoshis != proposed.FeeSatoshis ||
+		msg.LockTime != proposed.LockTime ||
+		!bytes.Equal(msg.CloserScript, proposed.CloserScript) ||
+		!bytes.Equal(msg.CloseeScript, proposed.CloseeScript) {
+
+		return nil, false, fmt.Errorf("closing sig doesn't match " +
+			"proposed closing transaction")
+	}
+
+	var remoteWireSig *lnwire.Sig
+	switch {
+	case proposed.ClosingSigs.CloserAndClosee != nil:
+		remoteWireSig = msg.ClosingSigs.CloserAndClosee
+
+	case proposed.ClosingSigs.CloserNoClosee != nil:
+		remoteWireSig = msg.ClosingSigs.CloserNoClosee
+
+	case proposed.ClosingSigs.NoCloserClosee != nil:
+		remoteWireSig = msg.ClosingSigs.NoCloserClosee
+	}
+	if remoteWireSig == nil {
+		return nil, false, fmt.Errorf("closing sig doesn't contain " +
+			"signature for proposed closing transaction")
+	}
+
+	remoteSig, err := remoteWireSig.ToSignature()
+	if err != nil {
+		return nil, false, err
+	}
+	localSig, err := proposal.sig.ToSignature()
+	if err != nil {
+		return nil, false, err
+	}
+
+	closeFin, err := c.completeRbfClose(
+		localSig, remoteSig, proposed.FeeSatoshis,
+		rbfCloseOpts(proposed.LockTime, true),
+	)
+	if err != nil {
+		return nil, false, err
+	}
+
+	c.pendingRbfProposal = nil
+
+	return nil, closeFin, nil
+}
+
+// completeRbfClose assembles, persists and broadcasts a fully signed closing
+// transaction of the simple close protocol. It returns true if this is the
+// first closing transaction that was broadcast.
+func (c *ChanCloser) completeRbfClose(localSig, remoteSig input.Signature,
+	fee btcutil.Amount, opts []lnwallet.ChanCloseOpt) (bool, error) {
+
+	closeTx, _, err := c.cfg.Channel.CompleteCooperativeClose(
+		localSig, remoteSig, c.localDeliveryScript,
+		c.remoteDeliveryScript, fee, opts...,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	// Before publishing the closing tx, we persist it to the database,
+	// such that it can be republished if something goes wrong, and any
+	// of the versions we signed are recognized once confirmed.
+	err = c.cfg.Channel.MarkCoopBroadcasted(closeTx, c.locallyInitiated)
+	if err != nil {
+		return false, err
+	}
+
+	chancloserLog.Infof("Broadcasting rbf cooperative close tx: %v",
+		newLogClosure(func() string {
+			return spew.Sdump(closeTx)
+		}),
+	)
+
+	chanID := c.cfg.Channel.ShortChanID()
+	closeLabel := labels.MakeLabel(labels.LabelTypeChannelClose, &chanID)
+	if err := c.cfg.BroadcastTx(closeTx, closeLabel); err != nil {
+		return false, err
+	}
+
+	firstClose := c.closingTx == nil
+	c.closingTx = closeTx
+
+	return firstClose, nil
+}
+
 // feeInAcceptableRange returns true if the passed remote fee is deemed to be
 // in an "acceptable" range to our local fee. This is an attempt at a
 // compromise and to ensure that the fee negotiation has a stopping point. We
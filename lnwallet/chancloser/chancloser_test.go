@@ -11,7 +11,9 @@ import (
 	"github.com/btcsuite/btcd/txscript"
 	"github.com/btcsuite/btcd/wire"
 	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/htlcswitch"
 	"github.com/lightningnetwork/lnd/input"
+	"github.com/lightningnetwork/lnd/lnwallet"
 	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/stretchr/testify/require"
@@ -145,6 +147,10 @@ func (m *mockChannel) MarkCoopBroadcasted(*wire.MsgTx, bool) error {
 	return nil
 }
 
+func (m *mockChannel) AddCoopCloseTxid(chainhash.Hash) error {
+	return nil
+}
+
 func (m *mockChannel) IsInitiator() bool {
 	return m.initiator
 }
@@ -162,7 +168,7 @@ func (m *mockChannel) RemoteUpfrontShutdownScript() lnwire.DeliveryAddress {
 }
 
 func (m *mockChannel) CreateCloseProposal(fee btcutil.Amount,
-	localScript, remoteScript []byte,
+	localScript, remoteScript []byte, _ ...lnwallet.ChanCloseOpt,
 ) (input.Signature, *chainhash.Hash, btcutil.Amount, error) {
 
 	return nil, nil, 0, nil
@@ -170,11 +176,19 @@ func (m *mockChannel) CreateCloseProposal(fee btcutil.Amount,
 
 func (m *mockChannel) CompleteCooperativeClose(localSig,
 	remoteSig input.Signature, localScript, remoteScript []byte,
-	proposedFee btcutil.Amount) (*wire.MsgTx, btcutil.Amount, error) {
+	proposedFee btcutil.Amount,
+	_ ...lnwallet.ChanCloseOpt) (*wire.MsgTx, btcutil.Amount, error) {
 
 	return nil, 0, nil
 }
 
+func (m *mockChannel) CoopCloseOutputs(proposedFee btcutil.Amount,
+	localScript, remoteScript []byte,
+	_ ...lnwallet.ChanCloseOpt) (bool, bool, error) {
+
+	return true, true, nil
+}
+
 func (m *mockChannel) LocalBalanceDust() bool {
 	return false
 }
@@ -323,6 +337,153 @@ func TestMaxFeeBailOut(t *testing.T) {
 	}
 }
 
+// newRbfTestCloser creates a chan closer for the given channel using the simple
+// close protocol, along with a channel the broadcast closing transactions are
+// sent on.
+func newRbfTestCloser(channel *lnwallet.LightningChannel,
+	deliveryScript []byte, closeReq *htlcswitch.ChanClose) (*ChanCloser,
+	chan *wire.MsgTx) {
+
+	broadcasts := make(chan *wire.MsgTx, 10)
+	cfg := ChanCloseCfg{
+		Channel: channel,
+		BroadcastTx: func(tx *wire.MsgTx, _ string) error {
+			broadcasts <- tx
+			return nil
+		},
+		DisableChannel: func(wire.OutPoint) error {
+			return nil
+		},
+		Disconnect: func() error {
+			return nil
+		},
+		ChainParams:  &chaincfg.RegressionNetParams,
+		FeeEstimator: &SimpleCoopFeeEstimator{},
+		SimpleClose:  true,
+	}
+
+	chanCloser := NewChanCloser(
+		cfg, deliveryScript, chainfee.FeePerKwFloor, 100, closeReq,
+		closeReq != nil,
+	)
+
+	return chanCloser, broadcasts
+}
+
+// TestRbfCoopClose tests that both parties can propose their own closing
+// transaction with the simple close protocol, and that a closing transaction
+// can be replaced with one paying a higher fee.
+func TestRbfCoopClose(t *testing.T) {
+	t.Parallel()
+
+	aliceChannel, bobChannel, err := lnwallet.CreateTestChannels(
+		t, channeldb.SingleFunderTweaklessBit,
+	)
+	require.NoError(t, err)
+
+	aliceScript, err := txscript.NewScriptBuilder().AddOp(txscript.OP_0).
+		AddData(bytes.Repeat([]byte{0x1}, 20)).Script()
+	require.NoError(t, err)
+	bobScript, err := txscript.NewScriptBuilder().AddOp(txscript.OP_0).
+		AddData(bytes.Repeat([]byte{0x2}, 20)).Script()
+	require.NoError(t, err)
+
+	alice, aliceBroadcasts := newRbfTestCloser(
+		aliceChannel, aliceScript, &htlcswitch.ChanClose{},
+	)
+	bob, bobBroadcasts := newRbfTestCloser(bobChannel, bobScript, nil)
+
+	// Alice initiates the shutdown, which Bob responds to without
+	// proposing a closing transaction.
+	shutdown, err := alice.ShutdownChan()
+	require.NoError(t, err)
+
+	msgs, closeFin, err := bob.ProcessCloseMsg(shutdown)
+	require.NoError(t, err)
+	require.False(t, closeFin)
+	require.Len(t, msgs, 1)
+	require.IsType(t, &lnwire.Shutdown{}, msgs[0])
+
+	// Once Alice receives Bob's shutdown, she'll propose her closing
+	// transaction at her ideal fee rate.
+	msgs, closeFin, err = alice.ProcessCloseMsg(msgs[0])
+	require.NoError(t, err)
+	require.False(t, closeFin)
+	require.Len(t, msgs, 1)
+
+	closingComplete, ok := msgs[0].(*lnwire.ClosingComplete)
+	require.True(t, ok)
+	require.NotNil(t, closingComplete.ClosingSigs.CloserAndClosee)
+
+	// Bob countersigns and broadcasts Alice's closing transaction.
+	msgs, closeFin, err = bob.ProcessCloseMsg(closingComplete)
+	require.NoError(t, err)
+	require.True(t, closeFin)
+	require.Len(t, msgs, 1)
+
+	bobTx := <-bobBroadcasts
+	require.Equal(t, wire.MaxTxInSequenceNum-2, bobTx.TxIn[0].Sequence)
+
+	// Alice broadcasts the same transaction once she receives Bob's
+	// signature.
+	msgs, closeFin, err = alice.ProcessCloseMsg(msgs[0])
+	require.NoError(t, err)
+	require.True(t, closeFin)
+	require.Empty(t, msgs)
+
+	aliceTx := <-aliceBroadcasts
+	require.Equal(t, bobTx.TxHash(), aliceTx.TxHash())
+
+	closingTx, err := alice.ClosingTx()
+	require.NoError(t, err)
+	require.Equal(t, aliceTx.TxHash(), closingTx.TxHash())
+
+	// Alice can't replace her closing transaction without increasing the
+	// fee rate.
+	_, _, err = alice.ProposeRbfClose(chainfee.FeePerKwFloor)
+	require.ErrorIs(t, err, ErrFeeRateNotIncreased)
+
+	// With a higher fee rate, the closing transaction is replaced.
+	closingComplete, txid, err := alice.ProposeRbfClose(
+		chainfee.FeePerKwFloor * 2,
+	)
+	require.NoError(t, err)
+
+	msgs, closeFin, err = bob.ProcessCloseMsg(closingComplete)
+	require.NoError(t, err)
+	require.False(t, closeFin)
+
+	_, closeFin, err = alice.ProcessCloseMsg(msgs[0])
+	require.NoError(t, err)
+	require.False(t, closeFin)
+
+	aliceTx = <-aliceBroadcasts
+	require.Equal(t, *txid, aliceTx.TxHash())
+	require.Equal(t, aliceTx.TxHash(), (<-bobBroadcasts).TxHash())
+
+	// Bob can propose his own closing transaction, which pays the fee from
+	// his own output.
+	closingComplete, txid, err = bob.ProposeRbfClose(
+		chainfee.FeePerKwFloor,
+	)
+	require.NoError(t, err)
+
+	msgs, _, err = alice.ProcessCloseMsg(closingComplete)
+	require.NoError(t, err)
+
+	_, _, err = bob.ProcessCloseMsg(msgs[0])
+	require.NoError(t, err)
+
+	bobTx = <-bobBroadcasts
+	require.Equal(t, *txid, bobTx.TxHash())
+	require.Equal(t, bobTx.TxHash(), (<-aliceBroadcasts).TxHash())
+
+	// A closing signature that doesn't match a pending proposal is
+	// rejected.
+	_, _, err = bob.ProcessCloseMsg(msgs[0])
+	require.Error(t, err)
+}
+
 // TestParseUpfrontShutdownAddress tests the we are able to parse the upfront
 // shutdown address properly.
 func TestParseUpfrontShutdownAddress(t *testing.T) {
@@ -343,6 +343,12 @@ type PaymentDescriptor struct {
 	// NOTE: Populated only on add payment descriptor entry types.
 	OnionBlob []byte
 
+	// ExtraData is the tlv stream that was appended to the
+	// update_add_htlc message. It may carry custom wire records.
+	//
+	// NOTE: Populated only on add payment descriptor entry types.
+	ExtraData lnwire.ExtraOpaqueData
+
 	// ShaOnionBlob is a sha of the onion blob.
 	//
 	// NOTE: Populated only in payment descriptor with MalformedFail type.
@@ -421,6 +427,7 @@ func PayDescsFromRemoteLogUpdates(chanID lnwire.ShortChannelID, height uint64,
 					Height: height,
 					Index:  uint16(i),
 				},
+				ExtraData: wireMsg.ExtraData,
 			}
 			pd.OnionBlob = make([]byte, len(wireMsg.OnionBlob))
 			copy(pd.OnionBlob[:], wireMsg.OnionBlob[:])
@@ -747,6 +754,7 @@ func (c *commitment) toDiskCommit(ourCommit bool) *channeldb.ChannelCommitment {
 		}
 		h.OnionBlob = make([]byte, len(htlc.OnionBlob))
 		copy(h.OnionBlob[:], htlc.OnionBlob)
+		h.ExtraData = htlc.ExtraData
 
 		if ourCommit && htlc.sig != nil {
 			h.Signature = htlc.sig.Serialize()
@@ -772,6 +780,7 @@ func (c *commitment) toDiskCommit(ourCommit bool) *channeldb.ChannelCommitment {
 		}
 		h.OnionBlob = make([]byte, len(htlc.OnionBlob))
 		copy(h.OnionBlob[:], htlc.OnionBlob)
+		h.ExtraData = htlc.ExtraData
 
 		if ourCommit && htlc.sig != nil {
 			h.Signature = htlc.sig.Serialize()
@@ -860,6 +869,7 @@ func (lc *LightningChannel) diskHtlcToPayDesc(feeRate chainfee.SatPerKWeight,
 		HtlcIndex:          htlc.HtlcIndex,
 		LogIndex:           htlc.LogIndex,
 		OnionBlob:          htlc.OnionBlob,
+		ExtraData:          htlc.ExtraData,
 		localOutputIndex:   localOutputIndex,
 		remoteOutputIndex:  remoteOutputIndex,
 		ourPkScript:        ourP2WSH,
@@ -1452,6 +1462,7 @@ func (lc *LightningChannel) logUpdateToPayDesc(logUpdate *channeldb.LogUpdate,
 			HtlcIndex:             wireMsg.ID,
 			LogIndex:              logUpdate.LogIndex,
 			addCommitHeightRemote: commitHeight,
+			ExtraData:             wireMsg.ExtraData,
 		}
 		pd.OnionBlob = make([]byte, len(wireMsg.OnionBlob))
 		copy(pd.OnionBlob[:], wireMsg.OnionBlob[:])
@@ -1649,6 +1660,7 @@ func (lc *LightningChannel) remoteLogUpdateToPayDesc(logUpdate *channeldb.LogUpd
 			HtlcIndex:            wireMsg.ID,
 			LogIndex:             logUpdate.LogIndex,
 			addCommitHeightLocal: commitHeight,
+			ExtraData:            wireMsg.ExtraData,
 		}
 		pd.OnionBlob = make([]byte, len(wireMsg.OnionBlob))
 		copy(pd.OnionBlob, wireMsg.OnionBlob[:])
@@ -2326,18 +2338,27 @@ func NewBreachRetribution(chanState *channeldb.OpenChannel, stateNum uint64,
 		revocationPreimage[:],
 	)
 
+	// The commitment of the channel may have been upgraded since this
+	// state was created, so we'll look up the parameters that were used
+	// to construct it.
+	params, err := chanState.CommitParamsAt(false, stateNum)
+	if err != nil {
+		return nil, err
+	}
+	chanType := params.ChanType
+
 	// With the commitment point generated, we can now generate the four
 	// keys we'll need to reconstruct the commitment state,
 	keyRing := DeriveCommitmentKeys(
-		commitmentPoint, false, chanState.ChanType,
-		&chanState.LocalChanCfg, &chanState.RemoteChanCfg,
+		commitmentPoint, false, chanType,
+		&params.LocalChanCfg, &params.RemoteChanCfg,
 	)
 
 	// Next, reconstruct the scripts as they were present at this state
 	// number so we can have the proper witness script to sign and include
 	// within the final witness.
 	var leaseExpiry uint32
-	if chanState.ChanType.HasLeaseExpiration() {
+	if chanType.HasLeaseExpiration() {
 		leaseExpiry = chanState.ThawHeight
 	}
 
@@ -2345,16 +2366,16 @@ func NewBreachRetribution(chanState *channeldb.OpenChannel, stateNum uint64,
 	// going to us will be a to-remote script with our local params.
 	isRemoteInitiator := !chanState.IsInitiator
 	ourScript, ourDelay, err := CommitScriptToRemote(
-		chanState.ChanType, isRemoteInitiator, keyRing.ToRemoteKey,
+		chanType, isRemoteInitiator, keyRing.ToRemoteKey,
 		leaseExpiry,
 	)
 	if err != nil {
 		return nil, err
 	}
 
-	theirDelay := uint32(chanState.RemoteChanCfg.CsvDelay)
+	theirDelay := uint32(params.RemoteChanCfg.CsvDelay)
 	theirScript, err := CommitScriptToSelf(
-		chanState.ChanType, isRemoteInitiator, keyRing.ToLocalKey,
+		chanType, isRemoteInitiator, keyRing.ToLocalKey,
 		keyRing.RevocationKey, theirDelay, leaseExpiry,
 	)
 	if err != nil {
@@ -2372,7 +2393,7 @@ func NewBreachRetribution(chanState *channeldb.OpenChannel, stateNum uint64,
 	// we need.
 	if revokedLog != nil {
 		br, ourAmt, theirAmt, err = createBreachRetribution(
-			revokedLog, spendTx, chanState, keyRing,
+			revokedLog, spendTx, chanState, params, keyRing,
 			commitmentSecret, leaseExpiry,
 		)
 		if err != nil {
@@ -2387,8 +2408,8 @@ func NewBreachRetribution(chanState *channeldb.OpenChannel, stateNum uint64,
 		// data can still function. This branch can be deleted once we
 		// are confident that no legacy format is in use.
 		br, ourAmt, theirAmt, err = createBreachRetributionLegacy(
-			revokedLogLegacy, chanState, keyRing, commitmentSecret,
-			ourScript, theirScript, leaseExpiry,
+			revokedLogLegacy, chanState, params, keyRing,
+			commitmentSecret, ourScript, theirScript, leaseExpiry,
 		)
 		if err != nil {
 			return nil, err
@@ -2401,7 +2422,7 @@ func NewBreachRetribution(chanState *channeldb.OpenChannel, stateNum uint64,
 	//
 	// If our balance exceeds the remote party's dust limit, instantiate
 	// the sign descriptor for our output.
-	if ourAmt >= int64(chanState.RemoteChanCfg.DustLimit) {
+	if ourAmt >= int64(params.RemoteChanCfg.DustLimit) {
 		br.LocalOutputSignDesc = &input.SignDescriptor{
 			SingleTweak:   keyRing.LocalCommitKeyTweak,
 			KeyDesc:       chanState.LocalChanCfg.PaymentBasePoint,
@@ -2416,7 +2437,7 @@ func NewBreachRetribution(chanState *channeldb.OpenChannel, stateNum uint64,
 
 	// Similarly, if their balance exceeds the remote party's dust limit,
 	// assemble the sign descriptor for their output, which we can sweep.
-	if theirAmt >= int64(chanState.RemoteChanCfg.DustLimit) {
+	if theirAmt >= int64(params.RemoteChanCfg.DustLimit) {
 		br.RemoteOutputSignDesc = &input.SignDescriptor{
 			KeyDesc: chanState.LocalChanCfg.
 				RevocationBasePoint,
@@ -2442,22 +2463,24 @@ func NewBreachRetribution(chanState *channeldb.OpenChannel, stateNum uint64,
 }
 
 // createHtlcRetribution is a helper function to construct an HtlcRetribution
-// based on the passed params.
+// based on the passed params. The commitment params must be the ones that were
+// used to construct the revoked commitment.
 func createHtlcRetribution(chanState *channeldb.OpenChannel,
-	keyRing *CommitmentKeyRing, commitHash chainhash.Hash,
-	commitmentSecret *btcec.PrivateKey, leaseExpiry uint32,
+	params *channeldb.CommitParams, keyRing *CommitmentKeyRing,
+	commitHash chainhash.Hash, commitmentSecret *btcec.PrivateKey,
+	leaseExpiry uint32,
 	htlc *channeldb.HTLCEntry) (HtlcRetribution, error) {
 
 	var emptyRetribution HtlcRetribution
 
-	theirDelay := uint32(chanState.RemoteChanCfg.CsvDelay)
+	theirDelay := uint32(params.RemoteChanCfg.CsvDelay)
 	isRemoteInitiator := !chanState.IsInitiator
 
 	// We'll generate the original second level witness script now, as
 	// we'll need it if we're revoking an HTLC output on the remote
 	// commitment transaction, and *they* go to the second level.
 	secondLevelScript, err := SecondLevelHtlcScript(
-		chanState.ChanType, isRemoteInitiator,
+		params.ChanType, isRemoteInitiator,
 		keyRing.RevocationKey, keyRing.ToLocalKey, theirDelay,
 		leaseExpiry,
 	)
@@ -2471,7 +2494,7 @@ func createHtlcRetribution(chanState *channeldb.OpenChannel,
 	// then from the PoV of the remote commitment state, they're the
 	// receiver of this HTLC.
 	htlcPkScript, htlcWitnessScript, err := genHtlcScript(
-		chanState.ChanType, htlc.Incoming, false,
+		params.ChanType, htlc.Incoming, false,
 		htlc.RefundTimeout, htlc.RHash, keyRing,
 	)
 	if err != nil {
@@ -2508,7 +2531,8 @@ func createHtlcRetribution(chanState *channeldb.OpenChannel,
 // ErrRevLogDataMissing is returned.
 func createBreachRetribution(revokedLog *channeldb.RevocationLog,
 	spendTx *wire.MsgTx, chanState *channeldb.OpenChannel,
-	keyRing *CommitmentKeyRing, commitmentSecret *btcec.PrivateKey,
+	params *channeldb.CommitParams, keyRing *CommitmentKeyRing,
+	commitmentSecret *btcec.PrivateKey,
 	leaseExpiry uint32) (*BreachRetribution, int64, int64, error) {
 
 	commitHash := revokedLog.CommitTxHash
@@ -2517,7 +2541,7 @@ func createBreachRetribution(revokedLog *channeldb.RevocationLog,
 	htlcRetributions := make([]HtlcRetribution, len(revokedLog.HTLCEntries))
 	for i, htlc := range revokedLog.HTLCEntries {
 		hr, err := createHtlcRetribution(
-			chanState, keyRing, commitHash,
+			chanState, params, keyRing, commitHash,
 			commitmentSecret, leaseExpiry, htlc,
 		)
 		if err != nil {
@@ -2616,7 +2640,8 @@ func createBreachRetribution(revokedLog *channeldb.RevocationLog,
 // BreachRetribution using a ChannelCommitment. Returns the constructed
 // retribution, our amount, their amount, and a possible non-nil error.
 func createBreachRetributionLegacy(revokedLog *channeldb.ChannelCommitment,
-	chanState *channeldb.OpenChannel, keyRing *CommitmentKeyRing,
+	chanState *channeldb.OpenChannel, params *channeldb.CommitParams,
+	keyRing *CommitmentKeyRing,
 	commitmentSecret *btcec.PrivateKey,
 	ourScript, theirScript *ScriptInfo,
 	leaseExpiry uint32) (*BreachRetribution, int64, int64, error) {
@@ -2648,10 +2673,10 @@ func createBreachRetributionLegacy(revokedLog *channeldb.ChannelCommitment,
 		// If the HTLC is dust, then we'll skip it as it doesn't have
 		// an output on the commitment transaction.
 		if HtlcIsDust(
-			chanState.ChanType, htlc.Incoming, false,
+			params.ChanType, htlc.Incoming, false,
 			chainfee.SatPerKWeight(revokedLog.FeePerKw),
 			htlc.Amt.ToSatoshis(),
-			chanState.RemoteChanCfg.DustLimit,
+			params.RemoteChanCfg.DustLimit,
 		) {
 
 			continue
@@ -2665,7 +2690,7 @@ func createBreachRetributionLegacy(revokedLog *channeldb.ChannelCommitment,
 			Amt:           htlc.Amt.ToSatoshis(),
 		}
 		hr, err := createHtlcRetribution(
-			chanState, keyRing, commitHash,
+			chanState, params, keyRing, commitHash,
 			commitmentSecret, leaseExpiry, entry,
 		)
 		if err != nil {
@@ -3380,6 +3405,7 @@ func (lc *LightningChannel) createCommitDiff(
 				Amount:      pd.Amount,
 				Expiry:      pd.Timeout,
 				PaymentHash: pd.RHash,
+				ExtraData:   pd.ExtraData,
 			}
 			copy(htlc.OnionBlob[:], pd.OnionBlob)
 			logUpdate.UpdateMsg = htlc
@@ -3517,6 +3543,7 @@ func (lc *LightningChannel) getUnsignedAckedUpdates() []channeldb.LogUpdate {
 				Amount:      pd.Amount,
 				Expiry:      pd.Timeout,
 				PaymentHash: pd.RHash,
+				ExtraData:   pd.ExtraData,
 			}
 			copy(htlc.OnionBlob[:], pd.OnionBlob)
 			logUpdate.UpdateMsg = htlc
@@ -4254,6 +4281,15 @@ func (lc *LightningChannel) computeView(view *htlcView, remoteChain bool,
 	}
 	nextHeight := commitChain.tip().height + 1
 
+	// If this is the first commitment after an upgrade that added anchor
+	// outputs, the initiator pays for the anchors.
+	anchorCost := lc.upgradeAnchorCost(remoteChain, nextHeight)
+	if lc.channelState.IsInitiator {
+		ourBalance -= anchorCost
+	} else {
+		theirBalance -= anchorCost
+	}
+
 	// Initiate feePerKw to the last committed fee for this chain as we'll
 	// need this to determine which HTLCs are dust, and also the final fee
 	// rate.
@@ -4809,13 +4845,28 @@ func (lc *LightningChannel) oweCommitment(local bool) bool {
 			lastLocalCommit.ourMessageIndex
 	}
 
+	// If the commitment of the channel was upgraded, a commitment
+	// signature is owed until the commitment of the other party uses the
+	// new parameters.
+	var upgradePending bool
+	if upgrade := lc.lastCommitUpgrade(); upgrade != nil {
+		if local {
+			upgradePending = lastRemoteCommit.height <
+				upgrade.RemoteHeight
+		} else {
+			upgradePending = lastLocalCommit.height <
+				upgrade.LocalHeight
+		}
+	}
+
 	// If any of the conditions above is true, we owe a commitment
 	// signature.
-	oweCommitment := localUpdatesPending || remoteUpdatesPending
+	oweCommitment := localUpdatesPending || remoteUpdatesPending ||
+		upgradePending
 
 	lc.log.Tracef("%v owes commit: %v (local updates: %v, "+
-		"remote updates %v)", perspective, oweCommitment,
-		localUpdatesPending, remoteUpdatesPending)
+		"remote updates %v, upgrade: %v)", perspective, oweCommitment,
+		localUpdatesPending, remoteUpdatesPending, upgradePending)
 
 	return oweCommitment
 }
@@ -4831,6 +4882,19 @@ func (lc *LightningChannel) PendingLocalUpdateCount() uint64 {
 	return lc.localUpdateLog.logIndex - lastRemoteCommit.ourMessageIndex
 }
 
+// HasPendingLocalUpdates returns true if any of our updates isn't yet
+// irrevocably committed to by both commitments. This is the case until both
+// parties have revoked the commitments that don't include the update.
+func (lc *LightningChannel) HasPendingLocalUpdates() bool {
+	lc.RLock()
+	defer lc.RUnlock()
+
+	logIndex := lc.localUpdateLog.logIndex
+
+	return lc.localCommitChain.tail().ourMessageIndex != logIndex ||
+		lc.remoteCommitChain.tail().ourMessageIndex != logIndex
+}
+
 // RevokeCurrentCommitment revokes the next lowest unrevoked commitment
 // transaction in the local commitment chain. As a result the edge of our
 // revocation window is extended by one, and the tail of our local commitment
@@ -5052,6 +5116,7 @@ func (lc *LightningChannel) ReceiveRevocation(revMsg *lnwire.RevokeAndAck) (
 				Amount:      pd.Amount,
 				Expiry:      pd.Timeout,
 				PaymentHash: pd.RHash,
+				ExtraData:   pd.ExtraData,
 			}
 			copy(htlc.OnionBlob[:], pd.OnionBlob)
 			logUpdate.UpdateMsg = htlc
@@ -5244,58 +5309,125 @@ func (lc *LightningChannel) GetDustSum(remote bool) lnwire.MilliSatoshi {
 	lc.RLock()
 	defer lc.RUnlock()
 
+	commit := lc.channelState.LocalCommitment
+	if remote {
+		commit = lc.channelState.RemoteCommitment
+	}
+
+	dustSum, _ := lc.dustSumAndWeight(
+		remote, chainfee.SatPerKWeight(commit.FeePerKw),
+	)
+
+	return dustSum
+}
+
+// FeeExposure returns the total value that would go to miners if the
+// commitment of the given party confirmed at the passed feerate. This is the
+// commitment fee, the value of the anchor outputs and the value of all HTLCs
+// that are trimmed to dust at that feerate. The commitment fee is included
+// regardless of which party pays it, as a high feerate trims the HTLCs of both
+// parties.
+//
+// NOTE: Like GetDustSum, this over-estimates the exposure as it includes the
+// HTLCs that haven't been committed yet.
+func (lc *LightningChannel) FeeExposure(remote bool,
+	feeRate chainfee.SatPerKWeight) lnwire.MilliSatoshi {
+
+	lc.RLock()
+	defer lc.RUnlock()
+
+	dustSum, weight := lc.dustSumAndWeight(remote, feeRate)
+
+	exposure := dustSum + lnwire.NewMSatFromSatoshis(
+		feeRate.FeeForWeight(weight),
+	)
+	if lc.channelState.ChanType.HasAnchors() {
+		exposure += lnwire.NewMSatFromSatoshis(2 * anchorSize)
+	}
+
+	return exposure
+}
+
+// dustSumAndWeight returns the sum of the HTLCs that are dust on the
+// commitment of the given party at the passed feerate, along with the weight
+// of that commitment including its non-dust HTLCs.
+//
+// NOTE: The channel's read lock must be held when calling this method.
+func (lc *LightningChannel) dustSumAndWeight(remote bool,
+	feeRate chainfee.SatPerKWeight) (lnwire.MilliSatoshi, int64) {
+
 	var dustSum lnwire.MilliSatoshi
 
 	dustLimit := lc.channelState.LocalChanCfg.DustLimit
-	commit := lc.channelState.LocalCommitment
 	if remote {
 		// Calculate dust sum on the remote's commitment.
 		dustLimit = lc.channelState.RemoteChanCfg.DustLimit
-		commit = lc.channelState.RemoteCommitment
 	}
 
 	chanType := lc.channelState.ChanType
-	feeRate := chainfee.SatPerKWeight(commit.FeePerKw)
+	weight := CommitWeight(chanType)
+
+	// addHtlcs evaluates all the HTLC adds of the given update log against
+	// the dust limit.
+	addHtlcs := func(updates *updateLog, incoming bool) {
+		for e := updates.Front(); e != nil; e = e.Next() {
+			pd := e.Value.(*PaymentDescriptor)
+			if pd.EntryType != Add {
+				continue
+			}
 
-	// Grab all of our HTLCs and evaluate against the dust limit.
-	for e := lc.localUpdateLog.Front(); e != nil; e = e.Next() {
-		pd := e.Value.(*PaymentDescriptor)
-		if pd.EntryType != Add {
-			continue
-		}
+			amt := pd.Amount.ToSatoshis()
 
-		amt := pd.Amount.ToSatoshis()
+			// If the satoshi amount is under the dust limit, add
+			// the msat amount to the dust sum. Otherwise the HTLC
+			// gets an output on the commitment.
+			if HtlcIsDust(
+				chanType, incoming, !remote, feeRate, amt,
+				dustLimit,
+			) {
 
-		// If the satoshi amount is under the dust limit, add the msat
-		// amount to the dust sum.
-		if HtlcIsDust(
-			chanType, false, !remote, feeRate, amt, dustLimit,
-		) {
+				dustSum += pd.Amount
+				continue
+			}
 
-			dustSum += pd.Amount
+			weight += input.HTLCWeight
 		}
 	}
 
-	// Grab all of their HTLCs and evaluate against the dust limit.
-	for e := lc.remoteUpdateLog.Front(); e != nil; e = e.Next() {
-		pd := e.Value.(*PaymentDescriptor)
-		if pd.EntryType != Add {
-			continue
-		}
+	// Grab all of our HTLCs and evaluate against the dust limit, followed
+	// by all of theirs.
+	addHtlcs(lc.localUpdateLog, false)
+	addHtlcs(lc.remoteUpdateLog, true)
 
-		amt := pd.Amount.ToSatoshis()
+	return dustSum, weight
+}
 
-		// If the satoshi amount is under the dust limit, add the msat
-		// amount to the dust sum.
-		if HtlcIsDust(
-			chanType, true, !remote, feeRate, amt, dustLimit,
-		) {
+// NextFeeRate returns the feerate of the next commitment. This is the feerate
+// of the most recent fee update that hasn't been locked in yet, or the current
+// commitment feerate if there is none.
+func (lc *LightningChannel) NextFeeRate() chainfee.SatPerKWeight {
+	lc.RLock()
+	defer lc.RUnlock()
+
+	// Only the initiator can send fee updates, so we'll look for the most
+	// recent one in its update log.
+	updates := lc.remoteUpdateLog
+	if lc.channelState.IsInitiator {
+		updates = lc.localUpdateLog
+	}
 
-			dustSum += pd.Amount
+	for e := updates.Back(); e != nil; e = e.Prev() {
+		pd := e.Value.(*PaymentDescriptor)
+		if pd.EntryType != FeeUpdate {
+			continue
 		}
+
+		return chainfee.SatPerKWeight(pd.Amount.ToSatoshis())
 	}
 
-	return dustSum
+	return chainfee.SatPerKWeight(
+		lc.channelState.LocalCommitment.FeePerKw,
+	)
 }
 
 // MayAddOutgoingHtlc validates whether we can add an outgoing htlc to this
@@ -5357,6 +5489,7 @@ func (lc *LightningChannel) htlcAddDescriptor(htlc *lnwire.UpdateAddHTLC,
 		LogIndex:       lc.localUpdateLog.logIndex,
 		HtlcIndex:      lc.localUpdateLog.htlcCounter,
 		OnionBlob:      htlc.OnionBlob[:],
+		ExtraData:      htlc.ExtraData,
 		OpenCircuitKey: openKey,
 	}
 }
@@ -5413,6 +5546,7 @@ func (lc *LightningChannel) ReceiveHTLC(htlc *lnwire.UpdateAddHTLC) (uint64, err
 		LogIndex:  lc.remoteUpdateLog.logIndex,
 		HtlcIndex: lc.remoteUpdateLog.htlcCounter,
 		OnionBlob: htlc.OnionBlob[:],
+		ExtraData: htlc.ExtraData,
 	}
 
 	localACKedIndex := lc.remoteCommitChain.tail().ourMessageIndex
@@ -5841,26 +5975,39 @@ func NewUnilateralCloseSummary(chanState *channeldb.OpenChannel, signer input.Si
 	remoteCommit channeldb.ChannelCommitment,
 	commitPoint *btcec.PublicKey) (*UnilateralCloseSummary, error) {
 
+	// Look up the parameters that were used to construct the remote
+	// commitment. If we don't have the commitment stored, it must be their
+	// latest one, which uses the current parameters.
+	commitHeight := remoteCommit.CommitHeight
+	if remoteCommit.CommitTx == nil {
+		commitHeight = math.MaxUint64
+	}
+	params, err := chanState.CommitParamsAt(false, commitHeight)
+	if err != nil {
+		return nil, err
+	}
+	chanType := params.ChanType
+
 	// First, we'll generate the commitment point and the revocation point
 	// so we can re-construct the HTLC state and also our payment key.
 	isOurCommit := false
 	keyRing := DeriveCommitmentKeys(
-		commitPoint, isOurCommit, chanState.ChanType,
-		&chanState.LocalChanCfg, &chanState.RemoteChanCfg,
+		commitPoint, isOurCommit, chanType,
+		&params.LocalChanCfg, &params.RemoteChanCfg,
 	)
 
 	// Next, we'll obtain HTLC resolutions for all the outgoing HTLC's we
 	// had on their commitment transaction.
 	var leaseExpiry uint32
-	if chanState.ChanType.HasLeaseExpiration() {
+	if chanType.HasLeaseExpiration() {
 		leaseExpiry = chanState.ThawHeight
 	}
 	isRemoteInitiator := !chanState.IsInitiator
 	htlcResolutions, err := extractHtlcResolutions(
 		chainfee.SatPerKWeight(remoteCommit.FeePerKw), isOurCommit,
-		signer, remoteCommit.Htlcs, keyRing, &chanState.LocalChanCfg,
-		&chanState.RemoteChanCfg, commitSpend.SpendingTx,
-		chanState.ChanType, isRemoteInitiator, leaseExpiry,
+		signer, remoteCommit.Htlcs, keyRing, &params.LocalChanCfg,
+		&params.RemoteChanCfg, commitSpend.SpendingTx, chanType,
+		isRemoteInitiator, leaseExpiry,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("unable to create htlc "+
@@ -5873,7 +6020,7 @@ func NewUnilateralCloseSummary(chanState *channeldb.OpenChannel, signer input.Si
 	// locate the output index of our non-delayed output on the commitment
 	// transaction.
 	selfScript, maturityDelay, err := CommitScriptToRemote(
-		chanState.ChanType, isRemoteInitiator, keyRing.ToRemoteKey,
+		chanType, isRemoteInitiator, keyRing.ToRemoteKey,
 		leaseExpiry,
 	)
 	if err != nil {
@@ -6549,11 +6696,19 @@ func NewLocalForceCloseSummary(chanState *channeldb.OpenChannel,
 	signer input.Signer, commitTx *wire.MsgTx, stateNum uint64) (
 	*LocalForceCloseSummary, error) {
 
+	// Look up the parameters that were used to construct the commitment,
+	// as the commitment of the channel may have been upgraded since.
+	params, err := chanState.CommitParamsAt(true, stateNum)
+	if err != nil {
+		return nil, err
+	}
+	chanType := params.ChanType
+
 	// Re-derive the original pkScript for to-self output within the
 	// commitment transaction. We'll need this to find the corresponding
 	// output in the commitment transaction and potentially for creating
 	// the sign descriptor.
-	csvTimeout := uint32(chanState.LocalChanCfg.CsvDelay)
+	csvTimeout := uint32(params.LocalChanCfg.CsvDelay)
 
 	// We use the passed state num to derive our scripts, since in case
 	// this is after recovery, our latest channels state might not be up to
@@ -6564,16 +6719,16 @@ func NewLocalForceCloseSummary(chanState *channeldb.OpenChannel,
 	}
 	commitPoint := input.ComputeCommitmentPoint(revocation[:])
 	keyRing := DeriveCommitmentKeys(
-		commitPoint, true, chanState.ChanType,
-		&chanState.LocalChanCfg, &chanState.RemoteChanCfg,
+		commitPoint, true, chanType, &params.LocalChanCfg,
+		&params.RemoteChanCfg,
 	)
 
 	var leaseExpiry uint32
-	if chanState.ChanType.HasLeaseExpiration() {
+	if chanType.HasLeaseExpiration() {
 		leaseExpiry = chanState.ThawHeight
 	}
 	toLocalScript, err := CommitScriptToSelf(
-		chanState.ChanType, chanState.IsInitiator, keyRing.ToLocalKey,
+		chanType, chanState.IsInitiator, keyRing.ToLocalKey,
 		keyRing.RevocationKey, csvTimeout, leaseExpiry,
 	)
 	if err != nil {
@@ -6633,8 +6788,8 @@ func NewLocalForceCloseSummary(chanState *channeldb.OpenChannel,
 	localCommit := chanState.LocalCommitment
 	htlcResolutions, err := extractHtlcResolutions(
 		chainfee.SatPerKWeight(localCommit.FeePerKw), true, signer,
-		localCommit.Htlcs, keyRing, &chanState.LocalChanCfg,
-		&chanState.RemoteChanCfg, commitTx, chanState.ChanType,
+		localCommit.Htlcs, keyRing, &params.LocalChanCfg,
+		&params.RemoteChanCfg, commitTx, chanType,
 		chanState.IsInitiator, leaseExpiry,
 	)
 	if err != nil {
@@ -6658,28 +6813,67 @@ func NewLocalForceCloseSummary(chanState *channeldb.OpenChannel,
 	}, nil
 }
 
-// CreateCloseProposal is used by both parties in a cooperative channel close
-// workflow to generate proposed close transactions and signatures. This method
-// should only be executed once all pending HTLCs (if any) on the channel have
-// been cleared/removed. Upon completion, the source channel will shift into
-// the "closing" state, which indicates that all incoming/outgoing HTLC
-// requests should be rejected. A signature for the closing transaction is
-// returned.
+// chanCloseOpt is a set of options that modify the cooperative close
+// transaction created for a channel.
+type chanCloseOpt struct {
+	// customSequence is the sequence of the funding input. If not set,
+	// the input is final.
+	customSequence *uint32
+
+	// customLockTime is the lock time of the close transaction.
+	customLockTime uint32
+
+	// localPaysFee is set if the fee payer is overridden. If true, we pay
+	// the whole fee, otherwise the remote party does.
+	localPaysFee *bool
+}
+
+// ChanCloseOpt is a functional option that can be used to modify the
+// cooperative close transaction.
+type ChanCloseOpt func(*chanCloseOpt)
+
+// WithCustomSequence can be used to specify a custom sequence for the funding
+// input of the cooperative close transaction, for example to signal
+// replaceability.
+func WithCustomSequence(sequence uint32) ChanCloseOpt {
+	return func(opts *chanCloseOpt) {
+		opts.customSequence = &sequence
+	}
+}
+
+// WithCustomLockTime can be used to specify a custom lock time for the
+// cooperative close transaction.
+func WithCustomLockTime(lockTime uint32) ChanCloseOpt {
+	return func(opts *chanCloseOpt) {
+		opts.customLockTime = lockTime
+	}
+}
+
+// WithCustomPayer can be used to specify which party pays the fee of the
+// cooperative close transaction. By default, the initiator of the channel
+// pays the whole fee.
+func WithCustomPayer(localPays bool) ChanCloseOpt {
+	return func(opts *chanCloseOpt) {
+		opts.localPaysFee = &localPays
+	}
+}
+
+// coopCloseTx creates the unsigned cooperative close transaction paying the
+// proposed fee, along with our final balance on it.
 //
-// TODO(roasbeef): caller should initiate signal to reject all incoming HTLCs,
-// settle any in flight.
-func (lc *LightningChannel) CreateCloseProposal(proposedFee btcutil.Amount,
-	localDeliveryScript []byte,
-	remoteDeliveryScript []byte) (input.Signature, *chainhash.Hash,
-	btcutil.Amount, error) {
+// NOTE: This method MUST be called with the channel's lock held.
+func (lc *LightningChannel) coopCloseTx(proposedFee btcutil.Amount,
+	localDeliveryScript, remoteDeliveryScript []byte,
+	closeOpts ...ChanCloseOpt) (*wire.MsgTx, btcutil.Amount, error) {
 
-	lc.Lock()
-	defer lc.Unlock()
+	var opts chanCloseOpt
+	for _, closeOpt := range closeOpts {
+		closeOpt(&opts)
+	}
 
-	// If we've already closed the channel, then ignore this request.
-	if lc.status == channelClosed {
-		// TODO(roasbeef): check to ensure no pending payments
-		return nil, nil, 0, ErrChanClosing
+	localPaysFee := lc.channelState.IsInitiator
+	if opts.localPaysFee != nil {
+		localPaysFee = *opts.localPaysFee
 	}
 
 	// Get the final balances after subtracting the proposed fee, taking
@@ -6687,23 +6881,108 @@ func (lc *LightningChannel) CreateCloseProposal(proposedFee btcutil.Amount,
 	// during the channel closing process.
 	ourBalance, theirBalance, err := CoopCloseBalance(
 		lc.channelState.ChanType, lc.channelState.IsInitiator,
-		proposedFee, lc.channelState.LocalCommitment,
+		proposedFee, lc.channelState.LocalCommitment, localPaysFee,
 	)
 	if err != nil {
-		return nil, nil, 0, err
+		return nil, 0, err
+	}
+
+	txIn := fundingTxIn(lc.channelState)
+	if opts.customSequence != nil {
+		txIn.Sequence = *opts.customSequence
 	}
 
 	closeTx := CreateCooperativeCloseTx(
-		fundingTxIn(lc.channelState), lc.channelState.LocalChanCfg.DustLimit,
+		txIn, lc.channelState.LocalChanCfg.DustLimit,
 		lc.channelState.RemoteChanCfg.DustLimit, ourBalance, theirBalance,
 		localDeliveryScript, remoteDeliveryScript,
 	)
+	closeTx.LockTime = opts.customLockTime
 
 	// Ensure that the transaction doesn't explicitly violate any
 	// consensus rules such as being too big, or having any value with a
 	// negative output.
 	tx := btcutil.NewTx(closeTx)
 	if err := blockchain.CheckTransactionSanity(tx); err != nil {
+		return nil, 0, err
+	}
+
+	return closeTx, ourBalance, nil
+}
+
+// isReplaceable returns true if the given transaction signals replaceability
+// as defined in BIP 125.
+func isReplaceable(tx *wire.MsgTx) bool {
+	for _, txIn := range tx.TxIn {
+		if txIn.Sequence < wire.MaxTxInSequenceNum-1 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CoopCloseOutputs returns whether our output and the output of the remote
+// party are present on the cooperative close transaction paying the proposed
+// fee. An output is omitted if it's below the dust limit of its owner.
+func (lc *LightningChannel) CoopCloseOutputs(proposedFee btcutil.Amount,
+	localDeliveryScript, remoteDeliveryScript []byte,
+	closeOpts ...ChanCloseOpt) (bool, bool, error) {
+
+	lc.RLock()
+	defer lc.RUnlock()
+
+	closeTx, _, err := lc.coopCloseTx(
+		proposedFee, localDeliveryScript, remoteDeliveryScript,
+		closeOpts...,
+	)
+	if err != nil {
+		return false, false, err
+	}
+
+	var hasLocal, hasRemote bool
+	for _, txOut := range closeTx.TxOut {
+		switch {
+		case bytes.Equal(txOut.PkScript, localDeliveryScript):
+			hasLocal = true
+
+		case bytes.Equal(txOut.PkScript, remoteDeliveryScript):
+			hasRemote = true
+		}
+	}
+
+	return hasLocal, hasRemote, nil
+}
+
+// CreateCloseProposal is used by both parties in a cooperative channel close
+// workflow to generate proposed close transactions and signatures. This method
+// should only be executed once all pending HTLCs (if any) on the channel have
+// been cleared/removed. Upon completion, the source channel will shift into
+// the "closing" state, which indicates that all incoming/outgoing HTLC
+// requests should be rejected. A signature for the closing transaction is
+// returned.
+//
+// TODO(roasbeef): caller should initiate signal to reject all incoming HTLCs,
+// settle any in flight.
+func (lc *LightningChannel) CreateCloseProposal(proposedFee btcutil.Amount,
+	localDeliveryScript []byte, remoteDeliveryScript []byte,
+	closeOpts ...ChanCloseOpt) (input.Signature, *chainhash.Hash,
+	btcutil.Amount, error) {
+
+	lc.Lock()
+	defer lc.Unlock()
+
+	// If we've already closed the channel, then ignore this request.
+	if lc.status == channelClosed {
+		// TODO(roasbeef): check to ensure no pending payments
+		return nil, nil, 0, ErrChanClosing
+	}
+
+	closeTx, ourBalance, err := lc.coopCloseTx(
+		proposedFee, localDeliveryScript, remoteDeliveryScript,
+		closeOpts...,
+	)
+	if err != nil {
 		return nil, nil, 0, err
 	}
 
@@ -6735,7 +7014,8 @@ func (lc *LightningChannel) CreateCloseProposal(proposedFee btcutil.Amount,
 func (lc *LightningChannel) CompleteCooperativeClose(
 	localSig, remoteSig input.Signature,
 	localDeliveryScript, remoteDeliveryScript []byte,
-	proposedFee btcutil.Amount) (*wire.MsgTx, btcutil.Amount, error) {
+	proposedFee btcutil.Amount,
+	closeOpts ...ChanCloseOpt) (*wire.MsgTx, btcutil.Amount, error) {
 
 	lc.Lock()
 	defer lc.Unlock()
@@ -6746,29 +7026,15 @@ func (lc *LightningChannel) CompleteCooperativeClose(
 		return nil, 0, ErrChanClosing
 	}
 
-	// Get the final balances after subtracting the proposed fee.
-	ourBalance, theirBalance, err := CoopCloseBalance(
-		lc.channelState.ChanType, lc.channelState.IsInitiator,
-		proposedFee, lc.channelState.LocalCommitment,
-	)
-	if err != nil {
-		return nil, 0, err
-	}
-
 	// Create the transaction used to return the current settled balance
-	// on this active channel back to both parties. In this current model,
-	// the initiator pays full fees for the cooperative close transaction.
-	closeTx := CreateCooperativeCloseTx(
-		fundingTxIn(lc.channelState), lc.channelState.LocalChanCfg.DustLimit,
-		lc.channelState.RemoteChanCfg.DustLimit, ourBalance, theirBalance,
-		localDeliveryScript, remoteDeliveryScript,
+	// on this active channel back to both parties. Unless a custom payer
+	// is specified, the initiator pays full fees for the cooperative close
+	// transaction.
+	closeTx, ourBalance, err := lc.coopCloseTx(
+		proposedFee, localDeliveryScript, remoteDeliveryScript,
+		closeOpts...,
 	)
-
-	// Ensure that the transaction doesn't explicitly validate any
-	// consensus rules such as being too big, or having any value with a
-	// negative output.
-	tx := btcutil.NewTx(closeTx)
-	if err := blockchain.CheckTransactionSanity(tx); err != nil {
+	if err != nil {
 		return nil, 0, err
 	}
 	hashCache := input.NewTxSigHashesV0Only(closeTx)
@@ -6804,7 +7070,12 @@ func (lc *LightningChannel) CompleteCooperativeClose(
 	// As the transaction is sane, and the scripts are valid we'll mark the
 	// channel now as closed as the closure transaction should get into the
 	// chain in a timely manner and possibly be re-broadcast by the wallet.
-	lc.status = channelClosed
+	// A closure transaction that signals replaceability can still be
+	// replaced by a version paying a higher fee though, so we'll allow new
+	// proposals in that case.
+	if !isReplaceable(closeTx) {
+		lc.status = channelClosed
+	}
 
 	return closeTx, ourBalance, nil
 }
@@ -7502,6 +7773,15 @@ func (lc *LightningChannel) MarkCoopBroadcasted(tx *wire.MsgTx,
 	return lc.channelState.MarkCoopBroadcasted(tx, localInitiated)
 }
 
+// AddCoopCloseTxid records the txid of a cooperative closing transaction that
+// the remote party may broadcast, so that it's recognized once it confirms.
+func (lc *LightningChannel) AddCoopCloseTxid(txid chainhash.Hash) error {
+	lc.Lock()
+	defer lc.Unlock()
+
+	return lc.channelState.AddCoopCloseTxid(txid)
+}
+
 // MarkDataLoss marks sets the channel status to LocalDataLoss and stores the
 // passed commitPoint for use to retrieve funds in case the remote force closes
 // the channel.
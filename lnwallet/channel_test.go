@@ -767,6 +767,101 @@ func testCoopClose(t *testing.T, testCase *coopCloseTestCase) {
 	}
 }
 
+// TestCoopCloseCustomPayer tests that the non-initiator of a channel can pay
+// the fee of a replaceable cooperative close transaction, and later replace it
+// with a version paying a higher fee.
+func TestCoopCloseCustomPayer(t *testing.T) {
+	t.Parallel()
+
+	aliceChannel, bobChannel, err := CreateTestChannels(
+		t, channeldb.SingleFunderTweaklessBit|
+			channeldb.AnchorOutputsBit,
+	)
+	require.NoError(t, err, "unable to create test channels")
+
+	aliceDeliveryScript := bobsPrivKey[:]
+	bobDeliveryScript := testHdSeed[:]
+
+	const (
+		sequence = wire.MaxTxInSequenceNum - 2
+		lockTime = 100
+	)
+
+	// closeWithFee lets Bob propose a closing transaction paying the given
+	// fee, which Alice then completes.
+	closeWithFee := func(fee btcutil.Amount) (*wire.MsgTx,
+		btcutil.Amount) {
+
+		bobOpts := []ChanCloseOpt{
+			WithCustomSequence(sequence),
+			WithCustomLockTime(lockTime),
+			WithCustomPayer(true),
+		}
+		aliceOpts := []ChanCloseOpt{
+			WithCustomSequence(sequence),
+			WithCustomLockTime(lockTime),
+			WithCustomPayer(false),
+		}
+
+		hasBob, hasAlice, err := bobChannel.CoopCloseOutputs(
+			fee, bobDeliveryScript, aliceDeliveryScript,
+			bobOpts...,
+		)
+		require.NoError(t, err)
+		require.True(t, hasBob)
+		require.True(t, hasAlice)
+
+		bobSig, bobTxid, _, err := bobChannel.CreateCloseProposal(
+			fee, bobDeliveryScript, aliceDeliveryScript,
+			bobOpts...,
+		)
+		require.NoError(t, err)
+
+		aliceSig, _, _, err := aliceChannel.CreateCloseProposal(
+			fee, aliceDeliveryScript, bobDeliveryScript,
+			aliceOpts...,
+		)
+		require.NoError(t, err)
+
+		closeTx, _, err := aliceChannel.CompleteCooperativeClose(
+			aliceSig, bobSig, aliceDeliveryScript,
+			bobDeliveryScript, fee, aliceOpts...,
+		)
+		require.NoError(t, err)
+		require.Equal(t, *bobTxid, closeTx.TxHash())
+
+		_, bobBalance, err := bobChannel.CompleteCooperativeClose(
+			bobSig, aliceSig, bobDeliveryScript,
+			aliceDeliveryScript, fee, bobOpts...,
+		)
+		require.NoError(t, err)
+
+		return closeTx, bobBalance
+	}
+
+	closeTx, bobBalance := closeWithFee(1000)
+	require.Equal(t, uint32(sequence), closeTx.TxIn[0].Sequence)
+	require.Equal(t, uint32(lockTime), closeTx.LockTime)
+
+	// Bob pays the fee, even though Alice is the initiator.
+	expBalanceBob := bobChannel.channelState.LocalCommitment.
+		LocalBalance.ToSatoshis() - 1000
+	require.Equal(t, expBalanceBob, bobBalance)
+
+	// As the closing transaction is replaceable, Bob can replace it with a
+	// version paying a higher fee.
+	replacementTx, bobBalance := closeWithFee(2000)
+	require.NotEqual(t, closeTx.TxHash(), replacementTx.TxHash())
+	require.Equal(t, expBalanceBob-1000, bobBalance)
+
+	// Bob can't pay a fee that exceeds his balance.
+	_, _, _, err = bobChannel.CreateCloseProposal(
+		expBalanceBob*2, bobDeliveryScript, aliceDeliveryScript,
+		WithCustomPayer(true),
+	)
+	require.Error(t, err)
+}
+
 // TestForceClose checks that the resulting ForceCloseSummary is correct when a
 // peer is ForceClosing the channel. Will check outputs both above and below
 // the dust limit. Additionally, we'll ensure that the node which executed the
@@ -1927,6 +2022,66 @@ func TestStateUpdatePersistence(t *testing.T) {
 	}
 }
 
+// TestHtlcExtraDataPersistence tests that the extra data of an add is handed
+// to the forwarding package of the receiver and that it survives a restart of
+// both parties.
+func TestHtlcExtraDataPersistence(t *testing.T) {
+	t.Parallel()
+
+	aliceChannel, bobChannel, err := CreateTestChannels(
+		t, channeldb.SingleFunderTweaklessBit,
+	)
+	require.NoError(t, err, "unable to create test channels")
+
+	extraData := lnwire.ExtraOpaqueData{
+		0xfe, 0x00, 0x01, 0x00, 0x01, 0x02, 0x03, 0x04,
+	}
+
+	htlc, _ := createHTLC(0, lnwire.MilliSatoshi(500000))
+	htlc.ExtraData = extraData
+
+	_, err = aliceChannel.AddHTLC(htlc, nil)
+	require.NoError(t, err, "alice unable to add htlc")
+	_, err = bobChannel.ReceiveHTLC(htlc)
+	require.NoError(t, err, "bob unable to receive htlc")
+
+	require.NoError(t, ForceStateTransition(aliceChannel, bobChannel))
+
+	// Bob's forwarding package must contain the add including its extra
+	// data, so that it is available when the htlc is forwarded.
+	fwdPkgs, err := bobChannel.LoadFwdPkgs()
+	require.NoError(t, err, "unable to load fwd pkgs")
+	require.Len(t, fwdPkgs, 1)
+	require.Len(t, fwdPkgs[0].Adds, 1)
+
+	payDescs, err := PayDescsFromRemoteLogUpdates(
+		bobChannel.ShortChanID(), fwdPkgs[0].Height, fwdPkgs[0].Adds,
+	)
+	require.NoError(t, err)
+	require.Equal(t, extraData, payDescs[0].ExtraData)
+
+	// The extra data is persisted with the htlc on both commitments.
+	fetchChannel := func(c *LightningChannel) *LightningChannel {
+		channels, err := c.channelState.Db.FetchOpenChannels(
+			c.channelState.IdentityPub,
+		)
+		require.NoError(t, err, "unable to fetch channel")
+
+		newChannel, err := NewLightningChannel(
+			c.Signer, channels[0], c.sigPool,
+		)
+		require.NoError(t, err, "unable to create new channel")
+
+		return newChannel
+	}
+
+	for _, c := range []*LightningChannel{aliceChannel, bobChannel} {
+		htlcs := fetchChannel(c).ActiveHtlcs()
+		require.Len(t, htlcs, 1)
+		require.Equal(t, extraData, htlcs[0].ExtraData)
+	}
+}
+
 func TestCancelHTLC(t *testing.T) {
 	t.Parallel()
 
@@ -9424,6 +9579,107 @@ func testGetDustSum(t *testing.T, chantype channeldb.ChannelType) {
 	}
 }
 
+// TestChannelFeeExposure tests that we correctly calculate the fee exposure of
+// the local and remote commitments.
+func TestChannelFeeExposure(t *testing.T) {
+	t.Run("fee exposure tweakless", func(t *testing.T) {
+		testFeeExposure(t, channeldb.SingleFunderTweaklessBit)
+	})
+	t.Run("fee exposure anchors zero htlc fee", func(t *testing.T) {
+		testFeeExposure(t, channeldb.SingleFunderTweaklessBit|
+			channeldb.AnchorOutputsBit|
+			channeldb.ZeroHtlcTxFeeBit,
+		)
+	})
+}
+
+func testFeeExposure(t *testing.T, chantype channeldb.ChannelType) {
+	t.Parallel()
+
+	// This makes a channel with Alice's dust limit set to 200sats and
+	// Bob's dust limit set to 1300sats.
+	aliceChannel, bobChannel, err := CreateTestChannels(t, chantype)
+	require.NoError(t, err)
+
+	var anchors lnwire.MilliSatoshi
+	if chantype.HasAnchors() {
+		anchors = lnwire.NewMSatFromSatoshis(2 * anchorSize)
+	}
+
+	commitFee := func(feeRate chainfee.SatPerKWeight,
+		numHtlcs int64) lnwire.MilliSatoshi {
+
+		weight := CommitWeight(chantype) + numHtlcs*input.HTLCWeight
+		return lnwire.NewMSatFromSatoshis(feeRate.FeeForWeight(weight))
+	}
+
+	// Without any pending fee update, the next feerate is the current
+	// one.
+	require.Equal(
+		t, aliceChannel.CommitFeeRate(), aliceChannel.NextFeeRate(),
+	)
+
+	// We'll lower the fee from 6000sats/kWU to 253sats/kWU. Until the
+	// update is locked in, it's only reflected by the next feerate.
+	lowFee := chainfee.SatPerKWeight(253)
+	err = aliceChannel.UpdateFee(lowFee)
+	require.NoError(t, err)
+	err = bobChannel.ReceiveUpdateFee(lowFee)
+	require.NoError(t, err)
+
+	require.Equal(t, lowFee, aliceChannel.NextFeeRate())
+	require.Equal(t, lowFee, bobChannel.NextFeeRate())
+	require.NotEqual(t, lowFee, aliceChannel.CommitFeeRate())
+
+	err = ForceStateTransition(aliceChannel, bobChannel)
+	require.NoError(t, err)
+	require.Equal(t, lowFee, aliceChannel.CommitFeeRate())
+	require.Equal(t, lowFee, aliceChannel.NextFeeRate())
+
+	// Without any HTLCs, the exposure is the fee of the bare commitment
+	// along with the value of the anchors.
+	require.Equal(
+		t, commitFee(lowFee, 0)+anchors,
+		aliceChannel.FeeExposure(false, lowFee),
+	)
+	require.Equal(
+		t, commitFee(lowFee, 0)+anchors,
+		aliceChannel.FeeExposure(true, lowFee),
+	)
+
+	// Alice adds an HTLC that is above her dust limit but below Bob's, so
+	// at the low feerate it only counts towards the exposure of Bob's
+	// commitment.
+	htlcAmt := lnwire.MilliSatoshi(1_000_000)
+	htlc, _ := createHTLC(0, htlcAmt)
+	_, err = aliceChannel.AddHTLC(htlc, nil)
+	require.NoError(t, err)
+
+	require.Equal(
+		t, commitFee(lowFee, 1)+anchors,
+		aliceChannel.FeeExposure(false, lowFee),
+	)
+	require.Equal(
+		t, commitFee(lowFee, 0)+anchors+htlcAmt,
+		aliceChannel.FeeExposure(true, lowFee),
+	)
+
+	// At a high feerate, the second-level fee trims the HTLC on Alice's
+	// commitment as well, unless the HTLC transactions don't pay any fee.
+	highFee := chainfee.SatPerKWeight(50_000)
+	expectedLocal := commitFee(highFee, 0) + anchors + htlcAmt
+	if chantype.ZeroHtlcTxFee() {
+		expectedLocal = commitFee(highFee, 1) + anchors
+	}
+	require.Equal(
+		t, expectedLocal, aliceChannel.FeeExposure(false, highFee),
+	)
+	require.Equal(
+		t, commitFee(highFee, 0)+anchors+htlcAmt,
+		aliceChannel.FeeExposure(true, highFee),
+	)
+}
+
 // deriveDummyRetributionParams is a helper function that derives a list of
 // dummy params to assist retribution creation related tests.
 func deriveDummyRetributionParams(chanState *channeldb.OpenChannel) (uint32,
@@ -9467,8 +9723,9 @@ func TestCreateHtlcRetribution(t *testing.T) {
 	}
 
 	// Create the htlc retribution.
+	params := aliceChannel.channelState.CommitParams()
 	hr, err := createHtlcRetribution(
-		aliceChannel.channelState, keyRing, commitHash,
+		aliceChannel.channelState, &params, keyRing, commitHash,
 		dummyPrivate, leaseExpiry, htlc,
 	)
 	// Expect no error.
@@ -9663,9 +9920,10 @@ func TestCreateBreachRetribution(t *testing.T) {
 				tx = nil
 			}
 
+			params := aliceChannel.channelState.CommitParams()
 			br, our, their, err := createBreachRetribution(
 				tc.revocationLog, tx,
-				aliceChannel.channelState, keyRing,
+				aliceChannel.channelState, &params, keyRing,
 				dummyPrivate, leaseExpiry,
 			)
 
@@ -9722,8 +9980,9 @@ func TestCreateBreachRetributionLegacy(t *testing.T) {
 	}
 
 	// Create the breach retribution using the legacy format.
+	params := aliceChannel.channelState.CommitParams()
 	br, ourAmt, theirAmt, err := createBreachRetributionLegacy(
-		&revokedLog, aliceChannel.channelState, keyRing,
+		&revokedLog, aliceChannel.channelState, &params, keyRing,
 		dummyPrivate, ourScript, theirScript, leaseExpiry,
 	)
 	require.NoError(t, err)
@@ -9866,3 +10125,62 @@ func testNewBreachRetribution(t *testing.T, chanType channeldb.ChannelType) {
 	)
 	require.ErrorIs(t, err, channeldb.ErrLogEntryNotFound)
 }
+
+// TestChannelHasPendingLocalUpdates tests that a party's updates are
+// considered pending until both commitments irrevocably include them.
+func TestChannelHasPendingLocalUpdates(t *testing.T) {
+	t.Parallel()
+
+	aliceChannel, bobChannel, err := CreateTestChannels(
+		t, channeldb.SingleFunderTweaklessBit,
+	)
+	require.NoError(t, err)
+
+	require.False(t, aliceChannel.HasPendingLocalUpdates())
+	require.False(t, bobChannel.HasPendingLocalUpdates())
+
+	// Once Alice adds an HTLC, only her updates are pending.
+	htlc, preimage := createHTLC(0, lnwire.MilliSatoshi(1_000_000))
+	_, err = aliceChannel.AddHTLC(htlc, nil)
+	require.NoError(t, err)
+	_, err = bobChannel.ReceiveHTLC(htlc)
+	require.NoError(t, err)
+
+	require.True(t, aliceChannel.HasPendingLocalUpdates())
+	require.False(t, bobChannel.HasPendingLocalUpdates())
+
+	// After Bob revoked his commitment that didn't include the HTLC, it's
+	// still pending as Alice's own commitment doesn't include it yet.
+	aliceSig, aliceHtlcSigs, _, err := aliceChannel.SignNextCommitment()
+	require.NoError(t, err)
+	err = bobChannel.ReceiveNewCommitment(aliceSig, aliceHtlcSigs)
+	require.NoError(t, err)
+	bobRevocation, _, _, err := bobChannel.RevokeCurrentCommitment()
+	require.NoError(t, err)
+	_, _, _, _, err = aliceChannel.ReceiveRevocation(bobRevocation)
+	require.NoError(t, err)
+
+	require.True(t, aliceChannel.HasPendingLocalUpdates())
+
+	// Completing the state transition locks the HTLC in.
+	err = ForceStateTransition(bobChannel, aliceChannel)
+	require.NoError(t, err)
+
+	require.False(t, aliceChannel.HasPendingLocalUpdates())
+	require.False(t, bobChannel.HasPendingLocalUpdates())
+
+	// Settling the HTLC is an update of Bob's.
+	err = bobChannel.SettleHTLC(preimage, 0, nil, nil, nil)
+	require.NoError(t, err)
+	err = aliceChannel.ReceiveHTLCSettle(preimage, 0)
+	require.NoError(t, err)
+
+	require.False(t, aliceChannel.HasPendingLocalUpdates())
+	require.True(t, bobChannel.HasPendingLocalUpdates())
+
+	err = ForceStateTransition(bobChannel, aliceChannel)
+	require.NoError(t, err)
+
+	require.False(t, aliceChannel.HasPendingLocalUpdates())
+	require.False(t, bobChannel.HasPendingLocalUpdates())
+}
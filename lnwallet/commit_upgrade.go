@@ -0,0 +1,373 @@
+package lnwallet
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/input"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+var (
+	// ErrCommitUpgradePending is returned when the commitment of a channel
+	// is upgraded while a previous upgrade isn't locked in yet.
+	ErrCommitUpgradePending = errors.New("previous commitment upgrade " +
+		"isn't locked in yet")
+
+	// ErrCommitUpgradeActiveHtlcs is returned when the commitment of a
+	// channel with active HTLCs is upgraded.
+	ErrCommitUpgradeActiveHtlcs = errors.New("commitment can't be " +
+		"upgraded while the channel has active htlcs")
+
+	// ErrCommitUpgradeUpdatesPending is returned when the commitment of a
+	// channel is upgraded while updates aren't committed to by both
+	// parties yet.
+	ErrCommitUpgradeUpdatesPending = errors.New("commitment can't be " +
+		"upgraded while the channel has pending updates")
+)
+
+// commitFormatBits are the bits of the channel type that determine the format
+// of the commitment transactions. These are the only bits that can change when
+// the commitment of a channel is upgraded.
+const commitFormatBits = channeldb.SingleFunderTweaklessBit |
+	channeldb.AnchorOutputsBit | channeldb.ZeroHtlcTxFeeBit
+
+// validateCommitUpgradeType checks whether the commitment format of a channel
+// can be changed from the current to the next channel type.
+func validateCommitUpgradeType(current, next channeldb.ChannelType) error {
+	if current.HasLeaseExpiration() {
+		return errors.New("commitment of script enforced lease " +
+			"channels can't be upgraded")
+	}
+
+	if current&^commitFormatBits != next&^commitFormatBits {
+		return fmt.Errorf("channel type %v can't be upgraded to %v, "+
+			"only the commitment format can change", current, next)
+	}
+
+	// Removing the anchor outputs would burn their value to fees, so we
+	// only allow them to be added.
+	if current.HasAnchors() && !next.HasAnchors() {
+		return errors.New("anchor outputs can't be removed")
+	}
+
+	switch next & commitFormatBits {
+	case channeldb.SingleFunderTweaklessBit,
+		channeldb.SingleFunderTweaklessBit | channeldb.AnchorOutputsBit,
+		commitFormatBits:
+
+		return nil
+
+	default:
+		return fmt.Errorf("unsupported commitment format %v", next)
+	}
+}
+
+// CommitFormatFeatures returns the channel type feature bits that describe the
+// commitment format of a channel of the given type.
+func CommitFormatFeatures(chanType channeldb.ChannelType) lnwire.ChannelType {
+	var bits []lnwire.FeatureBit
+	if chanType.IsTweakless() {
+		bits = append(bits, lnwire.StaticRemoteKeyRequired)
+	}
+
+	switch {
+	case chanType.ZeroHtlcTxFee():
+		bits = append(bits, lnwire.AnchorsZeroFeeHtlcTxRequired)
+
+	case chanType.HasAnchors():
+		bits = append(bits, lnwire.AnchorsRequired)
+	}
+
+	return lnwire.ChannelType(*lnwire.NewRawFeatureVector(bits...))
+}
+
+// UpgradedChannelType returns the type of a channel of the current type after
+// its commitment was switched to the format described by the passed channel
+// type feature bits. All bits that don't describe the commitment format are
+// retained.
+func UpgradedChannelType(current channeldb.ChannelType,
+	chanType lnwire.ChannelType) (channeldb.ChannelType, error) {
+
+	features := lnwire.RawFeatureVector(chanType)
+
+	var format channeldb.ChannelType
+	switch {
+	case features.OnlyContains(lnwire.StaticRemoteKeyRequired):
+		format = channeldb.SingleFunderTweaklessBit
+
+	case features.OnlyContains(
+		lnwire.StaticRemoteKeyRequired, lnwire.AnchorsRequired,
+	):
+		format = channeldb.SingleFunderTweaklessBit |
+			channeldb.AnchorOutputsBit
+
+	case features.OnlyContains(
+		lnwire.StaticRemoteKeyRequired,
+		lnwire.AnchorsZeroFeeHtlcTxRequired,
+	):
+		format = commitFormatBits
+
+	default:
+		return 0, errors.New("unsupported commitment format")
+	}
+
+	return current&^commitFormatBits | format, nil
+}
+
+// validateUpgradedConfig checks that only the parameters of a channel config
+// that can be changed by upgrading the commitment differ between the current
+// and the next config.
+func validateUpgradedConfig(current, next *channeldb.ChannelConfig) error {
+	expected := *current
+	expected.DustLimit = next.DustLimit
+	expected.CsvDelay = next.CsvDelay
+	expected.MaxAcceptedHtlcs = next.MaxAcceptedHtlcs
+
+	if !reflect.DeepEqual(&expected, next) {
+		return errors.New("only the dust limit, csv delay and max " +
+			"accepted htlcs can be upgraded")
+	}
+
+	return nil
+}
+
+// verifyUpgradedConstraints checks that the constraints that change by
+// upgrading the commitment satisfy the same limits that are enforced during
+// funding. Constraints that don't change aren't checked, as the limits may have
+// been different when the channel was opened.
+func verifyUpgradedConstraints(current, next *channeldb.ChannelConstraints,
+	maxLocalCSVDelay uint16) error {
+
+	if next.CsvDelay != current.CsvDelay &&
+		next.CsvDelay > maxLocalCSVDelay {
+
+		return ErrCsvDelayTooLarge(next.CsvDelay, maxLocalCSVDelay)
+	}
+
+	if next.DustLimit != current.DustLimit {
+		if next.DustLimit > next.ChanReserve {
+			return ErrChanReserveTooSmall(
+				next.ChanReserve, next.DustLimit,
+			)
+		}
+
+		maxWitnessLimit := DustLimitForSize(input.UnknownWitnessSize)
+		if next.DustLimit < maxWitnessLimit ||
+			next.DustLimit > 3*maxWitnessLimit {
+
+			return ErrInvalidDustLimit(next.DustLimit)
+		}
+	}
+
+	if next.MaxAcceptedHtlcs != current.MaxAcceptedHtlcs {
+		const minNumHtlc = 5
+		maxNumHtlc := uint16(input.MaxHTLCNumber / 2)
+
+		switch {
+		case next.MaxAcceptedHtlcs > maxNumHtlc:
+			return ErrMaxHtlcNumTooLarge(
+				next.MaxAcceptedHtlcs, maxNumHtlc,
+			)
+
+		case next.MaxAcceptedHtlcs < minNumHtlc:
+			return ErrMaxHtlcNumTooSmall(
+				next.MaxAcceptedHtlcs, minNumHtlc,
+			)
+		}
+	}
+
+	return nil
+}
+
+// ValidateCommitUpgrade checks whether the commitment of the channel can be
+// upgraded to the passed parameters. Besides the commitment format, only the
+// dust limits, CSV delays and maximum number of accepted HTLCs of both parties
+// can change, and the new values must satisfy the same constraints that are
+// enforced during funding. The maxLocalCSVDelay is the largest CSV delay we
+// accept for our own funds.
+func (lc *LightningChannel) ValidateCommitUpgrade(
+	params *channeldb.CommitParams, maxLocalCSVDelay uint16) error {
+
+	lc.RLock()
+	defer lc.RUnlock()
+
+	return lc.validateCommitUpgrade(params, maxLocalCSVDelay)
+}
+
+// validateCommitUpgrade is the internal version of ValidateCommitUpgrade. This
+// function expects to be executed with a lock held.
+func (lc *LightningChannel) validateCommitUpgrade(
+	params *channeldb.CommitParams, maxLocalCSVDelay uint16) error {
+
+	if lc.commitUpgradePending() {
+		return ErrCommitUpgradePending
+	}
+
+	// As the upgraded commitment can only be signed once the channel is in
+	// a stable state, the channel must not have any updates in flight. We
+	// also require all HTLCs to be resolved, so that we never need to
+	// resolve HTLCs across an upgrade.
+	localChain, remoteChain := lc.localCommitChain, lc.remoteCommitChain
+	if remoteChain.hasUnackedCommitment() ||
+		localChain.tip().height != localChain.tail().height ||
+		lc.oweCommitment(true) || lc.oweCommitment(false) {
+
+		return ErrCommitUpgradeUpdatesPending
+	}
+
+	if len(lc.channelState.ActiveHtlcs()) != 0 {
+		return ErrCommitUpgradeActiveHtlcs
+	}
+
+	current := lc.channelState.CommitParams()
+	err := validateCommitUpgradeType(current.ChanType, params.ChanType)
+	if err != nil {
+		return err
+	}
+
+	err = validateUpgradedConfig(
+		&current.LocalChanCfg, &params.LocalChanCfg,
+	)
+	if err != nil {
+		return err
+	}
+	err = validateUpgradedConfig(
+		&current.RemoteChanCfg, &params.RemoteChanCfg,
+	)
+	if err != nil {
+		return err
+	}
+
+	// The CSV delay of the remote party only delays their funds, so we
+	// don't limit it.
+	err = verifyUpgradedConstraints(
+		&current.LocalChanCfg.ChannelConstraints,
+		&params.LocalChanCfg.ChannelConstraints, maxLocalCSVDelay,
+	)
+	if err != nil {
+		return err
+	}
+	err = verifyUpgradedConstraints(
+		&current.RemoteChanCfg.ChannelConstraints,
+		&params.RemoteChanCfg.ChannelConstraints, math.MaxUint16,
+	)
+	if err != nil {
+		return err
+	}
+
+	// Finally, the initiator pays for the upgraded commitment, including
+	// any anchor outputs that are added, and must still be able to meet
+	// its channel reserve afterwards.
+	commit := localChain.tail()
+	initiatorBalance := commit.theirBalance
+	initiatorReserve := lc.channelState.RemoteChanCfg.ChanReserve
+	if lc.channelState.IsInitiator {
+		initiatorBalance = commit.ourBalance
+		initiatorReserve = lc.channelState.LocalChanCfg.ChanReserve
+	}
+	initiatorBalance += lnwire.NewMSatFromSatoshis(commit.fee)
+
+	cost := commit.feePerKw.FeeForWeight(CommitWeight(params.ChanType))
+	if !current.ChanType.HasAnchors() && params.ChanType.HasAnchors() {
+		cost += 2 * anchorSize
+	}
+
+	required := lnwire.NewMSatFromSatoshis(cost + initiatorReserve)
+	if initiatorBalance < required {
+		return fmt.Errorf("%w: initiator can't pay for the upgraded "+
+			"commitment", ErrBelowChanReserve)
+	}
+
+	return nil
+}
+
+// UpgradeCommitment switches the channel over to the passed commitment
+// parameters, which are used for all commitments created from now on. The
+// parameters of the prior commitments are retained, as they're still needed to
+// resolve these commitments on chain. The upgrade is locked in once both
+// parties have revoked their prior commitments.
+func (lc *LightningChannel) UpgradeCommitment(params *channeldb.CommitParams,
+	maxLocalCSVDelay uint16) error {
+
+	lc.Lock()
+	defer lc.Unlock()
+
+	err := lc.validateCommitUpgrade(params, maxLocalCSVDelay)
+	if err != nil {
+		return err
+	}
+
+	localHeight := lc.localCommitChain.tip().height + 1
+	remoteHeight := lc.remoteCommitChain.tip().height + 1
+
+	lc.log.Infof("upgrading commitment to type %v, starting at local "+
+		"height %v and remote height %v", params.ChanType, localHeight,
+		remoteHeight)
+
+	return lc.channelState.UpgradeCommitment(
+		params, localHeight, remoteHeight,
+	)
+}
+
+// CommitUpgradePending returns true if the commitment of the channel has been
+// upgraded, but either party hasn't revoked its prior commitment yet.
+func (lc *LightningChannel) CommitUpgradePending() bool {
+	lc.RLock()
+	defer lc.RUnlock()
+
+	return lc.commitUpgradePending()
+}
+
+// commitUpgradePending is the internal version of CommitUpgradePending. This
+// function expects to be executed with a lock held.
+func (lc *LightningChannel) commitUpgradePending() bool {
+	upgrade := lc.lastCommitUpgrade()
+	if upgrade == nil {
+		return false
+	}
+
+	return lc.localCommitChain.tail().height < upgrade.LocalHeight ||
+		lc.remoteCommitChain.tail().height < upgrade.RemoteHeight
+}
+
+// lastCommitUpgrade returns the most recent commitment upgrade of the channel,
+// or nil if the channel has never been upgraded.
+func (lc *LightningChannel) lastCommitUpgrade() *channeldb.CommitUpgrade {
+	upgrades := lc.channelState.CommitUpgrades
+	if len(upgrades) == 0 {
+		return nil
+	}
+
+	return &upgrades[len(upgrades)-1]
+}
+
+// upgradeAnchorCost returns the value of the anchor outputs the initiator pays
+// for if the commitment at the given height is the first one after an upgrade
+// that added anchor outputs to the channel. Balances are always stored after
+// deducting the anchor outputs, so this cost only needs to be accounted for
+// once.
+func (lc *LightningChannel) upgradeAnchorCost(remoteChain bool,
+	height uint64) lnwire.MilliSatoshi {
+
+	upgrade := lc.lastCommitUpgrade()
+	if upgrade == nil {
+		return 0
+	}
+
+	upgradeHeight := upgrade.LocalHeight
+	if remoteChain {
+		upgradeHeight = upgrade.RemoteHeight
+	}
+
+	if height != upgradeHeight || upgrade.Prev.ChanType.HasAnchors() ||
+		!lc.channelState.ChanType.HasAnchors() {
+
+		return 0
+	}
+
+	return lnwire.NewMSatFromSatoshis(2 * anchorSize)
+}
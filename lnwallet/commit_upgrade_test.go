@@ -0,0 +1,177 @@
+package lnwallet
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/stretchr/testify/require"
+)
+
+// testMaxLocalCSVDelay is the maximum CSV delay used to validate commitment
+// upgrades in the tests.
+const testMaxLocalCSVDelay = 2016
+
+// upgradeParams returns the commitment parameters of both channels, upgraded to
+// the given channel type and dust limit.
+func upgradeParams(alice, bob *LightningChannel,
+	chanType channeldb.ChannelType, dustLimit btcutil.Amount) (
+	*channeldb.CommitParams, *channeldb.CommitParams) {
+
+	aliceParams := alice.channelState.CommitParams()
+	aliceParams.ChanType = chanType
+	aliceParams.LocalChanCfg.DustLimit = dustLimit
+	aliceParams.RemoteChanCfg.DustLimit = dustLimit
+
+	bobParams := bob.channelState.CommitParams()
+	bobParams.ChanType = chanType
+	bobParams.LocalChanCfg.DustLimit = dustLimit
+	bobParams.RemoteChanCfg.DustLimit = dustLimit
+
+	return &aliceParams, &bobParams
+}
+
+// TestCommitUpgrade tests that the commitment of a static remote key channel
+// can be upgraded to zero fee HTLC anchors, and that commitments that were
+// revoked before the upgrade can still be resolved on chain.
+func TestCommitUpgrade(t *testing.T) {
+	t.Parallel()
+
+	aliceChannel, bobChannel, err := CreateTestChannels(
+		t, channeldb.SingleFunderTweaklessBit,
+	)
+	require.NoError(t, err)
+
+	// First, Alice sends an HTLC to Bob, which Bob settles. This leaves
+	// Bob with commitments that are revoked before the upgrade.
+	htlc, preimage := createHTLC(0, lnwire.MilliSatoshi(100_000_000))
+	_, err = aliceChannel.AddHTLC(htlc, nil)
+	require.NoError(t, err)
+	_, err = bobChannel.ReceiveHTLC(htlc)
+	require.NoError(t, err)
+	require.NoError(t, ForceStateTransition(aliceChannel, bobChannel))
+
+	revokedHeight := bobChannel.channelState.LocalCommitment.CommitHeight
+	revokedTx := bobChannel.channelState.LocalCommitment.CommitTx
+
+	chanType := channeldb.SingleFunderTweaklessBit |
+		channeldb.AnchorOutputsBit | channeldb.ZeroHtlcTxFeeBit
+	aliceParams, bobParams := upgradeParams(
+		aliceChannel, bobChannel, chanType, 354,
+	)
+
+	// The commitment can't be upgraded while the HTLC is active.
+	err = aliceChannel.UpgradeCommitment(aliceParams, testMaxLocalCSVDelay)
+	require.ErrorIs(t, err, ErrCommitUpgradeActiveHtlcs)
+
+	err = bobChannel.SettleHTLC(preimage, 0, nil, nil, nil)
+	require.NoError(t, err)
+	err = aliceChannel.ReceiveHTLCSettle(preimage, 0)
+	require.NoError(t, err)
+
+	// Nor while the settle isn't committed to yet.
+	err = aliceChannel.UpgradeCommitment(aliceParams, testMaxLocalCSVDelay)
+	require.ErrorIs(t, err, ErrCommitUpgradeUpdatesPending)
+
+	require.NoError(t, ForceStateTransition(bobChannel, aliceChannel))
+
+	// Only the commitment format, dust limits, CSV delays and max accepted
+	// HTLCs can be upgraded.
+	invalidParams := *aliceParams
+	invalidParams.LocalChanCfg.ChanReserve++
+	err = aliceChannel.ValidateCommitUpgrade(
+		&invalidParams, testMaxLocalCSVDelay,
+	)
+	require.Error(t, err)
+
+	// The upgraded constraints must also satisfy the limits enforced
+	// during funding.
+	invalidParams = *aliceParams
+	invalidParams.LocalChanCfg.DustLimit = 100
+	err = aliceChannel.ValidateCommitUpgrade(
+		&invalidParams, testMaxLocalCSVDelay,
+	)
+	require.Error(t, err)
+
+	invalidParams = *aliceParams
+	invalidParams.LocalChanCfg.CsvDelay = testMaxLocalCSVDelay + 1
+	err = aliceChannel.ValidateCommitUpgrade(
+		&invalidParams, testMaxLocalCSVDelay,
+	)
+	require.Error(t, err)
+
+	invalidParams = *aliceParams
+	invalidParams.ChanType |= channeldb.FrozenBit
+	err = aliceChannel.ValidateCommitUpgrade(
+		&invalidParams, testMaxLocalCSVDelay,
+	)
+	require.Error(t, err)
+
+	// Both parties upgrade the commitment, after which both owe the other
+	// a commitment using the new parameters.
+	balance := aliceChannel.channelState.LocalCommitment.LocalBalance
+	err = aliceChannel.UpgradeCommitment(aliceParams, testMaxLocalCSVDelay)
+	require.NoError(t, err)
+	err = bobChannel.UpgradeCommitment(bobParams, testMaxLocalCSVDelay)
+	require.NoError(t, err)
+
+	require.True(t, aliceChannel.OweCommitment())
+	require.True(t, bobChannel.OweCommitment())
+	require.True(t, aliceChannel.CommitUpgradePending())
+
+	require.NoError(t, ForceStateTransition(aliceChannel, bobChannel))
+
+	require.False(t, aliceChannel.OweCommitment())
+	require.False(t, bobChannel.OweCommitment())
+	require.False(t, aliceChannel.CommitUpgradePending())
+	require.False(t, bobChannel.CommitUpgradePending())
+
+	// The new commitments have anchor outputs, which are paid for by
+	// Alice, the initiator of the channel.
+	for _, c := range []*LightningChannel{aliceChannel, bobChannel} {
+		commit := c.channelState.LocalCommitment
+		require.Len(t, commit.CommitTx.TxOut, 4)
+	}
+	newBalance := aliceChannel.channelState.LocalCommitment.LocalBalance
+	require.Less(
+		t, newBalance, balance-lnwire.NewMSatFromSatoshis(2*anchorSize),
+	)
+
+	// A previous upgrade can't be reverted.
+	legacyParams, _ := upgradeParams(
+		aliceChannel, bobChannel, channeldb.SingleFunderTweaklessBit,
+		354,
+	)
+	err = aliceChannel.ValidateCommitUpgrade(
+		legacyParams, testMaxLocalCSVDelay,
+	)
+	require.Error(t, err)
+
+	// Payments continue to work on the upgraded channel.
+	htlc, _ = createHTLC(1, lnwire.MilliSatoshi(100_000_000))
+	_, err = aliceChannel.AddHTLC(htlc, nil)
+	require.NoError(t, err)
+	_, err = bobChannel.ReceiveHTLC(htlc)
+	require.NoError(t, err)
+	require.NoError(t, ForceStateTransition(aliceChannel, bobChannel))
+
+	// If Bob broadcasts a commitment he revoked before the upgrade, Alice
+	// uses the parameters of the commitment at that time to sweep it.
+	br, err := NewBreachRetribution(
+		aliceChannel.channelState, revokedHeight, 0, revokedTx,
+	)
+	require.NoError(t, err)
+
+	require.NotNil(t, br.LocalOutputSignDesc)
+	localOut := revokedTx.TxOut[br.LocalOutpoint.Index]
+	require.Equal(
+		t, localOut.PkScript, br.LocalOutputSignDesc.Output.PkScript,
+	)
+
+	require.NotNil(t, br.RemoteOutputSignDesc)
+	remoteOut := revokedTx.TxOut[br.RemoteOutpoint.Index]
+	require.Equal(
+		t, remoteOut.PkScript, br.RemoteOutputSignDesc.Output.PkScript,
+	)
+}
@@ -813,10 +813,12 @@ func CreateCommitTx(chanType channeldb.ChannelType,
 }
 
 // CoopCloseBalance returns the final balances that should be used to create
-// the cooperative close tx, given the channel type and transaction fee.
+// the cooperative close tx, given the channel type and transaction fee. The
+// close fee is paid by the party indicated by localPaysFee, while the current
+// commitment fee and anchor values are always returned to the initiator.
 func CoopCloseBalance(chanType channeldb.ChannelType, isInitiator bool,
-	coopCloseFee btcutil.Amount, localCommit channeldb.ChannelCommitment) (
-	btcutil.Amount, btcutil.Amount, error) {
+	coopCloseFee btcutil.Amount, localCommit channeldb.ChannelCommitment,
+	localPaysFee bool) (btcutil.Amount, btcutil.Amount, error) {
 
 	// Get both parties' balances from the latest commitment.
 	ourBalance := localCommit.LocalBalance.ToSatoshis()
@@ -832,21 +834,25 @@ func CoopCloseBalance(chanType channeldb.ChannelType, isInitiator bool,
 		initiatorDelta += 2 * anchorSize
 	}
 
-	// The initiator will pay the full coop close fee, subtract that value
-	// from their balance.
-	initiatorDelta -= coopCloseFee
-
 	if isInitiator {
 		ourBalance += initiatorDelta
 	} else {
 		theirBalance += initiatorDelta
 	}
 
-	// During fee negotiation it should always be verified that the
-	// initiator can pay the proposed fee, but we do a sanity check just to
-	// be sure here.
+	// The fee payer will pay the full coop close fee, subtract that value
+	// from their balance.
+	if localPaysFee {
+		ourBalance -= coopCloseFee
+	} else {
+		theirBalance -= coopCloseFee
+	}
+
+	// During fee negotiation it should always be verified that the fee
+	// payer can pay the proposed fee, but we do a sanity check just to be
+	// sure here.
 	if ourBalance < 0 || theirBalance < 0 {
-		return 0, 0, fmt.Errorf("initiator cannot afford proposed " +
+		return 0, 0, fmt.Errorf("fee payer cannot afford proposed " +
 			"coop close fee")
 	}
 
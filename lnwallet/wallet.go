@@ -183,6 +183,12 @@ type InitFundingReserveMsg struct {
 	// channel that will be useful to our future selves.
 	Memo []byte
 
+	// LeaseExpiry is the absolute expiration height of a script enforced
+	// lease channel that wasn't registered through a funding shim, such as
+	// one leased through a liquidity ad. It's ignored for other commitment
+	// types and if the shim specifies a thaw height.
+	LeaseExpiry uint32
+
 	// err is a channel in which all errors will be sent across. Will be
 	// nil if this initial set is successful.
 	//
@@ -962,6 +968,13 @@ func (l *LightningWallet) handleFundingReserveRequest(req *InitFundingReserveMsg
 		thawHeight = shimIntent.ThawHeight()
 	}
 
+	// Leases that weren't arranged through a shim carry their expiration
+	// within the request itself.
+	isLease := req.CommitType == CommitmentTypeScriptEnforcedLease
+	if thawHeight == 0 && isLease {
+		thawHeight = req.LeaseExpiry
+	}
+
 	// Now that we have a funding intent, we'll check whether funding a
 	// channel using it would violate our reserved value for anchor channel
 	// fee bumping.
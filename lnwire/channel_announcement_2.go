@@ -0,0 +1,321 @@
+package lnwire
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+const (
+	// ChanAnn2ChainHash is the TLV type number that identifies the record
+	// for ChannelAnnouncement2.ChainHash.
+	ChanAnn2ChainHash tlv.Type = 0
+
+	// ChanAnn2Features is the TLV type number that identifies the record
+	// for ChannelAnnouncement2.Features.
+	ChanAnn2Features tlv.Type = 2
+
+	// ChanAnn2ShortChannelID is the TLV type number that identifies the
+	// record for ChannelAnnouncement2.ShortChannelID.
+	ChanAnn2ShortChannelID tlv.Type = 4
+
+	// ChanAnn2Capacity is the TLV type number that identifies the record
+	// for ChannelAnnouncement2.Capacity.
+	ChanAnn2Capacity tlv.Type = 6
+
+	// ChanAnn2NodeID1 is the TLV type number that identifies the record
+	// for ChannelAnnouncement2.NodeID1.
+	ChanAnn2NodeID1 tlv.Type = 8
+
+	// ChanAnn2NodeID2 is the TLV type number that identifies the record
+	// for ChannelAnnouncement2.NodeID2.
+	ChanAnn2NodeID2 tlv.Type = 10
+
+	// ChanAnn2BitcoinKey1 is the TLV type number that identifies the
+	// record for ChannelAnnouncement2.BitcoinKey1.
+	ChanAnn2BitcoinKey1 tlv.Type = 12
+
+	// ChanAnn2BitcoinKey2 is the TLV type number that identifies the
+	// record for ChannelAnnouncement2.BitcoinKey2.
+	ChanAnn2BitcoinKey2 tlv.Type = 14
+
+	// ChanAnn2MerkleRootHash is the TLV type number that identifies the
+	// record for ChannelAnnouncement2.MerkleRootHash.
+	ChanAnn2MerkleRootHash tlv.Type = 16
+)
+
+var (
+	// chanAnn2SigTag is the tag used to compute the tagged hash that is
+	// signed by a ChannelAnnouncement2 message.
+	chanAnn2SigTag = []byte("lightningchannel_announcement_2signature")
+)
+
+// ChannelAnnouncement2 is the taproot aware version of the
+// ChannelAnnouncement message. Instead of four ECDSA signatures proving
+// ownership of a P2WSH multisig funding output, it carries a single Schnorr
+// signature by the MuSig2 aggregate of the node keys and, if present, the
+// bitcoin keys of the channel. This allows announcing channels that are funded
+// by a P2TR output. All fields but the signature are encoded as a TLV stream.
+type ChannelAnnouncement2 struct {
+	// Signature is the Schnorr signature over the digest returned by
+	// DigestToSign, produced by the MuSig2 aggregate of NodeID1, NodeID2
+	// and, if set, BitcoinKey1 and BitcoinKey2, in that order.
+	Signature Sig
+
+	// ChainHash denotes the target chain that this channel was opened
+	// within. This value should be the genesis hash of the target chain.
+	ChainHash chainhash.Hash
+
+	// Features is the feature vector that encodes the features supported
+	// by the channel.
+	Features *RawFeatureVector
+
+	// ShortChannelID is the unique description of the funding transaction,
+	// or where exactly it's located within the target blockchain.
+	ShortChannelID ShortChannelID
+
+	// Capacity is the value of the funding output of the channel.
+	Capacity btcutil.Amount
+
+	// The public keys of the two nodes who are operating the channel, such
+	// that is NodeID1 the numerically-lesser than NodeID2 (ascending
+	// numerical order).
+	NodeID1 [33]byte
+	NodeID2 [33]byte
+
+	// BitcoinKey1 and BitcoinKey2 are the optional public keys that are
+	// aggregated into the internal key of the taproot funding output. If
+	// they aren't set, the node keys are used instead.
+	BitcoinKey1 *[33]byte
+	BitcoinKey2 *[33]byte
+
+	// MerkleRootHash is the optional hash of the tapscript tree that is
+	// committed to by the funding output. If it isn't set, the funding
+	// output is a BIP-0086 key spend only output.
+	MerkleRootHash *[32]byte
+
+	// ExtraOpaqueData is the set of unknown TLV records that were included
+	// in the message. By holding onto them, we ensure that we're able to
+	// properly validate the signature that covers these new fields, and
+	// ensure we're able to make upgrades to the network in a forwards
+	// compatible manner.
+	ExtraOpaqueData ExtraOpaqueData
+}
+
+// A compile time check to ensure ChannelAnnouncement2 implements the
+// lnwire.Message interface.
+var _ Message = (*ChannelAnnouncement2)(nil)
+
+// Decode deserializes a serialized ChannelAnnouncement2 stored in the passed
+// io.Reader observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (a *ChannelAnnouncement2) Decode(r io.Reader, pver uint32) error {
+	var tlvData ExtraOpaqueData
+	if err := ReadElements(r, &a.Signature, &tlvData); err != nil {
+		return err
+	}
+
+	var (
+		capacity    uint64
+		bitcoinKey1 [33]byte
+		bitcoinKey2 [33]byte
+		merkleRoot  [32]byte
+	)
+	a.Features = NewRawFeatureVector()
+	typeMap, err := tlvData.ExtractRecords(
+		a.knownRecords(&capacity, &bitcoinKey1, &bitcoinKey2,
+			&merkleRoot)...,
+	)
+	if err != nil {
+		return err
+	}
+
+	a.Capacity = btcutil.Amount(capacity)
+
+	// We'll only set the optional fields whose TLV types were included in
+	// the stream.
+	if val, ok := typeMap[ChanAnn2BitcoinKey1]; ok && val == nil {
+		a.BitcoinKey1 = &bitcoinKey1
+	}
+	if val, ok := typeMap[ChanAnn2BitcoinKey2]; ok && val == nil {
+		a.BitcoinKey2 = &bitcoinKey2
+	}
+	if val, ok := typeMap[ChanAnn2MerkleRootHash]; ok && val == nil {
+		a.MerkleRootHash = &merkleRoot
+	}
+
+	// Hold onto the records we don't know about, so they can be included
+	// when the message is relayed.
+	var unknownRecords []tlv.RecordProducer
+	for typ, val := range typeMap {
+		if val == nil {
+			continue
+		}
+
+		val := val
+		unknownRecords = append(unknownRecords, &tlvRecord{
+			tlv.MakePrimitiveRecord(typ, &val),
+		})
+	}
+
+	a.ExtraOpaqueData = make([]byte, 0)
+	if len(unknownRecords) == 0 {
+		return nil
+	}
+
+	return a.ExtraOpaqueData.PackRecords(unknownRecords...)
+}
+
+// Encode serializes the target ChannelAnnouncement2 into the passed io.Writer
+// observing the protocol version specified.
+//
+// This is part of the lnwire.Message interface.
+func (a *ChannelAnnouncement2) Encode(w *bytes.Buffer, pver uint32) error {
+	if err := WriteSig(w, a.Signature); err != nil {
+		return err
+	}
+
+	tlvData, err := a.DataToSign()
+	if err != nil {
+		return err
+	}
+
+	return WriteBytes(w, tlvData)
+}
+
+// MsgType returns the integer uniquely identifying this message type on the
+// wire.
+//
+// This is part of the lnwire.Message interface.
+func (a *ChannelAnnouncement2) MsgType() MessageType {
+	return MsgChannelAnnouncement2
+}
+
+// DataToSign returns the TLV stream of the message, which consists of all the
+// fields but the signature.
+func (a *ChannelAnnouncement2) DataToSign() ([]byte, error) {
+	var (
+		capacity = uint64(a.Capacity)
+		records  = a.knownRecords(&capacity, a.BitcoinKey1,
+			a.BitcoinKey2, a.MerkleRootHash)
+	)
+
+	// Add the unknown records we're holding onto, skipping any that would
+	// collide with the known ones.
+	unknown, err := a.ExtraOpaqueData.ExtractRecords()
+	if err != nil {
+		return nil, err
+	}
+	for typ, val := range unknown {
+		if typ <= ChanAnn2MerkleRootHash && typ%2 == 0 {
+			continue
+		}
+
+		val := val
+		records = append(records, &tlvRecord{
+			tlv.MakePrimitiveRecord(typ, &val),
+		})
+	}
+
+	var tlvData ExtraOpaqueData
+	if err := tlvData.PackRecords(records...); err != nil {
+		return nil, err
+	}
+
+	return tlvData, nil
+}
+
+// DigestToSign returns the tagged hash of the TLV stream of the message, which
+// is the digest signed by the aggregate key of the channel.
+func (a *ChannelAnnouncement2) DigestToSign() (*chainhash.Hash, error) {
+	data, err := a.DataToSign()
+	if err != nil {
+		return nil, err
+	}
+
+	return chainhash.TaggedHash(chanAnn2SigTag, data), nil
+}
+
+// knownRecords returns the record producers of all the fields known to this
+// version of the message. The optional fields are only included if the
+// passed pointer isn't nil.
+func (a *ChannelAnnouncement2) knownRecords(capacity *uint64, bitcoinKey1,
+	bitcoinKey2 *[33]byte, merkleRoot *[32]byte) []tlv.RecordProducer {
+
+	if a.Features == nil {
+		a.Features = NewRawFeatureVector()
+	}
+
+	chainHash := (*[32]byte)(&a.ChainHash)
+	records := []tlv.RecordProducer{
+		&tlvRecord{
+			tlv.MakePrimitiveRecord(ChanAnn2ChainHash, chainHash),
+		},
+		&tlvRecord{featuresRecord(ChanAnn2Features, a.Features)},
+		&tlvRecord{tlv.MakeStaticRecord(
+			ChanAnn2ShortChannelID, &a.ShortChannelID, 8,
+			EShortChannelID, DShortChannelID,
+		)},
+		&tlvRecord{tlv.MakePrimitiveRecord(ChanAnn2Capacity, capacity)},
+		&tlvRecord{
+			tlv.MakePrimitiveRecord(ChanAnn2NodeID1, &a.NodeID1),
+		},
+		&tlvRecord{
+			tlv.MakePrimitiveRecord(ChanAnn2NodeID2, &a.NodeID2),
+		},
+	}
+
+	if bitcoinKey1 != nil {
+		records = append(records, &tlvRecord{tlv.MakePrimitiveRecord(
+			ChanAnn2BitcoinKey1, bitcoinKey1,
+		)})
+	}
+	if bitcoinKey2 != nil {
+		records = append(records, &tlvRecord{tlv.MakePrimitiveRecord(
+			ChanAnn2BitcoinKey2, bitcoinKey2,
+		)})
+	}
+	if merkleRoot != nil {
+		records = append(records, &tlvRecord{tlv.MakePrimitiveRecord(
+			ChanAnn2MerkleRootHash, merkleRoot,
+		)})
+	}
+
+	return records
+}
+
+// featuresRecord returns a TLV record that encodes a raw feature vector as
+// its base256 bytes, without a length prefix.
+func featuresRecord(typ tlv.Type, fv *RawFeatureVector) tlv.Record {
+	sizeFunc := func() uint64 {
+		return uint64(fv.SerializeSize())
+	}
+
+	return tlv.MakeDynamicRecord(
+		typ, fv, sizeFunc, featuresEncoder, featuresDecoder,
+	)
+}
+
+// featuresEncoder is a custom TLV encoder for a raw feature vector record.
+func featuresEncoder(w io.Writer, val interface{}, buf *[8]byte) error {
+	if v, ok := val.(*RawFeatureVector); ok {
+		return v.encode(w, v.SerializeSize(), 8)
+	}
+
+	return tlv.NewTypeForEncodingErr(val, "lnwire.RawFeatureVector")
+}
+
+// featuresDecoder is a custom TLV decoder for a raw feature vector record.
+func featuresDecoder(r io.Reader, val interface{}, buf *[8]byte,
+	l uint64) error {
+
+	if v, ok := val.(*RawFeatureVector); ok {
+		return v.decode(r, int(l), 8)
+	}
+
+	return tlv.NewTypeForDecodingErr(val, "lnwire.RawFeatureVector", l, l)
+}
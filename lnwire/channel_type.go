@@ -2,6 +2,7 @@ package lnwire
 
 import (
 	"io"
+	"sort"
 
 	"github.com/lightningnetwork/lnd/tlv"
 )
@@ -22,6 +23,20 @@ func (c ChannelType) featureBitLen() uint64 {
 	return uint64(fv.SerializeSize())
 }
 
+// FeatureBits returns the feature bits that comprise the channel type, in
+// ascending order.
+func (c ChannelType) FeatureBits() []FeatureBit {
+	bits := make([]FeatureBit, 0, len(c.features))
+	for bit := range c.features {
+		bits = append(bits, bit)
+	}
+	sort.Slice(bits, func(i, j int) bool {
+		return bits[i] < bits[j]
+	})
+
+	return bits
+}
+
 // Record returns a TLV record that can be used to encode/decode the channel
 // type from a given TLV stream.
 func (c *ChannelType) Record() tlv.Record {
@@ -26,3 +26,22 @@ func TestChannelTypeEncodeDecode(t *testing.T) {
 	require.Contains(t, tlvs, ChannelTypeRecordType)
 	require.Equal(t, chanType, chanType2)
 }
+
+// TestChannelTypeFeatureBits tests that the feature bits of a channel type are
+// returned in ascending order.
+func TestChannelTypeFeatureBits(t *testing.T) {
+	t.Parallel()
+
+	chanType := ChannelType(*NewRawFeatureVector(
+		ScidAliasRequired,
+		StaticRemoteKeyRequired,
+		AnchorsZeroFeeHtlcTxRequired,
+	))
+	require.Equal(t, []FeatureBit{
+		StaticRemoteKeyRequired,
+		AnchorsZeroFeeHtlcTxRequired,
+		ScidAliasRequired,
+	}, chanType.FeatureBits())
+
+	require.Empty(t, ChannelType(*NewRawFeatureVector()).FeatureBits())
+}
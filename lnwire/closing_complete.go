@@ -0,0 +1,205 @@
+package lnwire
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+const (
+	// CloserNoCloseeType is the TLV record type of the signature for a
+	// closing transaction that only pays out to the closer, as the output
+	// of the closee would be dust.
+	CloserNoCloseeType tlv.Type = 1
+
+	// NoCloserCloseeType is the TLV record type of the signature for a
+	// closing transaction that only pays out to the closee, as the closer
+	// spends its whole output on fees.
+	NoCloserCloseeType tlv.Type = 2
+
+	// CloserAndCloseeType is the TLV record type of the signature for a
+	// closing transaction that pays out to both parties.
+	CloserAndCloseeType tlv.Type = 3
+)
+
+// ClosingSigs is the set of signatures for the variants of a closing
+// transaction in the simple cooperative close protocol. Each variant differs
+// in the outputs that are present on the transaction. Only the signatures of
+// the variants the sender deems valid are set.
+type ClosingSigs struct {
+	// CloserNoClosee is the signature for the closing transaction that
+	// omits the output of the closee.
+	CloserNoClosee *Sig
+
+	// NoCloserClosee is the signature for the closing transaction that
+	// omits the output of the closer.
+	NoCloserClosee *Sig
+
+	// CloserAndClosee is the signature for the closing transaction that
+	// pays out to both parties.
+	CloserAndClosee *Sig
+}
+
+// sigRecord is a record producer for a signature of one of the variants of a
+// closing transaction.
+type sigRecord struct {
+	typ tlv.Type
+	sig *Sig
+}
+
+// Record returns a TLV record that can be used to encode the signature within
+// the ExtraData TLV stream.
+func (s *sigRecord) Record() tlv.Record {
+	return tlv.MakeStaticRecord(
+		s.typ, (*[64]byte)(s.sig), 64, tlv.EBytes64, tlv.DBytes64,
+	)
+}
+
+// encode packs the set signatures into the given TLV stream.
+func (c *ClosingSigs) encode(extraData *ExtraOpaqueData) error {
+	var recordProducers []tlv.RecordProducer
+	if c.CloserNoClosee != nil {
+		recordProducers = append(recordProducers, &sigRecord{
+			typ: CloserNoCloseeType, sig: c.CloserNoClosee,
+		})
+	}
+	if c.NoCloserClosee != nil {
+		recordProducers = append(recordProducers, &sigRecord{
+			typ: NoCloserCloseeType, sig: c.NoCloserClosee,
+		})
+	}
+	if c.CloserAndClosee != nil {
+		recordProducers = append(recordProducers, &sigRecord{
+			typ: CloserAndCloseeType, sig: c.CloserAndClosee,
+		})
+	}
+
+	// We'll leave the TLV stream untouched if no signature is set.
+	if len(recordProducers) == 0 {
+		return nil
+	}
+
+	return EncodeMessageExtraData(extraData, recordProducers...)
+}
+
+// decode parses the signatures contained in the given TLV stream.
+func (c *ClosingSigs) decode(extraData ExtraOpaqueData) error {
+	var closerNoClosee, noCloserClosee, closerAndClosee Sig
+	typeMap, err := extraData.ExtractRecords(
+		&sigRecord{typ: CloserNoCloseeType, sig: &closerNoClosee},
+		&sigRecord{typ: NoCloserCloseeType, sig: &noCloserClosee},
+		&sigRecord{typ: CloserAndCloseeType, sig: &closerAndClosee},
+	)
+	if err != nil {
+		return err
+	}
+
+	// We'll only set the signatures whose TLV types were included in the
+	// stream.
+	if val, ok := typeMap[CloserNoCloseeType]; ok && val == nil {
+		c.CloserNoClosee = &closerNoClosee
+	}
+	if val, ok := typeMap[NoCloserCloseeType]; ok && val == nil {
+		c.NoCloserClosee = &noCloserClosee
+	}
+	if val, ok := typeMap[CloserAndCloseeType]; ok && val == nil {
+		c.CloserAndClosee = &closerAndClosee
+	}
+
+	return nil
+}
+
+// ClosingComplete is sent by either party of a channel that is being closed
+// with the simple cooperative close protocol, once both sides have exchanged
+// Shutdown messages. The sender, the closer, proposes a closing transaction
+// that pays the whole fee from its own output, and signs it. The closer can
+// send another ClosingComplete paying a higher fee later on, replacing the
+// prior version of its closing transaction.
+type ClosingComplete struct {
+	// ChannelID serves to identify which channel is to be closed.
+	ChannelID ChannelID
+
+	// CloserScript is the script the output of the closer pays to.
+	CloserScript DeliveryAddress
+
+	// CloseeScript is the script the output of the closee pays to.
+	CloseeScript DeliveryAddress
+
+	// FeeSatoshis is the absolute fee the closer pays for the closing
+	// transaction.
+	FeeSatoshis btcutil.Amount
+
+	// LockTime is the lock time of the closing transaction.
+	LockTime uint32
+
+	// ClosingSigs are the signatures of the closer for the variants of
+	// the closing transaction.
+	ClosingSigs ClosingSigs
+
+	// ExtraData is the set of data that was appended to this message to
+	// fill out the full maximum transport message size. These fields can
+	// be used to specify optional data such as custom TLV fields.
+	ExtraData ExtraOpaqueData
+}
+
+// A compile time check to ensure ClosingComplete implements the
+// lnwire.Message interface.
+var _ Message = (*ClosingComplete)(nil)
+
+// Decode deserializes a serialized ClosingComplete message stored in the
+// passed io.Reader observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (c *ClosingComplete) Decode(r io.Reader, pver uint32) error {
+	err := ReadElements(
+		r, &c.ChannelID, &c.CloserScript, &c.CloseeScript,
+		&c.FeeSatoshis, &c.LockTime, &c.ExtraData,
+	)
+	if err != nil {
+		return err
+	}
+
+	return c.ClosingSigs.decode(c.ExtraData)
+}
+
+// Encode serializes the target ClosingComplete into the passed io.Writer
+// observing the protocol version specified.
+//
+// This is part of the lnwire.Message interface.
+func (c *ClosingComplete) Encode(w *bytes.Buffer, pver uint32) error {
+	if err := c.ClosingSigs.encode(&c.ExtraData); err != nil {
+		return err
+	}
+
+	if err := WriteChannelID(w, c.ChannelID); err != nil {
+		return err
+	}
+
+	if err := WriteDeliveryAddress(w, c.CloserScript); err != nil {
+		return err
+	}
+
+	if err := WriteDeliveryAddress(w, c.CloseeScript); err != nil {
+		return err
+	}
+
+	if err := WriteSatoshi(w, c.FeeSatoshis); err != nil {
+		return err
+	}
+
+	if err := WriteUint32(w, c.LockTime); err != nil {
+		return err
+	}
+
+	return WriteBytes(w, c.ExtraData)
+}
+
+// MsgType returns the integer uniquely identifying this message type on the
+// wire.
+//
+// This is part of the lnwire.Message interface.
+func (c *ClosingComplete) MsgType() MessageType {
+	return MsgClosingComplete
+}
@@ -0,0 +1,100 @@
+package lnwire
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/btcsuite/btcd/btcutil"
+)
+
+// ClosingSig is sent in response to a ClosingComplete message by the closee.
+// It echoes the parameters of the closing transaction proposed by the closer,
+// along with the signature of the closee for the variant of the transaction
+// it accepted. With this signature, the closer is able to broadcast the fully
+// signed closing transaction.
+type ClosingSig struct {
+	// ChannelID serves to identify which channel is to be closed.
+	ChannelID ChannelID
+
+	// CloserScript is the script the output of the closer pays to.
+	CloserScript DeliveryAddress
+
+	// CloseeScript is the script the output of the closee pays to.
+	CloseeScript DeliveryAddress
+
+	// FeeSatoshis is the absolute fee the closer pays for the closing
+	// transaction.
+	FeeSatoshis btcutil.Amount
+
+	// LockTime is the lock time of the closing transaction.
+	LockTime uint32
+
+	// ClosingSigs holds the signature of the closee for the variant of the
+	// closing transaction it accepted. Exactly one signature is set.
+	ClosingSigs ClosingSigs
+
+	// ExtraData is the set of data that was appended to this message to
+	// fill out the full maximum transport message size. These fields can
+	// be used to specify optional data such as custom TLV fields.
+	ExtraData ExtraOpaqueData
+}
+
+// A compile time check to ensure ClosingSig implements the lnwire.Message
+// interface.
+var _ Message = (*ClosingSig)(nil)
+
+// Decode deserializes a serialized ClosingSig message stored in the passed
+// io.Reader observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (c *ClosingSig) Decode(r io.Reader, pver uint32) error {
+	err := ReadElements(
+		r, &c.ChannelID, &c.CloserScript, &c.CloseeScript,
+		&c.FeeSatoshis, &c.LockTime, &c.ExtraData,
+	)
+	if err != nil {
+		return err
+	}
+
+	return c.ClosingSigs.decode(c.ExtraData)
+}
+
+// Encode serializes the target ClosingSig into the passed io.Writer observing
+// the protocol version specified.
+//
+// This is part of the lnwire.Message interface.
+func (c *ClosingSig) Encode(w *bytes.Buffer, pver uint32) error {
+	if err := c.ClosingSigs.encode(&c.ExtraData); err != nil {
+		return err
+	}
+
+	if err := WriteChannelID(w, c.ChannelID); err != nil {
+		return err
+	}
+
+	if err := WriteDeliveryAddress(w, c.CloserScript); err != nil {
+		return err
+	}
+
+	if err := WriteDeliveryAddress(w, c.CloseeScript); err != nil {
+		return err
+	}
+
+	if err := WriteSatoshi(w, c.FeeSatoshis); err != nil {
+		return err
+	}
+
+	if err := WriteUint32(w, c.LockTime); err != nil {
+		return err
+	}
+
+	return WriteBytes(w, c.ExtraData)
+}
+
+// MsgType returns the integer uniquely identifying this message type on the
+// wire.
+//
+// This is part of the lnwire.Message interface.
+func (c *ClosingSig) MsgType() MessageType {
+	return MsgClosingSig
+}
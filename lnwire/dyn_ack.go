@@ -0,0 +1,58 @@
+package lnwire
+
+import (
+	"bytes"
+	"io"
+)
+
+// DynAck is sent in reply to a DynPropose message to accept all of the
+// proposed commitment parameters.
+type DynAck struct {
+	// ChanID identifies the channel whose commitment is upgraded.
+	ChanID ChannelID
+
+	// ExtraData is the set of data that was appended to this message to
+	// fill out the full maximum transport message size. These fields can
+	// be used to specify optional data such as custom TLV fields.
+	ExtraData ExtraOpaqueData
+}
+
+// A compile time check to ensure DynAck implements the lnwire.Message
+// interface.
+var _ Message = (*DynAck)(nil)
+
+// Encode serializes the target DynAck into the passed io.Writer observing the
+// protocol version specified.
+//
+// This is part of the lnwire.Message interface.
+func (da *DynAck) Encode(w *bytes.Buffer, pver uint32) error {
+	if err := WriteChannelID(w, da.ChanID); err != nil {
+		return err
+	}
+
+	return WriteBytes(w, da.ExtraData)
+}
+
+// Decode deserializes a serialized DynAck message stored in the passed
+// io.Reader observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (da *DynAck) Decode(r io.Reader, pver uint32) error {
+	return ReadElements(r, &da.ChanID, &da.ExtraData)
+}
+
+// TargetChanID returns the channel id of the link for which this message is
+// intended.
+//
+// NOTE: Part of peer.LinkUpdater interface.
+func (da *DynAck) TargetChanID() ChannelID {
+	return da.ChanID
+}
+
+// MsgType returns the integer uniquely identifying this message type on the
+// wire.
+//
+// This is part of the lnwire.Message interface.
+func (da *DynAck) MsgType() MessageType {
+	return MsgDynAck
+}
@@ -0,0 +1,201 @@
+package lnwire
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+const (
+	// DPDustLimitSatoshis is the TLV type number that identifies the
+	// record for DynPropose.DustLimit.
+	DPDustLimitSatoshis tlv.Type = 0
+
+	// DPToSelfDelay is the TLV type number that identifies the record for
+	// DynPropose.CsvDelay.
+	DPToSelfDelay tlv.Type = 8
+
+	// DPMaxAcceptedHtlcs is the TLV type number that identifies the record
+	// for DynPropose.MaxAcceptedHTLCs.
+	DPMaxAcceptedHtlcs tlv.Type = 10
+
+	// DPChannelType is the TLV type number that identifies the record for
+	// DynPropose.ChannelType.
+	DPChannelType tlv.Type = 14
+)
+
+// DynPropose is sent by the initiator of quiescence to propose new commitment
+// parameters for a quiescent channel. Only the parameters that should change
+// are included. The receiver replies with DynAck if it accepts all of them,
+// or with DynReject otherwise. Once accepted, the proposer signs a new
+// commitment using the new parameters, which are used for all commitments
+// from then on.
+type DynPropose struct {
+	// ChanID identifies the channel whose commitment is upgraded.
+	ChanID ChannelID
+
+	// DustLimit, if set, is the new dust limit of the sender, below which
+	// outputs aren't materialized on its commitment.
+	DustLimit *btcutil.Amount
+
+	// CsvDelay, if set, is the new relative time lock the receiver must
+	// use for its own outputs on its commitment.
+	CsvDelay *uint16
+
+	// MaxAcceptedHTLCs, if set, is the new maximum number of HTLCs the
+	// receiver may offer to the sender at any time.
+	MaxAcceptedHTLCs *uint16
+
+	// ChannelType, if set, is the new commitment format of the channel.
+	ChannelType *ChannelType
+
+	// ExtraData is the set of data that was appended to this message to
+	// fill out the full maximum transport message size. These fields can
+	// be used to specify optional data such as custom TLV fields.
+	ExtraData ExtraOpaqueData
+}
+
+// A compile time check to ensure DynPropose implements the lnwire.Message
+// interface.
+var _ Message = (*DynPropose)(nil)
+
+// Encode serializes the target DynPropose into the passed io.Writer observing
+// the protocol version specified.
+//
+// This is part of the lnwire.Message interface.
+func (dp *DynPropose) Encode(w *bytes.Buffer, pver uint32) error {
+	var recordProducers []tlv.RecordProducer
+	if dp.DustLimit != nil {
+		dustLimit := uint64(*dp.DustLimit)
+		recordProducers = append(recordProducers, &tlvRecord{
+			tlv.MakePrimitiveRecord(
+				DPDustLimitSatoshis, &dustLimit,
+			),
+		})
+	}
+	if dp.CsvDelay != nil {
+		recordProducers = append(recordProducers, &tlvRecord{
+			tlv.MakePrimitiveRecord(DPToSelfDelay, dp.CsvDelay),
+		})
+	}
+	if dp.MaxAcceptedHTLCs != nil {
+		recordProducers = append(recordProducers, &tlvRecord{
+			tlv.MakePrimitiveRecord(
+				DPMaxAcceptedHtlcs, dp.MaxAcceptedHTLCs,
+			),
+		})
+	}
+	if dp.ChannelType != nil {
+		recordProducers = append(recordProducers, &tlvRecord{
+			dynChannelTypeRecord(dp.ChannelType),
+		})
+	}
+
+	// We'll leave the TLV stream untouched if no parameter is set.
+	if len(recordProducers) > 0 {
+		err := EncodeMessageExtraData(
+			&dp.ExtraData, recordProducers...,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := WriteChannelID(w, dp.ChanID); err != nil {
+		return err
+	}
+
+	return WriteBytes(w, dp.ExtraData)
+}
+
+// Decode deserializes a serialized DynPropose message stored in the passed
+// io.Reader observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (dp *DynPropose) Decode(r io.Reader, pver uint32) error {
+	if err := ReadElements(r, &dp.ChanID, &dp.ExtraData); err != nil {
+		return err
+	}
+
+	var (
+		dustLimit        uint64
+		csvDelay         uint16
+		maxAcceptedHTLCs uint16
+		chanType         ChannelType
+	)
+	typeMap, err := dp.ExtraData.ExtractRecords(
+		&tlvRecord{
+			tlv.MakePrimitiveRecord(
+				DPDustLimitSatoshis, &dustLimit,
+			),
+		},
+		&tlvRecord{tlv.MakePrimitiveRecord(DPToSelfDelay, &csvDelay)},
+		&tlvRecord{
+			tlv.MakePrimitiveRecord(
+				DPMaxAcceptedHtlcs, &maxAcceptedHTLCs,
+			),
+		},
+		&tlvRecord{dynChannelTypeRecord(&chanType)},
+	)
+	if err != nil {
+		return err
+	}
+
+	// We'll only set the parameters whose TLV types were included in the
+	// stream.
+	if val, ok := typeMap[DPDustLimitSatoshis]; ok && val == nil {
+		amt := btcutil.Amount(dustLimit)
+		dp.DustLimit = &amt
+	}
+	if val, ok := typeMap[DPToSelfDelay]; ok && val == nil {
+		dp.CsvDelay = &csvDelay
+	}
+	if val, ok := typeMap[DPMaxAcceptedHtlcs]; ok && val == nil {
+		dp.MaxAcceptedHTLCs = &maxAcceptedHTLCs
+	}
+	if val, ok := typeMap[DPChannelType]; ok && val == nil {
+		dp.ChannelType = &chanType
+	}
+
+	return nil
+}
+
+// TargetChanID returns the channel id of the link for which this message is
+// intended.
+//
+// NOTE: Part of peer.LinkUpdater interface.
+func (dp *DynPropose) TargetChanID() ChannelID {
+	return dp.ChanID
+}
+
+// MsgType returns the integer uniquely identifying this message type on the
+// wire.
+//
+// This is part of the lnwire.Message interface.
+func (dp *DynPropose) MsgType() MessageType {
+	return MsgDynPropose
+}
+
+// tlvRecord wraps a single TLV record, so that it can be passed where a record
+// producer is expected.
+type tlvRecord struct {
+	record tlv.Record
+}
+
+// Record returns the wrapped TLV record.
+//
+// NOTE: Part of the tlv.RecordProducer interface.
+func (t *tlvRecord) Record() tlv.Record {
+	return t.record
+}
+
+// dynChannelTypeRecord returns a TLV record that encodes the channel type
+// within a DynPropose message.
+func dynChannelTypeRecord(chanType *ChannelType) tlv.Record {
+	return tlv.MakeDynamicRecord(
+		DPChannelType, chanType, chanType.featureBitLen,
+		channelTypeEncoder, channelTypeDecoder,
+	)
+}
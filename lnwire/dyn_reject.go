@@ -0,0 +1,67 @@
+package lnwire
+
+import (
+	"bytes"
+	"io"
+)
+
+// DynReject is sent in reply to a DynPropose message if the receiver doesn't
+// accept the proposed commitment parameters. The commitment of the channel
+// remains unchanged.
+type DynReject struct {
+	// ChanID identifies the channel whose upgrade was rejected.
+	ChanID ChannelID
+
+	// Reason is a human readable description of why the proposal was
+	// rejected.
+	Reason WarningData
+
+	// ExtraData is the set of data that was appended to this message to
+	// fill out the full maximum transport message size. These fields can
+	// be used to specify optional data such as custom TLV fields.
+	ExtraData ExtraOpaqueData
+}
+
+// A compile time check to ensure DynReject implements the lnwire.Message
+// interface.
+var _ Message = (*DynReject)(nil)
+
+// Encode serializes the target DynReject into the passed io.Writer observing
+// the protocol version specified.
+//
+// This is part of the lnwire.Message interface.
+func (dr *DynReject) Encode(w *bytes.Buffer, pver uint32) error {
+	if err := WriteChannelID(w, dr.ChanID); err != nil {
+		return err
+	}
+
+	if err := WriteWarningData(w, dr.Reason); err != nil {
+		return err
+	}
+
+	return WriteBytes(w, dr.ExtraData)
+}
+
+// Decode deserializes a serialized DynReject message stored in the passed
+// io.Reader observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (dr *DynReject) Decode(r io.Reader, pver uint32) error {
+	return ReadElements(r, &dr.ChanID, &dr.Reason, &dr.ExtraData)
+}
+
+// TargetChanID returns the channel id of the link for which this message is
+// intended.
+//
+// NOTE: Part of peer.LinkUpdater interface.
+func (dr *DynReject) TargetChanID() ChannelID {
+	return dr.ChanID
+}
+
+// MsgType returns the integer uniquely identifying this message type on the
+// wire.
+//
+// This is part of the lnwire.Message interface.
+func (dr *DynReject) MsgType() MessageType {
+	return MsgDynReject
+}
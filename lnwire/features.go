@@ -161,6 +161,26 @@ const (
 	// sender-generated preimages according to BOLT XX.
 	AMPOptional FeatureBit = 31
 
+	// QuiescenceRequired is a required feature bit that signals that the
+	// node requires support for the stfu message, which is used to pause
+	// all updates of a channel.
+	QuiescenceRequired FeatureBit = 34
+
+	// QuiescenceOptional is an optional feature bit that signals that the
+	// node supports the stfu message, which is used to pause all updates
+	// of a channel.
+	QuiescenceOptional FeatureBit = 35
+
+	// ProvideStorageRequired is a required feature bit that signals that
+	// the node is willing to store a small blob of data on behalf of its
+	// channel peers, and hand it back to them upon reconnection.
+	ProvideStorageRequired FeatureBit = 42
+
+	// ProvideStorageOptional is an optional feature bit that signals that
+	// the node is willing to store a small blob of data on behalf of its
+	// channel peers, and hand it back to them upon reconnection.
+	ProvideStorageOptional FeatureBit = 43
+
 	// ExplicitChannelTypeRequired is a required bit that denotes that a
 	// connection established with this node is to use explicit channel
 	// commitment types for negotiation instead of the existing implicit
@@ -213,6 +233,18 @@ const (
 	// able and willing to accept keysend payments.
 	KeysendOptional = 55
 
+	// SimpleCloseRequired is a required feature bit that signals that the
+	// node requires the simple cooperative close protocol, in which each
+	// party signs and can later replace its own closing transaction,
+	// paying its own fee.
+	SimpleCloseRequired FeatureBit = 60
+
+	// SimpleCloseOptional is an optional feature bit that signals that the
+	// node supports the simple cooperative close protocol, in which each
+	// party signs and can later replace its own closing transaction,
+	// paying its own fee.
+	SimpleCloseOptional FeatureBit = 61
+
 	// ScriptEnforcedLeaseOptional is an optional feature bit that signals
 	// that the node requires channels having zero-fee second-level HTLC
 	// transactions, which also imply anchor commitments, along with an
@@ -231,6 +263,34 @@ const (
 	// TODO: Decide on actual feature bit value.
 	ScriptEnforcedLeaseOptional FeatureBit = 2023
 
+	// DynamicCommitmentsRequired is a required feature bit that signals
+	// that the node requires support for upgrading the commitment type and
+	// parameters of an open channel using the dyn_propose message.
+	//
+	// TODO: Decide on actual feature bit value.
+	DynamicCommitmentsRequired FeatureBit = 2024
+
+	// DynamicCommitmentsOptional is an optional feature bit that signals
+	// that the node supports upgrading the commitment type and parameters
+	// of an open channel using the dyn_propose message.
+	//
+	// TODO: Decide on actual feature bit value.
+	DynamicCommitmentsOptional FeatureBit = 2025
+
+	// GossipReconcileRequired is a required feature bit that signals that
+	// the node requires support for synchronizing the channel updates of
+	// the network using set reconciliation.
+	//
+	// TODO: Decide on actual feature bit value.
+	GossipReconcileRequired FeatureBit = 2026
+
+	// GossipReconcileOptional is an optional feature bit that signals that
+	// the node supports synchronizing the channel updates of the network
+	// using set reconciliation.
+	//
+	// TODO: Decide on actual feature bit value.
+	GossipReconcileOptional FeatureBit = 2027
+
 	// MaxBolt11Feature is the maximum feature bit value allowed in bolt 11
 	// invoices.
 	//
@@ -277,6 +337,8 @@ var Features = map[FeatureBit]string{
 	WumboChannelsOptional:         "wumbo-channels",
 	AMPRequired:                   "amp",
 	AMPOptional:                   "amp",
+	ProvideStorageRequired:        "provide-storage",
+	ProvideStorageOptional:        "provide-storage",
 	PaymentMetadataOptional:       "payment-metadata",
 	PaymentMetadataRequired:       "payment-metadata",
 	ExplicitChannelTypeOptional:   "explicit-commitment-type",
@@ -291,6 +353,14 @@ var Features = map[FeatureBit]string{
 	ZeroConfOptional:              "zero-conf",
 	ShutdownAnySegwitRequired:     "shutdown-any-segwit",
 	ShutdownAnySegwitOptional:     "shutdown-any-segwit",
+	SimpleCloseRequired:           "simple-close",
+	SimpleCloseOptional:           "simple-close",
+	QuiescenceRequired:            "quiescence",
+	QuiescenceOptional:            "quiescence",
+	DynamicCommitmentsRequired:    "dynamic-commitments",
+	DynamicCommitmentsOptional:    "dynamic-commitments",
+	GossipReconcileRequired:       "gossip-reconcile",
+	GossipReconcileOptional:       "gossip-reconcile",
 }
 
 // RawFeatureVector represents a set of feature bits as defined in BOLT-09.  A
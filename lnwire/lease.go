@@ -0,0 +1,187 @@
+package lnwire
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+)
+
+// LeaseRequest is sent to a node advertising a liquidity ad to buy a channel
+// lease from it. If the node accepts the request, it replies with a LeaseOffer
+// carrying the invoice for the lease fee, and opens a script enforced lease
+// channel to the sender once the invoice is paid. Otherwise, it replies with
+// an Error referencing the LeaseID.
+type LeaseRequest struct {
+	// LeaseID uniquely identifies the lease throughout the request flow.
+	// It's generated by the sender and is only meaningful until the lease
+	// channel is opened.
+	LeaseID [32]byte
+
+	// ChainHash is the target chain of the lease channel.
+	ChainHash chainhash.Hash
+
+	// LeaseAmt is the amount of inbound liquidity requested, which is the
+	// capacity of the lease channel.
+	LeaseAmt btcutil.Amount
+
+	// LeaseDuration is the number of blocks the leased funds should be
+	// locked for, which must match the one advertised by the receiver.
+	LeaseDuration uint32
+
+	// MaxFee is the maximum fee the sender is willing to pay for the
+	// lease.
+	MaxFee btcutil.Amount
+
+	// ExtraData is the set of data that was appended to this message to
+	// fill out the full maximum transport message size. These fields can
+	// be used to specify optional data such as custom TLV fields.
+	ExtraData ExtraOpaqueData
+}
+
+// A compile time check to ensure LeaseRequest implements the lnwire.Message
+// interface.
+var _ Message = (*LeaseRequest)(nil)
+
+// Decode deserializes a serialized LeaseRequest message stored in the passed
+// io.Reader observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (l *LeaseRequest) Decode(r io.Reader, pver uint32) error {
+	return ReadElements(r,
+		l.LeaseID[:],
+		l.ChainHash[:],
+		&l.LeaseAmt,
+		&l.LeaseDuration,
+		&l.MaxFee,
+		&l.ExtraData,
+	)
+}
+
+// Encode serializes the target LeaseRequest into the passed io.Writer
+// observing the protocol version specified.
+//
+// This is part of the lnwire.Message interface.
+func (l *LeaseRequest) Encode(w *bytes.Buffer, pver uint32) error {
+	if err := WriteBytes(w, l.LeaseID[:]); err != nil {
+		return err
+	}
+
+	if err := WriteBytes(w, l.ChainHash[:]); err != nil {
+		return err
+	}
+
+	if err := WriteSatoshi(w, l.LeaseAmt); err != nil {
+		return err
+	}
+
+	if err := WriteUint32(w, l.LeaseDuration); err != nil {
+		return err
+	}
+
+	if err := WriteSatoshi(w, l.MaxFee); err != nil {
+		return err
+	}
+
+	return WriteBytes(w, l.ExtraData)
+}
+
+// MsgType returns the integer uniquely identifying this message type on the
+// wire.
+//
+// This is part of the lnwire.Message interface.
+func (l *LeaseRequest) MsgType() MessageType {
+	return MsgLeaseRequest
+}
+
+// LeaseOffer is sent in reply to a LeaseRequest that was accepted. It carries
+// the fee charged for the lease and the invoice to pay it. Once the invoice is
+// paid, the sender opens the lease channel.
+type LeaseOffer struct {
+	// LeaseID is the identifier of the LeaseRequest this offer replies to.
+	LeaseID [32]byte
+
+	// Fee is the fee charged for the lease, which is the amount of the
+	// invoice.
+	Fee btcutil.Amount
+
+	// LeaseExpiry is the earliest absolute height the lease channel will
+	// expire at. As the channel is only opened once the fee is paid, the
+	// actual expiry may be later.
+	LeaseExpiry uint32
+
+	// PaymentRequest is the encoded invoice for the lease fee.
+	PaymentRequest []byte
+
+	// ExtraData is the set of data that was appended to this message to
+	// fill out the full maximum transport message size. These fields can
+	// be used to specify optional data such as custom TLV fields.
+	ExtraData ExtraOpaqueData
+}
+
+// A compile time check to ensure LeaseOffer implements the lnwire.Message
+// interface.
+var _ Message = (*LeaseOffer)(nil)
+
+// Decode deserializes a serialized LeaseOffer message stored in the passed
+// io.Reader observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (l *LeaseOffer) Decode(r io.Reader, pver uint32) error {
+	err := ReadElements(r, l.LeaseID[:], &l.Fee, &l.LeaseExpiry)
+	if err != nil {
+		return err
+	}
+
+	var payReqLen [2]byte
+	if _, err := io.ReadFull(r, payReqLen[:]); err != nil {
+		return err
+	}
+
+	l.PaymentRequest = make([]byte, binary.BigEndian.Uint16(payReqLen[:]))
+	if _, err := io.ReadFull(r, l.PaymentRequest); err != nil {
+		return err
+	}
+
+	return ReadElements(r, &l.ExtraData)
+}
+
+// Encode serializes the target LeaseOffer into the passed io.Writer observing
+// the protocol version specified.
+//
+// This is part of the lnwire.Message interface.
+func (l *LeaseOffer) Encode(w *bytes.Buffer, pver uint32) error {
+	if err := WriteBytes(w, l.LeaseID[:]); err != nil {
+		return err
+	}
+
+	if err := WriteSatoshi(w, l.Fee); err != nil {
+		return err
+	}
+
+	if err := WriteUint32(w, l.LeaseExpiry); err != nil {
+		return err
+	}
+
+	if len(l.PaymentRequest) > math.MaxUint16 {
+		return fmt.Errorf("payment request too large: %d bytes",
+			len(l.PaymentRequest))
+	}
+	if err := writeDataWithLength(w, l.PaymentRequest); err != nil {
+		return err
+	}
+
+	return WriteBytes(w, l.ExtraData)
+}
+
+// MsgType returns the integer uniquely identifying this message type on the
+// wire.
+//
+// This is part of the lnwire.Message interface.
+func (l *LeaseOffer) MsgType() MessageType {
+	return MsgLeaseOffer
+}
@@ -0,0 +1,217 @@
+package lnwire
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+const (
+	// LiquidityAdRecordType is the type of the experimental record within
+	// the extra data of a node announcement that advertises the rates at
+	// which the node leases out inbound liquidity.
+	//
+	// TODO: Decide on actual TLV type. Custom records start at 2^16.
+	LiquidityAdRecordType tlv.Type = 1<<16 + 1
+
+	// liquidityAdLength is the length of an encoded LiquidityAd.
+	liquidityAdLength = 32
+
+	// feeRatePPMDenominator is the denominator of the proportional lease
+	// fee rate, which is expressed in parts per million.
+	feeRatePPMDenominator = 1_000_000
+)
+
+var (
+	// ErrInvalidLiquidityAd is returned when a liquidity ad contains
+	// values that don't make up a valid offer.
+	ErrInvalidLiquidityAd = errors.New("invalid liquidity ad")
+)
+
+// LiquidityAd advertises the terms under which a node leases out inbound
+// liquidity. A lease is a channel opened by the advertising node that locks
+// its funds within the channel until the lease expires, in exchange for a fee
+// paid by the node buying the lease.
+type LiquidityAd struct {
+	// LeaseDuration is the number of blocks the funds of a lease are
+	// locked for once the lease channel is opened.
+	LeaseDuration uint32
+
+	// FeeBase is the fixed fee charged for a lease.
+	FeeBase btcutil.Amount
+
+	// FeeRate is the fee charged proportionally to the amount leased, in
+	// parts per million.
+	FeeRate uint32
+
+	// MinLeaseAmt is the minimum amount that can be leased.
+	MinLeaseAmt btcutil.Amount
+
+	// MaxLeaseAmt is the maximum amount that can be leased, which caps the
+	// capacity of a lease channel.
+	MaxLeaseAmt btcutil.Amount
+}
+
+// Fee returns the fee charged for leasing the given amount.
+func (l *LiquidityAd) Fee(amt btcutil.Amount) btcutil.Amount {
+	return l.FeeBase + amt*btcutil.Amount(l.FeeRate)/feeRatePPMDenominator
+}
+
+// Validate checks that the liquidity ad makes up a valid offer.
+func (l *LiquidityAd) Validate() error {
+	switch {
+	case l.LeaseDuration == 0:
+		return fmt.Errorf("%w: lease duration must be positive",
+			ErrInvalidLiquidityAd)
+
+	case l.FeeBase < 0:
+		return fmt.Errorf("%w: negative base fee",
+			ErrInvalidLiquidityAd)
+
+	case l.MinLeaseAmt < 0:
+		return fmt.Errorf("%w: negative min lease amount",
+			ErrInvalidLiquidityAd)
+
+	case l.MaxLeaseAmt <= 0:
+		return fmt.Errorf("%w: max lease amount must be positive",
+			ErrInvalidLiquidityAd)
+
+	case l.MinLeaseAmt > l.MaxLeaseAmt:
+		return fmt.Errorf("%w: min lease amount %v above max lease "+
+			"amount %v", ErrInvalidLiquidityAd, l.MinLeaseAmt,
+			l.MaxLeaseAmt)
+	}
+
+	return nil
+}
+
+// Record returns a TLV record that can be used to encode/decode the
+// LiquidityAd type from a given TLV stream.
+func (l *LiquidityAd) Record() tlv.Record {
+	return tlv.MakeStaticRecord(
+		LiquidityAdRecordType, l, liquidityAdLength,
+		liquidityAdEncoder, liquidityAdDecoder,
+	)
+}
+
+// liquidityAdEncoder is a custom TLV encoder for the LiquidityAd record.
+func liquidityAdEncoder(w io.Writer, val interface{}, buf *[8]byte) error {
+	if v, ok := val.(*LiquidityAd); ok {
+		if err := tlv.EUint32T(w, v.LeaseDuration, buf); err != nil {
+			return err
+		}
+		if err := tlv.EUint64T(w, uint64(v.FeeBase), buf); err != nil {
+			return err
+		}
+		if err := tlv.EUint32T(w, v.FeeRate, buf); err != nil {
+			return err
+		}
+		err := tlv.EUint64T(w, uint64(v.MinLeaseAmt), buf)
+		if err != nil {
+			return err
+		}
+
+		return tlv.EUint64T(w, uint64(v.MaxLeaseAmt), buf)
+	}
+
+	return tlv.NewTypeForEncodingErr(val, "lnwire.LiquidityAd")
+}
+
+// liquidityAdDecoder is a custom TLV decoder for the LiquidityAd record.
+func liquidityAdDecoder(r io.Reader, val interface{}, buf *[8]byte,
+	l uint64) error {
+
+	if v, ok := val.(*LiquidityAd); ok && l == liquidityAdLength {
+		var feeBase, minLeaseAmt, maxLeaseAmt uint64
+		err := tlv.DUint32(r, &v.LeaseDuration, buf, 4)
+		if err != nil {
+			return err
+		}
+		if err := tlv.DUint64(r, &feeBase, buf, 8); err != nil {
+			return err
+		}
+		if err := tlv.DUint32(r, &v.FeeRate, buf, 4); err != nil {
+			return err
+		}
+		if err := tlv.DUint64(r, &minLeaseAmt, buf, 8); err != nil {
+			return err
+		}
+		if err := tlv.DUint64(r, &maxLeaseAmt, buf, 8); err != nil {
+			return err
+		}
+
+		v.FeeBase = btcutil.Amount(feeBase)
+		v.MinLeaseAmt = btcutil.Amount(minLeaseAmt)
+		v.MaxLeaseAmt = btcutil.Amount(maxLeaseAmt)
+
+		return nil
+	}
+
+	return tlv.NewTypeForDecodingErr(
+		val, "lnwire.LiquidityAd", l, liquidityAdLength,
+	)
+}
+
+// ParseLiquidityAd extracts the liquidity ad from the extra data of a node
+// announcement. If the node doesn't advertise any liquidity, nil is returned.
+func ParseLiquidityAd(extraData ExtraOpaqueData) (*LiquidityAd, error) {
+	var ad LiquidityAd
+	typeMap, err := extraData.ExtractRecords(&ad)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := typeMap[LiquidityAdRecordType]; !ok {
+		return nil, nil
+	}
+
+	return &ad, nil
+}
+
+// SetLiquidityAd sets the liquidity ad within the extra data of a node
+// announcement, replacing any prior one, or removes it if the ad is nil. Any
+// other records within the extra data are preserved.
+func SetLiquidityAd(extraData *ExtraOpaqueData, ad *LiquidityAd) error {
+	typeMap, err := extraData.ExtractRecords(&LiquidityAd{})
+	if err != nil {
+		return err
+	}
+
+	// Unknown records are returned along with their raw value, which we
+	// re-encode as is.
+	records := make([]tlv.Record, 0, len(typeMap))
+	for typ, val := range typeMap {
+		if typ == LiquidityAdRecordType || val == nil {
+			continue
+		}
+
+		val := val
+		records = append(records, tlv.MakePrimitiveRecord(typ, &val))
+	}
+	if ad != nil {
+		records = append(records, ad.Record())
+	}
+
+	if len(records) == 0 {
+		*extraData = make([]byte, 0)
+		return nil
+	}
+
+	tlv.SortRecords(records)
+	tlvStream, err := tlv.NewStream(records...)
+	if err != nil {
+		return err
+	}
+
+	var b bytes.Buffer
+	if err := tlvStream.Encode(&b); err != nil {
+		return err
+	}
+	*extraData = b.Bytes()
+
+	return nil
+}
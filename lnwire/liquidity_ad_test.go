@@ -0,0 +1,95 @@
+package lnwire
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/stretchr/testify/require"
+)
+
+// TestLiquidityAdExtraData tests that a liquidity ad can be set in and parsed
+// from the extra data of a node announcement, without affecting any other
+// records.
+func TestLiquidityAdExtraData(t *testing.T) {
+	t.Parallel()
+
+	ad := &LiquidityAd{
+		LeaseDuration: 4032,
+		FeeBase:       1000,
+		FeeRate:       2500,
+		MinLeaseAmt:   100_000,
+		MaxLeaseAmt:   10_000_000,
+	}
+
+	// Without any records, no ad is found.
+	var extraData ExtraOpaqueData
+	parsed, err := ParseLiquidityAd(extraData)
+	require.NoError(t, err)
+	require.Nil(t, parsed)
+
+	// Start out with an unrelated record which should be preserved.
+	leaseExpiry := LeaseExpiry(100)
+	require.NoError(t, extraData.PackRecords(&leaseExpiry))
+	original := append(ExtraOpaqueData(nil), extraData...)
+
+	require.NoError(t, SetLiquidityAd(&extraData, ad))
+	parsed, err = ParseLiquidityAd(extraData)
+	require.NoError(t, err)
+	require.Equal(t, ad, parsed)
+
+	var parsedExpiry LeaseExpiry
+	_, err = extraData.ExtractRecords(&parsedExpiry)
+	require.NoError(t, err)
+	require.Equal(t, leaseExpiry, parsedExpiry)
+
+	// Replacing the ad leaves a single one behind.
+	ad.FeeRate = 5000
+	require.NoError(t, SetLiquidityAd(&extraData, ad))
+	parsed, err = ParseLiquidityAd(extraData)
+	require.NoError(t, err)
+	require.Equal(t, ad, parsed)
+
+	// Removing the ad restores the original extra data.
+	require.NoError(t, SetLiquidityAd(&extraData, nil))
+	require.Equal(t, original, extraData)
+}
+
+// TestLiquidityAdFee tests the fee charged for a lease.
+func TestLiquidityAdFee(t *testing.T) {
+	t.Parallel()
+
+	ad := &LiquidityAd{
+		FeeBase: 1000,
+		FeeRate: 2500,
+	}
+	require.Equal(t, btcutil.Amount(1000+2500), ad.Fee(1_000_000))
+	require.Equal(t, btcutil.Amount(1000), ad.Fee(0))
+}
+
+// TestLiquidityAdValidate tests that invalid liquidity ads are rejected.
+func TestLiquidityAdValidate(t *testing.T) {
+	t.Parallel()
+
+	valid := LiquidityAd{
+		LeaseDuration: 144,
+		MinLeaseAmt:   100_000,
+		MaxLeaseAmt:   1_000_000,
+	}
+	require.NoError(t, valid.Validate())
+
+	noDuration := valid
+	noDuration.LeaseDuration = 0
+	require.ErrorIs(t, noDuration.Validate(), ErrInvalidLiquidityAd)
+
+	negativeFee := valid
+	negativeFee.FeeBase = -1
+	require.ErrorIs(t, negativeFee.Validate(), ErrInvalidLiquidityAd)
+
+	noMax := valid
+	noMax.MaxLeaseAmt = 0
+	require.ErrorIs(t, noMax.Validate(), ErrInvalidLiquidityAd)
+
+	minAboveMax := valid
+	minAboveMax.MinLeaseAmt = 2_000_000
+	require.ErrorIs(t, minAboveMax.Validate(), ErrInvalidLiquidityAd)
+}
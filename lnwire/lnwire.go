@@ -209,6 +209,21 @@ func WriteElement(w *bytes.Buffer, element interface{}) error {
 			return err
 		}
 
+	case PeerStorageBlob:
+		if len(e) > MaxPeerStorageBytes {
+			return ErrPeerStorageBytesExceeded
+		}
+
+		var l [2]byte
+		binary.BigEndian.PutUint16(l[:], uint16(len(e)))
+		if _, err := w.Write(l[:]); err != nil {
+			return err
+		}
+
+		if _, err := w.Write(e[:]); err != nil {
+			return err
+		}
+
 	case WarningData:
 		var l [2]byte
 		binary.BigEndian.PutUint16(l[:], uint16(len(e)))
@@ -634,6 +649,21 @@ func ReadElement(r io.Reader, element interface{}) error {
 			return err
 		}
 
+	case *PeerStorageBlob:
+		var l [2]byte
+		if _, err := io.ReadFull(r, l[:]); err != nil {
+			return err
+		}
+		blobLen := binary.BigEndian.Uint16(l[:])
+		if blobLen > MaxPeerStorageBytes {
+			return ErrPeerStorageBytesExceeded
+		}
+
+		*e = PeerStorageBlob(make([]byte, blobLen))
+		if _, err := io.ReadFull(r, *e); err != nil {
+			return err
+		}
+
 	case *WarningData:
 		var l [2]byte
 		if _, err := io.ReadFull(r, l[:]); err != nil {
@@ -18,6 +18,7 @@ import (
 	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/tlv"
 	"github.com/lightningnetwork/lnd/tor"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -79,6 +80,28 @@ func randDeliveryAddress(r *rand.Rand) (DeliveryAddress, error) {
 	return da, err
 }
 
+// randClosingSigs returns a set of closing signatures where each of the
+// variants is randomly set.
+func randClosingSigs(t *testing.T, r *rand.Rand) ClosingSigs {
+	var sigs ClosingSigs
+	for _, sig := range []**Sig{
+		&sigs.CloserNoClosee, &sigs.NoCloserClosee,
+		&sigs.CloserAndClosee,
+	} {
+		if r.Int31n(2) == 0 {
+			continue
+		}
+
+		s, err := NewSigFromSignature(testSig)
+		if err != nil {
+			t.Fatalf("unable to parse sig: %v", err)
+		}
+		*sig = &s
+	}
+
+	return sigs
+}
+
 func randRawFeatureVector(r *rand.Rand) *RawFeatureVector {
 	featureVec := NewRawFeatureVector()
 	for i := 0; i < 10000; i++ {
@@ -583,6 +606,92 @@ func TestLightningWireProtocol(t *testing.T) {
 
 			v[0] = reflect.ValueOf(*req)
 		},
+		MsgClosingComplete: func(v []reflect.Value, r *rand.Rand) {
+			req := ClosingComplete{
+				FeeSatoshis: btcutil.Amount(r.Int63()),
+				LockTime:    r.Uint32(),
+				ExtraData:   make([]byte, 0),
+			}
+			req.ClosingSigs = randClosingSigs(t, r)
+
+			if _, err := r.Read(req.ChannelID[:]); err != nil {
+				t.Fatalf("unable to generate chan id: %v", err)
+				return
+			}
+
+			var err error
+			req.CloserScript, err = randDeliveryAddress(r)
+			if err != nil {
+				t.Fatalf("unable to generate script: %v", err)
+				return
+			}
+			req.CloseeScript, err = randDeliveryAddress(r)
+			if err != nil {
+				t.Fatalf("unable to generate script: %v", err)
+				return
+			}
+
+			v[0] = reflect.ValueOf(req)
+		},
+		MsgClosingSig: func(v []reflect.Value, r *rand.Rand) {
+			req := ClosingSig{
+				FeeSatoshis: btcutil.Amount(r.Int63()),
+				LockTime:    r.Uint32(),
+				ExtraData:   make([]byte, 0),
+			}
+			req.ClosingSigs = randClosingSigs(t, r)
+
+			if _, err := r.Read(req.ChannelID[:]); err != nil {
+				t.Fatalf("unable to generate chan id: %v", err)
+				return
+			}
+
+			var err error
+			req.CloserScript, err = randDeliveryAddress(r)
+			if err != nil {
+				t.Fatalf("unable to generate script: %v", err)
+				return
+			}
+			req.CloseeScript, err = randDeliveryAddress(r)
+			if err != nil {
+				t.Fatalf("unable to generate script: %v", err)
+				return
+			}
+
+			v[0] = reflect.ValueOf(req)
+		},
+		MsgDynPropose: func(v []reflect.Value, r *rand.Rand) {
+			req := DynPropose{
+				ExtraData: make([]byte, 0),
+			}
+
+			if _, err := r.Read(req.ChanID[:]); err != nil {
+				t.Fatalf("unable to generate chan id: %v", err)
+				return
+			}
+
+			if r.Intn(2) == 0 {
+				dustLimit := btcutil.Amount(r.Int63())
+				req.DustLimit = &dustLimit
+			}
+			if r.Intn(2) == 0 {
+				csvDelay := uint16(r.Int31())
+				req.CsvDelay = &csvDelay
+			}
+			if r.Intn(2) == 0 {
+				maxAcceptedHTLCs := uint16(r.Int31())
+				req.MaxAcceptedHTLCs = &maxAcceptedHTLCs
+			}
+			if r.Intn(2) == 0 {
+				chanType := ChannelType(*NewRawFeatureVector(
+					StaticRemoteKeyRequired,
+					AnchorsZeroFeeHtlcTxRequired,
+				))
+				req.ChannelType = &chanType
+			}
+
+			v[0] = reflect.ValueOf(req)
+		},
 		MsgClosingSigned: func(v []reflect.Value, r *rand.Rand) {
 			req := ClosingSigned{
 				FeeSatoshis: btcutil.Amount(r.Int63()),
@@ -652,6 +761,107 @@ func TestLightningWireProtocol(t *testing.T) {
 
 			v[0] = reflect.ValueOf(*req)
 		},
+		MsgChannelAnnouncement2: func(v []reflect.Value, r *rand.Rand) {
+			var err error
+			req := ChannelAnnouncement2{
+				ShortChannelID: NewShortChanIDFromInt(
+					uint64(r.Int63()),
+				),
+				Capacity:        btcutil.Amount(r.Int63()),
+				Features:        randRawFeatureVector(r),
+				ExtraOpaqueData: make([]byte, 0),
+			}
+			if _, err := r.Read(req.Signature[:]); err != nil {
+				t.Fatalf("unable to generate sig: %v", err)
+				return
+			}
+
+			req.NodeID1, err = randRawKey()
+			if err != nil {
+				t.Fatalf("unable to generate key: %v", err)
+				return
+			}
+			req.NodeID2, err = randRawKey()
+			if err != nil {
+				t.Fatalf("unable to generate key: %v", err)
+				return
+			}
+			if _, err := r.Read(req.ChainHash[:]); err != nil {
+				t.Fatalf("unable to generate chain hash: %v", err)
+				return
+			}
+
+			// The bitcoin keys and the merkle root are optional.
+			if r.Intn(2) == 0 {
+				key1, err := randRawKey()
+				if err != nil {
+					t.Fatalf("unable to generate key: %v", err)
+					return
+				}
+				key2, err := randRawKey()
+				if err != nil {
+					t.Fatalf("unable to generate key: %v", err)
+					return
+				}
+				req.BitcoinKey1 = &key1
+				req.BitcoinKey2 = &key2
+			}
+			if r.Intn(2) == 0 {
+				var merkleRoot [32]byte
+				if _, err := r.Read(merkleRoot[:]); err != nil {
+					t.Fatalf("unable to generate root: %v", err)
+					return
+				}
+				req.MerkleRootHash = &merkleRoot
+			}
+
+			// Add an unknown odd record, which must be retained.
+			if r.Intn(2) == 0 {
+				extra := []byte{0x01, 0x02, 0x03}
+				err := req.ExtraOpaqueData.PackRecords(&tlvRecord{
+					tlv.MakePrimitiveRecord(101, &extra),
+				})
+				if err != nil {
+					t.Fatalf("unable to pack records: %v", err)
+					return
+				}
+			}
+
+			v[0] = reflect.ValueOf(req)
+		},
+		MsgReconcileSketch: func(v []reflect.Value, r *rand.Rand) {
+			req := ReconcileSketch{
+				Sketch:    make([]byte, r.Intn(1000)),
+				ExtraData: make([]byte, 0),
+			}
+			if _, err := r.Read(req.ChainHash[:]); err != nil {
+				t.Fatalf("unable to read chain hash: %v", err)
+				return
+			}
+			if _, err := r.Read(req.Sketch); err != nil {
+				t.Fatalf("unable to generate sketch: %v", err)
+				return
+			}
+
+			v[0] = reflect.ValueOf(req)
+		},
+		MsgReconcileDiff: func(v []reflect.Value, r *rand.Rand) {
+			req := ReconcileDiff{
+				Complete:  uint8(r.Intn(2)),
+				ExtraData: make([]byte, 0),
+			}
+			if _, err := r.Read(req.ChainHash[:]); err != nil {
+				t.Fatalf("unable to read chain hash: %v", err)
+				return
+			}
+
+			numElements := r.Intn(100)
+			for i := 0; i < numElements; i++ {
+				req.Elements = append(req.Elements, r.Uint64())
+			}
+
+			v[0] = reflect.ValueOf(req)
+		},
 		MsgChannelAnnouncement: func(v []reflect.Value, r *rand.Rand) {
 			var err error
 			req := ChannelAnnouncement{
@@ -969,6 +1179,12 @@ func TestLightningWireProtocol(t *testing.T) {
 				return mainScenario(&m)
 			},
 		},
+		{
+			msgType: MsgStfu,
+			scenario: func(m Stfu) bool {
+				return mainScenario(&m)
+			},
+		},
 		{
 			msgType: MsgError,
 			scenario: func(m Error) bool {
@@ -987,6 +1203,18 @@ func TestLightningWireProtocol(t *testing.T) {
 				return mainScenario(&m)
 			},
 		},
+		{
+			msgType: MsgPeerStorage,
+			scenario: func(m PeerStorage) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgYourPeerStorage,
+			scenario: func(m YourPeerStorage) bool {
+				return mainScenario(&m)
+			},
+		},
 		{
 			msgType: MsgOpenChannel,
 			scenario: func(m OpenChannel) bool {
@@ -1029,6 +1257,48 @@ func TestLightningWireProtocol(t *testing.T) {
 				return mainScenario(&m)
 			},
 		},
+		{
+			msgType: MsgClosingComplete,
+			scenario: func(m ClosingComplete) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgClosingSig,
+			scenario: func(m ClosingSig) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgLeaseRequest,
+			scenario: func(m LeaseRequest) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgLeaseOffer,
+			scenario: func(m LeaseOffer) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgDynPropose,
+			scenario: func(m DynPropose) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgDynAck,
+			scenario: func(m DynAck) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgDynReject,
+			scenario: func(m DynReject) bool {
+				return mainScenario(&m)
+			},
+		},
 		{
 			msgType: MsgUpdateAddHTLC,
 			scenario: func(m UpdateAddHTLC) bool {
@@ -1084,6 +1354,12 @@ func TestLightningWireProtocol(t *testing.T) {
 				return mainScenario(&m)
 			},
 		},
+		{
+			msgType: MsgChannelAnnouncement2,
+			scenario: func(m ChannelAnnouncement2) bool {
+				return mainScenario(&m)
+			},
+		},
 		{
 			msgType: MsgNodeAnnouncement,
 			scenario: func(m NodeAnnouncement) bool {
@@ -1132,6 +1408,18 @@ func TestLightningWireProtocol(t *testing.T) {
 				return mainScenario(&m)
 			},
 		},
+		{
+			msgType: MsgReconcileSketch,
+			scenario: func(m ReconcileSketch) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgReconcileDiff,
+			scenario: func(m ReconcileDiff) bool {
+				return mainScenario(&m)
+			},
+		},
 	}
 	for _, test := range tests {
 		var config *quick.Config
@@ -23,6 +23,9 @@ type MessageType uint16
 // Lightning protocol.
 const (
 	MsgWarning                 MessageType = 1
+	MsgStfu                                = 2
+	MsgPeerStorage                         = 7
+	MsgYourPeerStorage                     = 9
 	MsgInit                                = 16
 	MsgError                               = 17
 	MsgPing                                = 18
@@ -34,6 +37,13 @@ const (
 	MsgChannelReady                        = 36
 	MsgShutdown                            = 38
 	MsgClosingSigned                       = 39
+	MsgClosingComplete                     = 40
+	MsgClosingSig                          = 41
+	MsgLeaseRequest                        = 53
+	MsgLeaseOffer                          = 55
+	MsgDynPropose                          = 111
+	MsgDynAck                              = 113
+	MsgDynReject                           = 115
 	MsgUpdateAddHTLC                       = 128
 	MsgUpdateFulfillHTLC                   = 130
 	MsgUpdateFailHTLC                      = 131
@@ -51,6 +61,9 @@ const (
 	MsgQueryChannelRange                   = 263
 	MsgReplyChannelRange                   = 264
 	MsgGossipTimestampRange                = 265
+	MsgChannelAnnouncement2                = 267
+	MsgReconcileSketch                     = 269
+	MsgReconcileDiff                       = 271
 )
 
 // ErrorEncodeMessage is used when failed to encode the message payload.
@@ -78,6 +91,12 @@ func (t MessageType) String() string {
 	switch t {
 	case MsgWarning:
 		return "Warning"
+	case MsgStfu:
+		return "Stfu"
+	case MsgPeerStorage:
+		return "PeerStorage"
+	case MsgYourPeerStorage:
+		return "YourPeerStorage"
 	case MsgInit:
 		return "Init"
 	case MsgOpenChannel:
@@ -94,6 +113,20 @@ func (t MessageType) String() string {
 		return "Shutdown"
 	case MsgClosingSigned:
 		return "ClosingSigned"
+	case MsgClosingComplete:
+		return "ClosingComplete"
+	case MsgClosingSig:
+		return "ClosingSig"
+	case MsgLeaseRequest:
+		return "LeaseRequest"
+	case MsgLeaseOffer:
+		return "LeaseOffer"
+	case MsgDynPropose:
+		return "DynPropose"
+	case MsgDynAck:
+		return "DynAck"
+	case MsgDynReject:
+		return "DynReject"
 	case MsgUpdateAddHTLC:
 		return "UpdateAddHTLC"
 	case MsgUpdateFailHTLC:
@@ -112,6 +145,12 @@ func (t MessageType) String() string {
 		return "Error"
 	case MsgChannelAnnouncement:
 		return "ChannelAnnouncement"
+	case MsgChannelAnnouncement2:
+		return "ChannelAnnouncement2"
+	case MsgReconcileSketch:
+		return "ReconcileSketch"
+	case MsgReconcileDiff:
+		return "ReconcileDiff"
 	case MsgChannelUpdate:
 		return "ChannelUpdate"
 	case MsgNodeAnnouncement:
@@ -180,6 +219,12 @@ func makeEmptyMessage(msgType MessageType) (Message, error) {
 	switch msgType {
 	case MsgWarning:
 		msg = &Warning{}
+	case MsgStfu:
+		msg = &Stfu{}
+	case MsgPeerStorage:
+		msg = &PeerStorage{}
+	case MsgYourPeerStorage:
+		msg = &YourPeerStorage{}
 	case MsgInit:
 		msg = &Init{}
 	case MsgOpenChannel:
@@ -196,6 +241,20 @@ func makeEmptyMessage(msgType MessageType) (Message, error) {
 		msg = &Shutdown{}
 	case MsgClosingSigned:
 		msg = &ClosingSigned{}
+	case MsgClosingComplete:
+		msg = &ClosingComplete{}
+	case MsgClosingSig:
+		msg = &ClosingSig{}
+	case MsgLeaseRequest:
+		msg = &LeaseRequest{}
+	case MsgLeaseOffer:
+		msg = &LeaseOffer{}
+	case MsgDynPropose:
+		msg = &DynPropose{}
+	case MsgDynAck:
+		msg = &DynAck{}
+	case MsgDynReject:
+		msg = &DynReject{}
 	case MsgUpdateAddHTLC:
 		msg = &UpdateAddHTLC{}
 	case MsgUpdateFailHTLC:
@@ -216,6 +275,12 @@ func makeEmptyMessage(msgType MessageType) (Message, error) {
 		msg = &Error{}
 	case MsgChannelAnnouncement:
 		msg = &ChannelAnnouncement{}
+	case MsgChannelAnnouncement2:
+		msg = &ChannelAnnouncement2{}
+	case MsgReconcileSketch:
+		msg = &ReconcileSketch{}
+	case MsgReconcileDiff:
+		msg = &ReconcileDiff{}
 	case MsgChannelUpdate:
 		msg = &ChannelUpdate{}
 	case MsgNodeAnnouncement:
@@ -255,6 +320,20 @@ func makeEmptyMessage(msgType MessageType) (Message, error) {
 	return msg, nil
 }
 
+// IsKnownMessage returns true if the message type is one of the protocol
+// messages we know how to decode. Custom messages, including protocol messages
+// that are overridden for custom use, aren't known.
+func IsKnownMessage(msgType MessageType) bool {
+	msg, err := makeEmptyMessage(msgType)
+	if err != nil {
+		return false
+	}
+
+	_, isCustom := msg.(*Custom)
+
+	return !isCustom
+}
+
 // WriteMessage writes a lightning Message to a buffer including the necessary
 // header information and returns the number of bytes written. If any error is
 // encountered, the buffer passed will be reset to its original state since we
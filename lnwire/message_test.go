@@ -260,10 +260,13 @@ func makeAllMessages(t testing.TB, r *rand.Rand) []lnwire.Message {
 	msgAll := []lnwire.Message{}
 
 	msgAll = append(msgAll, newMsgWarning(t, r))
+	msgAll = append(msgAll, newMsgStfu(t, r))
 	msgAll = append(msgAll, newMsgInit(t, r))
 	msgAll = append(msgAll, newMsgError(t, r))
 	msgAll = append(msgAll, newMsgPing(t, r))
 	msgAll = append(msgAll, newMsgPong(t, r))
+	msgAll = append(msgAll, newMsgPeerStorage(t, r))
+	msgAll = append(msgAll, newMsgYourPeerStorage(t, r))
 	msgAll = append(msgAll, newMsgOpenChannel(t, r))
 	msgAll = append(msgAll, newMsgAcceptChannel(t, r))
 	msgAll = append(msgAll, newMsgFundingCreated(t, r))
@@ -271,6 +274,13 @@ func makeAllMessages(t testing.TB, r *rand.Rand) []lnwire.Message {
 	msgAll = append(msgAll, newMsgChannelReady(t, r))
 	msgAll = append(msgAll, newMsgShutdown(t, r))
 	msgAll = append(msgAll, newMsgClosingSigned(t, r))
+	msgAll = append(msgAll, newMsgClosingComplete(t, r))
+	msgAll = append(msgAll, newMsgClosingSig(t, r))
+	msgAll = append(msgAll, newMsgLeaseRequest(t, r))
+	msgAll = append(msgAll, newMsgLeaseOffer(t, r))
+	msgAll = append(msgAll, newMsgDynPropose(t, r))
+	msgAll = append(msgAll, newMsgDynAck(t, r))
+	msgAll = append(msgAll, newMsgDynReject(t, r))
 	msgAll = append(msgAll, newMsgUpdateAddHTLC(t, r))
 	msgAll = append(msgAll, newMsgUpdateFulfillHTLC(t, r))
 	msgAll = append(msgAll, newMsgUpdateFailHTLC(t, r))
@@ -280,6 +290,7 @@ func makeAllMessages(t testing.TB, r *rand.Rand) []lnwire.Message {
 	msgAll = append(msgAll, newMsgUpdateFailMalformedHTLC(t, r))
 	msgAll = append(msgAll, newMsgChannelReestablish(t, r))
 	msgAll = append(msgAll, newMsgChannelAnnouncement(t, r))
+	msgAll = append(msgAll, newMsgChannelAnnouncement2(t, r))
 	msgAll = append(msgAll, newMsgNodeAnnouncement(t, r))
 	msgAll = append(msgAll, newMsgChannelUpdate(t, r))
 	msgAll = append(msgAll, newMsgAnnounceSignatures(t, r))
@@ -290,6 +301,8 @@ func makeAllMessages(t testing.TB, r *rand.Rand) []lnwire.Message {
 	msgAll = append(msgAll, newMsgGossipTimestampRange(t, r))
 	msgAll = append(msgAll, newMsgQueryShortChanIDsZlib(t, r))
 	msgAll = append(msgAll, newMsgReplyChannelRangeZlib(t, r))
+	msgAll = append(msgAll, newMsgReconcileSketch(t, r))
+	msgAll = append(msgAll, newMsgReconcileDiff(t, r))
 
 	return msgAll
 }
@@ -307,6 +320,20 @@ func newMsgWarning(tb testing.TB, r io.Reader) *lnwire.Warning {
 	return msg
 }
 
+func newMsgStfu(t testing.TB, r *rand.Rand) *lnwire.Stfu {
+	t.Helper()
+
+	msg := &lnwire.Stfu{
+		Initiator: r.Intn(2) == 0,
+		ExtraData: createExtraData(t, r),
+	}
+
+	_, err := r.Read(msg.ChanID[:])
+	require.NoError(t, err, "unable to generate chan id")
+
+	return msg
+}
+
 func newMsgInit(t testing.TB, r io.Reader) *lnwire.Init {
 	t.Helper()
 
@@ -406,6 +433,28 @@ func newMsgPong(t testing.TB, r io.Reader) *lnwire.Pong {
 	}
 }
 
+func newMsgPeerStorage(t testing.TB, r *rand.Rand) *lnwire.PeerStorage {
+	t.Helper()
+
+	blob := make([]byte, r.Intn(1000))
+	_, err := r.Read(blob)
+	require.NoError(t, err, "unable to generate blob")
+
+	return lnwire.NewPeerStorage(blob)
+}
+
+func newMsgYourPeerStorage(t testing.TB,
+	r *rand.Rand) *lnwire.YourPeerStorage {
+
+	t.Helper()
+
+	blob := make([]byte, r.Intn(1000))
+	_, err := r.Read(blob)
+	require.NoError(t, err, "unable to generate blob")
+
+	return lnwire.NewYourPeerStorage(blob)
+}
+
 func newMsgFundingCreated(t testing.TB, r *rand.Rand) *lnwire.FundingCreated {
 	t.Helper()
 
@@ -499,6 +548,140 @@ func newMsgClosingSigned(t testing.TB, r *rand.Rand) *lnwire.ClosingSigned {
 	return msg
 }
 
+func newMsgClosingComplete(t testing.TB,
+	r *rand.Rand) *lnwire.ClosingComplete {
+
+	t.Helper()
+
+	sig := testNodeSig
+	msg := &lnwire.ClosingComplete{
+		CloserScript: randDeliveryAddress(t, r),
+		CloseeScript: randDeliveryAddress(t, r),
+		FeeSatoshis:  btcutil.Amount(r.Int63()),
+		LockTime:     r.Uint32(),
+		ClosingSigs: lnwire.ClosingSigs{
+			CloserAndClosee: &sig,
+		},
+		ExtraData: make([]byte, 0),
+	}
+
+	_, err := r.Read(msg.ChannelID[:])
+	require.NoError(t, err, "unable to generate chan id")
+
+	return msg
+}
+
+func newMsgClosingSig(t testing.TB, r *rand.Rand) *lnwire.ClosingSig {
+	t.Helper()
+
+	sig := testNodeSig
+	msg := &lnwire.ClosingSig{
+		CloserScript: randDeliveryAddress(t, r),
+		CloseeScript: randDeliveryAddress(t, r),
+		FeeSatoshis:  btcutil.Amount(r.Int63()),
+		LockTime:     r.Uint32(),
+		ClosingSigs: lnwire.ClosingSigs{
+			CloserAndClosee: &sig,
+		},
+		ExtraData: make([]byte, 0),
+	}
+
+	_, err := r.Read(msg.ChannelID[:])
+	require.NoError(t, err, "unable to generate chan id")
+
+	return msg
+}
+
+func newMsgLeaseRequest(t testing.TB, r *rand.Rand) *lnwire.LeaseRequest {
+	t.Helper()
+
+	msg := &lnwire.LeaseRequest{
+		LeaseAmt:      btcutil.Amount(r.Int63()),
+		LeaseDuration: r.Uint32(),
+		MaxFee:        btcutil.Amount(r.Int63()),
+		ExtraData:     createExtraData(t, r),
+	}
+
+	_, err := r.Read(msg.LeaseID[:])
+	require.NoError(t, err, "unable to generate lease id")
+
+	_, err = r.Read(msg.ChainHash[:])
+	require.NoError(t, err, "unable to generate chain hash")
+
+	return msg
+}
+
+func newMsgLeaseOffer(t testing.TB, r *rand.Rand) *lnwire.LeaseOffer {
+	t.Helper()
+
+	msg := &lnwire.LeaseOffer{
+		Fee:            btcutil.Amount(r.Int63()),
+		LeaseExpiry:    r.Uint32(),
+		PaymentRequest: make([]byte, r.Intn(500)),
+		ExtraData:      createExtraData(t, r),
+	}
+
+	_, err := r.Read(msg.LeaseID[:])
+	require.NoError(t, err, "unable to generate lease id")
+
+	_, err = r.Read(msg.PaymentRequest)
+	require.NoError(t, err, "unable to generate payment request")
+
+	return msg
+}
+
+func newMsgDynPropose(t testing.TB, r *rand.Rand) *lnwire.DynPropose {
+	t.Helper()
+
+	dustLimit := btcutil.Amount(r.Int63())
+	csvDelay := uint16(r.Int31())
+	maxAcceptedHTLCs := uint16(r.Int31())
+	chanType := lnwire.ChannelType(*lnwire.NewRawFeatureVector(
+		lnwire.StaticRemoteKeyRequired,
+		lnwire.AnchorsZeroFeeHtlcTxRequired,
+	))
+
+	msg := &lnwire.DynPropose{
+		DustLimit:        &dustLimit,
+		CsvDelay:         &csvDelay,
+		MaxAcceptedHTLCs: &maxAcceptedHTLCs,
+		ChannelType:      &chanType,
+		ExtraData:        make([]byte, 0),
+	}
+
+	_, err := r.Read(msg.ChanID[:])
+	require.NoError(t, err, "unable to generate chan id")
+
+	return msg
+}
+
+func newMsgDynAck(t testing.TB, r *rand.Rand) *lnwire.DynAck {
+	t.Helper()
+
+	msg := &lnwire.DynAck{
+		ExtraData: createExtraData(t, r),
+	}
+
+	_, err := r.Read(msg.ChanID[:])
+	require.NoError(t, err, "unable to generate chan id")
+
+	return msg
+}
+
+func newMsgDynReject(t testing.TB, r *rand.Rand) *lnwire.DynReject {
+	t.Helper()
+
+	msg := &lnwire.DynReject{
+		Reason:    createExtraData(t, r),
+		ExtraData: createExtraData(t, r),
+	}
+
+	_, err := r.Read(msg.ChanID[:])
+	require.NoError(t, err, "unable to generate chan id")
+
+	return msg
+}
+
 func newMsgUpdateAddHTLC(t testing.TB, r *rand.Rand) *lnwire.UpdateAddHTLC {
 	t.Helper()
 
@@ -669,6 +852,31 @@ func newMsgChannelAnnouncement(t testing.TB,
 	return msg
 }
 
+func newMsgChannelAnnouncement2(t testing.TB,
+	r *rand.Rand) *lnwire.ChannelAnnouncement2 {
+
+	t.Helper()
+
+	bitcoinKey1 := randRawKey(t)
+	bitcoinKey2 := randRawKey(t)
+	msg := &lnwire.ChannelAnnouncement2{
+		ShortChannelID:  lnwire.NewShortChanIDFromInt(uint64(r.Int63())),
+		Capacity:        btcutil.Amount(r.Int63()),
+		Features:        rawFeatureVector(),
+		NodeID1:         randRawKey(t),
+		NodeID2:         randRawKey(t),
+		BitcoinKey1:     &bitcoinKey1,
+		BitcoinKey2:     &bitcoinKey2,
+		ExtraOpaqueData: make([]byte, 0),
+		Signature:       testNodeSig,
+	}
+
+	_, err := r.Read(msg.ChainHash[:])
+	require.NoError(t, err, "unable to generate chain hash")
+
+	return msg
+}
+
 func newMsgNodeAnnouncement(t testing.TB,
 	r *rand.Rand) *lnwire.NodeAnnouncement {
 
@@ -804,6 +1012,47 @@ func newMsgReplyShortChanIDsEnd(t testing.TB,
 	return msg
 }
 
+func newMsgReconcileSketch(t testing.TB,
+	r *rand.Rand) *lnwire.ReconcileSketch {
+
+	t.Helper()
+
+	sketch := make([]byte, 20*(1+r.Intn(3000)))
+	_, err := r.Read(sketch)
+	require.NoError(t, err, "unable to generate sketch")
+
+	msg := &lnwire.ReconcileSketch{
+		Sketch: sketch,
+	}
+
+	_, err = r.Read(msg.ChainHash[:])
+	require.NoError(t, err, "unable to read chain hash")
+
+	msg.ExtraData = createExtraData(t, r)
+
+	return msg
+}
+
+func newMsgReconcileDiff(t testing.TB, r *rand.Rand) *lnwire.ReconcileDiff {
+	t.Helper()
+
+	msg := lnwire.NewReconcileDiff()
+
+	_, err := r.Read(msg.ChainHash[:])
+	require.NoError(t, err, "unable to read chain hash")
+
+	msg.Complete = uint8(r.Int31n(2))
+
+	numElements := r.Intn(1000)
+	for i := 0; i < numElements; i++ {
+		msg.Elements = append(msg.Elements, r.Uint64())
+	}
+
+	msg.ExtraData = createExtraData(t, r)
+
+	return msg
+}
+
 func newMsgQueryChannelRange(t testing.TB,
 	r *rand.Rand) *lnwire.QueryChannelRange {
 
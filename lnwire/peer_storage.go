@@ -0,0 +1,110 @@
+package lnwire
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+)
+
+// MaxPeerStorageBytes is the maximum size of a peer storage blob. The type of
+// the message takes 2 bytes and the length field takes up another 2 bytes,
+// leaving 65531 bytes for the blob itself.
+const MaxPeerStorageBytes = 65531
+
+// ErrPeerStorageBytesExceeded indicates that a peer storage blob exceeds
+// MaxPeerStorageBytes.
+var ErrPeerStorageBytesExceeded = fmt.Errorf("peer storage bytes exceeded")
+
+// PeerStorageBlob is an opaque, length prefixed blob of data that a node asks
+// its peer to store on its behalf. The storing node cannot interpret the
+// contents of the blob, it's expected to be encrypted by the sender.
+type PeerStorageBlob []byte
+
+// PeerStorage is sent by a node to ask the remote peer to store the attached
+// blob on its behalf. The latest blob received replaces any prior one, and
+// is handed back to the sender via a YourPeerStorage message whenever the
+// two nodes reconnect.
+type PeerStorage struct {
+	// Blob is the opaque data the remote peer should store for us.
+	Blob PeerStorageBlob
+}
+
+// NewPeerStorage creates a new PeerStorage message carrying the given blob.
+func NewPeerStorage(blob []byte) *PeerStorage {
+	return &PeerStorage{
+		Blob: blob,
+	}
+}
+
+// A compile time check to ensure PeerStorage implements the lnwire.Message
+// interface.
+var _ Message = (*PeerStorage)(nil)
+
+// Decode deserializes a serialized PeerStorage message stored in the passed
+// io.Reader observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (p *PeerStorage) Decode(r io.Reader, pver uint32) error {
+	return ReadElements(r, &p.Blob)
+}
+
+// Encode serializes the target PeerStorage into the passed io.Writer
+// observing the protocol version specified.
+//
+// This is part of the lnwire.Message interface.
+func (p *PeerStorage) Encode(w *bytes.Buffer, pver uint32) error {
+	return WritePeerStorageBlob(w, p.Blob)
+}
+
+// MsgType returns the integer uniquely identifying this message type on the
+// wire.
+//
+// This is part of the lnwire.Message interface.
+func (p *PeerStorage) MsgType() MessageType {
+	return MsgPeerStorage
+}
+
+// YourPeerStorage is sent by a node that stores data on behalf of its peer
+// upon reconnection. It hands back the latest blob the peer asked us to store
+// through a PeerStorage message.
+type YourPeerStorage struct {
+	// Blob is the opaque data the remote peer previously asked us to
+	// store.
+	Blob PeerStorageBlob
+}
+
+// NewYourPeerStorage creates a new YourPeerStorage message carrying the given
+// blob.
+func NewYourPeerStorage(blob []byte) *YourPeerStorage {
+	return &YourPeerStorage{
+		Blob: blob,
+	}
+}
+
+// A compile time check to ensure YourPeerStorage implements the lnwire.Message
+// interface.
+var _ Message = (*YourPeerStorage)(nil)
+
+// Decode deserializes a serialized YourPeerStorage message stored in the
+// passed io.Reader observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (y *YourPeerStorage) Decode(r io.Reader, pver uint32) error {
+	return ReadElements(r, &y.Blob)
+}
+
+// Encode serializes the target YourPeerStorage into the passed io.Writer
+// observing the protocol version specified.
+//
+// This is part of the lnwire.Message interface.
+func (y *YourPeerStorage) Encode(w *bytes.Buffer, pver uint32) error {
+	return WritePeerStorageBlob(w, y.Blob)
+}
+
+// MsgType returns the integer uniquely identifying this message type on the
+// wire.
+//
+// This is part of the lnwire.Message interface.
+func (y *YourPeerStorage) MsgType() MessageType {
+	return MsgYourPeerStorage
+}
@@ -0,0 +1,116 @@
+package lnwire
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+)
+
+// MaxReconcileDiffElements is the maximum number of elements a ReconcileDiff
+// message can carry. The chain hash takes 32 bytes, the complete flag 1 byte
+// and the number of elements another 2 bytes of the maximum message body.
+const MaxReconcileDiffElements = (MaxMsgBody - 32 - 1 - 2) / 8
+
+// ReconcileDiff is sent in response to a ReconcileSketch message, after the
+// channel updates the sender of the sketch is missing have been sent. It marks
+// the end of the reconciliation, and lists the elements of the set of the
+// sketch sender that the responder is missing in turn.
+type ReconcileDiff struct {
+	// ChainHash denotes the target chain of the reconciliation.
+	ChainHash chainhash.Hash
+
+	// Complete is set to 1 if the responder was able to decode the
+	// difference between both sets. It's set to 0 if the responder doesn't
+	// know of the chain, or the difference was too large to be recovered
+	// from the sketch, in which case the sender should fall back to
+	// synchronizing through channel range queries.
+	Complete uint8
+
+	// Elements is the set of elements of the sketch sender's set that the
+	// responder either doesn't know of, or only knows an older version of.
+	Elements []uint64
+
+	// ExtraData is the set of data that was appended to this message to
+	// fill out the full maximum transport message size. These fields can
+	// be used to specify optional data such as custom TLV fields.
+	ExtraData ExtraOpaqueData
+}
+
+// NewReconcileDiff creates a new empty ReconcileDiff message.
+func NewReconcileDiff() *ReconcileDiff {
+	return &ReconcileDiff{}
+}
+
+// A compile time check to ensure ReconcileDiff implements the lnwire.Message
+// interface.
+var _ Message = (*ReconcileDiff)(nil)
+
+// Decode deserializes a serialized ReconcileDiff message stored in the passed
+// io.Reader observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (r *ReconcileDiff) Decode(reader io.Reader, pver uint32) error {
+	var numElements uint16
+	err := ReadElements(reader, r.ChainHash[:], &r.Complete, &numElements)
+	if err != nil {
+		return err
+	}
+
+	if numElements > MaxReconcileDiffElements {
+		return fmt.Errorf("%v elements exceed maximum of %v",
+			numElements, MaxReconcileDiffElements)
+	}
+
+	r.Elements = nil
+	if numElements > 0 {
+		r.Elements = make([]uint64, numElements)
+	}
+	for i := range r.Elements {
+		if err := ReadElement(reader, &r.Elements[i]); err != nil {
+			return err
+		}
+	}
+
+	return ReadElement(reader, &r.ExtraData)
+}
+
+// Encode serializes the target ReconcileDiff into the passed io.Writer
+// observing the protocol version specified.
+//
+// This is part of the lnwire.Message interface.
+func (r *ReconcileDiff) Encode(w *bytes.Buffer, pver uint32) error {
+	if len(r.Elements) > MaxReconcileDiffElements {
+		return fmt.Errorf("%v elements exceed maximum of %v",
+			len(r.Elements), MaxReconcileDiffElements)
+	}
+
+	if err := WriteBytes(w, r.ChainHash[:]); err != nil {
+		return err
+	}
+
+	if err := WriteUint8(w, r.Complete); err != nil {
+		return err
+	}
+
+	if err := WriteUint16(w, uint16(len(r.Elements))); err != nil {
+		return err
+	}
+
+	for _, element := range r.Elements {
+		if err := WriteUint64(w, element); err != nil {
+			return err
+		}
+	}
+
+	return WriteBytes(w, r.ExtraData)
+}
+
+// MsgType returns the integer uniquely identifying this message type on the
+// wire.
+//
+// This is part of the lnwire.Message interface.
+func (r *ReconcileDiff) MsgType() MessageType {
+	return MsgReconcileDiff
+}
@@ -0,0 +1,110 @@
+package lnwire
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+)
+
+// MaxReconcileSketchBytes is the maximum size of the sketch carried by a
+// ReconcileSketch message. The chain hash takes 32 bytes and the length of the
+// sketch another 2 bytes of the maximum message body.
+const MaxReconcileSketchBytes = MaxMsgBody - 32 - 2
+
+// ReconcileSketch is sent by a node that wants to synchronize its view of the
+// channel updates of the network with the remote peer using set
+// reconciliation. Instead of exchanging the full set of known short channel
+// IDs, the sender transmits a fixed size sketch of the set of channel updates
+// it knows of. The receiver subtracts its own sketch from it to recover the
+// symmetric difference of both sets, and responds with the channel updates
+// the sender is missing followed by a ReconcileDiff message.
+type ReconcileSketch struct {
+	// ChainHash denotes the target chain that the sketch covers.
+	ChainHash chainhash.Hash
+
+	// Sketch is the serialized sketch of the set of channel updates known
+	// to the sender.
+	Sketch []byte
+
+	// ExtraData is the set of data that was appended to this message to
+	// fill out the full maximum transport message size. These fields can
+	// be used to specify optional data such as custom TLV fields.
+	ExtraData ExtraOpaqueData
+}
+
+// NewReconcileSketch creates a new ReconcileSketch message.
+func NewReconcileSketch(chainHash chainhash.Hash,
+	sketch []byte) *ReconcileSketch {
+
+	return &ReconcileSketch{
+		ChainHash: chainHash,
+		Sketch:    sketch,
+	}
+}
+
+// A compile time check to ensure ReconcileSketch implements the
+// lnwire.Message interface.
+var _ Message = (*ReconcileSketch)(nil)
+
+// Decode deserializes a serialized ReconcileSketch message stored in the
+// passed io.Reader observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (r *ReconcileSketch) Decode(reader io.Reader, pver uint32) error {
+	if err := ReadElement(reader, r.ChainHash[:]); err != nil {
+		return err
+	}
+
+	var l [2]byte
+	if _, err := io.ReadFull(reader, l[:]); err != nil {
+		return err
+	}
+	sketchLen := binary.BigEndian.Uint16(l[:])
+	if sketchLen > MaxReconcileSketchBytes {
+		return fmt.Errorf("sketch of %v bytes exceeds maximum of %v",
+			sketchLen, MaxReconcileSketchBytes)
+	}
+
+	r.Sketch = make([]byte, sketchLen)
+	if _, err := io.ReadFull(reader, r.Sketch); err != nil {
+		return err
+	}
+
+	return ReadElement(reader, &r.ExtraData)
+}
+
+// Encode serializes the target ReconcileSketch into the passed io.Writer
+// observing the protocol version specified.
+//
+// This is part of the lnwire.Message interface.
+func (r *ReconcileSketch) Encode(w *bytes.Buffer, pver uint32) error {
+	if len(r.Sketch) > MaxReconcileSketchBytes {
+		return fmt.Errorf("sketch of %v bytes exceeds maximum of %v",
+			len(r.Sketch), MaxReconcileSketchBytes)
+	}
+
+	if err := WriteBytes(w, r.ChainHash[:]); err != nil {
+		return err
+	}
+
+	if err := WriteUint16(w, uint16(len(r.Sketch))); err != nil {
+		return err
+	}
+
+	if err := WriteBytes(w, r.Sketch); err != nil {
+		return err
+	}
+
+	return WriteBytes(w, r.ExtraData)
+}
+
+// MsgType returns the integer uniquely identifying this message type on the
+// wire.
+//
+// This is part of the lnwire.Message interface.
+func (r *ReconcileSketch) MsgType() MessageType {
+	return MsgReconcileSketch
+}
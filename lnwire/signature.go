@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
 	"github.com/lightningnetwork/lnd/input"
 )
 
@@ -170,3 +171,22 @@ func extractCanonicalPadding(b []byte) []byte {
 	}
 	return []byte{0x00}
 }
+
+// NewSigFromSchnorrRawSignature creates a new signature as used on the wire
+// from the 64-byte serialization of a BIP-0340 Schnorr signature.
+func NewSigFromSchnorrRawSignature(sig []byte) (Sig, error) {
+	var b Sig
+	if len(sig) != schnorr.SignatureSize {
+		return b, errBadLength
+	}
+
+	copy(b[:], sig)
+
+	return b, nil
+}
+
+// ToSchnorrSignature interprets the fixed-sized signature as a BIP-0340
+// Schnorr signature, which can be used for signature validation checks.
+func (b *Sig) ToSchnorrSignature() (*schnorr.Signature, error) {
+	return schnorr.ParseSignature(b[:])
+}
@@ -0,0 +1,75 @@
+package lnwire
+
+import (
+	"bytes"
+	"io"
+)
+
+// Stfu is sent to request that a channel be made quiescent, or to reply to
+// such a request. Once both sides have sent it, neither side sends any more
+// updates for the channel, which allows protocols that need a stable channel
+// state, such as splicing or commitment upgrades, to take place.
+type Stfu struct {
+	// ChanID identifies the channel that should be made quiescent.
+	ChanID ChannelID
+
+	// Initiator is true if the sender requested quiescence, and false if
+	// it's replying to a request of the receiver.
+	Initiator bool
+
+	// ExtraData is the set of data that was appended to this message to
+	// fill out the full maximum transport message size. These fields can
+	// be used to specify optional data such as custom TLV fields.
+	ExtraData ExtraOpaqueData
+}
+
+// NewStfu creates a new Stfu message for the given channel.
+func NewStfu(chanID ChannelID, initiator bool) *Stfu {
+	return &Stfu{
+		ChanID:    chanID,
+		Initiator: initiator,
+	}
+}
+
+// A compile time check to ensure Stfu implements the lnwire.Message interface.
+var _ Message = (*Stfu)(nil)
+
+// Decode deserializes a serialized Stfu message stored in the passed
+// io.Reader observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (s *Stfu) Decode(r io.Reader, pver uint32) error {
+	return ReadElements(r, &s.ChanID, &s.Initiator, &s.ExtraData)
+}
+
+// Encode serializes the target Stfu into the passed io.Writer observing the
+// protocol version specified.
+//
+// This is part of the lnwire.Message interface.
+func (s *Stfu) Encode(w *bytes.Buffer, pver uint32) error {
+	if err := WriteChannelID(w, s.ChanID); err != nil {
+		return err
+	}
+
+	if err := WriteBool(w, s.Initiator); err != nil {
+		return err
+	}
+
+	return WriteBytes(w, s.ExtraData)
+}
+
+// TargetChanID returns the channel id of the link for which this message is
+// intended.
+//
+// NOTE: Part of peer.LinkUpdater interface.
+func (s *Stfu) TargetChanID() ChannelID {
+	return s.ChanID
+}
+
+// MsgType returns the integer uniquely identifying this message type on the
+// wire.
+//
+// This is part of the lnwire.Message interface.
+func (s *Stfu) MsgType() MessageType {
+	return MsgStfu
+}
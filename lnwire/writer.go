@@ -241,6 +241,15 @@ func WritePongPayload(buf *bytes.Buffer, payload PongPayload) error {
 	return writeDataWithLength(buf, payload)
 }
 
+// WritePeerStorageBlob appends the peer storage blob to the provided buffer.
+func WritePeerStorageBlob(buf *bytes.Buffer, blob PeerStorageBlob) error {
+	if len(blob) > MaxPeerStorageBytes {
+		return ErrPeerStorageBytesExceeded
+	}
+
+	return writeDataWithLength(buf, blob)
+}
+
 // WriteWarningData appends the data to the provided buffer.
 func WriteWarningData(buf *bytes.Buffer, data WarningData) error {
 	return writeDataWithLength(buf, data)
@@ -2,6 +2,7 @@ package netann
 
 import (
 	"bytes"
+	"fmt"
 
 	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/lnwire"
@@ -64,23 +65,93 @@ func CreateChanAnnouncement(chanProof *channeldb.ChannelAuthProof,
 	// We'll unconditionally queue the channel's existence chanProof as it
 	// will need to be processed before either of the channel update
 	// networkMsgs.
+	edge1Ann, edge2Ann, err := createEdgeUpdates(chanInfo, e1, e2)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return chanAnn, edge1Ann, edge2Ann, nil
+}
+
+// CreateChanAnnouncement2 is the counterpart of CreateChanAnnouncement for
+// channels that were announced with a taproot ChannelAnnouncement2 message. It
+// re-creates the original announcement from the database items, along with
+// the channel updates of both directions.
+func CreateChanAnnouncement2(chanProof *channeldb.ChannelAuthProof,
+	chanInfo *channeldb.ChannelEdgeInfo,
+	e1, e2 *channeldb.ChannelEdgePolicy) (*lnwire.ChannelAnnouncement2,
+	*lnwire.ChannelUpdate, *lnwire.ChannelUpdate, error) {
+
+	if chanInfo.Version != channeldb.ChanAnnVersion2 {
+		return nil, nil, nil, fmt.Errorf("channel %v was announced "+
+			"with version %v", chanInfo.ChannelID,
+			chanInfo.Version)
+	}
+
+	chanAnn := &lnwire.ChannelAnnouncement2{
+		ShortChannelID: lnwire.NewShortChanIDFromInt(
+			chanInfo.ChannelID,
+		),
+		ChainHash:       chanInfo.ChainHash,
+		Capacity:        chanInfo.Capacity,
+		NodeID1:         chanInfo.NodeKey1Bytes,
+		NodeID2:         chanInfo.NodeKey2Bytes,
+		MerkleRootHash:  chanInfo.MerkleRootHash,
+		Features:        lnwire.NewRawFeatureVector(),
+		ExtraOpaqueData: chanInfo.ExtraOpaqueData,
+	}
+
+	// The bitcoin keys are only stored if they were part of the original
+	// announcement.
+	if chanInfo.BitcoinKey1Bytes != [33]byte{} {
+		bitcoinKey1 := chanInfo.BitcoinKey1Bytes
+		bitcoinKey2 := chanInfo.BitcoinKey2Bytes
+		chanAnn.BitcoinKey1 = &bitcoinKey1
+		chanAnn.BitcoinKey2 = &bitcoinKey2
+	}
 
-	// Since it's up to a node's policy as to whether they advertise the
-	// edge in a direction, we don't create an advertisement if the edge is
-	// nil.
-	var edge1Ann, edge2Ann *lnwire.ChannelUpdate
+	err := chanAnn.Features.Decode(bytes.NewReader(chanInfo.Features))
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	chanAnn.Signature, err = lnwire.NewSigFromSchnorrRawSignature(
+		chanProof.SchnorrSigBytes,
+	)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	edge1Ann, edge2Ann, err := createEdgeUpdates(chanInfo, e1, e2)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return chanAnn, edge1Ann, edge2Ann, nil
+}
+
+// createEdgeUpdates creates the channel updates of both directions of a
+// channel. Since it's up to a node's policy as to whether they advertise the
+// edge in a direction, we don't create an advertisement if the edge is nil.
+func createEdgeUpdates(chanInfo *channeldb.ChannelEdgeInfo,
+	e1, e2 *channeldb.ChannelEdgePolicy) (*lnwire.ChannelUpdate,
+	*lnwire.ChannelUpdate, error) {
+
+	var (
+		edge1Ann, edge2Ann *lnwire.ChannelUpdate
+		err                error
+	)
 	if e1 != nil {
 		edge1Ann, err = ChannelUpdateFromEdge(chanInfo, e1)
 		if err != nil {
-			return nil, nil, nil, err
+			return nil, nil, err
 		}
 	}
 	if e2 != nil {
 		edge2Ann, err = ChannelUpdateFromEdge(chanInfo, e2)
 		if err != nil {
-			return nil, nil, nil, err
+			return nil, nil, err
 		}
 	}
 
-	return chanAnn, edge1Ann, edge2Ann, nil
+	return edge1Ann, edge2Ann, nil
 }
@@ -46,6 +46,19 @@ func NodeAnnSetFeatures(features *lnwire.RawFeatureVector) func(*lnwire.NodeAnno
 	}
 }
 
+// NodeAnnSetLiquidityAd is a functional option that sets the liquidity ad of
+// the given node announcement, or removes it if the ad is nil.
+func NodeAnnSetLiquidityAd(
+	ad *lnwire.LiquidityAd) func(*lnwire.NodeAnnouncement) {
+
+	return func(nodeAnn *lnwire.NodeAnnouncement) {
+		err := lnwire.SetLiquidityAd(&nodeAnn.ExtraOpaqueData, ad)
+		if err != nil {
+			log.Errorf("Unable to set liquidity ad: %v", err)
+		}
+	}
+}
+
 // NodeAnnSetTimestamp is a functional option that sets the timestamp of the
 // announcement to the current time, or increments it if the timestamp is
 // already in the future.
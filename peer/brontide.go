@@ -3,6 +3,7 @@ package peer
 import (
 	"bytes"
 	"container/list"
+	"encoding/binary"
 	"errors"
 	"fmt"
 	"net"
@@ -39,9 +40,11 @@ import (
 	"github.com/lightningnetwork/lnd/netann"
 	"github.com/lightningnetwork/lnd/pool"
 	"github.com/lightningnetwork/lnd/queue"
+	"github.com/lightningnetwork/lnd/routing/route"
 	"github.com/lightningnetwork/lnd/subscribe"
 	"github.com/lightningnetwork/lnd/ticker"
 	"github.com/lightningnetwork/lnd/watchtower/wtclient"
+	"github.com/lightningnetwork/lnd/wirecapture"
 )
 
 const (
@@ -65,6 +68,15 @@ const (
 
 	// ErrorBufferSize is the number of historic peer errors that we store.
 	ErrorBufferSize = 10
+
+	// drainPollInterval is the interval at which we check whether a
+	// channel that is drained before a cooperative close has resolved its
+	// in-flight HTLCs.
+	drainPollInterval = 1 * time.Second
+
+	// DefaultDrainTimeout is the default duration we wait for a channel to
+	// resolve its in-flight HTLCs before it's cooperatively closed.
+	DefaultDrainTimeout = 10 * time.Minute
 )
 
 var (
@@ -74,6 +86,23 @@ var (
 	ErrChannelNotFound = fmt.Errorf("channel not found")
 )
 
+// invalidMessageError wraps the error returned when a message read from the
+// peer couldn't be decoded, which allows it to be told apart from errors of
+// the underlying connection.
+type invalidMessageError struct {
+	err error
+}
+
+// Error returns the error of the decoding failure.
+func (e *invalidMessageError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the error of the decoding failure.
+func (e *invalidMessageError) Unwrap() error {
+	return e.err
+}
+
 // outgoingMsg packages an lnwire.Message to be sent out on the wire, along with
 // a buffered channel which will be sent upon once the write is complete. This
 // buffered channel acts as a semaphore to be used for synchronization purposes.
@@ -104,12 +133,28 @@ type closeMsg struct {
 	msg lnwire.Message
 }
 
+// bumpCloseFeeMsg is a request to replace the closing transaction of a channel
+// that is closed with the simple close protocol with one paying a higher fee
+// rate.
+type bumpCloseFeeMsg struct {
+	chanID  lnwire.ChannelID
+	feeRate chainfee.SatPerKWeight
+	txid    chan chainhash.Hash
+	err     chan error
+}
+
 // PendingUpdate describes the pending state of a closing channel.
 type PendingUpdate struct {
 	Txid        []byte
 	OutputIndex uint32
 }
 
+// DrainUpdate reports the progress of draining a channel of its in-flight
+// HTLCs before it's cooperatively closed.
+type DrainUpdate struct {
+	NumPendingHtlcs uint32
+}
+
 // ChannelCloseUpdate contains the outcome of the close channel operation.
 type ChannelCloseUpdate struct {
 	ClosingTxid []byte
@@ -296,10 +341,19 @@ type Config struct {
 	// commitment fee. This only applies for the initiator of the channel.
 	MaxChannelFeeAllocation float64
 
+	// MaxFeeExposure is used when creating ChannelLinks and is the maximum
+	// fee exposure of either commitment of a channel with this peer. A
+	// zero value disables the limit.
+	MaxFeeExposure lnwire.MilliSatoshi
+
 	// MaxAnchorsCommitFeeRate is the maximum fee rate we'll use as an
 	// initiator for anchor channel commitments.
 	MaxAnchorsCommitFeeRate chainfee.SatPerKWeight
 
+	// MaxLocalCSVDelay is the maximum CSV delay we accept for our own
+	// funds when the commitment of a channel is upgraded.
+	MaxLocalCSVDelay uint16
+
 	// CoopCloseTargetConfs is the confirmation target that will be used
 	// to estimate the fee rate to use during a cooperative channel
 	// closure initiated by the remote peer.
@@ -343,6 +397,33 @@ type Config struct {
 	AddLocalAlias func(alias, base lnwire.ShortChannelID,
 		gossip bool) error
 
+	// FetchPeerStorage fetches the latest blob the remote peer asked us to
+	// store on its behalf. It should return channeldb.ErrNoPeerStorage if
+	// the peer never sent us a blob.
+	FetchPeerStorage func(route.Vertex) ([]byte, error)
+
+	// StorePeerStorage persists a blob the remote peer asked us to store
+	// on its behalf, replacing any prior blob.
+	StorePeerStorage func(route.Vertex, []byte) error
+
+	// GenPeerBackup assembles an encrypted backup of all channels we have
+	// with the target peer, suitable to be stored by that peer.
+	GenPeerBackup func(*btcec.PublicKey) ([]byte, error)
+
+	// HandlePeerBackup is called whenever the remote peer hands back the
+	// backup blob we previously asked it to store, so that any channels
+	// we no longer know of can be recovered.
+	HandlePeerBackup func(*btcec.PublicKey, []byte) error
+
+	// ReportInvalidMessage is called when the remote peer sent us a
+	// message that couldn't be decoded, right before we disconnect from
+	// it.
+	ReportInvalidMessage func(route.Vertex, error)
+
+	// WireCapture, if non-nil, captures all decrypted messages exchanged
+	// with the peer. It's closed when the peer is disconnected.
+	WireCapture *wirecapture.Writer
+
 	// PongBuf is a slice we'll reuse instead of allocating memory on the
 	// heap. Since only reads will occur and no writes, there is no need
 	// for any synchronization primitives. As a result, it's safe to share
@@ -368,6 +449,18 @@ type Brontide struct {
 	bytesReceived uint64
 	bytesSent     uint64
 
+	// priorityQueueLen and lazyQueueLen are the number of messages
+	// currently held in the high and low priority queues of the
+	// queueHandler.
+	//
+	// MUST be used atomically.
+	priorityQueueLen int64
+	lazyQueueLen     int64
+
+	// msgStats records the number of messages and bytes exchanged with
+	// the peer by message type.
+	msgStats msgHistogram
+
 	// pingTime is a rough estimate of the RTT (round-trip-time) between us
 	// and the connected peer. This time is expressed in microseconds.
 	// To be used atomically.
@@ -452,6 +545,10 @@ type Brontide struct {
 	// well as lnwire.ClosingSigned messages.
 	chanCloseMsgs chan *closeMsg
 
+	// bumpCloseFeeReqs is a channel over which requests to bump the fee
+	// of a closing transaction are sent.
+	bumpCloseFeeReqs chan *bumpCloseFeeMsg
+
 	// remoteFeatures is the feature vector received from the peer during
 	// the connection handshake.
 	remoteFeatures *lnwire.FeatureVector
@@ -500,6 +597,7 @@ func NewBrontide(cfg Config) *Brontide {
 		localCloseChanReqs: make(chan *htlcswitch.ChanClose),
 		linkFailures:       make(chan linkFailureReport),
 		chanCloseMsgs:      make(chan *closeMsg),
+		bumpCloseFeeReqs:   make(chan *bumpCloseFeeMsg),
 		resentChanSyncMsg:  make(map[lnwire.ChannelID]struct{}),
 		queueQuit:          make(chan struct{}),
 		quit:               make(chan struct{}),
@@ -654,6 +752,12 @@ func (p *Brontide) Start() error {
 	// announcements through their timestamps.
 	p.maybeSendNodeAnn(activeChans)
 
+	// If we store a backup blob on behalf of the remote peer, we'll hand
+	// it back to them now, so they can detect any data loss. In return,
+	// we'll give them an up to date backup of our channels with them.
+	p.maybeSendYourPeerStorage()
+	p.maybeSendPeerStorage()
+
 	return nil
 }
 
@@ -678,6 +782,31 @@ func (p *Brontide) initGossipSync() {
 	}
 }
 
+// simpleCloseAllowed returns true if both parties have negotiated the simple
+// close feature, in which case channels are closed with replaceable closing
+// transactions.
+func (p *Brontide) simpleCloseAllowed() bool {
+	return p.RemoteFeatures().HasFeature(lnwire.SimpleCloseOptional) &&
+		p.LocalFeatures().HasFeature(lnwire.SimpleCloseOptional)
+}
+
+// quiescenceAllowed returns true if both parties have negotiated the
+// quiescence feature, in which case channels can be made quiescent using the
+// stfu message.
+func (p *Brontide) quiescenceAllowed() bool {
+	return p.RemoteFeatures().HasFeature(lnwire.QuiescenceOptional) &&
+		p.LocalFeatures().HasFeature(lnwire.QuiescenceOptional)
+}
+
+// dynamicCommitmentsAllowed returns true if both parties have negotiated the
+// dynamic commitments feature, in which case the commitment of channels can be
+// upgraded using the dyn_propose message.
+func (p *Brontide) dynamicCommitmentsAllowed() bool {
+	return p.RemoteFeatures().HasFeature(
+		lnwire.DynamicCommitmentsOptional,
+	) && p.LocalFeatures().HasFeature(lnwire.DynamicCommitmentsOptional)
+}
+
 // taprootShutdownAllowed returns true if both parties have negotiated the
 // shutdown-any-segwit feature.
 func (p *Brontide) taprootShutdownAllowed() bool {
@@ -995,11 +1124,17 @@ func (p *Brontide) addLink(chanPoint *wire.OutPoint,
 		TowerClient:             towerClient,
 		MaxOutgoingCltvExpiry:   p.cfg.MaxOutgoingCltvExpiry,
 		MaxFeeAllocation:        p.cfg.MaxChannelFeeAllocation,
+		MaxFeeExposure:          p.cfg.MaxFeeExposure,
 		MaxAnchorsCommitFeeRate: p.cfg.MaxAnchorsCommitFeeRate,
+		DisallowQuiescence:      !p.quiescenceAllowed(),
+		QuiescenceTimeout:       htlcswitch.DefaultQuiescenceTimeout,
+		DisallowDynamicCommits:  !p.dynamicCommitmentsAllowed(),
+		MaxLocalCSVDelay:        p.cfg.MaxLocalCSVDelay,
 		NotifyActiveLink:        p.cfg.ChannelNotifier.NotifyActiveLinkEvent,
 		NotifyActiveChannel:     p.cfg.ChannelNotifier.NotifyActiveChannelEvent,
 		NotifyInactiveChannel:   p.cfg.ChannelNotifier.NotifyInactiveChannelEvent,
 		NotifyInactiveLinkEvent: p.cfg.ChannelNotifier.NotifyInactiveLinkEvent,
+		NotifyUpgradedChannel:   p.cfg.ChannelNotifier.NotifyUpgradedChannelEvent,
 		HtlcNotifier:            p.cfg.HtlcNotifier,
 		GetAliases:              p.cfg.GetAliases,
 	}
@@ -1080,9 +1215,31 @@ func (p *Brontide) Disconnect(reason error) {
 	// Ensure that the TCP connection is properly closed before continuing.
 	p.cfg.Conn.Close()
 
+	if p.cfg.WireCapture != nil {
+		if err := p.cfg.WireCapture.Close(); err != nil {
+			p.log.Errorf("Unable to close wire capture: %v", err)
+		}
+	}
+
 	close(p.quit)
 }
 
+// captureMessage records a raw message in the wire capture of the peer, if
+// enabled.
+func (p *Brontide) captureMessage(direction wirecapture.Direction,
+	rawMsg []byte) {
+
+	if p.cfg.WireCapture == nil {
+		return
+	}
+
+	err := p.cfg.WireCapture.Record(direction, rawMsg, time.Now())
+	if err != nil && !errors.Is(err, wirecapture.ErrWriterClosed) {
+		p.log.Errorf("Unable to capture %v message: %v", direction,
+			err)
+	}
+}
+
 // String returns the string representation of this peer.
 func (p *Brontide) String() string {
 	return fmt.Sprintf("%x@%s", p.cfg.PubKeyBytes, p.cfg.Conn.RemoteAddr())
@@ -1132,12 +1289,23 @@ func (p *Brontide) readNextMessage() (lnwire.Message, error) {
 		}
 		msgLen = uint64(len(rawMsg))
 
+		// Record the message in the histogram by the type found in
+		// its header, so that messages we fail to decode are counted
+		// and captured as well.
+		p.captureMessage(wirecapture.Inbound, rawMsg)
+		if msgLen >= 2 {
+			msgType := lnwire.MessageType(
+				binary.BigEndian.Uint16(rawMsg[:2]),
+			)
+			p.msgStats.recordReceived(msgType, msgLen)
+		}
+
 		// Next, create a new io.Reader implementation from the raw
 		// message, and use this to decode the message directly from.
 		msgReader := bytes.NewReader(rawMsg)
 		nextMsg, err = lnwire.ReadMessage(msgReader, 0)
 		if err != nil {
-			return err
+			return &invalidMessageError{err: err}
 		}
 
 		// At this point, rawMsg and buf will be returned back to the
@@ -1480,6 +1648,14 @@ out:
 		if err != nil {
 			p.log.Infof("unable to read message from peer: %v", err)
 
+			// Errors of messages that couldn't be decoded are
+			// wrapped, so we unwrap them to inspect their type.
+			var invalidMsg *invalidMessageError
+			isInvalid := errors.As(err, &invalidMsg)
+			if isInvalid {
+				err = invalidMsg.err
+			}
+
 			// If we could not read our peer's message due to an
 			// unknown type or invalid alias, we continue processing
 			// as normal. We store unknown message and address
@@ -1514,8 +1690,15 @@ out:
 
 			// If the error we encountered wasn't just a message we
 			// didn't recognize, then we'll stop all processing as
-			// this is a fatal error.
+			// this is a fatal error. If the peer sent us a message
+			// we couldn't decode, we'll also report it as
+			// misbehaving.
 			default:
+				report := p.cfg.ReportInvalidMessage
+				if isInvalid && report != nil {
+					report(p.cfg.PubKeyBytes, err)
+				}
+
 				break out
 			}
 		}
@@ -1549,7 +1732,9 @@ out:
 			*lnwire.AcceptChannel,
 			*lnwire.FundingCreated,
 			*lnwire.FundingSigned,
-			*lnwire.ChannelReady:
+			*lnwire.ChannelReady,
+			*lnwire.LeaseRequest,
+			*lnwire.LeaseOffer:
 
 			p.cfg.FundingManager.ProcessFundingMsg(msg, p)
 
@@ -1566,6 +1751,20 @@ out:
 				break out
 			}
 
+		case *lnwire.ClosingComplete:
+			select {
+			case p.chanCloseMsgs <- &closeMsg{msg.ChannelID, msg}:
+			case <-p.quit:
+				break out
+			}
+
+		case *lnwire.ClosingSig:
+			select {
+			case p.chanCloseMsgs <- &closeMsg{msg.ChannelID, msg}:
+			case <-p.quit:
+				break out
+			}
+
 		case *lnwire.Warning:
 			targetChan = msg.ChanID
 			isLinkUpdate = p.handleWarning(msg)
@@ -1599,13 +1798,16 @@ out:
 
 		case *lnwire.ChannelUpdate,
 			*lnwire.ChannelAnnouncement,
+			*lnwire.ChannelAnnouncement2,
 			*lnwire.NodeAnnouncement,
 			*lnwire.AnnounceSignatures,
 			*lnwire.GossipTimestampRange,
 			*lnwire.QueryShortChanIDs,
 			*lnwire.QueryChannelRange,
 			*lnwire.ReplyChannelRange,
-			*lnwire.ReplyShortChanIDsEnd:
+			*lnwire.ReplyShortChanIDsEnd,
+			*lnwire.ReconcileSketch,
+			*lnwire.ReconcileDiff:
 
 			discStream.AddMsg(msg)
 
@@ -1616,6 +1818,12 @@ out:
 				p.log.Errorf("%v", err)
 			}
 
+		case *lnwire.PeerStorage:
+			p.handlePeerStorage(msg)
+
+		case *lnwire.YourPeerStorage:
+			p.handleYourPeerStorage(msg)
+
 		default:
 			// If the message we received is unknown to us, store
 			// the type to track the failure.
@@ -1664,6 +1872,107 @@ func (p *Brontide) handleCustomMessage(msg *lnwire.Custom) error {
 	return p.cfg.HandleCustomMessage(p.PubKey(), msg)
 }
 
+// peerStorageEnabled returns true if we advertise that we're willing to store
+// backup blobs on behalf of our peers.
+func (p *Brontide) peerStorageEnabled() bool {
+	return p.cfg.Features.HasFeature(lnwire.ProvideStorageOptional)
+}
+
+// handlePeerStorage persists the blob that the remote peer asked us to store
+// on its behalf. We only store blobs for peers that we have at least one
+// channel with, to prevent a peer from using our disk space for free.
+func (p *Brontide) handlePeerStorage(msg *lnwire.PeerStorage) {
+	if p.cfg.StorePeerStorage == nil || !p.peerStorageEnabled() {
+		p.log.Debugf("Ignoring peer storage request, feature not " +
+			"enabled")
+		return
+	}
+
+	p.activeChanMtx.RLock()
+	numChans := len(p.activeChannels)
+	p.activeChanMtx.RUnlock()
+
+	if numChans == 0 {
+		p.log.Debugf("Ignoring peer storage request from peer " +
+			"without channels")
+		return
+	}
+
+	p.log.Debugf("Storing %v byte peer storage blob", len(msg.Blob))
+
+	err := p.cfg.StorePeerStorage(route.Vertex(p.PubKey()), msg.Blob)
+	if err != nil {
+		p.log.Errorf("Unable to store peer storage blob: %v", err)
+	}
+}
+
+// handleYourPeerStorage is called when the remote peer hands back the backup
+// blob we previously asked it to store. The blob is passed on so that any
+// channels that we've lost can be recovered.
+func (p *Brontide) handleYourPeerStorage(msg *lnwire.YourPeerStorage) {
+	if p.cfg.HandlePeerBackup == nil || len(msg.Blob) == 0 {
+		return
+	}
+
+	p.log.Debugf("Received %v byte peer storage blob", len(msg.Blob))
+
+	err := p.cfg.HandlePeerBackup(p.IdentityKey(), msg.Blob)
+	if err != nil {
+		p.log.Errorf("Unable to handle peer storage blob: %v", err)
+	}
+}
+
+// maybeSendYourPeerStorage hands back the latest blob the remote peer asked us
+// to store, if any.
+func (p *Brontide) maybeSendYourPeerStorage() {
+	if p.cfg.FetchPeerStorage == nil || !p.peerStorageEnabled() {
+		return
+	}
+
+	blob, err := p.cfg.FetchPeerStorage(route.Vertex(p.PubKey()))
+	switch {
+	case errors.Is(err, channeldb.ErrNoPeerStorage):
+		return
+
+	case err != nil:
+		p.log.Errorf("Unable to fetch peer storage blob: %v", err)
+		return
+	}
+
+	p.log.Debugf("Handing back %v byte peer storage blob", len(blob))
+
+	p.queueMsgLazy(lnwire.NewYourPeerStorage(blob), nil)
+}
+
+// maybeSendPeerStorage sends the remote peer an up to date encrypted backup
+// of all channels we have with it, given the peer signaled that it's willing
+// to store it.
+func (p *Brontide) maybeSendPeerStorage() {
+	if p.cfg.GenPeerBackup == nil || !p.peerStorageEnabled() {
+		return
+	}
+
+	if !p.remoteFeatures.HasFeature(lnwire.ProvideStorageOptional) {
+		return
+	}
+
+	blob, err := p.cfg.GenPeerBackup(p.IdentityKey())
+	if err != nil {
+		p.log.Errorf("Unable to generate peer backup: %v", err)
+		return
+	}
+
+	// If we don't have any channels with the peer, there's nothing to
+	// back up.
+	if len(blob) == 0 {
+		return
+	}
+
+	p.log.Debugf("Sending %v byte peer backup", len(blob))
+
+	p.queueMsgLazy(lnwire.NewPeerStorage(blob), nil)
+}
+
 // isActiveChannel returns true if the provided channel id is active, otherwise
 // returns false.
 func (p *Brontide) isActiveChannel(chanID lnwire.ChannelID) bool {
@@ -1810,6 +2119,23 @@ func messageSummary(msg lnwire.Message) string {
 		return fmt.Sprintf("chan_id=%v, fee_sat=%v", msg.ChannelID,
 			msg.FeeSatoshis)
 
+	case *lnwire.ClosingComplete:
+		return fmt.Sprintf("chan_id=%v, fee_sat=%v, locktime=%v",
+			msg.ChannelID, msg.FeeSatoshis, msg.LockTime)
+
+	case *lnwire.ClosingSig:
+		return fmt.Sprintf("chan_id=%v, fee_sat=%v, locktime=%v",
+			msg.ChannelID, msg.FeeSatoshis, msg.LockTime)
+
+	case *lnwire.LeaseRequest:
+		return fmt.Sprintf("lease_id=%x, amt=%v, duration=%v, "+
+			"max_fee=%v", msg.LeaseID[:], msg.LeaseAmt,
+			msg.LeaseDuration, msg.MaxFee)
+
+	case *lnwire.LeaseOffer:
+		return fmt.Sprintf("lease_id=%x, fee=%v, expiry=%v",
+			msg.LeaseID[:], msg.Fee, msg.LeaseExpiry)
+
 	case *lnwire.UpdateAddHTLC:
 		return fmt.Sprintf("chan_id=%v, id=%v, amt=%v, expiry=%v, hash=%x",
 			msg.ChanID, msg.ID, msg.Amount, msg.Expiry, msg.PaymentHash[:])
@@ -1849,6 +2175,11 @@ func messageSummary(msg lnwire.Message) string {
 		return fmt.Sprintf("chain_hash=%v, short_chan_id=%v",
 			msg.ChainHash, msg.ShortChannelID.ToUint64())
 
+	case *lnwire.ChannelAnnouncement2:
+		return fmt.Sprintf("chain_hash=%v, short_chan_id=%v, "+
+			"capacity=%v", msg.ChainHash,
+			msg.ShortChannelID.ToUint64(), msg.Capacity)
+
 	case *lnwire.ChannelUpdate:
 		return fmt.Sprintf("chain_hash=%v, short_chan_id=%v, "+
 			"mflags=%v, cflags=%v, update_time=%v", msg.ChainHash,
@@ -1869,6 +2200,20 @@ func messageSummary(msg lnwire.Message) string {
 		return fmt.Sprintf("chan_id=%v, fee_update_sat=%v",
 			msg.ChanID, int64(msg.FeePerKw))
 
+	case *lnwire.Stfu:
+		return fmt.Sprintf("chan_id=%v, initiator=%v", msg.ChanID,
+			msg.Initiator)
+
+	case *lnwire.DynPropose:
+		return fmt.Sprintf("chan_id=%v", msg.ChanID)
+
+	case *lnwire.DynAck:
+		return fmt.Sprintf("chan_id=%v", msg.ChanID)
+
+	case *lnwire.DynReject:
+		return fmt.Sprintf("chan_id=%v, reason=%v", msg.ChanID,
+			msg.Reason)
+
 	case *lnwire.ChannelReestablish:
 		return fmt.Sprintf("next_local_height=%v, remote_tail_height=%v",
 			msg.NextLocalCommitHeight, msg.RemoteCommitTailHeight)
@@ -1892,12 +2237,27 @@ func messageSummary(msg lnwire.Message) string {
 			"end_height=%v", msg.ChainHash, msg.FirstBlockHeight,
 			msg.LastBlockHeight())
 
+	case *lnwire.ReconcileSketch:
+		return fmt.Sprintf("chain_hash=%v, sketch_len=%v",
+			msg.ChainHash, len(msg.Sketch))
+
+	case *lnwire.ReconcileDiff:
+		return fmt.Sprintf("chain_hash=%v, complete=%v, "+
+			"num_elements=%v", msg.ChainHash, msg.Complete,
+			len(msg.Elements))
+
 	case *lnwire.GossipTimestampRange:
 		return fmt.Sprintf("chain_hash=%v, first_stamp=%v, "+
 			"stamp_range=%v", msg.ChainHash,
 			time.Unix(int64(msg.FirstTimestamp), 0),
 			msg.TimestampRange)
 
+	case *lnwire.PeerStorage:
+		return fmt.Sprintf("blob_len=%v", len(msg.Blob))
+
+	case *lnwire.YourPeerStorage:
+		return fmt.Sprintf("blob_len=%v", len(msg.Blob))
+
 	case *lnwire.Custom:
 		return fmt.Sprintf("type=%d", msg.Type)
 	}
@@ -2001,10 +2361,12 @@ func (p *Brontide) writeMessage(msg lnwire.Message) error {
 	err := p.cfg.WritePool.Submit(func(buf *bytes.Buffer) error {
 		// Using a buffer allocated by the write pool, encode the
 		// message directly into the buffer.
-		_, writeErr := lnwire.WriteMessage(buf, msg, 0)
+		n, writeErr := lnwire.WriteMessage(buf, msg, 0)
 		if writeErr != nil {
 			return writeErr
 		}
+		p.msgStats.recordSent(msg.MsgType(), n)
+		p.captureMessage(wirecapture.Outbound, buf.Bytes())
 
 		// Finally, write the message itself in a single swoop. This
 		// will buffer the ciphertext on the underlying connection. We
@@ -2134,6 +2496,12 @@ func (p *Brontide) queueHandler() {
 	lazyMsgs := list.New()
 
 	for {
+		// Publish the current queue depths, which only change in
+		// between iterations of this loop.
+		numPriority, numLazy := priorityMsgs.Len(), lazyMsgs.Len()
+		atomic.StoreInt64(&p.priorityQueueLen, int64(numPriority))
+		atomic.StoreInt64(&p.lazyQueueLen, int64(numLazy))
+
 		// Examine the front of the priority queue, if it is empty check
 		// the low priority queue.
 		elem := priorityMsgs.Front()
@@ -2475,6 +2843,10 @@ out:
 
 			close(newChanReq.err)
 
+			// Now that the channel is active, the remote peer
+			// should store an updated backup that includes it.
+			p.maybeSendPeerStorage()
+
 		// We've just received a local request to close an active
 		// channel. It will either kick of a cooperative channel
 		// closure negotiation, or be a notification of a breached
@@ -2494,6 +2866,11 @@ out:
 		case closeMsg := <-p.chanCloseMsgs:
 			p.handleCloseMsg(closeMsg)
 
+		// We've received a local request to bump the fee of a closing
+		// transaction.
+		case req := <-p.bumpCloseFeeReqs:
+			p.handleBumpCloseFee(req)
+
 		// The channel reannounce delay has elapsed, broadcast the
 		// reenabled channel updates to the network. This should only
 		// fire once, so we set the reenableTimeout channel to nil to
@@ -2855,15 +3232,23 @@ func (p *Brontide) restartCoopClose(lnChan *lnwallet.LightningChannel) (
 	// Shutdown exactly, but doing so would mean persisting the RPC
 	// provided close script. Instead use the LocalUpfrontShutdownScript
 	// or generate a script.
+	//
+	// With the simple close protocol, a broadcast closing transaction can
+	// still be replaced, so we'll restart the process in that case as
+	// well.
 	c := lnChan.State()
-	_, err := c.BroadcastedCooperative()
+	closeTx, err := c.BroadcastedCooperative()
 	if err != nil && err != channeldb.ErrNoCloseTx {
 		// An error other than ErrNoCloseTx was encountered.
 		return nil, err
 	} else if err == nil {
-		// This channel has already completed the coop close
-		// negotiation.
-		return nil, nil
+		sequence := closeTx.TxIn[0].Sequence
+		replaceable := sequence < wire.MaxTxInSequenceNum-1
+		if !replaceable || !p.simpleCloseAllowed() {
+			// This channel has already completed the coop close
+			// negotiation.
+			return nil, nil
+		}
 	}
 
 	// As mentioned above, we don't re-create the delivery script.
@@ -2953,6 +3338,7 @@ func (p *Brontide) createChanCloser(channel *lnwallet.LightningChannel,
 			},
 			ChainParams: &p.cfg.Wallet.Cfg.NetParams,
 			Quit:        p.quit,
+			SimpleClose: p.simpleCloseAllowed(),
 		},
 		deliveryScript,
 		fee,
@@ -2988,6 +3374,14 @@ func (p *Brontide) handleLocalCloseReq(req *htlcswitch.ChanClose) {
 	// out this channel on-chain, so we execute the cooperative channel
 	// closure workflow.
 	case contractcourt.CloseRegular:
+		// If the caller asked us to drain the channel, we'll wait for
+		// all in-flight HTLCs to be resolved before we start the
+		// shutdown.
+		if req.DrainTimeout > 0 && !channel.IsChannelClean() {
+			p.drainChannel(req, channel)
+			return
+		}
+
 		// First, we'll choose a delivery address that we'll use to send the
 		// funds to in the case of a successful negotiation.
 
@@ -3001,6 +3395,7 @@ func (p *Brontide) handleLocalCloseReq(req *htlcswitch.ChanClose) {
 		)
 		if err != nil {
 			p.log.Errorf("cannot close channel %v: %v", req.ChanPoint, err)
+			p.restoreDrainedChannel(req)
 			req.Err <- err
 			return
 		}
@@ -3011,15 +3406,26 @@ func (p *Brontide) handleLocalCloseReq(req *htlcswitch.ChanClose) {
 			deliveryScript, err = p.genDeliveryScript()
 			if err != nil {
 				p.log.Errorf(err.Error())
+				p.restoreDrainedChannel(req)
 				req.Err <- err
 				return
 			}
 		}
 
 		// Optimistically try a link shutdown, erroring out if it
-		// failed.
-		if err := p.tryLinkShutdown(chanID); err != nil {
+		// failed. If the channel was drained, it may have picked up
+		// a new update in the meantime, so we'll keep draining in that
+		// case until the original deadline.
+		err = p.tryLinkShutdown(chanID)
+		if req.DrainTimeout > 0 &&
+			errors.Is(err, htlcswitch.ErrLinkFailedShutdown) {
+
+			p.drainChannel(req, channel)
+			return
+		}
+		if err != nil {
 			p.log.Errorf("failed link shutdown: %v", err)
+			p.restoreDrainedChannel(req)
 			req.Err <- err
 			return
 		}
@@ -3029,6 +3435,7 @@ func (p *Brontide) handleLocalCloseReq(req *htlcswitch.ChanClose) {
 		)
 		if err != nil {
 			p.log.Errorf(err.Error())
+			p.restoreDrainedChannel(req)
 			req.Err <- err
 			return
 		}
@@ -3041,6 +3448,7 @@ func (p *Brontide) handleLocalCloseReq(req *htlcswitch.ChanClose) {
 		shutdownMsg, err := chanCloser.ShutdownChan()
 		if err != nil {
 			p.log.Errorf(err.Error())
+			p.restoreDrainedChannel(req)
 			req.Err <- err
 			delete(p.activeChanCloses, chanID)
 
@@ -3133,10 +3541,18 @@ func (p *Brontide) handleLinkFailure(failure linkFailureReport) {
 		if failure.linkErr.SendData != nil {
 			data = failure.linkErr.SendData
 		}
-		err := p.SendMessage(true, &lnwire.Error{
+		var msg lnwire.Message = &lnwire.Error{
 			ChanID: failure.chanID,
 			Data:   data,
-		})
+		}
+		if failure.linkErr.Warning {
+			msg = &lnwire.Warning{
+				ChanID: failure.chanID,
+				Data:   data,
+			}
+		}
+
+		err := p.SendMessage(true, msg)
 		if err != nil {
 			p.log.Errorf("unable to send msg to "+
 				"remote peer: %v", err)
@@ -3200,6 +3616,138 @@ func (p *Brontide) fetchLinkFromKeyAndCid(
 	return chanLink
 }
 
+// drainChannel stops the link of the channel from accepting new HTLCs and
+// disables the channel, then waits in the background for its in-flight HTLCs
+// to be resolved. Once the channel is clean, the close request is handed back
+// to the channelManager to start the shutdown. If the channel isn't drained
+// within the request's DrainTimeout, the channel is enabled again and the
+// close request fails. A request that is drained again because the shutdown
+// failed keeps the deadline of its first drain.
+func (p *Brontide) drainChannel(req *htlcswitch.ChanClose,
+	channel *lnwallet.LightningChannel) {
+
+	resumed := !req.DrainDeadline.IsZero()
+	if !resumed {
+		req.DrainDeadline = time.Now().Add(req.DrainTimeout)
+	}
+
+	chanID := lnwire.NewChanIDFromOutPoint(req.ChanPoint)
+	chanLink := p.fetchLinkFromKeyAndCid(chanID)
+	if chanLink == nil {
+		p.restoreDrainedChannel(req)
+		req.Err <- ErrChannelNotFound
+		return
+	}
+
+	p.log.Infof("Draining ChannelPoint(%v) before cooperative close, "+
+		"deadline=%v", req.ChanPoint, req.DrainDeadline)
+
+	chanLink.DisableAdds()
+
+	// We'll also let the network know that the channel is disabled, so no
+	// new HTLCs are routed through it. This is a manual disable, such
+	// that the channel isn't re-enabled automatically while it drains.
+	err := p.cfg.ChanStatusMgr.RequestDisable(*req.ChanPoint, true)
+	if err != nil {
+		p.log.Warnf("Unable to disable ChannelPoint(%v) for drain: %v",
+			req.ChanPoint, err)
+	}
+
+	p.wg.Add(1)
+	go p.waitForDrain(req, channel, resumed)
+}
+
+// restoreDrainedChannel allows the link of a channel that was drained for a
+// close request to accept HTLCs again and re-enables the channel. It's called
+// when the close request fails, such that the channel can be used again. It
+// does nothing if the channel wasn't drained.
+func (p *Brontide) restoreDrainedChannel(req *htlcswitch.ChanClose) {
+	if req.DrainDeadline.IsZero() {
+		return
+	}
+
+	chanID := lnwire.NewChanIDFromOutPoint(req.ChanPoint)
+	if chanLink := p.fetchLinkFromKeyAndCid(chanID); chanLink != nil {
+		chanLink.EnableAdds()
+	}
+
+	err := p.cfg.ChanStatusMgr.RequestAuto(*req.ChanPoint)
+	if err != nil {
+		p.log.Warnf("Unable to restore status of ChannelPoint(%v): %v",
+			req.ChanPoint, err)
+	}
+}
+
+// waitForDrain polls the channel until all of its in-flight HTLCs have been
+// resolved, reporting the progress to the caller of the close request. If the
+// drain is resumed, the channel is only checked after the first poll interval,
+// so we don't spin while the link can't be shut down.
+//
+// NOTE: This method MUST be run as a goroutine.
+func (p *Brontide) waitForDrain(req *htlcswitch.ChanClose,
+	channel *lnwallet.LightningChannel, resumed bool) {
+
+	defer p.wg.Done()
+
+	timeout := time.NewTimer(time.Until(req.DrainDeadline))
+	defer timeout.Stop()
+
+	ticker := time.NewTicker(drainPollInterval)
+	defer ticker.Stop()
+
+	lastReported := -1
+	for {
+		// Report the number of in-flight HTLCs whenever it changes.
+		numHtlcs := len(channel.ActiveHtlcs())
+		if numHtlcs != lastReported {
+			lastReported = numHtlcs
+
+			select {
+			case req.Updates <- &DrainUpdate{
+				NumPendingHtlcs: uint32(numHtlcs),
+			}:
+			case <-p.quit:
+				return
+			}
+		}
+
+		// Once the channel is clean, we'll hand the request back to
+		// the channelManager to start the shutdown. We'll keep the
+		// adds disabled, so no new HTLCs can be added in the meantime.
+		if !resumed && channel.IsChannelClean() {
+			p.log.Infof("ChannelPoint(%v) drained, starting "+
+				"cooperative close", req.ChanPoint)
+
+			select {
+			case p.localCloseChanReqs <- req:
+			case <-p.quit:
+			}
+
+			return
+		}
+
+		select {
+		case <-ticker.C:
+			resumed = false
+
+		case <-timeout.C:
+			p.log.Warnf("Timed out draining ChannelPoint(%v), "+
+				"re-enabling channel", req.ChanPoint)
+
+			p.restoreDrainedChannel(req)
+
+			req.Err <- fmt.Errorf("timed out after %v waiting for "+
+				"%d in-flight htlcs to resolve",
+				req.DrainTimeout, numHtlcs)
+
+			return
+
+		case <-p.quit:
+			return
+		}
+	}
+}
+
 // finalizeChanClosure performs the final clean up steps once the cooperative
 // closure transaction has been fully broadcast. The finalized closing state
 // machine should be passed in. Once the transaction has been sufficiently
@@ -3212,6 +3760,14 @@ func (p *Brontide) finalizeChanClosure(chanCloser *chancloser.ChanCloser) {
 	chanPoint := chanCloser.Channel().ChannelPoint()
 	p.WipeChannel(chanPoint)
 
+	// With the simple close protocol, the closing transaction may still
+	// be replaced, so we'll keep the state machine around and wait for
+	// any version of the closing transaction to confirm.
+	if chanCloser.SimpleClose() {
+		p.finalizeRbfChanClosure(chanCloser)
+		return
+	}
+
 	// Also clear the activeChanCloses map of this channel.
 	cid := lnwire.NewChanIDFromOutPoint(chanPoint)
 	delete(p.activeChanCloses, cid)
@@ -3260,6 +3816,127 @@ func (p *Brontide) finalizeChanClosure(chanCloser *chancloser.ChanCloser) {
 		})
 }
 
+// finalizeRbfChanClosure performs the final clean up steps for a channel that
+// is closed with the simple close protocol, once the first closing
+// transaction was broadcast. As the closing transaction may be replaced, we
+// rely on the chain arbitrator to tell us which version confirmed.
+func (p *Brontide) finalizeRbfChanClosure(chanCloser *chancloser.ChanCloser) {
+	closeReq := chanCloser.CloseRequest()
+	chanPoint := chanCloser.Channel().ChannelPoint()
+
+	closingTx, err := chanCloser.ClosingTx()
+	if err != nil {
+		p.log.Error(err)
+		if closeReq != nil {
+			closeReq.Err <- err
+		}
+		return
+	}
+
+	closingTxid := closingTx.TxHash()
+	if closeReq != nil {
+		closeReq.Updates <- &PendingUpdate{
+			Txid: closingTxid[:],
+		}
+	}
+
+	chainEvents, err := p.cfg.ChainArb.SubscribeChannelEvents(*chanPoint)
+	if err != nil {
+		p.log.Errorf("Unable to subscribe to events of "+
+			"ChannelPoint(%v): %v", chanPoint, err)
+		if closeReq != nil {
+			closeReq.Err <- err
+		}
+		return
+	}
+
+	peerLog.Infof("Waiting for confirmation of rbf close of "+
+		"ChannelPoint(%v)", chanPoint)
+
+	cid := lnwire.NewChanIDFromOutPoint(chanPoint)
+
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		defer chainEvents.Cancel()
+
+		select {
+		case closeInfo := <-chainEvents.CooperativeClosure:
+			peerLog.Infof("ChannelPoint(%v) is now closed with "+
+				"txid %v", chanPoint, closeInfo.ClosingTXID)
+
+			if closeReq != nil {
+				closeReq.Updates <- &ChannelCloseUpdate{
+					ClosingTxid: closeInfo.ClosingTXID[:],
+					Success:     true,
+				}
+			}
+
+			// The state machine is no longer needed once the
+			// channel is closed. It's only accessed from the
+			// channelManager goroutine, so we'll hand it the
+			// clean up.
+			select {
+			case p.chanCloseMsgs <- &closeMsg{cid: cid}:
+			case <-p.quit:
+			}
+
+		case <-p.quit:
+		}
+	}()
+}
+
+// handleBumpCloseFee replaces the closing transaction of a channel closed with
+// the simple close protocol with one paying the requested fee rate.
+func (p *Brontide) handleBumpCloseFee(req *bumpCloseFeeMsg) {
+	chanCloser, ok := p.activeChanCloses[req.chanID]
+	if !ok {
+		req.err <- fmt.Errorf("channel %v isn't being closed",
+			req.chanID)
+		return
+	}
+
+	closingComplete, txid, err := chanCloser.ProposeRbfClose(req.feeRate)
+	if err != nil {
+		req.err <- err
+		return
+	}
+
+	p.queueMsg(closingComplete, nil)
+
+	req.txid <- *txid
+}
+
+// BumpCloseFee proposes a new closing transaction paying the given fee rate for
+// a channel that is closed with the simple close protocol. The closing
+// transaction is broadcast once the remote party signed it. The txid of the
+// proposed closing transaction is returned.
+func (p *Brontide) BumpCloseFee(chanPoint wire.OutPoint,
+	feeRate chainfee.SatPerKWeight) (chainhash.Hash, error) {
+
+	req := &bumpCloseFeeMsg{
+		chanID:  lnwire.NewChanIDFromOutPoint(&chanPoint),
+		feeRate: feeRate,
+		txid:    make(chan chainhash.Hash, 1),
+		err:     make(chan error, 1),
+	}
+
+	select {
+	case p.bumpCloseFeeReqs <- req:
+	case <-p.quit:
+		return chainhash.Hash{}, lnpeer.ErrPeerExiting
+	}
+
+	select {
+	case txid := <-req.txid:
+		return txid, nil
+	case err := <-req.err:
+		return chainhash.Hash{}, err
+	case <-p.quit:
+		return chainhash.Hash{}, lnpeer.ErrPeerExiting
+	}
+}
+
 // WaitForChanToClose uses the passed notifier to wait until the channel has
 // been detected as closed on chain and then concludes by executing the
 // following actions: the channel point will be sent over the settleChan, and
@@ -3312,6 +3989,10 @@ func (p *Brontide) WipeChannel(chanPoint *wire.OutPoint) {
 	// Instruct the HtlcSwitch to close this link as the channel is no
 	// longer active.
 	p.cfg.Switch.RemoveLink(chanID)
+
+	// The backup the remote peer stores for us should no longer include
+	// this channel.
+	p.maybeSendPeerStorage()
 }
 
 // handleInitMsg handles the incoming init message which contains global and
@@ -3585,6 +4266,14 @@ func (p *Brontide) StartTime() time.Time {
 // message is received from the remote peer. We'll use this message to advance
 // the chan closer state machine.
 func (p *Brontide) handleCloseMsg(msg *closeMsg) {
+	// A close message without a wire message signals that a channel closed
+	// with the simple close protocol confirmed, so we can clean up its
+	// state machine.
+	if msg.msg == nil {
+		delete(p.activeChanCloses, msg.cid)
+		return
+	}
+
 	// We'll now fetch the matching closing state machine in order to continue,
 	// or finalize the channel closure process.
 	chanCloser, err := p.fetchActiveChanCloser(msg.cid)
@@ -3613,12 +4302,28 @@ func (p *Brontide) handleCloseMsg(msg *closeMsg) {
 		err := fmt.Errorf("unable to process close msg: %v", err)
 		p.log.Error(err)
 
+		// With the simple close protocol, a closing transaction may
+		// already have been broadcast. In that case, we'll keep the
+		// state machine around, so the closing transaction can still
+		// be replaced, and let the remote party know about the
+		// failure.
+		if _, txErr := chanCloser.ClosingTx(); txErr == nil &&
+			chanCloser.SimpleClose() {
+
+			p.queueMsg(&lnwire.Warning{
+				ChanID: msg.cid,
+				Data:   lnwire.WarningData(err.Error()),
+			}, nil)
+			return
+		}
+
 		// As the negotiations failed, we'll reset the channel state machine to
 		// ensure we act to on-chain events as normal.
 		chanCloser.Channel().ResetState()
 
-		if chanCloser.CloseRequest() != nil {
-			chanCloser.CloseRequest().Err <- err
+		if closeReq := chanCloser.CloseRequest(); closeReq != nil {
+			p.restoreDrainedChannel(closeReq)
+			closeReq.Err <- err
 		}
 		delete(p.activeChanCloses, msg.cid)
 		return
@@ -3701,6 +4406,21 @@ func (p *Brontide) BytesSent() uint64 {
 	return atomic.LoadUint64(&p.bytesSent)
 }
 
+// MessageStats returns the number of messages and bytes exchanged with the
+// peer for each message type seen so far, sorted by message type.
+func (p *Brontide) MessageStats() []MessageStats {
+	return p.msgStats.snapshot()
+}
+
+// QueueDepths returns the number of messages currently waiting in the
+// outgoing queues of the peer.
+func (p *Brontide) QueueDepths() QueueDepths {
+	return QueueDepths{
+		Priority: int(atomic.LoadInt64(&p.priorityQueueLen)),
+		Lazy:     int(atomic.LoadInt64(&p.lazyQueueLen)),
+	}
+}
+
 // LastRemotePingPayload returns the last payload the remote party sent as part
 // of their ping.
 func (p *Brontide) LastRemotePingPayload() []byte {
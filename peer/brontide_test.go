@@ -2,6 +2,8 @@ package peer
 
 import (
 	"bytes"
+	"crypto/sha256"
+	"errors"
 	"testing"
 	"time"
 
@@ -16,6 +18,7 @@ import (
 	"github.com/lightningnetwork/lnd/contractcourt"
 	"github.com/lightningnetwork/lnd/htlcswitch"
 	"github.com/lightningnetwork/lnd/lntest/mock"
+	"github.com/lightningnetwork/lnd/lnwallet"
 	"github.com/lightningnetwork/lnd/lnwallet/chancloser"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/pool"
@@ -637,6 +640,184 @@ func TestPeerChannelClosureFeeNegotiationsInitiator(t *testing.T) {
 	notifier.ConfChan <- &chainntnfs.TxConfirmation{}
 }
 
+// addTestHtlc adds an HTLC from Alice to Bob and locks it in on both
+// commitments, returning its preimage.
+func addTestHtlc(t *testing.T, alice, bob *lnwallet.LightningChannel,
+	id uint64) [32]byte {
+
+	t.Helper()
+
+	var preimage [32]byte
+	preimage[0] = byte(id + 1)
+
+	htlc := &lnwire.UpdateAddHTLC{
+		ID:          id,
+		PaymentHash: sha256.Sum256(preimage[:]),
+		Amount:      lnwire.NewMSatFromSatoshis(100_000),
+		Expiry:      500,
+	}
+	_, err := alice.AddHTLC(htlc, nil)
+	require.NoError(t, err)
+	_, err = bob.ReceiveHTLC(htlc)
+	require.NoError(t, err)
+	require.NoError(t, lnwallet.ForceStateTransition(alice, bob))
+
+	return preimage
+}
+
+// settleTestHtlc settles an HTLC added with addTestHtlc and removes it from
+// both commitments.
+func settleTestHtlc(t *testing.T, alice, bob *lnwallet.LightningChannel,
+	id uint64, preimage [32]byte) {
+
+	t.Helper()
+
+	require.NoError(t, bob.SettleHTLC(preimage, id, nil, nil, nil))
+	require.NoError(t, alice.ReceiveHTLCSettle(preimage, id))
+	require.NoError(t, lnwallet.ForceStateTransition(bob, alice))
+}
+
+// TestPeerDrainBeforeClose tests that a channel is drained before a
+// cooperative close if requested, and that the channel is usable again
+// whenever the close fails after the drain started.
+func TestPeerDrainBeforeClose(t *testing.T) {
+	t.Parallel()
+
+	notifier := &mock.ChainNotifier{
+		SpendChan: make(chan *chainntnfs.SpendDetail),
+		EpochChan: make(chan *chainntnfs.BlockEpoch),
+		ConfChan:  make(chan *chainntnfs.TxConfirmation),
+	}
+	broadcastTxChan := make(chan *wire.MsgTx)
+
+	mockSwitch := &mockMessageSwitch{}
+
+	alicePeer, bobChan, err := createTestPeer(
+		t, notifier, broadcastTxChan, noUpdate, mockSwitch,
+	)
+	require.NoError(t, err, "unable to create test channels")
+
+	chanID := lnwire.NewChanIDFromOutPoint(bobChan.ChannelPoint())
+	mockLink := newMockUpdateHandler(chanID)
+	mockSwitch.links = append(mockSwitch.links, mockLink)
+
+	alicePeer.activeChanMtx.RLock()
+	aliceChan := alicePeer.activeChannels[chanID]
+	alicePeer.activeChanMtx.RUnlock()
+
+	// The test channels use random channel constraints, so we'll replace
+	// them to be able to add HTLCs.
+	constraints := channeldb.ChannelConstraints{
+		MaxPendingAmount: lnwire.NewMSatFromSatoshis(1e8),
+		ChanReserve:      1000,
+		MinHTLC:          1,
+		MaxAcceptedHtlcs: 10,
+	}
+	for _, channel := range []*lnwallet.LightningChannel{
+		aliceChan, bobChan,
+	} {
+		state := channel.State()
+		for _, cfg := range []*channeldb.ChannelConfig{
+			&state.LocalChanCfg, &state.RemoteChanCfg,
+		} {
+			constraints.DustLimit = cfg.DustLimit
+			constraints.CsvDelay = cfg.CsvDelay
+			cfg.ChannelConstraints = constraints
+		}
+	}
+
+	// We'll also need to populate the initial revocation windows to be
+	// able to update the commitments.
+	aliceNextRevoke, err := aliceChan.NextRevocationKey()
+	require.NoError(t, err)
+	require.NoError(t, bobChan.InitNextRevocation(aliceNextRevoke))
+	bobNextRevoke, err := bobChan.NextRevocationKey()
+	require.NoError(t, err)
+	require.NoError(t, aliceChan.InitNextRevocation(bobNextRevoke))
+
+	newCloseReq := func(drainTimeout time.Duration) *htlcswitch.ChanClose {
+		return &htlcswitch.ChanClose{
+			CloseType:      contractcourt.CloseRegular,
+			ChanPoint:      bobChan.ChannelPoint(),
+			TargetFeePerKw: 12500,
+			DrainTimeout:   drainTimeout,
+			Updates:        make(chan interface{}, 10),
+			Err:            make(chan error, 1),
+		}
+	}
+
+	assertDrainUpdate := func(req *htlcswitch.ChanClose, numHtlcs int) {
+		t.Helper()
+
+		select {
+		case update := <-req.Updates:
+			require.Equal(t, &DrainUpdate{
+				NumPendingHtlcs: uint32(numHtlcs),
+			}, update)
+
+		case <-time.After(timeout):
+			t.Fatalf("did not receive drain update")
+		}
+	}
+
+	assertCloseErr := func(req *htlcswitch.ChanClose) error {
+		t.Helper()
+
+		select {
+		case err := <-req.Err:
+			return err
+
+		case <-time.After(timeout):
+			t.Fatalf("close request didn't fail")
+			return nil
+		}
+	}
+
+	preimage := addTestHtlc(t, aliceChan, bobChan, 0)
+
+	// If the HTLC isn't resolved in time, the close fails and the link
+	// accepts adds again.
+	req := newCloseReq(drainPollInterval)
+	alicePeer.localCloseChanReqs <- req
+	assertDrainUpdate(req, 1)
+	require.True(t, mockLink.isAddsDisabled())
+
+	require.ErrorContains(t, assertCloseErr(req), "timed out")
+	require.False(t, mockLink.isAddsDisabled())
+
+	// If the link can't be shut down once the HTLC is resolved, we'll
+	// keep draining until the original deadline rather than starting
+	// over.
+	mockLink.setShutdownErr(htlcswitch.ErrLinkFailedShutdown)
+	drainTimeout := 3 * drainPollInterval
+	start := time.Now()
+	req = newCloseReq(drainTimeout)
+	alicePeer.localCloseChanReqs <- req
+	assertDrainUpdate(req, 1)
+
+	settleTestHtlc(t, aliceChan, bobChan, 0, preimage)
+	assertDrainUpdate(req, 0)
+
+	require.ErrorContains(t, assertCloseErr(req), "timed out")
+	require.Less(t, time.Since(start), drainTimeout+drainPollInterval)
+	require.False(t, mockLink.isAddsDisabled())
+
+	// Finally, if the close fails for any other reason once the channel
+	// is drained, the link accepts adds again as well.
+	preimage = addTestHtlc(t, aliceChan, bobChan, 1)
+
+	errShutdown := errors.New("shutdown failed")
+	mockLink.setShutdownErr(errShutdown)
+	req = newCloseReq(timeout)
+	alicePeer.localCloseChanReqs <- req
+	assertDrainUpdate(req, 1)
+	require.True(t, mockLink.isAddsDisabled())
+
+	settleTestHtlc(t, aliceChan, bobChan, 1, preimage)
+	require.ErrorIs(t, assertCloseErr(req), errShutdown)
+	require.False(t, mockLink.isAddsDisabled())
+}
+
 // TestChooseDeliveryScript tests that chooseDeliveryScript correctly errors
 // when upfront and user set scripts that do not match are provided, allows
 // matching values and returns appropriate values in the case where one or none
@@ -0,0 +1,117 @@
+package peer
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// MsgTypeUnknown is the message type under which all messages of a type that
+// isn't known to lnwire, including custom messages, are recorded. This keeps a
+// peer from growing the histogram by sending messages of arbitrary types. No
+// protocol message uses type zero.
+const MsgTypeUnknown lnwire.MessageType = 0
+
+// MessageStats holds the number of messages and bytes of a single message
+// type exchanged with a peer.
+type MessageStats struct {
+	// Type is the message type the counters refer to.
+	Type lnwire.MessageType
+
+	// Sent is the number of messages of this type sent to the peer.
+	Sent uint64
+
+	// Received is the number of messages of this type received from the
+	// peer.
+	Received uint64
+
+	// BytesSent is the total size of the messages of this type sent to the
+	// peer, excluding the transport overhead.
+	BytesSent uint64
+
+	// BytesReceived is the total size of the messages of this type
+	// received from the peer, excluding the transport overhead.
+	BytesReceived uint64
+}
+
+// QueueDepths describes the number of messages waiting in the outgoing queues
+// of a peer.
+type QueueDepths struct {
+	// Priority is the number of high-priority messages, such as channel
+	// updates, waiting to be sent.
+	Priority int
+
+	// Lazy is the number of low-priority messages, mostly gossip, waiting
+	// to be sent.
+	Lazy int
+}
+
+// msgHistogram records the number of messages and bytes exchanged with a peer
+// by message type. It is safe for concurrent use, and its zero value is ready
+// to use.
+type msgHistogram struct {
+	mu    sync.Mutex
+	stats map[lnwire.MessageType]*MessageStats
+}
+
+// entry returns the counters of the given message type, creating them if
+// needed. Message types unknown to lnwire share the MsgTypeUnknown counters.
+// The caller MUST hold the mutex.
+func (h *msgHistogram) entry(msgType lnwire.MessageType) *MessageStats {
+	if !lnwire.IsKnownMessage(msgType) {
+		msgType = MsgTypeUnknown
+	}
+
+	if h.stats == nil {
+		h.stats = make(map[lnwire.MessageType]*MessageStats)
+	}
+
+	stats, ok := h.stats[msgType]
+	if !ok {
+		stats = &MessageStats{Type: msgType}
+		h.stats[msgType] = stats
+	}
+
+	return stats
+}
+
+// recordSent records a message of the given type and size sent to the peer.
+func (h *msgHistogram) recordSent(msgType lnwire.MessageType, size int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	stats := h.entry(msgType)
+	stats.Sent++
+	stats.BytesSent += uint64(size)
+}
+
+// recordReceived records a message of the given type and size received from
+// the peer.
+func (h *msgHistogram) recordReceived(msgType lnwire.MessageType,
+	size uint64) {
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	stats := h.entry(msgType)
+	stats.Received++
+	stats.BytesReceived += size
+}
+
+// snapshot returns a copy of the counters, sorted by message type.
+func (h *msgHistogram) snapshot() []MessageStats {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	snapshot := make([]MessageStats, 0, len(h.stats))
+	for _, stats := range h.stats {
+		snapshot = append(snapshot, *stats)
+	}
+
+	sort.Slice(snapshot, func(i, j int) bool {
+		return snapshot[i].Type < snapshot[j].Type
+	})
+
+	return snapshot
+}
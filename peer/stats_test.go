@@ -0,0 +1,70 @@
+package peer
+
+import (
+	"testing"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMsgHistogram asserts that the message histogram records messages by
+// type and returns its counters sorted by message type.
+func TestMsgHistogram(t *testing.T) {
+	t.Parallel()
+
+	var h msgHistogram
+	require.Empty(t, h.snapshot())
+
+	h.recordSent(lnwire.MsgUpdateAddHTLC, 100)
+	h.recordSent(lnwire.MsgUpdateAddHTLC, 50)
+	h.recordReceived(lnwire.MsgPing, 10)
+	h.recordReceived(lnwire.MsgUpdateAddHTLC, 70)
+
+	stats := h.snapshot()
+	require.Equal(t, []MessageStats{
+		{
+			Type:          lnwire.MsgPing,
+			Received:      1,
+			BytesReceived: 10,
+		},
+		{
+			Type:          lnwire.MsgUpdateAddHTLC,
+			Sent:          2,
+			Received:      1,
+			BytesSent:     150,
+			BytesReceived: 70,
+		},
+	}, stats)
+
+	// The snapshot must not be affected by later updates.
+	h.recordReceived(lnwire.MsgPing, 10)
+	require.EqualValues(t, 1, stats[0].Received)
+}
+
+// TestMsgHistogramUnknownTypes asserts that messages of types unknown to
+// lnwire, including custom messages, share a single histogram entry.
+func TestMsgHistogramUnknownTypes(t *testing.T) {
+	t.Parallel()
+
+	var h msgHistogram
+	h.recordReceived(lnwire.MessageType(3), 10)
+	h.recordReceived(lnwire.MessageType(1000), 20)
+	h.recordReceived(lnwire.CustomTypeStart, 30)
+	h.recordSent(lnwire.CustomTypeStart+1, 40)
+	h.recordReceived(lnwire.MsgPing, 10)
+
+	require.Equal(t, []MessageStats{
+		{
+			Type:          MsgTypeUnknown,
+			Sent:          1,
+			Received:      3,
+			BytesSent:     40,
+			BytesReceived: 60,
+		},
+		{
+			Type:          lnwire.MsgPing,
+			Received:      1,
+			BytesReceived: 10,
+		},
+	}, h.snapshot())
+}
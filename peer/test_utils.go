@@ -7,6 +7,7 @@ import (
 	"io"
 	"math/rand"
 	"net"
+	"sync"
 	"testing"
 	"time"
 
@@ -191,22 +192,24 @@ func createTestPeer(t *testing.T, notifier chainntnfs.ChainNotifier,
 		return nil, nil, err
 	}
 
-	// TODO(roasbeef): need to factor in commit fee?
+	// As the initiator, Alice pays the commitment fee.
+	commitFee := feePerKw.FeeForWeight(input.CommitWeight)
+	aliceBal := channelBal - commitFee
 	aliceCommit := channeldb.ChannelCommitment{
 		CommitHeight:  0,
-		LocalBalance:  lnwire.NewMSatFromSatoshis(channelBal),
+		LocalBalance:  lnwire.NewMSatFromSatoshis(aliceBal),
 		RemoteBalance: lnwire.NewMSatFromSatoshis(channelBal),
 		FeePerKw:      btcutil.Amount(feePerKw),
-		CommitFee:     feePerKw.FeeForWeight(input.CommitWeight),
+		CommitFee:     commitFee,
 		CommitTx:      aliceCommitTx,
 		CommitSig:     bytes.Repeat([]byte{1}, 71),
 	}
 	bobCommit := channeldb.ChannelCommitment{
 		CommitHeight:  0,
 		LocalBalance:  lnwire.NewMSatFromSatoshis(channelBal),
-		RemoteBalance: lnwire.NewMSatFromSatoshis(channelBal),
+		RemoteBalance: lnwire.NewMSatFromSatoshis(aliceBal),
 		FeePerKw:      btcutil.Amount(feePerKw),
-		CommitFee:     feePerKw.FeeForWeight(input.CommitWeight),
+		CommitFee:     commitFee,
 		CommitTx:      bobCommitTx,
 		CommitSig:     bytes.Repeat([]byte{1}, 71),
 	}
@@ -453,6 +456,10 @@ func (m *mockMessageSwitch) GetLinksByInterface(pub [33]byte) (
 // interface. It is used in mockMessageSwitch's GetLinksByInterface method.
 type mockUpdateHandler struct {
 	cid lnwire.ChannelID
+
+	mu           sync.Mutex
+	shutdownErr  error
+	addsDisabled bool
 }
 
 // newMockUpdateHandler creates a new mockUpdateHandler.
@@ -477,8 +484,68 @@ func (m *mockUpdateHandler) EligibleToForward() bool { return false }
 // MayAddOutgoingHtlc currently returns nil.
 func (m *mockUpdateHandler) MayAddOutgoingHtlc(lnwire.MilliSatoshi) error { return nil }
 
-// ShutdownIfChannelClean currently returns nil.
-func (m *mockUpdateHandler) ShutdownIfChannelClean() error { return nil }
+// ShutdownIfChannelClean returns the error set with setShutdownErr.
+func (m *mockUpdateHandler) ShutdownIfChannelClean() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.shutdownErr
+}
+
+// setShutdownErr sets the error returned by ShutdownIfChannelClean.
+func (m *mockUpdateHandler) setShutdownErr(err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.shutdownErr = err
+}
+
+// DisableAdds records that adds are disabled.
+func (m *mockUpdateHandler) DisableAdds() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.addsDisabled = true
+}
+
+// EnableAdds records that adds are enabled.
+func (m *mockUpdateHandler) EnableAdds() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.addsDisabled = false
+}
+
+// isAddsDisabled returns whether adds are currently disabled.
+func (m *mockUpdateHandler) isAddsDisabled() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.addsDisabled
+}
+
+// FeeExposureStats currently returns empty stats.
+func (m *mockUpdateHandler) FeeExposureStats() htlcswitch.FeeExposureStats {
+	return htlcswitch.FeeExposureStats{}
+}
+
+// Quiesce currently returns that quiescence isn't supported.
+func (m *mockUpdateHandler) Quiesce() (bool, error) {
+	return false, htlcswitch.ErrQuiescenceNotSupported
+}
+
+// Resume currently returns that the channel isn't quiescent.
+func (m *mockUpdateHandler) Resume() error {
+	return htlcswitch.ErrChannelNotQuiescent
+}
+
+// UpgradeCommitment currently returns that dynamic commitments aren't
+// supported.
+func (m *mockUpdateHandler) UpgradeCommitment(
+	*htlcswitch.CommitUpgrade) error {
+
+	return htlcswitch.ErrDynamicCommitmentsNotSupported
+}
 
 type mockMessageConn struct {
 	t *testing.T
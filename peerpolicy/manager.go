@@ -0,0 +1,552 @@
+package peerpolicy
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/clock"
+	"github.com/lightningnetwork/lnd/routing/route"
+)
+
+const (
+	// DefaultAutoBanThreshold is the default number of invalid messages a
+	// peer can send us within the auto ban duration before it's banned.
+	// Automatic bans are disabled by default.
+	DefaultAutoBanThreshold = 0
+
+	// DefaultAutoBanDuration is the default duration of the bans of peers
+	// that sent us too many invalid messages.
+	DefaultAutoBanDuration = 24 * time.Hour
+)
+
+var (
+	// ErrPeerBanned is returned when a connection is refused because
+	// either the public key or the IP address of the peer is banned.
+	ErrPeerBanned = errors.New("peer is banned")
+
+	// ErrNotAllowListed is returned when an inbound connection is refused
+	// because only allow-listed peers are accepted, and the peer isn't on
+	// the allow list.
+	ErrNotAllowListed = errors.New("peer is not on the allow list")
+
+	// ErrTooManyConnections is returned when an inbound connection is
+	// refused because we already have the maximum number of connections
+	// from the IP address of the peer.
+	ErrTooManyConnections = errors.New("too many connections from IP " +
+		"address")
+)
+
+// Store persists the lists of the peer connection policy.
+type Store interface {
+	// PutPeerPolicyEntry adds an entry to the given list, replacing any
+	// entry of the list with the same target.
+	PutPeerPolicyEntry(channeldb.PeerPolicyList,
+		*channeldb.PeerPolicyEntry) error
+
+	// DeletePeerPolicyEntry removes the entry with the same target as the
+	// given one from the list.
+	DeletePeerPolicyEntry(channeldb.PeerPolicyList,
+		*channeldb.PeerPolicyEntry) error
+
+	// FetchPeerPolicyEntries returns all entries of the given list.
+	FetchPeerPolicyEntries(channeldb.PeerPolicyList) (
+		[]*channeldb.PeerPolicyEntry, error)
+}
+
+// Config holds the configuration of the peer policy manager.
+type Config struct {
+	// Store persists the ban and allow lists.
+	Store Store
+
+	// AllowListOnly if true, only accepts inbound connections from peers
+	// that are on the allow list.
+	AllowListOnly bool
+
+	// MaxConnsPerIP is the maximum number of inbound connections we accept
+	// from a single IP address. Connections from loopback addresses, such
+	// as the ones forwarded by a local Tor daemon, and from allow-listed
+	// peers aren't limited. Zero disables the limit.
+	MaxConnsPerIP uint32
+
+	// AutoBanThreshold is the number of invalid messages a peer can send
+	// us within AutoBanDuration before it's banned automatically. Zero
+	// disables automatic bans.
+	AutoBanThreshold uint32
+
+	// AutoBanDuration is the duration of automatic bans, as well as the
+	// window in which invalid messages are counted towards one.
+	AutoBanDuration time.Duration
+
+	// HasChannels returns true if we have open or pending channels with
+	// the peer. Such peers are never banned automatically, as the ban
+	// would disconnect them and disrupt our channels. If nil, all peers
+	// that aren't allow-listed can be banned automatically.
+	HasChannels func(route.Vertex) (bool, error)
+
+	// Clock is the clock used to expire bans.
+	Clock clock.Clock
+}
+
+// entryList is a list of peer policy entries.
+type entryList []*channeldb.PeerPolicyEntry
+
+// misbehavior tracks the invalid messages a peer sent us.
+type misbehavior struct {
+	// count is the number of invalid messages received since the start
+	// of the current window.
+	count uint32
+
+	// since is the start of the current window.
+	since time.Time
+}
+
+// Manager enforces our peer connection policy. It decides which peers we
+// accept connections from based on the ban and allow lists, limits the
+// number of inbound connections per IP address and bans peers that keep
+// sending us invalid messages.
+type Manager struct {
+	cfg *Config
+
+	mu sync.Mutex
+
+	// lists holds the unexpired entries of the ban and allow lists.
+	lists map[channeldb.PeerPolicyList]entryList
+
+	// inbound holds the IP addresses of the peers connected to us.
+	inbound map[route.Vertex]net.IP
+
+	// misbehavior tracks the invalid messages of our peers.
+	misbehavior map[route.Vertex]*misbehavior
+}
+
+// NewManager creates a new peer policy manager, loading the persisted ban and
+// allow lists and pruning the entries that have expired since.
+func NewManager(cfg *Config) (*Manager, error) {
+	m := &Manager{
+		cfg:         cfg,
+		lists:       make(map[channeldb.PeerPolicyList]entryList),
+		inbound:     make(map[route.Vertex]net.IP),
+		misbehavior: make(map[route.Vertex]*misbehavior),
+	}
+
+	now := cfg.Clock.Now()
+	for _, list := range []channeldb.PeerPolicyList{
+		channeldb.PeerBanList, channeldb.PeerAllowList,
+	} {
+		entries, err := cfg.Store.FetchPeerPolicyEntries(list)
+		if err != nil {
+			return nil, fmt.Errorf("unable to fetch %v: %w", list,
+				err)
+		}
+
+		for _, entry := range entries {
+			if !entry.Expired(now) {
+				m.lists[list] = append(m.lists[list], entry)
+				continue
+			}
+
+			log.Debugf("Pruning expired %v entry for %v", list,
+				entry.Target())
+
+			err := cfg.Store.DeletePeerPolicyEntry(list, entry)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	log.Infof("Loaded %d bans and %d allow list entries, "+
+		"allow_list_only=%v", len(m.lists[channeldb.PeerBanList]),
+		len(m.lists[channeldb.PeerAllowList]), cfg.AllowListOnly)
+
+	return m, nil
+}
+
+// AllowListOnly returns true if only inbound connections from allow-listed
+// peers are accepted.
+func (m *Manager) AllowListOnly() bool {
+	return m.cfg.AllowListOnly
+}
+
+// AddEntry adds an entry to the given list, replacing any entry of the list
+// with the same target. If the creation time of the entry isn't set, it's set
+// to the current time.
+func (m *Manager) AddEntry(list channeldb.PeerPolicyList,
+	entry *channeldb.PeerPolicyEntry) error {
+
+	now := m.cfg.Clock.Now()
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = now
+	}
+	if entry.Expired(now) {
+		return fmt.Errorf("%v entry for %v already expired", list,
+			entry.Target())
+	}
+
+	if err := normalizeEntry(entry); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.addEntry(list, entry)
+}
+
+// addEntry persists the entry and adds it to the given list.
+//
+// NOTE: The mutex MUST be held when calling this method.
+func (m *Manager) addEntry(list channeldb.PeerPolicyList,
+	entry *channeldb.PeerPolicyEntry) error {
+
+	if err := m.cfg.Store.PutPeerPolicyEntry(list, entry); err != nil {
+		return err
+	}
+
+	m.removeEntry(list, entry)
+	m.lists[list] = append(m.lists[list], entry)
+
+	log.Infof("Added %v entry for %v, expiry=%v, reason=%q", list,
+		entry.Target(), entry.Expiry, entry.Reason)
+
+	return nil
+}
+
+// RemoveEntry removes the entry with the same target as the given one from
+// the list. If there is no such entry, channeldb.ErrPeerPolicyEntryNotFound
+// is returned.
+func (m *Manager) RemoveEntry(list channeldb.PeerPolicyList,
+	entry *channeldb.PeerPolicyEntry) error {
+
+	if err := normalizeEntry(entry); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.cfg.Store.DeletePeerPolicyEntry(list, entry); err != nil {
+		return err
+	}
+
+	m.removeEntry(list, entry)
+
+	log.Infof("Removed %v entry for %v", list, entry.Target())
+
+	return nil
+}
+
+// removeEntry removes the entry with the same target as the given one from
+// the in-memory list.
+//
+// NOTE: The mutex MUST be held when calling this method.
+func (m *Manager) removeEntry(list channeldb.PeerPolicyList,
+	entry *channeldb.PeerPolicyEntry) {
+
+	entries := m.lists[list][:0]
+	for _, e := range m.lists[list] {
+		if e.Target() != entry.Target() {
+			entries = append(entries, e)
+		}
+	}
+	m.lists[list] = entries
+}
+
+// Entries returns the unexpired entries of the given list, ordered by their
+// creation time.
+func (m *Manager) Entries(
+	list channeldb.PeerPolicyList) []*channeldb.PeerPolicyEntry {
+
+	now := m.cfg.Clock.Now()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var entries []*channeldb.PeerPolicyEntry
+	for _, entry := range m.lists[list] {
+		if !entry.Expired(now) {
+			e := *entry
+			entries = append(entries, &e)
+		}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].CreatedAt.Before(entries[j].CreatedAt)
+	})
+
+	return entries
+}
+
+// findEntry returns the first unexpired entry of the list that matches either
+// the peer or its IP address, which may be nil if it isn't known.
+//
+// NOTE: The mutex MUST be held when calling this method.
+func (m *Manager) findEntry(list channeldb.PeerPolicyList, peer route.Vertex,
+	ip net.IP, now time.Time) *channeldb.PeerPolicyEntry {
+
+	for _, entry := range m.lists[list] {
+		if entry.Expired(now) {
+			continue
+		}
+
+		switch {
+		case entry.Pubkey != nil && *entry.Pubkey == peer:
+			return entry
+
+		case entry.IPNet != nil && ip != nil &&
+			entry.IPNet.Contains(ip):
+
+			return entry
+		}
+	}
+
+	return nil
+}
+
+// checkBanned returns an error wrapping ErrPeerBanned if either the peer or
+// its IP address is banned.
+//
+// NOTE: The mutex MUST be held when calling this method.
+func (m *Manager) checkBanned(peer route.Vertex, ip net.IP,
+	now time.Time) error {
+
+	ban := m.findEntry(channeldb.PeerBanList, peer, ip, now)
+	if ban == nil {
+		return nil
+	}
+
+	expiry := "never"
+	if !ban.Expiry.IsZero() {
+		expiry = ban.Expiry.String()
+	}
+
+	return fmt.Errorf("%w: %v (reason=%q, expiry=%v)", ErrPeerBanned,
+		ban.Target(), ban.Reason, expiry)
+}
+
+// CheckBanned returns an error wrapping ErrPeerBanned if either the public key
+// of the peer or the given address of it is banned. The address may be nil if
+// it isn't known.
+func (m *Manager) CheckBanned(peer route.Vertex, addr net.Addr) error {
+	now := m.cfg.Clock.Now()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.checkBanned(peer, addrIP(addr), now)
+}
+
+// AcceptInbound returns nil if an inbound connection from the peer at the
+// given address should be accepted. Otherwise, an error wrapping the reason
+// of the refusal is returned.
+func (m *Manager) AcceptInbound(peer route.Vertex, addr net.Addr) error {
+	ip := addrIP(addr)
+	now := m.cfg.Clock.Now()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// Bans take precedence over the allow list, so a node can be banned
+	// even if it's part of an allow-listed IP range.
+	if err := m.checkBanned(peer, ip, now); err != nil {
+		return err
+	}
+
+	if m.findEntry(channeldb.PeerAllowList, peer, ip, now) != nil {
+		return nil
+	}
+
+	if m.cfg.AllowListOnly {
+		return ErrNotAllowListed
+	}
+
+	if m.cfg.MaxConnsPerIP == 0 || ip == nil || ip.IsLoopback() {
+		return nil
+	}
+
+	// The peer itself isn't counted, as a new connection of a connected
+	// peer replaces the existing one.
+	var numConns uint32
+	for p, connIP := range m.inbound {
+		if p != peer && connIP.Equal(ip) {
+			numConns++
+		}
+	}
+	if numConns >= m.cfg.MaxConnsPerIP {
+		return fmt.Errorf("%w: %v", ErrTooManyConnections, ip)
+	}
+
+	return nil
+}
+
+// PeerConnected records a new connection with the peer, so that inbound
+// connections count towards the connection limit of their IP address.
+func (m *Manager) PeerConnected(peer route.Vertex, addr net.Addr,
+	inbound bool) {
+
+	ip := addrIP(addr)
+	if !inbound || ip == nil {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.inbound[peer] = ip
+}
+
+// PeerDisconnected records that the connection with the peer was closed.
+func (m *Manager) PeerDisconnected(peer route.Vertex) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.inbound, peer)
+}
+
+// ReportMisbehavior records that the peer sent us an invalid message. Once a
+// peer sent us AutoBanThreshold invalid messages within AutoBanDuration, it's
+// banned for AutoBanDuration, in which case true is returned. Allow-listed
+// peers and peers we have channels with are never banned automatically.
+func (m *Manager) ReportMisbehavior(peer route.Vertex, reason error) (bool,
+	error) {
+
+	if m.cfg.AutoBanThreshold == 0 {
+		return false, nil
+	}
+
+	if m.cfg.HasChannels != nil {
+		hasChannels, err := m.cfg.HasChannels(peer)
+		if err != nil {
+			return false, err
+		}
+
+		if hasChannels {
+			log.Debugf("Not counting invalid message of peer %v "+
+				"we have channels with: %v", peer, reason)
+
+			return false, nil
+		}
+	}
+
+	now := m.cfg.Clock.Now()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ip := m.inbound[peer]
+	if m.findEntry(channeldb.PeerAllowList, peer, ip, now) != nil {
+		return false, nil
+	}
+
+	record, ok := m.misbehavior[peer]
+	if !ok || now.Sub(record.since) >= m.cfg.AutoBanDuration {
+		record = &misbehavior{since: now}
+		m.misbehavior[peer] = record
+	}
+	record.count++
+
+	log.Debugf("Peer %v sent invalid message (%d/%d): %v", peer,
+		record.count, m.cfg.AutoBanThreshold, reason)
+
+	if record.count < m.cfg.AutoBanThreshold {
+		return false, nil
+	}
+	delete(m.misbehavior, peer)
+
+	// Don't replace a ban of the peer that lasts longer than the one
+	// we're about to add.
+	expiry := now.Add(m.cfg.AutoBanDuration)
+	for _, ban := range m.lists[channeldb.PeerBanList] {
+		if ban.Pubkey == nil || *ban.Pubkey != peer {
+			continue
+		}
+
+		if ban.Expiry.IsZero() || ban.Expiry.After(expiry) {
+			return true, nil
+		}
+	}
+
+	err := m.addEntry(channeldb.PeerBanList, &channeldb.PeerPolicyEntry{
+		Pubkey: &peer,
+		Reason: fmt.Sprintf("sent %d invalid messages, last: %v",
+			record.count, reason),
+		CreatedAt: now,
+		Expiry:    expiry,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// ParseIPRange parses either a CIDR range or a single IP address into an IP
+// range.
+func ParseIPRange(s string) (*net.IPNet, error) {
+	if _, ipNet, err := net.ParseCIDR(s); err == nil {
+		return ipNet, nil
+	}
+
+	ip := net.ParseIP(s)
+	if ip == nil {
+		return nil, fmt.Errorf("invalid IP address or range: %v", s)
+	}
+
+	if ip4 := ip.To4(); ip4 != nil {
+		ip = ip4
+	}
+
+	return &net.IPNet{
+		IP:   ip,
+		Mask: net.CIDRMask(len(ip)*8, len(ip)*8),
+	}, nil
+}
+
+// normalizeEntry makes sure the IP range of the entry, if any, is in its
+// canonical form, so that entries for the same range have the same target.
+func normalizeEntry(entry *channeldb.PeerPolicyEntry) error {
+	if entry.IPNet == nil {
+		return nil
+	}
+
+	ip := entry.IPNet.IP.To4()
+	if ip == nil {
+		ip = entry.IPNet.IP.To16()
+	}
+
+	_, bits := entry.IPNet.Mask.Size()
+	if ip == nil || bits != len(ip)*8 {
+		return fmt.Errorf("invalid IP range: %v", entry.IPNet)
+	}
+
+	entry.IPNet = &net.IPNet{
+		IP:   ip.Mask(entry.IPNet.Mask),
+		Mask: entry.IPNet.Mask,
+	}
+
+	return nil
+}
+
+// addrIP returns the IP address of the given network address, or nil if the
+// address isn't an IP address, such as an onion address.
+func addrIP(addr net.Addr) net.IP {
+	switch a := addr.(type) {
+	case nil:
+		return nil
+
+	case *net.TCPAddr:
+		return a.IP
+	}
+
+	host, _, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		host = addr.String()
+	}
+
+	return net.ParseIP(host)
+}
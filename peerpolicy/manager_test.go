@@ -0,0 +1,256 @@
+package peerpolicy
+
+import (
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/clock"
+	"github.com/lightningnetwork/lnd/routing/route"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testTime = time.Unix(1000, 0)
+
+	peer1 = route.Vertex{1}
+	peer2 = route.Vertex{2}
+	peer3 = route.Vertex{3}
+
+	addr1 = &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 9735}
+	addr2 = &net.TCPAddr{IP: net.ParseIP("10.0.0.2"), Port: 9735}
+)
+
+// newTestManager creates a manager backed by a fresh database.
+func newTestManager(t *testing.T, cfg Config) (*Manager, *clock.TestClock) {
+	t.Helper()
+
+	db, err := channeldb.MakeTestDB(t)
+	require.NoError(t, err)
+
+	testClock := clock.NewTestClock(testTime)
+	cfg.Store = db
+	cfg.Clock = testClock
+
+	m, err := NewManager(&cfg)
+	require.NoError(t, err)
+
+	return m, testClock
+}
+
+// TestBans asserts that banned pubkeys and IP ranges are refused until their
+// bans expire, and that bans are persisted.
+func TestBans(t *testing.T) {
+	t.Parallel()
+
+	m, testClock := newTestManager(t, Config{})
+	require.NoError(t, m.AcceptInbound(peer1, addr1))
+
+	ipRange, err := ParseIPRange("10.0.0.0/24")
+	require.NoError(t, err)
+
+	require.NoError(t, m.AddEntry(channeldb.PeerBanList,
+		&channeldb.PeerPolicyEntry{
+			Pubkey: &peer1,
+			Expiry: testTime.Add(time.Hour),
+		},
+	))
+
+	err = m.AcceptInbound(peer1, nil)
+	require.ErrorIs(t, err, ErrPeerBanned)
+	require.ErrorIs(t, m.CheckBanned(peer1, nil), ErrPeerBanned)
+	require.NoError(t, m.AcceptInbound(peer2, addr2))
+
+	// A ban of an IP range applies to every peer connecting from it.
+	require.NoError(t, m.AddEntry(channeldb.PeerBanList,
+		&channeldb.PeerPolicyEntry{IPNet: ipRange},
+	))
+	require.ErrorIs(t, m.AcceptInbound(peer2, addr2), ErrPeerBanned)
+	require.NoError(t, m.AcceptInbound(peer2, nil))
+	require.Len(t, m.Entries(channeldb.PeerBanList), 2)
+
+	// Once the ban of the pubkey expires, the peer is accepted again, as
+	// long as it doesn't connect from the banned range.
+	testClock.SetTime(testTime.Add(time.Hour))
+	require.NoError(t, m.AcceptInbound(peer1, nil))
+	require.Len(t, m.Entries(channeldb.PeerBanList), 1)
+
+	// Bans are removed by their target, which doesn't depend on the
+	// address used to specify the range.
+	sameRange, err := ParseIPRange("10.0.0.7/24")
+	require.NoError(t, err)
+	require.NoError(t, m.RemoveEntry(channeldb.PeerBanList,
+		&channeldb.PeerPolicyEntry{IPNet: sameRange},
+	))
+	require.NoError(t, m.AcceptInbound(peer2, addr2))
+
+	err = m.RemoveEntry(channeldb.PeerBanList,
+		&channeldb.PeerPolicyEntry{IPNet: sameRange},
+	)
+	require.ErrorIs(t, err, channeldb.ErrPeerPolicyEntryNotFound)
+
+	// A ban of a single address is persisted and loaded by a new manager,
+	// while the expired ban of the pubkey is pruned.
+	single, err := ParseIPRange("10.0.0.2")
+	require.NoError(t, err)
+	require.NoError(t, m.AddEntry(channeldb.PeerBanList,
+		&channeldb.PeerPolicyEntry{IPNet: single, Reason: "spam"},
+	))
+
+	restarted, err := NewManager(m.cfg)
+	require.NoError(t, err)
+	require.ErrorIs(t, restarted.AcceptInbound(peer2, addr2), ErrPeerBanned)
+	require.NoError(t, restarted.AcceptInbound(peer1, addr1))
+
+	entries, err := m.cfg.Store.FetchPeerPolicyEntries(
+		channeldb.PeerBanList,
+	)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Equal(t, "spam", entries[0].Reason)
+}
+
+// TestAllowList asserts that only allow-listed peers are accepted in allow
+// list only mode, and that allow-listed peers aren't subject to the
+// connection limit.
+func TestAllowList(t *testing.T) {
+	t.Parallel()
+
+	m, _ := newTestManager(t, Config{
+		AllowListOnly: true,
+		MaxConnsPerIP: 1,
+	})
+
+	require.ErrorIs(t, m.AcceptInbound(peer1, addr1), ErrNotAllowListed)
+
+	require.NoError(t, m.AddEntry(channeldb.PeerAllowList,
+		&channeldb.PeerPolicyEntry{Pubkey: &peer1},
+	))
+	require.NoError(t, m.AcceptInbound(peer1, addr1))
+	m.PeerConnected(peer1, addr1, true)
+
+	allowed, err := ParseIPRange("10.0.0.0/8")
+	require.NoError(t, err)
+	require.NoError(t, m.AddEntry(channeldb.PeerAllowList,
+		&channeldb.PeerPolicyEntry{IPNet: allowed},
+	))
+	require.NoError(t, m.AcceptInbound(peer2, addr1))
+
+	// Bans take precedence over the allow list.
+	require.NoError(t, m.AddEntry(channeldb.PeerBanList,
+		&channeldb.PeerPolicyEntry{Pubkey: &peer2},
+	))
+	require.ErrorIs(t, m.AcceptInbound(peer2, addr1), ErrPeerBanned)
+}
+
+// TestMaxConnsPerIP asserts that the number of inbound connections from a
+// single IP address is limited.
+func TestMaxConnsPerIP(t *testing.T) {
+	t.Parallel()
+
+	m, _ := newTestManager(t, Config{MaxConnsPerIP: 2})
+
+	m.PeerConnected(peer1, addr1, true)
+	m.PeerConnected(peer2, addr1, true)
+
+	err := m.AcceptInbound(peer3, addr1)
+	require.ErrorIs(t, err, ErrTooManyConnections)
+
+	// Reconnections of connected peers, connections from other addresses
+	// and from loopback addresses aren't limited.
+	require.NoError(t, m.AcceptInbound(peer1, addr1))
+	require.NoError(t, m.AcceptInbound(peer3, addr2))
+
+	loopback := &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1)}
+	m.PeerConnected(peer1, loopback, true)
+	m.PeerConnected(peer2, loopback, true)
+	require.NoError(t, m.AcceptInbound(peer3, loopback))
+
+	// Outbound connections don't count towards the limit.
+	m.PeerDisconnected(peer1)
+	m.PeerDisconnected(peer2)
+	m.PeerConnected(peer1, addr1, false)
+	m.PeerConnected(peer2, addr1, true)
+	require.NoError(t, m.AcceptInbound(peer3, addr1))
+}
+
+// TestAutoBan asserts that peers sending too many invalid messages within the
+// auto ban duration are banned temporarily.
+func TestAutoBan(t *testing.T) {
+	t.Parallel()
+
+	m, testClock := newTestManager(t, Config{
+		AutoBanThreshold: 2,
+		AutoBanDuration:  time.Hour,
+	})
+	errInvalid := errors.New("invalid message")
+
+	// Invalid messages further apart than the auto ban duration don't
+	// lead to a ban.
+	banned, err := m.ReportMisbehavior(peer1, errInvalid)
+	require.NoError(t, err)
+	require.False(t, banned)
+
+	testClock.SetTime(testTime.Add(time.Hour))
+	banned, err = m.ReportMisbehavior(peer1, errInvalid)
+	require.NoError(t, err)
+	require.False(t, banned)
+
+	banned, err = m.ReportMisbehavior(peer1, errInvalid)
+	require.NoError(t, err)
+	require.True(t, banned)
+	require.ErrorIs(t, m.AcceptInbound(peer1, nil), ErrPeerBanned)
+
+	// The ban expires after the auto ban duration.
+	testClock.SetTime(testTime.Add(2 * time.Hour))
+	require.NoError(t, m.AcceptInbound(peer1, nil))
+
+	// A permanent ban isn't replaced by a temporary one.
+	require.NoError(t, m.AddEntry(channeldb.PeerBanList,
+		&channeldb.PeerPolicyEntry{Pubkey: &peer2},
+	))
+	for i := 0; i < 2; i++ {
+		_, err := m.ReportMisbehavior(peer2, errInvalid)
+		require.NoError(t, err)
+	}
+	bans := m.Entries(channeldb.PeerBanList)
+	require.Len(t, bans, 1)
+	require.True(t, bans[0].Expiry.IsZero())
+
+	// Allow-listed peers are never banned automatically.
+	require.NoError(t, m.AddEntry(channeldb.PeerAllowList,
+		&channeldb.PeerPolicyEntry{Pubkey: &peer3},
+	))
+	for i := 0; i < 3; i++ {
+		banned, err := m.ReportMisbehavior(peer3, errInvalid)
+		require.NoError(t, err)
+		require.False(t, banned)
+	}
+}
+
+// TestAutoBanChannelPeers asserts that peers we have channels with are never
+// banned automatically.
+func TestAutoBanChannelPeers(t *testing.T) {
+	t.Parallel()
+
+	m, _ := newTestManager(t, Config{
+		AutoBanThreshold: 1,
+		AutoBanDuration:  time.Hour,
+		HasChannels: func(peer route.Vertex) (bool, error) {
+			return peer == peer1, nil
+		},
+	})
+	errInvalid := errors.New("invalid message")
+
+	banned, err := m.ReportMisbehavior(peer1, errInvalid)
+	require.NoError(t, err)
+	require.False(t, banned)
+	require.NoError(t, m.AcceptInbound(peer1, nil))
+
+	banned, err = m.ReportMisbehavior(peer2, errInvalid)
+	require.NoError(t, err)
+	require.True(t, banned)
+	require.ErrorIs(t, m.AcceptInbound(peer2, nil), ErrPeerBanned)
+}
@@ -1,6 +1,12 @@
 package record
 
-import "fmt"
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/tlv"
+)
 
 const (
 	// CustomTypeStart is the start of the custom tlv type range as defined
@@ -22,3 +28,64 @@ func (c CustomSet) Validate() error {
 
 	return nil
 }
+
+// EncodeWireCustomRecords encodes a set of custom records as the tlv stream
+// that is appended to a wire message such as update_add_htlc.
+func EncodeWireCustomRecords(
+	records CustomSet) (lnwire.ExtraOpaqueData, error) {
+
+	if err := records.Validate(); err != nil {
+		return nil, err
+	}
+
+	tlvRecords := make([]tlv.Record, 0, len(records))
+	for recordType, value := range records {
+		value := value
+		tlvRecords = append(tlvRecords, tlv.MakePrimitiveRecord(
+			tlv.Type(recordType), &value,
+		))
+	}
+	tlv.SortRecords(tlvRecords)
+
+	stream, err := tlv.NewStream(tlvRecords...)
+	if err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+	if err := stream.Encode(&b); err != nil {
+		return nil, err
+	}
+
+	return b.Bytes(), nil
+}
+
+// ParseWireCustomRecords extracts the records in the custom type range from
+// the tlv stream that is appended to a wire message. Records of other types
+// are ignored. If there are no custom records, nil is returned.
+func ParseWireCustomRecords(
+	extraData lnwire.ExtraOpaqueData) (CustomSet, error) {
+
+	if len(extraData) == 0 {
+		return nil, nil
+	}
+
+	parsedTypes, err := extraData.ExtractRecords()
+	if err != nil {
+		return nil, err
+	}
+
+	var records CustomSet
+	for recordType, value := range parsedTypes {
+		if recordType < CustomTypeStart {
+			continue
+		}
+
+		if records == nil {
+			records = make(CustomSet)
+		}
+		records[uint64(recordType)] = value
+	}
+
+	return records, nil
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/record"
 	"github.com/lightningnetwork/lnd/tlv"
+	"github.com/stretchr/testify/require"
 )
 
 type recordEncDecTest struct {
@@ -97,3 +98,61 @@ func TestRecordEncodeDecode(t *testing.T) {
 		})
 	}
 }
+
+// TestWireCustomRecords asserts that custom records survive an encoding round
+// trip through the extra data of a wire message and that records outside of
+// the custom range are ignored when parsing.
+func TestWireCustomRecords(t *testing.T) {
+	records := record.CustomSet{
+		record.CustomTypeStart:     {1, 2, 3},
+		record.CustomTypeStart + 1: {},
+	}
+
+	extraData, err := record.EncodeWireCustomRecords(records)
+	require.NoError(t, err)
+
+	parsed, err := record.ParseWireCustomRecords(extraData)
+	require.NoError(t, err)
+	require.Equal(t, records, parsed)
+
+	// Records below the custom range can't be encoded.
+	_, err = record.EncodeWireCustomRecords(record.CustomSet{1: {1}})
+	require.Error(t, err)
+
+	// A stream that contains other records only yields the custom ones.
+	var mixed lnwire.ExtraOpaqueData
+	value := []byte{4}
+	customValue := []byte{5}
+	require.NoError(t, mixed.PackRecords(
+		&recordProducer{tlv.MakePrimitiveRecord(1, &value)},
+		&recordProducer{tlv.MakePrimitiveRecord(
+			record.CustomTypeStart, &customValue,
+		)},
+	))
+
+	parsed, err = record.ParseWireCustomRecords(mixed)
+	require.NoError(t, err)
+	require.Equal(t, record.CustomSet{
+		record.CustomTypeStart: customValue,
+	}, parsed)
+
+	// Empty extra data doesn't contain any records.
+	parsed, err = record.ParseWireCustomRecords(nil)
+	require.NoError(t, err)
+	require.Nil(t, parsed)
+
+	// Malformed extra data is rejected.
+	_, err = record.ParseWireCustomRecords(lnwire.ExtraOpaqueData{1})
+	require.Error(t, err)
+}
+
+// recordProducer wraps a tlv.Record so that it can be packed into the extra
+// data of a wire message.
+type recordProducer struct {
+	record tlv.Record
+}
+
+// Record returns the wrapped record.
+func (r *recordProducer) Record() tlv.Record {
+	return r.record
+}
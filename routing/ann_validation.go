@@ -9,6 +9,7 @@ import (
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/davecgh/go-spew/spew"
 	"github.com/go-errors/errors"
+	"github.com/lightningnetwork/lnd/input"
 	"github.com/lightningnetwork/lnd/lnwire"
 )
 
@@ -85,6 +86,56 @@ func ValidateChannelAnn(a *lnwire.ChannelAnnouncement) error {
 
 }
 
+// ValidateChannelAnn2 validates the taproot channel announcement message by
+// checking that its Schnorr signature is a valid signature over the
+// announcement by the MuSig2 aggregate of the node keys and, if present, the
+// bitcoin keys of the channel.
+func ValidateChannelAnn2(a *lnwire.ChannelAnnouncement2) error {
+	// The bitcoin keys must either both be present or both be absent.
+	if (a.BitcoinKey1 == nil) != (a.BitcoinKey2 == nil) {
+		return errors.New("channel announcement must include either " +
+			"both or none of the bitcoin keys")
+	}
+
+	rawKeys := [][33]byte{a.NodeID1, a.NodeID2}
+	if a.BitcoinKey1 != nil {
+		rawKeys = append(rawKeys, *a.BitcoinKey1, *a.BitcoinKey2)
+	}
+
+	keys := make([]*btcec.PublicKey, 0, len(rawKeys))
+	for _, rawKey := range rawKeys {
+		key, err := btcec.ParsePubKey(rawKey[:])
+		if err != nil {
+			return err
+		}
+		keys = append(keys, key)
+	}
+
+	// The keys are aggregated in the order they appear within the
+	// announcement, without sorting them first.
+	aggKey, err := input.MuSig2CombineKeys(
+		input.MuSig2Version100RC2, keys, false, &input.MuSig2Tweaks{},
+	)
+	if err != nil {
+		return err
+	}
+
+	digest, err := a.DigestToSign()
+	if err != nil {
+		return err
+	}
+
+	sig, err := a.Signature.ToSchnorrSignature()
+	if err != nil {
+		return err
+	}
+	if !sig.Verify(digest[:], aggKey.FinalKey) {
+		return errors.New("can't verify channel announcement signature")
+	}
+
+	return nil
+}
+
 // ValidateNodeAnn validates the node announcement by ensuring that the
 // attached signature is needed a signature of the node announcement under the
 // specified node public key.
@@ -0,0 +1,115 @@
+package routing
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr/musig2"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/stretchr/testify/require"
+)
+
+// signChannelAnn2 signs the taproot channel announcement with the MuSig2
+// aggregate of the given keys, in the given order.
+func signChannelAnn2(t *testing.T, ann *lnwire.ChannelAnnouncement2,
+	privKeys []*btcec.PrivateKey) {
+
+	t.Helper()
+
+	pubKeys := make([]*btcec.PublicKey, 0, len(privKeys))
+	for _, privKey := range privKeys {
+		pubKeys = append(pubKeys, privKey.PubKey())
+	}
+
+	digest, err := ann.DigestToSign()
+	require.NoError(t, err)
+
+	nonces := make([]*musig2.Nonces, 0, len(privKeys))
+	pubNonces := make([][musig2.PubNonceSize]byte, 0, len(privKeys))
+	for _, pubKey := range pubKeys {
+		nonce, err := musig2.GenNonces(musig2.WithPublicKey(pubKey))
+		require.NoError(t, err)
+
+		nonces = append(nonces, nonce)
+		pubNonces = append(pubNonces, nonce.PubNonce)
+	}
+
+	combinedNonce, err := musig2.AggregateNonces(pubNonces)
+	require.NoError(t, err)
+
+	partialSigs := make([]*musig2.PartialSignature, 0, len(privKeys))
+	for i, privKey := range privKeys {
+		partialSig, err := musig2.Sign(
+			nonces[i].SecNonce, privKey, combinedNonce, pubKeys,
+			*digest,
+		)
+		require.NoError(t, err)
+
+		partialSigs = append(partialSigs, partialSig)
+	}
+
+	sig := musig2.CombineSigs(partialSigs[0].R, partialSigs)
+	ann.Signature, err = lnwire.NewSigFromSchnorrRawSignature(
+		sig.Serialize(),
+	)
+	require.NoError(t, err)
+}
+
+// TestValidateChannelAnn2 asserts that taproot channel announcements are only
+// valid if they're signed by the aggregate of all the keys they include.
+func TestValidateChannelAnn2(t *testing.T) {
+	t.Parallel()
+
+	var privKeys []*btcec.PrivateKey
+	for i := 0; i < 4; i++ {
+		privKey, err := btcec.NewPrivateKey()
+		require.NoError(t, err)
+
+		privKeys = append(privKeys, privKey)
+	}
+
+	newAnn := func(withBitcoinKeys bool) *lnwire.ChannelAnnouncement2 {
+		ann := &lnwire.ChannelAnnouncement2{
+			ChainHash:       *chaincfg.MainNetParams.GenesisHash,
+			Features:        lnwire.NewRawFeatureVector(),
+			ShortChannelID:  lnwire.NewShortChanIDFromInt(1234),
+			Capacity:        100000,
+			ExtraOpaqueData: make([]byte, 0),
+		}
+		copy(ann.NodeID1[:], privKeys[0].PubKey().SerializeCompressed())
+		copy(ann.NodeID2[:], privKeys[1].PubKey().SerializeCompressed())
+
+		if withBitcoinKeys {
+			var key1, key2 [33]byte
+			copy(key1[:], privKeys[2].PubKey().SerializeCompressed())
+			copy(key2[:], privKeys[3].PubKey().SerializeCompressed())
+			ann.BitcoinKey1 = &key1
+			ann.BitcoinKey2 = &key2
+		}
+
+		return ann
+	}
+
+	// An announcement without bitcoin keys is signed by the node keys
+	// only.
+	ann := newAnn(false)
+	signChannelAnn2(t, ann, privKeys[:2])
+	require.NoError(t, ValidateChannelAnn2(ann))
+
+	// Changing any field invalidates the signature.
+	ann.Capacity++
+	require.Error(t, ValidateChannelAnn2(ann))
+
+	// An announcement with bitcoin keys must be signed by all four keys.
+	ann = newAnn(true)
+	signChannelAnn2(t, ann, privKeys[:2])
+	require.Error(t, ValidateChannelAnn2(ann))
+
+	signChannelAnn2(t, ann, privKeys)
+	require.NoError(t, ValidateChannelAnn2(ann))
+
+	// Including just one of the bitcoin keys is invalid.
+	ann.BitcoinKey2 = nil
+	require.Error(t, ValidateChannelAnn2(ann))
+}
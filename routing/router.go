@@ -23,7 +23,6 @@ import (
 	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/clock"
 	"github.com/lightningnetwork/lnd/htlcswitch"
-	"github.com/lightningnetwork/lnd/input"
 	"github.com/lightningnetwork/lnd/kvdb"
 	"github.com/lightningnetwork/lnd/lntypes"
 	"github.com/lightningnetwork/lnd/lnutils"
@@ -1522,16 +1521,10 @@ func (r *ChannelRouter) processUpdate(msg interface{},
 				"locate funding tx: %v", err)
 		}
 
-		// Recreate witness output to be sure that declared in channel
-		// edge bitcoin keys and channel value corresponds to the
-		// reality.
-		witnessScript, err := input.GenMultiSigScript(
-			msg.BitcoinKey1Bytes[:], msg.BitcoinKey2Bytes[:],
-		)
-		if err != nil {
-			return err
-		}
-		pkScript, err := input.WitnessScriptHash(witnessScript)
+		// Recreate the funding output script to be sure that declared
+		// in channel edge bitcoin keys and channel value corresponds to
+		// the reality.
+		pkScript, err := msg.FundingPkScript()
 		if err != nil {
 			return err
 		}
@@ -1561,12 +1554,8 @@ func (r *ChannelRouter) processUpdate(msg interface{},
 		// Now that we have the funding outpoint of the channel, ensure
 		// that it hasn't yet been spent. If so, then this channel has
 		// been closed so we'll ignore it.
-		fundingPkScript, err := input.WitnessScriptHash(witnessScript)
-		if err != nil {
-			return err
-		}
 		chanUtxo, err := r.cfg.Chain.GetUtxo(
-			fundingPoint, fundingPkScript, channelID.BlockHeight,
+			fundingPoint, pkScript, channelID.BlockHeight,
 			r.quit,
 		)
 		if err != nil {
@@ -1582,9 +1571,26 @@ func (r *ChannelRouter) processUpdate(msg interface{},
 				msg.ChannelID, fundingPoint, err)
 		}
 
+		// Unlike the legacy announcement, the v2 announcement commits
+		// to the capacity of the channel, so it must match the value of
+		// the funding output.
+		capacity := btcutil.Amount(chanUtxo.Value)
+		if msg.Version == channeldb.ChanAnnVersion2 &&
+			msg.Capacity != capacity {
+
+			if err := r.addZombieEdge(msg.ChannelID); err != nil {
+				return err
+			}
+
+			return newErrf(ErrInvalidFundingOutput, "announced "+
+				"capacity %v of chan_id=%v doesn't match "+
+				"funding output value %v", msg.Capacity,
+				msg.ChannelID, capacity)
+		}
+
 		// TODO(roasbeef): this is a hack, needs to be removed
 		// after commitment fees are dynamic.
-		msg.Capacity = btcutil.Amount(chanUtxo.Value)
+		msg.Capacity = capacity
 		msg.ChannelPoint = *fundingPoint
 		if err := r.cfg.Graph.AddChannelEdge(msg, op...); err != nil {
 			return errors.Errorf("unable to add edge: %v", err)
@@ -1603,7 +1609,7 @@ func (r *ChannelRouter) processUpdate(msg interface{},
 		// closed.
 		filterUpdate := []channeldb.EdgePoint{
 			{
-				FundingPkScript: fundingPkScript,
+				FundingPkScript: pkScript,
 				OutPoint:        *fundingPoint,
 			},
 		}
@@ -2502,6 +2508,68 @@ func (r *ChannelRouter) AddEdge(edge *channeldb.ChannelEdgeInfo,
 	}
 }
 
+// AddTrustedEdge adds a channel to the graph whose capacity and funding
+// outpoint are provided by a trusted source, such as a graph snapshot, rather
+// than being looked up on chain. Unless AssumeChannelValid is set, the funding
+// output is still added to the chain filter, so the channel is pruned from the
+// graph once it's closed.
+func (r *ChannelRouter) AddTrustedEdge(edge *channeldb.ChannelEdgeInfo) error {
+	_, _, exists, isZombie, err := r.cfg.Graph.HasChannelEdge(
+		edge.ChannelID,
+	)
+	if err != nil && err != channeldb.ErrGraphNoEdgesFound {
+		return errors.Errorf("unable to check for edge existence: %v",
+			err)
+	}
+	if isZombie {
+		return newErrf(ErrIgnored, "ignoring trusted edge for zombie "+
+			"chan_id=%v", edge.ChannelID)
+	}
+	if exists {
+		return newErrf(ErrIgnored, "ignoring trusted edge for known "+
+			"chan_id=%v", edge.ChannelID)
+	}
+
+	pkScript, err := edge.FundingPkScript()
+	if err != nil {
+		return err
+	}
+
+	err = r.cfg.Graph.AddChannelEdge(edge)
+	switch {
+	case err == channeldb.ErrEdgeAlreadyExist:
+		return newErrf(ErrIgnored, "ignoring trusted edge for known "+
+			"chan_id=%v", edge.ChannelID)
+
+	case err != nil:
+		return fmt.Errorf("unable to add edge: %w", err)
+	}
+
+	log.Tracef("Added trusted channel with ChannelPoint(%v): chan_id=%v, "+
+		"capacity=%v", edge.ChannelPoint, edge.ChannelID,
+		edge.Capacity)
+	r.stats.incNumEdgesDiscovered()
+
+	if r.cfg.AssumeChannelValid {
+		return nil
+	}
+
+	filterUpdate := []channeldb.EdgePoint{
+		{
+			FundingPkScript: pkScript,
+			OutPoint:        edge.ChannelPoint,
+		},
+	}
+	err = r.cfg.ChainView.UpdateFilter(
+		filterUpdate, atomic.LoadUint32(&r.bestHeight),
+	)
+	if err != nil {
+		return errors.Errorf("unable to update chain view: %v", err)
+	}
+
+	return nil
+}
+
 // UpdateEdge is used to update edge information, without this message edge
 // considered as not fully constructed.
 //
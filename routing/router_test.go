@@ -20,6 +20,7 @@ import (
 	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/clock"
 	"github.com/lightningnetwork/lnd/htlcswitch"
+	"github.com/lightningnetwork/lnd/input"
 	lnmock "github.com/lightningnetwork/lnd/lntest/mock"
 	"github.com/lightningnetwork/lnd/lntest/wait"
 	"github.com/lightningnetwork/lnd/lntypes"
@@ -3309,6 +3310,71 @@ func TestChannelOnChainRejectionZombie(t *testing.T) {
 	assertChanChainRejection(t, ctx, edge, ErrInvalidFundingOutput)
 }
 
+// TestChannelV2CapacityMismatch tests that a channel announced with a v2
+// announcement is rejected and marked as a zombie if its announced capacity
+// doesn't match the value of its funding output.
+func TestChannelV2CapacityMismatch(t *testing.T) {
+	t.Parallel()
+
+	ctx := createTestCtxSingleNode(t, 0)
+
+	const chanValue = btcutil.Amount(100000)
+	pkScript, err := input.GenTaprootFundingScript(
+		bitcoinKey1, bitcoinKey2, nil,
+	)
+	require.NoError(t, err)
+
+	key1 := bitcoinKey1.SerializeCompressed()
+	key2 := bitcoinKey2.SerializeCompressed()
+
+	newEdge := func(fundingHeight uint32,
+		capacity btcutil.Amount) *channeldb.ChannelEdgeInfo {
+
+		node1, err := createTestNode()
+		require.NoError(t, err)
+		node2, err := createTestNode()
+		require.NoError(t, err)
+
+		// Each funding transaction needs a unique txid, so we'll use
+		// the height as its lock time.
+		fundingTx := wire.NewMsgTx(2)
+		fundingTx.LockTime = fundingHeight
+		fundingTx.AddTxOut(wire.NewTxOut(int64(chanValue), pkScript))
+		ctx.chain.addUtxo(wire.OutPoint{
+			Hash: fundingTx.TxHash(),
+		}, fundingTx.TxOut[0])
+		ctx.chain.addBlock(&wire.MsgBlock{
+			Transactions: []*wire.MsgTx{fundingTx},
+		}, fundingHeight, fundingHeight)
+
+		chanID := lnwire.ShortChannelID{BlockHeight: fundingHeight}
+		edge := &channeldb.ChannelEdgeInfo{
+			ChannelID:     chanID.ToUint64(),
+			NodeKey1Bytes: node1.PubKeyBytes,
+			NodeKey2Bytes: node2.PubKeyBytes,
+			Capacity:      capacity,
+			Version:       channeldb.ChanAnnVersion2,
+		}
+		copy(edge.BitcoinKey1Bytes[:], key1)
+		copy(edge.BitcoinKey2Bytes[:], key2)
+
+		return edge
+	}
+
+	// An edge announcing a capacity below the value of the funding output
+	// is rejected.
+	edge := newEdge(1, chanValue-1)
+	assertChanChainRejection(t, ctx, edge, ErrInvalidFundingOutput)
+
+	// An edge announcing the correct capacity is added to the graph.
+	edge = newEdge(2, chanValue)
+	require.NoError(t, ctx.router.AddEdge(edge))
+
+	info, _, _, err := ctx.graph.FetchChannelEdgesByID(edge.ChannelID)
+	require.NoError(t, err)
+	require.Equal(t, chanValue, info.Capacity)
+}
+
 func createDummyTestGraph(t *testing.T) *testGraphInstance {
 	// Setup two simple channels such that we can mock sending along this
 	// route.
@@ -122,6 +122,22 @@ func (v *ValidationBarrier) InitJobDependencies(job interface{}) {
 			v.chanAnnFinSignal[msg.ShortChannelID] = signals
 			v.chanEdgeDependencies[msg.ShortChannelID] = signals
 
+			v.nodeAnnDependencies[route.Vertex(msg.NodeID1)] = signals
+			v.nodeAnnDependencies[route.Vertex(msg.NodeID2)] = signals
+		}
+	case *lnwire.ChannelAnnouncement2:
+
+		// Taproot channel announcements set up the same dependencies
+		// as the original ones.
+		if _, ok := v.chanAnnFinSignal[msg.ShortChannelID]; !ok {
+			signals := &validationSignals{
+				allow: make(chan struct{}),
+				deny:  make(chan struct{}),
+			}
+
+			v.chanAnnFinSignal[msg.ShortChannelID] = signals
+			v.chanEdgeDependencies[msg.ShortChannelID] = signals
+
 			v.nodeAnnDependencies[route.Vertex(msg.NodeID1)] = signals
 			v.nodeAnnDependencies[route.Vertex(msg.NodeID2)] = signals
 		}
@@ -219,6 +235,7 @@ func (v *ValidationBarrier) WaitForDependants(job interface{}) error {
 		// TODO(roasbeef): need to wait on chan ann?
 	case *channeldb.ChannelEdgeInfo:
 	case *lnwire.ChannelAnnouncement:
+	case *lnwire.ChannelAnnouncement2:
 	}
 
 	// Release the lock once the above read is finished.
@@ -285,6 +302,18 @@ func (v *ValidationBarrier) SignalDependants(job interface{}, allow bool) {
 			delete(v.chanAnnFinSignal, msg.ShortChannelID)
 		}
 
+		delete(v.chanEdgeDependencies, msg.ShortChannelID)
+	case *lnwire.ChannelAnnouncement2:
+		finSignals, ok := v.chanAnnFinSignal[msg.ShortChannelID]
+		if ok {
+			if allow {
+				close(finSignals.allow)
+			} else {
+				close(finSignals.deny)
+			}
+			delete(v.chanAnnFinSignal, msg.ShortChannelID)
+		}
+
 		delete(v.chanEdgeDependencies, msg.ShortChannelID)
 
 	// For all other job types, we'll delete the tracking entries from the
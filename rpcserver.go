@@ -43,6 +43,7 @@ import (
 	"github.com/lightningnetwork/lnd/discovery"
 	"github.com/lightningnetwork/lnd/feature"
 	"github.com/lightningnetwork/lnd/funding"
+	"github.com/lightningnetwork/lnd/graphsnapshot"
 	"github.com/lightningnetwork/lnd/htlcswitch"
 	"github.com/lightningnetwork/lnd/htlcswitch/hop"
 	"github.com/lightningnetwork/lnd/input"
@@ -65,6 +66,7 @@ import (
 	"github.com/lightningnetwork/lnd/macaroons"
 	"github.com/lightningnetwork/lnd/peer"
 	"github.com/lightningnetwork/lnd/peernotifier"
+	"github.com/lightningnetwork/lnd/peerpolicy"
 	"github.com/lightningnetwork/lnd/record"
 	"github.com/lightningnetwork/lnd/routing"
 	"github.com/lightningnetwork/lnd/routing/route"
@@ -325,6 +327,38 @@ func MainRPCServerPermissions() map[string][]bakery.Op {
 			Entity: "offchain",
 			Action: "write",
 		}},
+		"/lnrpc.Lightning/UpgradeChannelCommitment": {{
+			Entity: "offchain",
+			Action: "write",
+		}},
+		"/lnrpc.Lightning/UpdateLiquidityAd": {{
+			Entity: "onchain",
+			Action: "write",
+		}},
+		"/lnrpc.Lightning/RequestLease": {{
+			Entity: "offchain",
+			Action: "write",
+		}},
+		"/lnrpc.Lightning/ListLeases": {{
+			Entity: "offchain",
+			Action: "read",
+		}},
+		"/lnrpc.Lightning/BumpCloseFee": {{
+			Entity: "offchain",
+			Action: "write",
+		}},
+		"/lnrpc.Lightning/SimulateForceClose": {{
+			Entity: "offchain",
+			Action: "read",
+		}},
+		"/lnrpc.Lightning/ListHtlcDeadlines": {{
+			Entity: "offchain",
+			Action: "read",
+		}},
+		"/lnrpc.Lightning/SubscribeResolverProgress": {{
+			Entity: "offchain",
+			Action: "read",
+		}},
 		"/lnrpc.Lightning/GetInfo": {{
 			Entity: "info",
 			Action: "read",
@@ -337,6 +371,18 @@ func MainRPCServerPermissions() map[string][]bakery.Op {
 			Entity: "peers",
 			Action: "read",
 		}},
+		"/lnrpc.Lightning/AddPeerPolicyEntry": {{
+			Entity: "peers",
+			Action: "write",
+		}},
+		"/lnrpc.Lightning/RemovePeerPolicyEntry": {{
+			Entity: "peers",
+			Action: "write",
+		}},
+		"/lnrpc.Lightning/ListPeerPolicy": {{
+			Entity: "peers",
+			Action: "read",
+		}},
 		"/lnrpc.Lightning/WalletBalance": {{
 			Entity: "onchain",
 			Action: "read",
@@ -429,6 +475,18 @@ func MainRPCServerPermissions() map[string][]bakery.Op {
 			Entity: "info",
 			Action: "read",
 		}},
+		"/lnrpc.Lightning/GossipBudgetUsage": {{
+			Entity: "info",
+			Action: "read",
+		}},
+		"/lnrpc.Lightning/ExportGraphSnapshot": {{
+			Entity: "info",
+			Action: "read",
+		}},
+		"/lnrpc.Lightning/ImportGraphSnapshot": {{
+			Entity: "offchain",
+			Action: "write",
+		}},
 		"/lnrpc.Lightning/StopDaemon": {{
 			Entity: "info",
 			Action: "write",
@@ -489,6 +547,10 @@ func MainRPCServerPermissions() map[string][]bakery.Op {
 			Entity: "offchain",
 			Action: "read",
 		}},
+		"/lnrpc.Lightning/BackupSinkStatus": {{
+			Entity: "offchain",
+			Action: "read",
+		}},
 		"/lnrpc.Lightning/ChannelAcceptor": {{
 			Entity: "onchain",
 			Action: "write",
@@ -520,6 +582,10 @@ func MainRPCServerPermissions() map[string][]bakery.Op {
 			Entity: "peers",
 			Action: "read",
 		}},
+		"/lnrpc.Lightning/SubscribePeerStats": {{
+			Entity: "peers",
+			Action: "read",
+		}},
 		"/lnrpc.Lightning/FundingStateStep": {{
 			Entity: "onchain",
 			Action: "write",
@@ -714,7 +780,8 @@ func (r *rpcServer) addDeps(s *server, macService *macaroons.Service,
 		SetChannelDisabled: func(outpoint wire.OutPoint) error {
 			return s.chanStatusMgr.RequestDisable(outpoint, true)
 		},
-		SetChannelAuto: s.chanStatusMgr.RequestAuto,
+		SetChannelAuto:      s.chanStatusMgr.RequestAuto,
+		FetchRateLimitStats: s.htlcSwitch.RateLimitStats,
 	}
 
 	genInvoiceFeatures := func() *lnwire.FeatureVector {
@@ -742,11 +809,11 @@ func (r *rpcServer) addDeps(s *server, macService *macaroons.Service,
 		r.cfg, s.cc, r.cfg.networkDir, macService, atpl, invoiceRegistry,
 		s.htlcSwitch, r.cfg.ActiveNetParams.Params, s.chanRouter,
 		routerBackend, s.nodeSigner, s.graphDB, s.chanStateDB,
-		s.sweeper, tower, s.towerClient, s.anchorTowerClient,
-		r.cfg.net.ResolveTCPAddr, genInvoiceFeatures,
+		s.sweeper, s.anchorReserve, tower, s.towerClient,
+		s.anchorTowerClient, r.cfg.net.ResolveTCPAddr, genInvoiceFeatures,
 		genAmpInvoiceFeatures, s.getNodeAnnouncement,
-		s.updateAndBrodcastSelfNode, parseAddr, rpcsLog,
-		s.aliasMgr.GetPeerAlias,
+		s.updateAndBrodcastSelfNode, s.featureMgr.ListFeatures,
+		s.toggleFeatures, parseAddr, rpcsLog, s.aliasMgr.GetPeerAlias,
 	)
 	if err != nil {
 		return err
@@ -1538,6 +1605,16 @@ func (r *rpcServer) NewAddress(ctx context.Context,
 	return &lnrpc.NewAddressResponse{Address: addr.String()}, nil
 }
 
+const (
+	// defaultPeerStatsInterval is the interval in between peer stats
+	// updates if the client doesn't specify one.
+	defaultPeerStatsInterval = 5 * time.Second
+
+	// minPeerStatsInterval is the smallest interval in between peer stats
+	// updates a client may request.
+	minPeerStatsInterval = 100 * time.Millisecond
+)
+
 var (
 	// signedMsgPrefix is a special prefix that we'll prepend to any
 	// messages we sign/verify. We do this to ensure that we don't
@@ -2074,6 +2151,30 @@ func (r *rpcServer) parseOpenChannelReq(in *lnrpc.OpenChannelRequest,
 			err)
 	}
 
+	// An ordered list of acceptable channel types replaces the commitment
+	// type and its options.
+	var channelTypes []lnwire.ChannelType
+	if len(in.ChannelTypes) > 0 {
+		unknownType := lnrpc.CommitmentType_UNKNOWN_COMMITMENT_TYPE
+		if in.CommitmentType != unknownType || in.ZeroConf ||
+			in.ScidAlias || in.FundingShim != nil {
+
+			return nil, fmt.Errorf("channel types can't be " +
+				"combined with a commitment type, zero-conf, " +
+				"scid-alias or a funding shim")
+		}
+
+		for _, rpcChanType := range in.ChannelTypes {
+			chanType, err := lnrpc.UnmarshallChannelType(
+				rpcChanType,
+			)
+			if err != nil {
+				return nil, err
+			}
+			channelTypes = append(channelTypes, chanType)
+		}
+	}
+
 	var channelType *lnwire.ChannelType
 	switch in.CommitmentType {
 	case lnrpc.CommitmentType_UNKNOWN_COMMITMENT_TYPE:
@@ -2163,6 +2264,7 @@ func (r *rpcServer) parseOpenChannelReq(in *lnrpc.OpenChannelRequest,
 		MaxHtlcs:          maxHtlcs,
 		MaxLocalCsv:       uint16(in.MaxLocalCsv),
 		ChannelType:       channelType,
+		ChannelTypes:      channelTypes,
 		FundUpToMaxAmt:    fundUpToMaxAmt,
 		MinFundAmt:        minFundAmt,
 		Memo:              []byte(in.Memo),
@@ -2559,8 +2661,17 @@ func (r *rpcServer) CloseChannel(in *lnrpc.CloseChannelRequest,
 
 		// Before we attempt the cooperative channel closure, we'll
 		// examine the channel to ensure that it doesn't have a
-		// lingering HTLC.
-		if len(channel.ActiveHtlcs()) != 0 {
+		// lingering HTLC, unless we were asked to drain the channel
+		// first.
+		var drainTimeout time.Duration
+		if in.DrainHtlcs {
+			drainTimeout = peer.DefaultDrainTimeout
+			if in.DrainTimeoutSeconds != 0 {
+				drainTimeout = time.Duration(
+					in.DrainTimeoutSeconds,
+				) * time.Second
+			}
+		} else if len(channel.ActiveHtlcs()) != 0 {
 			return fmt.Errorf("cannot co-op close channel " +
 				"with active htlcs")
 		}
@@ -2594,7 +2705,7 @@ func (r *rpcServer) CloseChannel(in *lnrpc.CloseChannelRequest,
 		).FeePerKWeight()
 		updateChan, errChan = r.server.htlcSwitch.CloseLink(
 			chanPoint, contractcourt.CloseRegular, feeRate,
-			maxFee, deliveryScript,
+			maxFee, deliveryScript, drainTimeout,
 		)
 	}
 out:
@@ -2659,6 +2770,14 @@ func createRPCCloseUpdate(update interface{}) (
 				},
 			},
 		}, nil
+	case *peer.DrainUpdate:
+		return &lnrpc.CloseStatusUpdate{
+			Update: &lnrpc.CloseStatusUpdate_ChanDrain{
+				ChanDrain: &lnrpc.ChannelDrainUpdate{
+					NumPendingHtlcs: u.NumPendingHtlcs,
+				},
+			},
+		}, nil
 	}
 
 	return nil, errors.New("unknown close status update")
@@ -2724,6 +2843,241 @@ func (r *rpcServer) abandonChan(chanPoint *wire.OutPoint,
 	return nil
 }
 
+// SimulateForceClose performs a dry run of a force close of an active
+// channel. It works out how each of our outputs on the local commitment would
+// be resolved and estimates the fees needed to sweep them, without signing or
+// broadcasting anything.
+func (r *rpcServer) SimulateForceClose(_ context.Context,
+	in *lnrpc.SimulateForceCloseRequest) (*lnrpc.SimulateForceCloseResponse,
+	error) {
+
+	if in.ChannelPoint == nil {
+		return nil, fmt.Errorf("channel point must be set")
+	}
+
+	txid, err := lnrpc.GetChanPointFundingTxid(in.GetChannelPoint())
+	if err != nil {
+		return nil, err
+	}
+	chanPoint := wire.NewOutPoint(txid, in.ChannelPoint.OutputIndex)
+
+	channel, err := r.server.chanStateDB.FetchChannel(nil, *chanPoint)
+	if err != nil {
+		return nil, err
+	}
+
+	feeRate, err := lnrpc.CalculateFeeRate(
+		0, in.SatPerVbyte, uint32(in.TargetConf),
+		r.server.cc.FeeEstimator,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	_, bestHeight, err := r.server.cc.ChainIO.GetBestBlock()
+	if err != nil {
+		return nil, err
+	}
+
+	sim, err := contractcourt.SimulateForceClose(
+		channel, r.server.cc.Signer, uint32(bestHeight), feeRate,
+		r.server.witnessBeacon.LookupPreimage,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var closeTx bytes.Buffer
+	if err := sim.CloseTx.Serialize(&closeTx); err != nil {
+		return nil, err
+	}
+
+	resp := &lnrpc.SimulateForceCloseResponse{
+		CloseTx:           closeTx.Bytes(),
+		CloseTxid:         sim.CloseTx.TxHash().String(),
+		CommitFeeSat:      int64(sim.CommitFee),
+		SatPerVbyte:       uint64(sim.FeeRate.FeePerKVByte() / 1000),
+		ConfHeight:        sim.ConfHeight,
+		TotalSweepFeeSat:  int64(sim.TotalSweepFee),
+		TotalRecoveredSat: int64(sim.TotalRecovered),
+	}
+
+	for i := range sim.Outputs {
+		output := &sim.Outputs[i]
+
+		resolution := &lnrpc.SimulatedResolution{
+			Resolver:       output.Resolver,
+			Outpoint:       lnrpc.MarshalOutPoint(&output.OutPoint),
+			AmountSat:      int64(output.Amount),
+			SecondLevel:    output.SecondLevel,
+			CltvExpiry:     output.CltvExpiry,
+			CsvDelay:       output.CsvDelay,
+			MaturityHeight: output.MaturityHeight,
+			SweepFeeSat:    int64(output.SweepFee),
+			NetAmountSat:   int64(output.NetAmount),
+		}
+
+		switch output.ResolverType {
+		case channeldb.ResolverTypeAnchor:
+			resolution.ResolutionType = lnrpc.ResolutionType_ANCHOR
+
+		case channeldb.ResolverTypeIncomingHtlc:
+			resolution.ResolutionType =
+				lnrpc.ResolutionType_INCOMING_HTLC
+
+		case channeldb.ResolverTypeOutgoingHtlc:
+			resolution.ResolutionType =
+				lnrpc.ResolutionType_OUTGOING_HTLC
+
+		case channeldb.ResolverTypeCommit:
+			resolution.ResolutionType = lnrpc.ResolutionType_COMMIT
+		}
+
+		resp.Resolutions = append(resp.Resolutions, resolution)
+	}
+
+	return resp, nil
+}
+
+// BumpCloseFee replaces the closing transaction of a channel that is being
+// cooperatively closed with the simple close protocol with one paying a higher
+// fee rate. The new closing transaction is broadcast once the remote peer
+// signed it.
+func (r *rpcServer) BumpCloseFee(_ context.Context,
+	in *lnrpc.BumpCloseFeeRequest) (*lnrpc.BumpCloseFeeResponse, error) {
+
+	if in.ChannelPoint == nil {
+		return nil, fmt.Errorf("channel point must be set")
+	}
+
+	txid, err := lnrpc.GetChanPointFundingTxid(in.GetChannelPoint())
+	if err != nil {
+		return nil, err
+	}
+	chanPoint := wire.NewOutPoint(txid, in.ChannelPoint.OutputIndex)
+
+	channel, err := r.server.chanStateDB.FetchChannel(nil, *chanPoint)
+	if err != nil {
+		return nil, err
+	}
+
+	feeRate, err := lnrpc.CalculateFeeRate(
+		0, in.SatPerVbyte, uint32(in.TargetConf),
+		r.server.cc.FeeEstimator,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// The closing transaction can only be replaced while we're connected
+	// to the peer, as it needs to sign the new version.
+	peer, err := r.server.FindPeer(channel.IdentityPub)
+	if err != nil {
+		return nil, fmt.Errorf("unable to find peer of channel %v: %w",
+			chanPoint, err)
+	}
+
+	rpcsLog.Debugf("[bumpclosefee] bumping closing tx of "+
+		"ChannelPoint(%v) to fee rate %v", chanPoint, feeRate)
+
+	closingTxid, err := peer.BumpCloseFee(*chanPoint, feeRate)
+	if err != nil {
+		return nil, err
+	}
+
+	return &lnrpc.BumpCloseFeeResponse{
+		ClosingTxid: closingTxid.String(),
+		SatPerVbyte: uint64(feeRate.FeePerKVByte() / 1000),
+	}, nil
+}
+
+// ListHtlcDeadlines lists the HTLCs of open channels that are approaching the
+// height at which they'd trigger a force close of their channel, as determined
+// by the force close policy.
+func (r *rpcServer) ListHtlcDeadlines(_ context.Context,
+	in *lnrpc.ListHtlcDeadlinesRequest) (*lnrpc.ListHtlcDeadlinesResponse,
+	error) {
+
+	_, bestHeight, err := r.server.cc.ChainIO.GetBestBlock()
+	if err != nil {
+		return nil, err
+	}
+
+	channels, err := r.server.chanStateDB.FetchAllOpenChannels()
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &lnrpc.ListHtlcDeadlinesResponse{
+		BestHeight: uint32(bestHeight),
+	}
+	for _, channel := range channels {
+		peer := route.NewVertex(channel.IdentityPub)
+		chanPoint := channel.FundingOutpoint.String()
+
+		// An HTLC may be on either commitment, so we'll examine both
+		// while making sure to list each HTLC only once.
+		type htlcKey struct {
+			incoming bool
+			index    uint64
+		}
+		seen := make(map[htlcKey]struct{})
+
+		localHtlcs := channel.LocalCommitment.Htlcs
+		remoteHtlcs := channel.RemoteCommitment.Htlcs
+		htlcs := make(
+			[]channeldb.HTLC, 0, len(localHtlcs)+len(remoteHtlcs),
+		)
+		htlcs = append(htlcs, localHtlcs...)
+		htlcs = append(htlcs, remoteHtlcs...)
+		for _, htlc := range htlcs {
+			key := htlcKey{htlc.Incoming, htlc.HtlcIndex}
+			if _, ok := seen[key]; ok {
+				continue
+			}
+			seen[key] = struct{}{}
+
+			deadline := r.server.forceClosePolicy.Evaluate(
+				peer, htlc,
+			)
+
+			blocksLeft := int32(deadline.TriggerHeight) - bestHeight
+			if in.WithinBlocks != 0 &&
+				blocksLeft > int32(in.WithinBlocks) {
+
+				continue
+			}
+
+			rpcDeadline := &lnrpc.HtlcDeadline{
+				ChannelPoint:       chanPoint,
+				RemotePubkey:       peer.String(),
+				Incoming:           htlc.Incoming,
+				HtlcIndex:          htlc.HtlcIndex,
+				AmountSat:          int64(htlc.Amt.ToSatoshis()),
+				HashLock:           htlc.RHash[:],
+				ExpirationHeight:   htlc.RefundTimeout,
+				BroadcastDelta:     deadline.BroadcastDelta,
+				TriggerHeight:      deadline.TriggerHeight,
+				BlocksUntilTrigger: blocksLeft,
+				Exempt:             deadline.Exempt(),
+				OnChainCostSat:     int64(deadline.OnChainCost),
+			}
+			if deadline.Exempt() {
+				rpcDeadline.ExemptReason =
+					deadline.ExemptReason.String()
+			}
+
+			resp.Htlcs = append(resp.Htlcs, rpcDeadline)
+		}
+	}
+
+	sort.Slice(resp.Htlcs, func(i, j int) bool {
+		return resp.Htlcs[i].TriggerHeight < resp.Htlcs[j].TriggerHeight
+	})
+
+	return resp, nil
+}
+
 // AbandonChannel removes all channel state from the database except for a
 // close summary. This method can be used to get rid of permanently unusable
 // channels due to bugs fixed in newer versions of lnd.
@@ -2810,6 +3164,257 @@ func (r *rpcServer) AbandonChannel(_ context.Context,
 	return &lnrpc.AbandonChannelResponse{}, nil
 }
 
+// UpgradeChannelCommitment upgrades the commitment format and parameters of an
+// open channel using the dynamic commitments protocol.
+func (r *rpcServer) UpgradeChannelCommitment(_ context.Context,
+	in *lnrpc.UpgradeChannelCommitmentRequest) (
+	*lnrpc.UpgradeChannelCommitmentResponse, error) {
+
+	if in.ChannelPoint == nil {
+		return nil, fmt.Errorf("channel point must be set")
+	}
+
+	txid, err := lnrpc.GetChanPointFundingTxid(in.GetChannelPoint())
+	if err != nil {
+		return nil, err
+	}
+	chanPoint := wire.NewOutPoint(txid, in.ChannelPoint.OutputIndex)
+
+	channel, err := r.server.chanStateDB.FetchChannel(nil, *chanPoint)
+	if err != nil {
+		return nil, err
+	}
+
+	upgrade := &htlcswitch.CommitUpgrade{}
+	if in.CommitmentType != lnrpc.CommitmentType_UNKNOWN_COMMITMENT_TYPE {
+		var features *lnwire.RawFeatureVector
+		switch in.CommitmentType {
+		case lnrpc.CommitmentType_STATIC_REMOTE_KEY:
+			features = lnwire.NewRawFeatureVector(
+				lnwire.StaticRemoteKeyRequired,
+			)
+
+		case lnrpc.CommitmentType_ANCHORS:
+			features = lnwire.NewRawFeatureVector(
+				lnwire.StaticRemoteKeyRequired,
+				lnwire.AnchorsZeroFeeHtlcTxRequired,
+			)
+
+		default:
+			return nil, fmt.Errorf("unable to upgrade to "+
+				"commitment type %v", in.CommitmentType)
+		}
+
+		chanType, err := lnwallet.UpgradedChannelType(
+			channel.ChanType, lnwire.ChannelType(*features),
+		)
+		if err != nil {
+			return nil, err
+		}
+		upgrade.ChanType = &chanType
+	}
+	if in.LocalDustLimitSat != 0 {
+		dustLimit := btcutil.Amount(in.LocalDustLimitSat)
+		upgrade.LocalDustLimit = &dustLimit
+	}
+	if in.RemoteCsvDelay != 0 {
+		if in.RemoteCsvDelay > math.MaxUint16 {
+			return nil, fmt.Errorf("remote csv delay too large")
+		}
+		csvDelay := uint16(in.RemoteCsvDelay)
+		upgrade.RemoteCsvDelay = &csvDelay
+	}
+	if in.RemoteMaxAcceptedHtlcs != 0 {
+		if in.RemoteMaxAcceptedHtlcs > math.MaxUint16 {
+			return nil, fmt.Errorf("remote max accepted htlcs " +
+				"too large")
+		}
+		maxHtlcs := uint16(in.RemoteMaxAcceptedHtlcs)
+		upgrade.RemoteMaxAcceptedHtlcs = &maxHtlcs
+	}
+
+	// The upgrade is negotiated by the link of the channel, so the peer
+	// must be online.
+	chanID := lnwire.NewChanIDFromOutPoint(chanPoint)
+	link, err := r.server.htlcSwitch.GetLink(chanID)
+	if err != nil {
+		return nil, fmt.Errorf("unable to find link of channel %v: %w",
+			chanPoint, err)
+	}
+
+	rpcsLog.Debugf("[upgradechannel] upgrading commitment of "+
+		"ChannelPoint(%v)", chanPoint)
+
+	if err := link.UpgradeCommitment(upgrade); err != nil {
+		return nil, err
+	}
+
+	return &lnrpc.UpgradeChannelCommitmentResponse{}, nil
+}
+
+// UpdateLiquidityAd replaces the liquidity ad advertised in our node
+// announcement, or removes it to stop selling channel leases.
+func (r *rpcServer) UpdateLiquidityAd(_ context.Context,
+	in *lnrpc.UpdateLiquidityAdRequest) (*lnrpc.UpdateLiquidityAdResponse,
+	error) {
+
+	var ad *lnwire.LiquidityAd
+	switch {
+	case in.Disable && in.Ad != nil:
+		return nil, fmt.Errorf("cannot set a liquidity ad and " +
+			"disable it at the same time")
+
+	case in.Disable:
+
+	case in.Ad == nil:
+		return nil, fmt.Errorf("liquidity ad must be set unless " +
+			"disabled")
+
+	default:
+		ad = &lnwire.LiquidityAd{
+			LeaseDuration: in.Ad.LeaseDuration,
+			FeeBase:       btcutil.Amount(in.Ad.FeeBaseSat),
+			FeeRate:       in.Ad.FeeRatePpm,
+			MinLeaseAmt:   btcutil.Amount(in.Ad.MinLeaseSat),
+			MaxLeaseAmt:   btcutil.Amount(in.Ad.MaxLeaseSat),
+		}
+	}
+
+	if err := r.server.updateLiquidityAd(ad); err != nil {
+		return nil, err
+	}
+
+	return &lnrpc.UpdateLiquidityAdResponse{}, nil
+}
+
+// RequestLease requests a channel lease from a connected peer advertising a
+// liquidity ad, returning the offer of the peer including the invoice for the
+// lease fee.
+func (r *rpcServer) RequestLease(_ context.Context,
+	in *lnrpc.RequestLeaseRequest) (*lnrpc.RequestLeaseResponse, error) {
+
+	vertex, err := route.NewVertexFromStr(in.NodePubkey)
+	if err != nil {
+		return nil, err
+	}
+
+	// The terms of the lease are taken from the liquidity ad of the peer
+	// as we know it from its latest node announcement.
+	node, err := r.server.graphDB.FetchLightningNode(vertex)
+	switch {
+	case err == channeldb.ErrGraphNodeNotFound:
+		return nil, funding.ErrNoLiquidityAd
+
+	case err != nil:
+		return nil, err
+	}
+
+	ad, err := lnwire.ParseLiquidityAd(node.ExtraOpaqueData)
+	if err != nil {
+		return nil, err
+	}
+	if ad == nil {
+		return nil, funding.ErrNoLiquidityAd
+	}
+
+	leaseAmt := btcutil.Amount(in.LeaseSat)
+	if leaseAmt < ad.MinLeaseAmt || leaseAmt > ad.MaxLeaseAmt {
+		return nil, fmt.Errorf("lease amount %v outside of the range "+
+			"[%v, %v] advertised by the peer", leaseAmt,
+			ad.MinLeaseAmt, ad.MaxLeaseAmt)
+	}
+
+	duration := in.LeaseDuration
+	if duration == 0 {
+		duration = ad.LeaseDuration
+	}
+
+	maxFee := btcutil.Amount(in.MaxFeeSat)
+	if maxFee == 0 {
+		maxFee = ad.Fee(leaseAmt)
+	}
+
+	pubKey, err := btcec.ParsePubKey(vertex[:])
+	if err != nil {
+		return nil, err
+	}
+	peer, err := r.server.FindPeer(pubKey)
+	if err != nil {
+		return nil, err
+	}
+
+	lease, err := r.server.fundingMgr.RequestLease(
+		peer, leaseAmt, duration, maxFee,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &lnrpc.RequestLeaseResponse{
+		LeaseId:           hex.EncodeToString(lease.LeaseID[:]),
+		FeeSat:            int64(lease.Fee),
+		LeaseExpiryHeight: lease.LeaseExpiry,
+		PaymentRequest:    lease.PaymentRequest,
+	}, nil
+}
+
+// ListLeases returns the liquidity ad we advertise along with the channel
+// leases we bought or sold.
+func (r *rpcServer) ListLeases(_ context.Context,
+	_ *lnrpc.ListLeasesRequest) (*lnrpc.ListLeasesResponse, error) {
+
+	channels, err := r.server.chanStateDB.FetchAllOpenChannels()
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &lnrpc.ListLeasesResponse{
+		LiquidityAd: marshalLiquidityAd(r.server.currentLiquidityAd()),
+	}
+
+	// Leases are sold by opening a lease channel, so the initiator of a
+	// lease channel is always the seller.
+	for _, channel := range channels {
+		if !channel.ChanType.HasLeaseExpiration() {
+			continue
+		}
+
+		lease := &lnrpc.ActiveLease{
+			ChannelPoint: channel.FundingOutpoint.String(),
+			RemotePubkey: hex.EncodeToString(
+				channel.IdentityPub.SerializeCompressed(),
+			),
+			LeaseSat:          int64(channel.Capacity),
+			LeaseExpiryHeight: channel.ThawHeight,
+			Sold:              channel.IsInitiator,
+		}
+		resp.ActiveLeases = append(resp.ActiveLeases, lease)
+	}
+
+	for _, lease := range r.server.fundingMgr.PendingLeases() {
+		rpcLease := &lnrpc.PendingLease{
+			LeaseId: hex.EncodeToString(lease.LeaseID[:]),
+			RemotePubkey: hex.EncodeToString(
+				lease.Peer.SerializeCompressed(),
+			),
+			Sold:              lease.Sold,
+			LeaseSat:          int64(lease.LeaseAmt),
+			LeaseDuration:     lease.LeaseDuration,
+			FeeSat:            int64(lease.Fee),
+			LeaseExpiryHeight: lease.LeaseExpiry,
+			PaymentRequest:    lease.PaymentRequest,
+			Paid:              lease.Paid,
+		}
+		if lease.OpenErr != nil {
+			rpcLease.OpenError = lease.OpenErr.Error()
+		}
+
+		resp.PendingLeases = append(resp.PendingLeases, rpcLease)
+	}
+
+	return resp, nil
+}
+
 // GetInfo returns general information concerning the lightning node including
 // its identity pubkey, alias, the chains it is connected to, and information
 // concerning the number of open+pending channels.
@@ -2997,17 +3602,10 @@ func (r *rpcServer) ListPeers(ctx context.Context,
 				nodePub)
 			lnrpcSyncType = lnrpc.Peer_UNKNOWN_SYNC
 		} else {
-			syncType := syncer.SyncType()
-			switch syncType {
-			case discovery.ActiveSync:
-				lnrpcSyncType = lnrpc.Peer_ACTIVE_SYNC
-			case discovery.PassiveSync:
-				lnrpcSyncType = lnrpc.Peer_PASSIVE_SYNC
-			case discovery.PinnedSync:
-				lnrpcSyncType = lnrpc.Peer_PINNED_SYNC
-			default:
-				return nil, fmt.Errorf("unhandled sync type %v",
-					syncType)
+			var err error
+			lnrpcSyncType, err = marshallSyncType(syncer.SyncType())
+			if err != nil {
+				return nil, err
 			}
 		}
 
@@ -3029,6 +3627,11 @@ func (r *rpcServer) ListPeers(ctx context.Context,
 			LastPingPayload: serverPeer.LastRemotePingPayload(),
 		}
 
+		if in.IncludeStats {
+			stats := marshallPeerTrafficStats(serverPeer)
+			rpcPeer.TrafficStats = stats
+		}
+
 		var peerErrors []interface{}
 
 		// If we only want the most recent error, get the most recent
@@ -3065,82 +3668,375 @@ func (r *rpcServer) ListPeers(ctx context.Context,
 				return nil, err
 			}
 
-			flap, ts, err := r.server.chanEventStore.FlapCount(
-				vertex,
-			)
-			if err != nil {
-				return nil, err
-			}
+			flap, ts, err := r.server.chanEventStore.FlapCount(
+				vertex,
+			)
+			if err != nil {
+				return nil, err
+			}
+
+			// If our timestamp is non-nil, we have values for our
+			// peer's flap count, so we set them.
+			if ts != nil {
+				rpcPeer.FlapCount = int32(flap)
+				rpcPeer.LastFlapNs = ts.UnixNano()
+			}
+		}
+
+		resp.Peers = append(resp.Peers, rpcPeer)
+	}
+
+	rpcsLog.Debugf("[listpeers] yielded %v peers", serverPeers)
+
+	return resp, nil
+}
+
+// SubscribePeerEvents returns a uni-directional stream (server -> client)
+// for notifying the client of peer online and offline events.
+func (r *rpcServer) SubscribePeerEvents(req *lnrpc.PeerEventSubscription,
+	eventStream lnrpc.Lightning_SubscribePeerEventsServer) error {
+
+	peerEventSub, err := r.server.peerNotifier.SubscribePeerEvents()
+	if err != nil {
+		return err
+	}
+	defer peerEventSub.Cancel()
+
+	for {
+		select {
+		// A new update has been sent by the peer notifier, we'll
+		// marshal it into the form expected by the gRPC client, then
+		// send it off to the client.
+		case e := <-peerEventSub.Updates():
+			var event *lnrpc.PeerEvent
+
+			switch peerEvent := e.(type) {
+			case peernotifier.PeerOfflineEvent:
+				event = &lnrpc.PeerEvent{
+					PubKey: hex.EncodeToString(peerEvent.PubKey[:]),
+					Type:   lnrpc.PeerEvent_PEER_OFFLINE,
+				}
+
+			case peernotifier.PeerOnlineEvent:
+				event = &lnrpc.PeerEvent{
+					PubKey: hex.EncodeToString(peerEvent.PubKey[:]),
+					Type:   lnrpc.PeerEvent_PEER_ONLINE,
+				}
+
+			default:
+				return fmt.Errorf("unexpected peer event: %v", event)
+			}
+
+			if err := eventStream.Send(event); err != nil {
+				return err
+			}
+
+		// The response stream's context for whatever reason has been
+		// closed. If context is closed by an exceeded deadline we will
+		// return an error.
+		case <-eventStream.Context().Done():
+			if errors.Is(eventStream.Context().Err(), context.Canceled) {
+				return nil
+			}
+			return eventStream.Context().Err()
+
+		case <-r.quit:
+			return nil
+		}
+	}
+}
+
+// marshallPeerTrafficStats returns the detailed traffic statistics of a peer.
+func marshallPeerTrafficStats(p *peer.Brontide) *lnrpc.PeerTrafficStats {
+	msgStats := p.MessageStats()
+	queueDepths := p.QueueDepths()
+
+	stats := &lnrpc.PeerTrafficStats{
+		Messages: make(
+			[]*lnrpc.MessageTypeStats, 0, len(msgStats),
+		),
+		PendingPriorityMsgs: uint32(queueDepths.Priority),
+		PendingLazyMsgs:     uint32(queueDepths.Lazy),
+	}
+	for _, m := range msgStats {
+		stats.Messages = append(stats.Messages, &lnrpc.MessageTypeStats{
+			MsgType:       uint32(m.Type),
+			MsgName:       m.Type.String(),
+			Sent:          m.Sent,
+			Received:      m.Received,
+			BytesSent:     m.BytesSent,
+			BytesReceived: m.BytesReceived,
+		})
+	}
+
+	return stats
+}
+
+// SubscribePeerStats returns a uni-directional stream (server -> client) over
+// which the traffic statistics of the connected peers are sent periodically.
+func (r *rpcServer) SubscribePeerStats(req *lnrpc.PeerStatsSubscription,
+	updateStream lnrpc.Lightning_SubscribePeerStatsServer) error {
+
+	interval := defaultPeerStatsInterval
+	if req.IntervalMs != 0 {
+		interval = time.Duration(req.IntervalMs) * time.Millisecond
+	}
+	if interval < minPeerStatsInterval {
+		return fmt.Errorf("interval must be at least %v",
+			minPeerStatsInterval)
+	}
+
+	// If the client is only interested in some peers, we'll only send
+	// updates for those.
+	var filter map[route.Vertex]struct{}
+	if len(req.PubKeys) > 0 {
+		filter = make(map[route.Vertex]struct{}, len(req.PubKeys))
+		for _, pubKey := range req.PubKeys {
+			vertex, err := route.NewVertexFromStr(pubKey)
+			if err != nil {
+				return fmt.Errorf("invalid pub key %v: %w",
+					pubKey, err)
+			}
+			filter[vertex] = struct{}{}
+		}
+	}
+
+	// lastErrors tracks the timestamp of the last error sent for each
+	// peer, so that every stored error is only sent once.
+	lastErrors := make(map[route.Vertex]time.Time)
+
+	sendUpdates := func() error {
+		now := time.Now()
+		for _, serverPeer := range r.server.Peers() {
+			vertex := route.Vertex(serverPeer.PubKey())
+			if filter != nil {
+				if _, ok := filter[vertex]; !ok {
+					continue
+				}
+			}
+
+			stats := marshallPeerTrafficStats(serverPeer)
+			update := &lnrpc.PeerStatsUpdate{
+				PubKey:       hex.EncodeToString(vertex[:]),
+				Timestamp:    now.Unix(),
+				BytesSent:    serverPeer.BytesSent(),
+				BytesRecv:    serverPeer.BytesReceived(),
+				PingTime:     serverPeer.PingTime(),
+				TrafficStats: stats,
+			}
+
+			lastErr := lastErrors[vertex]
+			for _, e := range serverPeer.ErrorBuffer().List() {
+				tsErr := e.(*peer.TimestampedError)
+				errTime := tsErr.Timestamp
+				if !errTime.After(lastErr) {
+					continue
+				}
+				lastErrors[vertex] = errTime
+
+				rpcErr := &lnrpc.TimestampedError{
+					Timestamp: uint64(errTime.Unix()),
+					Error:     tsErr.Error.Error(),
+				}
+				update.NewErrors = append(
+					update.NewErrors, rpcErr,
+				)
+			}
+
+			if err := updateStream.Send(update); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := sendUpdates(); err != nil {
+			return err
+		}
+
+		select {
+		case <-ticker.C:
+
+		// The response stream's context for whatever reason has been
+		// closed. If context is closed by an exceeded deadline we will
+		// return an error.
+		case <-updateStream.Context().Done():
+			err := updateStream.Context().Err()
+			if errors.Is(err, context.Canceled) {
+				return nil
+			}
+			return err
+
+		case <-r.quit:
+			return nil
+		}
+	}
+}
+
+// parsePeerPolicyEntry parses the target of a peer policy entry, which is
+// either the pubkey of a node or a range of IP addresses.
+func parsePeerPolicyEntry(pubKey,
+	ipRange string) (*channeldb.PeerPolicyEntry, error) {
+
+	switch {
+	case pubKey != "" && ipRange != "":
+		return nil, errors.New("only one of pub_key and ip_range " +
+			"can be set")
+
+	case pubKey != "":
+		vertex, err := route.NewVertexFromStr(pubKey)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pub_key: %w", err)
+		}
+
+		return &channeldb.PeerPolicyEntry{Pubkey: &vertex}, nil
+
+	case ipRange != "":
+		ipNet, err := peerpolicy.ParseIPRange(ipRange)
+		if err != nil {
+			return nil, err
+		}
+
+		return &channeldb.PeerPolicyEntry{IPNet: ipNet}, nil
+
+	default:
+		return nil, errors.New("either pub_key or ip_range must be set")
+	}
+}
+
+// unmarshallPeerPolicyList converts an RPC peer policy list to its channeldb
+// counterpart.
+func unmarshallPeerPolicyList(
+	list lnrpc.PeerPolicyList) (channeldb.PeerPolicyList, error) {
+
+	switch list {
+	case lnrpc.PeerPolicyList_BAN_LIST:
+		return channeldb.PeerBanList, nil
+
+	case lnrpc.PeerPolicyList_ALLOW_LIST:
+		return channeldb.PeerAllowList, nil
+
+	default:
+		return 0, fmt.Errorf("unknown peer policy list: %v", list)
+	}
+}
+
+// marshallPeerPolicyEntries converts peer policy entries to their RPC
+// counterpart.
+func marshallPeerPolicyEntries(
+	entries []*channeldb.PeerPolicyEntry) []*lnrpc.PeerPolicyEntry {
+
+	rpcEntries := make([]*lnrpc.PeerPolicyEntry, 0, len(entries))
+	for _, entry := range entries {
+		rpcEntry := &lnrpc.PeerPolicyEntry{
+			Reason:    entry.Reason,
+			CreatedAt: entry.CreatedAt.Unix(),
+		}
+		if entry.Pubkey != nil {
+			rpcEntry.PubKey = entry.Pubkey.String()
+		}
+		if entry.IPNet != nil {
+			rpcEntry.IpRange = entry.IPNet.String()
+		}
+		if !entry.Expiry.IsZero() {
+			rpcEntry.Expiry = entry.Expiry.Unix()
+		}
+
+		rpcEntries = append(rpcEntries, rpcEntry)
+	}
+
+	return rpcEntries
+}
+
+// AddPeerPolicyEntry adds a node or a range of IP addresses to either the ban
+// list or the allow list of the peer connection policy. Connected peers that
+// are banned as a result are disconnected.
+func (r *rpcServer) AddPeerPolicyEntry(_ context.Context,
+	in *lnrpc.AddPeerPolicyEntryRequest) (*lnrpc.AddPeerPolicyEntryResponse,
+	error) {
+
+	list, err := unmarshallPeerPolicyList(in.List)
+	if err != nil {
+		return nil, err
+	}
+
+	entry, err := parsePeerPolicyEntry(in.PubKey, in.IpRange)
+	if err != nil {
+		return nil, err
+	}
 
-			// If our timestamp is non-nil, we have values for our
-			// peer's flap count, so we set them.
-			if ts != nil {
-				rpcPeer.FlapCount = int32(flap)
-				rpcPeer.LastFlapNs = ts.UnixNano()
-			}
-		}
+	selfNode := route.NewVertex(r.server.identityECDH.PubKey())
+	if list == channeldb.PeerBanList && entry.Pubkey != nil &&
+		*entry.Pubkey == selfNode {
 
-		resp.Peers = append(resp.Peers, rpcPeer)
+		return nil, errors.New("cannot ban our own node")
 	}
 
-	rpcsLog.Debugf("[listpeers] yielded %v peers", serverPeers)
+	entry.Reason = in.Reason
+	entry.CreatedAt = time.Now()
+	if in.DurationSeconds != 0 {
+		entry.Expiry = entry.CreatedAt.Add(
+			time.Duration(in.DurationSeconds) * time.Second,
+		)
+	}
+
+	if err := r.server.peerPolicy.AddEntry(list, entry); err != nil {
+		return nil, err
+	}
+
+	resp := &lnrpc.AddPeerPolicyEntryResponse{}
+	if list == channeldb.PeerBanList {
+		resp.NumDisconnected = r.server.disconnectBannedPeers()
+	}
 
 	return resp, nil
 }
 
-// SubscribePeerEvents returns a uni-directional stream (server -> client)
-// for notifying the client of peer online and offline events.
-func (r *rpcServer) SubscribePeerEvents(req *lnrpc.PeerEventSubscription,
-	eventStream lnrpc.Lightning_SubscribePeerEventsServer) error {
+// RemovePeerPolicyEntry removes a node or a range of IP addresses from either
+// the ban list or the allow list of the peer connection policy.
+func (r *rpcServer) RemovePeerPolicyEntry(_ context.Context,
+	in *lnrpc.RemovePeerPolicyEntryRequest) (
+	*lnrpc.RemovePeerPolicyEntryResponse, error) {
 
-	peerEventSub, err := r.server.peerNotifier.SubscribePeerEvents()
+	list, err := unmarshallPeerPolicyList(in.List)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	defer peerEventSub.Cancel()
-
-	for {
-		select {
-		// A new update has been sent by the peer notifier, we'll
-		// marshal it into the form expected by the gRPC client, then
-		// send it off to the client.
-		case e := <-peerEventSub.Updates():
-			var event *lnrpc.PeerEvent
 
-			switch peerEvent := e.(type) {
-			case peernotifier.PeerOfflineEvent:
-				event = &lnrpc.PeerEvent{
-					PubKey: hex.EncodeToString(peerEvent.PubKey[:]),
-					Type:   lnrpc.PeerEvent_PEER_OFFLINE,
-				}
+	entry, err := parsePeerPolicyEntry(in.PubKey, in.IpRange)
+	if err != nil {
+		return nil, err
+	}
 
-			case peernotifier.PeerOnlineEvent:
-				event = &lnrpc.PeerEvent{
-					PubKey: hex.EncodeToString(peerEvent.PubKey[:]),
-					Type:   lnrpc.PeerEvent_PEER_ONLINE,
-				}
+	if err := r.server.peerPolicy.RemoveEntry(list, entry); err != nil {
+		return nil, err
+	}
 
-			default:
-				return fmt.Errorf("unexpected peer event: %v", event)
-			}
+	return &lnrpc.RemovePeerPolicyEntryResponse{}, nil
+}
 
-			if err := eventStream.Send(event); err != nil {
-				return err
-			}
+// ListPeerPolicy returns the active entries of the ban list and the allow list
+// of the peer connection policy.
+func (r *rpcServer) ListPeerPolicy(_ context.Context,
+	_ *lnrpc.ListPeerPolicyRequest) (*lnrpc.ListPeerPolicyResponse, error) {
 
-		// The response stream's context for whatever reason has been
-		// closed. If context is closed by an exceeded deadline we will
-		// return an error.
-		case <-eventStream.Context().Done():
-			if errors.Is(eventStream.Context().Err(), context.Canceled) {
-				return nil
-			}
-			return eventStream.Context().Err()
+	policy := r.server.peerPolicy
 
-		case <-r.quit:
-			return nil
-		}
-	}
+	return &lnrpc.ListPeerPolicyResponse{
+		Bans: marshallPeerPolicyEntries(
+			policy.Entries(channeldb.PeerBanList),
+		),
+		AllowList: marshallPeerPolicyEntries(
+			policy.Entries(channeldb.PeerAllowList),
+		),
+		AllowListOnly: policy.AllowListOnly(),
+		MaxConnsPerIp: r.cfg.PeerPolicy.MaxConnsPerIP,
+	}, nil
 }
 
 // WalletBalance returns total unspent outputs(confirmed and unconfirmed), all
@@ -3828,11 +4724,180 @@ func (r *rpcServer) arbitratorPopulateForceCloseResp(chanPoint *wire.OutPoint,
 
 		forceClose.LimboBalance += int64(report.LimboBalance)
 		forceClose.RecoveredBalance += int64(report.RecoveredBalance)
+		forceClose.Resolvers = append(
+			forceClose.Resolvers,
+			marshalResolverProgress(report, currentHeight),
+		)
 	}
 
 	return nil
 }
 
+// marshalResolverProgress converts a contract report into the progress of a
+// resolver as expected by the RPC.
+func marshalResolverProgress(report *contractcourt.ContractReport,
+	currentHeight int32) *lnrpc.ResolverProgress {
+
+	progress := &lnrpc.ResolverProgress{
+		Outpoint:         report.Outpoint.String(),
+		Stage:            marshalResolverStage(report.ResolverStage),
+		AmountSat:        int64(report.Amount),
+		MaturityHeight:   report.MaturityHeight,
+		SweepFeeSat:      int64(report.SweepFee),
+		SweepAttempts:    report.SweepAttempts,
+		LimboBalance:     int64(report.LimboBalance),
+		RecoveredBalance: int64(report.RecoveredBalance),
+	}
+
+	switch report.Type {
+	case contractcourt.ReportOutputUnencumbered:
+		progress.ResolutionType = lnrpc.ResolutionType_COMMIT
+
+	case contractcourt.ReportOutputIncomingHtlc:
+		progress.ResolutionType = lnrpc.ResolutionType_INCOMING_HTLC
+
+	case contractcourt.ReportOutputOutgoingHtlc:
+		progress.ResolutionType = lnrpc.ResolutionType_OUTGOING_HTLC
+
+	case contractcourt.ReportOutputAnchor:
+		progress.ResolutionType = lnrpc.ResolutionType_ANCHOR
+	}
+
+	if report.MaturityHeight != 0 {
+		progress.BlocksTilMaturity = int32(report.MaturityHeight) -
+			currentHeight
+	}
+
+	if report.SweepTxid != nil {
+		progress.SweepTxid = report.SweepTxid.String()
+	}
+
+	return progress
+}
+
+// marshalResolverStage converts a resolver stage into its RPC counterpart.
+func marshalResolverStage(
+	stage contractcourt.ResolverStage) lnrpc.ResolverProgress_Stage {
+
+	switch stage {
+	case contractcourt.ResolverStageWaitingForCommitConf:
+		return lnrpc.ResolverProgress_WAITING_FOR_COMMIT_CONF
+
+	case contractcourt.ResolverStageWaitingForPreimage:
+		return lnrpc.ResolverProgress_WAITING_FOR_PREIMAGE
+
+	case contractcourt.ResolverStageWaitingForExpiry:
+		return lnrpc.ResolverProgress_WAITING_FOR_EXPIRY
+
+	case contractcourt.ResolverStageWaitingForSecondLevel:
+		return lnrpc.ResolverProgress_WAITING_FOR_SECOND_LEVEL
+
+	case contractcourt.ResolverStageWaitingForCsv:
+		return lnrpc.ResolverProgress_WAITING_FOR_CSV
+
+	case contractcourt.ResolverStageSweeping:
+		return lnrpc.ResolverProgress_SWEEPING
+
+	case contractcourt.ResolverStageResolved:
+		return lnrpc.ResolverProgress_RESOLVED
+
+	default:
+		return lnrpc.ResolverProgress_STAGE_UNKNOWN
+	}
+}
+
+// marshalResolverProgressUpdate converts the reports of the resolvers of a
+// channel into a resolver progress update as expected by the RPC.
+func marshalResolverProgressUpdate(chanPoint wire.OutPoint,
+	reports []*contractcourt.ContractReport,
+	currentHeight int32) *lnrpc.ResolverProgressUpdate {
+
+	update := &lnrpc.ResolverProgressUpdate{
+		ChannelPoint: chanPoint.String(),
+		Resolvers: make(
+			[]*lnrpc.ResolverProgress, 0, len(reports),
+		),
+	}
+	for _, report := range reports {
+		update.Resolvers = append(
+			update.Resolvers,
+			marshalResolverProgress(report, currentHeight),
+		)
+	}
+
+	return update
+}
+
+// SubscribeResolverProgress streams the progress of the on-chain resolution
+// of force closed channels. The current progress of all channels being
+// resolved is sent first, followed by an update each time the progress of a
+// channel changes.
+func (r *rpcServer) SubscribeResolverProgress(
+	_ *lnrpc.ResolverProgressSubscription,
+	updateStream lnrpc.Lightning_SubscribeResolverProgressServer) error {
+
+	// Subscribe before taking the snapshot, so we don't miss any update
+	// in between.
+	progressSub, err := r.server.chainArb.SubscribeResolverProgress()
+	if err != nil {
+		return err
+	}
+
+	// Ensure that the resources for the client is cleaned up once either
+	// the server, or client exits.
+	defer progressSub.Cancel()
+
+	_, currentHeight, err := r.server.cc.ChainIO.GetBestBlock()
+	if err != nil {
+		return err
+	}
+
+	for chanPoint, reports := range r.server.chainArb.ResolverProgress() {
+		update := marshalResolverProgressUpdate(
+			chanPoint, reports, currentHeight,
+		)
+		if err := updateStream.Send(update); err != nil {
+			return err
+		}
+	}
+
+	for {
+		select {
+		case e := <-progressSub.Updates():
+			upd, ok := e.(*contractcourt.ResolverProgressUpdate)
+			if !ok {
+				return fmt.Errorf("unexpected resolver "+
+					"progress update: %v", e)
+			}
+
+			_, height, err := r.server.cc.ChainIO.GetBestBlock()
+			if err != nil {
+				return err
+			}
+
+			update := marshalResolverProgressUpdate(
+				upd.ChanPoint, upd.Reports, height,
+			)
+			if err := updateStream.Send(update); err != nil {
+				return err
+			}
+
+		// The response stream's context for whatever reason has been
+		// closed. If context is closed by an exceeded deadline we will
+		// return an error.
+		case <-updateStream.Context().Done():
+			err := updateStream.Context().Err()
+			if errors.Is(err, context.Canceled) {
+				return nil
+			}
+			return err
+
+		case <-r.quit:
+			return nil
+		}
+	}
+}
+
 // nurseryPopulateForceCloseResp populates the pending channels response
 // message with contract resolution information from utxonursery.
 func (r *rpcServer) nurseryPopulateForceCloseResp(chanPoint *wire.OutPoint,
@@ -4028,7 +5093,8 @@ func (r *rpcServer) ListChannels(ctx context.Context,
 
 		channelID := lnwire.NewChanIDFromOutPoint(&chanPoint)
 		var linkActive bool
-		if link, err := r.server.htlcSwitch.GetLink(channelID); err == nil {
+		link, err := r.server.htlcSwitch.GetLink(channelID)
+		if err == nil {
 			// A channel is only considered active if it is known
 			// by the switch *and* able to forward
 			// incoming/outgoing payments.
@@ -4045,6 +5111,25 @@ func (r *rpcServer) ListChannels(ctx context.Context,
 			return nil, err
 		}
 
+		// If the channel is loaded by the switch, we'll also report
+		// the fee exposure of its commitments.
+		if link != nil {
+			stats := link.FeeExposureStats()
+			channel.FeeExposure = &lnrpc.FeeExposure{
+				LocalExposureMsat: uint64(
+					stats.LocalExposure,
+				),
+				RemoteExposureMsat: uint64(
+					stats.RemoteExposure,
+				),
+				MaxFeeExposureMsat: uint64(
+					stats.MaxFeeExposure,
+				),
+				RejectedAdds:       stats.RejectedAdds,
+				RejectedFeeUpdates: stats.RejectedFeeUpdates,
+			}
+		}
+
 		// We'll only skip returning this channel if we were requested
 		// for a specific kind and this channel doesn't satisfy it.
 		switch {
@@ -4669,7 +5754,8 @@ func (r *rpcServer) SubscribeChannelEvents(req *lnrpc.ChannelEventSubscription,
 			// InactiveLinkEvent as this is explicitly not exposed
 			// to the RPC.
 			case channelnotifier.ActiveLinkEvent,
-				channelnotifier.InactiveLinkEvent:
+				channelnotifier.InactiveLinkEvent,
+				channelnotifier.UpgradedChannelEvent:
 
 				continue
 
@@ -6086,6 +7172,14 @@ func marshalNode(node *channeldb.LightningNode) *lnrpc.LightningNode {
 
 	customRecords := marshalExtraOpaqueData(node.ExtraOpaqueData)
 
+	// A malformed liquidity ad is simply not shown, it's still included in
+	// the custom records.
+	ad, err := lnwire.ParseLiquidityAd(node.ExtraOpaqueData)
+	if err != nil {
+		rpcsLog.Debugf("Invalid liquidity ad of node %x: %v",
+			node.PubKeyBytes[:], err)
+	}
+
 	return &lnrpc.LightningNode{
 		LastUpdate:    uint32(node.LastUpdate.Unix()),
 		PubKey:        hex.EncodeToString(node.PubKeyBytes[:]),
@@ -6094,6 +7188,23 @@ func marshalNode(node *channeldb.LightningNode) *lnrpc.LightningNode {
 		Color:         routing.EncodeHexColor(node.Color),
 		Features:      features,
 		CustomRecords: customRecords,
+		LiquidityAd:   marshalLiquidityAd(ad),
+	}
+}
+
+// marshalLiquidityAd converts a liquidity ad into its RPC counterpart, which
+// is nil if the ad is.
+func marshalLiquidityAd(ad *lnwire.LiquidityAd) *lnrpc.LiquidityAd {
+	if ad == nil {
+		return nil
+	}
+
+	return &lnrpc.LiquidityAd{
+		LeaseDuration: ad.LeaseDuration,
+		FeeBaseSat:    int64(ad.FeeBase),
+		FeeRatePpm:    ad.FeeRate,
+		MinLeaseSat:   int64(ad.MinLeaseAmt),
+		MaxLeaseSat:   int64(ad.MaxLeaseAmt),
 	}
 }
 
@@ -6251,6 +7362,183 @@ func (r *rpcServer) GetNetworkInfo(ctx context.Context,
 	return netInfo, nil
 }
 
+// GossipBudgetUsage returns the gossip exchanged with our peers and how it was
+// affected by the configured gossip bandwidth budgets, both in total and for
+// the gossip syncer of each connected peer.
+func (r *rpcServer) GossipBudgetUsage(_ context.Context,
+	_ *lnrpc.GossipBudgetUsageRequest) (*lnrpc.GossipBudgetUsageResponse,
+	error) {
+
+	syncMgr := r.server.authGossiper.SyncManager()
+	total, syncers := syncMgr.BudgetUsage()
+
+	resp := &lnrpc.GossipBudgetUsageResponse{
+		PeerOutboundBudget:   r.cfg.Gossip.PeerOutboundBudget,
+		PeerInboundBudget:    r.cfg.Gossip.PeerInboundBudget,
+		GlobalOutboundBudget: r.cfg.Gossip.GlobalOutboundBudget,
+		GlobalInboundBudget:  r.cfg.Gossip.GlobalInboundBudget,
+		Total:                marshallGossipUsage(total),
+		Syncers: make(
+			[]*lnrpc.GossipSyncerUsage, 0, len(syncers),
+		),
+	}
+
+	for _, syncer := range syncers {
+		syncType, err := marshallSyncType(syncer.SyncType)
+		if err != nil {
+			return nil, err
+		}
+
+		resp.Syncers = append(resp.Syncers, &lnrpc.GossipSyncerUsage{
+			PubKey:   syncer.Peer.String(),
+			SyncType: syncType,
+			Usage:    marshallGossipUsage(syncer.Usage),
+		})
+	}
+
+	// Sort the syncers by their usage, so the peers we exchange the most
+	// gossip with come first.
+	sort.Slice(resp.Syncers, func(i, j int) bool {
+		usageI, usageJ := resp.Syncers[i].Usage, resp.Syncers[j].Usage
+
+		return usageI.BytesSent+usageI.BytesReceived >
+			usageJ.BytesSent+usageJ.BytesReceived
+	})
+
+	for mode, bandwidth := range syncMgr.Bandwidth() {
+		var rpcMode lnrpc.GossipSyncBandwidth_SyncMode
+		switch mode {
+		case discovery.LegacySync:
+			rpcMode = lnrpc.GossipSyncBandwidth_LEGACY_SYNC
+
+		case discovery.ReconcileSync:
+			rpcMode = lnrpc.GossipSyncBandwidth_RECONCILE_SYNC
+
+		default:
+			return nil, fmt.Errorf("unknown sync mode %v", mode)
+		}
+
+		resp.SyncBandwidth = append(
+			resp.SyncBandwidth, &lnrpc.GossipSyncBandwidth{
+				SyncMode:      rpcMode,
+				MsgsSent:      bandwidth.MsgsSent,
+				BytesSent:     bandwidth.BytesSent,
+				MsgsReceived:  bandwidth.MsgsReceived,
+				BytesReceived: bandwidth.BytesReceived,
+			},
+		)
+	}
+
+	// The sync modes are sorted to keep the response stable.
+	sort.Slice(resp.SyncBandwidth, func(i, j int) bool {
+		return resp.SyncBandwidth[i].SyncMode <
+			resp.SyncBandwidth[j].SyncMode
+	})
+
+	return resp, nil
+}
+
+// ExportGraphSnapshot returns a snapshot of the public channel graph in a
+// compact binary format, holding the signed announcements of all public
+// channels and nodes.
+func (r *rpcServer) ExportGraphSnapshot(_ context.Context,
+	_ *lnrpc.ExportGraphSnapshotRequest) (*lnrpc.GraphSnapshot, error) {
+
+	snapshot, err := graphsnapshot.Export(
+		r.server.graphDB, *r.cfg.ActiveNetParams.GenesisHash,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to export graph: %w", err)
+	}
+
+	var b bytes.Buffer
+	if err := snapshot.Encode(&b); err != nil {
+		return nil, err
+	}
+
+	return &lnrpc.GraphSnapshot{
+		Snapshot:    b.Bytes(),
+		NumChannels: uint32(len(snapshot.Channels)),
+		NumNodes:    uint32(len(snapshot.Nodes)),
+	}, nil
+}
+
+// ImportGraphSnapshot adds the channels and nodes of a graph snapshot to the
+// channel graph, after verifying the signatures of their announcements.
+func (r *rpcServer) ImportGraphSnapshot(_ context.Context,
+	in *lnrpc.ImportGraphSnapshotRequest) (
+	*lnrpc.ImportGraphSnapshotResponse, error) {
+
+	var snapshot graphsnapshot.Snapshot
+	err := snapshot.Decode(bytes.NewReader(in.Snapshot))
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode snapshot: %w", err)
+	}
+
+	chainHash := *r.cfg.ActiveNetParams.GenesisHash
+	if snapshot.ChainHash != chainHash {
+		return nil, fmt.Errorf("snapshot is for chain %v, expected %v",
+			snapshot.ChainHash, chainHash)
+	}
+
+	if !in.SkipSignatureVerification {
+		err := graphsnapshot.Verify(&snapshot, chainHash)
+		if err != nil {
+			return nil, fmt.Errorf("invalid snapshot: %w", err)
+		}
+	}
+
+	rpcsLog.Infof("Importing graph snapshot taken at %v with %v "+
+		"channels and %v nodes", snapshot.Timestamp,
+		len(snapshot.Channels), len(snapshot.Nodes))
+
+	summary, err := graphsnapshot.Import(r.server.chanRouter, &snapshot)
+	if err != nil {
+		return nil, fmt.Errorf("unable to import snapshot: %w", err)
+	}
+
+	return &lnrpc.ImportGraphSnapshotResponse{
+		ChannelsImported: uint32(summary.ChannelsImported),
+		ChannelsSkipped:  uint32(summary.ChannelsSkipped),
+		UpdatesImported:  uint32(summary.UpdatesImported),
+		UpdatesSkipped:   uint32(summary.UpdatesSkipped),
+		NodesImported:    uint32(summary.NodesImported),
+		NodesSkipped:     uint32(summary.NodesSkipped),
+	}, nil
+}
+
+// marshallSyncType converts the sync type of a gossip syncer to its RPC
+// counterpart.
+func marshallSyncType(syncType discovery.SyncerType) (lnrpc.Peer_SyncType,
+	error) {
+
+	switch syncType {
+	case discovery.ActiveSync:
+		return lnrpc.Peer_ACTIVE_SYNC, nil
+	case discovery.PassiveSync:
+		return lnrpc.Peer_PASSIVE_SYNC, nil
+	case discovery.PinnedSync:
+		return lnrpc.Peer_PINNED_SYNC, nil
+	default:
+		return 0, fmt.Errorf("unhandled sync type %v", syncType)
+	}
+}
+
+// marshallGossipUsage converts the usage of the gossip bandwidth budgets to
+// its RPC counterpart.
+func marshallGossipUsage(usage discovery.GossipBudgetUsage) *lnrpc.GossipUsage {
+	return &lnrpc.GossipUsage{
+		MsgsSent:        usage.MsgsSent,
+		BytesSent:       usage.BytesSent,
+		MsgsReceived:    usage.MsgsReceived,
+		BytesReceived:   usage.BytesReceived,
+		MsgsDelayed:     usage.MsgsDelayed,
+		TotalDelayMs:    uint64(usage.TotalDelay.Milliseconds()),
+		OutboundDropped: usage.OutboundDropped,
+		InboundDropped:  usage.InboundDropped,
+	}
+}
+
 // StopDaemon will send a shutdown request to the interrupt handler, triggering
 // a graceful shutdown of the daemon.
 func (r *rpcServer) StopDaemon(_ context.Context,
@@ -7273,6 +8561,7 @@ func (r *rpcServer) RestoreChannelBackups(ctx context.Context,
 		db:         r.server.chanStateDB,
 		secretKeys: r.server.cc.KeyRing,
 		chainArb:   r.server.chainArb,
+		publishTx:  r.server.cc.Wallet.PublishTransaction,
 	}
 
 	// We'll accept either a list of Single backups, or a single Multi
@@ -7405,6 +8694,40 @@ func (r *rpcServer) SubscribeChannelBackups(req *lnrpc.ChannelBackupSubscription
 	}
 }
 
+// BackupSinkStatus returns the upload status of every remote sink the
+// multi-channel backup is uploaded to.
+func (r *rpcServer) BackupSinkStatus(_ context.Context,
+	_ *lnrpc.BackupSinkStatusRequest) (*lnrpc.BackupSinkStatusResponse,
+	error) {
+
+	resp := &lnrpc.BackupSinkStatusResponse{}
+
+	// If no remote sinks are configured, there's nothing to report.
+	if r.server.backupSinkDispatcher == nil {
+		return resp, nil
+	}
+
+	for _, status := range r.server.backupSinkDispatcher.SinkStatuses() {
+		sink := &lnrpc.BackupSink{
+			Name:        status.Name,
+			NumFailures: status.NumFailures,
+		}
+		if !status.LastSuccess.IsZero() {
+			sink.LastSuccessTimestamp = status.LastSuccess.Unix()
+		}
+		if !status.LastAttempt.IsZero() {
+			sink.LastAttemptTimestamp = status.LastAttempt.Unix()
+		}
+		if status.LastErr != nil {
+			sink.LastError = status.LastErr.Error()
+		}
+
+		resp.Sinks = append(resp.Sinks, sink)
+	}
+
+	return resp, nil
+}
+
 // ChannelAcceptor dispatches a bi-directional streaming RPC in which
 // OpenChannel requests are sent to the client and the client responds with
 // a boolean that tells LND whether or not to accept the channel. This allows
@@ -49,8 +49,11 @@ import (
 	"github.com/lightningnetwork/lnd/lnencrypt"
 	"github.com/lightningnetwork/lnd/lnpeer"
 	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lnrpc/invoicesrpc"
 	"github.com/lightningnetwork/lnd/lnrpc/routerrpc"
+	"github.com/lightningnetwork/lnd/lntypes"
 	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/lnwallet/anchorreserve"
 	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
 	"github.com/lightningnetwork/lnd/lnwallet/rpcwallet"
 	"github.com/lightningnetwork/lnd/lnwire"
@@ -58,6 +61,7 @@ import (
 	"github.com/lightningnetwork/lnd/netann"
 	"github.com/lightningnetwork/lnd/peer"
 	"github.com/lightningnetwork/lnd/peernotifier"
+	"github.com/lightningnetwork/lnd/peerpolicy"
 	"github.com/lightningnetwork/lnd/pool"
 	"github.com/lightningnetwork/lnd/queue"
 	"github.com/lightningnetwork/lnd/routing"
@@ -72,6 +76,7 @@ import (
 	"github.com/lightningnetwork/lnd/watchtower/wtclient"
 	"github.com/lightningnetwork/lnd/watchtower/wtpolicy"
 	"github.com/lightningnetwork/lnd/watchtower/wtserver"
+	"github.com/lightningnetwork/lnd/wirecapture"
 )
 
 const (
@@ -277,8 +282,25 @@ type server struct {
 
 	sweeper *sweep.UtxoSweeper
 
+	// anchorReserve plans the wallet reserve needed to fee bump all
+	// anchor channels, and optionally locks the outputs assigned to them.
+	anchorReserve *anchorreserve.Manager
+
 	chainArb *contractcourt.ChainArbitrator
 
+	forceClosePolicy *contractcourt.ForceClosePolicyEngine
+
+	// peerPolicy decides which peers we accept connections from.
+	peerPolicy *peerpolicy.Manager
+
+	// capturePeers is the set of peers whose wire messages are captured.
+	capturePeers map[route.Vertex]struct{}
+
+	// liquidityAd is the liquidity ad we advertise in our node
+	// announcement to sell channel leases, or nil if we don't.
+	liquidityAd    *lnwire.LiquidityAd
+	liquidityAdMtx sync.RWMutex
+
 	sphinx *hop.OnionProcessor
 
 	towerClient wtclient.Client
@@ -313,6 +335,11 @@ type server struct {
 	// channelNotifier to be notified of newly opened and closed channels.
 	chanSubSwapper *chanbackup.SubSwapper
 
+	// backupSinkDispatcher uploads each new multi-channel backup to the
+	// configured remote backup sinks. It's nil if no remote sinks are
+	// configured.
+	backupSinkDispatcher *chanbackup.SinkDispatcher
+
 	// chanEventStore tracks the behaviour of channels and their remote peers to
 	// provide insights into their health and performance.
 	chanEventStore *chanfitness.ChannelEventStore
@@ -541,6 +568,11 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		NoOptionScidAlias:        !cfg.ProtocolOptions.ScidAlias(),
 		NoZeroConf:               !cfg.ProtocolOptions.ZeroConf(),
 		NoAnySegwit:              cfg.ProtocolOptions.NoAnySegwit(),
+		NoPeerStorage:            !cfg.ProtocolOptions.PeerStorage(),
+		NoRbfCoopClose:           !cfg.ProtocolOptions.RbfCoopClose(),
+		NoQuiescence:             !cfg.ProtocolOptions.Quiescence(),
+		NoDynamicCommitments:     !cfg.ProtocolOptions.DynamicCommitments(),
+		NoGossipReconcile:        !cfg.ProtocolOptions.GossipReconcile(),
 		CustomFeatures:           cfg.ProtocolOptions.ExperimentalProtocol.CustomFeatures(),
 	})
 	if err != nil {
@@ -633,6 +665,24 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		return nil, err
 	}
 
+	rateLimits := htlcswitch.RateLimitConfig{
+		Peer: htlcswitch.RateLimit{
+			HtlcsPerSecond: cfg.Htlcswitch.PeerHtlcsPerSec,
+			MsatPerMinute: lnwire.MilliSatoshi(
+				cfg.Htlcswitch.PeerMsatPerMin,
+			),
+		},
+		Channel: htlcswitch.RateLimit{
+			HtlcsPerSecond: cfg.Htlcswitch.ChanHtlcsPerSec,
+			MsatPerMinute: lnwire.MilliSatoshi(
+				cfg.Htlcswitch.ChanMsatPerMin,
+			),
+		},
+	}
+	if cfg.Htlcswitch.RateLimitQueue {
+		rateLimits.Policy = htlcswitch.RateLimitQueue
+	}
+
 	s.htlcSwitch, err = htlcswitch.New(htlcswitch.Config{
 		DB:                   dbs.ChanStateDB,
 		FetchAllOpenChannels: s.chanStateDB.FetchAllOpenChannels,
@@ -668,6 +718,7 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		DustThreshold:          thresholdMSats,
 		SignAliasUpdate:        s.signAliasUpdate,
 		IsAlias:                aliasmgr.IsAlias,
+		RateLimits:             rateLimits,
 	}, uint32(currentHeight))
 	if err != nil {
 		return nil, err
@@ -825,6 +876,18 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 	}
 	copy(selfNode.PubKeyBytes[:], nodeKeyDesc.PubKey.SerializeCompressed())
 
+	// If we sell channel leases, we'll advertise our liquidity ad within
+	// the extra data of our node announcement.
+	s.liquidityAd = cfg.LiquidityAd.Ad()
+	if s.liquidityAd != nil {
+		var extraData lnwire.ExtraOpaqueData
+		err := lnwire.SetLiquidityAd(&extraData, s.liquidityAd)
+		if err != nil {
+			return nil, err
+		}
+		selfNode.ExtraOpaqueData = extraData
+	}
+
 	// Based on the disk representation of the node announcement generated
 	// above, we'll generate a node announcement that can go out on the
 	// network so we can properly sign it.
@@ -984,6 +1047,38 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		return nil, fmt.Errorf("can't create router: %v", err)
 	}
 
+	s.peerPolicy, err = peerpolicy.NewManager(&peerpolicy.Config{
+		Store:            dbs.ChanStateDB,
+		AllowListOnly:    cfg.PeerPolicy.AllowListOnly,
+		MaxConnsPerIP:    cfg.PeerPolicy.MaxConnsPerIP,
+		AutoBanThreshold: cfg.PeerPolicy.AutoBanThreshold,
+		AutoBanDuration:  cfg.PeerPolicy.AutoBanDuration,
+		HasChannels: func(peer route.Vertex) (bool, error) {
+			pubKey, err := btcec.ParsePubKey(peer[:])
+			if err != nil {
+				return false, err
+			}
+
+			channels, err := s.chanStateDB.FetchOpenChannels(
+				pubKey,
+			)
+			if err != nil {
+				return false, err
+			}
+
+			return len(channels) > 0, nil
+		},
+		Clock: clock.NewDefaultClock(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.capturePeers, err = cfg.WireCapture.PeerSet()
+	if err != nil {
+		return nil, err
+	}
+
 	chanSeries := discovery.NewChanSeries(s.graphDB)
 	gossipMessageStore, err := discovery.NewMessageStore(dbs.ChanStateDB)
 	if err != nil {
@@ -1024,11 +1119,13 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		PinnedSyncers:           cfg.Gossip.PinnedSyncers,
 		MaxChannelUpdateBurst:   cfg.Gossip.MaxChannelUpdateBurst,
 		ChannelUpdateInterval:   cfg.Gossip.ChannelUpdateInterval,
+		GossipBudget:            cfg.Gossip.Budget(),
 		IsAlias:                 aliasmgr.IsAlias,
 		SignAliasUpdate:         s.signAliasUpdate,
 		FindBaseByAlias:         s.aliasMgr.FindBaseSCID,
 		GetAlias:                s.aliasMgr.GetPeerAlias,
 		FindChannel:             s.findChannel,
+		ReportInvalidAnn:        s.reportMisbehavior,
 	}, nodeKeyDesc)
 
 	s.localChanMgr = &localchans.Manager{
@@ -1074,6 +1171,20 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		FeeRateBucketSize:    sweep.DefaultFeeRateBucketSize,
 	})
 
+	s.anchorReserve = anchorreserve.NewManager(anchorreserve.Config{
+		FetchChannels: s.chanStateDB.FetchAllOpenChannels,
+		IsClosing: func() (bool, error) {
+			return hasClosingChannels(s.chanStateDB)
+		},
+		Wallet:              cc.Wallet,
+		CoinSelectionLocker: cc.Wallet,
+		FeeEstimator:        cc.FeeEstimator,
+		ConfTarget:          cfg.AnchorReserve.ConfTarget,
+		AutoLock:            cfg.AnchorReserve.AutoLock,
+		LeaseDuration:       cfg.AnchorReserve.LeaseDuration,
+		Ticker:              ticker.New(cfg.AnchorReserve.Interval),
+	})
+
 	s.utxoNursery = contractcourt.NewUtxoNursery(&contractcourt.NurseryConfig{
 		ChainIO:             cc.ChainIO,
 		ConfDepth:           1,
@@ -1094,7 +1205,7 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		// Instruct the switch to close the channel.  Provide no close out
 		// delivery script or target fee per kw because user input is not
 		// available when the remote peer closes the channel.
-		s.htlcSwitch.CloseLink(chanPoint, closureType, 0, 0, nil)
+		s.htlcSwitch.CloseLink(chanPoint, closureType, 0, 0, nil, 0)
 	}
 
 	// We will use the following channel to reliably hand off contract
@@ -1115,10 +1226,24 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		),
 	})
 
+	// The force close policy engine is consulted by the channel
+	// arbitrators to decide whether an expiring HTLC justifies going
+	// on-chain.
+	s.forceClosePolicy, err = contractcourt.NewForceClosePolicyEngine(
+		cfg.ForceClosePolicy.Policy(), cc.FeeEstimator,
+	)
+	if err != nil {
+		return nil, err
+	}
+	for peer, policy := range cfg.ForceClosePolicy.PeerPolicies {
+		s.forceClosePolicy.SetPeerPolicy(peer, policy)
+	}
+
 	s.chainArb = contractcourt.NewChainArbitrator(contractcourt.ChainArbitratorConfig{
 		ChainHash:              *s.cfg.ActiveNetParams.GenesisHash,
 		IncomingBroadcastDelta: lncfg.DefaultIncomingBroadcastDelta,
 		OutgoingBroadcastDelta: lncfg.DefaultOutgoingBroadcastDelta,
+		ForceClosePolicy:       s.forceClosePolicy,
 		NewSweepAddr:           newSweepPkScriptGen(cc.Wallet),
 		PublishTx:              cc.Wallet.PublishTransaction,
 		DeliverResolutionMsg: func(msgs ...contractcourt.ResolutionMsg) error {
@@ -1217,6 +1342,7 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		SubscribeBreachComplete:       s.breachArbiter.SubscribeBreachComplete,
 		PutFinalHtlcOutcome:           s.chanStateDB.PutOnchainFinalHtlcOutcome, //nolint: lll
 		HtlcNotifier:                  s.htlcNotifier,
+		ReleaseAnchorReserve:          s.anchorReserve.ReleaseReserve,
 	}, dbs.ChanStateDB)
 
 	// Select the configuration and furnding parameters for Bitcoin or
@@ -1451,6 +1577,12 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		DeleteAliasEdge:          deleteAliasEdge,
 		AliasManager:             s.aliasMgr,
 		UpdateForwardingPolicies: s.htlcSwitch.UpdateForwardingPolicies,
+		LiquidityAd:              s.currentLiquidityAd,
+		AddLeaseInvoice:          s.addLeaseInvoice,
+		WaitForLeasePayment:      s.waitForLeasePayment,
+		LeaseFeePaid:             s.leaseFeePaid,
+		MaxUnpaidLeases:          cfg.LiquidityAd.MaxUnpaidLeases,
+		MaxUnpaidLeasesPerPeer:   cfg.LiquidityAd.MaxUnpaidLeasesPerPeer,
 	})
 	if err != nil {
 		return nil, err
@@ -1462,7 +1594,36 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		chanNotifier: s.channelNotifier,
 		addrs:        dbs.ChanStateDB,
 	}
-	backupFile := chanbackup.NewMultiFile(cfg.BackupFilePath)
+	var backupSwapper chanbackup.Swapper = chanbackup.NewMultiFile(
+		cfg.BackupFilePath,
+	)
+
+	// If any remote backup sinks are configured, we'll wrap the backup
+	// file so that each new backup is also uploaded to those sinks.
+	backupSinks, err := newBackupSinks(cfg.RemoteBackup)
+	if err != nil {
+		return nil, err
+	}
+	if len(backupSinks) > 0 {
+		s.backupSinkDispatcher, err = chanbackup.NewSinkDispatcher(
+			chanbackup.SinkDispatcherConfig{
+				Sinks:       backupSinks,
+				MinBackoff:  cfg.RemoteBackup.MinBackoff,
+				MaxBackoff:  cfg.RemoteBackup.MaxBackoff,
+				MaxAttempts: cfg.RemoteBackup.MaxAttempts,
+				Timeout:     cfg.RemoteBackup.Timeout,
+				Clock:       clock.NewDefaultClock(),
+			},
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		backupSwapper = chanbackup.NewSinkSwapper(
+			backupSwapper, s.backupSinkDispatcher,
+		)
+	}
+
 	startingChans, err := chanbackup.FetchStaticChanBackups(
 		s.chanStateDB, s.addrSource,
 	)
@@ -1470,7 +1631,7 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		return nil, err
 	}
 	s.chanSubSwapper, err = chanbackup.NewSubSwapper(
-		startingChans, chanNotifier, s.cc.KeyRing, backupFile,
+		startingChans, chanNotifier, s.cc.KeyRing, backupSwapper,
 	)
 	if err != nil {
 		return nil, err
@@ -1557,7 +1718,17 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 				return nil, 0, err
 			}
 
-			return br, channel.ChanType, nil
+			// The revoked commitment may predate an upgrade of the
+			// channel's commitment format, so we'll report the
+			// type that was in use at the revoked height.
+			params, err := channel.CommitParamsAt(
+				false, commitHeight,
+			)
+			if err != nil {
+				return nil, 0, err
+			}
+
+			return br, params.ChanType, nil
 		}
 
 		fetchClosedChannel := s.chanStateDB.FetchClosedChannelForID
@@ -1956,6 +2127,12 @@ func (s *server) Start() error {
 		}
 		cleanup = cleanup.add(s.sweeper.Stop)
 
+		if err := s.anchorReserve.Start(); err != nil {
+			startErr = err
+			return
+		}
+		cleanup = cleanup.add(s.anchorReserve.Stop)
+
 		if err := s.utxoNursery.Start(); err != nil {
 			startErr = err
 			return
@@ -2048,6 +2225,7 @@ func (s *server) Start() error {
 			db:         s.chanStateDB,
 			secretKeys: s.cc.KeyRing,
 			chainArb:   s.chainArb,
+			publishTx:  s.cc.Wallet.PublishTransaction,
 		}
 		if len(s.chansToRestore.PackedSingleChanBackups) != 0 {
 			err := chanbackup.UnpackAndRecoverSingles(
@@ -2072,6 +2250,16 @@ func (s *server) Start() error {
 			}
 		}
 
+		// The sink dispatcher must be running before the sub swapper
+		// starts, as the sub swapper refreshes the backup on start up.
+		if s.backupSinkDispatcher != nil {
+			if err := s.backupSinkDispatcher.Start(); err != nil {
+				startErr = err
+				return
+			}
+			cleanup = cleanup.add(s.backupSinkDispatcher.Stop)
+		}
+
 		if err := s.chanSubSwapper.Start(); err != nil {
 			startErr = err
 			return
@@ -2289,6 +2477,9 @@ func (s *server) Stop() error {
 		if err := s.sweeper.Stop(); err != nil {
 			srvrLog.Warnf("failed to stop sweeper: %v", err)
 		}
+		if err := s.anchorReserve.Stop(); err != nil {
+			srvrLog.Warnf("failed to stop anchorReserve: %v", err)
+		}
 		if err := s.channelNotifier.Stop(); err != nil {
 			srvrLog.Warnf("failed to stop channelNotifier: %v", err)
 		}
@@ -2301,6 +2492,12 @@ func (s *server) Stop() error {
 		if err := s.chanSubSwapper.Stop(); err != nil {
 			srvrLog.Warnf("failed to stop chanSubSwapper: %v", err)
 		}
+		if s.backupSinkDispatcher != nil {
+			if err := s.backupSinkDispatcher.Stop(); err != nil {
+				srvrLog.Warnf("failed to stop "+
+					"backupSinkDispatcher: %v", err)
+			}
+		}
 		if err := s.cc.ChainNotifier.Stop(); err != nil {
 			srvrLog.Warnf("Unable to stop ChainNotifier: %v", err)
 		}
@@ -2929,8 +3126,9 @@ func (s *server) createNewHiddenService() error {
 		Features: lnwire.NewFeatureVector(
 			newNodeAnn.Features, lnwire.Features,
 		),
-		Color:        newNodeAnn.RGBColor,
-		AuthSigBytes: newNodeAnn.Signature.ToSignatureBytes(),
+		Color:           newNodeAnn.RGBColor,
+		AuthSigBytes:    newNodeAnn.Signature.ToSignatureBytes(),
+		ExtraOpaqueData: newNodeAnn.ExtraOpaqueData,
 	}
 	copy(selfNode.PubKeyBytes[:], s.identityECDH.PubKey().SerializeCompressed())
 	if err := s.graphDB.SetSourceNode(selfNode); err != nil {
@@ -3044,6 +3242,7 @@ func (s *server) updateAndBrodcastSelfNode(features *lnwire.RawFeatureVector,
 	selfNode.Features = s.featureMgr.Get(feature.SetNodeAnn)
 	selfNode.Color = newNodeAnn.RGBColor
 	selfNode.AuthSigBytes = newNodeAnn.Signature.ToSignatureBytes()
+	selfNode.ExtraOpaqueData = newNodeAnn.ExtraOpaqueData
 
 	copy(selfNode.PubKeyBytes[:], s.identityECDH.PubKey().SerializeCompressed())
 
@@ -3062,6 +3261,158 @@ func (s *server) updateAndBrodcastSelfNode(features *lnwire.RawFeatureVector,
 	return nil
 }
 
+// toggleFeatures enables or disables optional feature bits at runtime. If the
+// features advertised in our node announcement changed, a new announcement is
+// signed and broadcast to the network.
+func (s *server) toggleFeatures(toggles []feature.Toggle) error {
+	updated, err := s.featureMgr.ToggleFeatures(toggles)
+	if err != nil {
+		return err
+	}
+
+	nodeAnnFeatures, ok := updated[feature.SetNodeAnn]
+	if !ok {
+		return nil
+	}
+
+	// The feature manager was already updated, so we only need to apply
+	// the new features to the announcement itself.
+	return s.updateAndBrodcastSelfNode(
+		nil, netann.NodeAnnSetFeatures(nodeAnnFeatures),
+	)
+}
+
+// currentLiquidityAd returns the liquidity ad we currently advertise, or nil
+// if we don't sell channel leases.
+//
+// NOTE: This function is safe for concurrent access.
+func (s *server) currentLiquidityAd() *lnwire.LiquidityAd {
+	s.liquidityAdMtx.RLock()
+	defer s.liquidityAdMtx.RUnlock()
+
+	if s.liquidityAd == nil {
+		return nil
+	}
+
+	ad := *s.liquidityAd
+	return &ad
+}
+
+// updateLiquidityAd replaces the liquidity ad we advertise, or stops selling
+// channel leases if the ad is nil. A new node announcement carrying the ad is
+// signed and broadcast to the network.
+func (s *server) updateLiquidityAd(ad *lnwire.LiquidityAd) error {
+	if ad != nil {
+		if err := ad.Validate(); err != nil {
+			return err
+		}
+	}
+
+	s.liquidityAdMtx.Lock()
+	s.liquidityAd = ad
+	s.liquidityAdMtx.Unlock()
+
+	return s.updateAndBrodcastSelfNode(
+		nil, netann.NodeAnnSetLiquidityAd(ad),
+	)
+}
+
+// addLeaseInvoice adds an invoice for the fee of a channel lease sold through
+// our liquidity ad. The invoice includes hints for our private channels, as
+// the buyer might not be able to reach us through the public graph otherwise.
+func (s *server) addLeaseInvoice(fee btcutil.Amount, memo string) (string,
+	lntypes.Hash, error) {
+
+	defaultDelta := s.cfg.Bitcoin.TimeLockDelta
+	if s.cfg.registeredChains.PrimaryChain() == chainreg.LitecoinChain {
+		defaultDelta = s.cfg.Litecoin.TimeLockDelta
+	}
+
+	addInvoiceCfg := &invoicesrpc.AddInvoiceConfig{
+		AddInvoice:        s.invoices.AddInvoice,
+		IsChannelActive:   s.htlcSwitch.HasActiveLink,
+		ChainParams:       s.cfg.ActiveNetParams.Params,
+		NodeSigner:        s.nodeSigner,
+		DefaultCLTVExpiry: defaultDelta,
+		ChanDB:            s.chanStateDB,
+		Graph:             s.graphDB,
+		GenInvoiceFeatures: func() *lnwire.FeatureVector {
+			return s.featureMgr.Get(feature.SetInvoice)
+		},
+		GenAmpInvoiceFeatures: func() *lnwire.FeatureVector {
+			return s.featureMgr.Get(feature.SetInvoiceAmp)
+		},
+		GetAlias: s.aliasMgr.GetPeerAlias,
+	}
+
+	hash, invoice, err := invoicesrpc.AddInvoice(
+		context.Background(), addInvoiceCfg,
+		&invoicesrpc.AddInvoiceData{
+			Memo:    memo,
+			Value:   lnwire.NewMSatFromSatoshis(fee),
+			Private: true,
+		},
+	)
+	if err != nil {
+		return "", lntypes.Hash{}, err
+	}
+
+	return string(invoice.PaymentRequest), *hash, nil
+}
+
+// waitForLeasePayment blocks until the invoice of a lease fee with the given
+// payment hash is settled, returning funding.ErrLeaseInvoiceCanceled if it's
+// canceled, or another error if the quit channel is closed.
+func (s *server) waitForLeasePayment(hash lntypes.Hash,
+	quit <-chan struct{}) error {
+
+	invoiceClient, err := s.invoices.SubscribeSingleInvoice(hash)
+	if err != nil {
+		return err
+	}
+	defer invoiceClient.Cancel()
+
+	for {
+		select {
+		case invoice := <-invoiceClient.Updates:
+			switch invoice.State {
+			case invoices.ContractSettled:
+				return nil
+
+			case invoices.ContractCanceled:
+				return funding.ErrLeaseInvoiceCanceled
+			}
+
+		case <-quit:
+			return ErrServerShuttingDown
+
+		case <-s.quit:
+			return ErrServerShuttingDown
+		}
+	}
+}
+
+// leaseFeePaid returns true if our payment of the fee of a lease we bought
+// with the given payment hash succeeded or is still in flight.
+func (s *server) leaseFeePaid(hash lntypes.Hash) (bool, error) {
+	payment, err := s.controlTower.FetchPayment(hash)
+	switch {
+	case errors.Is(err, channeldb.ErrPaymentNotInitiated):
+		return false, nil
+
+	case err != nil:
+		return false, err
+	}
+
+	switch payment.Status {
+	case channeldb.StatusInFlight, channeldb.StatusSucceeded:
+		return true, nil
+
+	default:
+		return false, nil
+	}
+}
+
 type nodeAddresses struct {
 	pubKey    *btcec.PublicKey
 	addresses []net.Addr
@@ -3525,6 +3876,19 @@ func (s *server) InboundPeerConnected(conn net.Conn) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	// Refuse the connection if our peer policy doesn't accept it, e.g.
+	// because the peer is banned.
+	err := s.peerPolicy.AcceptInbound(
+		route.NewVertex(nodePub), conn.RemoteAddr(),
+	)
+	if err != nil {
+		srvrLog.Debugf("Refusing inbound connection from %x@%v: %v",
+			nodePub.SerializeCompressed(), conn.RemoteAddr(), err)
+
+		conn.Close()
+		return
+	}
+
 	// If we already have an outbound connection to this peer, then ignore
 	// this new connection.
 	if p, ok := s.outboundPeers[pubStr]; ok {
@@ -3632,6 +3996,22 @@ func (s *server) OutboundPeerConnected(connReq *connmgr.ConnReq, conn net.Conn)
 		return
 	}
 
+	// We also refuse to connect to banned peers, which stops any further
+	// attempts of the connection request.
+	err := s.peerPolicy.CheckBanned(
+		route.NewVertex(nodePub), conn.RemoteAddr(),
+	)
+	if err != nil {
+		srvrLog.Debugf("Refusing outbound connection to %x@%v: %v",
+			nodePub.SerializeCompressed(), conn.RemoteAddr(), err)
+
+		if connReq != nil {
+			s.connMgr.Remove(connReq.ID())
+		}
+		conn.Close()
+		return
+	}
+
 	// If we already have a valid connection that is scheduled to take
 	// precedence once the prior peer has finished disconnecting, we'll
 	// ignore this connection.
@@ -3816,6 +4196,40 @@ func (s *server) peerConnected(conn net.Conn, connReq *connmgr.ConnReq,
 		}
 	}
 
+	// The channels with this peer may have their own limit on the fee
+	// exposure of their commitments.
+	maxFeeExposure := lnwire.NewMSatFromSatoshis(
+		s.cfg.Htlcswitch.MaxFeeExposureFor(route.NewVertex(pubKey)),
+	)
+
+	// The CSV delay of our funds is limited by the configuration of the
+	// primary chain when the commitment of a channel is upgraded.
+	maxLocalDelay := s.cfg.Bitcoin.MaxLocalDelay
+	if s.cfg.registeredChains.PrimaryChain() == chainreg.LitecoinChain {
+		maxLocalDelay = s.cfg.Litecoin.MaxLocalDelay
+	}
+
+	// If the messages exchanged with this peer are to be captured, we'll
+	// open its capture file now. Failing to do so shouldn't prevent the
+	// connection.
+	var wireCapture *wirecapture.Writer
+	if _, ok := s.capturePeers[route.NewVertex(pubKey)]; ok {
+		captureCfg := wirecapture.WriterConfig{
+			Dir:         s.cfg.WireCapture.Dir,
+			Peer:        route.NewVertex(pubKey),
+			MaxFileSize: s.cfg.WireCapture.MaxFileSize,
+			MaxFiles:    s.cfg.WireCapture.MaxFiles,
+		}
+
+		var err error
+		wireCapture, err = wirecapture.NewWriter(captureCfg)
+		if err != nil {
+			srvrLog.Errorf("Unable to capture wire messages of "+
+				"peer %x: %v", pubKey.SerializeCompressed(),
+				err)
+		}
+	}
+
 	// Now that we've established a connection, create a peer, and it to the
 	// set of currently active peers. Configure the peer with the incoming
 	// and outgoing broadcast deltas to prevent htlcs from being accepted or
@@ -3874,9 +4288,11 @@ func (s *server) peerConnected(conn net.Conn, connReq *connmgr.ConnReq,
 		UnsafeReplay:            s.cfg.UnsafeReplay,
 		MaxOutgoingCltvExpiry:   s.cfg.MaxOutgoingCltvExpiry,
 		MaxChannelFeeAllocation: s.cfg.MaxChannelFeeAllocation,
+		MaxFeeExposure:          maxFeeExposure,
 		CoopCloseTargetConfs:    s.cfg.CoopCloseTargetConfs,
 		MaxAnchorsCommitFeeRate: chainfee.SatPerKVByte(
 			s.cfg.MaxCommitFeeRateAnchors * 1000).FeePerKWeight(),
+		MaxLocalCSVDelay:       maxLocalDelay,
 		ChannelCommitInterval:  s.cfg.ChannelCommitInterval,
 		PendingCommitInterval:  s.cfg.PendingCommitInterval,
 		ChannelCommitBatchSize: s.cfg.ChannelCommitBatchSize,
@@ -3884,6 +4300,12 @@ func (s *server) peerConnected(conn net.Conn, connReq *connmgr.ConnReq,
 		GetAliases:             s.aliasMgr.GetAliases,
 		RequestAlias:           s.aliasMgr.RequestAlias,
 		AddLocalAlias:          s.aliasMgr.AddLocalAlias,
+		FetchPeerStorage:       s.miscDB.FetchPeerStorage,
+		StorePeerStorage:       s.miscDB.PutPeerStorage,
+		GenPeerBackup:          s.genPeerBackup,
+		HandlePeerBackup:       s.handlePeerBackup,
+		ReportInvalidMessage:   s.reportMisbehavior,
+		WireCapture:            wireCapture,
 		Quit:                   s.quit,
 	}
 
@@ -3938,6 +4360,10 @@ func (s *server) addPeer(p *peer.Brontide) {
 		s.outboundPeers[pubStr] = p
 	}
 
+	s.peerPolicy.PeerConnected(
+		route.NewVertex(p.IdentityKey()), p.Address(), p.Inbound(),
+	)
+
 	// Inform the peer notifier of a peer online event so that it can be reported
 	// to clients listening for peer events.
 	var pubKey [33]byte
@@ -4334,6 +4760,8 @@ func (s *server) removePeer(p *peer.Brontide) {
 		delete(s.outboundPeers, pubStr)
 	}
 
+	s.peerPolicy.PeerDisconnected(pKey)
+
 	// Copy the peer's error buffer across to the server if it has any items
 	// in it so that we can restore peer errors across connections.
 	if p.ErrorBuffer().Total() > 0 {
@@ -4358,6 +4786,13 @@ func (s *server) ConnectToPeer(addr *lnwire.NetAddress,
 
 	targetPub := string(addr.IdentityKey.SerializeCompressed())
 
+	err := s.peerPolicy.CheckBanned(
+		route.NewVertex(addr.IdentityKey), addr.Address,
+	)
+	if err != nil {
+		return err
+	}
+
 	// Acquire mutex, but use explicit unlocking instead of defer for
 	// better granularity.  In certain conditions, this method requires
 	// making an outbound connection to a remote peer, which requires the
@@ -4451,6 +4886,49 @@ func (s *server) connectToPeer(addr *lnwire.NetAddress,
 	s.OutboundPeerConnected(nil, conn)
 }
 
+// reportMisbehavior reports an invalid message sent by the peer to our peer
+// policy. If the peer gets banned as a result, we disconnect from it.
+func (s *server) reportMisbehavior(peer route.Vertex, reason error) {
+	banned, err := s.peerPolicy.ReportMisbehavior(peer, reason)
+	if err != nil {
+		srvrLog.Errorf("Unable to report misbehavior of peer %v: %v",
+			peer, err)
+		return
+	}
+
+	// We disconnect asynchronously, as we may be called by a subsystem
+	// that the server waits on while holding its mutex.
+	if banned {
+		go s.disconnectBannedPeers()
+	}
+}
+
+// disconnectBannedPeers disconnects from all connected peers that are banned
+// by our peer policy, either by their public key or their address, and returns
+// the number of peers disconnected.
+//
+// NOTE: This function is safe for concurrent access.
+func (s *server) disconnectBannedPeers() uint32 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var numDisconnected uint32
+	for _, p := range s.peersByPub {
+		err := s.peerPolicy.CheckBanned(
+			route.NewVertex(p.IdentityKey()), p.Address(),
+		)
+		if err == nil {
+			continue
+		}
+
+		srvrLog.Infof("Disconnecting from banned peer %v", p)
+		p.Disconnect(err)
+		numDisconnected++
+	}
+
+	return numDisconnected
+}
+
 // DisconnectPeer sends the request to server to close the connection with peer
 // identified by public key.
 //
@@ -4726,3 +5204,78 @@ func shouldPeerBootstrap(cfg *Config) bool {
 	// covering the bootstrapping process.
 	return !cfg.NoNetBootstrap && !isDevNetwork
 }
+
+// newBackupSinks creates the remote backup sinks that are activated in the
+// given config.
+func newBackupSinks(cfg *lncfg.RemoteBackup) ([]chanbackup.Sink, error) {
+	var sinks []chanbackup.Sink
+
+	if cfg.S3.Active {
+		sink, err := chanbackup.NewS3Sink(chanbackup.S3SinkConfig{
+			Endpoint:        cfg.S3.Endpoint,
+			Region:          cfg.S3.Region,
+			Bucket:          cfg.S3.Bucket,
+			Key:             cfg.S3.Key,
+			AccessKeyID:     cfg.S3.AccessKeyID,
+			SecretAccessKey: cfg.S3.SecretAccessKey,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		sinks = append(sinks, sink)
+	}
+
+	if cfg.WebDAV.Active {
+		sink, err := chanbackup.NewWebDAVSink(chanbackup.WebDAVSinkConfig{
+			URL:      cfg.WebDAV.URL,
+			Username: cfg.WebDAV.Username,
+			Password: cfg.WebDAV.Password,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		sinks = append(sinks, sink)
+	}
+
+	if cfg.Exec.Active {
+		sinks = append(
+			sinks, chanbackup.NewExecSink(
+				cfg.Exec.Command, cfg.Exec.Args...,
+			),
+		)
+	}
+
+	return sinks, nil
+}
+
+// hasClosingChannels returns true if any anchor channel has a closing
+// transaction broadcast that isn't confirmed yet, or if any channel was force
+// closed and still has outputs to be swept.
+func hasClosingChannels(chanStateDB *channeldb.ChannelStateDB) (bool, error) {
+	waitingClose, err := chanStateDB.FetchWaitingCloseChannels()
+	if err != nil {
+		return false, err
+	}
+
+	for _, channel := range waitingClose {
+		if channel.ChanType.HasAnchors() {
+			return true, nil
+		}
+	}
+
+	pendingClosed, err := chanStateDB.FetchClosedChannels(true)
+	if err != nil {
+		return false, err
+	}
+
+	for _, summary := range pendingClosed {
+		switch summary.CloseType {
+		case channeldb.LocalForceClose, channeldb.RemoteForceClose:
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
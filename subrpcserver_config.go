@@ -10,6 +10,7 @@ import (
 	"github.com/lightningnetwork/lnd/autopilot"
 	"github.com/lightningnetwork/lnd/chainreg"
 	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/feature"
 	"github.com/lightningnetwork/lnd/htlcswitch"
 	"github.com/lightningnetwork/lnd/invoices"
 	"github.com/lightningnetwork/lnd/lncfg"
@@ -24,6 +25,7 @@ import (
 	"github.com/lightningnetwork/lnd/lnrpc/walletrpc"
 	"github.com/lightningnetwork/lnd/lnrpc/watchtowerrpc"
 	"github.com/lightningnetwork/lnd/lnrpc/wtclientrpc"
+	"github.com/lightningnetwork/lnd/lnwallet/anchorreserve"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/macaroons"
 	"github.com/lightningnetwork/lnd/netann"
@@ -112,6 +114,7 @@ func (s *subRPCServerConfigs) PopulateDependencies(cfg *Config,
 	graphDB *channeldb.ChannelGraph,
 	chanStateDB *channeldb.ChannelStateDB,
 	sweeper *sweep.UtxoSweeper,
+	anchorReserve *anchorreserve.Manager,
 	tower *watchtower.Standalone,
 	towerClient wtclient.Client,
 	anchorTowerClient wtclient.Client,
@@ -121,6 +124,8 @@ func (s *subRPCServerConfigs) PopulateDependencies(cfg *Config,
 	getNodeAnnouncement func() lnwire.NodeAnnouncement,
 	updateNodeAnnouncement func(features *lnwire.RawFeatureVector,
 		modifiers ...netann.NodeAnnModifier) error,
+	listFeatures func(set feature.Set) []feature.Status,
+	toggleFeatures func(toggles []feature.Toggle) error,
 	parseAddr func(addr string) (net.Addr, error),
 	rpcLogger btclog.Logger,
 	getAlias func(lnwire.ChannelID) (lnwire.ShortChannelID, error)) error {
@@ -198,6 +203,9 @@ func (s *subRPCServerConfigs) PopulateDependencies(cfg *Config,
 			subCfgValue.FieldByName("CurrentNumAnchorChans").Set(
 				reflect.ValueOf(cc.Wallet.CurrentNumAnchorChans),
 			)
+			subCfgValue.FieldByName("AnchorReserve").Set(
+				reflect.ValueOf(anchorReserve),
+			)
 
 		case *autopilotrpc.Config:
 			subCfgValue := extractReflectValue(subCfg)
@@ -334,6 +342,14 @@ func (s *subRPCServerConfigs) PopulateDependencies(cfg *Config,
 				reflect.ValueOf(updateNodeAnnouncement),
 			)
 
+			subCfgValue.FieldByName("ListFeatures").Set(
+				reflect.ValueOf(listFeatures),
+			)
+
+			subCfgValue.FieldByName("ToggleFeatures").Set(
+				reflect.ValueOf(toggleFeatures),
+			)
+
 		default:
 			return fmt.Errorf("unknown field: %v, %T", fieldName,
 				cfg)
@@ -130,6 +130,14 @@ type pendingInput struct {
 	// lastFeeRate is the most recent fee rate used for this input within a
 	// transaction broadcast to the network.
 	lastFeeRate chainfee.SatPerKWeight
+
+	// lastSweepTxid is the txid of the most recent transaction sweeping
+	// this input that was successfully broadcast to the network.
+	lastSweepTxid *chainhash.Hash
+
+	// sweepFee is the share of this input in the fees of all transactions
+	// sweeping it that were successfully broadcast to the network.
+	sweepFee btcutil.Amount
 }
 
 // parameters returns the sweep parameters for this input.
@@ -178,6 +186,17 @@ type PendingInput struct {
 	// input.
 	BroadcastAttempts int
 
+	// LastSweepTxid is the txid of the most recent transaction sweeping
+	// the input that was successfully broadcast, if any.
+	LastSweepTxid *chainhash.Hash
+
+	// SweepFee is the fee spent on sweeping the input so far. It adds up
+	// the share of the input in the fee of each transaction sweeping it
+	// that was successfully broadcast, including those that were replaced
+	// by a fee bump. The fee of a transaction sweeping several inputs is
+	// shared between them in proportion to their weight.
+	SweepFee btcutil.Amount
+
 	// NextBroadcastHeight is the next height of the chain at which we'll
 	// attempt to broadcast a transaction sweeping the input.
 	NextBroadcastHeight uint32
@@ -1348,6 +1367,7 @@ func (s *UtxoSweeper) sweep(inputs inputSet, feeRate chainfee.SatPerKWeight,
 	}
 
 	// Otherwise log the error.
+	published := err == nil
 	if err != nil {
 		log.Errorf("Publish sweep tx %v got error: %v", tx.TxHash(),
 			err)
@@ -1358,6 +1378,18 @@ func (s *UtxoSweeper) sweep(inputs inputSet, feeRate chainfee.SatPerKWeight,
 		s.currentOutputScript = nil
 	}
 
+	// Work out the fee paid by the sweep tx, so we can attribute a share
+	// of it to each of its inputs.
+	var totalIn, totalOut btcutil.Amount
+	for _, inp := range inputs {
+		totalIn += btcutil.Amount(inp.SignDesc().Output.Value)
+	}
+	for _, txOut := range tx.TxOut {
+		totalOut += btcutil.Amount(txOut.Value)
+	}
+	feeShares := shareSweepFee(inputs, totalIn-totalOut)
+	txid := tx.TxHash()
+
 	// Reschedule sweep.
 	for _, input := range tx.TxIn {
 		pi, ok := s.pendingInputs[input.PreviousOutPoint]
@@ -1373,6 +1405,11 @@ func (s *UtxoSweeper) sweep(inputs inputSet, feeRate chainfee.SatPerKWeight,
 		// Record another publish attempt.
 		pi.publishAttempts++
 
+		if published {
+			pi.lastSweepTxid = &txid
+			pi.sweepFee += feeShares[input.PreviousOutPoint]
+		}
+
 		// We don't care what the result of the publish call was. Even
 		// if it is published successfully, it can still be that it
 		// needs to be retried. Call NextAttemptDeltaFunc to calculate
@@ -1404,6 +1441,45 @@ func (s *UtxoSweeper) sweep(inputs inputSet, feeRate chainfee.SatPerKWeight,
 	return nil
 }
 
+// shareSweepFee splits the fee of a sweep transaction between its inputs in
+// proportion to their weight. If the weight of the inputs is unknown, the fee
+// is split evenly.
+func shareSweepFee(inputs []input.Input,
+	fee btcutil.Amount) map[wire.OutPoint]btcutil.Amount {
+
+	var (
+		emptyWeight = (&input.TxWeightEstimator{}).Weight()
+		weights     = make([]int64, len(inputs))
+		totalWeight int64
+	)
+	for i, inp := range inputs {
+		var estimator input.TxWeightEstimator
+		err := inp.WitnessType().AddWeightEstimation(&estimator)
+		if err != nil {
+			log.Debugf("Unable to estimate weight of input %v: %v",
+				inp.OutPoint(), err)
+
+			continue
+		}
+
+		weights[i] = int64(estimator.Weight() - emptyWeight)
+		totalWeight += weights[i]
+	}
+
+	shares := make(map[wire.OutPoint]btcutil.Amount, len(inputs))
+	for i, inp := range inputs {
+		share := fee / btcutil.Amount(len(inputs))
+		if totalWeight > 0 {
+			share = fee * btcutil.Amount(weights[i]) /
+				btcutil.Amount(totalWeight)
+		}
+
+		shares[*inp.OutPoint()] = share
+	}
+
+	return shares
+}
+
 // waitForSpend registers a spend notification with the chain notifier. It
 // returns a cancel function that can be used to cancel the registration.
 func (s *UtxoSweeper) waitForSpend(outpoint wire.OutPoint,
@@ -1489,6 +1565,8 @@ func (s *UtxoSweeper) handlePendingSweepsReq(
 			),
 			LastFeeRate:         pendingInput.lastFeeRate,
 			BroadcastAttempts:   pendingInput.publishAttempts,
+			LastSweepTxid:       pendingInput.lastSweepTxid,
+			SweepFee:            pendingInput.sweepFee,
 			NextBroadcastHeight: uint32(pendingInput.minPublishHeight),
 			Params:              pendingInput.params,
 		}
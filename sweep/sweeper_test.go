@@ -1176,6 +1176,18 @@ func TestPendingInputs(t *testing.T) {
 	ctx.tick()
 	ctx.receiveTx()
 	lowFeeRateTx := ctx.receiveTx()
+
+	// The pending input of the lower fee rate sweep should report the
+	// sweep transaction that was just broadcast.
+	pendingInputs, err := ctx.sweeper.PendingInputs()
+	require.NoError(t, err)
+
+	lowFeeRateTxid := lowFeeRateTx.TxHash()
+	pendingInput3 := pendingInputs[*input3.OutPoint()]
+	require.Equal(t, &lowFeeRateTxid, pendingInput3.LastSweepTxid)
+	require.Equal(t, 1, pendingInput3.BroadcastAttempts)
+	require.Positive(t, pendingInput3.SweepFee)
+
 	ctx.backend.deleteUnconfirmed(lowFeeRateTx.TxHash())
 	ctx.backend.mine()
 	ctx.expectResult(resultChan1, nil)
@@ -2289,3 +2301,26 @@ func TestSweeperShutdownHandling(t *testing.T) {
 	)
 	require.Error(t, err)
 }
+
+// TestShareSweepFee tests that the fee of a sweep transaction is split between
+// its inputs in proportion to their weight.
+func TestShareSweepFee(t *testing.T) {
+	t.Parallel()
+
+	smallInput := createTestInput(100_000, input.WitnessKeyHash)
+	largeInput := createTestInput(100_000, input.HtlcAcceptedRemoteSuccess)
+	inputs := []input.Input{&smallInput, &largeInput}
+
+	const fee = btcutil.Amount(10_000)
+	shares := shareSweepFee(inputs, fee)
+	require.Len(t, shares, 2)
+
+	smallShare := shares[*smallInput.OutPoint()]
+	largeShare := shares[*largeInput.OutPoint()]
+	require.Greater(t, largeShare, smallShare)
+
+	// Rounding down may leave a satoshi per input unattributed.
+	totalShares := smallShare + largeShare
+	require.LessOrEqual(t, totalShares, fee)
+	require.Greater(t, totalShares, fee-btcutil.Amount(len(inputs)))
+}
@@ -0,0 +1,225 @@
+package wirecapture
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing/route"
+)
+
+// A capture file starts with a header identifying the format and the peer the
+// messages were exchanged with, followed by any number of records:
+//
+//	header: magic (4 bytes) || version (2 bytes) || pubkey (33 bytes)
+//	record: unix nanos (8 bytes) || direction (1 byte) ||
+//	        message length (4 bytes) || raw message
+//
+// The raw message is the decrypted wire message, starting with its two byte
+// type, exactly as it was read from or written to the connection.
+const (
+	// Version is the version of the capture format written by this
+	// package.
+	Version uint16 = 1
+
+	// headerSize is the size of the file header.
+	headerSize = 4 + 2 + route.VertexSize
+
+	// recordHeaderSize is the size of a record without its message.
+	recordHeaderSize = 8 + 1 + 4
+)
+
+var (
+	// magic are the bytes every capture file starts with.
+	magic = [4]byte{'L', 'N', 'W', 'C'}
+
+	// ErrInvalidCapture is returned when reading a file that isn't a
+	// capture file.
+	ErrInvalidCapture = errors.New("not a wire capture file")
+)
+
+// Direction is the direction in which a captured message was sent.
+type Direction uint8
+
+const (
+	// Inbound denotes a message received from the peer.
+	Inbound Direction = 0
+
+	// Outbound denotes a message sent to the peer.
+	Outbound Direction = 1
+)
+
+// String returns a human readable representation of the direction.
+func (d Direction) String() string {
+	switch d {
+	case Inbound:
+		return "inbound"
+
+	case Outbound:
+		return "outbound"
+
+	default:
+		return fmt.Sprintf("<unknown direction %d>", uint8(d))
+	}
+}
+
+// Record is a single captured message.
+type Record struct {
+	// Timestamp is the time at which the message was read or written.
+	Timestamp time.Time
+
+	// Direction is the direction in which the message was sent.
+	Direction Direction
+
+	// Msg is the raw message, starting with its type.
+	Msg []byte
+}
+
+// MsgType returns the type of the captured message.
+func (r *Record) MsgType() lnwire.MessageType {
+	if len(r.Msg) < 2 {
+		return 0
+	}
+
+	return lnwire.MessageType(binary.BigEndian.Uint16(r.Msg[:2]))
+}
+
+// Message decodes the captured message.
+func (r *Record) Message() (lnwire.Message, error) {
+	return lnwire.ReadMessage(bytes.NewReader(r.Msg), 0)
+}
+
+// writeHeader writes the file header for the given peer.
+func writeHeader(w io.Writer, peer route.Vertex) error {
+	var header [headerSize]byte
+	copy(header[:4], magic[:])
+	binary.BigEndian.PutUint16(header[4:6], Version)
+	copy(header[6:], peer[:])
+
+	_, err := w.Write(header[:])
+	return err
+}
+
+// encodeRecord serializes a record.
+func encodeRecord(r *Record) ([]byte, error) {
+	if uint64(len(r.Msg)) > math.MaxUint32 {
+		return nil, fmt.Errorf("message of %d bytes too large",
+			len(r.Msg))
+	}
+
+	b := make([]byte, recordHeaderSize+len(r.Msg))
+	binary.BigEndian.PutUint64(b[:8], uint64(r.Timestamp.UnixNano()))
+	b[8] = byte(r.Direction)
+	binary.BigEndian.PutUint32(b[9:13], uint32(len(r.Msg)))
+	copy(b[recordHeaderSize:], r.Msg)
+
+	return b, nil
+}
+
+// Reader reads the records of a capture file.
+type Reader struct {
+	r    io.Reader
+	peer route.Vertex
+}
+
+// NewReader creates a reader for the capture read from r, reading its header.
+func NewReader(r io.Reader) (*Reader, error) {
+	var header [headerSize]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		if errors.Is(err, io.EOF) ||
+			errors.Is(err, io.ErrUnexpectedEOF) {
+
+			return nil, ErrInvalidCapture
+		}
+
+		return nil, err
+	}
+
+	if !bytes.Equal(header[:4], magic[:]) {
+		return nil, ErrInvalidCapture
+	}
+
+	version := binary.BigEndian.Uint16(header[4:6])
+	if version != Version {
+		return nil, fmt.Errorf("unsupported capture version %d",
+			version)
+	}
+
+	reader := &Reader{r: r}
+	copy(reader.peer[:], header[6:])
+
+	return reader, nil
+}
+
+// Peer returns the pubkey of the peer the captured messages were exchanged
+// with.
+func (r *Reader) Peer() route.Vertex {
+	return r.peer
+}
+
+// Next returns the next record of the capture. It returns io.EOF once all
+// records have been read. A record that was only partially written, for
+// example because the node crashed, results in io.ErrUnexpectedEOF.
+func (r *Reader) Next() (*Record, error) {
+	var header [recordHeaderSize]byte
+	if _, err := io.ReadFull(r.r, header[:]); err != nil {
+		return nil, err
+	}
+
+	msgLen := binary.BigEndian.Uint32(header[9:13])
+	if msgLen > lnwire.MaxMsgBody+2 {
+		return nil, fmt.Errorf("invalid message length %d", msgLen)
+	}
+
+	msg := make([]byte, msgLen)
+	if _, err := io.ReadFull(r.r, msg); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, io.ErrUnexpectedEOF
+		}
+
+		return nil, err
+	}
+
+	nanos := int64(binary.BigEndian.Uint64(header[:8]))
+
+	return &Record{
+		Timestamp: time.Unix(0, nanos),
+		Direction: Direction(header[8]),
+		Msg:       msg,
+	}, nil
+}
+
+// ReadFile reads all records of the capture file at the given path, returning
+// them along with the pubkey of the peer they were exchanged with.
+func ReadFile(path string) (route.Vertex, []*Record, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return route.Vertex{}, nil, err
+	}
+	defer f.Close()
+
+	reader, err := NewReader(f)
+	if err != nil {
+		return route.Vertex{}, nil, err
+	}
+
+	var records []*Record
+	for {
+		record, err := reader.Next()
+		switch {
+		case errors.Is(err, io.EOF):
+			return reader.Peer(), records, nil
+
+		case err != nil:
+			return route.Vertex{}, nil, err
+		}
+
+		records = append(records, record)
+	}
+}
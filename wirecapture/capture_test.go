@@ -0,0 +1,144 @@
+package wirecapture
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing/route"
+	"github.com/stretchr/testify/require"
+)
+
+var testPeer = route.Vertex{2, 1, 2, 3}
+
+// encodeMsg returns the raw encoding of a wire message.
+func encodeMsg(t *testing.T, msg lnwire.Message) []byte {
+	t.Helper()
+
+	var b bytes.Buffer
+	_, err := lnwire.WriteMessage(&b, msg, 0)
+	require.NoError(t, err)
+
+	return b.Bytes()
+}
+
+// TestCaptureRoundTrip asserts that captured messages are read back in order
+// with their timestamps and directions, and that they can be decoded.
+func TestCaptureRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	w, err := NewWriter(WriterConfig{Dir: dir, Peer: testPeer})
+	require.NoError(t, err)
+
+	ping := encodeMsg(t, &lnwire.Ping{NumPongBytes: 4})
+	pong := encodeMsg(t, &lnwire.Pong{PongBytes: make([]byte, 4)})
+	now := time.Unix(1000, 5)
+
+	require.NoError(t, w.Record(Inbound, ping, now))
+	require.NoError(t, w.Record(Outbound, pong, now.Add(time.Second)))
+	require.NoError(t, w.Close())
+	require.ErrorIs(t, w.Record(Inbound, ping, now), ErrWriterClosed)
+
+	// A new writer for the same peer appends to the existing file.
+	w, err = NewWriter(WriterConfig{Dir: dir, Peer: testPeer})
+	require.NoError(t, err)
+	require.NoError(t, w.Record(Inbound, []byte{0xff}, now))
+	require.NoError(t, w.Close())
+
+	peer, records, err := ReadFile(FileName(dir, testPeer))
+	require.NoError(t, err)
+	require.Equal(t, testPeer, peer)
+	require.Len(t, records, 3)
+
+	require.Equal(t, Inbound, records[0].Direction)
+	require.True(t, now.Equal(records[0].Timestamp))
+	require.EqualValues(t, lnwire.MsgPing, records[0].MsgType())
+	msg, err := records[0].Message()
+	require.NoError(t, err)
+	require.Equal(t, &lnwire.Ping{
+		NumPongBytes: 4,
+		PaddingBytes: []byte{},
+	}, msg)
+
+	require.Equal(t, Outbound, records[1].Direction)
+	require.Equal(t, pong, records[1].Msg)
+
+	// Messages that can't be decoded are captured as well.
+	require.Equal(t, []byte{0xff}, records[2].Msg)
+	_, err = records[2].Message()
+	require.Error(t, err)
+}
+
+// TestCaptureRotation asserts that capture files are rotated once they reach
+// their maximum size, and that only the configured number of rotated files
+// is kept.
+func TestCaptureRotation(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	msg := make([]byte, 100)
+	w, err := NewWriter(WriterConfig{
+		Dir:  dir,
+		Peer: testPeer,
+
+		// Each file fits two records.
+		MaxFileSize: headerSize + 2*(recordHeaderSize+100),
+		MaxFiles:    2,
+	})
+	require.NoError(t, err)
+
+	// Write seven records, numbering them with their first byte.
+	for i := 0; i < 7; i++ {
+		msg[0] = byte(i)
+		require.NoError(t, w.Record(Inbound, msg, time.Unix(0, 0)))
+	}
+	require.NoError(t, w.Close())
+
+	// The current file holds the last record, while the two rotated ones
+	// hold the four records before, the oldest ones being dropped.
+	name := FileName(dir, testPeer)
+	for file, expected := range map[string][]byte{
+		name:        {6},
+		name + ".1": {4, 5},
+		name + ".2": {2, 3},
+	} {
+		_, records, err := ReadFile(file)
+		require.NoError(t, err)
+
+		var numbers []byte
+		for _, record := range records {
+			numbers = append(numbers, record.Msg[0])
+		}
+		require.Equal(t, expected, numbers, file)
+	}
+
+	_, err = os.Stat(name + ".3")
+	require.True(t, os.IsNotExist(err))
+}
+
+// TestReaderInvalid asserts that the reader rejects files that aren't capture
+// files, and reports truncated records.
+func TestReaderInvalid(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewReader(bytes.NewReader([]byte("LNWC")))
+	require.ErrorIs(t, err, ErrInvalidCapture)
+
+	_, err = NewReader(bytes.NewReader(make([]byte, headerSize)))
+	require.ErrorIs(t, err, ErrInvalidCapture)
+
+	var b bytes.Buffer
+	require.NoError(t, writeHeader(&b, testPeer))
+	record, err := encodeRecord(&Record{Msg: []byte{1, 2, 3}})
+	require.NoError(t, err)
+	b.Write(record[:len(record)-1])
+
+	r, err := NewReader(&b)
+	require.NoError(t, err)
+	_, err = r.Next()
+	require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+}
@@ -0,0 +1,199 @@
+package wirecapture
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/lightningnetwork/lnd/routing/route"
+)
+
+const (
+	// FileExtension is the extension of capture files.
+	FileExtension = ".lncap"
+
+	// DefaultMaxFileSize is the default size in bytes after which a
+	// capture file is rotated.
+	DefaultMaxFileSize = 10 * 1024 * 1024
+
+	// DefaultMaxFiles is the default number of rotated capture files that
+	// are kept per peer.
+	DefaultMaxFiles = 3
+)
+
+// ErrWriterClosed is returned when recording a message with a closed writer.
+var ErrWriterClosed = errors.New("capture writer closed")
+
+// WriterConfig holds the configuration of a capture writer.
+type WriterConfig struct {
+	// Dir is the directory the capture files are written to.
+	Dir string
+
+	// Peer is the pubkey of the peer whose messages are captured.
+	Peer route.Vertex
+
+	// MaxFileSize is the size in bytes after which the capture file is
+	// rotated. If zero, DefaultMaxFileSize is used.
+	MaxFileSize int64
+
+	// MaxFiles is the number of rotated capture files that are kept in
+	// addition to the current one. If zero, DefaultMaxFiles is used.
+	MaxFiles int
+}
+
+// Writer captures the messages exchanged with a single peer to a rotating
+// capture file. The current file is named after the pubkey of the peer, and
+// rotated files get an increasing numeric suffix, the oldest file having the
+// highest one. It is safe for concurrent use.
+type Writer struct {
+	cfg WriterConfig
+
+	mu   sync.Mutex
+	file *os.File
+	size int64
+}
+
+// NewWriter creates a writer for the given configuration, appending to the
+// current capture file of the peer if one exists.
+func NewWriter(cfg WriterConfig) (*Writer, error) {
+	if cfg.MaxFileSize == 0 {
+		cfg.MaxFileSize = DefaultMaxFileSize
+	}
+	if cfg.MaxFiles == 0 {
+		cfg.MaxFiles = DefaultMaxFiles
+	}
+
+	if err := os.MkdirAll(cfg.Dir, 0700); err != nil {
+		return nil, err
+	}
+
+	w := &Writer{cfg: cfg}
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+
+	return w, nil
+}
+
+// FileName returns the path of the current capture file for a peer.
+func FileName(dir string, peer route.Vertex) string {
+	return filepath.Join(dir, peer.String()+FileExtension)
+}
+
+// open opens the current capture file, writing its header if it's new. The
+// caller MUST hold the mutex.
+func (w *Writer) open() error {
+	file, err := os.OpenFile(
+		FileName(w.cfg.Dir, w.cfg.Peer),
+		os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600,
+	)
+	if err != nil {
+		return err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	w.file = file
+	w.size = info.Size()
+	if w.size > 0 {
+		return nil
+	}
+
+	if err := writeHeader(file, w.cfg.Peer); err != nil {
+		file.Close()
+		return err
+	}
+	w.size = headerSize
+
+	return nil
+}
+
+// rotate closes the current capture file, shifts the rotated files, dropping
+// the oldest one, and opens a new capture file. The caller MUST hold the
+// mutex.
+func (w *Writer) rotate() error {
+	if err := w.file.Close(); err != nil {
+		return err
+	}
+
+	name := FileName(w.cfg.Dir, w.cfg.Peer)
+	rotated := func(i int) string {
+		return fmt.Sprintf("%s.%d", name, i)
+	}
+
+	err := os.Remove(rotated(w.cfg.MaxFiles))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	for i := w.cfg.MaxFiles - 1; i > 0; i-- {
+		err := os.Rename(rotated(i), rotated(i+1))
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	if err := os.Rename(name, rotated(1)); err != nil {
+		return err
+	}
+
+	return w.open()
+}
+
+// Record captures a message sent in the given direction at the given time.
+// The message must start with its type.
+func (w *Writer) Record(direction Direction, msg []byte,
+	timestamp time.Time) error {
+
+	record, err := encodeRecord(&Record{
+		Timestamp: timestamp,
+		Direction: direction,
+		Msg:       msg,
+	})
+	if err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.file == nil {
+		return ErrWriterClosed
+	}
+
+	// Rotate the file before the write that would take it over the
+	// maximum size, unless it doesn't hold any record yet.
+	newSize := w.size + int64(len(record))
+	if newSize > w.cfg.MaxFileSize && w.size > headerSize {
+		if err := w.rotate(); err != nil {
+			w.file = nil
+			return fmt.Errorf("unable to rotate capture file: %w",
+				err)
+		}
+	}
+
+	n, err := w.file.Write(record)
+	w.size += int64(n)
+
+	return err
+}
+
+// Close closes the capture file.
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.file == nil {
+		return nil
+	}
+
+	err := w.file.Close()
+	w.file = nil
+
+	return err
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/lightningnetwork/lnd/htlcswitch/hop"
 	"github.com/lightningnetwork/lnd/lntypes"
 	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/record"
 )
 
 // preimageSubscriber reprints an active subscription to be notified once the
@@ -91,6 +92,15 @@ func (p *preimageBeacon) SubscribeUpdates(
 		},
 	}
 
+	// Extract the custom records that were attached to the incoming htlc.
+	// They were validated when the htlc was received, so a failure to
+	// parse them isn't fatal.
+	inWireRecords, err := record.ParseWireCustomRecords(htlc.ExtraData)
+	if err != nil {
+		srvrLog.Warnf("Unable to parse custom records of htlc %v: %v",
+			htlc.HtlcIndex, err)
+	}
+
 	// Notify the htlc interceptor. There may be a client connected
 	// and willing to supply a preimage.
 	packet := &htlcswitch.InterceptedPacket{
@@ -105,12 +115,14 @@ func (p *preimageBeacon) SubscribeUpdates(
 		OutgoingExpiry: payload.FwdInfo.OutgoingCTLV,
 		OutgoingAmount: payload.FwdInfo.AmountToForward,
 		CustomRecords:  payload.CustomRecords(),
+
+		InWireCustomRecords: inWireRecords,
 	}
 	copy(packet.OnionBlob[:], nextHopOnionBlob)
 
 	fwd := newInterceptedForward(packet, p)
 
-	err := p.interceptor(fwd)
+	err = p.interceptor(fwd)
 	if err != nil {
 		return nil, err
 	}